@@ -5,6 +5,7 @@ import (
 	"TUI-Blender-Launcher/config"
 	"encoding/json"
 	"fmt"
+	"io"
 	"net/http"
 	"runtime"
 	"strings"
@@ -31,6 +32,21 @@ func NewAPI() *API {
 	}
 }
 
+// SourceURL returns the API address listing the builds of buildType.
+func SourceURL(buildType string) string {
+	switch buildType {
+	case "daily":
+		return dailyBlenderAPIURL
+	case "patch":
+		return patchBlenderAPIURL
+	case "experimental":
+		return experimentalBlenderAPIURL
+	default:
+		// Default to daily builds if not specified or invalid
+		return dailyBlenderAPIURL
+	}
+}
+
 // FetchBuilds fetches the list of Blender builds from the official API,
 // filtering for the current OS/architecture, file extensions, and minimum version.
 func (a *API) FetchBuilds(versionFilter string, buildType string) ([]model.BlenderBuild, error) {
@@ -40,19 +56,7 @@ func (a *API) FetchBuilds(versionFilter string, buildType string) ([]model.Blend
 		return nil, fmt.Errorf("failed to load config: %w", err)
 	}
 
-	// Determine which API URL to use based on buildType
-	var apiURL string
-	switch buildType {
-	case "daily":
-		apiURL = dailyBlenderAPIURL
-	case "patch":
-		apiURL = patchBlenderAPIURL
-	case "experimental":
-		apiURL = experimentalBlenderAPIURL
-	default:
-		// Default to daily builds if not specified or invalid
-		apiURL = dailyBlenderAPIURL
-	}
+	apiURL := SourceURL(buildType)
 
 	// Add UUID to request headers
 	req, err := http.NewRequest("GET", apiURL, nil)
@@ -71,11 +75,6 @@ func (a *API) FetchBuilds(versionFilter string, buildType string) ([]model.Blend
 		return nil, fmt.Errorf("failed to fetch data: status code %d", resp.StatusCode)
 	}
 
-	var allBuildEntries []model.BlenderBuild
-	if err := json.NewDecoder(resp.Body).Decode(&allBuildEntries); err != nil {
-		return nil, fmt.Errorf("failed to decode JSON (check API response structure): %w", err)
-	}
-
 	// --- Filtering Setup ---
 	currentOS := runtime.GOOS
 	currentArch := runtime.GOARCH
@@ -137,21 +136,20 @@ func (a *API) FetchBuilds(versionFilter string, buildType string) ([]model.Blend
 		}
 	}
 
-	// --- Filtering Loop ---
-	var platformFilteredBuilds []model.BlenderBuild
-	for _, build := range allBuildEntries {
+	// --- Filtering ---
+	keep := func(build model.BlenderBuild) bool {
 		// Check OS
 		if build.OperatingSystem != currentOS {
-			continue
+			return false
 		}
 		// Check Arch: Use the explicitly mapped apiArch
 		if build.Architecture != apiArch {
-			continue
+			return false
 		}
 		// Check Extension
 		ext := strings.ToLower(build.FileExtension)
 		if _, ok := allowedExtensions[ext]; !ok {
-			continue
+			return false
 		}
 
 		// Check Version Filter
@@ -159,17 +157,53 @@ func (a *API) FetchBuilds(versionFilter string, buildType string) ([]model.Blend
 			buildVersion, err := version.NewVersion(build.Version)
 			if err != nil {
 				// Skip builds with unparseable versions if filter is active
-				continue
+				return false
 			}
 			if buildVersion.LessThan(minVersion) {
-				continue // Skip if build version is less than filter
+				return false // Skip if build version is less than filter
 			}
 		}
+		return true
+	}
 
-		// Passed all filters
-		build.Status = model.StateOnline
-		platformFilteredBuilds = append(platformFilteredBuilds, build)
+	platformFilteredBuilds, err := decodeBuilds(resp.Body, keep)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode JSON (check API response structure): %w", err)
+	}
+	for i := range platformFilteredBuilds {
+		platformFilteredBuilds[i].Status = model.StateOnline
 	}
 
 	return platformFilteredBuilds, nil
 }
+
+// decodeBuilds reads the JSON array of builds one entry at a time and keeps only
+// those accepted by keep. The builder lists every platform and has no paging, so
+// streaming bounds memory to the matching builds instead of the whole catalogue.
+func decodeBuilds(r io.Reader, keep func(model.BlenderBuild) bool) ([]model.BlenderBuild, error) {
+	dec := json.NewDecoder(r)
+	tok, err := dec.Token()
+	if err != nil {
+		return nil, err
+	}
+	if delim, ok := tok.(json.Delim); !ok || delim != '[' {
+		return nil, fmt.Errorf("expected a JSON array, got %v", tok)
+	}
+
+	var builds []model.BlenderBuild
+	for dec.More() {
+		var build model.BlenderBuild
+		if err := dec.Decode(&build); err != nil {
+			return nil, err
+		}
+		if keep(build) {
+			builds = append(builds, build)
+		}
+	}
+
+	// Consume the closing bracket so truncated responses are reported
+	if _, err := dec.Token(); err != nil {
+		return nil, err
+	}
+	return builds, nil
+}
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 )
 
@@ -284,3 +285,75 @@ func (m *mockTransport) RoundTrip(req *http.Request) (*http.Response, error) {
 	// For other requests, use the default transport
 	return http.DefaultTransport.RoundTrip(req)
 }
+
+func TestDecodeBuilds(t *testing.T) {
+	keepLinux := func(build model.BlenderBuild) bool { return build.OperatingSystem == "linux" }
+
+	tests := []struct {
+		name     string
+		body     string
+		versions []string
+		wantErr  bool
+	}{
+		{
+			name:     "filters while decoding",
+			body:     `[{"version": "4.2.0", "platform": "linux"}, {"version": "4.2.0", "platform": "windows"}, {"version": "4.1.0", "platform": "linux"}]`,
+			versions: []string{"4.2.0", "4.1.0"},
+		},
+		{name: "empty catalogue", body: `[]`},
+		{name: "not an array", body: `{"version": "4.2.0"}`, wantErr: true},
+		{name: "truncated response", body: `[{"version": "4.2.0", "platform": "linux"}`, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			builds, err := decodeBuilds(strings.NewReader(tt.body), keepLinux)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("decodeBuilds() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if len(builds) != len(tt.versions) {
+				t.Fatalf("Expected %d builds, got %d", len(tt.versions), len(builds))
+			}
+			for i, build := range builds {
+				if build.Version != tt.versions[i] {
+					t.Errorf("Build %d: expected version %s, got %s", i, tt.versions[i], build.Version)
+				}
+			}
+		})
+	}
+}
+
+func TestCheckHealth(t *testing.T) {
+	tests := []struct {
+		name   string
+		status int // 0 for a server that is gone
+		want   Health
+	}{
+		{"ok", http.StatusOK, HealthUp},
+		{"method not allowed", http.StatusMethodNotAllowed, HealthUp}, // Still answering
+		{"server error", http.StatusServiceUnavailable, HealthDown},
+		{"gone", 0, HealthUnreachable},
+	}
+
+	a := NewAPI()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.Method != http.MethodHead {
+					t.Errorf("Expected a HEAD request, got %s", r.Method)
+				}
+				w.WriteHeader(tt.status)
+			}))
+			url := server.URL
+			if tt.status == 0 {
+				server.Close()
+			} else {
+				defer server.Close()
+			}
+
+			if got := a.CheckHealth(url); got != tt.want {
+				t.Errorf("CheckHealth() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,42 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"time"
+)
+
+// healthTimeout is how long a health check waits for an answer.
+const healthTimeout = 10 * time.Second
+
+// Health is the reachability of a build source
+type Health int
+
+const (
+	HealthUnknown     Health = iota
+	HealthUp                 // The server answered
+	HealthDown               // The server answered with an error of its own
+	HealthUnreachable        // No answer, because of the network or the server
+)
+
+// CheckHealth sends a HEAD request to url, which is cheap for the server, and
+// reports whether it answered. Any status below 500 counts as up, as the
+// server is there to answer.
+func (a *API) CheckHealth(url string) Health {
+	ctx, cancel := context.WithTimeout(context.Background(), healthTimeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodHead, url, nil)
+	if err != nil {
+		return HealthUnreachable
+	}
+	resp, err := a.client.Do(req)
+	if err != nil {
+		return HealthUnreachable
+	}
+	resp.Body.Close()
+	if resp.StatusCode >= 500 {
+		return HealthDown
+	}
+	return HealthUp
+}
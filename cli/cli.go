@@ -0,0 +1,783 @@
+package cli
+
+import (
+	"TUI-Blender-Launcher/api"
+	"TUI-Blender-Launcher/config"
+	"TUI-Blender-Launcher/download"
+	"TUI-Blender-Launcher/launch"
+	"TUI-Blender-Launcher/local"
+	"TUI-Blender-Launcher/model"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"os/signal"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// usageText lists the available commands, printed for `help` and usage errors.
+const usageText = `Usage: TUI-Blender-Launcher [--config <path>] [--quiet] <command> [arguments]
+       TUI-Blender-Launcher [--config <path>] [--quiet] -- [blender arguments]
+
+Without a command the interactive TUI is started. With -- the default build runs
+with the arguments that follow, so the launcher can stand in for blender.
+
+Commands:
+  list [--online] [--tag <t>]  List installed builds (or builds available online)
+  download [--copy-settings] <version>
+                               Download and install a build, copying the settings of the
+                               previous series to a new one
+  launch [--env <profile>] [--integrated] [--lang <locale>] [--display x11|wayland] [--sandbox] <version> [args...]
+                               Run an installed build in the foreground, with the variables
+                               of an env profile set, on the integrated GPU despite gpu_offload,
+                               in another language than the configured one, forced onto X11
+                               or Wayland (Linux), through the sandbox command like builds
+                               tagged sandbox
+  launch [--env <profile>] [--integrated] [--lang <locale>] [--display x11|wayland] [--sandbox] <file.blend> [args...]
+                               Open a file with the build its project sets
+  default <version>            Point the "current" symlink in the download directory at a build
+  journal [--version <v>] [--json]
+                               Show every downloaded archive with its digest and result
+  repair                       Check and fix every installed build, e.g. after restoring a backup
+  import <path>                Register a Blender installed elsewhere, from its directory or executable
+  export [--config] [--output <dir>] <version>
+                               Pack an installed build into a .tar.xz to move it to another machine
+  move <version> <root>        Move an installed build to another install root
+  rollback <version>           Swap an installed build for the one its last update replaced
+  check <version>              Test run an installed build to make sure it starts
+  help                         Show this help
+
+Flags:
+  --config <path>              Use another config file, also set with ` + config.ConfigEnvVar + `
+  -q, --quiet                  Only print errors
+
+Exit codes:
+  0    success
+  1    unexpected error
+  2    invalid usage
+  3    network failure
+  4    build not found
+  5    archive verification failed
+  6    not enough disk space
+  7    build does not run
+  130  cancelled
+`
+
+// command runs a CLI verb with the arguments that follow it.
+type command func(c *cli, args []string) error
+
+var commands = map[string]command{
+	"list":     (*cli).list,
+	"download": (*cli).download,
+	"launch":   (*cli).launch,
+	"default":  (*cli).setDefault,
+	"journal":  (*cli).journal,
+	"repair":   (*cli).repair,
+	"import":   (*cli).importBuild,
+	"export":   (*cli).export,
+	"move":     (*cli).move,
+	"rollback": (*cli).rollback,
+	"check":    (*cli).check,
+}
+
+// cli holds the state shared by all commands.
+type cli struct {
+	cfg   config.Config
+	quiet bool
+	out   io.Writer // Regular output, discarded in quiet mode
+	err   io.Writer // Errors, always shown
+}
+
+// SplitConfigFlag takes --config <path> or --config=<path> out of the global
+// flags in args, so the config can be loaded before anything runs. It
+// returns the path, "" if none is given, and the remaining arguments.
+func SplitConfigFlag(args []string) (string, []string, error) {
+	path := ""
+	rest := make([]string, 0, len(args))
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		if !strings.HasPrefix(arg, "-") || arg == "--" {
+			rest = append(rest, args[i:]...)
+			break
+		}
+		switch {
+		case arg == "--config":
+			if i+1 == len(args) || args[i+1] == "" {
+				return "", nil, fmt.Errorf("%w: --config needs a path", errUsage)
+			}
+			i++
+			path = args[i]
+		case strings.HasPrefix(arg, "--config="):
+			path = strings.TrimPrefix(arg, "--config=")
+			if path == "" {
+				return "", nil, fmt.Errorf("%w: --config needs a path", errUsage)
+			}
+		default:
+			rest = append(rest, arg)
+		}
+	}
+	return path, rest, nil
+}
+
+// Run executes the command in args and returns the process exit code.
+func Run(cfg config.Config, args []string) int {
+	c := &cli{cfg: cfg, out: os.Stdout, err: os.Stderr}
+
+	// Global flags go before the command name
+	for len(args) > 0 && strings.HasPrefix(args[0], "-") {
+		switch args[0] {
+		case "-q", "--quiet":
+			c.quiet = true
+			c.out = io.Discard
+		case "-h", "--help":
+			fmt.Fprint(os.Stdout, usageText)
+			return ExitOK
+		case "--":
+			return c.passThrough(args[1:])
+		default:
+			return c.fail(fmt.Errorf("%w: unknown flag %s", errUsage, args[0]))
+		}
+		args = args[1:]
+	}
+
+	if len(args) == 0 {
+		return c.fail(fmt.Errorf("%w: missing command", errUsage))
+	}
+	if args[0] == "help" {
+		fmt.Fprint(c.out, usageText)
+		return ExitOK
+	}
+
+	cmd, ok := commands[args[0]]
+	if !ok {
+		return c.fail(fmt.Errorf("%w: unknown command %q", errUsage, args[0]))
+	}
+	return c.fail(cmd(c, args[1:]))
+}
+
+// passThrough runs the default build with args like blender itself would,
+// so the launcher can stand in for it. Blender's exit code is the launcher's.
+func (c *cli) passThrough(args []string) int {
+	current := local.CurrentBuild(c.cfg.DownloadDir)
+	if current == nil {
+		return c.fail(fmt.Errorf("%w: no default build to run, set one with default <version>", errUsage))
+	}
+	err := c.runBuild(append([]string{"--", current.Version}, args...), true)
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) && exitErr.ExitCode() > 0 {
+		return exitErr.ExitCode()
+	}
+	return c.fail(err)
+}
+
+// fail prints an error (if any) and returns its exit code.
+func (c *cli) fail(err error) int {
+	if err == nil {
+		return ExitOK
+	}
+	fmt.Fprintf(c.err, "Error: %v\n", err)
+	if errors.Is(err, errUsage) {
+		fmt.Fprintf(c.err, "Run 'TUI-Blender-Launcher help' for usage.\n")
+	}
+	return ExitCode(err)
+}
+
+// newFlagSet creates a flag set that reports errors through the usage exit code.
+func newFlagSet(name string) *flag.FlagSet {
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+	return fs
+}
+
+// parseFlags parses command flags, wrapping failures as usage errors.
+func parseFlags(fs *flag.FlagSet, args []string) error {
+	if err := fs.Parse(args); err != nil {
+		return fmt.Errorf("%w: %v", errUsage, err)
+	}
+	return nil
+}
+
+// list prints installed builds, or the builds available online with --online.
+func (c *cli) list(args []string) error {
+	fs := newFlagSet("list")
+	online := fs.Bool("online", false, "list builds available online")
+	tag := fs.String("tag", "", "only list installed builds with this tag")
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+
+	var builds []model.BlenderBuild
+	var err error
+	if *online {
+		builds, err = c.fetchOnline()
+	} else {
+		builds, err = local.ScanLocalBuilds(c.cfg.Roots()...)
+	}
+	if err != nil {
+		return err
+	}
+
+	for _, build := range builds {
+		if *tag != "" && !build.HasTag(*tag) {
+			continue
+		}
+		fmt.Fprintf(c.out, "%s\t%s\t%s\t%s\n",
+			build.Version, build.Branch, build.Hash, build.BuildDate.Time().Format("2006-01-02 15:04"))
+		for _, dir := range build.Duplicates {
+			fmt.Fprintf(c.err, "Blender %s is also installed in %s, one copy is enough\n", build.Version, dir)
+		}
+	}
+	return nil
+}
+
+// download fetches and installs the build matching the given version.
+func (c *cli) download(args []string) error {
+	fs := newFlagSet("download")
+	copySettings := fs.Bool("copy-settings", false, "copy the settings and add-ons of the previous series to a new one")
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+	if fs.NArg() != 1 {
+		return fmt.Errorf("%w: download expects exactly one version", errUsage)
+	}
+	version := fs.Arg(0)
+
+	builds, err := c.fetchOnline()
+	if err != nil {
+		return err
+	}
+	build, err := local.ResolveBuild(builds, version, c.cfg.Aliases)
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(c.cfg.DownloadDir, 0750); err != nil {
+		return fmt.Errorf("failed to create download directory: %w", err)
+	}
+
+	cancelCh := make(chan struct{})
+	stop := cancelOnInterrupt(cancelCh)
+	defer stop()
+
+	fmt.Fprintf(c.out, "Downloading Blender %s (%s)\n", build.Version, build.Hash)
+	progressCb := func(downloaded, total int64) {
+		if total > 0 {
+			fmt.Fprintf(c.out, "\rDownloading... %3.0f%%", float64(downloaded)/float64(total)*100)
+		}
+	}
+	extractCb := func(progress float64) {
+		fmt.Fprintf(c.out, "\rExtracting...  %3.0f%%", progress*100)
+	}
+
+	dir, err := download.DownloadAndExtractBuild(build, c.cfg, progressCb, extractCb, cancelCh, nil, nil)
+	fmt.Fprintln(c.out)
+	if errors.Is(err, download.ErrAlreadyInstalled) {
+		fmt.Fprintf(c.out, "Already installed in %s\n", dir)
+		return nil
+	}
+	if err == nil {
+		if addonsErr := download.InstallAddons(c.cfg.Addons, dir); addonsErr != nil {
+			fmt.Fprintf(c.err, "Warning: %v\n", addonsErr)
+		}
+		if hookErr := download.RunPostInstallHook(c.cfg.PostInstallHook, build, dir); hookErr != nil {
+			fmt.Fprintf(c.err, "Warning: %v\n", hookErr)
+		}
+	}
+	if !errors.Is(err, download.ErrCancelled) {
+		if notifyErr := download.Notify(c.cfg.NotifyURL, c.cfg.NotifyCommand, download.NewNotification(build, dir, err)); notifyErr != nil {
+			fmt.Fprintf(c.err, "Warning: %v\n", notifyErr)
+		}
+	}
+	if err != nil {
+		return err
+	}
+	if c.cfg.Insights {
+		_ = local.RecordDownloadInsight(build.Size)
+	}
+	fmt.Fprintf(c.out, "Installed to %s\n", dir)
+	if from := local.PreviousSettings(build.Version); *copySettings && from != "" {
+		if err := local.CopyPreviousSettings(from, build.Version); err != nil {
+			fmt.Fprintf(c.err, "Warning: %v\n", err)
+		} else {
+			fmt.Fprintf(c.out, "Copied the settings of Blender %s\n", from)
+		}
+	}
+	if len(c.cfg.Addons) > 0 && !local.HasUserPreferences(build.Version) {
+		fmt.Fprintf(c.out, "Enable your add-ons in Preferences > Add-ons, Blender %s has no preferences loading them yet\n",
+			model.VersionSeries(build.Version))
+	}
+	c.enforceRetention()
+	return nil
+}
+
+// enforceRetention removes the builds beyond the newest keep_per_series of
+// each series. Failing to is only a warning, the download itself succeeded.
+func (c *cli) enforceRetention() {
+	if c.cfg.KeepPerSeries <= 0 {
+		return
+	}
+	items, err := local.RetentionExcess(c.cfg.Roots(), c.cfg.KeepPerSeries)
+	if err == nil && len(items) == 0 {
+		return
+	}
+	var freed int64
+	if err == nil {
+		freed, err = local.RemoveReclaimable(items)
+	}
+	if err != nil {
+		fmt.Fprintf(c.err, "Warning: failed to remove old builds: %v\n", err)
+		return
+	}
+	fmt.Fprintf(c.out, "Removed %d old builds beyond keep_per_series, freed %s\n", len(items), model.FormatByteSize(freed))
+}
+
+// launch runs an installed build in the foreground, forwarding extra arguments.
+// The build may be given as a version, series or alias, or left out when
+// opening a .blend file of a project that sets its build.
+func (c *cli) launch(args []string) error {
+	return c.runBuild(args, false)
+}
+
+// runBuild runs a build like launch. In place, Blender starts in the caller's
+// working directory whatever the project or launch_dir say, so relative
+// arguments resolve the way they would for blender itself.
+func (c *cli) runBuild(args []string, inPlace bool) error {
+	fs := newFlagSet("launch")
+	profile := fs.String("env", "", "env profile to launch with, else the project's or env_profile")
+	integrated := fs.Bool("integrated", false, "stay on the integrated GPU despite gpu_offload")
+	language := fs.String("lang", c.cfg.Language, "locale to run Blender in, e.g. fr_FR")
+	display := fs.String("display", "", "display server to force Blender onto, x11 or wayland")
+	sandbox := fs.Bool("sandbox", false, "launch through the sandbox command, as builds tagged sandbox are")
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+	args = fs.Args()
+	if len(args) == 0 {
+		return fmt.Errorf("%w: launch expects a version", errUsage)
+	}
+	name, extra := args[0], args[1:]
+	if isBlendFile(name) {
+		name, extra = c.cfg.BuildFor(name), args
+		if name == "" {
+			return fmt.Errorf("%w: no project sets the build for %s, launch expects a version", errUsage, args[0])
+		}
+	}
+
+	// Opening a .blend file of a project starts Blender in the project's
+	// directory, so the file is passed as an absolute path
+	var file string
+	extra = append([]string(nil), extra...)
+	for i, arg := range extra {
+		if isBlendFile(arg) {
+			if abs, err := filepath.Abs(arg); err == nil {
+				extra[i] = abs
+			}
+			file = extra[i]
+			break
+		}
+	}
+	if *profile == "" {
+		*profile = c.cfg.EnvProfileFor(file)
+	}
+	env, ok := c.cfg.EnvProfiles[*profile]
+	if *profile != "" && !ok {
+		return fmt.Errorf("%w: env profile %s is not in env_profiles", errUsage, *profile)
+	}
+
+	installed, err := local.ScanLocalBuilds(c.cfg.Roots()...)
+	if err != nil {
+		return err
+	}
+	build, err := local.ResolveBuild(installed, name, c.cfg.Aliases)
+	if err != nil {
+		return err
+	}
+	version := build.Version
+	exe, err := local.FindBuildExecutable(c.cfg.Roots(), version)
+	if err != nil {
+		return err
+	}
+
+	workDir := c.cfg.WorkDirFor(file)
+	if inPlace {
+		workDir = ""
+	}
+	opts := launch.Options{
+		WorkDir:    workDir,
+		Env:        env,
+		Args:       extra,
+		Template:   c.cfg.LaunchTemplate,
+		Language:   *language,
+		Display:    *display,
+		PythonPath: c.cfg.PythonPathFor(version),
+	}
+	if !*integrated {
+		opts.Offload = c.cfg.GPUOffload
+	}
+	if *sandbox || build.HasTag(config.SandboxTag) {
+		if opts, err = c.sandbox(opts, version, file); err != nil {
+			return err
+		}
+	}
+	cmd, err := launch.Command(exe, opts)
+	if err != nil {
+		return err
+	}
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	if c.quiet {
+		cmd.Stdout = nil
+	}
+	if c.cfg.LaunchLogs {
+		// Blender's output goes to the terminal and a log of the session
+		logDir := launch.LogDir()
+		logFile, err := launch.CreateLog(launch.LogPath(logDir, version, time.Now()))
+		if err != nil {
+			return err
+		}
+		defer logFile.Close()
+		cmd.Stderr = io.MultiWriter(os.Stderr, logFile)
+		cmd.Stdout = logFile
+		if !c.quiet {
+			cmd.Stdout = io.MultiWriter(os.Stdout, logFile)
+		}
+		_ = launch.RotateLogs(logDir, launch.LaunchLogsKept)
+	}
+	// Usage only feeds the prefetch heuristic, so failing to record it is not fatal
+	_ = local.RecordLaunch(version)
+	if c.cfg.Insights {
+		_ = local.RecordLaunchInsight(version)
+	}
+	_ = local.RecordBuildLaunch(c.cfg.Roots(), version, time.Now())
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("blender %s failed: %w", version, err)
+	}
+	return nil
+}
+
+// sandbox returns opts confining Blender version to the project of file with
+// the sandbox command, or an error if none is set.
+func (c *cli) sandbox(opts launch.Options, version, file string) (launch.Options, error) {
+	if c.cfg.Sandbox == "" {
+		return opts, fmt.Errorf("%w: set sandbox in config.toml to launch Blender %s sandboxed", launch.ErrNoSandbox, version)
+	}
+	dir, err := local.FindBuildDir(c.cfg.Roots(), version)
+	if err != nil {
+		return opts, err
+	}
+	opts.Sandbox, opts.Project, opts.BuildDir = c.cfg.Sandbox, c.cfg.ProjectDirFor(file), dir
+	return opts, nil
+}
+
+// setDefault points the current symlink at the installed build matching the
+// given version, series or alias.
+func (c *cli) setDefault(args []string) error {
+	fs := newFlagSet("default")
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+	if fs.NArg() != 1 {
+		return fmt.Errorf("%w: default expects exactly one version", errUsage)
+	}
+
+	installed, err := local.ScanLocalBuilds(c.cfg.Roots()...)
+	if err != nil {
+		return err
+	}
+	build, err := local.ResolveBuild(installed, fs.Arg(0), c.cfg.Aliases)
+	if err != nil {
+		return err
+	}
+	link, err := local.SetCurrentBuild(c.cfg.Roots(), c.cfg.DownloadDir, build.Version)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(c.out, "%s now points at Blender %s\n", link, build.Version)
+	return nil
+}
+
+// journal prints the download journal, optionally only the entries of one
+// version or series, as tab-separated columns or as JSON lines.
+func (c *cli) journal(args []string) error {
+	fs := newFlagSet("journal")
+	version := fs.String("version", "", "only show archives of this version or series")
+	asJSON := fs.Bool("json", false, "print entries as JSON lines")
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+	if fs.NArg() != 0 {
+		return fmt.Errorf("%w: journal takes no arguments", errUsage)
+	}
+
+	entries, err := download.ReadJournal()
+	if err != nil {
+		return err
+	}
+
+	encoder := json.NewEncoder(c.out)
+	for _, entry := range entries {
+		if *version != "" && entry.Version != *version && model.VersionSeries(entry.Version) != *version {
+			continue
+		}
+		if *asJSON {
+			if err := encoder.Encode(entry); err != nil {
+				return err
+			}
+			continue
+		}
+		fmt.Fprintf(c.out, "%s\t%s\t%s\t%d\t%s\t%s\n",
+			entry.Time.Format("2006-01-02 15:04:05"), entry.Version, entry.Result, entry.Size, entry.SHA256, entry.URL)
+	}
+	return nil
+}
+
+// importBuild registers a Blender installed outside the launcher.
+func (c *cli) importBuild(args []string) error {
+	fs := newFlagSet("import")
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+	if fs.NArg() != 1 {
+		return fmt.Errorf("%w: import expects exactly one path", errUsage)
+	}
+
+	build, err := local.ImportBuild(fs.Arg(0), c.cfg.Roots(), c.cfg.DownloadDir)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(c.out, "Imported Blender %s (%s)\n", build.Version, build.Hash)
+	return nil
+}
+
+// export packs an installed build into a .tar.xz archive.
+func (c *cli) export(args []string) error {
+	fs := newFlagSet("export")
+	withConfig := fs.Bool("config", false, "include Blender's user config of the build's series")
+	output := fs.String("output", ".", "directory the archive is written to")
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+	if fs.NArg() != 1 {
+		return fmt.Errorf("%w: export expects exactly one version", errUsage)
+	}
+
+	installed, err := local.ScanLocalBuilds(c.cfg.Roots()...)
+	if err != nil {
+		return err
+	}
+	build, err := local.ResolveBuild(installed, fs.Arg(0), c.cfg.Aliases)
+	if err != nil {
+		return err
+	}
+
+	cancelCh := make(chan struct{})
+	stop := cancelOnInterrupt(cancelCh)
+	defer stop()
+
+	fmt.Fprintf(c.out, "Exporting Blender %s (%s)\n", build.Version, build.Hash)
+	progressCb := func(progress float64) {
+		fmt.Fprintf(c.out, "\rPacking... %3.0f%%", progress*100)
+	}
+	path, err := local.ExportBuild(c.cfg.Roots(), build.Version, *output, *withConfig, progressCb, cancelCh)
+	fmt.Fprintln(c.out)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(c.out, "Exported to %s\n", path)
+	return nil
+}
+
+// move moves an installed build to another of the install roots.
+func (c *cli) move(args []string) error {
+	fs := newFlagSet("move")
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+	if fs.NArg() != 2 {
+		return fmt.Errorf("%w: move expects a version and an install root", errUsage)
+	}
+
+	roots := c.cfg.Roots()
+	destRoot := ""
+	if want, err := filepath.Abs(fs.Arg(1)); err == nil {
+		for _, root := range roots {
+			if abs, err := filepath.Abs(root); err == nil && abs == want {
+				destRoot = root
+			}
+		}
+	}
+	if destRoot == "" {
+		return fmt.Errorf("%w: %s is neither the download directory nor in install_roots", errUsage, fs.Arg(1))
+	}
+
+	installed, err := local.ScanLocalBuilds(roots...)
+	if err != nil {
+		return err
+	}
+	build, err := local.ResolveBuild(installed, fs.Arg(0), c.cfg.Aliases)
+	if err != nil {
+		return err
+	}
+
+	cancelCh := make(chan struct{})
+	stop := cancelOnInterrupt(cancelCh)
+	defer stop()
+
+	fmt.Fprintf(c.out, "Moving Blender %s (%s)\n", build.Version, build.Hash)
+	progressCb := func(progress float64) {
+		fmt.Fprintf(c.out, "\rMoving... %3.0f%%", progress*100)
+	}
+	dir, err := local.MoveBuild(roots, build.Version, destRoot, progressCb, cancelCh)
+	fmt.Fprintln(c.out)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(c.out, "Moved to %s\n", dir)
+	return nil
+}
+
+// rollback restores the build the last update of an installed build replaced.
+func (c *cli) rollback(args []string) error {
+	fs := newFlagSet("rollback")
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+	if fs.NArg() != 1 {
+		return fmt.Errorf("%w: rollback expects exactly one version", errUsage)
+	}
+
+	installed, err := local.ScanLocalBuilds(c.cfg.Roots()...)
+	if err != nil {
+		return err
+	}
+	build, err := local.ResolveBuild(installed, fs.Arg(0), c.cfg.Aliases)
+	if err != nil {
+		return err
+	}
+	restored, err := local.RollbackBuild(c.cfg.Roots(), c.cfg.DownloadDir, build.Version)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(c.out, "Rolled back Blender %s from %s to %s\n", build.Version, build.Hash, restored.Hash)
+	return nil
+}
+
+// check test runs an installed build, failing with the reason it doesn't run.
+func (c *cli) check(args []string) error {
+	fs := newFlagSet("check")
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+	if fs.NArg() != 1 {
+		return fmt.Errorf("%w: check expects exactly one version", errUsage)
+	}
+
+	installed, err := local.ScanLocalBuilds(c.cfg.Roots()...)
+	if err != nil {
+		return err
+	}
+	build, err := local.ResolveBuild(installed, fs.Arg(0), c.cfg.Aliases)
+	if err != nil {
+		return err
+	}
+	exe, err := local.FindBuildExecutable(c.cfg.Roots(), build.Version)
+	if err != nil {
+		return err
+	}
+	var opts launch.Options
+	if build.HasTag(config.SandboxTag) {
+		if opts, err = c.sandbox(opts, build.Version, ""); err != nil {
+			return err
+		}
+	}
+	if err := local.CheckBuildRuns(exe, opts); err != nil {
+		return fmt.Errorf("Blender %s: %w", build.Version, err)
+	}
+	fmt.Fprintf(c.out, "Blender %s runs\n", build.Version)
+	return nil
+}
+
+// repair checks and fixes every installed build and prints what it found.
+// Builds left damaged fail the command with the verification exit code.
+func (c *cli) repair(args []string) error {
+	fs := newFlagSet("repair")
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+	if fs.NArg() != 0 {
+		return fmt.Errorf("%w: repair takes no arguments", errUsage)
+	}
+
+	// The launcher lists the library from the rebuilt index on its next start
+	var scanCachePath string
+	if cacheDir, err := config.GetCacheDir(); err == nil {
+		scanCachePath = filepath.Join(cacheDir, local.ScanCacheFilename)
+	}
+	report, err := local.RepairLibrary(c.cfg, scanCachePath)
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintf(c.out, "Checked %d builds: %d verified, %d without manifest\n", report.Builds, report.Verified, report.NoManifest)
+	for _, fix := range []struct {
+		count int
+		what  string
+	}{
+		{report.Repaired, "Repaired from their kept archive"},
+		{report.PermissionsFixed, "Executable permissions restored"},
+		{report.MetadataRestored, "version.json restored from --version"},
+	} {
+		if fix.count > 0 {
+			fmt.Fprintf(c.out, "%s: %d\n", fix.what, fix.count)
+		}
+	}
+	for _, dir := range report.Skipped {
+		fmt.Fprintf(c.out, "Skipped %s, not a Blender build\n", dir)
+	}
+	for _, damaged := range report.Damaged {
+		fmt.Fprintf(c.err, "Damaged: %s\n", damaged)
+	}
+	fmt.Fprintf(c.out, "Index rebuilt with %d builds\n", report.Indexed)
+	if len(report.Damaged) > 0 {
+		return fmt.Errorf("%d builds are damaged, download them again: %w", len(report.Damaged), download.ErrVerificationFailed)
+	}
+	return nil
+}
+
+// fetchOnline fetches the builds available for the configured filter and build type.
+func (c *cli) fetchOnline() ([]model.BlenderBuild, error) {
+	builds, err := api.NewAPI().FetchBuilds(c.cfg.VersionFilter, c.cfg.BuildType)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", errNetwork, err)
+	}
+	return builds, nil
+}
+
+// isBlendFile reports whether arg names a .blend file.
+func isBlendFile(arg string) bool {
+	return strings.HasSuffix(strings.ToLower(arg), ".blend")
+}
+
+// cancelOnInterrupt closes cancelCh on Ctrl+C. The returned function stops listening.
+func cancelOnInterrupt(cancelCh chan struct{}) func() {
+	sigCh := make(chan os.Signal, 1)
+	signal.Notify(sigCh, os.Interrupt)
+	done := make(chan struct{})
+	go func() {
+		select {
+		case <-sigCh:
+			close(cancelCh)
+		case <-done:
+		}
+	}()
+	return func() {
+		signal.Stop(sigCh)
+		close(done)
+	}
+}
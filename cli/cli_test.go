@@ -0,0 +1,1031 @@
+package cli
+
+import (
+	"TUI-Blender-Launcher/config"
+	"TUI-Blender-Launcher/download"
+	"TUI-Blender-Launcher/launch"
+	"TUI-Blender-Launcher/local"
+	"TUI-Blender-Launcher/model"
+	"archive/tar"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ulikunitz/xz"
+)
+
+// writeTestBuild installs a fake build in root, in a directory named after the
+// version in meta, with meta as its version.json and, unless script is "",
+// script as its blender executable. It returns the build directory.
+func writeTestBuild(t *testing.T, root, meta, script string) string {
+	t.Helper()
+	var build model.BlenderBuild
+	if err := json.Unmarshal([]byte(meta), &build); err != nil {
+		t.Fatalf("Invalid test metadata: %v", err)
+	}
+	dir := filepath.Join(root, "blender-"+build.Version+"-linux-x64")
+	if err := os.MkdirAll(dir, 0750); err != nil {
+		t.Fatalf("Failed to create %s: %v", dir, err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "version.json"), []byte(meta), 0644); err != nil {
+		t.Fatalf("Failed to write version.json: %v", err)
+	}
+	if script != "" {
+		if err := os.WriteFile(filepath.Join(dir, "blender"), []byte(script), 0755); err != nil {
+			t.Fatalf("Failed to write the executable: %v", err)
+		}
+	}
+	return dir
+}
+
+func TestRepair(t *testing.T) {
+	if runtime.GOOS != "linux" {
+		t.Skip("The fake Blender executable is a shell script")
+	}
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+	cfg := config.DefaultConfig()
+	cfg.DownloadDir = t.TempDir()
+	cfg.InstallRoots = []string{t.TempDir()}
+
+	writeFile := func(path, content string, mode os.FileMode) {
+		t.Helper()
+		if err := os.MkdirAll(filepath.Dir(path), 0750); err != nil {
+			t.Fatalf("Failed to create %s: %v", filepath.Dir(path), err)
+		}
+		if err := os.WriteFile(path, []byte(content), mode); err != nil {
+			t.Fatalf("Failed to write %s: %v", path, err)
+		}
+	}
+
+	// A healthy build
+	good := writeTestBuild(t, cfg.DownloadDir, `{"version": "4.4.1", "hash": "abcdef012345"}`, "#!/bin/sh\n")
+	if err := download.WriteManifest(good); err != nil {
+		t.Fatalf("WriteManifest returned an error: %v", err)
+	}
+
+	// A build restored without version.json and executable bits
+	restored := filepath.Join(cfg.InstallRoots[0], "blender-4.2.1-linux-x64")
+	writeFile(filepath.Join(restored, "blender"), `#!/bin/sh
+echo "Blender 4.2.1 LTS"
+echo "	build commit date: 2024-08-19"
+echo "	build commit time: 09:46"
+echo "	build hash: 396f546c9d82"
+echo "	build branch: blender-v4.2-release"
+`, 0644)
+
+	// A damaged build without a kept archive, and a directory that isn't a build
+	damaged := writeTestBuild(t, cfg.DownloadDir, `{"version": "4.3.0", "hash": "1111111111aa"}`, "#!/bin/sh\n")
+	if err := download.WriteManifest(damaged); err != nil {
+		t.Fatalf("WriteManifest returned an error: %v", err)
+	}
+	writeFile(filepath.Join(damaged, "blender"), "#!/bin/sh\nexit 1\n", 0755)
+	writeFile(filepath.Join(cfg.DownloadDir, "notes", "todo.txt"), "", 0644)
+	writeFile(filepath.Join(cfg.DownloadDir, "notes", "draft"), "", 0644)
+
+	var out, errOut bytes.Buffer
+	c := &cli{cfg: cfg, out: &out, err: &errOut}
+	err := c.repair(nil)
+	if !errors.Is(err, download.ErrVerificationFailed) {
+		t.Errorf("Expected the damaged build to fail the repair, got %v", err)
+	}
+	for _, want := range []string{
+		"Checked 3 builds: 1 verified, 1 without manifest",
+		"Executable permissions restored: 1",
+		"version.json restored from --version: 1",
+		"Skipped " + filepath.Join(cfg.DownloadDir, "notes"),
+		"Index rebuilt with 3 builds",
+	} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("Expected %q in the output:\n%s", want, out.String())
+		}
+	}
+	if !strings.Contains(errOut.String(), "Damaged: 4.3.0") {
+		t.Errorf("Expected 4.3.0 to be reported damaged, got:\n%s", errOut.String())
+	}
+
+	// The restored build is usable again
+	build, err := local.ReadBuildInfo(restored)
+	if err != nil || build == nil {
+		t.Fatalf("Expected version.json to be restored, got %v, %v", build, err)
+	}
+	if build.Version != "4.2.1" || build.Hash != "396f546c9d82" || build.Branch != "blender-v4.2-release" || build.ReleaseCycle != "stable" {
+		t.Errorf("Unexpected restored metadata %+v", build)
+	}
+	if _, err := local.FindBuildExecutable(cfg.Roots(), "4.2.1"); err != nil {
+		t.Errorf("Expected the restored build to launch: %v", err)
+	}
+
+	// What isn't a build is left alone, and the next start lists the rebuilt index
+	if info, err := os.Stat(filepath.Join(cfg.DownloadDir, "notes", "draft")); err != nil || info.Mode().Perm() != 0644 {
+		t.Errorf("Expected a file outside of builds to keep its permissions, got %v, %v", info, err)
+	}
+	cacheDir, err := config.GetCacheDir()
+	if err != nil {
+		t.Fatalf("GetCacheDir returned an error: %v", err)
+	}
+	if cached := local.LoadScanCache(filepath.Join(cacheDir, local.ScanCacheFilename), cfg.Roots()); len(cached) != 3 {
+		t.Errorf("Expected the 3 builds in the index, got %+v", cached)
+	}
+}
+
+func TestImport(t *testing.T) {
+	if runtime.GOOS != "linux" {
+		t.Skip("The fake Blender executable is a shell script")
+	}
+	cfg := config.DefaultConfig()
+	cfg.DownloadDir = t.TempDir()
+	cfg.InstallRoots = nil
+
+	// A build installed by hand, without version.json
+	external := filepath.Join(t.TempDir(), "blender-4.2.1-linux-x64")
+	if err := os.MkdirAll(external, 0750); err != nil {
+		t.Fatalf("Failed to create %s: %v", external, err)
+	}
+	script := `#!/bin/sh
+echo "Blender 4.2.1 LTS"
+echo "	build hash: 396f546c9d82"
+`
+	if err := os.WriteFile(filepath.Join(external, "blender"), []byte(script), 0755); err != nil {
+		t.Fatalf("Failed to write the executable: %v", err)
+	}
+
+	var out, errOut bytes.Buffer
+	c := &cli{cfg: cfg, out: &out, err: &errOut}
+	if err := c.importBuild([]string{filepath.Join(external, "blender")}); err != nil {
+		t.Fatalf("importBuild returned an error: %v", err)
+	}
+	if !strings.Contains(out.String(), "Imported Blender 4.2.1 (396f546c9d82)") {
+		t.Errorf("Unexpected output %q", out.String())
+	}
+	if build, err := local.ReadBuildInfo(external); err != nil || build == nil {
+		t.Errorf("Expected version.json to be written next to the build, got %v, %v", build, err)
+	}
+
+	builds, err := local.ScanLocalBuilds(cfg.Roots()...)
+	if err != nil {
+		t.Fatalf("ScanLocalBuilds returned an error: %v", err)
+	}
+	if len(builds) != 1 || builds[0].Version != "4.2.1" {
+		t.Fatalf("Expected the imported build to be listed, got %+v", builds)
+	}
+	if _, err := local.FindBuildExecutable(cfg.Roots(), "4.2.1"); err != nil {
+		t.Errorf("Expected the imported build to launch: %v", err)
+	}
+
+	// Importing it again is refused, and deleting it keeps the original
+	if err := c.importBuild([]string{external}); err == nil {
+		t.Error("Expected importing an installed version to fail")
+	}
+
+	// As is another copy of it, which is left untouched
+	other := filepath.Join(t.TempDir(), "blender-4.2.1-linux-x64")
+	if err := os.MkdirAll(other, 0750); err != nil {
+		t.Fatalf("Failed to create %s: %v", other, err)
+	}
+	if err := os.WriteFile(filepath.Join(other, "blender"), []byte(script), 0755); err != nil {
+		t.Fatalf("Failed to write the executable: %v", err)
+	}
+	if err := c.importBuild([]string{other}); err == nil {
+		t.Error("Expected importing a copy of an installed version to fail")
+	}
+	if _, err := os.Stat(filepath.Join(other, "version.json")); !os.IsNotExist(err) {
+		t.Errorf("Expected a refused import to leave the copy alone, got %v", err)
+	}
+	if _, err := local.DeleteBuild(cfg.Roots(), "4.2.1", false); err != nil {
+		t.Fatalf("DeleteBuild returned an error: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(external, "blender")); err != nil {
+		t.Errorf("Expected deleting to only remove the link: %v", err)
+	}
+}
+
+func TestListWithoutMetadata(t *testing.T) {
+	if runtime.GOOS != "linux" {
+		t.Skip("The fake Blender executables are shell scripts")
+	}
+	cfg := config.DefaultConfig()
+	cfg.DownloadDir = t.TempDir()
+
+	// Hand-copied builds: one whose executable runs, one whose doesn't, and a
+	// directory without Blender
+	for dir, script := range map[string]string{
+		"my-blender": `#!/bin/sh
+echo "Blender 4.1.1"
+echo "	build commit date: 2024-04-15"
+echo "	build commit time: 10:21"
+echo "	build hash: e1743a0317bc"
+`,
+		"blender-4.0.2-linux-x64": "#!/bin/sh\nexit 1\n",
+	} {
+		path := filepath.Join(cfg.DownloadDir, dir, "blender")
+		if err := os.MkdirAll(filepath.Dir(path), 0750); err != nil {
+			t.Fatalf("Failed to create %s: %v", dir, err)
+		}
+		if err := os.WriteFile(path, []byte(script), 0755); err != nil {
+			t.Fatalf("Failed to write %s: %v", path, err)
+		}
+	}
+	if err := os.MkdirAll(filepath.Join(cfg.DownloadDir, "notes"), 0750); err != nil {
+		t.Fatalf("Failed to create notes: %v", err)
+	}
+
+	var out, errOut bytes.Buffer
+	c := &cli{cfg: cfg, out: &out, err: &errOut}
+	if err := c.list(nil); err != nil {
+		t.Fatalf("list returned an error: %v", err)
+	}
+	want := "4.1.1\t\te1743a0317bc\t2024-04-15 10:21\n4.0.2\t\t\t"
+	if !strings.HasPrefix(out.String(), want) || strings.Count(out.String(), "\n") != 2 {
+		t.Errorf("Expected both builds to be listed, got:\n%s", out.String())
+	}
+
+	// Tagging one gives it a version.json
+	if err := local.SetBuildTags(cfg.Roots(), "4.1.1", []string{"studio"}, false); err != nil {
+		t.Fatalf("SetBuildTags returned an error: %v", err)
+	}
+	build, err := local.ReadBuildInfo(filepath.Join(cfg.DownloadDir, "my-blender"))
+	if err != nil || build == nil || build.Hash != "e1743a0317bc" || !build.HasTag("studio") {
+		t.Errorf("Expected version.json with the probed metadata and the tag, got %+v, %v", build, err)
+	}
+}
+
+func TestRetention(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.DownloadDir = t.TempDir()
+	cfg.InstallRoots = []string{t.TempDir()}
+	cfg.KeepPerSeries = 1
+
+	// Paths are relative to the download directory
+	other, err := filepath.Rel(cfg.DownloadDir, filepath.Join(cfg.InstallRoots[0], "blender-4.3.0-beta-linux-x64"))
+	if err != nil {
+		t.Fatalf("Failed to relate the install roots: %v", err)
+	}
+	builds := map[string]string{
+		"blender-4.3.2-linux-x64": `{"version": "4.3.2", "file_mtime": 1728100000}`,
+		filepath.Join(download.OldBuildsDir, "blender-4.3.2-linux-x64_20241001_120000"): `{"version": "4.3.2", "file_mtime": 1727700000}`,
+		filepath.Join(download.OldBuildsDir, "blender-4.3.1-linux-x64_20240920_120000"): `{"version": "4.3.1", "file_mtime": 1726800000}`,
+		"blender-4.3.0-linux-x64":        `{"version": "4.3.0", "file_mtime": 1726000000, "favorite": true}`,
+		"blender-4.3.0-stable-linux-x64": `{"version": "4.3.0", "file_mtime": 1726000000, "release_cycle": "stable"}`,
+		other:                            `{"version": "4.3.0", "file_mtime": 1725900000, "release_cycle": "beta"}`,
+		"blender-4.2.9-linux-x64":        `{"version": "4.2.9", "file_mtime": 1725000000}`,
+	}
+	for dir, meta := range builds {
+		path := filepath.Join(cfg.DownloadDir, dir)
+		if err := os.MkdirAll(path, 0750); err != nil {
+			t.Fatalf("Failed to create %s: %v", path, err)
+		}
+		if err := os.WriteFile(filepath.Join(path, "version.json"), []byte(meta), 0644); err != nil {
+			t.Fatalf("Failed to write version.json: %v", err)
+		}
+	}
+
+	var out, errOut bytes.Buffer
+	c := &cli{cfg: cfg, out: &out, err: &errOut}
+	c.enforceRetention()
+	if !strings.Contains(out.String(), "Removed 3 old builds") || errOut.Len() > 0 {
+		t.Errorf("Expected three builds to be removed, got:\n%s%s", out.String(), errOut.String())
+	}
+	for dir := range builds {
+		_, err := os.Stat(filepath.Join(cfg.DownloadDir, dir))
+		if removed := strings.HasPrefix(dir, download.OldBuildsDir) || dir == other; removed != os.IsNotExist(err) {
+			t.Errorf("Expected %s to be removed: %v, got %v", dir, removed, err)
+		}
+	}
+}
+
+func TestExport(t *testing.T) {
+	if runtime.GOOS != "linux" {
+		t.Skip("The fake Blender executable is a shell script")
+	}
+	cfg := config.DefaultConfig()
+	cfg.DownloadDir = t.TempDir()
+	configHome := t.TempDir()
+	t.Setenv("XDG_CONFIG_HOME", configHome)
+
+	writeFile := func(path, content string, mode os.FileMode) {
+		t.Helper()
+		if err := os.MkdirAll(filepath.Dir(path), 0750); err != nil {
+			t.Fatalf("Failed to create %s: %v", filepath.Dir(path), err)
+		}
+		if err := os.WriteFile(path, []byte(content), mode); err != nil {
+			t.Fatalf("Failed to write %s: %v", path, err)
+		}
+	}
+	writeTestBuild(t, cfg.DownloadDir, `{"version": "4.2.1", "hash": "396f546c9d82"}`, "#!/bin/sh\n")
+	writeFile(filepath.Join(configHome, "blender", "4.2", "config", "userpref.blend"), "prefs", 0644)
+
+	tests := []struct {
+		name       string
+		args       []string
+		wantConfig bool
+	}{
+		{name: "build only", args: []string{"4.2.1"}},
+		{name: "with config", args: []string{"--config", "4.2"}, wantConfig: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			output := t.TempDir()
+			var out, errOut bytes.Buffer
+			c := &cli{cfg: cfg, out: &out, err: &errOut}
+			if err := c.export(append([]string{"--output", output}, tt.args...)); err != nil {
+				t.Fatalf("export returned an error: %v", err)
+			}
+			archive := filepath.Join(output, "blender-4.2.1-linux-x64.tar.xz")
+			if !strings.Contains(out.String(), "Exported to "+archive) {
+				t.Errorf("Unexpected output %q", out.String())
+			}
+
+			names := archiveNames(t, archive)
+			for _, want := range []string{"blender-4.2.1-linux-x64/blender", "blender-4.2.1-linux-x64/version.json"} {
+				if !names[want] {
+					t.Errorf("Expected %s in the archive, got %v", want, names)
+				}
+			}
+			if got := names["blender-4.2.1-linux-x64/portable/config/userpref.blend"]; got != tt.wantConfig {
+				t.Errorf("Expected the config in the archive to be %v, got %v", tt.wantConfig, got)
+			}
+		})
+	}
+}
+
+// archiveNames lists the files in a .tar.xz archive.
+func archiveNames(t *testing.T, path string) map[string]bool {
+	t.Helper()
+	file, err := os.Open(path)
+	if err != nil {
+		t.Fatalf("Failed to open %s: %v", path, err)
+	}
+	defer file.Close()
+	xzReader, err := xz.NewReader(file)
+	if err != nil {
+		t.Fatalf("Failed to read %s: %v", path, err)
+	}
+	names := make(map[string]bool)
+	tarReader := tar.NewReader(xzReader)
+	for {
+		header, err := tarReader.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatalf("Failed to read %s: %v", path, err)
+		}
+		names[header.Name] = true
+	}
+	return names
+}
+
+func TestMove(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.DownloadDir = t.TempDir()
+	cfg.InstallRoots = []string{t.TempDir()}
+	writeTestBuild(t, cfg.DownloadDir, `{"version": "4.2.1", "hash": "396f546c9d82"}`, "")
+	if _, err := local.SetCurrentBuild(cfg.Roots(), cfg.DownloadDir, "4.2.1"); err != nil {
+		t.Skipf("Symlinks unavailable: %v", err)
+	}
+
+	var out, errOut bytes.Buffer
+	c := &cli{cfg: cfg, out: &out, err: &errOut}
+	if err := c.move([]string{"4.2.1", t.TempDir()}); !errors.Is(err, errUsage) {
+		t.Errorf("Expected moving outside the install roots to be refused, got %v", err)
+	}
+	if err := c.move([]string{"4.2", cfg.InstallRoots[0]}); err != nil {
+		t.Fatalf("move returned an error: %v", err)
+	}
+
+	moved := filepath.Join(cfg.InstallRoots[0], "blender-4.2.1-linux-x64")
+	if !strings.Contains(out.String(), "Moved to "+moved) {
+		t.Errorf("Unexpected output %q", out.String())
+	}
+	if dir, err := local.FindBuildDir(cfg.Roots(), "4.2.1"); err != nil || dir != moved {
+		t.Errorf("Expected the build in %s, got %s, %v", moved, dir, err)
+	}
+	if current := local.CurrentBuild(cfg.DownloadDir); current == nil || current.Version != "4.2.1" {
+		t.Errorf("Expected the current link to follow the build, got %+v", current)
+	}
+}
+
+func TestRollback(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.DownloadDir = t.TempDir()
+	writeVersion := func(dir, version, hash string) {
+		t.Helper()
+		if err := os.MkdirAll(dir, 0750); err != nil {
+			t.Fatalf("Failed to create %s: %v", dir, err)
+		}
+		info := `{"version": "` + version + `", "hash": "` + hash + `"}`
+		if err := os.WriteFile(filepath.Join(dir, "version.json"), []byte(info), 0644); err != nil {
+			t.Fatalf("Failed to write version.json: %v", err)
+		}
+	}
+	name := "blender-4.5.0-alpha+main.b0b0b0b0b0b0-linux.x86_64-release"
+	writeVersion(filepath.Join(cfg.DownloadDir, name), "4.5.0", "b0b0b0b0b0b0")
+
+	var out, errOut bytes.Buffer
+	c := &cli{cfg: cfg, out: &out, err: &errOut}
+	if err := c.rollback([]string{"4.5.0"}); !errors.Is(err, local.ErrNoBackup) {
+		t.Fatalf("Expected ErrNoBackup without a backup, got %v", err)
+	}
+
+	oldName := "blender-4.5.0-alpha+main.a1a1a1a1a1a1-linux.x86_64-release"
+	oldBuilds := filepath.Join(cfg.DownloadDir, download.OldBuildsDir)
+	writeVersion(filepath.Join(oldBuilds, oldName+"_20250101_120000"), "4.5.0", "a1a1a1a1a1a1")
+	writeVersion(filepath.Join(oldBuilds, oldName+"_20240101_120000"), "4.5.0", "c2c2c2c2c2c2")
+	if err := c.rollback([]string{"4.5.0"}); err != nil {
+		t.Fatalf("rollback returned an error: %v", err)
+	}
+	if !strings.Contains(out.String(), "from b0b0b0b0b0b0 to a1a1a1a1a1a1") {
+		t.Errorf("Unexpected output %q", out.String())
+	}
+	dir, err := local.FindBuildDir(cfg.Roots(), "4.5.0")
+	if err != nil || dir != filepath.Join(cfg.DownloadDir, oldName) {
+		t.Errorf("Expected the newest backup restored, got %s, %v", dir, err)
+	}
+
+	// Rolling back again brings the replaced build back
+	out.Reset()
+	if err := c.rollback([]string{"4.5.0"}); err != nil {
+		t.Fatalf("second rollback returned an error: %v", err)
+	}
+	if !strings.Contains(out.String(), "from a1a1a1a1a1a1 to b0b0b0b0b0b0") {
+		t.Errorf("Unexpected output %q", out.String())
+	}
+
+	// A build in another install root rolls back to the backup in that root
+	root := t.TempDir()
+	c.cfg.InstallRoots = []string{root}
+	otherName := "blender-4.4.0-stable+v44.d3d3d3d3d3d3-linux.x86_64-release"
+	writeVersion(filepath.Join(root, otherName), "4.4.0", "d3d3d3d3d3d3")
+	writeVersion(filepath.Join(root, download.OldBuildsDir, otherName+"_20250101_120000"), "4.4.0", "e4e4e4e4e4e4")
+	out.Reset()
+	if err := c.rollback([]string{"4.4.0"}); err != nil {
+		t.Fatalf("rollback in an install root returned an error: %v", err)
+	}
+	if !strings.Contains(out.String(), "from d3d3d3d3d3d3 to e4e4e4e4e4e4") {
+		t.Errorf("Unexpected output %q", out.String())
+	}
+	if dir, err := local.FindBuildDir(c.cfg.Roots(), "4.4.0"); err != nil || dir != filepath.Join(root, otherName) {
+		t.Errorf("Expected the backup restored in its own root, got %s, %v", dir, err)
+	}
+}
+
+func TestCheck(t *testing.T) {
+	if runtime.GOOS != "linux" {
+		t.Skip("The fake Blender executables are shell scripts")
+	}
+	tests := []struct {
+		name    string
+		script  string
+		wantErr string // Empty if the build runs
+	}{
+		{"runs", "#!/bin/sh\necho 'Blender 4.4.1'\n", ""},
+		{"missing library", "#!/bin/sh\necho 'blender: error while loading shared libraries: libXi.so.6: cannot open shared object file' >&2\nexit 127\n", "missing library libXi.so.6"},
+		{"crashes", "#!/bin/sh\necho 'Segmentation fault'\nexit 139\n", "Segmentation fault"},
+		{"no version", "#!/bin/sh\n", "no version reported"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := config.DefaultConfig()
+			cfg.DownloadDir = t.TempDir()
+			writeTestBuild(t, cfg.DownloadDir, `{"version": "4.4.1"}`, tt.script)
+
+			var out, errOut bytes.Buffer
+			c := &cli{cfg: cfg, out: &out, err: &errOut}
+			err := c.check([]string{"4.4.1"})
+			if tt.wantErr == "" {
+				if err != nil || !strings.Contains(out.String(), "Blender 4.4.1 runs") {
+					t.Errorf("Expected the build to run, got %v, %q", err, out.String())
+				}
+				return
+			}
+			if ExitCode(err) != ExitDoesNotRun || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Expected %q with exit code %d, got %v", tt.wantErr, ExitDoesNotRun, err)
+			}
+		})
+	}
+}
+
+func TestLaunchEnvProfile(t *testing.T) {
+	if runtime.GOOS != "linux" {
+		t.Skip("The fake Blender executable is a shell script")
+	}
+	t.Setenv("XDG_STATE_HOME", t.TempDir())
+	cfg := config.DefaultConfig()
+	cfg.DownloadDir = t.TempDir()
+	cfg.EnvProfiles = map[string]map[string]string{
+		"hip": {"HSA_OVERRIDE_GFX_VERSION": "11.0.0"},
+	}
+	script := "#!/bin/sh\nprintf '%s' \"$HSA_OVERRIDE_GFX_VERSION\" > \"$1\"\n"
+	writeTestBuild(t, cfg.DownloadDir, `{"version": "4.4.1"}`, script)
+
+	tests := []struct {
+		name    string
+		args    []string
+		profile string // Configured env_profile
+		want    string // Variable seen by Blender
+		wantErr bool
+	}{
+		{"without profile", nil, "", "", false},
+		{"picked profile", []string{"--env", "hip"}, "", "11.0.0", false},
+		{"configured profile", nil, "hip", "11.0.0", false},
+		{"unknown profile", []string{"--env", "cuda"}, "", "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("HSA_OVERRIDE_GFX_VERSION", "")
+			cfg.EnvProfile = tt.profile
+			seen := filepath.Join(t.TempDir(), "env")
+			var out, errOut bytes.Buffer
+			c := &cli{cfg: cfg, out: &out, err: &errOut, quiet: true}
+			err := c.launch(append(tt.args, "4.4.1", seen))
+			if tt.wantErr {
+				if ExitCode(err) != ExitUsage {
+					t.Errorf("Expected a usage error, got %v", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("launch returned an error: %v", err)
+			}
+			got, err := os.ReadFile(seen)
+			if err != nil {
+				t.Fatalf("Blender didn't run: %v", err)
+			}
+			if string(got) != tt.want {
+				t.Errorf("Expected HSA_OVERRIDE_GFX_VERSION=%q, got %q", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestLaunchLogs(t *testing.T) {
+	if runtime.GOOS != "linux" {
+		t.Skip("The fake Blender executable is a shell script")
+	}
+	stateHome := t.TempDir()
+	t.Setenv("XDG_STATE_HOME", stateHome)
+	cfg := config.DefaultConfig()
+	cfg.DownloadDir = t.TempDir()
+	cfg.LaunchLogs = true
+	script := "#!/bin/sh\necho 'Read prefs'\necho 'Segmentation fault' >&2\n"
+	writeTestBuild(t, cfg.DownloadDir, `{"version": "4.4.1"}`, script)
+
+	// Logs of earlier sessions beyond the newest ones are rotated out
+	logDir := filepath.Join(stateHome, config.AppName, "logs")
+	if err := os.MkdirAll(logDir, 0750); err != nil {
+		t.Fatalf("Failed to create %s: %v", logDir, err)
+	}
+	old := time.Now().Add(-time.Hour)
+	for i := range 25 {
+		path := filepath.Join(logDir, fmt.Sprintf("blender-4.2.%d-20250101-120000.log", i))
+		if err := os.WriteFile(path, nil, 0644); err != nil {
+			t.Fatalf("Failed to write %s: %v", path, err)
+		}
+		_ = os.Chtimes(path, old, old.Add(time.Duration(i)*time.Second))
+	}
+	// An old log still written to by a running session stays
+	running, err := os.Open(filepath.Join(logDir, "blender-4.2.1-20250101-120000.log"))
+	if err != nil {
+		t.Fatalf("Failed to open the running session's log: %v", err)
+	}
+	defer running.Close()
+
+	var out, errOut bytes.Buffer
+	c := &cli{cfg: cfg, out: &out, err: &errOut, quiet: true}
+	if err := c.launch([]string{"4.4.1"}); err != nil {
+		t.Fatalf("launch returned an error: %v", err)
+	}
+
+	logs, _ := filepath.Glob(filepath.Join(logDir, "blender-*.log"))
+	if len(logs) != launch.LaunchLogsKept+1 {
+		t.Errorf("Expected %d logs and the open one to be kept, got %d", launch.LaunchLogsKept, len(logs))
+	}
+	if _, err := os.Stat(filepath.Join(logDir, "blender-4.2.0-20250101-120000.log")); !os.IsNotExist(err) {
+		t.Errorf("Expected the oldest log to be removed, got %v", err)
+	}
+	if _, err := os.Stat(running.Name()); err != nil {
+		t.Errorf("Expected the open log to be kept, got %v", err)
+	}
+	session, _ := filepath.Glob(filepath.Join(logDir, "blender-4.4.1-*.log"))
+	if len(session) != 1 {
+		t.Fatalf("Expected a log of the session, got %v", session)
+	}
+	if log, err := os.ReadFile(session[0]); err != nil || !strings.Contains(string(log), "Read prefs") || !strings.Contains(string(log), "Segmentation fault") {
+		t.Errorf("Expected Blender's output in the log, got %q, %v", log, err)
+	}
+
+	// A second session right after gets a log of its own
+	if err := c.launch([]string{"4.4.1"}); err != nil {
+		t.Fatalf("launch returned an error: %v", err)
+	}
+	if session, _ := filepath.Glob(filepath.Join(logDir, "blender-4.4.1-*.log")); len(session) != 2 {
+		t.Errorf("Expected a log per session, got %v", session)
+	}
+}
+
+func TestLaunchGPUOffload(t *testing.T) {
+	if runtime.GOOS != "linux" {
+		t.Skip("The fake Blender executable and prime-run are shell scripts")
+	}
+	t.Setenv("XDG_STATE_HOME", t.TempDir())
+	cfg := config.DefaultConfig()
+	cfg.DownloadDir = t.TempDir()
+	script := "#!/bin/sh\nprintf '%s' \"${DRI_PRIME}${WRAPPED}\" > \"$1\"\n"
+	writeTestBuild(t, cfg.DownloadDir, `{"version": "4.4.1"}`, script)
+	bin := t.TempDir()
+	if err := os.WriteFile(filepath.Join(bin, "prime-run"), []byte("#!/bin/sh\nWRAPPED=prime-run exec \"$@\"\n"), 0755); err != nil {
+		t.Fatalf("Failed to write prime-run: %v", err)
+	}
+	t.Setenv("PATH", bin+string(os.PathListSeparator)+os.Getenv("PATH"))
+
+	tests := []struct {
+		name       string
+		offload    string // Configured gpu_offload
+		integrated bool
+		want       string // What Blender saw of the offload
+		wantErr    bool
+	}{
+		{"no offload", "", false, "", false},
+		{"dri-prime", "dri-prime", false, "1", false},
+		{"prime-run", "prime-run", false, "prime-run", false},
+		{"integrated", "prime-run", true, "", false},
+		{"unknown", "bumblebee", false, "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("DRI_PRIME", "")
+			t.Setenv("WRAPPED", "")
+			cfg.GPUOffload = tt.offload
+			seen := filepath.Join(t.TempDir(), "gpu")
+			args := []string{"4.4.1", seen}
+			if tt.integrated {
+				args = append([]string{"--integrated"}, args...)
+			}
+			var out, errOut bytes.Buffer
+			c := &cli{cfg: cfg, out: &out, err: &errOut, quiet: true}
+			err := c.launch(args)
+			if tt.wantErr {
+				if !errors.Is(err, launch.ErrUnknownOffload) {
+					t.Errorf("Expected the unknown offload reported, got %v", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("launch returned an error: %v", err)
+			}
+			if got, err := os.ReadFile(seen); err != nil || string(got) != tt.want {
+				t.Errorf("Expected Blender to see %q, got %q, %v", tt.want, got, err)
+			}
+		})
+	}
+}
+
+func TestLaunchTemplate(t *testing.T) {
+	if runtime.GOOS != "linux" {
+		t.Skip("The fake Blender executable and wrapper are shell scripts")
+	}
+	t.Setenv("XDG_STATE_HOME", t.TempDir())
+	cfg := config.DefaultConfig()
+	cfg.DownloadDir = t.TempDir()
+	script := "#!/bin/sh\nprintf '%s %s' \"$WRAPPED\" \"$2\" > \"$1\"\n"
+	writeTestBuild(t, cfg.DownloadDir, `{"version": "4.4.1"}`, script)
+	bin := t.TempDir()
+	if err := os.WriteFile(filepath.Join(bin, "wrap"), []byte("#!/bin/sh\nWRAPPED=wrap exec \"$@\"\n"), 0755); err != nil {
+		t.Fatalf("Failed to write the wrapper: %v", err)
+	}
+	t.Setenv("PATH", bin+string(os.PathListSeparator)+os.Getenv("PATH"))
+
+	tests := []struct {
+		name     string
+		template string
+		want     string // The wrapper Blender ran through and its second argument
+		wantErr  bool
+	}{
+		{"no template", "", " it's me", false},
+		{"wrapper", "wrap {exe} {args}", "wrap it's me", false},
+		{"shell command", "sh -c 'WRAPPED=sh {exe} {args}'", "sh it's me", false},
+		{"no exe", "wrap blender {args}", "", true},
+		{"unterminated quote", "sh -c '{exe} {args}", "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("WRAPPED", "")
+			cfg.LaunchTemplate = tt.template
+			seen := filepath.Join(t.TempDir(), "wrapped")
+			var out, errOut bytes.Buffer
+			c := &cli{cfg: cfg, out: &out, err: &errOut, quiet: true}
+			err := c.launch([]string{"4.4.1", seen, "it's me"})
+			if tt.wantErr {
+				if !errors.Is(err, launch.ErrBadTemplate) {
+					t.Errorf("Expected the template to be rejected, got %v", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("launch returned an error: %v", err)
+			}
+			if got, err := os.ReadFile(seen); err != nil || string(got) != tt.want {
+				t.Errorf("Expected Blender to see %q, got %q, %v", tt.want, got, err)
+			}
+		})
+	}
+}
+
+func TestLaunchLanguage(t *testing.T) {
+	if runtime.GOOS != "linux" {
+		t.Skip("The fake Blender executable is a shell script")
+	}
+	t.Setenv("XDG_STATE_HOME", t.TempDir())
+	t.Setenv("LANG", "en_US.UTF-8")
+	t.Setenv("LC_ALL", "")
+	t.Setenv("LANGUAGE", "")
+	cfg := config.DefaultConfig()
+	cfg.DownloadDir = t.TempDir()
+	cfg.EnvProfiles = map[string]map[string]string{"german": {"LANGUAGE": "de"}}
+	script := "#!/bin/sh\nprintf '%s %s %s' \"$LANG\" \"$LC_ALL\" \"$LANGUAGE\" > \"$1\"\n"
+	writeTestBuild(t, cfg.DownloadDir, `{"version": "4.4.1"}`, script)
+
+	tests := []struct {
+		name     string
+		language string   // Configured language
+		flags    []string // Flags passed to launch
+		want     string   // LANG, LC_ALL and LANGUAGE as Blender saw them
+	}{
+		{"system language", "", nil, "en_US.UTF-8  "},
+		{"configured", "fr_FR", nil, "fr_FR.UTF-8 fr_FR.UTF-8 fr_FR"},
+		{"flag", "fr_FR", []string{"--lang", "ja_JP.eucJP"}, "ja_JP.eucJP ja_JP.eucJP ja_JP"},
+		{"env profile wins", "fr_FR", []string{"--env", "german"}, "fr_FR.UTF-8 fr_FR.UTF-8 de"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg.Language = tt.language
+			seen := filepath.Join(t.TempDir(), "language")
+			var out, errOut bytes.Buffer
+			c := &cli{cfg: cfg, out: &out, err: &errOut, quiet: true}
+			if err := c.launch(append(tt.flags, "4.4.1", seen)); err != nil {
+				t.Fatalf("launch returned an error: %v", err)
+			}
+			if got, err := os.ReadFile(seen); err != nil || string(got) != tt.want {
+				t.Errorf("Expected Blender to see %q, got %q, %v", tt.want, got, err)
+			}
+		})
+	}
+}
+
+func TestLaunchDisplay(t *testing.T) {
+	if runtime.GOOS != "linux" {
+		t.Skip("The fake Blender executable is a shell script")
+	}
+	t.Setenv("XDG_STATE_HOME", t.TempDir())
+	t.Setenv("WAYLAND_DISPLAY", "wayland-0")
+	t.Setenv("DISPLAY", ":0")
+	cfg := config.DefaultConfig()
+	cfg.DownloadDir = t.TempDir()
+	script := "#!/bin/sh\nprintf '[%s] [%s]' \"$WAYLAND_DISPLAY\" \"$DISPLAY\" > \"$1\"\n"
+	writeTestBuild(t, cfg.DownloadDir, `{"version": "4.4.1"}`, script)
+
+	tests := []struct {
+		display string // Value of --display
+		want    string // WAYLAND_DISPLAY and DISPLAY as Blender saw them
+	}{
+		{"", "[wayland-0] [:0]"},
+		{"x11", "[] [:0]"},
+		{"wayland", "[wayland-0] []"},
+		{"mir", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.display, func(t *testing.T) {
+			seen := filepath.Join(t.TempDir(), "display")
+			var out, errOut bytes.Buffer
+			c := &cli{cfg: cfg, out: &out, err: &errOut, quiet: true}
+			err := c.launch([]string{"--display", tt.display, "4.4.1", seen})
+			if tt.want == "" {
+				if !errors.Is(err, launch.ErrUnknownDisplay) {
+					t.Errorf("Expected ErrUnknownDisplay, got %v", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("launch returned an error: %v", err)
+			}
+			if got, err := os.ReadFile(seen); err != nil || string(got) != tt.want {
+				t.Errorf("Expected Blender to see %q, got %q, %v", tt.want, got, err)
+			}
+		})
+	}
+}
+
+func TestLaunchSandbox(t *testing.T) {
+	if runtime.GOOS != "linux" {
+		t.Skip("The fake Blender executable is a shell script")
+	}
+	t.Setenv("XDG_STATE_HOME", t.TempDir())
+	cfg := config.DefaultConfig()
+	cfg.DownloadDir = t.TempDir()
+	cfg.LaunchDir = t.TempDir()
+	write := func(path, content string, mode os.FileMode) {
+		t.Helper()
+		if err := os.MkdirAll(filepath.Dir(path), 0750); err != nil {
+			t.Fatalf("Failed to create %s: %v", filepath.Dir(path), err)
+		}
+		if err := os.WriteFile(path, []byte(content), mode); err != nil {
+			t.Fatalf("Failed to write %s: %v", path, err)
+		}
+	}
+	trusted := writeTestBuild(t, cfg.DownloadDir, `{"version": "4.4.1"}`, "#!/bin/sh\nprintf direct > \"$1\"\n")
+	untrusted := writeTestBuild(t, cfg.DownloadDir, `{"version": "5.0.0", "tags": ["sandbox"]}`, "#!/bin/sh\nprintf direct > \"$1\"\n")
+	// The sandbox records how it was called instead of running Blender
+	sandboxSeen := filepath.Join(t.TempDir(), "sandbox")
+	sandbox := filepath.Join(t.TempDir(), "sandbox.sh")
+	write(sandbox, "#!/bin/sh\nprintf '%s\\n' \"$@\" > "+sandboxSeen+"\n", 0755)
+
+	tests := []struct {
+		name    string
+		sandbox string   // Configured sandbox command
+		args    []string // Arguments of launch, the last being where Blender writes
+		want    string   // What the sandbox saw, "" if Blender ran directly
+		wantErr error
+	}{
+		{"untagged", sandbox + " {exe} {args}", []string{"4.4.1"}, "", nil},
+		{"tagged", sandbox + " --bind={project} --ro-bind {build} {build} {exe} {args}", []string{"5.0.0"},
+			"--bind=" + cfg.LaunchDir + "\n--ro-bind\n" + untrusted + "\n" + untrusted + "\n" + filepath.Join(untrusted, "blender") + "\n", nil},
+		{"flag", sandbox + " {exe} {args}", []string{"--sandbox", "4.4.1"}, filepath.Join(trusted, "blender") + "\n", nil},
+		{"no sandbox command", "", []string{"5.0.0"}, "", launch.ErrNoSandbox},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			os.Remove(sandboxSeen)
+			cfg.Sandbox = tt.sandbox
+			seen := filepath.Join(t.TempDir(), "seen")
+			var out, errOut bytes.Buffer
+			c := &cli{cfg: cfg, out: &out, err: &errOut, quiet: true}
+			err := c.launch(append(tt.args, seen))
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Errorf("Expected %v, got %v", tt.wantErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("launch returned an error: %v", err)
+			}
+			if tt.want == "" {
+				if got, err := os.ReadFile(seen); err != nil || string(got) != "direct" {
+					t.Errorf("Expected Blender to run directly, got %q, %v", got, err)
+				}
+				return
+			}
+			if got, err := os.ReadFile(sandboxSeen); err != nil || string(got) != tt.want+seen+"\n" {
+				t.Errorf("Expected the sandbox to get %q, got %q, %v", tt.want+seen+"\n", got, err)
+			}
+		})
+	}
+}
+
+func TestLaunchPythonPath(t *testing.T) {
+	if runtime.GOOS != "linux" {
+		t.Skip("The fake Blender executable is a shell script")
+	}
+	t.Setenv("XDG_STATE_HOME", t.TempDir())
+	t.Setenv("PYTHONPATH", "/system")
+	cfg := config.DefaultConfig()
+	cfg.DownloadDir = t.TempDir()
+	cfg.EnvProfiles = map[string]map[string]string{"studio": {"PYTHONPATH": "/studio"}}
+	script := "#!/bin/sh\nfor seen; do :; done\nprintf '%s|%s' \"$1\" \"$PYTHONPATH\" > \"$seen\"\n"
+	writeTestBuild(t, cfg.DownloadDir, `{"version": "4.4.1"}`, script)
+
+	tests := []struct {
+		name        string
+		pythonPaths map[string][]string
+		flags       []string
+		want        string // First argument and PYTHONPATH as Blender saw them, the file going first if the argument is left out
+	}{
+		{"none", nil, nil, "|/system"},
+		{"series", map[string][]string{"4.4": {"/modules/a", "/modules/b"}}, nil, "--python-use-system-env|/modules/a:/modules/b"},
+		{"env profile", nil, []string{"--env", "studio"}, "--python-use-system-env|/studio"},
+		{"both", map[string][]string{"4.4.1": {"/modules/a"}}, []string{"--env", "studio"}, "--python-use-system-env|/modules/a:/studio"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg.PythonPaths = tt.pythonPaths
+			seen := filepath.Join(t.TempDir(), "python")
+			var out, errOut bytes.Buffer
+			c := &cli{cfg: cfg, out: &out, err: &errOut, quiet: true}
+			if err := c.launch(append(tt.flags, "4.4.1", seen)); err != nil {
+				t.Fatalf("launch returned an error: %v", err)
+			}
+			want := tt.want
+			if strings.HasPrefix(want, "|") {
+				want = seen + want
+			}
+			if got, err := os.ReadFile(seen); err != nil || string(got) != want {
+				t.Errorf("Expected Blender to see %q, got %q, %v", want, got, err)
+			}
+		})
+	}
+}
+
+func TestLaunchProjectProfile(t *testing.T) {
+	if runtime.GOOS != "linux" {
+		t.Skip("The fake Blender executable is a shell script")
+	}
+	t.Setenv("XDG_STATE_HOME", t.TempDir())
+	t.Setenv("OCIO", "")
+	project, other := t.TempDir(), t.TempDir()
+	cfg := config.DefaultConfig()
+	cfg.DownloadDir = t.TempDir()
+	cfg.EnvProfiles = map[string]map[string]string{
+		"aces": {"OCIO": "/studio/aces.ocio", launch.AssetLibrariesVar: "/studio/props" + string(os.PathListSeparator) + "/studio/sets"},
+	}
+	cfg.Projects = []config.Project{{Path: project, Build: "4.4.1", EnvProfile: "aces"}}
+	script := "#!/bin/sh\nfor file; do :; done\nprintf '%s|%s|%s' \"$OCIO\" \"$1\" \"$2\" > \"$file.seen\"\n"
+	writeTestBuild(t, cfg.DownloadDir, `{"version": "4.4.1"}`, script)
+
+	// Files of the project open with its env profile
+	file := filepath.Join(project, "shot.blend")
+	c := &cli{cfg: cfg, out: io.Discard, err: io.Discard, quiet: true}
+	if err := c.launch([]string{file}); err != nil {
+		t.Fatalf("launch returned an error: %v", err)
+	}
+	seen, err := os.ReadFile(file + ".seen")
+	if err != nil {
+		t.Fatalf("Expected Blender to run: %v", err)
+	}
+	parts := strings.SplitN(string(seen), "|", 3)
+	if parts[0] != "/studio/aces.ocio" {
+		t.Errorf("Expected the OCIO config of the profile, got %q", parts[0])
+	}
+	if parts[1] != "--python-expr" || !strings.Contains(parts[2], `["/studio/props", "/studio/sets"]`) {
+		t.Errorf("Expected Blender to add the asset libraries, got %q", seen)
+	}
+
+	// Others don't
+	file = filepath.Join(other, "test.blend")
+	if err := c.launch([]string{"4.4.1", file}); err != nil {
+		t.Fatalf("launch returned an error: %v", err)
+	}
+	if seen, err := os.ReadFile(file + ".seen"); err != nil || string(seen) != "|"+file+"|" {
+		t.Errorf("Expected no profile outside the project, got %q, %v", seen, err)
+	}
+}
+
+func TestPassThrough(t *testing.T) {
+	if runtime.GOOS != "linux" {
+		t.Skip("The fake Blender executable is a shell script")
+	}
+	t.Setenv("XDG_STATE_HOME", t.TempDir())
+	cfg := config.DefaultConfig()
+	cfg.DownloadDir = t.TempDir()
+	seen := filepath.Join(t.TempDir(), "args")
+	script := "#!/bin/sh\necho \"$@\" > " + seen + "\npwd > " + seen + ".pwd\n[ \"$1\" = --fail ] && exit 3\nexit 0\n"
+	writeTestBuild(t, cfg.DownloadDir, `{"version": "4.4.1"}`, script)
+	scene := filepath.Join(t.TempDir(), "scene.blend")
+	// Relative arguments resolve where the launcher was started, not in the
+	// scene's project or launch_dir
+	cfg.Projects = []config.Project{{Path: filepath.Dir(scene)}}
+	cfg.LaunchDir = t.TempDir()
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatalf("Failed to get the working directory: %v", err)
+	}
+
+	if code := Run(cfg, []string{"-q", "--", scene}); code != ExitUsage {
+		t.Errorf("Expected a usage error without a default build, got exit code %d", code)
+	}
+	if _, err := local.SetCurrentBuild(cfg.Roots(), cfg.DownloadDir, "4.4.1"); err != nil {
+		t.Fatalf("Failed to set the default build: %v", err)
+	}
+
+	tests := []struct {
+		name     string
+		args     []string
+		wantArgs string
+		wantCode int
+	}{
+		{"file and flags", []string{scene, "--factory-startup"}, scene + " --factory-startup", ExitOK},
+		{"no arguments", nil, "", ExitOK},
+		{"blender's exit code", []string{"--fail"}, "--fail", 3},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if code := Run(cfg, append([]string{"-q", "--"}, tt.args...)); code != tt.wantCode {
+				t.Errorf("Expected exit code %d, got %d", tt.wantCode, code)
+			}
+			if got, err := os.ReadFile(seen); err != nil || strings.TrimSpace(string(got)) != tt.wantArgs {
+				t.Errorf("Expected Blender to get %q, got %q, %v", tt.wantArgs, got, err)
+			}
+			if got, err := os.ReadFile(seen + ".pwd"); err != nil || strings.TrimSpace(string(got)) != wd {
+				t.Errorf("Expected Blender to run in %s, got %q, %v", wd, got, err)
+			}
+		})
+	}
+}
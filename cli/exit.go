@@ -0,0 +1,63 @@
+package cli
+
+import (
+	"TUI-Blender-Launcher/download"
+	"TUI-Blender-Launcher/local"
+	"errors"
+	"net"
+	"net/url"
+)
+
+// Exit codes returned by every CLI command. They are part of the scripting
+// interface, so existing values must never change meaning.
+const (
+	ExitOK           = 0   // Command succeeded
+	ExitError        = 1   // Any failure not covered below
+	ExitUsage        = 2   // Unknown command, bad flags or missing arguments
+	ExitNetwork      = 3   // The Blender builder could not be reached or answered with an error
+	ExitNotFound     = 4   // The requested build doesn't exist online or isn't installed
+	ExitVerification = 5   // A downloaded archive is incomplete or corrupt
+	ExitNoSpace      = 6   // Not enough disk space to install the build
+	ExitDoesNotRun   = 7   // The build's executable failed its test run
+	ExitCancelled    = 130 // Interrupted by the user (Ctrl+C), matching the shell convention
+)
+
+// errUsage marks errors caused by invalid command line usage.
+var errUsage = errors.New("usage error")
+
+// errNetwork marks failures to fetch the list of builds from the Blender builder.
+var errNetwork = errors.New("network error")
+
+// ExitCode maps an error returned by a command to its exit code.
+func ExitCode(err error) int {
+	// Don't use net.Error here, syscall.Errno satisfies it too
+	var opErr *net.OpError
+	var urlErr *url.Error
+	var netErr *download.NetworkError
+
+	switch {
+	case err == nil:
+		return ExitOK
+	case errors.Is(err, errUsage):
+		return ExitUsage
+	case errors.Is(err, download.ErrCancelled):
+		return ExitCancelled
+	case errors.Is(err, local.ErrBuildNotFound):
+		return ExitNotFound
+	case errors.Is(err, download.ErrVerificationFailed):
+		return ExitVerification
+	case errors.Is(err, download.ErrInsufficientSpace):
+		return ExitNoSpace
+	case errors.Is(err, local.ErrBuildDoesNotRun):
+		return ExitDoesNotRun
+	case errors.Is(err, errNetwork),
+		errors.As(err, &netErr),
+		errors.Is(err, download.ErrIdleTimeout),
+		errors.Is(err, download.ErrUnexpectedStatus),
+		errors.As(err, &opErr),
+		errors.As(err, &urlErr):
+		return ExitNetwork
+	default:
+		return ExitError
+	}
+}
@@ -0,0 +1,78 @@
+package cli
+
+import (
+	"TUI-Blender-Launcher/download"
+	"TUI-Blender-Launcher/local"
+	"errors"
+	"fmt"
+	"net/url"
+	"slices"
+	"testing"
+)
+
+func TestExitCode(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want int
+	}{
+		{"success", nil, ExitOK},
+		{"usage", fmt.Errorf("%w: missing command", errUsage), ExitUsage},
+		{"cancelled", fmt.Errorf("download failed: %w", download.ErrCancelled), ExitCancelled},
+		{"not found", fmt.Errorf("blender version 9.9: %w", local.ErrBuildNotFound), ExitNotFound},
+		{"verification", fmt.Errorf("%w: expected 10 bytes", download.ErrVerificationFailed), ExitVerification},
+		{"no space", fmt.Errorf("%w in /tmp", download.ErrInsufficientSpace), ExitNoSpace},
+		{"does not run", fmt.Errorf("%w: missing library libXi.so.6", local.ErrBuildDoesNotRun), ExitDoesNotRun},
+		{"idle timeout", fmt.Errorf("download failed: %w", download.ErrIdleTimeout), ExitNetwork},
+		{"bad status", fmt.Errorf("%w 503", download.ErrUnexpectedStatus), ExitNetwork},
+		{"request failed", &url.Error{Op: "Get", URL: "https://builder.blender.org", Err: errors.New("no route to host")}, ExitNetwork},
+		{"fetch failed", fmt.Errorf("%w: %w", errNetwork, errors.New("status code 500")), ExitNetwork},
+		{"other", errors.New("disk full"), ExitError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ExitCode(tt.err); got != tt.want {
+				t.Errorf("ExitCode(%v) = %d, want %d", tt.err, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSplitConfigFlag(t *testing.T) {
+	tests := []struct {
+		name     string
+		args     []string
+		wantPath string
+		wantRest []string
+		wantErr  bool
+	}{
+		{"none", []string{"list"}, "", []string{"list"}, false},
+		{"separate", []string{"--config", "a.toml", "list"}, "a.toml", []string{"list"}, false},
+		{"joined", []string{"--config=a.toml", "list"}, "a.toml", []string{"list"}, false},
+		{"among global flags", []string{"-q", "--config", "a.toml", "list"}, "a.toml", []string{"-q", "list"}, false},
+		{"tui", []string{"--config", "a.toml"}, "a.toml", []string{}, false},
+		{"after the command", []string{"launch", "--config", "a.toml"}, "", []string{"launch", "--config", "a.toml"}, false},
+		{"pass-through", []string{"--", "--config", "a.toml"}, "", []string{"--", "--config", "a.toml"}, false},
+		{"missing path", []string{"--config"}, "", nil, true},
+		{"empty path", []string{"--config=", "list"}, "", nil, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path, rest, err := SplitConfigFlag(tt.args)
+			if tt.wantErr {
+				if !errors.Is(err, errUsage) {
+					t.Errorf("SplitConfigFlag(%q) error = %v, want a usage error", tt.args, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("SplitConfigFlag(%q) returned an error: %v", tt.args, err)
+			}
+			if path != tt.wantPath || !slices.Equal(rest, tt.wantRest) {
+				t.Errorf("SplitConfigFlag(%q) = %q, %q, want %q, %q", tt.args, path, rest, tt.wantPath, tt.wantRest)
+			}
+		})
+	}
+}
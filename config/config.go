@@ -1,9 +1,14 @@
 package config
 
 import (
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
+	"runtime"
+	"slices"
+	"sort"
+	"strings"
 	"sync"
 
 	"github.com/BurntSushi/toml"
@@ -13,12 +18,239 @@ import (
 // AppName is used for the config directory
 const AppName = "tui-blender-launcher" // Use lowercase app name
 
+// ConfigBackups is how many previous versions of the config file are kept
+// next to it, as config.toml.1 (the newest) to config.toml.N.
+const ConfigBackups = 3
+
 // Config holds the application settings.
 type Config struct {
-	DownloadDir   string `toml:"download_dir"`
-	VersionFilter string `toml:"version_filter"` // e.g., "4.0", "3.6", or empty for no filter
-	BuildType     string `toml:"build_type"`     // "daily", "patch", or "experimental"
-	UUID          string `toml:"uuid"`           // Unique identifier for this instance
+	DownloadDir            string                       `toml:"download_dir"`
+	VersionFilter          string                       `toml:"version_filter"`           // e.g., "4.0", "3.6", or empty for no filter
+	BuildType              string                       `toml:"build_type"`               // "daily", "patch", or "experimental"
+	UUID                   string                       `toml:"uuid"`                     // Unique identifier for this instance
+	Prefetch               bool                         `toml:"prefetch"`                 // Pre-download the newest build of the most launched series when idle
+	DownloadSegments       int                          `toml:"download_segments"`        // Parallel connections per download (1 disables segmenting)
+	MaxConcurrentDownloads int                          `toml:"max_concurrent_downloads"` // Downloads running at once, the rest are queued
+	DownloadRateLimit      float64                      `toml:"download_rate_limit"`      // Combined download limit in MB/s, 0 for unlimited
+	SelectNewest           bool                         `toml:"select_newest"`            // Move the cursor to the newest build after a fetch
+	DownloadRetries        int                          `toml:"download_retries"`         // Automatic retries of downloads failing with transient errors
+	KeepArchives           bool                         `toml:"keep_archives"`            // Move archives to archives/ after extraction instead of deleting them
+	DeltaUpdates           bool                         `toml:"delta_updates"`            // Hard-link unchanged files from the replaced build instead of rewriting them
+	Torrent                bool                         `toml:"torrent"`                  // Download over BitTorrent (aria2c) when a .torrent is published
+	Insights               bool                         `toml:"insights"`                 // Record library growth, downloads and launches locally for the insights view
+	LowDiskThreshold       float64                      `toml:"low_disk_threshold"`       // Free space in GB below which cleanup is suggested, 0 to disable
+	UpdateCheck            string                       `toml:"update_check"`             // How installed builds are compared with online ones: "hash" or "date"
+	Mirrors                []string                     `toml:"mirrors"`                  // Base URLs serving the builder's archives under the same paths
+	Extractor              string                       `toml:"extractor"`                // Archive extraction backend: "builtin", "bsdtar" or "7z"
+	InstallRoots           []string                     `toml:"install_roots"`            // Further directories with installed builds, downloads go to download_dir
+	LaunchDir              string                       `toml:"launch_dir"`               // Working directory of launched Blender, the launcher's own if empty
+	NotifyURL              string                       `toml:"notify_url"`               // Webhook receiving a JSON POST when a download completes or fails
+	NotifyCommand          string                       `toml:"notify_command"`           // Shell command run with the same JSON on stdin
+	PostInstallHook        string                       `toml:"post_install_hook"`        // Shell command run inside each newly installed build
+	HealthCheckInterval    int                          `toml:"health_check_interval"`    // Minutes between reachability checks of the builder, 0 to disable
+	RowIcons               string                       `toml:"row_icons"`                // Icons starting each build row: "none", "unicode", "nerd" or "ascii"
+	TemporaryDays          int                          `toml:"temporary_days"`           // Days until builds installed to try them out are offered for cleanup
+	KeepPerSeries          int                          `toml:"keep_per_series"`          // Daily builds kept per version series, older ones are removed after downloads; 0 keeps all
+	ExportConfig           bool                         `toml:"export_config"`            // Add Blender's user config of the build's series to exported archives
+	DeleteToTrash          bool                         `toml:"delete_to_trash"`          // Move deleted builds to the trash instead of removing them for good
+	SystemBuilds           bool                         `toml:"system_builds"`            // List Blender installed by package managers, Steam and installers, to launch them
+	Addons                 []string                     `toml:"addons"`                   // Add-on .zip archives, directories or .py files installed into each new build
+	EnvProfile             string                       `toml:"env_profile"`              // Profile of env_profiles Blender is launched with unless another is picked
+	LaunchMode             string                       `toml:"launch_mode"`              // How Blender is launched: "terminal", "background" or "tmux", keeping the launcher in charge
+	LaunchLogs             bool                         `toml:"launch_logs"`              // Copy Blender's output to a log per launch, as background launches always do
+	GPUOffload             string                       `toml:"gpu_offload"`              // Launch on the discrete GPU of hybrid graphics: "prime-run", "dri-prime", "nvidia" or "switcheroo"
+	LaunchTemplate         string                       `toml:"launch_template"`          // Command line launching Blender with {exe} and {args} filled in, e.g. "gamemoderun {exe} {args}"
+	Language               string                       `toml:"language"`                 // Locale Blender runs in, e.g. "fr_FR" or "en_US", the system's if empty
+	Sandbox                string                       `toml:"sandbox"`                  // Command line confining builds tagged "sandbox", with {exe}, {args}, {build} and {project} filled in
+	Aliases                map[string]string            `toml:"aliases"`                  // Names for versions, series or other aliases, e.g. studio = "4.2"
+	EnvProfiles            map[string]map[string]string `toml:"env_profiles"`             // Environment variables for launching Blender by profile name
+	PythonPaths            map[string][]string          `toml:"python_paths"`             // Directories of Python modules for Blender by version or series, e.g. "4.2" = ["~/blender-modules/4.2"]
+	Columns                []CustomColumn               `toml:"columns"`                  // Extra build list columns
+	Projects               []Project                    `toml:"projects"`                 // Working directories and builds for files of a project
+}
+
+// Roots returns the directories holding installed builds: DownloadDir, where
+// downloads go, followed by the InstallRoots.
+func (c Config) Roots() []string {
+	roots := []string{c.DownloadDir}
+	seen := map[string]bool{filepath.Clean(c.DownloadDir): true}
+	for _, root := range c.InstallRoots {
+		if rest, ok := strings.CutPrefix(root, "~"); ok {
+			if homeDir, err := os.UserHomeDir(); err == nil {
+				root = filepath.Join(homeDir, rest)
+			}
+		}
+		if root == "" || seen[filepath.Clean(root)] {
+			continue
+		}
+		seen[filepath.Clean(root)] = true
+		roots = append(roots, root)
+	}
+	return roots
+}
+
+// PythonPathFor returns the directories of Python modules of the build of
+// version: those set for the version, else for its major.minor series.
+func (c Config) PythonPathFor(version string) []string {
+	dirs, ok := c.PythonPaths[version]
+	if parts := strings.SplitN(version, ".", 3); !ok && len(parts) == 3 {
+		dirs = c.PythonPaths[parts[0]+"."+parts[1]]
+	}
+	expanded := make([]string, 0, len(dirs))
+	for _, dir := range dirs {
+		if rest, ok := strings.CutPrefix(dir, "~"); ok {
+			if homeDir, err := os.UserHomeDir(); err == nil {
+				dir = filepath.Join(homeDir, rest)
+			}
+		}
+		if dir != "" {
+			expanded = append(expanded, dir)
+		}
+	}
+	return expanded
+}
+
+// EnvProfileNames returns the names of the env profiles in order.
+func (c Config) EnvProfileNames() []string {
+	names := make([]string, 0, len(c.EnvProfiles))
+	for name := range c.EnvProfiles {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Values of UpdateCheck. With "hash", builds from different commits are updates
+// of each other and dates only decide when a hash is missing; "date" compares
+// build dates alone.
+const (
+	UpdateCheckHash = "hash"
+	UpdateCheckDate = "date"
+)
+
+// Values of LaunchMode. With "terminal", Blender runs in a new terminal window
+// showing its output; "background" starts it without one and shows it in the
+// header while it runs; "tmux" runs it in a new window of the tmux session the
+// launcher runs in, or like "terminal" outside tmux.
+const (
+	LaunchModeTerminal   = "terminal"
+	LaunchModeBackground = "background"
+	LaunchModeTmux       = "tmux"
+)
+
+// LaunchModes lists the values of LaunchMode.
+var LaunchModes = []string{LaunchModeTerminal, LaunchModeBackground, LaunchModeTmux}
+
+// Values of GPUOffload, the methods of moving a launch to the discrete GPU of
+// a laptop with hybrid graphics (Linux).
+const (
+	OffloadPrimeRun   = "prime-run"  // The prime-run wrapper of NVIDIA's driver packages
+	OffloadDRIPrime   = "dri-prime"  // DRI_PRIME=1 for Mesa drivers
+	OffloadNvidia     = "nvidia"     // NVIDIA's render offload variables, without a wrapper
+	OffloadSwitcheroo = "switcheroo" // switcheroo-control, as GNOME's "Launch using Dedicated Graphics Card"
+)
+
+// OffloadMethods lists the values of GPUOffload.
+var OffloadMethods = []string{OffloadPrimeRun, OffloadDRIPrime, OffloadNvidia, OffloadSwitcheroo}
+
+// SandboxTag is the build tag opting a build into launching through Sandbox.
+const SandboxTag = "sandbox"
+
+// Values of RowIcons. Unicode and Nerd Font icons fall back to ASCII when the
+// locale isn't UTF-8.
+const (
+	RowIconsNone    = "none"
+	RowIconsUnicode = "unicode"
+	RowIconsNerd    = "nerd"
+	RowIconsASCII   = "ascii"
+)
+
+// CustomColumn is a user-defined build list column computed from build metadata
+// with a Go template, e.g. "{{.Branch}}/{{.Hash | short}}".
+type CustomColumn struct {
+	Name     string `toml:"name"`
+	Template string `toml:"template"`
+}
+
+// Project sets the working directory Blender starts in when opening a file
+// below Path, for pipelines whose scripts and output paths are relative, and
+// the build and env profile such files open with.
+type Project struct {
+	Path       string `toml:"path"`        // Root directory of the project
+	WorkDir    string `toml:"work_dir"`    // Working directory, relative ones below Path; Path itself if empty
+	Build      string `toml:"build"`       // Version, series or alias files of the project open with
+	EnvProfile string `toml:"env_profile"` // Profile of env_profiles files of the project open with, e.g. for their OCIO config
+}
+
+// projectFor returns the innermost project containing file for which use
+// holds, with its absolute root.
+func (c Config) projectFor(file string, use func(Project) bool) (Project, string, bool) {
+	if file == "" {
+		return Project{}, "", false
+	}
+	file, err := filepath.Abs(file)
+	if err != nil {
+		return Project{}, "", false
+	}
+
+	var best Project
+	var bestRoot string
+	for _, project := range c.Projects {
+		root, err := filepath.Abs(project.Path)
+		if err != nil || project.Path == "" || len(root) <= len(bestRoot) || !use(project) {
+			continue
+		}
+		if rel, err := filepath.Rel(root, file); err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+			continue
+		}
+		best, bestRoot = project, root
+	}
+	return best, bestRoot, bestRoot != ""
+}
+
+// WorkDirFor returns the working directory to launch Blender in for opening
+// file: that of the innermost project containing it, else LaunchDir. An empty
+// file or result means no particular directory.
+func (c Config) WorkDirFor(file string) string {
+	project, root, ok := c.projectFor(file, func(Project) bool { return true })
+	switch {
+	case !ok:
+		return c.LaunchDir
+	case filepath.IsAbs(project.WorkDir):
+		return project.WorkDir
+	case project.WorkDir != "":
+		return filepath.Join(root, project.WorkDir)
+	}
+	return root
+}
+
+// ProjectDirFor returns the directory a sandboxed Blender opening file may
+// write to: the innermost project containing it, else the file's own
+// directory, else LaunchDir. An empty result means no particular directory.
+func (c Config) ProjectDirFor(file string) string {
+	if _, root, ok := c.projectFor(file, func(Project) bool { return true }); ok {
+		return root
+	}
+	if abs, err := filepath.Abs(file); err == nil && file != "" {
+		return filepath.Dir(abs)
+	}
+	return c.LaunchDir
+}
+
+// EnvProfileFor returns the env profile to open file with, as set by the
+// innermost project containing it that sets one, else EnvProfile.
+func (c Config) EnvProfileFor(file string) string {
+	if project, _, ok := c.projectFor(file, func(p Project) bool { return p.EnvProfile != "" }); ok {
+		return project.EnvProfile
+	}
+	return c.EnvProfile
+}
+
+// BuildFor returns the build to open file with, as set by the innermost
+// project containing it that sets one, or "" if none does.
+func (c Config) BuildFor(file string) string {
+	project, _, _ := c.projectFor(file, func(p Project) bool { return p.Build != "" })
+	return project.Build
 }
 
 var (
@@ -49,27 +281,126 @@ func DefaultConfig() Config {
 	defaultDownloadPath := filepath.Join(homeDir, "blender/blender-build")
 
 	return Config{
-		DownloadDir:   defaultDownloadPath,
-		VersionFilter: "",                  // No filter by default
-		BuildType:     "daily",             // Default to patch builds
-		UUID:          uuid.New().String(), // Generate a new UUID
+		DownloadDir:            defaultDownloadPath,
+		VersionFilter:          "",                  // No filter by default
+		BuildType:              "daily",             // Default to patch builds
+		UUID:                   uuid.New().String(), // Generate a new UUID
+		DownloadSegments:       1,                   // Single connection by default
+		MaxConcurrentDownloads: 2,                   // Queue anything beyond two downloads
+		DownloadRetries:        3,                   // Ride out short network hiccups
+		LowDiskThreshold:       10,                  // Suggest cleaning up below 10 GB free
+		UpdateCheck:            UpdateCheckHash,     // Commit hashes are immune to clock skew
+		Extractor:              "builtin",           // No external tools needed
+		HealthCheckInterval:    5,                   // Notice outages without bothering the builder
+		RowIcons:               RowIconsNone,        // Icons need a font that has them
+		TemporaryDays:          7,                   // A week to try out a build
+		DeleteToTrash:          true,                // A slip of the finger can be undone
+		SystemBuilds:           true,                // Every Blender on the machine in one list
+		LaunchMode:             LaunchModeTerminal,  // Blender's console output stays in sight
 	}
 }
 
-// GetConfigPath returns the full path to the config file.
+// ConfigEnvVar is the environment variable pointing at a config file to use
+// instead of the one in the user's config directory.
+const ConfigEnvVar = "TUI_BLENDER_LAUNCHER_CONFIG"
+
+// configPathOverride is the config file set with SetConfigPath, "" for none.
+var configPathOverride string
+
+// SetConfigPath makes path the config file to load and save, as given with
+// --config. It wins over ConfigEnvVar; "" goes back to the default.
+func SetConfigPath(path string) {
+	configPathOverride = path
+}
+
+// GetConfigPath returns the full path to the config file: the one set with
+// SetConfigPath, else the one in ConfigEnvVar, else config.toml in the
+// config directory.
 func GetConfigPath() (string, error) {
+	for _, path := range []string{configPathOverride, os.Getenv(ConfigEnvVar)} {
+		if path == "" {
+			continue
+		}
+		if path == "~" || strings.HasPrefix(path, "~/") {
+			homeDir, err := os.UserHomeDir()
+			if err != nil {
+				return "", fmt.Errorf("could not get home directory to expand path: %w", err)
+			}
+			path = filepath.Join(homeDir, path[1:])
+		}
+		absPath, err := filepath.Abs(path)
+		if err != nil {
+			return "", fmt.Errorf("could not resolve config path %s: %w", path, err)
+		}
+		return absPath, nil
+	}
+
+	configDir, err := GetConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(configDir, "config.toml"), nil
+}
+
+// GetConfigDir returns the default directory of the config file, whatever
+// config file is used. It follows XDG_CONFIG_HOME on Linux.
+func GetConfigDir() (string, error) {
 	configDir, err := os.UserConfigDir() // Gets ~/.config on Linux, appropriate paths on other OS
 	if err != nil {
 		return "", fmt.Errorf("could not get user config directory: %w", err)
 	}
+	return filepath.Join(configDir, AppName), nil
+}
+
+// GetStateDir returns the directory for runtime state such as logs.
+// It follows XDG_STATE_HOME on Linux and uses the config directory elsewhere.
+func GetStateDir() (string, error) {
+	if runtime.GOOS == "linux" {
+		stateHome := os.Getenv("XDG_STATE_HOME")
+		if stateHome == "" {
+			homeDir, err := os.UserHomeDir()
+			if err != nil {
+				return "", fmt.Errorf("could not get home directory: %w", err)
+			}
+			stateHome = filepath.Join(homeDir, ".local", "state")
+		}
+		return filepath.Join(stateHome, AppName), nil
+	}
+	return GetConfigDir()
+}
 
-	appConfigDir := filepath.Join(configDir, AppName)
-	configFilePath := filepath.Join(appConfigDir, "config.toml")
+// GetCacheDir returns the directory for data that can be rebuilt at any time,
+// such as the last scan of the install roots. It follows XDG_CACHE_HOME on
+// Linux.
+func GetCacheDir() (string, error) {
+	cacheDir, err := os.UserCacheDir() // Gets ~/.cache on Linux, appropriate paths on other OS
+	if err != nil {
+		return "", fmt.Errorf("could not get user cache directory: %w", err)
+	}
+	return filepath.Join(cacheDir, AppName), nil
+}
 
-	return configFilePath, nil
+// Validate reports settings that would make every launch fail, such as env
+// profiles that aren't in env_profiles or an unknown gpu_offload.
+func (c Config) Validate() error {
+	var errs []error
+	if c.GPUOffload != "" && !slices.Contains(OffloadMethods, c.GPUOffload) {
+		errs = append(errs, fmt.Errorf("gpu_offload %q is not one of %s", c.GPUOffload, strings.Join(OffloadMethods, ", ")))
+	}
+	if c.EnvProfile != "" {
+		if _, ok := c.EnvProfiles[c.EnvProfile]; !ok {
+			errs = append(errs, fmt.Errorf("env_profile %q is not in env_profiles", c.EnvProfile))
+		}
+	}
+	for _, project := range c.Projects {
+		if _, ok := c.EnvProfiles[project.EnvProfile]; project.EnvProfile != "" && !ok {
+			errs = append(errs, fmt.Errorf("env_profile %q of project %s is not in env_profiles", project.EnvProfile, project.Path))
+		}
+	}
+	return errors.Join(errs...)
 }
 
-// LoadConfig loads the configuration from the default path.
+// LoadConfig loads the configuration from the path GetConfigPath returns.
 // If the file doesn't exist, it returns default settings without error.
 func LoadConfig() (Config, error) {
 	cfgPath, err := GetConfigPath()
@@ -93,6 +424,9 @@ func LoadConfig() (Config, error) {
 	if _, err := toml.DecodeFile(cfgPath, &cfg); err != nil {
 		return Config{}, fmt.Errorf("could not decode config file %s: %w", cfgPath, err)
 	}
+	if err := cfg.Validate(); err != nil {
+		return Config{}, fmt.Errorf("invalid config file %s: %w", cfgPath, err)
+	}
 
 	// Expand ~ in DownloadDir if present
 	if cfg.DownloadDir != "" && cfg.DownloadDir[0] == '~' {
@@ -106,8 +440,11 @@ func LoadConfig() (Config, error) {
 	return cfg, nil
 }
 
-// SaveConfig saves the configuration to the default path.
-// It creates the config directory if it doesn't exist.
+// SaveConfig saves the configuration to the path GetConfigPath returns.
+// It creates the config directory if it doesn't exist. The config is written
+// to a temporary file that replaces the old one only once it is on disk, so a
+// crash mid-save never leaves a truncated config behind. The replaced config
+// is kept as the newest of ConfigBackups backups.
 func SaveConfig(cfg Config) error {
 	cfgPath, err := GetConfigPath()
 	if err != nil {
@@ -121,18 +458,75 @@ func SaveConfig(cfg Config) error {
 		return fmt.Errorf("could not create config directory %s: %w", appConfigDir, err)
 	}
 
-	// Create and open the file for writing
-	file, err := os.Create(cfgPath)
+	// Write the new config next to the old one, on the same filesystem
+	file, err := os.CreateTemp(appConfigDir, filepath.Base(cfgPath)+".tmp-*")
 	if err != nil {
-		return fmt.Errorf("could not create config file %s: %w", cfgPath, err)
+		return fmt.Errorf("could not create config file in %s: %w", appConfigDir, err)
 	}
-	defer file.Close()
+	tmpPath := file.Name()
+	defer os.Remove(tmpPath) // No-op once renamed
 
 	// Encode the config to the file
 	encoder := toml.NewEncoder(file)
 	if err := encoder.Encode(cfg); err != nil {
+		file.Close()
 		return fmt.Errorf("could not encode config to file %s: %w", cfgPath, err)
 	}
+	if err := file.Sync(); err != nil {
+		file.Close()
+		return fmt.Errorf("could not flush config file %s: %w", cfgPath, err)
+	}
+	if err := file.Close(); err != nil {
+		return fmt.Errorf("could not write config file %s: %w", cfgPath, err)
+	}
+	if err := os.Chmod(tmpPath, 0644); err != nil {
+		return fmt.Errorf("could not set config file permissions: %w", err)
+	}
+
+	if err := rotateBackups(cfgPath); err != nil {
+		return err
+	}
+	if err := os.Rename(tmpPath, cfgPath); err != nil {
+		return fmt.Errorf("could not replace config file %s: %w", cfgPath, err)
+	}
+	syncDir(appConfigDir)
 
 	return nil
 }
+
+// backupPath returns the path of the n-th config backup, 1 being the newest.
+func backupPath(cfgPath string, n int) string {
+	return fmt.Sprintf("%s.%d", cfgPath, n)
+}
+
+// rotateBackups shifts the existing backups by one, dropping the oldest, and
+// copies the current config to the newest backup. The config itself stays in
+// place until it is replaced.
+func rotateBackups(cfgPath string) error {
+	data, err := os.ReadFile(cfgPath)
+	if os.IsNotExist(err) {
+		return nil // Nothing to back up on the first save
+	} else if err != nil {
+		return fmt.Errorf("could not read config file %s: %w", cfgPath, err)
+	}
+
+	for n := ConfigBackups - 1; n >= 1; n-- {
+		err := os.Rename(backupPath(cfgPath, n), backupPath(cfgPath, n+1))
+		if err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("could not rotate config backups: %w", err)
+		}
+	}
+	if err := os.WriteFile(backupPath(cfgPath, 1), data, 0644); err != nil {
+		return fmt.Errorf("could not back up config file %s: %w", cfgPath, err)
+	}
+	return nil
+}
+
+// syncDir flushes a directory so a rename in it survives a crash. Not every
+// platform supports this, which only costs that guarantee.
+func syncDir(dir string) {
+	if d, err := os.Open(dir); err == nil {
+		_ = d.Sync()
+		d.Close()
+	}
+}
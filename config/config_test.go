@@ -1,8 +1,11 @@
 package config
 
 import (
+	"fmt"
 	"os"
 	"path/filepath"
+	"runtime"
+	"slices"
 	"strings"
 	"testing"
 )
@@ -231,3 +234,316 @@ func TestSaveConfig(t *testing.T) {
 func containsStr(s, substr string) bool {
 	return strings.HasPrefix(s, substr) || strings.Contains(s, "\n"+substr) || strings.Contains(s, substr+"\n")
 }
+
+func TestSaveConfigBackups(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	configPath, _ := GetConfigPath()
+
+	// Save one more time than there are backups
+	for i := 0; i <= ConfigBackups+1; i++ {
+		if err := SaveConfig(Config{VersionFilter: fmt.Sprintf("%d", i)}); err != nil {
+			t.Fatalf("SaveConfig #%d returned an error: %v", i, err)
+		}
+	}
+
+	// The newest backup holds the previous save, older ones follow
+	for n := 1; n <= ConfigBackups; n++ {
+		data, err := os.ReadFile(backupPath(configPath, n))
+		if err != nil {
+			t.Fatalf("Failed to read backup %d: %v", n, err)
+		}
+		want := fmt.Sprintf("version_filter = \"%d\"", ConfigBackups+1-n)
+		if !containsStr(string(data), want) {
+			t.Errorf("Backup %d doesn't contain %s, got: %s", n, want, data)
+		}
+	}
+	if _, err := os.Stat(backupPath(configPath, ConfigBackups+1)); !os.IsNotExist(err) {
+		t.Errorf("Expected no more than %d backups", ConfigBackups)
+	}
+
+	// No temporary files are left behind
+	if leftovers, _ := filepath.Glob(configPath + ".tmp-*"); len(leftovers) > 0 {
+		t.Errorf("Expected temporary files to be removed, found %v", leftovers)
+	}
+}
+
+func TestWorkDirFor(t *testing.T) {
+	root := t.TempDir()
+	cfg := Config{
+		LaunchDir: filepath.Join(root, "default"),
+		Projects: []Project{
+			{Path: filepath.Join(root, "film")},
+			{Path: filepath.Join(root, "film", "shot010"), WorkDir: "render"},
+			{Path: filepath.Join(root, "game"), WorkDir: filepath.Join(root, "assets")},
+		},
+	}
+
+	tests := []struct {
+		file string
+		want string
+	}{
+		{"", filepath.Join(root, "default")},                                                                  // Nothing opened
+		{filepath.Join(root, "other", "a.blend"), filepath.Join(root, "default")},                             // Outside all projects
+		{filepath.Join(root, "film", "a.blend"), filepath.Join(root, "film")},                                 // Project root
+		{filepath.Join(root, "film", "shot010", "a.blend"), filepath.Join(root, "film", "shot010", "render")}, // Innermost project wins
+		{filepath.Join(root, "filmx", "a.blend"), filepath.Join(root, "default")},                             // Only whole path elements match
+		{filepath.Join(root, "game", "level.blend"), filepath.Join(root, "assets")},                           // Absolute work_dir
+	}
+	for _, tt := range tests {
+		if got := cfg.WorkDirFor(tt.file); got != tt.want {
+			t.Errorf("WorkDirFor(%q) = %q, want %q", tt.file, got, tt.want)
+		}
+	}
+}
+
+func TestProjectDirFor(t *testing.T) {
+	root := t.TempDir()
+	cfg := Config{
+		LaunchDir: filepath.Join(root, "default"),
+		Projects: []Project{
+			{Path: filepath.Join(root, "film")},
+			{Path: filepath.Join(root, "film", "shot010"), WorkDir: "render"},
+		},
+	}
+
+	tests := []struct {
+		file string
+		want string
+	}{
+		{"", filepath.Join(root, "default")},                                                        // Nothing opened
+		{filepath.Join(root, "other", "a.blend"), filepath.Join(root, "other")},                     // Outside all projects
+		{filepath.Join(root, "film", "seq", "a.blend"), filepath.Join(root, "film")},                // Project root
+		{filepath.Join(root, "film", "shot010", "a.blend"), filepath.Join(root, "film", "shot010")}, // Innermost project, not its work_dir
+	}
+	for _, tt := range tests {
+		if got := cfg.ProjectDirFor(tt.file); got != tt.want {
+			t.Errorf("ProjectDirFor(%q) = %q, want %q", tt.file, got, tt.want)
+		}
+	}
+}
+
+func TestBuildFor(t *testing.T) {
+	root := t.TempDir()
+	cfg := Config{
+		Projects: []Project{
+			{Path: filepath.Join(root, "film"), Build: "studio"},
+			{Path: filepath.Join(root, "film", "shot010"), WorkDir: "render"},
+			{Path: filepath.Join(root, "film", "shot020"), Build: "4.5"},
+		},
+	}
+
+	tests := []struct {
+		file string
+		want string
+	}{
+		{filepath.Join(root, "other", "a.blend"), ""},                 // Outside all projects
+		{filepath.Join(root, "film", "a.blend"), "studio"},            // Project root
+		{filepath.Join(root, "film", "shot010", "a.blend"), "studio"}, // Inner project without a build
+		{filepath.Join(root, "film", "shot020", "a.blend"), "4.5"},    // Innermost project wins
+	}
+	for _, tt := range tests {
+		if got := cfg.BuildFor(tt.file); got != tt.want {
+			t.Errorf("BuildFor(%q) = %q, want %q", tt.file, got, tt.want)
+		}
+	}
+}
+
+func TestPythonPathFor(t *testing.T) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		t.Skip("no home directory to expand ~ with")
+	}
+	cfg := Config{PythonPaths: map[string][]string{
+		"4.2":   {"~/modules/4.2", ""},
+		"4.2.3": {"/opt/modules/pinned"},
+		"5.0":   {"/opt/modules/5.0", "/opt/modules/shared"},
+	}}
+
+	tests := []struct {
+		version string
+		want    []string
+	}{
+		{"4.2.1", []string{filepath.Join(home, "modules/4.2")}},        // Series
+		{"4.2.3", []string{"/opt/modules/pinned"}},                     // Version wins over series
+		{"5.0.0", []string{"/opt/modules/5.0", "/opt/modules/shared"}}, // Several directories
+		{"4.4.1", []string{}}, // Neither set
+	}
+	for _, tt := range tests {
+		if got := cfg.PythonPathFor(tt.version); !slices.Equal(got, tt.want) {
+			t.Errorf("PythonPathFor(%q) = %q, want %q", tt.version, got, tt.want)
+		}
+	}
+}
+
+func TestEnvProfileFor(t *testing.T) {
+	root := t.TempDir()
+	cfg := Config{
+		EnvProfile: "hip",
+		Projects: []Project{
+			{Path: filepath.Join(root, "film"), EnvProfile: "aces"},
+			{Path: filepath.Join(root, "film", "shot010"), WorkDir: "render"},
+			{Path: filepath.Join(root, "film", "shot020"), EnvProfile: "filmic"},
+		},
+	}
+
+	tests := []struct {
+		file string
+		want string
+	}{
+		{"", "hip"}, // Nothing opened
+		{filepath.Join(root, "other", "a.blend"), "hip"},              // Outside all projects
+		{filepath.Join(root, "film", "a.blend"), "aces"},              // Project root
+		{filepath.Join(root, "film", "shot010", "a.blend"), "aces"},   // Inner project without a profile
+		{filepath.Join(root, "film", "shot020", "a.blend"), "filmic"}, // Innermost project wins
+	}
+	for _, tt := range tests {
+		if got := cfg.EnvProfileFor(tt.file); got != tt.want {
+			t.Errorf("EnvProfileFor(%q) = %q, want %q", tt.file, got, tt.want)
+		}
+	}
+}
+
+func TestRoots(t *testing.T) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		t.Skip("no home directory to expand ~ with")
+	}
+	cfg := Config{
+		DownloadDir:  "/builds",
+		InstallRoots: []string{"/mnt/data/blender", "", "/builds/", "~/blender", "/mnt/data/blender"},
+	}
+
+	// The download directory comes first, empty and repeated roots are dropped
+	want := []string{"/builds", "/mnt/data/blender", filepath.Join(home, "blender")}
+	if got := cfg.Roots(); fmt.Sprint(got) != fmt.Sprint(want) {
+		t.Errorf("Roots() = %v, want %v", got, want)
+	}
+}
+
+func TestValidate(t *testing.T) {
+	profiles := map[string]map[string]string{"aces": {"OCIO": "/studio/aces.ocio"}}
+	tests := []struct {
+		name    string
+		cfg     Config
+		wantErr string
+	}{
+		{"no profiles", Config{}, ""},
+		{"known profile", Config{EnvProfile: "aces", EnvProfiles: profiles, Projects: []Project{{Path: "/film", EnvProfile: "aces"}}}, ""},
+		{"unknown profile", Config{EnvProfile: "hip", EnvProfiles: profiles}, `env_profile "hip" is not in env_profiles`},
+		{"unknown project profile", Config{EnvProfiles: profiles, Projects: []Project{{Path: "/film", EnvProfile: "filmic"}}},
+			`env_profile "filmic" of project /film is not in env_profiles`},
+		{"known offload", Config{GPUOffload: OffloadPrimeRun}, ""},
+		{"unknown offload", Config{GPUOffload: "optimus"}, `gpu_offload "optimus" is not one of prime-run, dri-prime, nvidia, switcheroo`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.cfg.Validate()
+			if tt.wantErr == "" && err != nil {
+				t.Errorf("Validate() = %v, want no error", err)
+			}
+			if tt.wantErr != "" && (err == nil || !strings.Contains(err.Error(), tt.wantErr)) {
+				t.Errorf("Validate() = %v, want %s", err, tt.wantErr)
+			}
+		})
+	}
+
+	// Loading refuses such a config instead of failing every launch later
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	t.Setenv(ConfigEnvVar, "")
+	configPath, _ := GetConfigPath()
+	if err := os.MkdirAll(filepath.Dir(configPath), 0750); err != nil {
+		t.Fatalf("Failed to create config dir: %v", err)
+	}
+	if err := os.WriteFile(configPath, []byte(`env_profile = "hip"`), 0644); err != nil {
+		t.Fatalf("Failed to write config: %v", err)
+	}
+	if _, err := LoadConfig(); err == nil || !strings.Contains(err.Error(), `env_profile "hip"`) {
+		t.Errorf("Expected LoadConfig to report the unknown profile, got %v", err)
+	}
+}
+
+func TestConfigPathOverride(t *testing.T) {
+	configHome := t.TempDir()
+	t.Setenv("XDG_CONFIG_HOME", configHome)
+	t.Setenv("HOME", configHome) // For macOS, where the config directory ignores XDG
+	t.Cleanup(func() { SetConfigPath("") })
+	dotfiles := t.TempDir()
+	envPath := filepath.Join(dotfiles, "env.toml")
+	flagPath := filepath.Join(dotfiles, "flag.toml")
+	wd, _ := os.Getwd()
+
+	tests := []struct {
+		name string
+		flag string
+		env  string
+		want string
+	}{
+		{"default", "", "", ""},
+		{"env", "", envPath, envPath},
+		{"flag wins over env", flagPath, envPath, flagPath},
+		{"relative flag", "flag.toml", "", filepath.Join(wd, "flag.toml")},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			SetConfigPath(tt.flag)
+			t.Setenv(ConfigEnvVar, tt.env)
+			got, err := GetConfigPath()
+			if err != nil {
+				t.Fatalf("GetConfigPath returned an error: %v", err)
+			}
+			if tt.want == "" {
+				configDir, _ := GetConfigDir()
+				tt.want = filepath.Join(configDir, "config.toml")
+			}
+			if got != tt.want {
+				t.Errorf("GetConfigPath() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+
+	// The config is loaded from and saved to the file given, and state stays
+	// in the default directories
+	SetConfigPath(flagPath)
+	if err := SaveConfig(Config{VersionFilter: "4.2"}); err != nil {
+		t.Fatalf("SaveConfig returned an error: %v", err)
+	}
+	cfg, err := LoadConfig()
+	if err != nil {
+		t.Fatalf("LoadConfig returned an error: %v", err)
+	}
+	if cfg.VersionFilter != "4.2" {
+		t.Errorf("Expected version filter 4.2 from %s, got %q", flagPath, cfg.VersionFilter)
+	}
+	if stateDir, _ := GetStateDir(); strings.HasPrefix(stateDir, dotfiles) {
+		t.Errorf("Expected the state directory to stay out of %s, got %s", dotfiles, stateDir)
+	}
+}
+
+func TestXDGDirs(t *testing.T) {
+	if runtime.GOOS != "linux" {
+		t.Skip("XDG base directories are only followed on Linux")
+	}
+	root := t.TempDir()
+	t.Setenv("XDG_CONFIG_HOME", filepath.Join(root, "config"))
+	t.Setenv("XDG_CACHE_HOME", filepath.Join(root, "cache"))
+	t.Setenv("XDG_STATE_HOME", filepath.Join(root, "state"))
+
+	tests := []struct {
+		name string
+		dir  func() (string, error)
+		want string
+	}{
+		{"config", GetConfigDir, filepath.Join(root, "config", AppName)},
+		{"cache", GetCacheDir, filepath.Join(root, "cache", AppName)},
+		{"state", GetStateDir, filepath.Join(root, "state", AppName)},
+	}
+	for _, tt := range tests {
+		got, err := tt.dir()
+		if err != nil {
+			t.Fatalf("%s directory: %v", tt.name, err)
+		}
+		if got != tt.want {
+			t.Errorf("%s directory = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,131 @@
+package download
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// seriesDirPattern matches the directory of a build holding its bundled
+// scripts and data, named after its version series, e.g. "4.2".
+var seriesDirPattern = regexp.MustCompile(`^\d+\.\d+$`)
+
+// InstallAddons installs the add-ons in addons, .zip archives, module
+// directories or single .py files, into the bundled scripts/addons directory
+// of the build in installDir, so every new build comes with them. Add-ons
+// enabled in the user preferences of the series load from there. Failing
+// add-ons don't keep the others from being installed.
+func InstallAddons(addons []string, installDir string) error {
+	if len(addons) == 0 {
+		return nil
+	}
+	addonsDir, err := bundledAddonsDir(installDir)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(addonsDir, 0750); err != nil {
+		return fmt.Errorf("failed to create %s: %w", addonsDir, err)
+	}
+
+	var errs []error
+	for _, addon := range addons {
+		if rest, ok := strings.CutPrefix(addon, "~"); ok {
+			if homeDir, err := os.UserHomeDir(); err == nil {
+				addon = filepath.Join(homeDir, rest)
+			}
+		}
+		if err := installAddon(addon, addonsDir); err != nil {
+			errs = append(errs, fmt.Errorf("failed to install add-on %s: %w", addon, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// bundledAddonsDir returns the scripts/addons directory of the build in
+// installDir, inside its series directory, which macOS keeps in the app bundle.
+func bundledAddonsDir(installDir string) (string, error) {
+	bundles, _ := filepath.Glob(filepath.Join(installDir, "*.app", "Contents", "Resources"))
+	for _, base := range append([]string{installDir}, bundles...) {
+		entries, err := os.ReadDir(base)
+		if err != nil {
+			continue
+		}
+		for _, entry := range entries {
+			if entry.IsDir() && seriesDirPattern.MatchString(entry.Name()) {
+				return filepath.Join(base, entry.Name(), "scripts", "addons"), nil
+			}
+		}
+	}
+	return "", fmt.Errorf("no bundled scripts directory in %s", installDir)
+}
+
+// installAddon copies or extracts one add-on into addonsDir, replacing an
+// older copy of it.
+func installAddon(addon, addonsDir string) error {
+	info, err := os.Stat(addon)
+	if err != nil {
+		return err
+	}
+	switch {
+	case info.IsDir():
+		dest := filepath.Join(addonsDir, filepath.Base(addon))
+		if err := os.RemoveAll(dest); err != nil {
+			return err
+		}
+		return copyAddonDir(addon, dest)
+	case strings.EqualFold(filepath.Ext(addon), ".zip"):
+		return extractZip(addon, addonsDir, nil, nil, nil)
+	case strings.EqualFold(filepath.Ext(addon), ".py"):
+		return copyAddonFile(addon, filepath.Join(addonsDir, filepath.Base(addon)), info.Mode().Perm())
+	}
+	return errors.New("not a .zip archive, directory or .py file")
+}
+
+// copyAddonDir copies the add-on module in srcDir to destDir.
+func copyAddonDir(srcDir, destDir string) error {
+	return filepath.WalkDir(srcDir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(srcDir, path)
+		if err != nil {
+			return err
+		}
+		dest := filepath.Join(destDir, rel)
+		if d.IsDir() {
+			// Compiled caches belong to the Python that wrote them
+			if d.Name() == "__pycache__" {
+				return filepath.SkipDir
+			}
+			return os.MkdirAll(dest, 0750)
+		}
+		info, err := d.Info()
+		if err != nil || !info.Mode().IsRegular() {
+			return err
+		}
+		return copyAddonFile(path, dest, info.Mode().Perm())
+	})
+}
+
+// copyAddonFile copies a regular file, keeping its permissions.
+func copyAddonFile(src, dest string, perm fs.FileMode) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+	out, err := os.OpenFile(dest, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, perm)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(out, in); err != nil {
+		out.Close()
+		return err
+	}
+	return out.Close()
+}
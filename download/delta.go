@@ -0,0 +1,152 @@
+package download
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// deltaSource lets an update reuse the files of the install it replaces. Archive
+// entries whose content matches the previous build are hard-linked from its
+// backup in .oldbuilds instead of being written again, so updating a daily build
+// only writes the files that actually changed.
+type deltaSource struct {
+	oldRoot string // Previous install, moved to .oldbuilds
+}
+
+// newDeltaSource returns a deltaSource for the previous install at oldRoot,
+// or nil if there is none.
+func newDeltaSource(oldRoot string) *deltaSource {
+	if info, err := os.Stat(oldRoot); err != nil || !info.IsDir() {
+		return nil
+	}
+	return &deltaSource{oldRoot: oldRoot}
+}
+
+// candidate returns the file of the previous install matching an archive entry
+// by path, size and permissions. Entry names start with the archive's root
+// directory, which differs between builds and is skipped.
+func (d *deltaSource) candidate(name string, size int64, mode os.FileMode) (string, bool) {
+	parts := strings.SplitN(filepath.ToSlash(name), "/", 2)
+	if len(parts) < 2 || parts[1] == "" {
+		return "", false
+	}
+	oldPath := filepath.Join(d.oldRoot, filepath.FromSlash(parts[1]))
+
+	info, err := os.Lstat(oldPath)
+	if err != nil || !info.Mode().IsRegular() || info.Size() != size || info.Mode().Perm() != mode.Perm() {
+		return "", false
+	}
+	return oldPath, true
+}
+
+// reuseBytes links the previous install's copy of an entry read into memory to
+// targetPath if its content is unchanged. It reports whether the entry was reused.
+func (d *deltaSource) reuseBytes(targetPath, name string, contents []byte, mode os.FileMode) bool {
+	oldPath, ok := d.candidate(name, int64(len(contents)), mode)
+	if !ok {
+		return false
+	}
+	oldContents, err := os.ReadFile(oldPath)
+	if err != nil || !bytes.Equal(oldContents, contents) {
+		return false
+	}
+	return linkOrCopy(oldPath, targetPath, mode) == nil
+}
+
+// writeStream extracts a streamed entry to targetPath, comparing it with the
+// previous install's copy as it is read. Nothing is written while the content
+// matches; on the first difference the matching prefix is copied over and the
+// rest is written from the archive. It reports false without reading from r if
+// there is no matching file to compare against, leaving the write to the caller.
+func (d *deltaSource) writeStream(targetPath, name string, r io.Reader, size int64, mode os.FileMode, buf []byte) (bool, error) {
+	oldPath, ok := d.candidate(name, size, mode)
+	if !ok {
+		return false, nil
+	}
+	oldFile, err := os.Open(oldPath)
+	if err != nil {
+		return false, nil
+	}
+	defer oldFile.Close()
+
+	oldBuf := make([]byte, len(buf))
+	var matched int64
+	for {
+		n, readErr := io.ReadFull(r, buf)
+		if n > 0 {
+			if _, err := io.ReadFull(oldFile, oldBuf[:n]); err != nil || !bytes.Equal(buf[:n], oldBuf[:n]) {
+				return true, writeDiverged(targetPath, oldPath, matched, buf[:n], r, mode)
+			}
+			matched += int64(n)
+		}
+		if readErr == io.EOF || readErr == io.ErrUnexpectedEOF {
+			break
+		}
+		if readErr != nil {
+			return true, readErr
+		}
+	}
+
+	return true, linkOrCopy(oldPath, targetPath, mode)
+}
+
+// writeDiverged writes an entry that matched the old file for its first matched
+// bytes: that prefix comes from the old file, then the differing chunk and the
+// remainder of the entry follow.
+func writeDiverged(targetPath, oldPath string, matched int64, chunk []byte, rest io.Reader, mode os.FileMode) error {
+	out, err := os.OpenFile(targetPath, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, mode)
+	if err != nil {
+		return fmt.Errorf("failed to create file %s: %w", targetPath, err)
+	}
+	defer out.Close()
+
+	oldFile, err := os.Open(oldPath)
+	if err != nil {
+		return fmt.Errorf("failed to reopen %s: %w", oldPath, err)
+	}
+	defer oldFile.Close()
+
+	if _, err := io.CopyN(out, oldFile, matched); err != nil {
+		return fmt.Errorf("failed to write file %s: %w", targetPath, err)
+	}
+	if _, err := out.Write(chunk); err != nil {
+		return fmt.Errorf("failed to write file %s: %w", targetPath, err)
+	}
+	if _, err := io.Copy(out, rest); err != nil {
+		if errors.Is(err, ErrCancelled) {
+			return ErrCancelled
+		}
+		return fmt.Errorf("failed to write file %s: %w", targetPath, err)
+	}
+	return out.Close()
+}
+
+// linkOrCopy hard-links oldPath to targetPath, copying it where links aren't
+// supported (e.g. .oldbuilds on another filesystem).
+func linkOrCopy(oldPath, targetPath string, mode os.FileMode) error {
+	_ = os.Remove(targetPath)
+	if err := os.Link(oldPath, targetPath); err == nil {
+		return nil
+	}
+
+	in, err := os.Open(oldPath)
+	if err != nil {
+		return fmt.Errorf("failed to open %s: %w", oldPath, err)
+	}
+	defer in.Close()
+
+	out, err := os.OpenFile(targetPath, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, mode)
+	if err != nil {
+		return fmt.Errorf("failed to create file %s: %w", targetPath, err)
+	}
+	if _, err := io.Copy(out, in); err != nil {
+		out.Close()
+		return fmt.Errorf("failed to copy %s: %w", oldPath, err)
+	}
+	return out.Close()
+}
@@ -0,0 +1,87 @@
+package download
+
+import (
+	"TUI-Blender-Launcher/model"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// ErrInsufficientSpace is returned when the download directory can't fit a build.
+var ErrInsufficientSpace = errors.New("not enough disk space")
+
+// extractedSizeFactor estimates how much bigger a build gets once extracted.
+// Blender archives compress to roughly a third of their installed size.
+const extractedSizeFactor = 3.5
+
+// RequiredSpace estimates the disk space needed to download and install a build:
+// the archive plus its extracted contents, which exist side by side until the
+// archive is removed.
+func RequiredSpace(build model.BlenderBuild) int64 {
+	return build.Size + int64(float64(build.Size)*extractedSizeFactor)
+}
+
+// CheckFreeSpace makes sure downloadBaseDir has room for a build, counting the
+// part of the archive that is already downloaded. Builds of unknown size pass.
+func CheckFreeSpace(build model.BlenderBuild, downloadBaseDir string) error {
+	return CheckFreeSpaceAll([]model.BlenderBuild{build}, downloadBaseDir)
+}
+
+// CheckFreeSpaceAll makes sure downloadBaseDir has room for all of builds at
+// once, as when they are downloaded side by side.
+func CheckFreeSpaceAll(builds []model.BlenderBuild, downloadBaseDir string) error {
+	var required int64
+	for _, build := range builds {
+		if build.Size <= 0 {
+			continue
+		}
+		required += RequiredSpace(build)
+		if info, err := os.Stat(ArchivePath(build, downloadBaseDir)); err == nil {
+			required -= info.Size()
+		}
+	}
+	if required <= 0 {
+		return nil
+	}
+	return checkSpace(downloadBaseDir, required)
+}
+
+// checkSpace returns ErrInsufficientSpace when dir has less than required bytes free.
+func checkSpace(dir string, required int64) error {
+	free, err := freeSpace(existingParent(dir))
+	if err != nil {
+		// Not knowing is no reason to refuse the download
+		return nil
+	}
+	if uint64(required) > free {
+		return fmt.Errorf("%w in %s: need about %s, only %s free", ErrInsufficientSpace, dir,
+			model.FormatByteSize(required), model.FormatByteSize(int64(free)))
+	}
+	return nil
+}
+
+// FreeSpace returns the bytes available on the filesystem holding dir, which
+// doesn't need to exist yet.
+func FreeSpace(dir string) (int64, error) {
+	free, err := freeSpace(existingParent(dir))
+	if err != nil {
+		return 0, err
+	}
+	return int64(free), nil
+}
+
+// existingParent returns dir or its closest ancestor that exists, since the
+// download directory may only be created when the first build is installed.
+func existingParent(dir string) string {
+	for {
+		if _, err := os.Stat(dir); err == nil {
+			return dir
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return dir
+		}
+		dir = parent
+	}
+}
@@ -0,0 +1,152 @@
+//go:build darwin
+// +build darwin
+
+package download
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+// extractDmg mounts a macOS disk image and copies the Blender.app bundle it contains
+// into destDir/rootDir, reporting progress by bytes copied.
+func extractDmg(archivePath, destDir, rootDir string, progressCb ExtractionProgressCallback, cancelCh <-chan struct{}) error {
+	mountPoint, err := os.MkdirTemp("", "blender-dmg-")
+	if err != nil {
+		return fmt.Errorf("failed to create mount point: %w", err)
+	}
+	defer os.Remove(mountPoint)
+
+	attach := exec.Command("hdiutil", "attach", "-nobrowse", "-readonly", "-noautoopen", "-mountpoint", mountPoint, archivePath)
+	if out, err := attach.CombinedOutput(); err != nil {
+		return fmt.Errorf("%w: failed to mount disk image: %s", ErrVerificationFailed, strings.TrimSpace(string(out)))
+	}
+	defer exec.Command("hdiutil", "detach", "-force", mountPoint).Run()
+
+	appName, err := findAppBundle(mountPoint)
+	if err != nil {
+		return err
+	}
+
+	src := filepath.Join(mountPoint, appName)
+	dst := filepath.Join(destDir, rootDir, appName)
+	if err := os.MkdirAll(filepath.Dir(dst), 0750); err != nil {
+		return fmt.Errorf("failed to create install directory: %w", err)
+	}
+	return copyTree(src, dst, progressCb, cancelCh)
+}
+
+// findAppBundle returns the name of the first .app bundle at the top of a mounted image.
+func findAppBundle(mountPoint string) (string, error) {
+	entries, err := os.ReadDir(mountPoint)
+	if err != nil {
+		return "", fmt.Errorf("failed to read disk image: %w", err)
+	}
+	for _, entry := range entries {
+		if entry.IsDir() && strings.HasSuffix(entry.Name(), ".app") {
+			return entry.Name(), nil
+		}
+	}
+	return "", fmt.Errorf("%w: no .app bundle in disk image", ErrVerificationFailed)
+}
+
+// copyTree copies a directory tree, keeping file modes and the symlinks that app
+// bundle frameworks rely on.
+func copyTree(src, dst string, progressCb ExtractionProgressCallback, cancelCh <-chan struct{}) error {
+	// Sum up the size first so progress can be reported
+	var totalSize int64
+	err := filepath.WalkDir(src, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.Type().IsRegular() {
+			info, err := d.Info()
+			if err != nil {
+				return err
+			}
+			totalSize += info.Size()
+		}
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to scan app bundle: %w", err)
+	}
+
+	if progressCb != nil {
+		progressCb(0.0)
+	}
+
+	var copied int64
+	copyBuffer := make([]byte, 4*1024*1024)
+	err = filepath.WalkDir(src, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if isClosed(cancelCh) {
+			return ErrCancelled
+		}
+
+		rel, err := filepath.Rel(src, path)
+		if err != nil {
+			return err
+		}
+		target := filepath.Join(dst, rel)
+
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+
+		switch {
+		case d.IsDir():
+			return os.MkdirAll(target, info.Mode().Perm()|0700)
+		case d.Type()&fs.ModeSymlink != 0:
+			link, err := os.Readlink(path)
+			if err != nil {
+				return err
+			}
+			return os.Symlink(link, target)
+		case d.Type().IsRegular():
+			in, err := os.Open(path)
+			if err != nil {
+				return err
+			}
+			defer in.Close()
+
+			out, err := os.OpenFile(target, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, info.Mode().Perm())
+			if err != nil {
+				return err
+			}
+			written, err := io.CopyBuffer(out, &CancelableReader{Reader: in, CancelCh: cancelCh}, copyBuffer)
+			if closeErr := out.Close(); err == nil {
+				err = closeErr
+			}
+			if err != nil {
+				return err
+			}
+
+			copied += written
+			if progressCb != nil && totalSize > 0 {
+				progressCb(float64(copied) / float64(totalSize))
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		if errors.Is(err, ErrCancelled) {
+			return ErrCancelled
+		}
+		return fmt.Errorf("failed to copy app bundle: %w", err)
+	}
+
+	if progressCb != nil {
+		progressCb(1.0)
+	}
+	return nil
+}
@@ -0,0 +1,11 @@
+//go:build !darwin
+// +build !darwin
+
+package download
+
+import "fmt"
+
+// extractDmg is only supported on macOS, where hdiutil can mount disk images.
+func extractDmg(archivePath, destDir, rootDir string, progressCb ExtractionProgressCallback, cancelCh <-chan struct{}) error {
+	return fmt.Errorf("disk images (.dmg) can only be installed on macOS")
+}
@@ -2,31 +2,38 @@ package download
 
 import (
 	"TUI-Blender-Launcher/config"
+	"TUI-Blender-Launcher/launch"
 	"TUI-Blender-Launcher/model"
 	"archive/tar"
 	"archive/zip"
 	"bufio"
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
 	"net/http"
 	"os"
+	"path"
 	"path/filepath"
 	"strings"
 	"sync"
 	"time"
 
-	"github.com/cavaliergopher/grab/v3"
 	"github.com/ulikunitz/xz"
 )
 
 const DownloadingDir = ".downloading"
 const OldBuildsDir = ".oldbuilds"
+const ArchivesDir = "archives"
 
 // Error constants
 var ErrCancelled = errors.New("operation cancelled")
 var ErrIdleTimeout = errors.New("download timed out: connection idle for too long")
+var ErrUnexpectedStatus = errors.New("unexpected status code")
+var ErrVerificationFailed = errors.New("archive verification failed")
+var ErrNoKeptArchive = errors.New("no kept archive to repair from")
+var ErrAlreadyInstalled = errors.New("identical build already installed")
 
 // versionMetaFilename is the name of the metadata file saved in the extracted directory.
 const versionMetaFilename = "version.json"
@@ -35,49 +42,170 @@ const versionMetaFilename = "version.json"
 // It receives bytes downloaded and total file size.
 type ProgressCallback func(downloadedBytes, totalBytes int64)
 
-// ExtractionProgressCallback represents a callback used to report extraction progress.
-// Since we can't know the total size up front, we use a percentage (0.0-1.0) estimate.
+// ExtractionProgressCallback represents a callback used to report extraction progress
+// as the fraction (0.0-1.0) of the archive's uncompressed bytes extracted so far.
 type ExtractionProgressCallback func(estimatedProgress float64)
 
+// userAgent identifies the launcher to the Blender builder.
+const userAgent = "TUI-Blender-Launcher"
+
+// idleTimeout is how long a download may go without receiving data before it is aborted.
+const idleTimeout = 2 * time.Minute
+
+// progressInterval throttles how often download progress is reported.
+const progressInterval = 100 * time.Millisecond
+
+// httpClient is shared by all downloads. No overall timeout is set since
+// archives can take a long time to arrive; stalls are caught by idleTimeout.
+var httpClient = &http.Client{
+	Transport: &http.Transport{
+		Proxy:               http.ProxyFromEnvironment,
+		IdleConnTimeout:     2 * time.Minute,
+		TLSHandshakeTimeout: 1 * time.Minute,
+	},
+}
+
 // downloadFile downloads a file, reporting progress via the callback.
-func downloadFile(url string, destFilePath string, progressCb ProgressCallback, cancelCh <-chan struct{}) error {
+// If destFilePath already holds a partial download, the transfer is resumed
+// with an HTTP Range request instead of starting over.
+func downloadFile(url string, destFilePath string, progressCb ProgressCallback, cancelCh <-chan struct{}, opts fetchOptions) error {
 	// Create download directory if it doesn't exist
 	downloadDir := filepath.Dir(destFilePath)
 	if err := os.MkdirAll(downloadDir, 0755); err != nil {
 		return fmt.Errorf("failed to create download directory: %w", err)
 	}
 
-	// Create download client
-	client := grab.NewClient()
-	client.HTTPClient = &http.Client{
-		// Timeout: 30 * time.Second,
+	// Pick up where a previous attempt left off
+	var offset int64
+	if info, err := os.Stat(destFilePath); err == nil {
+		offset = info.Size()
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// Translate our cancel channel into context cancellation
+	go func() {
+		select {
+		case <-cancelCh:
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+
+	req, err := newDownloadRequest(ctx, url, opts)
+	if err != nil {
+		return err
+	}
+	if offset > 0 {
+		req.Header.Set("Range", fmt.Sprintf("bytes=%d-", offset))
+	}
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		if isClosed(cancelCh) {
+			return ErrCancelled
+		}
+		return fmt.Errorf("request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	flags := os.O_CREATE | os.O_WRONLY
+	var total int64
+	switch resp.StatusCode {
+	case http.StatusPartialContent:
+		flags |= os.O_APPEND
+		total = offset + resp.ContentLength
+	case http.StatusOK:
+		// Server ignored the range (or this is a fresh download), start over
+		offset = 0
+		flags |= os.O_TRUNC
+		total = resp.ContentLength
+	case http.StatusRequestedRangeNotSatisfiable:
+		// Nothing left to fetch, the partial file is already complete
+		if progressCb != nil {
+			progressCb(offset, offset)
+		}
+		return nil
+	default:
+		return &StatusError{Code: resp.StatusCode}
 	}
-	client.UserAgent = "TUI-Blender-Launcher"
 
-	// Create request
-	req, err := grab.NewRequest(destFilePath, url)
+	outFile, err := os.OpenFile(destFilePath, flags, 0644)
 	if err != nil {
-		return fmt.Errorf("failed to create download request: %w", err)
+		return fmt.Errorf("failed to open download file: %w", err)
 	}
+	defer outFile.Close()
 
-	// Set headers
-	req.HTTPRequest.Header.Set("X-Download-ID", config.GetConfigInstance().UUID)
-	req.HTTPRequest.Header.Set("User-Agent", "TUI-Blender-Launcher")
+	// Abort the request if the connection goes quiet for too long
+	watchdog := startIdleWatchdog(opts.gate, cancel)
+	defer watchdog.Stop()
 
-	// Start download
-	resp := client.Do(req)
+	var lastReport time.Time
+	tracker := &progressTracker{
+		reader:   &limitedReader{reader: resp.Body, limiter: opts.limiter, gate: opts.gate, cancelCh: cancelCh},
+		current:  offset,
+		total:    total,
+		cancelCh: cancelCh,
+		callback: func(read, total int64) {
+			watchdog.Reset()
+			if progressCb != nil && time.Since(lastReport) >= progressInterval {
+				lastReport = time.Now()
+				progressCb(read, total)
+			}
+		},
+	}
 
-	// Wait for completion
-	select {
-	case <-resp.Done:
-		if err := resp.Err(); err != nil {
-			return fmt.Errorf("download failed: %w", err)
+	// Report the starting point so callers can tell a resumed download apart
+	if progressCb != nil {
+		progressCb(offset, total)
+	}
+
+	const bufferSize = 256 * 1024
+	if _, err := io.CopyBuffer(outFile, tracker, make([]byte, bufferSize)); err != nil {
+		switch {
+		case watchdog.TimedOut():
+			return ErrIdleTimeout
+		case errors.Is(err, ErrCancelled) || isClosed(cancelCh):
+			return ErrCancelled
 		}
-		return nil
-	case <-cancelCh:
-		return ErrCancelled
-	case <-time.After(10 * time.Minute):
-		return ErrIdleTimeout
+		return fmt.Errorf("download interrupted: %w", err)
+	}
+
+	if progressCb != nil {
+		progressCb(tracker.current, tracker.current)
+	}
+	return nil
+}
+
+// fetchOptions are what the requests of one archive download carry along.
+// The zero value downloads anonymously, unlimited and without pausing.
+type fetchOptions struct {
+	clientID string       // UUID of the config, sent along with every request
+	limiter  *RateLimiter // Shared with the downloads running alongside
+	gate     *PauseGate
+}
+
+// newDownloadRequest creates a GET request carrying the launcher's identifying headers.
+func newDownloadRequest(ctx context.Context, url string, opts fetchOptions) (*http.Request, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create download request: %w", err)
+	}
+	if opts.clientID != "" {
+		req.Header.Set("X-Download-ID", opts.clientID)
+	}
+	req.Header.Set("User-Agent", userAgent)
+	return req, nil
+}
+
+// isClosed reports whether the given cancel channel has been closed.
+func isClosed(ch <-chan struct{}) bool {
+	select {
+	case <-ch:
+		return true
+	default:
+		return false
 	}
 }
 
@@ -97,14 +225,8 @@ func (r *CancelableReader) Read(p []byte) (n int, err error) {
 }
 
 // extractTarXz extracts a .tar.xz archive with progress updates.
-func extractTarXz(archivePath, destDir string, progressCb ExtractionProgressCallback, cancelCh <-chan struct{}) error {
-	// Get file info to calculate rough progress based on archive size
-	fileInfo, err := os.Stat(archivePath)
-	if err != nil {
-		return fmt.Errorf("failed to stat archive file: %w", err)
-	}
-	archiveSize := fileInfo.Size()
-
+// A non-nil delta reuses unchanged files of the install being replaced.
+func extractTarXz(archivePath, destDir string, progressCb ExtractionProgressCallback, cancelCh <-chan struct{}, delta *deltaSource) error {
 	file, err := os.Open(archivePath)
 	if err != nil {
 		return fmt.Errorf("failed to open archive: %w", err)
@@ -115,26 +237,46 @@ func extractTarXz(archivePath, destDir string, progressCb ExtractionProgressCall
 	const bufferSize = 4 * 1024 * 1024 // 4MB buffer for better throughput
 	bufferedFile := bufio.NewReaderSize(file, bufferSize)
 
-	// Create a reader that will track read progress
-	progressBuffer := &progressTracker{
-		reader:   bufferedFile,
-		total:    archiveSize,
-		cancelCh: cancelCh,
-		callback: func(read, total int64) {
-			if progressCb != nil {
-				// Convert to estimated extraction progress (0.0-1.0)
-				estimatedProgress := float64(read) / float64(total)
-				progressCb(estimatedProgress)
-			}
-		},
+	report := func(read, total int64) {
+		if progressCb != nil && total > 0 {
+			progressCb(min(float64(read)/float64(total), 1.0))
+		}
 	}
 
-	xzReader, err := xz.NewReader(progressBuffer)
+	// Progress is measured over the uncompressed tar stream, whose size the xz
+	// index records. Archives without a readable index fall back to the
+	// compressed bytes read, which only approximates the extraction progress.
+	var xzInput io.Reader = bufferedFile
+	uncompressedSize, sizeErr := xzUncompressedSize(archivePath)
+	if sizeErr != nil {
+		fileInfo, err := file.Stat()
+		if err != nil {
+			return fmt.Errorf("failed to stat archive file: %w", err)
+		}
+		xzInput = &progressTracker{
+			reader:   bufferedFile,
+			total:    fileInfo.Size(),
+			cancelCh: cancelCh,
+			callback: report,
+		}
+	}
+
+	xzReader, err := xz.NewReader(xzInput)
 	if err != nil {
 		return fmt.Errorf("failed to create xz reader: %w", err)
 	}
 
-	bufferedXzReader := bufio.NewReaderSize(xzReader, bufferSize)
+	var tarInput io.Reader = xzReader
+	if sizeErr == nil {
+		tarInput = &progressTracker{
+			reader:   xzReader,
+			total:    uncompressedSize,
+			cancelCh: cancelCh,
+			callback: report,
+		}
+	}
+
+	bufferedXzReader := bufio.NewReaderSize(tarInput, bufferSize)
 	tarReader := tar.NewReader(bufferedXzReader)
 
 	copyBuffer := make([]byte, bufferSize)
@@ -193,8 +335,12 @@ extractLoop:
 		}
 		entryCount++
 
-		// Use header.Name as is without modifying the path
-		targetPath := filepath.Join(destDir, header.Name)
+		// Get proper file path ensuring no path traversal
+		targetPath, err := extractPath(destDir, header.Name)
+		if err != nil {
+			setFirstError(err)
+			break extractLoop
+		}
 
 		switch header.Typeflag {
 		case tar.TypeDir:
@@ -216,7 +362,7 @@ extractLoop:
 					}
 
 					wg.Add(1)
-					go func(targetPath string, fileMode int64, contents []byte) {
+					go func(targetPath, name string, fileMode int64, contents []byte) {
 						defer wg.Done()
 						select {
 						case sem <- struct{}{}: // Acquire semaphore
@@ -231,26 +377,40 @@ extractLoop:
 							return
 						}
 
+						if delta != nil && delta.reuseBytes(targetPath, name, contents, os.FileMode(fileMode)) {
+							return
+						}
 						if err := os.WriteFile(targetPath, contents, os.FileMode(fileMode)); err != nil {
 							errChan <- fmt.Errorf("failed to write file %s: %w", targetPath, err)
 							return
 						}
-					}(targetPath, header.Mode, fileContents)
+					}(targetPath, header.Name, header.Mode, fileContents)
 				} else {
 					if err := os.MkdirAll(filepath.Dir(targetPath), 0750); err != nil {
 						setFirstError(fmt.Errorf("failed to create parent dir for file %s: %w", targetPath, err))
 						break extractLoop
 					}
 
+					// Wrap tarReader with cancellation check
+					cancelReader := &CancelableReader{Reader: tarReader, CancelCh: cancelCh}
+
+					if delta != nil {
+						handled, err := delta.writeStream(targetPath, header.Name, cancelReader, header.Size, os.FileMode(header.Mode), copyBuffer)
+						if err != nil {
+							setFirstError(err)
+							break extractLoop
+						}
+						if handled {
+							continue extractLoop
+						}
+					}
+
 					outFile, err := os.OpenFile(targetPath, os.O_CREATE|os.O_WRONLY, os.FileMode(header.Mode))
 					if err != nil {
 						setFirstError(fmt.Errorf("failed to create file %s: %w", targetPath, err))
 						break extractLoop
 					}
 
-					// Wrap tarReader with cancellation check
-					cancelReader := &CancelableReader{Reader: tarReader, CancelCh: cancelCh}
-
 					bufferedWriter := bufio.NewWriterSize(outFile, bufferSize)
 					if _, err := io.CopyBuffer(bufferedWriter, cancelReader, copyBuffer); err != nil {
 						outFile.Close()
@@ -285,6 +445,11 @@ extractLoop:
 				}
 			}
 		case tar.TypeSymlink:
+			// Later entries must not be written through a link out of destDir
+			if _, err := extractPath(destDir, path.Join(path.Dir(header.Name), header.Linkname)); err != nil || path.IsAbs(header.Linkname) {
+				setFirstError(fmt.Errorf("%w: link %s points outside the extraction directory", ErrVerificationFailed, header.Name))
+				break extractLoop
+			}
 			if err := os.MkdirAll(filepath.Dir(targetPath), 0750); err != nil {
 				setFirstError(fmt.Errorf("failed to create parent dir for symlink %s: %w", targetPath, err))
 				break extractLoop
@@ -340,8 +505,8 @@ func (pt *progressTracker) Read(p []byte) (n int, err error) {
 	return
 }
 
-// saveVersionMetadata saves the build info as version.json inside the extracted directory.
-func saveVersionMetadata(build model.BlenderBuild, extractedDir string) error {
+// SaveVersionMetadata saves the build info as version.json inside the extracted directory.
+func SaveVersionMetadata(build model.BlenderBuild, extractedDir string) error {
 	metaPath := filepath.Join(extractedDir, versionMetaFilename)
 
 	if build.BuildDate.Time().IsZero() {
@@ -360,7 +525,8 @@ func saveVersionMetadata(build model.BlenderBuild, extractedDir string) error {
 }
 
 // extractZip extracts a .zip archive with progress updates.
-func extractZip(archivePath, destDir string, progressCb ExtractionProgressCallback, cancelCh <-chan struct{}) error {
+// A non-nil delta reuses unchanged files of the install being replaced.
+func extractZip(archivePath, destDir string, progressCb ExtractionProgressCallback, cancelCh <-chan struct{}, delta *deltaSource) error {
 	zipReader, err := zip.OpenReader(archivePath)
 	if err != nil {
 		return fmt.Errorf("failed to open zip archive: %w", err)
@@ -418,10 +584,11 @@ func extractZip(archivePath, destDir string, progressCb ExtractionProgressCallba
 		}
 
 		// Get proper file path ensuring no path traversal
-		targetPath := filepath.Join(destDir, file.Name)
-
-		// Make sure we follow zip entry slashes on Windows
-		targetPath = filepath.FromSlash(targetPath)
+		targetPath, err := extractPath(destDir, file.Name)
+		if err != nil {
+			setFirstError(err)
+			break
+		}
 
 		if file.FileInfo().IsDir() {
 			// Create directory
@@ -464,9 +631,11 @@ func extractZip(archivePath, destDir string, progressCb ExtractionProgressCallba
 					return
 				}
 
-				if err := os.WriteFile(targetPath, fileContents, file.Mode()); err != nil {
-					errChan <- fmt.Errorf("failed to write file %s: %w", targetPath, err)
-					return
+				if delta == nil || !delta.reuseBytes(targetPath, file.Name, fileContents, file.Mode()) {
+					if err := os.WriteFile(targetPath, fileContents, file.Mode()); err != nil {
+						errChan <- fmt.Errorf("failed to write file %s: %w", targetPath, err)
+						return
+					}
 				}
 
 				// Update processed size for progress reporting
@@ -487,18 +656,25 @@ func extractZip(archivePath, destDir string, progressCb ExtractionProgressCallba
 				break
 			}
 
-			outFile, err := os.OpenFile(targetPath, os.O_CREATE|os.O_WRONLY, file.Mode())
-			if err != nil {
-				rc.Close()
-				setFirstError(fmt.Errorf("failed to create file %s: %w", targetPath, err))
-				break
-			}
-
 			// Wrap reader with cancellation check
 			cancelReader := &CancelableReader{Reader: rc, CancelCh: cancelCh}
 
-			written, err := io.CopyBuffer(outFile, cancelReader, copyBuffer)
-			outFile.Close()
+			var written int64
+			handled := false
+			if delta != nil {
+				handled, err = delta.writeStream(targetPath, file.Name, cancelReader, int64(file.UncompressedSize64), file.Mode(), copyBuffer)
+				written = int64(file.UncompressedSize64)
+			}
+			if !handled {
+				outFile, openErr := os.OpenFile(targetPath, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, file.Mode())
+				if openErr != nil {
+					rc.Close()
+					setFirstError(fmt.Errorf("failed to create file %s: %w", targetPath, openErr))
+					break
+				}
+				written, err = io.CopyBuffer(outFile, cancelReader, copyBuffer)
+				outFile.Close()
+			}
 			rc.Close()
 
 			if err != nil {
@@ -544,85 +720,258 @@ cleanup:
 	return firstErr
 }
 
-// findRootDirInZip peeks into the ZIP archive to find the root directory name
-func findRootDirInZip(archivePath string) (string, error) {
-	zipReader, err := zip.OpenReader(archivePath)
-	if err != nil {
-		return "", fmt.Errorf("failed to open zip archive: %w", err)
+// extractPath returns where an archive entry is extracted to, rejecting
+// absolute entries and those that would end up outside of destDir
+// (e.g. "../../.bashrc").
+func extractPath(destDir, name string) (string, error) {
+	// Archive entries always use forward slashes, also on Windows
+	if path.IsAbs(name) || filepath.IsAbs(filepath.FromSlash(name)) || filepath.VolumeName(filepath.FromSlash(name)) != "" {
+		return "", fmt.Errorf("%w: entry %s has an absolute path", ErrVerificationFailed, name)
 	}
-	defer zipReader.Close()
-
-	if len(zipReader.File) == 0 {
-		return "", fmt.Errorf("empty archive")
+	targetPath := filepath.Join(destDir, filepath.FromSlash(name))
+	rel, err := filepath.Rel(destDir, targetPath)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return "", fmt.Errorf("%w: entry %s escapes the extraction directory", ErrVerificationFailed, name)
 	}
+	return targetPath, nil
+}
 
-	// Get the first entry and extract the root directory
-	firstEntry := zipReader.File[0].Name
-	parts := strings.Split(firstEntry, "/")
-	if len(parts) > 0 {
-		return parts[0], nil
+// ArchivePath returns where the archive of a build is kept while it is being downloaded.
+func ArchivePath(build model.BlenderBuild, downloadBaseDir string) string {
+	return filepath.Join(downloadBaseDir, DownloadingDir, filepath.Base(build.DownloadURL))
+}
+
+// KeptArchivePath returns where the archive of a build is kept after installing it
+// when archives are retained.
+func KeptArchivePath(build model.BlenderBuild, downloadBaseDir string) string {
+	return filepath.Join(downloadBaseDir, ArchivesDir, filepath.Base(build.DownloadURL))
+}
+
+// restoreKeptArchive moves a complete kept archive back into the .downloading
+// directory so it can be installed again without downloading. It reports
+// whether an archive was restored.
+func restoreKeptArchive(build model.BlenderBuild, downloadBaseDir string) bool {
+	if build.Size <= 0 || build.DownloadURL == "" || IsArchiveComplete(build, downloadBaseDir) {
+		return false
 	}
+	keptPath := KeptArchivePath(build, downloadBaseDir)
+	if info, err := os.Stat(keptPath); err != nil || info.Size() != build.Size {
+		return false
+	}
+	return os.Rename(keptPath, ArchivePath(build, downloadBaseDir)) == nil
+}
 
-	return "", fmt.Errorf("no root directory found in archive")
+// keepArchive moves an installed archive into the archives directory.
+func keepArchive(build model.BlenderBuild, downloadBaseDir string) error {
+	if err := os.MkdirAll(filepath.Join(downloadBaseDir, ArchivesDir), 0750); err != nil {
+		return fmt.Errorf("failed to create %s directory: %w", ArchivesDir, err)
+	}
+	return os.Rename(ArchivePath(build, downloadBaseDir), KeptArchivePath(build, downloadBaseDir))
 }
 
-// findRootDirInTarXz peeks into the archive to find the root directory name
-func findRootDirInTarXz(archivePath string) (string, error) {
-	file, err := os.Open(archivePath)
-	if err != nil {
-		return "", fmt.Errorf("failed to open archive: %w", err)
+// IsArchiveComplete reports whether the archive of a build is fully present in the
+// .downloading directory (e.g. left there by the prefetcher) and ready to install.
+func IsArchiveComplete(build model.BlenderBuild, downloadBaseDir string) bool {
+	if build.Size <= 0 || build.DownloadURL == "" {
+		return false
 	}
-	defer file.Close()
+	info, err := os.Stat(ArchivePath(build, downloadBaseDir))
+	return err == nil && info.Size() == build.Size
+}
 
-	xzReader, err := xz.NewReader(file)
-	if err != nil {
-		return "", fmt.Errorf("failed to create xz reader: %w", err)
+// PrefetchArchive downloads the archive of a build into the download directory
+// of cfg without extracting it, so a later DownloadAndExtractBuild can install
+// it without going back to the network. The download shares the bandwidth of
+// limiter with the others using it.
+func PrefetchArchive(build model.BlenderBuild, cfg config.Config, progressCb ProgressCallback, cancelCh <-chan struct{}, limiter *RateLimiter) error {
+	downloadBaseDir := cfg.DownloadDir
+	if IsArchiveComplete(build, downloadBaseDir) {
+		return nil
+	}
+	// Only prefetch what could also be installed afterwards
+	if err := CheckFreeSpace(build, downloadBaseDir); err != nil {
+		return err
 	}
+	if err := saveBuildInfo(build, downloadBaseDir); err != nil {
+		return fmt.Errorf("failed to record prefetch: %w", err)
+	}
+	if err := fetchArchive(cfg, build.DownloadURL, ArchivePath(build, downloadBaseDir), progressCb, cancelCh, nil, limiter); err != nil {
+		if errors.Is(err, ErrCancelled) {
+			return ErrCancelled
+		}
+		return fmt.Errorf("prefetch failed: %w", err)
+	}
+	return nil
+}
 
-	tarReader := tar.NewReader(xzReader)
+// DownloadAndExtractBuild downloads and extracts a build into the download
+// directory of cfg, with its download and install settings, handling cancellation.
+// The archive is kept in the .downloading directory until extraction succeeds,
+// so an interrupted or cancelled download resumes where it stopped next time.
+// A non-nil gate allows pausing the download phase, a non-nil limiter shares
+// the bandwidth limit with the other downloads using it. Errors are classified
+// with Classify, e.g. as a *NetworkError or *ChecksumError.
+func DownloadAndExtractBuild(build model.BlenderBuild, cfg config.Config, progressCb ProgressCallback, extractCb ExtractionProgressCallback, cancelCh <-chan struct{}, gate *PauseGate, limiter *RateLimiter) (string, error) {
+	extractedPath, err := downloadAndExtractBuild(build, cfg, progressCb, extractCb, cancelCh, gate, limiter, sourceAny)
+	return extractedPath, Classify(err)
+}
 
-	// Read the first header
-	header, err := tarReader.Next()
-	if err != nil {
-		if err == io.EOF {
-			return "", fmt.Errorf("empty archive")
+// ReinstallBuild downloads and installs a build like DownloadAndExtractBuild,
+// but always fetches the archive afresh instead of using a kept one, e.g. to
+// replace an install whose files were modified. The install it replaces is
+// backed up to .oldbuilds.
+func ReinstallBuild(build model.BlenderBuild, cfg config.Config, progressCb ProgressCallback, extractCb ExtractionProgressCallback, cancelCh <-chan struct{}, gate *PauseGate, limiter *RateLimiter) (string, error) {
+	extractedPath, err := downloadAndExtractBuild(build, cfg, progressCb, extractCb, cancelCh, gate, limiter, sourceNetwork)
+	return extractedPath, Classify(err)
+}
+
+// ReplaceCorruptedBuild downloads a build afresh like ReinstallBuild to replace
+// an install with missing or damaged files. The broken install waits in
+// .oldbuilds until the new one verifies against its manifest and is removed
+// then, so it never becomes a rollback target.
+func ReplaceCorruptedBuild(build model.BlenderBuild, cfg config.Config, progressCb ProgressCallback, extractCb ExtractionProgressCallback, cancelCh <-chan struct{}, gate *PauseGate, limiter *RateLimiter) (string, error) {
+	var name, root string
+	if existing := findExistingBuildDir(build, cfg.Roots()); existing != "" {
+		name, root = filepath.Base(existing), filepath.Dir(existing)
+	}
+	before := backupsOf(name, root)
+	extractedPath, err := ReinstallBuild(build, cfg, progressCb, extractCb, cancelCh, gate, limiter)
+	if err != nil || name == "" {
+		return extractedPath, err
+	}
+	if err := VerifyManifest(extractedPath); err != nil {
+		return extractedPath, fmt.Errorf("the new install doesn't verify either, the broken one stays in %s: %w", OldBuildsDir, err)
+	}
+	for backup := range backupsOf(name, root) {
+		if !before[backup] {
+			if err := os.RemoveAll(backup); err != nil {
+				return extractedPath, fmt.Errorf("failed to remove the broken install: %w", err)
+			}
 		}
-		return "", fmt.Errorf("error reading tar header: %w", err)
 	}
+	return extractedPath, nil
+}
 
-	// Extract the root directory from the path
-	rootPath := header.Name
-	parts := strings.Split(rootPath, "/")
-	if len(parts) > 0 {
-		return parts[0], nil
+// backupsOf returns the paths of the backups in the .oldbuilds of root of
+// the build directory called name.
+func backupsOf(name, root string) map[string]bool {
+	backups := make(map[string]bool)
+	if name == "" {
+		return backups
+	}
+	oldBuildsDir := filepath.Join(root, OldBuildsDir)
+	entries, err := os.ReadDir(oldBuildsDir)
+	if err != nil {
+		return backups
+	}
+	for _, entry := range entries {
+		if entry.IsDir() && strings.HasPrefix(entry.Name(), name+"_") {
+			backups[filepath.Join(oldBuildsDir, entry.Name())] = true
+		}
 	}
+	return backups
+}
 
-	return "", fmt.Errorf("no root directory found in archive")
+// RepairBuild extracts an installed build again from its kept archive, replacing
+// a damaged install. It never downloads: without a complete kept archive it
+// fails with ErrNoKeptArchive.
+func RepairBuild(build model.BlenderBuild, cfg config.Config, extractCb ExtractionProgressCallback, cancelCh <-chan struct{}) (string, error) {
+	extractedPath, err := downloadAndExtractBuild(build, cfg, nil, extractCb, cancelCh, nil, nil, sourceKept)
+	return extractedPath, Classify(err)
 }
 
-// DownloadAndExtractBuild downloads and extracts a build, handling cancellation.
-func DownloadAndExtractBuild(build model.BlenderBuild, downloadBaseDir string, progressCb ProgressCallback, cancelCh <-chan struct{}) (string, error) {
+// HasKeptArchive reports whether a complete archive of the build is kept in
+// archives/, so it can be repaired.
+func HasKeptArchive(build model.BlenderBuild, downloadBaseDir string) bool {
+	if build.Size <= 0 || build.DownloadURL == "" {
+		return false
+	}
+	info, err := os.Stat(KeptArchivePath(build, downloadBaseDir))
+	return err == nil && info.Size() == build.Size
+}
+
+// Where downloadAndExtractBuild gets the archive of a build from
+type archiveSource int
+
+const (
+	sourceAny     archiveSource = iota // A kept or prefetched archive if there is one, else the network
+	sourceKept                         // Only a kept archive, for repairs
+	sourceNetwork                      // The network, ignoring kept archives, for reinstalls
+)
+
+func downloadAndExtractBuild(build model.BlenderBuild, cfg config.Config, progressCb ProgressCallback, extractCb ExtractionProgressCallback, cancelCh <-chan struct{}, gate *PauseGate, limiter *RateLimiter, source archiveSource) (_ string, err error) {
+	downloadBaseDir := cfg.DownloadDir
+
 	// 1. Download
 	downloadFileName := filepath.Base(build.DownloadURL)
 	downloadTempDir := filepath.Join(downloadBaseDir, DownloadingDir)
 	if err := os.MkdirAll(downloadTempDir, 0750); err != nil {
 		return "", fmt.Errorf("failed to create download temp dir: %w", err)
 	}
-	downloadPath := filepath.Join(downloadTempDir, downloadFileName)
+	downloadPath := ArchivePath(build, downloadBaseDir)
+
+	// A re-published archive of the installed commit isn't worth downloading.
+	// Its metadata is taken over, so the build no longer shows as an update.
+	if source == sourceAny {
+		if dir := findExistingBuildDir(build, cfg.Roots()); dir != "" && sameCommit(build, dir) {
+			keepUserMetadata(&build, dir)
+			if err := SaveVersionMetadata(build, dir); err != nil {
+				return "", err
+			}
+			return dir, fmt.Errorf("%s (%s): %w", build.Version, build.Hash, ErrAlreadyInstalled)
+		}
+	}
+
+	// Refuse up front rather than failing halfway through extraction
+	if err := CheckFreeSpace(build, downloadBaseDir); err != nil {
+		return "", err
+	}
 
-	// Defer cleanup of the downloaded archive file
+	// Remember the build until its archive is installed or gone, so an interrupted
+	// download can be resumed after a restart
+	if err := saveBuildInfo(build, downloadBaseDir); err != nil {
+		return "", fmt.Errorf("failed to record download: %w", err)
+	}
 	defer func() {
-		if err := os.Remove(downloadPath); err != nil && !os.IsNotExist(err) {
+		if PartialSize(build, downloadBaseDir) == 0 {
+			_ = os.Remove(buildInfoPath(build, downloadBaseDir))
 		}
 	}()
 
-	if err := downloadFile(build.DownloadURL, downloadPath, progressCb, cancelCh); err != nil {
+	// A prefetched or kept archive can be installed straight away
+	restored := source != sourceNetwork && restoreKeptArchive(build, downloadBaseDir)
+	if IsArchiveComplete(build, downloadBaseDir) {
+		if progressCb != nil {
+			progressCb(build.Size, build.Size)
+		}
+	} else if source == sourceKept {
+		return "", fmt.Errorf("%s: %w", build.Version, ErrNoKeptArchive)
+	} else if err := fetchArchive(cfg, build.DownloadURL, downloadPath, progressCb, cancelCh, gate, limiter); err != nil {
 		if errors.Is(err, ErrCancelled) {
 			return "", ErrCancelled // Propagate cancellation error
 		}
 		return "", fmt.Errorf("download failed: %w", err)
 	}
 
+	// Journal the archive as downloaded and what became of it, for audits
+	if entry, journalErr := newJournalEntry(build, downloadPath); journalErr == nil {
+		defer func() {
+			entry.Result = journalResult(err)
+			if err != nil {
+				entry.Error = err.Error()
+			}
+			_ = AppendJournal(entry)
+		}()
+	}
+
+	// A short or oversized archive would only fail later during extraction
+	if build.Size > 0 {
+		if info, err := os.Stat(downloadPath); err != nil || info.Size() != build.Size {
+			_ = os.Remove(downloadPath)
+			return "", fmt.Errorf("%w: expected %d bytes", ErrVerificationFailed, build.Size)
+		}
+	}
+
 	// Check for cancellation after download, before extraction
 	select {
 	case <-cancelCh:
@@ -631,95 +980,230 @@ func DownloadAndExtractBuild(build model.BlenderBuild, downloadBaseDir string, p
 		// Continue
 	}
 
-	// 2. The archive contains a root directory, we'll extract directly to downloadBaseDir
-	// Look for any existing directory with this build version
-	var existingBuildDir string
-	entries, err := os.ReadDir(downloadBaseDir)
-	if err == nil {
-		// Find any directories that might contain this version
-		version := build.Version
-		for _, entry := range entries {
-			if entry.IsDir() && entry.Name() != DownloadingDir && entry.Name() != OldBuildsDir {
-				// Check if this directory contains the version we're downloading
-				if strings.Contains(entry.Name(), version) {
-					existingBuildDir = filepath.Join(downloadBaseDir, entry.Name())
-					break
-				}
-			}
+	// 2. Look for any existing directory with this build version
+	existingBuildDir := findExistingBuildDir(build, cfg.Roots())
+
+	// With delta updates unchanged files are reused from the existing build,
+	// which stays in place until the new one is complete
+	var delta *deltaSource
+	if existingBuildDir != "" && cfg.DeltaUpdates {
+		delta = newDeltaSource(existingBuildDir)
+	}
+
+	// 3. Extract into a staging directory inside .downloading, so a scan never
+	// finds a partial install. Leftovers of an interrupted extraction are discarded.
+	stagingDir := StagingPath(build, downloadBaseDir)
+	if err := os.RemoveAll(stagingDir); err != nil {
+		return "", fmt.Errorf("failed to clear staging directory: %w", err)
+	}
+	if err := os.MkdirAll(stagingDir, 0750); err != nil {
+		return "", fmt.Errorf("failed to create staging directory: %w", err)
+	}
+	defer os.RemoveAll(stagingDir)
+
+	var rootDir string
+	var extractErr error
+	extractor := newExtractor(cfg.Extractor, downloadFileName, delta)
+
+	// Handle different archive formats
+	if strings.HasSuffix(downloadFileName, ".dmg") {
+		// Disk images have no root directory, install the app bundle into a versioned one
+		rootDir = strings.TrimSuffix(downloadFileName, ".dmg")
+
+		extractErr = extractDmg(downloadPath, stagingDir, rootDir, extractCb, cancelCh)
+	} else if extractor.Supports(downloadFileName) {
+		// The root directory is whatever the archive extracted into, rather
+		// than a name listed in it, which may be "." or worse
+		extractErr = extractor.Extract(downloadPath, stagingDir, extractCb, cancelCh)
+		if extractErr == nil {
+			rootDir, extractErr = singleRootDir(stagingDir)
 		}
+	} else {
+		return "", fmt.Errorf("unsupported archive format: %s", downloadFileName)
+	}
+	if extractErr == nil {
+		extractErr = checkRootDir(rootDir)
 	}
 
-	// If we found an existing build directory, back it up
-	if existingBuildDir != "" {
-		oldBuildsDir := filepath.Join(downloadBaseDir, OldBuildsDir)
-		if err := os.MkdirAll(oldBuildsDir, 0750); err != nil {
-			return "", fmt.Errorf("failed to create %s directory: %w", OldBuildsDir, err)
-		}
-		timestamp := time.Now().Format("20060102_150405")
-		oldBuildName := fmt.Sprintf("%s_%s", filepath.Base(existingBuildDir), timestamp)
-		oldBuildPath := filepath.Join(oldBuildsDir, oldBuildName)
-		if err := os.Rename(existingBuildDir, oldBuildPath); err != nil {
-			if errRem := os.RemoveAll(existingBuildDir); errRem != nil {
-				return "", fmt.Errorf("failed to replace old build dir: %w", err)
-			}
+	// Handle extraction error, the staging directory is removed on return
+	if extractErr != nil {
+		if errors.Is(extractErr, ErrCancelled) {
+			return "", ErrCancelled // Propagate cancellation, the archive stays for next time
 		}
+		// The archive itself may be corrupt, so don't resume from it again
+		_ = os.Remove(downloadPath)
+		return "", &ExtractionError{Err: extractErr}
 	}
+	stagedRootDir := filepath.Join(stagingDir, rootDir)
 
-	// 3. Extract based on archive type
-	extractionCb := func(progress float64) {
-		if progressCb != nil {
-			// Use a large virtual size to indicate extraction phase to the UI
-			const extractionVirtualSize int64 = 100 * 1024 * 1024
-			currentBytes := int64(progress * float64(extractionVirtualSize))
-			progressCb(currentBytes, extractionVirtualSize)
+	// 4. Save Metadata, with the tags and expiry of the install it replaces
+	keepUserMetadata(&build, existingBuildDir)
+	if err := SaveVersionMetadata(build, stagedRootDir); err != nil {
+		return "", fmt.Errorf("metadata save failed: %w", err)
+	}
+
+	// 5. Record checksums so the install can be verified later
+	if err := WriteManifest(stagedRootDir); err != nil {
+		return "", fmt.Errorf("manifest write failed: %w", err)
+	}
+
+	// 6. Move the complete build into place, backing up the one it replaces
+	if err := installStagedBuild(stagedRootDir, downloadBaseDir, rootDir, existingBuildDir); err != nil {
+		return "", err
+	}
+	extractedRootDir := filepath.Join(downloadBaseDir, rootDir)
+
+	// The archive is no longer needed once it has been installed, unless archives
+	// are kept. One that was kept before goes back regardless.
+	if restored || cfg.KeepArchives {
+		if err := keepArchive(build, downloadBaseDir); err != nil {
+			return extractedRootDir, fmt.Errorf("failed to keep downloaded archive: %w", err)
 		}
+	} else if err := os.Remove(downloadPath); err != nil && !os.IsNotExist(err) {
+		return extractedRootDir, fmt.Errorf("failed to remove downloaded archive: %w", err)
 	}
 
-	var extractedRootDir string
-	var extractErr error
+	return extractedRootDir, nil
+}
 
-	// Handle different archive formats
-	if strings.HasSuffix(downloadFileName, ".tar.xz") {
-		// Peek into the archive to find the root directory
-		rootDir, err := findRootDirInTarXz(downloadPath)
+// findExistingBuildDir returns the installed directory of the build's version
+// in any of roots, or "" if there is none.
+func findExistingBuildDir(build model.BlenderBuild, roots []string) string {
+	for _, root := range roots {
+		entries, err := os.ReadDir(root)
 		if err != nil {
-			return "", fmt.Errorf("failed to find root directory in archive: %w", err)
+			continue
+		}
+		ignore := LoadIgnore(root)
+		for _, entry := range entries {
+			if entry.IsDir() && entry.Name() != DownloadingDir && entry.Name() != OldBuildsDir && entry.Name() != ArchivesDir &&
+				!ignore.Ignored(entry.Name()) {
+				// Check if this directory contains the version we're downloading
+				if strings.Contains(entry.Name(), build.Version) {
+					return filepath.Join(root, entry.Name())
+				}
+			}
 		}
-		extractedRootDir = filepath.Join(downloadBaseDir, rootDir)
+	}
+	return ""
+}
 
-		// Extract the archive
-		extractErr = extractTarXz(downloadPath, downloadBaseDir, extractionCb, cancelCh)
-	} else if strings.HasSuffix(downloadFileName, ".zip") {
-		// Peek into the archive to find the root directory
-		rootDir, err := findRootDirInZip(downloadPath)
-		if err != nil {
-			return "", fmt.Errorf("failed to find root directory in zip archive: %w", err)
+// sameCommit reports whether the build installed in dir was made from the
+// same commit as build, according to its version.json.
+func sameCommit(build model.BlenderBuild, dir string) bool {
+	if build.Hash == "" {
+		return false
+	}
+	data, err := os.ReadFile(filepath.Join(dir, versionMetaFilename))
+	if err != nil {
+		return false
+	}
+	var installed model.BlenderBuild
+	if err := json.Unmarshal(data, &installed); err != nil {
+		return false
+	}
+	return installed.Hash == build.Hash && installed.Branch == build.Branch
+}
+
+// keepUserMetadata carries the tags, favorite mark, expiry and launch history
+// of the build installed in dir over to build. An expiry set on build wins, as trying out
+// an update of a kept build makes it temporary.
+func keepUserMetadata(build *model.BlenderBuild, dir string) {
+	if dir == "" {
+		return
+	}
+	data, err := os.ReadFile(filepath.Join(dir, versionMetaFilename))
+	if err != nil {
+		return
+	}
+	var installed model.BlenderBuild
+	if err := json.Unmarshal(data, &installed); err != nil {
+		return
+	}
+	build.Tags, build.Favorite = installed.Tags, installed.Favorite
+	build.LastLaunched, build.LaunchCount = installed.LastLaunched, installed.LaunchCount
+	if build.ExpiresAt == nil {
+		build.ExpiresAt = installed.ExpiresAt
+	}
+}
+
+// checkRootDir rejects the root directory of an archive unless it names a
+// build directory of its own in the download directory.
+func checkRootDir(name string) error {
+	switch {
+	case name == "", name == ".", name == "..", name == DownloadingDir, name == OldBuildsDir, name == ArchivesDir,
+		strings.ContainsAny(name, `/\`):
+		return fmt.Errorf("%w: %q isn't a build directory", ErrVerificationFailed, name)
+	}
+	return nil
+}
+
+// BackupTimeFormat is the time appended to the name of a build backed up to
+// .oldbuilds. Its fraction of a second keeps backups made in one second apart.
+const BackupTimeFormat = "20060102_150405.000000000"
+
+// BackupPath returns where the build directory called name is backed up to in
+// oldBuildsDir at the given time, numbered should that backup exist already.
+func BackupPath(oldBuildsDir, name string, at time.Time) string {
+	backup := filepath.Join(oldBuildsDir, name+"_"+at.Format(BackupTimeFormat))
+	for i := 2; ; i++ {
+		if _, err := os.Lstat(backup); os.IsNotExist(err) {
+			return backup
 		}
-		extractedRootDir = filepath.Join(downloadBaseDir, rootDir)
+		backup = filepath.Join(oldBuildsDir, fmt.Sprintf("%s_%s-%d", name, at.Format(BackupTimeFormat), i))
+	}
+}
 
-		// Extract the zip archive
-		extractErr = extractZip(downloadPath, downloadBaseDir, extractionCb, cancelCh)
-	} else {
-		return "", fmt.Errorf("unsupported archive format: %s", downloadFileName)
+// StagingPath returns the directory a build is extracted into before it is
+// moved into the download directory. It is named after the version and commit
+// like the download, so builds of one version on two branches extract apart.
+func StagingPath(build model.BlenderBuild, downloadBaseDir string) string {
+	name := build.Version
+	if build.Hash != "" {
+		name += "-" + build.Hash[:min(len(build.Hash), 8)]
 	}
+	return filepath.Join(downloadBaseDir, DownloadingDir, name+"-tmp")
+}
 
-	// Handle extraction error
-	if extractErr != nil {
-		// Attempt to clean up partially extracted directory
-		if extractedRootDir != "" {
-			if remErr := os.RemoveAll(extractedRootDir); remErr != nil {
-			}
+// installStagedBuild renames a fully extracted build from staging to the
+// directory name in root. The existing install of the version, if any, is
+// moved to the .oldbuilds of its root first and put back should the rename fail.
+func installStagedBuild(staged, root, name, existing string) error {
+	// Only a build directory of its own may be replaced, never the root or
+	// the launcher's directories in it
+	if err := checkRootDir(name); err != nil {
+		return err
+	}
+	target := filepath.Join(root, name)
+
+	var oldBuildPath string
+	if existing != "" {
+		// Blender may have been started from it while the new one downloaded
+		if err := launch.CheckNotInUse(existing); err != nil {
+			return fmt.Errorf("can't replace the installed build: %w", err)
 		}
-		if errors.Is(extractErr, ErrCancelled) {
-			return "", ErrCancelled // Propagate cancellation
+		oldBuildsDir := filepath.Join(filepath.Dir(existing), OldBuildsDir)
+		if err := os.MkdirAll(oldBuildsDir, 0750); err != nil {
+			return fmt.Errorf("failed to create %s directory: %w", OldBuildsDir, err)
+		}
+		oldBuildPath = BackupPath(oldBuildsDir, filepath.Base(existing), time.Now())
+		if err := os.Rename(existing, oldBuildPath); err != nil {
+			return fmt.Errorf("failed to back up old build dir: %w", err)
 		}
-		return "", fmt.Errorf("extraction failed: %w", extractErr)
 	}
 
-	// 4. Save Metadata
-	if err := saveVersionMetadata(build, extractedRootDir); err != nil {
-		return extractedRootDir, fmt.Errorf("metadata save failed: %w", err)
+	// A directory of the same name that isn't a build of this version is in the way
+	if existing != target {
+		if err := os.RemoveAll(target); err != nil {
+			return fmt.Errorf("failed to replace %s: %w", target, err)
+		}
 	}
 
-	return extractedRootDir, nil
+	if err := os.Rename(staged, target); err != nil {
+		if oldBuildPath != "" {
+			_ = os.Rename(oldBuildPath, existing)
+		}
+		return fmt.Errorf("failed to move build into place: %w", err)
+	}
+	return nil
 }
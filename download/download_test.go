@@ -0,0 +1,1709 @@
+package download
+
+import (
+	"TUI-Blender-Launcher/config"
+	"TUI-Blender-Launcher/launch"
+	"TUI-Blender-Launcher/model"
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/ulikunitz/xz"
+)
+
+func TestDownloadFileResume(t *testing.T) {
+	payload := bytes.Repeat([]byte("blender"), 10000)
+
+	// http.ServeContent honours Range headers, like the Blender builder does
+	var gotRange string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotRange = r.Header.Get("Range")
+		http.ServeContent(w, r, "blender.tar.xz", time.Now(), bytes.NewReader(payload))
+	}))
+	defer server.Close()
+
+	destPath := filepath.Join(t.TempDir(), "blender.tar.xz")
+
+	// Simulate an interrupted previous attempt
+	partial := len(payload) / 3
+	if err := os.WriteFile(destPath, payload[:partial], 0644); err != nil {
+		t.Fatalf("Failed to write partial file: %v", err)
+	}
+
+	var firstReport int64 = -1
+	progressCb := func(downloaded, total int64) {
+		if firstReport < 0 {
+			firstReport = downloaded
+		}
+	}
+
+	if err := downloadFile(server.URL, destPath, progressCb, make(chan struct{}), fetchOptions{}); err != nil {
+		t.Fatalf("downloadFile returned an error: %v", err)
+	}
+
+	if gotRange == "" {
+		t.Error("Expected a Range header when resuming, got none")
+	}
+	if firstReport != int64(partial) {
+		t.Errorf("Expected first progress report at %d bytes, got %d", partial, firstReport)
+	}
+
+	data, err := os.ReadFile(destPath)
+	if err != nil {
+		t.Fatalf("Failed to read downloaded file: %v", err)
+	}
+	if !bytes.Equal(data, payload) {
+		t.Errorf("Resumed file doesn't match payload: got %d bytes, want %d", len(data), len(payload))
+	}
+}
+
+func TestFetchArchiveClientID(t *testing.T) {
+	payload := bytes.Repeat([]byte("blender"), 1000)
+
+	// Each archive is only served once both downloads have asked for theirs
+	var arrived sync.WaitGroup
+	arrived.Add(2)
+	var mu sync.Mutex
+	got := make(map[string]string)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		_, seen := got[r.URL.Path]
+		got[r.URL.Path] = r.Header.Get("X-Download-ID")
+		mu.Unlock()
+		if !seen {
+			arrived.Done()
+			arrived.Wait()
+		}
+		http.ServeContent(w, r, "blender.tar.xz", time.Now(), bytes.NewReader(payload))
+	}))
+	defer server.Close()
+
+	dir := t.TempDir()
+	limiter := &RateLimiter{}
+	errCh := make(chan error, 2)
+	for _, id := range []string{"first", "second"} {
+		cfg := testConfig(dir)
+		cfg.UUID = id
+		cfg.DownloadSegments = 1
+		go func() {
+			errCh <- fetchArchive(cfg, server.URL+"/"+id, filepath.Join(dir, id), nil, make(chan struct{}), nil, limiter)
+		}()
+	}
+	for range 2 {
+		if err := <-errCh; err != nil {
+			t.Fatalf("fetchArchive returned an error: %v", err)
+		}
+	}
+
+	for _, id := range []string{"first", "second"} {
+		if got["/"+id] != id {
+			t.Errorf("download %s sent X-Download-ID %q, want %q", id, got["/"+id], id)
+		}
+	}
+}
+
+func TestDownloadSegmented(t *testing.T) {
+	payload := bytes.Repeat([]byte("0123456789"), 5000)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.ServeContent(w, r, "blender.zip", time.Now(), bytes.NewReader(payload))
+	}))
+	defer server.Close()
+
+	total, err := probeRangeSupport(server.URL, fetchOptions{})
+	if err != nil {
+		t.Fatalf("probeRangeSupport returned an error: %v", err)
+	}
+	if total != int64(len(payload)) {
+		t.Fatalf("Expected probed size %d, got %d", len(payload), total)
+	}
+
+	destPath := filepath.Join(t.TempDir(), "blender.zip")
+
+	// Pretend the second segment was half done in an earlier attempt
+	const count = 3
+	segmentSize := total / count
+	if err := os.WriteFile(segmentPath(destPath, 1, count), payload[segmentSize:segmentSize+segmentSize/2], 0644); err != nil {
+		t.Fatalf("Failed to write partial segment: %v", err)
+	}
+
+	var lastDownloaded, lastTotal int64
+	progressCb := func(downloaded, total int64) {
+		lastDownloaded, lastTotal = downloaded, total
+	}
+
+	if err := downloadSegmented(server.URL, destPath, total, count, progressCb, make(chan struct{}), fetchOptions{}); err != nil {
+		t.Fatalf("downloadSegmented returned an error: %v", err)
+	}
+
+	data, err := os.ReadFile(destPath)
+	if err != nil {
+		t.Fatalf("Failed to read merged file: %v", err)
+	}
+	if !bytes.Equal(data, payload) {
+		t.Errorf("Merged file doesn't match payload: got %d bytes, want %d", len(data), len(payload))
+	}
+	if lastDownloaded != total || lastTotal != total {
+		t.Errorf("Expected final progress %d/%d, got %d/%d", total, total, lastDownloaded, lastTotal)
+	}
+
+	if leftovers, _ := filepath.Glob(destPath + ".part*"); len(leftovers) > 0 {
+		t.Errorf("Expected segment files to be removed, found %v", leftovers)
+	}
+}
+
+func TestDownloadSegment(t *testing.T) {
+	payload := bytes.Repeat([]byte("0123456789"), 100)
+	tests := []struct {
+		name    string
+		handler http.HandlerFunc
+		have    []byte // Part file left by an earlier attempt
+		wantErr bool
+	}{
+		{"resumed", func(w http.ResponseWriter, r *http.Request) {
+			http.ServeContent(w, r, "blender.zip", time.Now(), bytes.NewReader(payload))
+		}, payload[100:150], false},
+		{"oversized part", func(w http.ResponseWriter, r *http.Request) {
+			http.ServeContent(w, r, "blender.zip", time.Now(), bytes.NewReader(payload))
+		}, payload[:300], false},
+		{"wrong range", func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Range", fmt.Sprintf("bytes 0-99/%d", len(payload)))
+			w.WriteHeader(http.StatusPartialContent)
+			_, _ = w.Write(payload[:100])
+		}, nil, true},
+		{"cut short", func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Range", fmt.Sprintf("bytes 100-199/%d", len(payload)))
+			w.WriteHeader(http.StatusPartialContent)
+			_, _ = w.Write(payload[100:150])
+		}, nil, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(tt.handler)
+			defer server.Close()
+			partPath := filepath.Join(t.TempDir(), "blender.zip.part1-3")
+			var done atomic.Int64
+			if tt.have != nil {
+				if err := os.WriteFile(partPath, tt.have, 0644); err != nil {
+					t.Fatalf("Failed to write the part: %v", err)
+				}
+				done.Store(int64(len(tt.have)))
+			}
+
+			err := downloadSegment(context.Background(), server.URL, partPath, 100, 199, &done, fetchOptions{})
+			if tt.wantErr {
+				if err == nil {
+					t.Error("Expected the segment to be refused")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("downloadSegment returned an error: %v", err)
+			}
+			if data, err := os.ReadFile(partPath); err != nil || !bytes.Equal(data, payload[100:200]) {
+				t.Errorf("Expected exactly bytes 100-199 in the part, got %d bytes, %v", len(data), err)
+			}
+		})
+	}
+}
+
+func TestVerifyManifest(t *testing.T) {
+	buildDir := t.TempDir()
+	files := map[string]string{
+		"blender":                   "binary",
+		"4.2/scripts/startup/bl.py": "import bpy",
+	}
+	for name, content := range files {
+		path := filepath.Join(buildDir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatalf("Failed to create directory: %v", err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatalf("Failed to write file: %v", err)
+		}
+	}
+
+	if err := VerifyManifest(buildDir); !errors.Is(err, ErrNoManifest) {
+		t.Fatalf("Expected ErrNoManifest before writing one, got %v", err)
+	}
+	if err := WriteManifest(buildDir); err != nil {
+		t.Fatalf("WriteManifest returned an error: %v", err)
+	}
+	if err := VerifyManifest(buildDir); err != nil {
+		t.Fatalf("Expected intact build to verify, got %v", err)
+	}
+
+	// Files added after installation don't count, changed ones do
+	if err := os.WriteFile(filepath.Join(buildDir, "user.py"), []byte("extra"), 0644); err != nil {
+		t.Fatalf("Failed to write file: %v", err)
+	}
+	if err := VerifyManifest(buildDir); err != nil {
+		t.Errorf("Expected extra files to be ignored, got %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(buildDir, "blender"), []byte("corrupt"), 0644); err != nil {
+		t.Fatalf("Failed to write file: %v", err)
+	}
+	if err := VerifyManifest(buildDir); !errors.Is(err, ErrVerificationFailed) {
+		t.Errorf("Expected ErrVerificationFailed for a changed file, got %v", err)
+	}
+}
+
+// writeZip creates a zip archive with the given entries
+func writeZip(t *testing.T, path string, entries map[string]string) {
+	t.Helper()
+	file, err := os.Create(path)
+	if err != nil {
+		t.Fatalf("Failed to create zip: %v", err)
+	}
+	defer file.Close()
+
+	zw := zip.NewWriter(file)
+	for name, content := range entries {
+		w, err := zw.Create(name)
+		if err != nil {
+			t.Fatalf("Failed to add %s to zip: %v", name, err)
+		}
+		if _, err := w.Write([]byte(content)); err != nil {
+			t.Fatalf("Failed to write %s to zip: %v", name, err)
+		}
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatalf("Failed to finish zip: %v", err)
+	}
+}
+
+func TestExtractZip(t *testing.T) {
+	dir := t.TempDir()
+	archive := filepath.Join(dir, "blender.zip")
+	writeZip(t, archive, map[string]string{
+		"blender-4.2.0-windows-x64/blender.exe":            "exe",
+		"blender-4.2.0-windows-x64/4.2/scripts/init.py":    "import bpy",
+		"blender-4.2.0-windows-x64/4.2/datafiles/icon.svg": "<svg/>",
+	})
+
+	destDir := filepath.Join(dir, "out")
+	var lastProgress float64
+	progressCb := func(progress float64) {
+		lastProgress = progress
+	}
+	if err := extractZip(archive, destDir, progressCb, make(chan struct{}), nil); err != nil {
+		t.Fatalf("extractZip returned an error: %v", err)
+	}
+	rootDir, err := singleRootDir(destDir)
+	if err != nil || rootDir != "blender-4.2.0-windows-x64" {
+		t.Errorf("Expected root dir blender-4.2.0-windows-x64, got %q (%v)", rootDir, err)
+	}
+	if lastProgress != 1.0 {
+		t.Errorf("Expected final progress 1.0, got %f", lastProgress)
+	}
+
+	data, err := os.ReadFile(filepath.Join(destDir, rootDir, "4.2", "scripts", "init.py"))
+	if err != nil {
+		t.Fatalf("Failed to read extracted file: %v", err)
+	}
+	if string(data) != "import bpy" {
+		t.Errorf("Extracted file has wrong content: %q", data)
+	}
+
+	// Entries escaping the destination must be rejected
+	evil := filepath.Join(dir, "evil.zip")
+	writeZip(t, evil, map[string]string{"../escaped.txt": "gotcha"})
+	if err := extractZip(evil, destDir, nil, make(chan struct{}), nil); !errors.Is(err, ErrVerificationFailed) {
+		t.Errorf("Expected ErrVerificationFailed for a path traversal entry, got %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "escaped.txt")); !os.IsNotExist(err) {
+		t.Error("Path traversal entry was written outside the destination")
+	}
+	writeZip(t, evil, map[string]string{"/escaped.txt": "gotcha"})
+	if err := extractZip(evil, destDir, nil, make(chan struct{}), nil); !errors.Is(err, ErrVerificationFailed) {
+		t.Errorf("Expected ErrVerificationFailed for an absolute entry, got %v", err)
+	}
+}
+
+// writeTarXz writes a .tar.xz archive holding the given entries, symlinks
+// for those with a link target.
+func writeTarXz(t *testing.T, path string, entries []tar.Header) {
+	t.Helper()
+	file, err := os.Create(path)
+	if err != nil {
+		t.Fatalf("Failed to create archive: %v", err)
+	}
+	defer file.Close()
+
+	xw, err := xz.NewWriter(file)
+	if err != nil {
+		t.Fatalf("Failed to create xz writer: %v", err)
+	}
+	tw := tar.NewWriter(xw)
+	for _, header := range entries {
+		if header.Linkname != "" {
+			header.Typeflag = tar.TypeSymlink
+		}
+		if err := tw.WriteHeader(&header); err != nil {
+			t.Fatalf("Failed to add %s to tar: %v", header.Name, err)
+		}
+	}
+	if err := tw.Close(); err != nil {
+		t.Fatalf("Failed to finish tar: %v", err)
+	}
+	if err := xw.Close(); err != nil {
+		t.Fatalf("Failed to finish xz stream: %v", err)
+	}
+}
+
+func TestExtractTarXzTraversal(t *testing.T) {
+	tests := []struct {
+		name  string
+		entry tar.Header
+	}{
+		{"parent directory", tar.Header{Name: "../escaped.txt", Mode: 0644}},
+		{"nested parent directory", tar.Header{Name: "blender/../../escaped.txt", Mode: 0644}},
+		{"absolute path", tar.Header{Name: "/tmp/escaped.txt", Mode: 0644}},
+		{"link out of the directory", tar.Header{Name: "blender/lib", Linkname: "../../lib"}},
+		{"absolute link", tar.Header{Name: "blender/lib", Linkname: "/usr/lib"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			archive := filepath.Join(dir, "evil.tar.xz")
+			writeTarXz(t, archive, []tar.Header{tt.entry})
+
+			destDir := filepath.Join(dir, "out")
+			if err := extractTarXz(archive, destDir, nil, make(chan struct{}), nil); !errors.Is(err, ErrVerificationFailed) {
+				t.Errorf("Expected ErrVerificationFailed, got %v", err)
+			}
+			if _, err := os.Stat(filepath.Join(dir, "escaped.txt")); !os.IsNotExist(err) {
+				t.Error("The entry was written outside the destination")
+			}
+			if _, err := os.Lstat(filepath.Join(destDir, "blender", "lib")); !os.IsNotExist(err) {
+				t.Error("The link out of the destination was created")
+			}
+		})
+	}
+
+	// Links within the archive are kept
+	dir := t.TempDir()
+	archive := filepath.Join(dir, "blender.tar.xz")
+	writeTarXz(t, archive, []tar.Header{{Name: "blender/lib/libcycles.so.4", Mode: 0644}, {Name: "blender/lib/libcycles.so", Linkname: "libcycles.so.4"}})
+	if err := extractTarXz(archive, filepath.Join(dir, "out"), nil, make(chan struct{}), nil); err != nil {
+		t.Fatalf("extractTarXz returned an error: %v", err)
+	}
+	if target, err := os.Readlink(filepath.Join(dir, "out", "blender", "lib", "libcycles.so")); err != nil || target != "libcycles.so.4" {
+		t.Errorf("Expected the link to libcycles.so.4, got %q, %v", target, err)
+	}
+}
+
+func TestExtractTarXzProgress(t *testing.T) {
+	dir := t.TempDir()
+	archive := filepath.Join(dir, "blender.tar.xz")
+
+	// A compressible payload, so compressed and uncompressed progress differ
+	var tarData bytes.Buffer
+	tw := tar.NewWriter(&tarData)
+	payload := bytes.Repeat([]byte("blender "), 64*1024)
+	for _, name := range []string{"blender-4.2.0-linux-x64/blender", "blender-4.2.0-linux-x64/lib/libcycles.so"} {
+		if err := tw.WriteHeader(&tar.Header{Name: name, Mode: 0755, Size: int64(len(payload))}); err != nil {
+			t.Fatalf("Failed to write tar header: %v", err)
+		}
+		if _, err := tw.Write(payload); err != nil {
+			t.Fatalf("Failed to write tar entry: %v", err)
+		}
+	}
+	if err := tw.Close(); err != nil {
+		t.Fatalf("Failed to finish tar: %v", err)
+	}
+
+	file, err := os.Create(archive)
+	if err != nil {
+		t.Fatalf("Failed to create archive: %v", err)
+	}
+	xw, err := xz.NewWriter(file)
+	if err != nil {
+		t.Fatalf("Failed to create xz writer: %v", err)
+	}
+	if _, err := xw.Write(tarData.Bytes()); err != nil {
+		t.Fatalf("Failed to compress archive: %v", err)
+	}
+	if err := xw.Close(); err != nil {
+		t.Fatalf("Failed to finish xz stream: %v", err)
+	}
+	// Trailing stream padding must be skipped
+	if _, err := file.Write(make([]byte, 8)); err != nil {
+		t.Fatalf("Failed to pad archive: %v", err)
+	}
+	file.Close()
+
+	size, err := xzUncompressedSize(archive)
+	if err != nil {
+		t.Fatalf("xzUncompressedSize returned an error: %v", err)
+	}
+	if size != int64(tarData.Len()) {
+		t.Errorf("Expected uncompressed size %d, got %d", tarData.Len(), size)
+	}
+
+	// Progress rises monotonically to exactly 1.0
+	var reports []float64
+	progressCb := func(progress float64) {
+		reports = append(reports, progress)
+	}
+	if err := extractTarXz(archive, filepath.Join(dir, "out"), progressCb, make(chan struct{}), nil); err != nil {
+		t.Fatalf("extractTarXz returned an error: %v", err)
+	}
+	for i := 1; i < len(reports); i++ {
+		if reports[i] < reports[i-1] || reports[i] > 1.0 {
+			t.Fatalf("Progress went from %f to %f", reports[i-1], reports[i])
+		}
+	}
+	if len(reports) < 3 || reports[len(reports)-1] != 1.0 {
+		t.Errorf("Expected intermediate reports ending at 1.0, got %v", reports)
+	}
+
+	// Files that aren't xz have no index to read
+	plain := filepath.Join(dir, "plain.txt")
+	if err := os.WriteFile(plain, []byte("not an xz file at all"), 0644); err != nil {
+		t.Fatalf("Failed to write file: %v", err)
+	}
+	if _, err := xzUncompressedSize(plain); err == nil {
+		t.Error("Expected an error for a file without an xz footer")
+	}
+}
+
+func TestInstallStagedBuild(t *testing.T) {
+	base := t.TempDir()
+	writeFile := func(path, content string) {
+		t.Helper()
+		if err := os.MkdirAll(filepath.Dir(path), 0750); err != nil {
+			t.Fatalf("Failed to create dir: %v", err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatalf("Failed to write file: %v", err)
+		}
+	}
+
+	existing := filepath.Join(base, "blender-4.2.0-linux-x64")
+	staged := filepath.Join(base, DownloadingDir, "4.2.0-tmp", "blender-4.2.0-linux-x64")
+	writeFile(filepath.Join(existing, "blender"), "old")
+	writeFile(filepath.Join(staged, "blender"), "new")
+
+	// A failed rename puts the existing build back
+	missing := filepath.Join(base, DownloadingDir, "missing")
+	if err := installStagedBuild(missing, base, filepath.Base(existing), existing); err == nil {
+		t.Fatal("Expected an error for a missing staging directory")
+	}
+	if data, err := os.ReadFile(filepath.Join(existing, "blender")); err != nil || string(data) != "old" {
+		t.Fatalf("Expected the existing build to be restored, got %q (%v)", data, err)
+	}
+
+	// The staged build replaces the existing one, which is backed up
+	if err := installStagedBuild(staged, base, filepath.Base(existing), existing); err != nil {
+		t.Fatalf("installStagedBuild returned an error: %v", err)
+	}
+	if data, err := os.ReadFile(filepath.Join(existing, "blender")); err != nil || string(data) != "new" {
+		t.Errorf("Expected the staged build in place, got %q (%v)", data, err)
+	}
+	if _, err := os.Stat(staged); !os.IsNotExist(err) {
+		t.Error("Expected the staging directory to be moved away")
+	}
+	backups, err := os.ReadDir(filepath.Join(base, OldBuildsDir))
+	if err != nil || len(backups) != 1 {
+		t.Fatalf("Expected one backup in %s, got %v (%v)", OldBuildsDir, backups, err)
+	}
+	if data, err := os.ReadFile(filepath.Join(base, OldBuildsDir, backups[0].Name(), "blender")); err != nil || string(data) != "old" {
+		t.Errorf("Expected the old build in the backup, got %q (%v)", data, err)
+	}
+
+	// Replacing it again right away keeps both backups
+	writeFile(filepath.Join(staged, "blender"), "newer")
+	if err := installStagedBuild(staged, base, filepath.Base(existing), existing); err != nil {
+		t.Fatalf("installStagedBuild returned an error: %v", err)
+	}
+	if backups, err := os.ReadDir(filepath.Join(base, OldBuildsDir)); err != nil || len(backups) != 2 {
+		t.Errorf("Expected two backups in %s, got %v (%v)", OldBuildsDir, backups, err)
+	}
+
+	// Backups made at the same time are numbered
+	at := time.Now()
+	first := BackupPath(filepath.Join(base, OldBuildsDir), "blender-4.2.0-linux-x64", at)
+	writeFile(filepath.Join(first, "blender"), "old")
+	if second := BackupPath(filepath.Join(base, OldBuildsDir), "blender-4.2.0-linux-x64", at); second != first+"-2" {
+		t.Errorf("Expected %s-2 for a second backup, got %s", first, second)
+	}
+}
+
+func TestInstallArchiveRoot(t *testing.T) {
+	tests := []struct {
+		name    string
+		entries map[string]string
+		wantDir string // Directory the build is installed to, "" if the archive is rejected
+	}{
+		{"dot-slash prefix", map[string]string{"./blender-4.2.0-linux-x64/blender": "binary"}, "blender-4.2.0-linux-x64"},
+		{"no root directory", map[string]string{"./blender": "binary"}, ""},
+		{"several root directories", map[string]string{"./blender-4.2.0/blender": "binary", "./lib/libcycles.so": "lib"}, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("XDG_STATE_HOME", t.TempDir())
+			dir := t.TempDir()
+			other := filepath.Join(dir, "blender-4.1.0-linux-x64")
+			if err := os.MkdirAll(other, 0750); err != nil {
+				t.Fatalf("Failed to create install: %v", err)
+			}
+
+			archive := filepath.Join(t.TempDir(), "blender.zip")
+			writeZip(t, archive, tt.entries)
+			payload, err := os.ReadFile(archive)
+			if err != nil {
+				t.Fatalf("Failed to read archive: %v", err)
+			}
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				http.ServeContent(w, r, "blender.zip", time.Time{}, bytes.NewReader(payload))
+			}))
+			defer server.Close()
+			build := model.BlenderBuild{Version: "4.2.0", DownloadURL: server.URL + "/blender-4.2.0-linux-x64.zip", Size: int64(len(payload))}
+
+			path, err := DownloadAndExtractBuild(build, testConfig(dir), nil, nil, make(chan struct{}), nil, nil)
+			if tt.wantDir == "" {
+				if !errors.Is(err, ErrVerificationFailed) {
+					t.Errorf("Expected the archive rejected, got %q, %v", path, err)
+				}
+			} else if want := filepath.Join(dir, tt.wantDir); err != nil || path != want {
+				t.Errorf("Expected the build installed to %s, got %q, %v", want, path, err)
+			}
+
+			// Nothing else in the download directory is touched
+			if _, err := os.Stat(other); err != nil {
+				t.Errorf("Expected the other build kept, got %v", err)
+			}
+			if _, err := os.Stat(filepath.Join(dir, DownloadingDir)); err != nil {
+				t.Errorf("Expected %s kept, got %v", DownloadingDir, err)
+			}
+		})
+	}
+
+	// The download directory itself or the launcher's directories are never replaced
+	base := t.TempDir()
+	staged := filepath.Join(base, DownloadingDir, "4.2.0-tmp", "blender")
+	if err := os.MkdirAll(staged, 0750); err != nil {
+		t.Fatalf("Failed to create dir: %v", err)
+	}
+	for _, name := range []string{"", ".", "..", DownloadingDir, OldBuildsDir, "a/b"} {
+		if err := installStagedBuild(staged, base, name, ""); !errors.Is(err, ErrVerificationFailed) {
+			t.Errorf("Expected %q refused, got %v", name, err)
+		}
+	}
+	if _, err := os.Stat(staged); err != nil {
+		t.Errorf("Expected the staged build untouched, got %v", err)
+	}
+}
+
+func TestStagingPath(t *testing.T) {
+	base := t.TempDir()
+	main := model.BlenderBuild{Version: "4.5.0", Branch: "main", Hash: "0123456789ab"}
+	branch := model.BlenderBuild{Version: "4.5.0", Branch: "npr-prototype", Hash: "fedcba987654"}
+
+	// Builds of one version downloading at once must not extract into each other
+	if StagingPath(main, base) == StagingPath(branch, base) {
+		t.Errorf("Expected builds of two commits to stage apart, both got %s", StagingPath(main, base))
+	}
+	if want := filepath.Join(base, DownloadingDir, "4.5.0-01234567-tmp"); StagingPath(main, base) != want {
+		t.Errorf("Expected %s, got %s", want, StagingPath(main, base))
+	}
+}
+
+func TestInstallStagedBuildInUse(t *testing.T) {
+	if runtime.GOOS != "linux" {
+		t.Skip("Processes are told apart by their executable only on Linux")
+	}
+	sleep, err := exec.LookPath("sleep")
+	if err != nil {
+		t.Skip("No sleep to stand in for Blender")
+	}
+	data, err := os.ReadFile(sleep)
+	if err != nil {
+		t.Fatalf("Failed to read %s: %v", sleep, err)
+	}
+	base := t.TempDir()
+	existing := filepath.Join(base, "blender-4.2.0-linux-x64")
+	staged := filepath.Join(base, DownloadingDir, "4.2.0-tmp", "blender-4.2.0-linux-x64")
+	for _, dir := range []string{existing, staged} {
+		if err := os.MkdirAll(dir, 0750); err != nil {
+			t.Fatalf("Failed to create dir: %v", err)
+		}
+		if err := os.WriteFile(filepath.Join(dir, "blender"), data, 0755); err != nil {
+			t.Fatalf("Failed to write the executable: %v", err)
+		}
+	}
+	cmd := exec.Command(filepath.Join(existing, "blender"), "60")
+	if err := cmd.Start(); err != nil {
+		t.Fatalf("Failed to start Blender: %v", err)
+	}
+	defer func() {
+		_ = cmd.Process.Kill()
+		_ = cmd.Wait()
+	}()
+
+	// Blender started while the update downloaded keeps its build
+	if err := installStagedBuild(staged, base, filepath.Base(existing), existing); !errors.Is(err, launch.ErrInUse) {
+		t.Fatalf("Expected the running build to be kept, got %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(base, OldBuildsDir)); !os.IsNotExist(err) {
+		t.Errorf("Expected no backup of the running build, got %v", err)
+	}
+}
+
+func TestCheckSpace(t *testing.T) {
+	// The directory doesn't exist yet, its parent is checked instead
+	dir := filepath.Join(t.TempDir(), "not", "created")
+
+	if err := checkSpace(dir, 1); err != nil {
+		t.Errorf("Expected a single byte to fit, got %v", err)
+	}
+	if err := checkSpace(dir, 1<<62); !errors.Is(err, ErrInsufficientSpace) {
+		t.Errorf("Expected ErrInsufficientSpace for an impossible size, got %v", err)
+	}
+}
+
+func TestIsTransient(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{"server error", fmt.Errorf("download failed: %w", &StatusError{Code: 503}), true},
+		{"rate limited", &StatusError{Code: http.StatusTooManyRequests}, true},
+		{"not found", &StatusError{Code: 404}, false},
+		{"idle timeout", fmt.Errorf("download failed: %w", ErrIdleTimeout), true},
+		{"truncated body", fmt.Errorf("download interrupted: %w", io.ErrUnexpectedEOF), true},
+		{"cancelled", ErrCancelled, false},
+		{"corrupt archive", fmt.Errorf("%w: bad zip", ErrVerificationFailed), false},
+		{"disk full", fmt.Errorf("%w in /tmp", ErrInsufficientSpace), false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := IsTransient(tt.err); got != tt.want {
+				t.Errorf("IsTransient(%v) = %v, want %v", tt.err, got, tt.want)
+			}
+		})
+	}
+
+	if RetryDelay(1) != retryBaseDelay || RetryDelay(2) != 2*retryBaseDelay || RetryDelay(10) != retryMaxDelay {
+		t.Errorf("Unexpected backoff: %v, %v, %v", RetryDelay(1), RetryDelay(2), RetryDelay(10))
+	}
+}
+
+func TestDownloadFilePause(t *testing.T) {
+	payload := bytes.Repeat([]byte("blender"), 10000)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.ServeContent(w, r, "blender.tar.xz", time.Now(), bytes.NewReader(payload))
+	}))
+	defer server.Close()
+
+	destPath := filepath.Join(t.TempDir(), "blender.tar.xz")
+
+	var received atomic.Int64
+	progressCb := func(downloaded, total int64) {
+		received.Store(downloaded)
+	}
+
+	// A gate paused up front holds the transfer before its first read
+	gate := NewPauseGate()
+	gate.Pause()
+
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- downloadFile(server.URL, destPath, progressCb, make(chan struct{}), fetchOptions{gate: gate})
+	}()
+
+	time.Sleep(100 * time.Millisecond)
+	if got := received.Load(); got != 0 {
+		t.Fatalf("Expected no data while paused, got %d bytes", got)
+	}
+
+	gate.Resume()
+	select {
+	case err := <-errCh:
+		if err != nil {
+			t.Fatalf("downloadFile returned an error: %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("Download did not finish after resuming")
+	}
+
+	data, err := os.ReadFile(destPath)
+	if err != nil {
+		t.Fatalf("Failed to read downloaded file: %v", err)
+	}
+	if !bytes.Equal(data, payload) {
+		t.Errorf("Downloaded file differs from payload (got %d bytes, want %d)", len(data), len(payload))
+	}
+}
+
+func TestRestoreKeptArchive(t *testing.T) {
+	payload := bytes.Repeat([]byte("blender"), 100)
+
+	tests := []struct {
+		name     string
+		keptSize int // Bytes in archives/, -1 for no kept archive
+		restored bool
+	}{
+		{"complete kept archive", len(payload), true},
+		{"truncated kept archive", len(payload) / 2, false},
+		{"no kept archive", -1, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			build := model.BlenderBuild{DownloadURL: "https://example.com/blender.tar.xz", Size: int64(len(payload))}
+
+			for _, sub := range []string{DownloadingDir, ArchivesDir} {
+				if err := os.MkdirAll(filepath.Join(dir, sub), 0750); err != nil {
+					t.Fatalf("Failed to create %s: %v", sub, err)
+				}
+			}
+			if tt.keptSize >= 0 {
+				if err := os.WriteFile(KeptArchivePath(build, dir), payload[:tt.keptSize], 0644); err != nil {
+					t.Fatalf("Failed to write kept archive: %v", err)
+				}
+			}
+
+			if got := restoreKeptArchive(build, dir); got != tt.restored {
+				t.Fatalf("restoreKeptArchive() = %v, want %v", got, tt.restored)
+			}
+			if got := IsArchiveComplete(build, dir); got != tt.restored {
+				t.Errorf("IsArchiveComplete() = %v after restoring, want %v", got, tt.restored)
+			}
+		})
+	}
+}
+
+func TestRepairBuild(t *testing.T) {
+	t.Setenv("XDG_STATE_HOME", t.TempDir())
+	dir := t.TempDir()
+	installDir := filepath.Join(dir, "blender-4.2.0-linux-x64")
+	if err := os.MkdirAll(installDir, 0750); err != nil {
+		t.Fatalf("Failed to create install: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(installDir, "blender"), []byte("damaged"), 0755); err != nil {
+		t.Fatalf("Failed to write install: %v", err)
+	}
+
+	archive := filepath.Join(dir, "blender.zip")
+	writeZip(t, archive, map[string]string{"blender-4.2.0-linux-x64/blender": "binary"})
+	info, err := os.Stat(archive)
+	if err != nil {
+		t.Fatalf("Failed to stat archive: %v", err)
+	}
+	// The URL is never contacted
+	build := model.BlenderBuild{Version: "4.2.0", DownloadURL: "http://127.0.0.1:1/blender-4.2.0-linux-x64.zip", Size: info.Size()}
+
+	// Without a kept archive the install is left alone
+	if _, err := RepairBuild(build, testConfig(dir), nil, make(chan struct{})); !errors.Is(err, ErrNoKeptArchive) {
+		t.Fatalf("Expected ErrNoKeptArchive without a kept archive, got %v", err)
+	}
+	if data, _ := os.ReadFile(filepath.Join(installDir, "blender")); string(data) != "damaged" {
+		t.Errorf("Expected the install to be untouched, got %q", data)
+	}
+
+	if err := os.MkdirAll(filepath.Join(dir, ArchivesDir), 0750); err != nil {
+		t.Fatalf("Failed to create %s: %v", ArchivesDir, err)
+	}
+	if err := os.Rename(archive, KeptArchivePath(build, dir)); err != nil {
+		t.Fatalf("Failed to keep archive: %v", err)
+	}
+	if !HasKeptArchive(build, dir) {
+		t.Fatal("Expected HasKeptArchive to find the kept archive")
+	}
+
+	extractedPath, err := RepairBuild(build, testConfig(dir), nil, make(chan struct{}))
+	if err != nil {
+		t.Fatalf("RepairBuild returned an error: %v", err)
+	}
+	if extractedPath != installDir {
+		t.Errorf("Expected the build repaired in place at %s, got %s", installDir, extractedPath)
+	}
+	if data, _ := os.ReadFile(filepath.Join(installDir, "blender")); string(data) != "binary" {
+		t.Errorf("Expected the repaired file, got %q", data)
+	}
+	if !HasKeptArchive(build, dir) {
+		t.Error("Expected the archive to be kept after repairing")
+	}
+}
+
+func TestReinstallBuild(t *testing.T) {
+	t.Setenv("XDG_STATE_HOME", t.TempDir())
+	dir := t.TempDir()
+	installDir := filepath.Join(dir, "blender-4.2.0-linux-x64")
+	if err := os.MkdirAll(installDir, 0750); err != nil {
+		t.Fatalf("Failed to create install: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(installDir, "blender"), []byte("modified"), 0755); err != nil {
+		t.Fatalf("Failed to write install: %v", err)
+	}
+
+	archive := filepath.Join(t.TempDir(), "blender.zip")
+	writeZip(t, archive, map[string]string{"blender-4.2.0-linux-x64/blender": "binary"})
+	payload, err := os.ReadFile(archive)
+	if err != nil {
+		t.Fatalf("Failed to read archive: %v", err)
+	}
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		http.ServeContent(w, r, "blender.zip", time.Time{}, bytes.NewReader(payload))
+	}))
+	defer server.Close()
+	build := model.BlenderBuild{Version: "4.2.0", DownloadURL: server.URL + "/blender-4.2.0-linux-x64.zip", Size: int64(len(payload))}
+
+	// A kept archive is ignored, the build is fetched again
+	if err := os.MkdirAll(filepath.Join(dir, ArchivesDir), 0750); err != nil {
+		t.Fatalf("Failed to create %s: %v", ArchivesDir, err)
+	}
+	if err := os.WriteFile(KeptArchivePath(build, dir), payload, 0644); err != nil {
+		t.Fatalf("Failed to keep archive: %v", err)
+	}
+
+	if _, err := ReinstallBuild(build, testConfig(dir), nil, nil, make(chan struct{}), nil, nil); err != nil {
+		t.Fatalf("ReinstallBuild returned an error: %v", err)
+	}
+	if requests.Load() == 0 {
+		t.Error("Expected the archive to be downloaded again")
+	}
+	if data, _ := os.ReadFile(filepath.Join(installDir, "blender")); string(data) != "binary" {
+		t.Errorf("Expected the reinstalled file, got %q", data)
+	}
+	backups, _ := filepath.Glob(filepath.Join(dir, OldBuildsDir, "blender-4.2.0-linux-x64_*", "blender"))
+	if len(backups) != 1 {
+		t.Fatalf("Expected the previous install in %s, found %v", OldBuildsDir, backups)
+	}
+	if data, _ := os.ReadFile(backups[0]); string(data) != "modified" {
+		t.Errorf("Expected the backup to keep the modified file, got %q", data)
+	}
+}
+
+func TestReplaceCorruptedBuild(t *testing.T) {
+	t.Setenv("XDG_STATE_HOME", t.TempDir())
+	dir := t.TempDir()
+	installDir := filepath.Join(dir, "blender-4.2.0-linux-x64")
+	if err := os.MkdirAll(installDir, 0750); err != nil {
+		t.Fatalf("Failed to create install: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(installDir, "blender"), []byte("trunc"), 0755); err != nil {
+		t.Fatalf("Failed to write install: %v", err)
+	}
+	// A backup left by an earlier update stays for rolling back
+	earlier := filepath.Join(dir, OldBuildsDir, "blender-4.2.0-linux-x64_20240101_120000")
+	if err := os.MkdirAll(earlier, 0750); err != nil {
+		t.Fatalf("Failed to create backup: %v", err)
+	}
+
+	archive := filepath.Join(t.TempDir(), "blender.zip")
+	writeZip(t, archive, map[string]string{"blender-4.2.0-linux-x64/blender": "binary"})
+	payload, err := os.ReadFile(archive)
+	if err != nil {
+		t.Fatalf("Failed to read archive: %v", err)
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.ServeContent(w, r, "blender.zip", time.Time{}, bytes.NewReader(payload))
+	}))
+	defer server.Close()
+	build := model.BlenderBuild{Version: "4.2.0", DownloadURL: server.URL + "/blender-4.2.0-linux-x64.zip", Size: int64(len(payload))}
+
+	if _, err := ReplaceCorruptedBuild(build, testConfig(dir), nil, nil, make(chan struct{}), nil, nil); err != nil {
+		t.Fatalf("ReplaceCorruptedBuild returned an error: %v", err)
+	}
+	if data, _ := os.ReadFile(filepath.Join(installDir, "blender")); string(data) != "binary" {
+		t.Errorf("Expected the replaced file, got %q", data)
+	}
+	backups, _ := filepath.Glob(filepath.Join(dir, OldBuildsDir, "blender-4.2.0-linux-x64_*"))
+	if len(backups) != 1 || backups[0] != earlier {
+		t.Errorf("Expected only the earlier backup left once the new install verified, found %v", backups)
+	}
+}
+
+func TestReplaceCorruptedBuildInInstallRoot(t *testing.T) {
+	t.Setenv("XDG_STATE_HOME", t.TempDir())
+	dir, root := t.TempDir(), t.TempDir()
+	brokenDir := filepath.Join(root, "blender-4.2.0-linux-x64")
+	if err := os.MkdirAll(brokenDir, 0750); err != nil {
+		t.Fatalf("Failed to create install: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(brokenDir, "blender"), []byte("trunc"), 0755); err != nil {
+		t.Fatalf("Failed to write install: %v", err)
+	}
+
+	archive := filepath.Join(t.TempDir(), "blender.zip")
+	writeZip(t, archive, map[string]string{"blender-4.2.0-linux-x64/blender": "binary"})
+	payload, err := os.ReadFile(archive)
+	if err != nil {
+		t.Fatalf("Failed to read archive: %v", err)
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.ServeContent(w, r, "blender.zip", time.Time{}, bytes.NewReader(payload))
+	}))
+	defer server.Close()
+	build := model.BlenderBuild{Version: "4.2.0", DownloadURL: server.URL + "/blender-4.2.0-linux-x64.zip", Size: int64(len(payload))}
+
+	// The broken install is found in the install root of the config passed in
+	cfg := testConfig(dir)
+	cfg.InstallRoots = []string{root}
+	path, err := ReplaceCorruptedBuild(build, cfg, nil, nil, make(chan struct{}), nil, nil)
+	if err != nil {
+		t.Fatalf("ReplaceCorruptedBuild returned an error: %v", err)
+	}
+	if want := filepath.Join(dir, "blender-4.2.0-linux-x64"); path != want {
+		t.Errorf("Expected the new install at %s, got %s", want, path)
+	}
+	if _, err := os.Stat(brokenDir); !os.IsNotExist(err) {
+		t.Errorf("Expected the broken install to be moved out of %s, got %v", root, err)
+	}
+	if backups, _ := filepath.Glob(filepath.Join(root, OldBuildsDir, "*")); len(backups) != 0 {
+		t.Errorf("Expected the broken install removed once the new one verified, found %v", backups)
+	}
+}
+
+func TestSkipIdenticalBuild(t *testing.T) {
+	t.Setenv("XDG_STATE_HOME", t.TempDir())
+	dir := t.TempDir()
+	installDir := filepath.Join(dir, "blender-4.2.0-linux-x64")
+	if err := os.MkdirAll(installDir, 0750); err != nil {
+		t.Fatalf("Failed to create install: %v", err)
+	}
+	installed := model.BlenderBuild{Version: "4.2.0", Branch: "main", Hash: "0123456789ab",
+		BuildDate: model.Timestamp(time.Date(2025, time.May, 1, 0, 0, 0, 0, time.UTC))}
+	if err := SaveVersionMetadata(installed, installDir); err != nil {
+		t.Fatalf("Failed to write version.json: %v", err)
+	}
+
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer server.Close()
+
+	// The same commit published again later isn't downloaded, its metadata is taken over
+	republished := installed
+	republished.BuildDate = model.Timestamp(time.Date(2025, time.May, 2, 0, 0, 0, 0, time.UTC))
+	republished.DownloadURL = server.URL + "/blender-4.2.0-linux-x64.zip"
+	path, err := DownloadAndExtractBuild(republished, testConfig(dir), nil, nil, make(chan struct{}), nil, nil)
+	if !errors.Is(err, ErrAlreadyInstalled) || path != installDir {
+		t.Fatalf("Expected ErrAlreadyInstalled for %s, got %q, %v", installDir, path, err)
+	}
+	if requests.Load() != 0 {
+		t.Error("Expected nothing to be downloaded")
+	}
+	data, _ := os.ReadFile(filepath.Join(installDir, versionMetaFilename))
+	var meta model.BlenderBuild
+	if err := json.Unmarshal(data, &meta); err != nil || !meta.BuildDate.Time().Equal(republished.BuildDate.Time()) {
+		t.Errorf("Expected the new build date in version.json, got %s", data)
+	}
+
+	// Another commit is downloaded
+	update := republished
+	update.Hash = "fedcba987654"
+	if _, err := DownloadAndExtractBuild(update, testConfig(dir), nil, nil, make(chan struct{}), nil, nil); errors.Is(err, ErrAlreadyInstalled) {
+		t.Error("Expected a different commit to be downloaded")
+	}
+	if requests.Load() == 0 {
+		t.Error("Expected the update to be requested")
+	}
+}
+
+func TestDeltaWriteStream(t *testing.T) {
+	const oldContent = "the quick brown fox"
+
+	tests := []struct {
+		name       string
+		newContent string
+		handled    bool // Compared against the old file instead of left to the caller
+		linked     bool // Reused the old file instead of writing a new one
+	}{
+		{"unchanged file is linked", oldContent, true, true},
+		{"change after the first chunk", "the quick brown cat", true, false},
+		{"change in the first chunk", "The quick brown fox", true, false},
+		{"different size is left to the caller", "a slow fox", false, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			oldRoot := filepath.Join(dir, "blender-4.2.0-old")
+			if err := os.MkdirAll(filepath.Join(oldRoot, "lib"), 0750); err != nil {
+				t.Fatalf("Failed to create old install: %v", err)
+			}
+			oldPath := filepath.Join(oldRoot, "lib", "file.so")
+			if err := os.WriteFile(oldPath, []byte(oldContent), 0644); err != nil {
+				t.Fatalf("Failed to write old file: %v", err)
+			}
+			if err := os.Chmod(oldPath, 0644); err != nil {
+				t.Fatalf("Failed to chmod old file: %v", err)
+			}
+
+			delta := newDeltaSource(oldRoot)
+			targetPath := filepath.Join(dir, "file.so")
+
+			// A tiny buffer makes the comparison span several chunks
+			handled, err := delta.writeStream(targetPath, "blender-4.2.1-new/lib/file.so",
+				bytes.NewReader([]byte(tt.newContent)), int64(len(tt.newContent)), 0644, make([]byte, 4))
+			if err != nil {
+				t.Fatalf("writeStream returned an error: %v", err)
+			}
+			if handled != tt.handled {
+				t.Fatalf("writeStream handled = %v, want %v", handled, tt.handled)
+			}
+			if !handled {
+				return
+			}
+
+			data, err := os.ReadFile(targetPath)
+			if err != nil {
+				t.Fatalf("Failed to read written file: %v", err)
+			}
+			if string(data) != tt.newContent {
+				t.Errorf("Written file = %q, want %q", data, tt.newContent)
+			}
+
+			oldInfo, _ := os.Stat(oldPath)
+			newInfo, _ := os.Stat(targetPath)
+			if got := os.SameFile(oldInfo, newInfo); got != tt.linked {
+				t.Errorf("Linked to the old file = %v, want %v", got, tt.linked)
+			}
+		})
+	}
+}
+
+func TestTorrentWorkDir(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]int{
+		"blender-4.2.0-linux-x64.tar.xz":        3000,
+		"blender-4.2.0-linux-x64.tar.xz.aria2":  50, // Client resume state
+		"extras/blender-4.2.0-linux-x64.sha256": 100,
+	}
+	for name, size := range files {
+		path := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0750); err != nil {
+			t.Fatalf("Failed to create dir for %s: %v", name, err)
+		}
+		if err := os.WriteFile(path, make([]byte, size), 0644); err != nil {
+			t.Fatalf("Failed to write %s: %v", name, err)
+		}
+	}
+
+	// A partial download counts the data but not the control file
+	if got := torrentBytes(dir); got != 3100 {
+		t.Errorf("torrentBytes() = %d, want 3100", got)
+	}
+
+	archive, err := largestFile(dir)
+	if err != nil {
+		t.Fatalf("largestFile returned an error: %v", err)
+	}
+	if filepath.Base(archive) != "blender-4.2.0-linux-x64.tar.xz" {
+		t.Errorf("largestFile() = %s, want the archive", archive)
+	}
+
+	if _, err := largestFile(t.TempDir()); err == nil {
+		t.Error("Expected an error for an empty torrent directory")
+	}
+}
+
+func TestTorrentClient(t *testing.T) {
+	if runtime.GOOS != "linux" {
+		t.Skip("The fake torrent client is a shell script")
+	}
+	// The client reports a piece verified before, then gets nothing more
+	bin := t.TempDir()
+	args := filepath.Join(t.TempDir(), "args")
+	script := `#!/bin/sh
+echo "$@" > ` + args + `
+printf '[#2089b0 1.5MiB/4.0MiB(37%%) CN:0 SD:0 DL:0B]\r'
+exec sleep 10
+`
+	if err := os.WriteFile(filepath.Join(bin, torrentClient), []byte(script), 0755); err != nil {
+		t.Fatalf("Failed to write the fake client: %v", err)
+	}
+	t.Setenv("PATH", bin+string(os.PathListSeparator)+os.Getenv("PATH"))
+	timeout := torrentStallTimeout
+	torrentStallTimeout = 500 * time.Millisecond
+	defer func() { torrentStallTimeout = timeout }()
+
+	var completed atomic.Int64
+	progressCb := func(done, total int64) { completed.Store(done) }
+	_, err := runTorrentClient("http://example.com/blender.tar.xz.torrent", t.TempDir(), 4<<20, 2<<20, progressCb, make(chan struct{}), NewPauseGate())
+	if !errors.Is(err, errTorrentStalled) {
+		t.Errorf("Expected the stalled torrent given up, got %v", err)
+	}
+	if got := completed.Load(); got != 3<<19 {
+		t.Errorf("Progress = %d, want the %d bytes verified before", got, 3<<19)
+	}
+	if data, err := os.ReadFile(args); err != nil || !strings.Contains(string(data), "--max-overall-download-limit=2097152") {
+		t.Errorf("Expected the rate limit passed to the client, got %q, %v", data, err)
+	}
+}
+
+func TestClassify(t *testing.T) {
+	var (
+		netErr        *NetworkError
+		diskErr       *DiskFullError
+		checksumErr   *ChecksumError
+		extractionErr *ExtractionError
+		cancelledErr  *CancelledError
+	)
+
+	tests := []struct {
+		name   string
+		err    error
+		target any
+		is     error // Sentinel the classified error must still match
+	}{
+		{"server error", fmt.Errorf("download failed: %w", &StatusError{Code: 503}), &netErr, ErrUnexpectedStatus},
+		{"idle connection", ErrIdleTimeout, &netErr, ErrIdleTimeout},
+		{"space check", fmt.Errorf("%w in /tmp", ErrInsufficientSpace), &diskErr, ErrInsufficientSpace},
+		{"disk full while extracting", &ExtractionError{Err: fmt.Errorf("write: %w", syscall.ENOSPC)}, &diskErr, ErrInsufficientSpace},
+		{"size mismatch", fmt.Errorf("%w: short archive", ErrVerificationFailed), &checksumErr, ErrVerificationFailed},
+		{"extraction", &ExtractionError{Err: os.ErrPermission}, &extractionErr, os.ErrPermission},
+		{"cancelled", fmt.Errorf("download failed: %w", ErrCancelled), &cancelledErr, ErrCancelled},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Classify(tt.err)
+			if !errors.As(got, tt.target) {
+				t.Errorf("Classify(%v) = %T, not the expected category", tt.err, got)
+			}
+			if !errors.Is(got, tt.is) {
+				t.Errorf("Classify(%v) no longer matches %v", tt.err, tt.is)
+			}
+		})
+	}
+
+	if Classify(nil) != nil {
+		t.Error("Classify(nil) should be nil")
+	}
+}
+
+func TestSelectMirror(t *testing.T) {
+	t.Setenv("XDG_STATE_HOME", t.TempDir())
+	payload := bytes.Repeat([]byte("0123456789"), mirrorProbeSize/5)
+
+	var probes atomic.Int32
+	serve := func(delay time.Duration) *httptest.Server {
+		return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			probes.Add(1)
+			time.Sleep(delay)
+			http.ServeContent(w, r, "blender.zip", time.Now(), bytes.NewReader(payload))
+		}))
+	}
+	builder := serve(200 * time.Millisecond)
+	defer builder.Close()
+	fast := serve(0)
+	defer fast.Close()
+	broken := httptest.NewServer(http.NotFoundHandler())
+	defer broken.Close()
+
+	archive := builder.URL + "/release/blender.zip"
+	mirrors := []string{fast.URL + "/blender/", broken.URL, "not a url"}
+
+	// The fastest mirror wins, with the archive's path below its base URL
+	want := fast.URL + "/blender/release/blender.zip"
+	if got := selectMirror(archive, mirrors, fetchOptions{}); got != want {
+		t.Fatalf("selectMirror() = %s, want %s", got, want)
+	}
+	stats := loadMirrorStats()
+	if len(stats) != 3 {
+		t.Fatalf("Expected stats for 3 hosts, got %v", stats)
+	}
+	if stats[mirrorHost(broken.URL)].Speed != 0 {
+		t.Errorf("Expected the broken mirror to have no speed, got %v", stats[mirrorHost(broken.URL)])
+	}
+
+	// Recent measurements are reused without probing
+	probes.Store(0)
+	if got := selectMirror(archive, mirrors, fetchOptions{}); got != want {
+		t.Errorf("selectMirror() with remembered stats = %s, want %s", got, want)
+	}
+	if n := probes.Load(); n != 0 {
+		t.Errorf("Expected no probes with fresh stats, got %d", n)
+	}
+
+	// A failed download demotes the mirror
+	recordMirrorSpeeds([]string{want}, []float64{0})
+	if got := selectMirror(archive, mirrors, fetchOptions{}); got != archive {
+		t.Errorf("selectMirror() after a mirror failure = %s, want the builder", got)
+	}
+
+	// Without mirrors nothing is probed
+	if got := selectMirror(archive, nil, fetchOptions{}); got != archive {
+		t.Errorf("selectMirror() without mirrors = %s, want %s", got, archive)
+	}
+}
+
+func TestVerifyMirrorDownload(t *testing.T) {
+	payload := []byte("the builder's archive")
+	digest := sha256.Sum256(payload)
+	builder := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/daily/blender-4.5.0-linux.sha256" {
+			http.NotFound(w, r)
+			return
+		}
+		fmt.Fprintf(w, "%x  blender-4.5.0-linux.tar.xz\n", digest)
+	}))
+	defer builder.Close()
+
+	tests := []struct {
+		name    string
+		archive string
+		content []byte
+		wantErr bool
+	}{
+		{"Same as the builder's", "/daily/blender-4.5.0-linux.tar.xz", payload, false},
+		{"Modified by the mirror", "/daily/blender-4.5.0-linux.tar.xz", []byte("something else"), true},
+		{"No published checksum", "/daily/blender-4.4.0-linux.tar.xz", payload, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "blender.tar.xz")
+			if err := os.WriteFile(path, tt.content, 0644); err != nil {
+				t.Fatalf("Failed to write archive: %v", err)
+			}
+			err := verifyMirrorDownload(builder.URL+tt.archive, path, fetchOptions{})
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("verifyMirrorDownload() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if _, statErr := os.Stat(path); (statErr == nil) == tt.wantErr {
+				t.Errorf("Expected an unverified archive to be removed and a verified one kept, stat: %v", statErr)
+			}
+		})
+	}
+}
+
+func TestExtractors(t *testing.T) {
+	dir := t.TempDir()
+	archive := filepath.Join(dir, "blender.zip")
+	writeZip(t, archive, map[string]string{
+		"blender-4.2.0-windows-x64/blender.exe":         "exe",
+		"blender-4.2.0-windows-x64/4.2/scripts/init.py": "import bpy",
+	})
+
+	// Tools that aren't installed or can't read the format fall back to the builtin extractor
+	tests := []struct {
+		name    string
+		archive string
+		delta   *deltaSource
+		builtin bool
+	}{
+		{ExtractorBuiltin, "blender.zip", nil, true},
+		{"", "blender.tar.xz", nil, true},
+		{ExtractorBsdtar, "blender.dmg", nil, true},
+		{ExtractorBsdtar, "blender.zip", newDeltaSource(dir), true}, // Only the builtin one links unchanged files
+	}
+	for _, tt := range tests {
+		if _, ok := newExtractor(tt.name, tt.archive, tt.delta).(builtinExtractor); ok != tt.builtin {
+			t.Errorf("newExtractor(%q, %q) builtin = %v, want %v", tt.name, tt.archive, ok, tt.builtin)
+		}
+	}
+
+	extractors := map[string]Extractor{ExtractorBuiltin: builtinExtractor{}}
+	if path, err := exec.LookPath("bsdtar"); err == nil {
+		extractors[ExtractorBsdtar] = bsdtarExtractor{path: path}
+	}
+	// A tool that fails leaves the archive to the builtin extractor
+	if path, err := exec.LookPath("false"); err == nil {
+		extractors["failing tool"] = fallbackExtractor{tool: bsdtarExtractor{path: path}}
+	}
+	for name, extractor := range extractors {
+		t.Run(name, func(t *testing.T) {
+			destDir := filepath.Join(t.TempDir(), "out")
+			if err := os.MkdirAll(destDir, 0750); err != nil {
+				t.Fatalf("Failed to create destination: %v", err)
+			}
+
+			var lastProgress float64
+			progressCb := func(progress float64) {
+				lastProgress = progress
+			}
+			if err := extractor.Extract(archive, destDir, progressCb, make(chan struct{})); err != nil {
+				t.Fatalf("Extract returned an error: %v", err)
+			}
+			if lastProgress != 1.0 {
+				t.Errorf("Expected final progress 1.0, got %f", lastProgress)
+			}
+
+			rootDir, err := singleRootDir(destDir)
+			if err != nil || rootDir != "blender-4.2.0-windows-x64" {
+				t.Fatalf("singleRootDir() = %q, %v", rootDir, err)
+			}
+			data, err := os.ReadFile(filepath.Join(destDir, rootDir, "4.2", "scripts", "init.py"))
+			if err != nil || string(data) != "import bpy" {
+				t.Errorf("Extracted file has wrong content: %q, %v", data, err)
+			}
+		})
+	}
+}
+
+func TestJournal(t *testing.T) {
+	t.Setenv("XDG_STATE_HOME", t.TempDir())
+
+	archive := filepath.Join(t.TempDir(), "blender.zip")
+	if err := os.WriteFile(archive, []byte("archive"), 0644); err != nil {
+		t.Fatalf("Failed to write archive: %v", err)
+	}
+	build := model.BlenderBuild{Version: "4.2.0", Hash: "0123456789ab", DownloadURL: "https://example.org/blender.zip"}
+
+	// Each outcome is journaled with the archive's digest
+	results := []struct {
+		err  error
+		want string
+	}{
+		{nil, JournalInstalled},
+		{fmt.Errorf("%w: expected 10 bytes", ErrVerificationFailed), JournalVerificationFailed},
+		{&ExtractionError{Err: errors.New("permission denied")}, JournalExtractionFailed},
+		{ErrCancelled, JournalCancelled},
+		{errors.New("metadata save failed"), JournalFailed},
+	}
+	for _, r := range results {
+		entry, err := newJournalEntry(build, archive)
+		if err != nil {
+			t.Fatalf("newJournalEntry returned an error: %v", err)
+		}
+		entry.Result = journalResult(r.err)
+		if err := AppendJournal(entry); err != nil {
+			t.Fatalf("AppendJournal returned an error: %v", err)
+		}
+	}
+
+	entries, err := ReadJournal()
+	if err != nil {
+		t.Fatalf("ReadJournal returned an error: %v", err)
+	}
+	if len(entries) != len(results) {
+		t.Fatalf("Expected %d entries, got %d", len(results), len(entries))
+	}
+	const digest = "0eb3e36bfb24dcd9bb1d1bece1531216b59539a8fde17ee80224af0653c92aa3" // sha256("archive")
+	for i, entry := range entries {
+		if entry.Result != results[i].want {
+			t.Errorf("Entry %d result = %q, want %q", i, entry.Result, results[i].want)
+		}
+		if entry.Version != "4.2.0" || entry.URL != build.DownloadURL || entry.Size != 7 || entry.Time.IsZero() {
+			t.Errorf("Entry %d doesn't describe the archive: %+v", i, entry)
+		}
+		if entry.SHA256 != digest {
+			t.Errorf("Entry %d digest = %q, want %q", i, entry.SHA256, digest)
+		}
+	}
+}
+
+func TestFindInterrupted(t *testing.T) {
+	baseDir := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(baseDir, DownloadingDir), 0750); err != nil {
+		t.Fatalf("Failed to create %s: %v", DownloadingDir, err)
+	}
+	build := func(version string) model.BlenderBuild {
+		return model.BlenderBuild{Version: version, Hash: "0123456789ab", Size: 1000,
+			DownloadURL: "https://example.org/blender-" + version + ".zip"}
+	}
+	single, segmented, stale := build("4.2.0"), build("4.3.0"), build("4.4.0")
+	for _, b := range []model.BlenderBuild{single, segmented, stale} {
+		if err := saveBuildInfo(b, baseDir); err != nil {
+			t.Fatalf("saveBuildInfo returned an error: %v", err)
+		}
+	}
+
+	// One download stopped as a single file, one in segments, one left nothing behind
+	archive := ArchivePath(segmented, baseDir)
+	files := map[string]int{
+		ArchivePath(single, baseDir): 400,
+		segmentPath(archive, 0, 2):   300,
+		segmentPath(archive, 1, 2):   200,
+	}
+	for path, size := range files {
+		if err := os.WriteFile(path, make([]byte, size), 0644); err != nil {
+			t.Fatalf("Failed to write %s: %v", path, err)
+		}
+	}
+
+	interrupted, err := FindInterrupted(baseDir)
+	if err != nil {
+		t.Fatalf("FindInterrupted returned an error: %v", err)
+	}
+	got := make(map[string]int64)
+	for _, d := range interrupted {
+		got[d.Build.Version] = d.Downloaded
+	}
+	want := map[string]int64{"4.2.0": 400, "4.3.0": 500}
+	if fmt.Sprint(got) != fmt.Sprint(want) {
+		t.Errorf("FindInterrupted() = %v, want %v", got, want)
+	}
+	if _, err := os.Stat(buildInfoPath(stale, baseDir)); !os.IsNotExist(err) {
+		t.Error("Expected the stale description to be removed")
+	}
+
+	// Discarding removes the segments and the description
+	if err := DiscardPartial(segmented, baseDir); err != nil {
+		t.Fatalf("DiscardPartial returned an error: %v", err)
+	}
+	if size := PartialSize(segmented, baseDir); size != 0 {
+		t.Errorf("Expected no partial data after discarding, got %d bytes", size)
+	}
+	if _, err := os.Stat(buildInfoPath(segmented, baseDir)); !os.IsNotExist(err) {
+		t.Error("Expected the description to be discarded")
+	}
+}
+
+func TestFindStaleFiles(t *testing.T) {
+	baseDir := t.TempDir()
+	dir := filepath.Join(baseDir, DownloadingDir)
+	resumable := model.BlenderBuild{Version: "4.2.0", Size: 1000, DownloadURL: "https://example.org/blender-4.2.0.zip"}
+
+	// Files of a described download stay, everything else is stale
+	files := []string{
+		"blender-4.2.0.zip.part0-2",
+		"blender-4.2.0.zip.part1-2",
+		"blender-4.1.0.tar.xz", // From before downloads were described
+		"blender-4.1.0.tar.xz.bt/blender.tar.xz",
+		"4.0.0-tmp/blender-4.0.0/blender", // Crashed extraction
+		"blender-4.3.0.partial-release.zip.build",
+	}
+	for _, name := range files {
+		path := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0750); err != nil {
+			t.Fatalf("Failed to create dir for %s: %v", name, err)
+		}
+		if err := os.WriteFile(path, make([]byte, 100), 0644); err != nil {
+			t.Fatalf("Failed to write %s: %v", name, err)
+		}
+	}
+	if err := saveBuildInfo(resumable, baseDir); err != nil {
+		t.Fatalf("saveBuildInfo returned an error: %v", err)
+	}
+
+	paths, size, err := FindStaleFiles(baseDir)
+	if err != nil {
+		t.Fatalf("FindStaleFiles returned an error: %v", err)
+	}
+	var names []string
+	for _, path := range paths {
+		names = append(names, filepath.Base(path))
+	}
+	want := []string{"4.0.0-tmp", "blender-4.1.0.tar.xz", "blender-4.1.0.tar.xz.bt", "blender-4.3.0.partial-release.zip.build"}
+	if fmt.Sprint(names) != fmt.Sprint(want) {
+		t.Errorf("FindStaleFiles() = %v, want %v", names, want)
+	}
+	if size != 400 {
+		t.Errorf("Expected 400 stale bytes, got %d", size)
+	}
+	for _, name := range []string{"blender-4.2.0.zip.part0-2", "4.0.0-tmp"} {
+		if got, want := Resumable(filepath.Join(dir, name)), name != "4.0.0-tmp"; got != want {
+			t.Errorf("Resumable(%s) = %v, want %v", name, got, want)
+		}
+	}
+
+	if err := RemoveStaleFiles(paths); err != nil {
+		t.Fatalf("RemoveStaleFiles returned an error: %v", err)
+	}
+	if got := PartialSize(resumable, baseDir); got != 200 {
+		t.Errorf("Expected the resumable download to keep its 200 bytes, got %d", got)
+	}
+}
+
+func TestIgnoreRules(t *testing.T) {
+	baseDir := t.TempDir()
+	rules := "# Not part of the library\ntextures/\n\n/cache-*\ntools/*/bin\n[invalid\n"
+	if err := os.WriteFile(filepath.Join(baseDir, IgnoreFilename), []byte(rules), 0644); err != nil {
+		t.Fatalf("Failed to write %s: %v", IgnoreFilename, err)
+	}
+	ignore := LoadIgnore(baseDir)
+
+	tests := []struct {
+		path string
+		want bool
+	}{
+		{"textures", true},              // Trailing slash is dropped
+		{"assets/textures", true},       // Names match at any depth
+		{"cache-blender", true},         // Anchored pattern at the root
+		{"assets/cache-blender", false}, // Anchored pattern below the root
+		{"tools/houdini/bin", true},     // Patterns with a slash match the path
+		{"bin", false},
+		{"blender-4.2.0-linux-x64", false},
+		{"[invalid", false}, // Invalid patterns are skipped
+	}
+	for _, tt := range tests {
+		if got := ignore.Ignored(filepath.FromSlash(tt.path)); got != tt.want {
+			t.Errorf("Ignored(%q) = %v, want %v", tt.path, got, tt.want)
+		}
+	}
+
+	// Without the file nothing is ignored
+	if LoadIgnore(t.TempDir()).Ignored("textures") {
+		t.Error("Expected nothing to be ignored without an ignore file")
+	}
+}
+
+func TestNotify(t *testing.T) {
+	build := model.BlenderBuild{Version: "4.2.0", Hash: "0123456789abcdef", DownloadURL: "https://example.org/blender-4.2.0.zip", Size: 1000}
+
+	var received Notification
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := json.NewDecoder(r.Body).Decode(&received); err != nil || r.Method != http.MethodPost {
+			w.WriteHeader(http.StatusBadRequest)
+		}
+	}))
+	defer server.Close()
+
+	// The command gets the same JSON on stdin
+	out := filepath.Join(t.TempDir(), "notification.json")
+	command := "cat > " + out
+	if _, err := exec.LookPath("sh"); err != nil {
+		command = ""
+	}
+
+	if err := Notify(server.URL, command, NewNotification(build, "/builds/blender-4.2.0", nil)); err != nil {
+		t.Fatalf("Notify returned an error: %v", err)
+	}
+	if received.Event != NotifyCompleted || received.Version != "4.2.0" || received.Path != "/builds/blender-4.2.0" {
+		t.Errorf("Webhook received %+v", received)
+	}
+	if command != "" {
+		data, err := os.ReadFile(out)
+		if err != nil {
+			t.Fatalf("Notify command didn't run: %v", err)
+		}
+		var fromCommand Notification
+		if err := json.Unmarshal(data, &fromCommand); err != nil || fromCommand.Hash != build.Hash {
+			t.Errorf("Notify command received %s", data)
+		}
+	}
+
+	// Failures carry the error instead of a path
+	if err := Notify(server.URL, "", NewNotification(build, "", ErrVerificationFailed)); err != nil {
+		t.Fatalf("Notify returned an error: %v", err)
+	}
+	if received.Event != NotifyFailed || received.Error != ErrVerificationFailed.Error() {
+		t.Errorf("Expected a failure notification, got %+v", received)
+	}
+
+	// A rejecting receiver is reported
+	failing := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer failing.Close()
+	if err := Notify(failing.URL, "", NewNotification(build, "", nil)); !errors.Is(err, ErrUnexpectedStatus) {
+		t.Errorf("Expected ErrUnexpectedStatus from a failing webhook, got %v", err)
+	}
+}
+
+func TestRunPostInstallHook(t *testing.T) {
+	if _, err := exec.LookPath("sh"); err != nil || runtime.GOOS == "windows" {
+		t.Skip("the hook command uses sh syntax")
+	}
+	build := model.BlenderBuild{Version: "4.2.0", Branch: "main", Hash: "0123456789abcdef"}
+	installDir := t.TempDir()
+
+	// The hook runs inside the build with it described in the environment
+	command := `printf '%s|%s|%s|%s' "$BLENDER_VERSION" "$BLENDER_PATH" "$BLENDER_HASH" "$PWD" > hook.txt`
+	if err := RunPostInstallHook(command, build, installDir); err != nil {
+		t.Fatalf("RunPostInstallHook returned an error: %v", err)
+	}
+	data, err := os.ReadFile(filepath.Join(installDir, "hook.txt"))
+	if err != nil {
+		t.Fatalf("Hook didn't run: %v", err)
+	}
+	resolved, _ := filepath.EvalSymlinks(installDir)
+	if got := strings.Split(string(data), "|"); len(got) != 4 || got[0] != "4.2.0" || got[1] != installDir || got[2] != build.Hash ||
+		(got[3] != installDir && got[3] != resolved) {
+		t.Errorf("Hook saw %q", data)
+	}
+
+	// A failing hook is reported with what it wrote to stderr
+	err = RunPostInstallHook("echo no addons here >&2; exit 3", build, installDir)
+	if err == nil || !strings.Contains(err.Error(), "no addons here") {
+		t.Errorf("Expected the hook's failure with its stderr, got %v", err)
+	}
+
+	// No hook configured
+	if err := RunPostInstallHook("", build, installDir); err != nil {
+		t.Errorf("Expected no error without a hook, got %v", err)
+	}
+}
+
+func TestInstallAddons(t *testing.T) {
+	installDir := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(installDir, "4.5", "scripts", "addons_core"), 0750); err != nil {
+		t.Fatalf("Failed to create the bundled scripts: %v", err)
+	}
+
+	sources := t.TempDir()
+	writeZip(t, filepath.Join(sources, "archived.zip"), map[string]string{"archived/__init__.py": "# zip"})
+	for name, content := range map[string]string{
+		"module/__init__.py":       "# dir",
+		"module/__pycache__/x.pyc": "stale",
+		"single.py":                "# file",
+		"notes.txt":                "not an add-on",
+	} {
+		path := filepath.Join(sources, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0750); err != nil {
+			t.Fatalf("Failed to create %s: %v", filepath.Dir(path), err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatalf("Failed to write %s: %v", path, err)
+		}
+	}
+
+	addons := []string{
+		filepath.Join(sources, "archived.zip"),
+		filepath.Join(sources, "module"),
+		filepath.Join(sources, "single.py"),
+		filepath.Join(sources, "notes.txt"),
+		filepath.Join(sources, "missing.zip"),
+	}
+	err := InstallAddons(addons, installDir)
+	if err == nil || !strings.Contains(err.Error(), "notes.txt") || !strings.Contains(err.Error(), "missing.zip") {
+		t.Errorf("Expected the failing add-ons reported, got %v", err)
+	}
+
+	addonsDir := filepath.Join(installDir, "4.5", "scripts", "addons")
+	for _, name := range []string{"archived/__init__.py", "module/__init__.py", "single.py"} {
+		if _, err := os.Stat(filepath.Join(addonsDir, filepath.FromSlash(name))); err != nil {
+			t.Errorf("Expected %s installed: %v", name, err)
+		}
+	}
+	if _, err := os.Stat(filepath.Join(addonsDir, "module", "__pycache__")); !os.IsNotExist(err) {
+		t.Errorf("Expected compiled caches left behind, got %v", err)
+	}
+
+	// Nothing configured, or no build to install into
+	if err := InstallAddons(nil, t.TempDir()); err != nil {
+		t.Errorf("Expected no error without add-ons, got %v", err)
+	}
+	if err := InstallAddons(addons[:1], t.TempDir()); err == nil {
+		t.Error("Expected an error without a bundled scripts directory")
+	}
+}
+
+// testConfig returns the default config with dir as its download directory.
+func testConfig(dir string) config.Config {
+	cfg := config.DefaultConfig()
+	cfg.DownloadDir = dir
+	return cfg
+}
@@ -0,0 +1,98 @@
+package download
+
+import (
+	"errors"
+	"io"
+	"net"
+	"net/url"
+	"syscall"
+)
+
+// Errors returned by DownloadAndExtractBuild are classified into one of the
+// types below, so callers can react to the kind of failure with errors.As
+// instead of matching messages. Each type wraps the original error and still
+// matches the package's sentinel errors with errors.Is.
+
+// NetworkError reports a failure talking to the server: unreachable host,
+// dropped or idle connection, or an error status.
+type NetworkError struct {
+	Err error
+}
+
+func (e *NetworkError) Error() string { return e.Err.Error() }
+func (e *NetworkError) Unwrap() error { return e.Err }
+
+// DiskFullError reports that the download directory ran out of space, either
+// detected up front or while writing. It matches ErrInsufficientSpace.
+type DiskFullError struct {
+	Err error
+}
+
+func (e *DiskFullError) Error() string { return e.Err.Error() }
+func (e *DiskFullError) Unwrap() error { return e.Err }
+
+// Is makes errors.Is(err, ErrInsufficientSpace) hold for running out of space while writing.
+func (e *DiskFullError) Is(target error) bool { return target == ErrInsufficientSpace }
+
+// ChecksumError reports an archive that is incomplete or corrupt. The archive
+// is discarded, so downloading again starts from scratch.
+type ChecksumError struct {
+	Err error
+}
+
+func (e *ChecksumError) Error() string { return e.Err.Error() }
+func (e *ChecksumError) Unwrap() error { return e.Err }
+
+// ExtractionError reports a failure unpacking an archive that was downloaded
+// and verified, such as a permission problem in the download directory.
+type ExtractionError struct {
+	Err error
+}
+
+func (e *ExtractionError) Error() string { return "extraction failed: " + e.Err.Error() }
+func (e *ExtractionError) Unwrap() error { return e.Err }
+
+// CancelledError reports a download stopped by the user. Its partial archive is
+// kept for resuming. It matches ErrCancelled.
+type CancelledError struct{}
+
+func (e *CancelledError) Error() string        { return ErrCancelled.Error() }
+func (e *CancelledError) Is(target error) bool { return target == ErrCancelled }
+
+// Classify wraps err in the type describing its kind of failure. Errors that
+// fit none of the categories, and nil, are returned unchanged.
+func Classify(err error) error {
+	var (
+		netErr        *NetworkError
+		diskErr       *DiskFullError
+		checksumErr   *ChecksumError
+		extractionErr *ExtractionError
+		cancelledErr  *CancelledError
+		statusErr     *StatusError
+		urlErr        *url.Error
+		opErr         *net.OpError
+	)
+
+	switch {
+	case err == nil:
+		return nil
+	case errors.As(err, &cancelledErr), errors.As(err, &diskErr), errors.As(err, &checksumErr), errors.As(err, &netErr):
+		return err
+	case errors.Is(err, ErrCancelled):
+		return &CancelledError{}
+	case errors.Is(err, ErrInsufficientSpace), errors.Is(err, syscall.ENOSPC):
+		return &DiskFullError{Err: err}
+	case errors.Is(err, ErrVerificationFailed):
+		return &ChecksumError{Err: err}
+	case errors.As(err, &extractionErr):
+		return err
+	case errors.As(err, &statusErr),
+		errors.Is(err, ErrIdleTimeout),
+		errors.Is(err, io.ErrUnexpectedEOF),
+		errors.As(err, &urlErr),
+		errors.As(err, &opErr):
+		return &NetworkError{Err: err}
+	default:
+		return err
+	}
+}
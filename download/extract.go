@@ -0,0 +1,273 @@
+package download
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// Extraction backends, selected with the extractor config setting
+const (
+	ExtractorBuiltin = "builtin" // Extracts in Go, needed for delta updates
+	ExtractorBsdtar  = "bsdtar"  // libarchive's tar, fast and reads most formats
+	Extractor7z      = "7z"      // 7-Zip, fast on large zip archives
+)
+
+// extractPollInterval is how often an external extractor's output is measured
+// for progress, walking the extracted tree is too costly for every update.
+const extractPollInterval = 500 * time.Millisecond
+
+// Extractor unpacks archives into a directory, reporting progress as the
+// fraction of the uncompressed data extracted so far.
+type Extractor interface {
+	// Supports reports whether the backend can extract the named archive.
+	Supports(archiveName string) bool
+	// Extract unpacks archivePath into destDir until done or cancelled.
+	Extract(archivePath, destDir string, progressCb ExtractionProgressCallback, cancelCh <-chan struct{}) error
+}
+
+// newExtractor returns the backend named in the config for an archive. The
+// builtin one is used when the tool isn't installed or can't handle the
+// format, for delta updates, which only it can do, and when the tool fails
+// on an archive the builtin one reads too.
+func newExtractor(name, archiveName string, delta *deltaSource) Extractor {
+	builtin := builtinExtractor{delta: delta}
+	if delta != nil {
+		return builtin
+	}
+
+	var tool Extractor
+	switch name {
+	case ExtractorBsdtar:
+		if path, err := exec.LookPath("bsdtar"); err == nil {
+			tool = bsdtarExtractor{path: path}
+		}
+	case Extractor7z:
+		for _, candidate := range []string{"7z", "7zz"} {
+			if path, err := exec.LookPath(candidate); err == nil {
+				tool = sevenZipExtractor{path: path}
+				break
+			}
+		}
+	}
+	switch {
+	case tool == nil || !tool.Supports(archiveName):
+		return builtin
+	case builtin.Supports(archiveName):
+		return fallbackExtractor{tool: tool, builtin: builtin}
+	}
+	return tool
+}
+
+// fallbackExtractor extracts with an external tool, and over again with the
+// builtin extractor if the tool fails.
+type fallbackExtractor struct {
+	tool    Extractor
+	builtin builtinExtractor
+}
+
+func (e fallbackExtractor) Supports(archiveName string) bool {
+	return e.tool.Supports(archiveName)
+}
+
+func (e fallbackExtractor) Extract(archivePath, destDir string, progressCb ExtractionProgressCallback, cancelCh <-chan struct{}) error {
+	toolErr := e.tool.Extract(archivePath, destDir, progressCb, cancelCh)
+	if toolErr == nil || errors.Is(toolErr, ErrCancelled) {
+		return toolErr
+	}
+	// Whatever the tool extracted before failing goes
+	if err := os.RemoveAll(destDir); err != nil {
+		return fmt.Errorf("%w, and clearing its output failed: %w", toolErr, err)
+	}
+	if err := os.MkdirAll(destDir, 0750); err != nil {
+		return fmt.Errorf("%w, and clearing its output failed: %w", toolErr, err)
+	}
+	if err := e.builtin.Extract(archivePath, destDir, progressCb, cancelCh); err != nil {
+		return fmt.Errorf("%w, and so did the builtin extractor: %w", toolErr, err)
+	}
+	return nil
+}
+
+// builtinExtractor extracts .tar.xz and .zip archives in Go. With a delta
+// source, unchanged files are linked from the build being replaced.
+type builtinExtractor struct {
+	delta *deltaSource
+}
+
+func (e builtinExtractor) Supports(archiveName string) bool {
+	return strings.HasSuffix(archiveName, ".tar.xz") || strings.HasSuffix(archiveName, ".zip")
+}
+
+func (e builtinExtractor) Extract(archivePath, destDir string, progressCb ExtractionProgressCallback, cancelCh <-chan struct{}) error {
+	if strings.HasSuffix(archivePath, ".zip") {
+		return extractZip(archivePath, destDir, progressCb, cancelCh, e.delta)
+	}
+	return extractTarXz(archivePath, destDir, progressCb, cancelCh, e.delta)
+}
+
+// bsdtarExtractor extracts archives with bsdtar, which detects the format itself.
+type bsdtarExtractor struct {
+	path string
+}
+
+func (e bsdtarExtractor) Supports(archiveName string) bool {
+	for _, suffix := range []string{".tar.xz", ".zip", ".tar.gz", ".tar.bz2", ".tar.zst", ".7z"} {
+		if strings.HasSuffix(archiveName, suffix) {
+			return true
+		}
+	}
+	return false
+}
+
+func (e bsdtarExtractor) Extract(archivePath, destDir string, progressCb ExtractionProgressCallback, cancelCh <-chan struct{}) error {
+	return runExtractTool(archivePath, destDir, progressCb, cancelCh, func(ctx context.Context) []*exec.Cmd {
+		return []*exec.Cmd{exec.CommandContext(ctx, e.path, "-x", "-f", archivePath, "-C", destDir)}
+	})
+}
+
+// sevenZipExtractor extracts archives with 7-Zip. Tarballs take two passes
+// through a pipe, one unpacking the compression and one the tar.
+type sevenZipExtractor struct {
+	path string
+}
+
+func (e sevenZipExtractor) Supports(archiveName string) bool {
+	return strings.HasSuffix(archiveName, ".tar.xz") || strings.HasSuffix(archiveName, ".zip") ||
+		strings.HasSuffix(archiveName, ".7z")
+}
+
+func (e sevenZipExtractor) Extract(archivePath, destDir string, progressCb ExtractionProgressCallback, cancelCh <-chan struct{}) error {
+	output := "-o" + destDir
+	return runExtractTool(archivePath, destDir, progressCb, cancelCh, func(ctx context.Context) []*exec.Cmd {
+		if strings.HasSuffix(archivePath, ".tar.xz") {
+			return []*exec.Cmd{
+				exec.CommandContext(ctx, e.path, "x", "-so", "-txz", archivePath),
+				exec.CommandContext(ctx, e.path, "x", "-si", "-ttar", "-y", "-bd", output),
+			}
+		}
+		return []*exec.Cmd{exec.CommandContext(ctx, e.path, "x", "-y", "-bd", output, archivePath)}
+	})
+}
+
+// runExtractTool runs the commands of an external extractor, each piping its
+// output into the next, until they finish or the extraction is cancelled.
+// Progress is reported from the bytes written to destDir.
+func runExtractTool(archivePath, destDir string, progressCb ExtractionProgressCallback, cancelCh <-chan struct{}, commands func(ctx context.Context) []*exec.Cmd) error {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// Each command writes its errors from a goroutine of its own
+	cmds := commands(ctx)
+	stderrs := make([]bytes.Buffer, len(cmds))
+	for i, cmd := range cmds {
+		cmd.Stderr = &stderrs[i]
+		if i > 0 {
+			pipe, err := cmds[i-1].StdoutPipe()
+			if err != nil {
+				return fmt.Errorf("failed to connect %s: %w", filepath.Base(cmd.Path), err)
+			}
+			cmd.Stdin = pipe
+		}
+	}
+	for _, cmd := range cmds {
+		if err := cmd.Start(); err != nil {
+			return fmt.Errorf("failed to start %s: %w", filepath.Base(cmd.Path), err)
+		}
+	}
+	done := make(chan error, 1)
+	go func() {
+		var firstErr error
+		for i, cmd := range cmds {
+			if err := cmd.Wait(); err != nil && firstErr == nil {
+				firstErr = fmt.Errorf("%s failed: %w: %s", filepath.Base(cmd.Path), err, strings.TrimSpace(stderrs[i].String()))
+			}
+		}
+		done <- firstErr
+	}()
+
+	total := uncompressedSize(archivePath)
+	report := func() {
+		if progressCb != nil && total > 0 {
+			progressCb(min(float64(extractedBytes(destDir))/float64(total), 1.0))
+		}
+	}
+
+	ticker := time.NewTicker(extractPollInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case err := <-done:
+			if err != nil {
+				return err
+			}
+			if progressCb != nil {
+				progressCb(1.0) // Archive headers make the measured size fall a bit short
+			}
+			return nil
+		case <-cancelCh:
+			cancel()
+			<-done
+			return ErrCancelled
+		case <-ticker.C:
+			report()
+		}
+	}
+}
+
+// uncompressedSize returns the size of an archive's contents, or 0 if the
+// format doesn't record it.
+func uncompressedSize(archivePath string) int64 {
+	switch {
+	case strings.HasSuffix(archivePath, ".tar.xz"):
+		if size, err := xzUncompressedSize(archivePath); err == nil {
+			return size
+		}
+	case strings.HasSuffix(archivePath, ".zip"):
+		reader, err := zip.OpenReader(archivePath)
+		if err != nil {
+			return 0
+		}
+		defer reader.Close()
+		var size int64
+		for _, f := range reader.File {
+			size += int64(f.UncompressedSize64)
+		}
+		return size
+	}
+	return 0
+}
+
+// singleRootDir returns the one directory an archive was extracted into.
+func singleRootDir(destDir string) (string, error) {
+	entries, err := os.ReadDir(destDir)
+	if err != nil {
+		return "", fmt.Errorf("failed to read extracted archive: %w", err)
+	}
+	if len(entries) != 1 || !entries[0].IsDir() {
+		return "", fmt.Errorf("%w: archive has no single root directory", ErrVerificationFailed)
+	}
+	return entries[0].Name(), nil
+}
+
+// extractedBytes returns the size of the regular files below dir.
+func extractedBytes(dir string) int64 {
+	var size int64
+	_ = filepath.WalkDir(dir, func(_ string, d fs.DirEntry, err error) error {
+		if err != nil || !d.Type().IsRegular() {
+			return nil
+		}
+		if info, err := d.Info(); err == nil {
+			size += info.Size()
+		}
+		return nil
+	})
+	return size
+}
@@ -0,0 +1,38 @@
+package download
+
+import (
+	"TUI-Blender-Launcher/model"
+	"context"
+	"fmt"
+	"os"
+	"time"
+)
+
+// hookTimeout bounds a post-install hook, so a stuck script can't hold up the
+// download slot forever.
+const hookTimeout = 10 * time.Minute
+
+// RunPostInstallHook runs command through the shell after build was installed
+// to installDir, e.g. to symlink it or install studio addons. The build is
+// described in BLENDER_VERSION, BLENDER_PATH, BLENDER_BRANCH and BLENDER_HASH,
+// and the command runs inside installDir. An empty command does nothing.
+func RunPostInstallHook(command string, build model.BlenderBuild, installDir string) error {
+	if command == "" {
+		return nil
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), hookTimeout)
+	defer cancel()
+
+	cmd := shellCommand(ctx, command)
+	cmd.Dir = installDir
+	cmd.Env = append(os.Environ(),
+		"BLENDER_VERSION="+build.Version,
+		"BLENDER_PATH="+installDir,
+		"BLENDER_BRANCH="+build.Branch,
+		"BLENDER_HASH="+build.Hash,
+	)
+	if err := runReportingStderr(cmd); err != nil {
+		return fmt.Errorf("post-install hook failed: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,65 @@
+package download
+
+import (
+	"bufio"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// IgnoreFilename is the file at the root of the download directory listing
+// glob patterns of entries that aren't part of the library, one per line.
+const IgnoreFilename = ".launcherignore"
+
+// IgnoreRules decides which entries of the download directory the launcher
+// leaves alone, like textures or caches of other tools kept alongside builds.
+type IgnoreRules struct {
+	patterns []string
+}
+
+// LoadIgnore reads the ignore rules of the library at downloadBaseDir. Blank
+// lines and lines starting with # are skipped, as are invalid patterns. A
+// missing file ignores nothing.
+func LoadIgnore(downloadBaseDir string) IgnoreRules {
+	var rules IgnoreRules
+	file, err := os.Open(filepath.Join(downloadBaseDir, IgnoreFilename))
+	if err != nil {
+		return rules
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		pattern := strings.TrimSpace(scanner.Text())
+		if pattern == "" || strings.HasPrefix(pattern, "#") {
+			continue
+		}
+		// Like .gitignore, a leading slash anchors to the root. A trailing one
+		// marking a directory is dropped.
+		pattern = strings.TrimSuffix(filepath.ToSlash(pattern), "/")
+		if _, err := path.Match(pattern, ""); err != nil || strings.Trim(pattern, "/") == "" {
+			continue
+		}
+		rules.patterns = append(rules.patterns, pattern)
+	}
+	return rules
+}
+
+// Ignored reports whether the entry at relPath, relative to the download
+// directory, is ignored. Patterns without a slash match the entry's name at
+// any depth, others the whole path.
+func (r IgnoreRules) Ignored(relPath string) bool {
+	relPath = filepath.ToSlash(relPath)
+	name := path.Base(relPath)
+	for _, pattern := range r.patterns {
+		target := name
+		if strings.Contains(pattern, "/") {
+			pattern, target = strings.TrimPrefix(pattern, "/"), relPath
+		}
+		if ok, _ := path.Match(pattern, target); ok {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,174 @@
+package download
+
+import (
+	"TUI-Blender-Launcher/model"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// buildInfoSuffix names the file next to an archive in .downloading that
+// describes its build, so an interrupted download can be offered for resuming
+// after a restart without fetching the builds first.
+const buildInfoSuffix = ".build.json"
+
+// InterruptedDownload is a download that stopped before its build was
+// installed, because it was cancelled or the launcher quit.
+type InterruptedDownload struct {
+	Build      model.BlenderBuild
+	Downloaded int64 // Bytes of the archive already on disk
+}
+
+// buildInfoPath returns where the description of a build being downloaded is kept.
+func buildInfoPath(build model.BlenderBuild, downloadBaseDir string) string {
+	return ArchivePath(build, downloadBaseDir) + buildInfoSuffix
+}
+
+// saveBuildInfo records which build the archive in .downloading belongs to.
+func saveBuildInfo(build model.BlenderBuild, downloadBaseDir string) error {
+	data, err := json.Marshal(build)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(buildInfoPath(build, downloadBaseDir), data, 0644)
+}
+
+// PartialSize returns how much of a build's archive is on disk in .downloading,
+// whether as a single file, parallel segments or torrent data.
+func PartialSize(build model.BlenderBuild, downloadBaseDir string) int64 {
+	if build.DownloadURL == "" {
+		return 0
+	}
+	archivePath := ArchivePath(build, downloadBaseDir)
+
+	var size int64
+	if info, err := os.Stat(archivePath); err == nil {
+		size += info.Size()
+	}
+	segments, _ := filepath.Glob(archivePath + ".part*")
+	for _, segment := range segments {
+		if info, err := os.Stat(segment); err == nil {
+			size += info.Size()
+		}
+	}
+	return size + torrentBytes(archivePath+torrentDirSuffix)
+}
+
+// DiscardPartial removes everything an interrupted download left behind.
+func DiscardPartial(build model.BlenderBuild, downloadBaseDir string) error {
+	archivePath := ArchivePath(build, downloadBaseDir)
+	paths, _ := filepath.Glob(archivePath + ".part*")
+	paths = append(paths, archivePath, archivePath+torrentDirSuffix, archivePath+buildInfoSuffix)
+	for _, path := range paths {
+		if err := os.RemoveAll(path); err != nil {
+			return fmt.Errorf("failed to discard partial download: %w", err)
+		}
+	}
+	return nil
+}
+
+// FindInterrupted lists the downloads in downloadBaseDir that stopped before
+// their build was installed. Descriptions whose archive data is gone are stale
+// and removed.
+func FindInterrupted(downloadBaseDir string) ([]InterruptedDownload, error) {
+	dir := filepath.Join(downloadBaseDir, DownloadingDir)
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to read %s directory: %w", DownloadingDir, err)
+	}
+
+	var interrupted []InterruptedDownload
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.HasSuffix(entry.Name(), buildInfoSuffix) {
+			continue
+		}
+		path := filepath.Join(dir, entry.Name())
+		data, err := os.ReadFile(path)
+		if err != nil {
+			continue
+		}
+		var build model.BlenderBuild
+		if err := json.Unmarshal(data, &build); err != nil || build.DownloadURL == "" {
+			continue
+		}
+
+		downloaded := PartialSize(build, downloadBaseDir)
+		if downloaded == 0 {
+			_ = os.Remove(path)
+			continue
+		}
+		interrupted = append(interrupted, InterruptedDownload{Build: build, Downloaded: downloaded})
+	}
+	return interrupted, nil
+}
+
+// FindStaleFiles lists what crashed or older sessions left in .downloading
+// that can't be resumed: staging directories of unfinished extractions and
+// archive data without a description of its build. It returns their paths
+// and total size. Only call it while no download is running.
+func FindStaleFiles(downloadBaseDir string) ([]string, int64, error) {
+	dir := filepath.Join(downloadBaseDir, DownloadingDir)
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, 0, nil
+		}
+		return nil, 0, fmt.Errorf("failed to read %s directory: %w", DownloadingDir, err)
+	}
+
+	described := make(map[string]bool)
+	for _, entry := range entries {
+		if name, ok := strings.CutSuffix(entry.Name(), buildInfoSuffix); ok {
+			described[name] = true
+		}
+	}
+
+	var paths []string
+	var size int64
+	for _, entry := range entries {
+		if described[partialArchiveName(entry.Name())] {
+			continue
+		}
+		path := filepath.Join(dir, entry.Name())
+		paths = append(paths, path)
+		size += extractedBytes(path)
+	}
+	return paths, size, nil
+}
+
+// Resumable reports whether path, a file or directory in .downloading, holds
+// data of an interrupted download that can still be resumed.
+func Resumable(path string) bool {
+	name := partialArchiveName(filepath.Base(path))
+	_, err := os.Stat(filepath.Join(filepath.Dir(path), name+buildInfoSuffix))
+	return err == nil
+}
+
+// partialArchiveName returns the archive a file in .downloading belongs to,
+// e.g. "blender.zip" for its segment "blender.zip.part1-4".
+func partialArchiveName(name string) string {
+	name = strings.TrimSuffix(name, buildInfoSuffix)
+	name = strings.TrimSuffix(name, torrentDirSuffix)
+	if i := strings.LastIndex(name, ".part"); i > 0 {
+		var index, count int
+		if n, _ := fmt.Sscanf(name[i:], ".part%d-%d", &index, &count); n == 2 {
+			name = name[:i]
+		}
+	}
+	return name
+}
+
+// RemoveStaleFiles deletes the leftovers found by FindStaleFiles.
+func RemoveStaleFiles(paths []string) error {
+	for _, path := range paths {
+		if err := os.RemoveAll(path); err != nil {
+			return fmt.Errorf("failed to remove %s: %w", filepath.Base(path), err)
+		}
+	}
+	return nil
+}
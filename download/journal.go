@@ -0,0 +1,148 @@
+package download
+
+import (
+	"TUI-Blender-Launcher/config"
+	"TUI-Blender-Launcher/model"
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// JournalFilename is the file in the state directory recording every
+// downloaded archive, one JSON object per line. Entries are only ever appended.
+const JournalFilename = "downloads.jsonl"
+
+// Results of a journaled install
+const (
+	JournalInstalled          = "installed"
+	JournalVerificationFailed = "verification failed"
+	JournalExtractionFailed   = "extraction failed"
+	JournalCancelled          = "cancelled"
+	JournalFailed             = "failed"
+)
+
+// JournalEntry records one archive the launcher downloaded and what became of it.
+type JournalEntry struct {
+	Time    time.Time `json:"time"`
+	Version string    `json:"version"`
+	Hash    string    `json:"hash"` // Commit the build was made from
+	URL     string    `json:"url"`
+	Size    int64     `json:"size"`   // Bytes of the archive as downloaded
+	SHA256  string    `json:"sha256"` // Digest of the archive as downloaded
+	Result  string    `json:"result"`
+	Error   string    `json:"error,omitempty"`
+}
+
+// journalMu serializes appends of concurrent downloads.
+var journalMu sync.Mutex
+
+// JournalPath returns the full path to the download journal.
+func JournalPath() (string, error) {
+	stateDir, err := config.GetStateDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(stateDir, JournalFilename), nil
+}
+
+// newJournalEntry describes a downloaded archive, hashing it as it is on disk.
+func newJournalEntry(build model.BlenderBuild, archivePath string) (JournalEntry, error) {
+	info, err := os.Stat(archivePath)
+	if err != nil {
+		return JournalEntry{}, err
+	}
+	digest, err := hashFile(archivePath)
+	if err != nil {
+		return JournalEntry{}, err
+	}
+	return JournalEntry{
+		Version: build.Version,
+		Hash:    build.Hash,
+		URL:     build.DownloadURL,
+		Size:    info.Size(),
+		SHA256:  digest,
+	}, nil
+}
+
+// journalResult sums up the outcome of an install for the journal.
+func journalResult(err error) string {
+	var extractionErr *ExtractionError
+	switch {
+	case err == nil:
+		return JournalInstalled
+	case errors.Is(err, ErrCancelled):
+		return JournalCancelled
+	case errors.Is(err, ErrVerificationFailed):
+		return JournalVerificationFailed
+	case errors.As(err, &extractionErr):
+		return JournalExtractionFailed
+	default:
+		return JournalFailed
+	}
+}
+
+// AppendJournal adds an entry to the download journal, stamping it with the
+// current time.
+func AppendJournal(entry JournalEntry) error {
+	path, err := JournalPath()
+	if err != nil {
+		return err
+	}
+
+	journalMu.Lock()
+	defer journalMu.Unlock()
+
+	if err := os.MkdirAll(filepath.Dir(path), 0750); err != nil {
+		return fmt.Errorf("failed to create state directory: %w", err)
+	}
+	file, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return fmt.Errorf("failed to open download journal: %w", err)
+	}
+	defer file.Close()
+
+	entry.Time = time.Now()
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+	if _, err := file.Write(append(data, '\n')); err != nil {
+		return fmt.Errorf("failed to write download journal: %w", err)
+	}
+	return file.Sync()
+}
+
+// ReadJournal returns all journaled downloads, oldest first. A missing journal
+// has no entries.
+func ReadJournal() ([]JournalEntry, error) {
+	path, err := JournalPath()
+	if err != nil {
+		return nil, err
+	}
+	file, err := os.Open(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	} else if err != nil {
+		return nil, fmt.Errorf("failed to open download journal: %w", err)
+	}
+	defer file.Close()
+
+	var entries []JournalEntry
+	scanner := bufio.NewScanner(file)
+	for line := 1; scanner.Scan(); line++ {
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+		var entry JournalEntry
+		if err := json.Unmarshal(scanner.Bytes(), &entry); err != nil {
+			return entries, fmt.Errorf("download journal line %d: %w", line, err)
+		}
+		entries = append(entries, entry)
+	}
+	return entries, scanner.Err()
+}
@@ -0,0 +1,123 @@
+package download
+
+import (
+	"bufio"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// ManifestFilename is the checksum manifest written into every installed build.
+// It uses the sha256sum format, so `sha256sum -c` can check a build by hand too.
+const ManifestFilename = "manifest.sha256"
+
+// ErrNoManifest is returned when verifying a build installed before manifests existed.
+var ErrNoManifest = errors.New("build has no checksum manifest")
+
+// maxReportedMismatches limits how many bad files a verification error lists.
+const maxReportedMismatches = 3
+
+// WriteManifest hashes every regular file of an installed build into its manifest.
+func WriteManifest(buildDir string) error {
+	var lines []string
+	err := filepath.WalkDir(buildDir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !d.Type().IsRegular() {
+			return nil
+		}
+		rel, err := filepath.Rel(buildDir, path)
+		if err != nil {
+			return err
+		}
+		if isManifestExcluded(rel) {
+			return nil
+		}
+		sum, err := hashFile(path)
+		if err != nil {
+			return err
+		}
+		lines = append(lines, sum+"  "+filepath.ToSlash(rel))
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to hash build files: %w", err)
+	}
+
+	sort.Strings(lines)
+	content := strings.Join(lines, "\n") + "\n"
+	if err := os.WriteFile(filepath.Join(buildDir, ManifestFilename), []byte(content), 0644); err != nil {
+		return fmt.Errorf("failed to write manifest: %w", err)
+	}
+	return nil
+}
+
+// VerifyManifest re-hashes the files of an installed build and compares them with
+// its manifest. Files added after installation (e.g. user scripts) are ignored.
+func VerifyManifest(buildDir string) error {
+	file, err := os.Open(filepath.Join(buildDir, ManifestFilename))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return ErrNoManifest
+		}
+		return fmt.Errorf("failed to open manifest: %w", err)
+	}
+	defer file.Close()
+
+	var mismatches []string
+	checked := 0
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		want, rel, ok := strings.Cut(scanner.Text(), "  ")
+		if !ok {
+			continue
+		}
+		checked++
+		got, err := hashFile(filepath.Join(buildDir, filepath.FromSlash(rel)))
+		if err != nil || got != want {
+			mismatches = append(mismatches, rel)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("failed to read manifest: %w", err)
+	}
+
+	if len(mismatches) > 0 {
+		shown := mismatches
+		if len(shown) > maxReportedMismatches {
+			shown = shown[:maxReportedMismatches]
+		}
+		return fmt.Errorf("%w: %d of %d files changed or missing (%s)",
+			ErrVerificationFailed, len(mismatches), checked, strings.Join(shown, ", "))
+	}
+	return nil
+}
+
+// isManifestExcluded reports whether a file is rewritten by the launcher itself
+// and therefore left out of the manifest.
+func isManifestExcluded(rel string) bool {
+	return rel == ManifestFilename || rel == versionMetaFilename
+}
+
+// hashFile returns the hex encoded SHA-256 digest of a file.
+func hashFile(path string) (string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer file.Close()
+
+	hash := sha256.New()
+	if _, err := io.Copy(hash, file); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
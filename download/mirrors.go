@@ -0,0 +1,266 @@
+package download
+
+import (
+	"TUI-Blender-Launcher/config"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Mirror selection
+const (
+	// Bytes fetched from each mirror to measure its speed
+	mirrorProbeSize = 256 * 1024
+	// Time a probe may take before the mirror counts as unusable
+	mirrorProbeTimeout = 10 * time.Second
+	// Measurements younger than this are trusted without probing again
+	mirrorProbeTTL = time.Hour
+	// Weight of a new measurement in a mirror's remembered speed
+	mirrorSpeedWeight = 0.5
+)
+
+// mirrorStatsFilename is the file in the state directory that remembers mirror speeds.
+const mirrorStatsFilename = "mirrors.json"
+
+// mirrorStat is the remembered performance of one mirror.
+type mirrorStat struct {
+	Speed     float64   `json:"speed"` // Bytes/sec, averaged over measurements; 0 after a failure
+	LastProbe time.Time `json:"last_probe"`
+}
+
+// mirrorStatsMu serializes updates of the mirror stats file across downloads.
+var mirrorStatsMu sync.Mutex
+
+// mirrorURL returns the address of an archive on a mirror: the archive's path
+// below the mirror's base URL.
+func mirrorURL(archiveURL, mirror string) (string, error) {
+	a, err := url.Parse(archiveURL)
+	if err != nil {
+		return "", err
+	}
+	m, err := url.Parse(mirror)
+	if err != nil || m.Scheme == "" || m.Host == "" {
+		return "", fmt.Errorf("invalid mirror %q", mirror)
+	}
+	m.Path = strings.TrimSuffix(m.Path, "/") + a.Path
+	m.RawQuery = a.RawQuery
+	return m.String(), nil
+}
+
+// mirrorHost identifies a mirror in the stats by its base address.
+func mirrorHost(rawURL string) string {
+	if u, err := url.Parse(rawURL); err == nil {
+		return u.Host
+	}
+	return rawURL
+}
+
+// selectMirror returns the fastest place to download the archive at
+// archiveURL from: the builder itself or one of the configured mirrors.
+// Recent measurements are reused; otherwise every candidate is probed with a
+// small range request. Without mirrors the builder is used as is.
+func selectMirror(archiveURL string, mirrors []string, opts fetchOptions) string {
+	candidates := []string{archiveURL}
+	for _, mirror := range mirrors {
+		if u, err := mirrorURL(archiveURL, mirror); err == nil {
+			candidates = append(candidates, u)
+		}
+	}
+	if len(candidates) == 1 {
+		return archiveURL
+	}
+
+	stats := loadMirrorStats()
+	fresh := true
+	for _, candidate := range candidates {
+		if stat, ok := stats[mirrorHost(candidate)]; !ok || time.Since(stat.LastProbe) > mirrorProbeTTL {
+			fresh = false
+			break
+		}
+	}
+
+	if !fresh {
+		speeds := make([]float64, len(candidates))
+		var wg sync.WaitGroup
+		for i, candidate := range candidates {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				speeds[i] = probeMirror(candidate, opts)
+			}()
+		}
+		wg.Wait()
+		stats = recordMirrorSpeeds(candidates, speeds)
+	}
+
+	best, bestSpeed := archiveURL, 0.0
+	for _, candidate := range candidates {
+		if speed := stats[mirrorHost(candidate)].Speed; speed > bestSpeed {
+			best, bestSpeed = candidate, speed
+		}
+	}
+	return best
+}
+
+// archiveExtensions are the archive types the builder publishes, longer ones
+// first so ".tar.xz" wins over ".xz".
+var archiveExtensions = []string{".tar.xz", ".tar.gz", ".tar.bz2", ".zip", ".dmg", ".pkg", ".xz"}
+
+// publishedChecksumURL returns where the builder publishes the SHA-256 of the
+// archive at archiveURL: next to it, with ".sha256" in place of its extension.
+func publishedChecksumURL(archiveURL string) (string, error) {
+	u, err := url.Parse(archiveURL)
+	if err != nil {
+		return "", err
+	}
+	for _, ext := range archiveExtensions {
+		if trimmed, ok := strings.CutSuffix(u.Path, ext); ok {
+			u.Path = trimmed + ".sha256"
+			return u.String(), nil
+		}
+	}
+	return "", fmt.Errorf("no published checksum for %s", archiveURL)
+}
+
+// fetchPublishedChecksum returns the hex encoded SHA-256 the builder publishes
+// for the archive at archiveURL.
+func fetchPublishedChecksum(archiveURL string, opts fetchOptions) (string, error) {
+	checksumURL, err := publishedChecksumURL(archiveURL)
+	if err != nil {
+		return "", err
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), mirrorProbeTimeout)
+	defer cancel()
+	req, err := newDownloadRequest(ctx, checksumURL, opts)
+	if err != nil {
+		return "", err
+	}
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("failed to fetch the published checksum: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("failed to fetch the published checksum: %w", &StatusError{Code: resp.StatusCode})
+	}
+
+	// The sha256sum format, the digest followed by the file name
+	data, err := io.ReadAll(io.LimitReader(resp.Body, 4096))
+	if err != nil {
+		return "", fmt.Errorf("failed to fetch the published checksum: %w", err)
+	}
+	fields := strings.Fields(string(data))
+	if len(fields) == 0 || len(fields[0]) != 64 {
+		return "", fmt.Errorf("invalid published checksum at %s", checksumURL)
+	}
+	return strings.ToLower(fields[0]), nil
+}
+
+// verifyMirrorDownload checks an archive downloaded from a mirror against the
+// checksum the builder publishes for it, as a mirror's content can't be
+// trusted on its own. An archive that can't be verified is removed.
+func verifyMirrorDownload(archiveURL, path string, opts fetchOptions) error {
+	want, err := fetchPublishedChecksum(archiveURL, opts)
+	if err == nil {
+		var got string
+		if got, err = hashFile(path); err == nil && got != want {
+			err = fmt.Errorf("%w: the mirror's archive doesn't match the builder's checksum", ErrVerificationFailed)
+		}
+	}
+	if err != nil {
+		_ = os.Remove(path)
+		return fmt.Errorf("can't trust the mirror download: %w", err)
+	}
+	return nil
+}
+
+// probeMirror downloads the first bytes of an archive and returns the
+// throughput in bytes/sec, or 0 if the mirror failed or can't serve ranges.
+func probeMirror(archiveURL string, opts fetchOptions) float64 {
+	ctx, cancel := context.WithTimeout(context.Background(), mirrorProbeTimeout)
+	defer cancel()
+
+	req, err := newDownloadRequest(ctx, archiveURL, opts)
+	if err != nil {
+		return 0
+	}
+	req.Header.Set("Range", fmt.Sprintf("bytes=0-%d", mirrorProbeSize-1))
+
+	start := time.Now()
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return 0
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusPartialContent {
+		return 0
+	}
+
+	n, err := io.Copy(io.Discard, io.LimitReader(resp.Body, mirrorProbeSize))
+	if err != nil || n == 0 {
+		return 0
+	}
+	return float64(n) / max(time.Since(start).Seconds(), 1e-3)
+}
+
+// mirrorStatsPath returns the full path to the mirror stats file.
+func mirrorStatsPath() (string, error) {
+	stateDir, err := config.GetStateDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(stateDir, mirrorStatsFilename), nil
+}
+
+// loadMirrorStats reads the remembered mirror performance. A missing or
+// unreadable file yields no stats, mirrors are then probed again.
+func loadMirrorStats() map[string]mirrorStat {
+	stats := make(map[string]mirrorStat)
+	path, err := mirrorStatsPath()
+	if err != nil {
+		return stats
+	}
+	if data, err := os.ReadFile(path); err == nil {
+		_ = json.Unmarshal(data, &stats)
+	}
+	return stats
+}
+
+// recordMirrorSpeeds folds new measurements into the remembered stats and
+// saves them. Failures reset a mirror's speed so it isn't picked next time.
+func recordMirrorSpeeds(candidates []string, speeds []float64) map[string]mirrorStat {
+	mirrorStatsMu.Lock()
+	defer mirrorStatsMu.Unlock()
+
+	stats := loadMirrorStats()
+	now := time.Now()
+	for i, candidate := range candidates {
+		host := mirrorHost(candidate)
+		stat := stats[host]
+		if speeds[i] == 0 || stat.Speed == 0 {
+			stat.Speed = speeds[i]
+		} else {
+			stat.Speed = mirrorSpeedWeight*speeds[i] + (1-mirrorSpeedWeight)*stat.Speed
+		}
+		stat.LastProbe = now
+		stats[host] = stat
+	}
+
+	// Remembering is an optimization, failing to save only means probing again
+	if path, err := mirrorStatsPath(); err == nil {
+		if err := os.MkdirAll(filepath.Dir(path), 0750); err == nil {
+			if data, err := json.MarshalIndent(stats, "", "  "); err == nil {
+				_ = os.WriteFile(path, data, 0644)
+			}
+		}
+	}
+	return stats
+}
@@ -0,0 +1,143 @@
+package download
+
+import (
+	"TUI-Blender-Launcher/model"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os/exec"
+	"runtime"
+	"strings"
+	"time"
+)
+
+// Events reported by download notifications
+const (
+	NotifyCompleted = "completed"
+	NotifyFailed    = "failed"
+)
+
+// notifyTimeout bounds a webhook request or notify command, so a hanging
+// receiver can't pile up notifications.
+const notifyTimeout = 30 * time.Second
+
+// Notification describes a finished download to a webhook or notify command.
+type Notification struct {
+	Event        string    `json:"event"`
+	Time         time.Time `json:"time"`
+	Version      string    `json:"version"`
+	Branch       string    `json:"branch"`
+	Hash         string    `json:"hash"`
+	ReleaseCycle string    `json:"release_cycle"`
+	BuildDate    time.Time `json:"build_date"`
+	URL          string    `json:"url"`
+	Size         int64     `json:"size"`
+	Path         string    `json:"path,omitempty"`  // Install directory of a completed download
+	Error        string    `json:"error,omitempty"` // Why the download failed
+}
+
+// NewNotification describes the outcome of downloading build: installed to
+// path, or failed with err.
+func NewNotification(build model.BlenderBuild, path string, err error) Notification {
+	n := Notification{
+		Event:        NotifyCompleted,
+		Time:         time.Now(),
+		Version:      build.Version,
+		Branch:       build.Branch,
+		Hash:         build.Hash,
+		ReleaseCycle: build.ReleaseCycle,
+		BuildDate:    build.BuildDate.Time(),
+		URL:          build.DownloadURL,
+		Size:         build.Size,
+		Path:         path,
+	}
+	if err != nil {
+		n.Event = NotifyFailed
+		n.Path = ""
+		n.Error = err.Error()
+	}
+	return n
+}
+
+// Notify POSTs the notification as JSON to webhookURL and runs command through
+// the shell with the JSON on its standard input. Either may be empty. Both are
+// attempted even if one fails.
+func Notify(webhookURL, command string, n Notification) error {
+	if webhookURL == "" && command == "" {
+		return nil
+	}
+	payload, err := json.Marshal(n)
+	if err != nil {
+		return err
+	}
+
+	var errs []error
+	if webhookURL != "" {
+		if err := postWebhook(webhookURL, payload); err != nil {
+			errs = append(errs, fmt.Errorf("webhook failed: %w", err))
+		}
+	}
+	if command != "" {
+		if err := runNotifyCommand(command, payload); err != nil {
+			errs = append(errs, fmt.Errorf("notify command failed: %w", err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// postWebhook sends payload to url, expecting a 2xx answer.
+func postWebhook(url string, payload []byte) error {
+	ctx, cancel := context.WithTimeout(context.Background(), notifyTimeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(payload))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", userAgent)
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("%w: %s", ErrUnexpectedStatus, resp.Status)
+	}
+	return nil
+}
+
+// runNotifyCommand runs command through the platform's shell, feeding it payload.
+func runNotifyCommand(command string, payload []byte) error {
+	ctx, cancel := context.WithTimeout(context.Background(), notifyTimeout)
+	defer cancel()
+
+	cmd := shellCommand(ctx, command)
+	cmd.Stdin = bytes.NewReader(payload)
+	return runReportingStderr(cmd)
+}
+
+// shellCommand prepares command to run through the platform's shell.
+func shellCommand(ctx context.Context, command string) *exec.Cmd {
+	if runtime.GOOS == "windows" {
+		return exec.CommandContext(ctx, "cmd", "/C", command)
+	}
+	return exec.CommandContext(ctx, "sh", "-c", command)
+}
+
+// runReportingStderr runs cmd, adding what it wrote to stderr to its error.
+func runReportingStderr(cmd *exec.Cmd) error {
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return fmt.Errorf("%w: %s", err, msg)
+		}
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,114 @@
+package download
+
+import (
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// PauseGate suspends a running download between reads, keeping its connection
+// and partial data. A nil gate never pauses.
+type PauseGate struct {
+	mu     sync.Mutex
+	paused bool
+	resume chan struct{}
+}
+
+// NewPauseGate creates a gate in the running state.
+func NewPauseGate() *PauseGate {
+	return &PauseGate{}
+}
+
+// Pause suspends the download at its next read.
+func (g *PauseGate) Pause() {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if !g.paused {
+		g.paused = true
+		g.resume = make(chan struct{})
+	}
+}
+
+// Resume lets a paused download continue.
+func (g *PauseGate) Resume() {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if g.paused {
+		g.paused = false
+		close(g.resume)
+	}
+}
+
+// Paused reports whether the download is currently paused.
+func (g *PauseGate) Paused() bool {
+	if g == nil {
+		return false
+	}
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	return g.paused
+}
+
+// wait blocks while the gate is paused.
+func (g *PauseGate) wait(cancelCh <-chan struct{}) error {
+	if g == nil {
+		return nil
+	}
+	g.mu.Lock()
+	if !g.paused {
+		g.mu.Unlock()
+		return nil
+	}
+	resume := g.resume
+	g.mu.Unlock()
+
+	select {
+	case <-resume:
+		return nil
+	case <-cancelCh:
+		return ErrCancelled
+	}
+}
+
+// idleWatchdog cancels a transfer that receives no data for idleTimeout,
+// except while its download is paused on purpose.
+type idleWatchdog struct {
+	mu       sync.Mutex
+	timer    *time.Timer
+	timedOut atomic.Bool
+}
+
+// startIdleWatchdog arms a watchdog that calls cancel when the transfer goes idle.
+func startIdleWatchdog(gate *PauseGate, cancel func()) *idleWatchdog {
+	w := &idleWatchdog{}
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.timer = time.AfterFunc(idleTimeout, func() {
+		if gate.Paused() {
+			w.Reset()
+			return
+		}
+		w.timedOut.Store(true)
+		cancel()
+	})
+	return w
+}
+
+// Reset restarts the idle countdown, called whenever data arrives.
+func (w *idleWatchdog) Reset() {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.timer.Reset(idleTimeout)
+}
+
+// Stop disarms the watchdog.
+func (w *idleWatchdog) Stop() {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.timer.Stop()
+}
+
+// TimedOut reports whether the watchdog cancelled the transfer.
+func (w *idleWatchdog) TimedOut() bool {
+	return w.timedOut.Load()
+}
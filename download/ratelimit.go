@@ -0,0 +1,92 @@
+package download
+
+import (
+	"io"
+	"sync"
+	"time"
+)
+
+// maxLimitedRead keeps individual reads small so throttled downloads flow smoothly.
+const maxLimitedRead = 32 * 1024
+
+// RateLimiter is a token bucket shared by the downloads running together, so
+// the configured limit applies to them as a whole rather than to each
+// connection. The zero value doesn't limit until a rate is set.
+type RateLimiter struct {
+	mu     sync.Mutex
+	rate   float64 // Bytes per second, 0 means unlimited
+	tokens float64
+	last   time.Time
+}
+
+// SetRate changes the limit in bytes per second. 0 disables limiting.
+func (l *RateLimiter) SetRate(bytesPerSec float64) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.rate = bytesPerSec
+}
+
+// wait blocks until n bytes may be consumed under the current rate. A nil
+// limiter never blocks.
+func (l *RateLimiter) wait(n int, cancelCh <-chan struct{}) error {
+	if l == nil {
+		return nil
+	}
+	l.mu.Lock()
+	if l.rate <= 0 {
+		l.mu.Unlock()
+		return nil
+	}
+
+	now := time.Now()
+	if !l.last.IsZero() {
+		l.tokens += now.Sub(l.last).Seconds() * l.rate
+	}
+	// Allow at most one second worth of burst
+	if l.tokens > l.rate {
+		l.tokens = l.rate
+	}
+	l.last = now
+	l.tokens -= float64(n)
+
+	var delay time.Duration
+	if l.tokens < 0 {
+		delay = time.Duration(-l.tokens / l.rate * float64(time.Second))
+	}
+	l.mu.Unlock()
+
+	if delay <= 0 {
+		return nil
+	}
+	select {
+	case <-time.After(delay):
+		return nil
+	case <-cancelCh:
+		return ErrCancelled
+	}
+}
+
+// limitedReader throttles reads from an underlying reader through a RateLimiter
+// and holds them while its download is paused.
+type limitedReader struct {
+	reader   io.Reader
+	limiter  *RateLimiter
+	gate     *PauseGate
+	cancelCh <-chan struct{}
+}
+
+func (r *limitedReader) Read(p []byte) (int, error) {
+	if err := r.gate.wait(r.cancelCh); err != nil {
+		return 0, err
+	}
+	if len(p) > maxLimitedRead {
+		p = p[:maxLimitedRead]
+	}
+	n, err := r.reader.Read(p)
+	if n > 0 {
+		if waitErr := r.limiter.wait(n, r.cancelCh); waitErr != nil {
+			return n, waitErr
+		}
+	}
+	return n, err
+}
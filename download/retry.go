@@ -0,0 +1,59 @@
+package download
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// Backoff between automatic retries of a failed download
+const (
+	retryBaseDelay = 2 * time.Second
+	retryMaxDelay  = 30 * time.Second
+)
+
+// StatusError reports an HTTP response with an unexpected status code.
+// It matches ErrUnexpectedStatus with errors.Is.
+type StatusError struct {
+	Code int
+}
+
+func (e *StatusError) Error() string {
+	return fmt.Sprintf("%s %d", ErrUnexpectedStatus, e.Code)
+}
+
+// Is makes errors.Is(err, ErrUnexpectedStatus) hold for every StatusError.
+func (e *StatusError) Is(target error) bool {
+	return target == ErrUnexpectedStatus
+}
+
+// IsTransient reports whether a download failed for a reason that may go away
+// on its own, such as a dropped connection or an overloaded server, so trying
+// again later is worthwhile.
+func IsTransient(err error) bool {
+	var netErr *NetworkError
+	if !errors.As(Classify(err), &netErr) {
+		return false
+	}
+
+	// Client errors won't fix themselves, unlike server errors and rate limiting
+	var statusErr *StatusError
+	if errors.As(err, &statusErr) {
+		return statusErr.Code >= 500 || statusErr.Code == http.StatusTooManyRequests
+	}
+	return true
+}
+
+// RetryDelay returns how long to wait before the given retry (starting at 1),
+// doubling each time up to a limit.
+func RetryDelay(retry int) time.Duration {
+	delay := retryBaseDelay
+	for i := 1; i < retry && delay < retryMaxDelay; i++ {
+		delay *= 2
+	}
+	if delay > retryMaxDelay {
+		delay = retryMaxDelay
+	}
+	return delay
+}
@@ -0,0 +1,323 @@
+package download
+
+import (
+	"TUI-Blender-Launcher/config"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// minSegmentSize keeps segments from getting so small that connection setup dominates.
+const minSegmentSize = 4 * 1024 * 1024
+
+// fetchArchive downloads an archive using the number of parallel segments set in cfg,
+// falling back to a single resumable stream when segmenting isn't possible.
+// The configured bandwidth limit applies to all connections combined, and to
+// the other downloads sharing limiter; a nil limiter limits this one alone.
+// With torrents enabled, archives that have a .torrent are fetched over
+// BitTorrent first and over HTTP if that fails. HTTP downloads come from the
+// fastest of the builder and the configured mirrors, and are checked against
+// the builder's published checksum when they come from a mirror.
+func fetchArchive(cfg config.Config, url string, destFilePath string, progressCb ProgressCallback, cancelCh <-chan struct{}, gate *PauseGate, limiter *RateLimiter) error {
+	rate := cfg.DownloadRateLimit * 1024 * 1024
+	if limiter == nil {
+		limiter = &RateLimiter{}
+	}
+	limiter.SetRate(rate)
+	opts := fetchOptions{clientID: cfg.UUID, limiter: limiter, gate: gate}
+	segments := cfg.DownloadSegments
+
+	// The torrent client throttles itself, a stalled torrent is given up for HTTP
+	if cfg.Torrent && torrentAvailable(url) {
+		err := fetchTorrent(url, destFilePath, int64(rate), progressCb, cancelCh, opts)
+		if err == nil || errors.Is(err, ErrCancelled) {
+			return err
+		}
+		// Start over on HTTP, the torrent's partial data can't be resumed there
+		_ = os.RemoveAll(destFilePath + torrentDirSuffix)
+	}
+
+	// A mirror that fails is remembered as such, so a retry picks another one.
+	// What a mirror serves must match the checksum the builder publishes.
+	source := selectMirror(url, cfg.Mirrors, opts)
+	err := fetchHTTP(source, destFilePath, segments, progressCb, cancelCh, opts)
+	if err == nil && source != url {
+		err = verifyMirrorDownload(url, destFilePath, opts)
+	}
+	if err != nil && source != url && !errors.Is(err, ErrCancelled) {
+		recordMirrorSpeeds([]string{source}, []float64{0})
+	}
+	return err
+}
+
+// fetchHTTP downloads an archive over HTTP in segments or as a single stream.
+func fetchHTTP(url string, destFilePath string, segments int, progressCb ProgressCallback, cancelCh <-chan struct{}, opts fetchOptions) error {
+	// An existing single-stream partial file is cheaper to resume as is
+	if _, err := os.Stat(destFilePath); segments <= 1 || err == nil {
+		return downloadFile(url, destFilePath, progressCb, cancelCh, opts)
+	}
+
+	total, err := probeRangeSupport(url, opts)
+	if err != nil || total < int64(segments)*minSegmentSize {
+		return downloadFile(url, destFilePath, progressCb, cancelCh, opts)
+	}
+
+	return downloadSegmented(url, destFilePath, total, segments, progressCb, cancelCh, opts)
+}
+
+// probeRangeSupport asks for the first byte of a file to learn its size and whether the
+// server honours range requests. It returns an error if ranges aren't supported.
+func probeRangeSupport(url string, opts fetchOptions) (int64, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	req, err := newDownloadRequest(ctx, url, opts)
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("Range", "bytes=0-0")
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return 0, fmt.Errorf("range probe failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusPartialContent {
+		return 0, fmt.Errorf("server does not support range requests")
+	}
+
+	// Content-Range looks like "bytes 0-0/123456"
+	contentRange := resp.Header.Get("Content-Range")
+	slash := strings.LastIndex(contentRange, "/")
+	if slash < 0 {
+		return 0, fmt.Errorf("malformed Content-Range %q", contentRange)
+	}
+	total, err := strconv.ParseInt(contentRange[slash+1:], 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("unknown file size in Content-Range %q", contentRange)
+	}
+	return total, nil
+}
+
+// segmentPath returns the on-disk file for one segment. The segment count is part of the
+// name so leftovers from a different segment setting are never mixed up.
+func segmentPath(destFilePath string, index, count int) string {
+	return fmt.Sprintf("%s.part%d-%d", destFilePath, index, count)
+}
+
+// downloadSegmented downloads total bytes in count parallel ranges, each into its own
+// resumable part file, then merges the parts into destFilePath.
+func downloadSegmented(url string, destFilePath string, total int64, count int, progressCb ProgressCallback, cancelCh <-chan struct{}, opts fetchOptions) error {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	go func() {
+		select {
+		case <-cancelCh:
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+
+	segmentSize := total / int64(count)
+	done := make([]atomic.Int64, count)
+
+	// Account for parts left behind by an earlier attempt
+	for i := 0; i < count; i++ {
+		if info, err := os.Stat(segmentPath(destFilePath, i, count)); err == nil {
+			done[i].Store(info.Size())
+		}
+	}
+
+	downloaded := func() int64 {
+		var sum int64
+		for i := range done {
+			sum += done[i].Load()
+		}
+		return sum
+	}
+
+	// Aggregate per-segment progress into a single report
+	if progressCb != nil {
+		progressCb(downloaded(), total)
+	}
+	reportDone := make(chan struct{})
+	reporterExited := make(chan struct{})
+	go func() {
+		defer close(reporterExited)
+		ticker := time.NewTicker(progressInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				if progressCb != nil {
+					progressCb(downloaded(), total)
+				}
+			case <-reportDone:
+				return
+			}
+		}
+	}()
+
+	var wg sync.WaitGroup
+	var firstErr error
+	var errOnce sync.Once
+	for i := 0; i < count; i++ {
+		start := int64(i) * segmentSize
+		end := start + segmentSize - 1
+		if i == count-1 {
+			end = total - 1
+		}
+
+		wg.Add(1)
+		go func(i int, start, end int64) {
+			defer wg.Done()
+			if err := downloadSegment(ctx, url, segmentPath(destFilePath, i, count), start, end, &done[i], opts); err != nil {
+				errOnce.Do(func() {
+					firstErr = err
+					cancel() // Stop the other segments
+				})
+			}
+		}(i, start, end)
+	}
+	wg.Wait()
+	close(reportDone)
+	<-reporterExited
+
+	if firstErr != nil {
+		if isClosed(cancelCh) {
+			return ErrCancelled
+		}
+		return firstErr
+	}
+
+	if err := mergeSegments(destFilePath, count); err != nil {
+		return err
+	}
+
+	if progressCb != nil {
+		progressCb(total, total)
+	}
+	return nil
+}
+
+// downloadSegment fetches bytes start..end (inclusive) into partPath, resuming from
+// whatever the part file already holds. done tracks the bytes present on disk.
+// The server must answer with exactly the range asked for, so a part never
+// holds bytes of another one.
+func downloadSegment(ctx context.Context, url, partPath string, start, end int64, done *atomic.Int64, opts fetchOptions) error {
+	length := end - start + 1
+	have := done.Load()
+	if have > length {
+		// Not this segment's bytes, start it over
+		if err := os.Remove(partPath); err != nil {
+			return fmt.Errorf("failed to remove oversized segment: %w", err)
+		}
+		have = 0
+		done.Store(0)
+	}
+	if have == length {
+		return nil // Finished in an earlier attempt
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	req, err := newDownloadRequest(ctx, url, opts)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Range", fmt.Sprintf("bytes=%d-%d", start+have, end))
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("segment request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusPartialContent {
+		return fmt.Errorf("segment request failed: %w", &StatusError{Code: resp.StatusCode})
+	}
+	// Content-Range looks like "bytes 100-199/123456"
+	if contentRange := resp.Header.Get("Content-Range"); !strings.HasPrefix(contentRange, fmt.Sprintf("bytes %d-%d/", start+have, end)) {
+		return fmt.Errorf("segment request for bytes %d-%d got Content-Range %q", start+have, end, contentRange)
+	}
+
+	outFile, err := os.OpenFile(partPath, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return fmt.Errorf("failed to open segment file: %w", err)
+	}
+	defer outFile.Close()
+
+	watchdog := startIdleWatchdog(opts.gate, cancel)
+	defer watchdog.Stop()
+
+	tracker := &progressTracker{
+		reader:  &limitedReader{reader: io.LimitReader(resp.Body, length-have), limiter: opts.limiter, gate: opts.gate, cancelCh: ctx.Done()},
+		current: have,
+		total:   length,
+		callback: func(read, total int64) {
+			watchdog.Reset()
+			done.Store(read)
+		},
+	}
+
+	written, err := io.CopyBuffer(outFile, tracker, make([]byte, 256*1024))
+	if err != nil {
+		if watchdog.TimedOut() {
+			return ErrIdleTimeout
+		}
+		if errors.Is(err, context.Canceled) {
+			return ErrCancelled
+		}
+		return fmt.Errorf("segment interrupted: %w", err)
+	}
+	// The next attempt resumes a segment cut short
+	if have+written != length {
+		return fmt.Errorf("segment ended after %d of %d bytes: %w", have+written, length, io.ErrUnexpectedEOF)
+	}
+	return nil
+}
+
+// mergeSegments concatenates the part files into destFilePath and removes them.
+func mergeSegments(destFilePath string, count int) error {
+	outFile, err := os.Create(destFilePath)
+	if err != nil {
+		return fmt.Errorf("failed to create merged file: %w", err)
+	}
+
+	for i := 0; i < count; i++ {
+		part, err := os.Open(segmentPath(destFilePath, i, count))
+		if err != nil {
+			outFile.Close()
+			return fmt.Errorf("failed to open segment %d: %w", i, err)
+		}
+		_, err = io.Copy(outFile, part)
+		part.Close()
+		if err != nil {
+			outFile.Close()
+			return fmt.Errorf("failed to merge segment %d: %w", i, err)
+		}
+	}
+
+	if err := outFile.Close(); err != nil {
+		return fmt.Errorf("failed to close merged file: %w", err)
+	}
+
+	// Remove every part, including leftovers from other segment counts
+	leftovers, _ := filepath.Glob(destFilePath + ".part*")
+	for _, part := range leftovers {
+		_ = os.Remove(part)
+	}
+	return nil
+}
@@ -0,0 +1,250 @@
+package download
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"net/http"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+)
+
+// torrentClient is the external BitTorrent client used for torrent downloads.
+// Like any BitTorrent client it uploads pieces to other peers while downloading.
+const torrentClient = "aria2c"
+
+// torrentDirSuffix names the working directory of a torrent download next to
+// the archive, holding the partial data and the client's resume state.
+const torrentDirSuffix = ".bt"
+
+// torrentStallTimeout is how long a torrent download may go without any new
+// data, e.g. for lack of peers, before it is given up for HTTP.
+var torrentStallTimeout = 2 * time.Minute
+
+// errTorrentStalled is returned for a torrent download that got no data for
+// torrentStallTimeout.
+var errTorrentStalled = errors.New("torrent download stalled")
+
+// torrentReadout matches the progress the torrent client prints, e.g.
+// "[#2089b0 400KiB/33MiB(1%) CN:44 SD:5 DL:11MiB]". Its first figure is the
+// verified data, including pieces checked from an earlier run.
+var torrentReadout = regexp.MustCompile(`\[#\w+ ([\d.]+)([KMG]i)?B/`)
+
+// torrentAvailable reports whether a .torrent is published next to the archive
+// at url and a torrent client is installed to use it.
+func torrentAvailable(url string) bool {
+	if _, err := exec.LookPath(torrentClient); err != nil {
+		return false
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodHead, url+".torrent", nil)
+	if err != nil {
+		return false
+	}
+	req.Header.Set("User-Agent", userAgent)
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return false
+	}
+	resp.Body.Close()
+	return resp.StatusCode == http.StatusOK
+}
+
+// fetchTorrent downloads the archive at url through its .torrent, at most
+// rate bytes per second unless that is 0. Progress is reported from the
+// verified data; pausing stops the client and resuming restarts it from its
+// saved state, as does a later attempt after cancelling. A download that
+// stalls fails with errTorrentStalled.
+func fetchTorrent(url string, destFilePath string, rate int64, progressCb ProgressCallback, cancelCh <-chan struct{}, opts fetchOptions) error {
+	workDir := destFilePath + torrentDirSuffix
+	if err := os.MkdirAll(workDir, 0750); err != nil {
+		return fmt.Errorf("failed to create torrent directory: %w", err)
+	}
+
+	// The torrent is fetched by the client, the archive size comes from the web server
+	var total int64
+	if size, err := probeRangeSupport(url, opts); err == nil {
+		total = size
+	}
+
+	for {
+		paused, err := runTorrentClient(url+".torrent", workDir, total, rate, progressCb, cancelCh, opts.gate)
+		if err != nil {
+			return err
+		}
+		if !paused {
+			break
+		}
+		if err := opts.gate.wait(cancelCh); err != nil {
+			return err
+		}
+	}
+
+	archive, err := largestFile(workDir)
+	if err != nil {
+		return fmt.Errorf("torrent download produced no archive: %w", err)
+	}
+	if err := os.Rename(archive, destFilePath); err != nil {
+		return fmt.Errorf("failed to move torrent download into place: %w", err)
+	}
+	return os.RemoveAll(workDir)
+}
+
+// runTorrentClient runs the torrent client until it finishes, the download is
+// cancelled, paused or stalls. It reports whether it stopped for a pause.
+func runTorrentClient(torrentURL, workDir string, total, rate int64, progressCb ProgressCallback, cancelCh <-chan struct{}, gate *PauseGate) (bool, error) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// Seeding stops once the download completes, the launcher doesn't stay running for it
+	readout := &torrentProgress{}
+	cmd := exec.CommandContext(ctx, torrentClient,
+		"--dir="+workDir,
+		"--continue=true",
+		"--seed-time=0",
+		"--follow-torrent=mem",
+		"--file-allocation=none",
+		"--max-overall-download-limit="+strconv.FormatInt(rate, 10),
+		"--summary-interval=1",
+		"--console-log-level=error",
+		"--download-result=hide",
+		torrentURL,
+	)
+	cmd.Stdout = readout
+	if err := cmd.Start(); err != nil {
+		return false, fmt.Errorf("failed to start %s: %w", torrentClient, err)
+	}
+	done := make(chan error, 1)
+	go func() { done <- cmd.Wait() }()
+
+	ticker := time.NewTicker(progressInterval)
+	defer ticker.Stop()
+	var last int64
+	lastGrowth := time.Now()
+	for {
+		select {
+		case err := <-done:
+			if err != nil {
+				return false, fmt.Errorf("%s failed: %w", torrentClient, err)
+			}
+			return false, nil
+		case <-cancelCh:
+			cancel()
+			<-done
+			return false, ErrCancelled
+		case <-ticker.C:
+			if gate.Paused() {
+				cancel()
+				<-done
+				return true, nil
+			}
+			completed := readout.completed.Load()
+			if completed > last {
+				last, lastGrowth = completed, time.Now()
+			} else if time.Since(lastGrowth) > torrentStallTimeout {
+				cancel()
+				<-done
+				return false, fmt.Errorf("%w: no data for %s", errTorrentStalled, torrentStallTimeout)
+			}
+			if progressCb != nil {
+				progressCb(completed, total)
+			}
+		}
+	}
+}
+
+// torrentProgress takes the output of the torrent client and keeps the
+// verified data of its latest readout.
+type torrentProgress struct {
+	completed atomic.Int64
+	line      []byte
+}
+
+func (p *torrentProgress) Write(data []byte) (int, error) {
+	for _, c := range data {
+		if c != '\n' && c != '\r' {
+			p.line = append(p.line, c)
+			continue
+		}
+		if completed, ok := parseTorrentReadout(p.line); ok {
+			p.completed.Store(completed)
+		}
+		p.line = p.line[:0]
+	}
+	return len(data), nil
+}
+
+// parseTorrentReadout returns the verified data of a readout line of the
+// torrent client.
+func parseTorrentReadout(line []byte) (int64, bool) {
+	match := torrentReadout.FindSubmatch(bytes.TrimSpace(line))
+	if match == nil {
+		return 0, false
+	}
+	value, err := strconv.ParseFloat(string(match[1]), 64)
+	if err != nil {
+		return 0, false
+	}
+	switch string(match[2]) {
+	case "Ki":
+		value *= 1 << 10
+	case "Mi":
+		value *= 1 << 20
+	case "Gi":
+		value *= 1 << 30
+	}
+	return int64(value), true
+}
+
+// torrentBytes returns how much data a torrent download holds so far, without
+// the client's control files.
+func torrentBytes(workDir string) int64 {
+	var size int64
+	_ = filepath.WalkDir(workDir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() || strings.HasSuffix(path, ".aria2") {
+			return nil
+		}
+		if info, err := d.Info(); err == nil {
+			size += info.Size()
+		}
+		return nil
+	})
+	return size
+}
+
+// largestFile returns the biggest file below dir, the archive of a torrent
+// that may also carry small extras such as checksums.
+func largestFile(dir string) (string, error) {
+	var largest string
+	var largestSize int64 = -1
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		if info.Size() > largestSize {
+			largest, largestSize = path, info.Size()
+		}
+		return nil
+	})
+	if err != nil {
+		return "", err
+	}
+	if largest == "" {
+		return "", fmt.Errorf("no files in %s", dir)
+	}
+	return largest, nil
+}
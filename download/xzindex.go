@@ -0,0 +1,130 @@
+package download
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+)
+
+// xz container constants, see https://tukaani.org/xz/xz-file-format.txt
+const (
+	xzHeaderSize = 12
+	xzFooterSize = 12
+)
+
+var xzFooterMagic = []byte{'Y', 'Z'}
+
+// xzUncompressedSize returns the total uncompressed size of an .xz file. It is
+// read from the index at the end of each stream, so nothing is decompressed.
+func xzUncompressedSize(path string) (int64, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return 0, err
+	}
+	defer file.Close()
+
+	info, err := file.Stat()
+	if err != nil {
+		return 0, err
+	}
+
+	// Streams may be concatenated and followed by zero padding; walk them from the end
+	var total int64
+	end := info.Size()
+	for end > 0 {
+		var word [4]byte
+		if _, err := file.ReadAt(word[:], end-4); err != nil {
+			return 0, err
+		}
+		if word == [4]byte{} {
+			end -= 4 // Stream padding
+			continue
+		}
+
+		size, streamSize, err := xzStreamSize(file, end)
+		if err != nil {
+			return 0, err
+		}
+		total += size
+		end -= streamSize
+	}
+	if end != 0 {
+		return 0, errors.New("xz: malformed stream layout")
+	}
+	return total, nil
+}
+
+// xzStreamSize reads the index of the stream ending at end, returning its
+// uncompressed size and the stream's length in the file.
+func xzStreamSize(file io.ReaderAt, end int64) (int64, int64, error) {
+	if end < xzHeaderSize+xzFooterSize {
+		return 0, 0, errors.New("xz: file too short")
+	}
+
+	footer := make([]byte, xzFooterSize)
+	if _, err := file.ReadAt(footer, end-xzFooterSize); err != nil {
+		return 0, 0, err
+	}
+	if !bytes.Equal(footer[10:], xzFooterMagic) {
+		return 0, 0, errors.New("xz: missing stream footer")
+	}
+	indexSize := (int64(binary.LittleEndian.Uint32(footer[4:8])) + 1) * 4
+
+	indexStart := end - xzFooterSize - indexSize
+	if indexStart < xzHeaderSize {
+		return 0, 0, errors.New("xz: index out of range")
+	}
+	index := make([]byte, indexSize)
+	if _, err := file.ReadAt(index, indexStart); err != nil {
+		return 0, 0, err
+	}
+	if index[0] != 0 {
+		return 0, 0, errors.New("xz: missing index indicator")
+	}
+
+	r := bytes.NewReader(index[1:])
+	records, err := readXzVarint(r)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	var uncompressed, blocks int64
+	for i := uint64(0); i < records; i++ {
+		unpadded, err := readXzVarint(r)
+		if err != nil {
+			return 0, 0, err
+		}
+		size, err := readXzVarint(r)
+		if err != nil {
+			return 0, 0, err
+		}
+		blocks += (int64(unpadded) + 3) &^ 3 // Blocks are padded to four bytes
+		uncompressed += int64(size)
+	}
+
+	streamSize := xzHeaderSize + blocks + indexSize + xzFooterSize
+	if streamSize > end {
+		return 0, 0, errors.New("xz: index doesn't match file size")
+	}
+	return uncompressed, streamSize, nil
+}
+
+// readXzVarint decodes an xz multibyte integer: 7 bits per byte, least
+// significant first, at most nine bytes.
+func readXzVarint(r io.ByteReader) (uint64, error) {
+	var value uint64
+	for i := 0; i < 9; i++ {
+		b, err := r.ReadByte()
+		if err != nil {
+			return 0, fmt.Errorf("xz: truncated index: %w", err)
+		}
+		value |= uint64(b&0x7f) << (7 * i)
+		if b&0x80 == 0 {
+			return value, nil
+		}
+	}
+	return 0, errors.New("xz: integer too long")
+}
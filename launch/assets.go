@@ -0,0 +1,62 @@
+package launch
+
+import (
+	"fmt"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// AssetLibrariesVar is the variable of an env profile listing asset library
+// directories, separated like PATH. Blender doesn't read it itself, so the
+// libraries are added to its preferences at startup for the session, named
+// after their directory with assetLibrarySuffix.
+const AssetLibrariesVar = "BLENDER_ASSET_LIBRARIES"
+
+// assetLibrarySuffix ends the names of the asset libraries the launcher adds,
+// so they can be told apart from the user's own.
+const assetLibrarySuffix = " (launcher)"
+
+// assetLibrariesScript adds the asset libraries in the Python list of
+// directories it is formatted with that Blender's preferences lack. Blender
+// has no way to add them without changing the preferences, which are saved on
+// quit when changed, so the preferences are left as unchanged as they were.
+// Should they be saved along with other changes anyway, the next launch with
+// libraries removes those added earlier that it doesn't want.
+const assetLibrariesScript = `import bpy, os
+prefs = bpy.context.preferences
+libraries = prefs.filepaths.asset_libraries
+dirty = prefs.is_dirty
+wanted = [os.path.normpath(directory) for directory in [%s]]
+for index in reversed(range(len(libraries))):
+    lib = libraries[index]
+    if lib.name.endswith(%s) and os.path.normpath(bpy.path.abspath(lib.path)) not in wanted:
+        bpy.ops.preferences.asset_library_remove(index=index)
+known = {os.path.normpath(bpy.path.abspath(lib.path)) for lib in libraries}
+for directory in wanted:
+    if directory not in known:
+        bpy.ops.preferences.asset_library_add(directory=directory)
+        libraries[-1].name = os.path.basename(directory) + %[2]s
+        known.add(directory)
+prefs.is_dirty = dirty`
+
+// applyAssetLibraries returns opts with Blender told to add the asset
+// libraries in the AssetLibrariesVar of opts.Env before anything else.
+// Other programs are left alone.
+func applyAssetLibraries(blenderExe string, opts Options) Options {
+	dirs := slices.DeleteFunc(filepath.SplitList(opts.Env[AssetLibrariesVar]), func(dir string) bool {
+		return dir == ""
+	})
+	if len(dirs) == 0 || !isBlender(blenderExe) {
+		return opts
+	}
+	// Go's quoting of ASCII and printable text is valid Python too
+	quoted := make([]string, len(dirs))
+	for i, dir := range dirs {
+		quoted[i] = strconv.Quote(dir)
+	}
+	script := fmt.Sprintf(assetLibrariesScript, strings.Join(quoted, ", "), strconv.Quote(assetLibrarySuffix))
+	opts.Args = append([]string{"--python-expr", script}, opts.Args...)
+	return opts
+}
@@ -0,0 +1,47 @@
+package launch
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+)
+
+// Detached is a Blender process started without a terminal. It runs in its
+// own process group, so it outlives the launcher.
+type Detached struct {
+	cmd     *exec.Cmd
+	logFile *os.File
+	LogPath string // Combined stdout/stderr of the process
+}
+
+// StartDetached starts Blender, or another program shipped with it, in the
+// background as opts say. Its output goes to opts.LogPath, which must be set.
+func StartDetached(blenderExe string, opts Options) (*Detached, error) {
+	cmd, err := Command(blenderExe, opts)
+	if err != nil {
+		return nil, err
+	}
+	logFile, err := CreateLog(opts.LogPath)
+	if err != nil {
+		return nil, err
+	}
+	cmd.Stdout = logFile
+	cmd.Stderr = logFile
+	cmd.SysProcAttr = detachedProcAttr()
+	if err := cmd.Start(); err != nil {
+		logFile.Close()
+		return nil, fmt.Errorf("failed to start Blender: %w", err)
+	}
+	return &Detached{cmd: cmd, logFile: logFile, LogPath: opts.LogPath}, nil
+}
+
+// Pid returns the process ID of the running program.
+func (d *Detached) Pid() int {
+	return d.cmd.Process.Pid
+}
+
+// Wait blocks until the program exits and returns why it failed, if it did.
+func (d *Detached) Wait() error {
+	defer d.logFile.Close()
+	return d.cmd.Wait()
+}
@@ -0,0 +1,12 @@
+//go:build windows
+// +build windows
+
+package launch
+
+import "syscall"
+
+// detachedProcAttr starts a detached process in its own process group, out of
+// reach of the Ctrl+C that stops the launcher.
+func detachedProcAttr() *syscall.SysProcAttr {
+	return &syscall.SysProcAttr{CreationFlags: syscall.CREATE_NEW_PROCESS_GROUP}
+}
@@ -0,0 +1,43 @@
+package launch
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+)
+
+// Display servers a launch can be forced onto (Linux). Blender picks Wayland
+// where it runs and X11 otherwise, and daily builds regularly break under one.
+const (
+	DisplayX11     = "x11"
+	DisplayWayland = "wayland"
+)
+
+// Displays lists the display servers in the order the launch menu cycles through them.
+var Displays = []string{DisplayX11, DisplayWayland}
+
+// ErrUnknownDisplay is returned for a display server that isn't one of the Display values.
+var ErrUnknownDisplay = errors.New("unknown display server")
+
+// displayEnv hides the other display server from the program, so it can only
+// connect to the one asked for.
+var displayEnv = map[string]map[string]string{
+	DisplayX11:     {"WAYLAND_DISPLAY": ""},
+	DisplayWayland: {"DISPLAY": ""},
+}
+
+// applyDisplay returns opts with opts.Env extended by the variables forcing
+// the program onto opts.Display. The variables of the env profile win over these.
+func applyDisplay(opts Options) (Options, error) {
+	if opts.Display == "" {
+		return opts, nil
+	}
+	vars, ok := displayEnv[opts.Display]
+	if !ok {
+		return opts, fmt.Errorf("%w %q", ErrUnknownDisplay, opts.Display)
+	}
+	env := maps.Clone(vars)
+	maps.Copy(env, opts.Env)
+	opts.Env = env
+	return opts, nil
+}
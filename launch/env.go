@@ -0,0 +1,59 @@
+package launch
+
+import (
+	"os"
+	"sort"
+	"strings"
+)
+
+// Environ returns the environment of a process started with the variables of
+// an env profile on top of the launcher's own, or nil for an empty profile so
+// the process inherits the launcher's environment unchanged.
+func Environ(profile map[string]string) []string {
+	if len(profile) == 0 {
+		return nil
+	}
+	env := os.Environ()
+	for _, name := range sortedNames(profile) {
+		env = append(env, name+"="+profile[name])
+	}
+	return env
+}
+
+// sortedNames returns the variable names of profile in order, so commands
+// built from it don't change between runs.
+func sortedNames(profile map[string]string) []string {
+	names := make([]string, 0, len(profile))
+	for name := range profile {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// shellCommand quotes a command line running name with args for a POSIX shell.
+func shellCommand(name string, args []string) string {
+	words := []string{shellQuote(name)}
+	for _, arg := range args {
+		words = append(words, shellQuote(arg))
+	}
+	return strings.Join(words, " ")
+}
+
+// envCommand prefixes command with env setting the variables of profile,
+// for shells that don't start with the launcher's environment.
+func envCommand(profile map[string]string, command string) string {
+	if len(profile) == 0 {
+		return command
+	}
+	vars := make([]string, 0, len(profile))
+	for _, name := range sortedNames(profile) {
+		vars = append(vars, shellQuote(name+"="+profile[name]))
+	}
+	return "env " + strings.Join(vars, " ") + " " + command
+}
+
+// shellQuote quotes s for a POSIX shell.
+func shellQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
@@ -0,0 +1,19 @@
+package launch
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrInUse is returned for removing or replacing a directory a running
+// program was started from.
+var ErrInUse = errors.New("in use by a running program")
+
+// CheckNotInUse returns an error wrapping ErrInUse if a process runs an
+// executable inside dir, so its files aren't pulled from under it.
+func CheckNotInUse(dir string) error {
+	if pids := ProcessesIn(dir); len(pids) > 0 {
+		return fmt.Errorf("%s is %w (PID %d), quit it first", dir, ErrInUse, pids[0])
+	}
+	return nil
+}
@@ -0,0 +1,69 @@
+//go:build linux
+// +build linux
+
+package launch
+
+import (
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// ProcessesIn returns the IDs of the processes running an executable inside
+// dir, e.g. a Blender started from the build in dir. Processes of other users
+// aren't seen.
+func ProcessesIn(dir string) []int {
+	if resolved, err := filepath.EvalSymlinks(dir); err == nil {
+		dir = resolved
+	}
+	entries, err := os.ReadDir("/proc")
+	if err != nil {
+		return nil
+	}
+	prefix := filepath.Clean(dir) + string(os.PathSeparator)
+	var pids []int
+	for _, entry := range entries {
+		pid, err := strconv.Atoi(entry.Name())
+		if err != nil {
+			continue
+		}
+		exe, err := os.Readlink(filepath.Join("/proc", entry.Name(), "exe"))
+		if err == nil && strings.HasPrefix(exe, prefix) {
+			pids = append(pids, pid)
+		}
+	}
+	return pids
+}
+
+// OpenFilesIn returns the paths of the files inside dir that a process holds
+// open, e.g. the log a running Blender's output is copied to. Files opened by
+// processes of other users aren't seen.
+func OpenFilesIn(dir string) map[string]bool {
+	if resolved, err := filepath.EvalSymlinks(dir); err == nil {
+		dir = resolved
+	}
+	entries, err := os.ReadDir("/proc")
+	if err != nil {
+		return nil
+	}
+	prefix := filepath.Clean(dir) + string(os.PathSeparator)
+	open := make(map[string]bool)
+	for _, entry := range entries {
+		if _, err := strconv.Atoi(entry.Name()); err != nil {
+			continue
+		}
+		fdDir := filepath.Join("/proc", entry.Name(), "fd")
+		fds, err := os.ReadDir(fdDir)
+		if err != nil {
+			continue
+		}
+		for _, fd := range fds {
+			target, err := os.Readlink(filepath.Join(fdDir, fd.Name()))
+			if err == nil && strings.HasPrefix(target, prefix) {
+				open[target] = true
+			}
+		}
+	}
+	return open
+}
@@ -0,0 +1,18 @@
+//go:build !linux
+// +build !linux
+
+package launch
+
+// ProcessesIn returns the IDs of the processes running an executable inside
+// dir. Other systems don't tell which executable a process runs as plainly as
+// Linux, so there are none.
+func ProcessesIn(dir string) []int {
+	return nil
+}
+
+// OpenFilesIn returns the paths of the files inside dir that a process holds
+// open. Other systems don't list the open files of processes as plainly as
+// Linux, so there are none.
+func OpenFilesIn(dir string) map[string]bool {
+	return nil
+}
@@ -0,0 +1,255 @@
+package launch
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// JobStatus describes where a queued launch job is in its lifecycle
+type JobStatus int
+
+const (
+	JobPending JobStatus = iota
+	JobRunning
+	JobSucceeded
+	JobFailed
+	JobCancelled
+)
+
+// String returns the string representation of the JobStatus
+func (s JobStatus) String() string {
+	switch s {
+	case JobPending:
+		return "Pending"
+	case JobRunning:
+		return "Running"
+	case JobSucceeded:
+		return "Done"
+	case JobFailed:
+		return "Failed"
+	case JobCancelled:
+		return "Cancelled"
+	default:
+		return "Unknown"
+	}
+}
+
+// Job is a headless Blender run waiting in, or finished by, a JobQueue
+type Job struct {
+	ID         int
+	Version    string   // Blender version the job runs with
+	Executable string   // Path to the Blender executable
+	File       string   // .blend file to open, may be empty
+	Args       []string // Extra arguments passed after the file
+	Options    Options  // How the run is started like a launch, e.g. its working directory and env profile
+	Status     JobStatus
+	LogPath    string // Combined stdout/stderr of the run
+	Frames     int    // Frames the run renders as its arguments tell, 0 if unknown
+	FramesDone int    // Frames Blender saved so far
+	Frame      int    // Frame Blender renders now, if Rendering
+	Rendering  bool   // Whether Blender reported rendering a frame yet
+	Started    time.Time
+	Finished   time.Time
+	Err        error
+
+	cmd *exec.Cmd
+}
+
+// Duration returns how long the job ran, or has been running so far
+func (j Job) Duration() time.Duration {
+	switch {
+	case j.Started.IsZero():
+		return 0
+	case j.Finished.IsZero():
+		return time.Since(j.Started)
+	default:
+		return j.Finished.Sub(j.Started)
+	}
+}
+
+// Progress returns how far the render of a job is, e.g. "12/250" frames, or
+// "" if it renders nothing so far
+func (j Job) Progress() string {
+	switch {
+	case j.Frames > 0 && (j.Rendering || j.FramesDone > 0):
+		return fmt.Sprintf("%d/%d", min(j.FramesDone, j.Frames), j.Frames)
+	case j.Rendering:
+		return fmt.Sprintf("Fra %d", j.Frame)
+	default:
+		return ""
+	}
+}
+
+// JobQueue runs launch jobs one after another so runs don't compete for the machine,
+// which keeps timings comparable across builds.
+type JobQueue struct {
+	mu      sync.Mutex
+	jobs    []*Job
+	nextID  int
+	running bool
+	logDir  string
+}
+
+// NewJobQueue creates a job queue that writes per-job logs into logDir
+func NewJobQueue(logDir string) *JobQueue {
+	return &JobQueue{logDir: logDir, nextID: 1}
+}
+
+// Add queues a headless run started as opts say, like a launch, and starts
+// working through the queue if it was idle. The arguments of opts are
+// replaced by those of the run. It returns the ID of the new job.
+func (q *JobQueue) Add(version, executable, file string, args []string, opts Options) int {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	job := &Job{
+		ID:         q.nextID,
+		Version:    version,
+		Executable: executable,
+		File:       file,
+		Args:       args,
+		Options:    opts,
+		Status:     JobPending,
+		Frames:     renderFrames(args),
+	}
+	q.nextID++
+	q.jobs = append(q.jobs, job)
+
+	if !q.running {
+		q.running = true
+		go q.work()
+	}
+	return job.ID
+}
+
+// Jobs returns a snapshot of all jobs in queue order
+func (q *JobQueue) Jobs() []Job {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	result := make([]Job, len(q.jobs))
+	for i, job := range q.jobs {
+		result[i] = *job
+		result[i].cmd = nil
+	}
+	return result
+}
+
+// Cancel skips a pending job or stops a running one
+func (q *JobQueue) Cancel(id int) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	for _, job := range q.jobs {
+		if job.ID != id {
+			continue
+		}
+		switch job.Status {
+		case JobPending:
+			job.Status = JobCancelled
+		case JobRunning:
+			job.Status = JobCancelled
+			if job.cmd != nil && job.cmd.Process != nil {
+				_ = job.cmd.Process.Kill()
+			}
+		}
+		return
+	}
+}
+
+// work runs pending jobs in order until none are left
+func (q *JobQueue) work() {
+	for {
+		q.mu.Lock()
+		var job *Job
+		for _, j := range q.jobs {
+			if j.Status == JobPending {
+				job = j
+				break
+			}
+		}
+		if job == nil {
+			q.running = false
+			q.mu.Unlock()
+			return
+		}
+		job.Status = JobRunning
+		job.Started = time.Now()
+		q.mu.Unlock()
+
+		err := q.run(job)
+
+		q.mu.Lock()
+		job.Finished = time.Now()
+		job.cmd = nil
+		if job.Status != JobCancelled {
+			if err != nil {
+				job.Status = JobFailed
+				job.Err = err
+			} else {
+				job.Status = JobSucceeded
+			}
+		}
+		q.mu.Unlock()
+	}
+}
+
+// run executes a single job in background mode, logging its output to a file
+func (q *JobQueue) run(job *Job) error {
+	if err := os.MkdirAll(q.logDir, 0750); err != nil {
+		return fmt.Errorf("failed to create log directory: %w", err)
+	}
+
+	logName := fmt.Sprintf("job-%d-blender-%s-%s.log", job.ID, job.Version, job.Started.Format("20060102-150405"))
+	logPath := filepath.Join(q.logDir, logName)
+	logFile, err := os.Create(logPath)
+	if err != nil {
+		return fmt.Errorf("failed to create log file: %w", err)
+	}
+	defer logFile.Close()
+
+	args := []string{"-b"}
+	if job.File != "" {
+		// Relative to where the job was added, not the run's working directory
+		file, err := filepath.Abs(job.File)
+		if err != nil {
+			file = job.File
+		}
+		args = append(args, file)
+	}
+	args = append(args, job.Args...)
+
+	// Started like a launch, so it gets the same env profile, asset libraries and wrappers
+	opts := job.Options
+	opts.Args, opts.LogPath = args, ""
+	cmd, err := Command(job.Executable, opts)
+	if err != nil {
+		return err
+	}
+	output := &progressWriter{queue: q, job: job, log: logFile}
+	cmd.Stdout = output
+	cmd.Stderr = output
+
+	// Start under the lock so Cancel never sees a half-started process
+	q.mu.Lock()
+	job.LogPath = logPath
+	if job.Status == JobCancelled {
+		q.mu.Unlock()
+		return nil
+	}
+	job.cmd = cmd
+	err = cmd.Start()
+	q.mu.Unlock()
+	if err != nil {
+		return fmt.Errorf("failed to start Blender: %w", err)
+	}
+
+	if err := cmd.Wait(); err != nil {
+		return fmt.Errorf("blender exited with error: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,40 @@
+//go:build !windows
+// +build !windows
+
+package launch
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"syscall"
+	"time"
+)
+
+// Terminate asks the processes pids to quit with SIGTERM and kills those
+// still running after grace with SIGKILL, as a Blender hanging on a GPU fault
+// ignores the first. They all get the same grace. A process that is already
+// gone isn't an error.
+func Terminate(pids []int, grace time.Duration) error {
+	var errs []error
+	var running []int
+	for _, pid := range pids {
+		if err := syscall.Kill(pid, syscall.SIGTERM); err == nil {
+			running = append(running, pid)
+		} else if !errors.Is(err, syscall.ESRCH) {
+			errs = append(errs, fmt.Errorf("failed to stop process %d: %w", pid, err))
+		}
+	}
+	for deadline := time.Now().Add(grace); len(running) > 0 && time.Now().Before(deadline); {
+		time.Sleep(100 * time.Millisecond)
+		running = slices.DeleteFunc(running, func(pid int) bool {
+			return syscall.Kill(pid, 0) != nil
+		})
+	}
+	for _, pid := range running {
+		if err := syscall.Kill(pid, syscall.SIGKILL); err != nil && !errors.Is(err, syscall.ESRCH) {
+			errs = append(errs, fmt.Errorf("failed to kill process %d: %w", pid, err))
+		}
+	}
+	return errors.Join(errs...)
+}
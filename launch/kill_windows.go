@@ -0,0 +1,59 @@
+//go:build windows
+// +build windows
+
+package launch
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"strconv"
+	"time"
+)
+
+// Terminate asks the processes pids to quit with taskkill, which closes their
+// windows, and kills those still running after grace, as a Blender hanging on
+// a GPU fault ignores the first. They all get the same grace. A process that
+// is already gone isn't an error.
+func Terminate(pids []int, grace time.Duration) error {
+	type target struct {
+		pid     int
+		process *os.Process
+		exited  chan struct{}
+	}
+	var targets []target
+	for _, pid := range pids {
+		process, err := os.FindProcess(pid)
+		if err != nil {
+			continue
+		}
+		defer process.Release()
+		exited := make(chan struct{})
+		go func() {
+			_, _ = process.Wait()
+			close(exited)
+		}()
+		_ = exec.Command("taskkill", "/PID", strconv.Itoa(pid)).Run()
+		targets = append(targets, target{pid: pid, process: process, exited: exited})
+	}
+
+	deadline := time.Now().Add(grace)
+	var errs []error
+	for _, t := range targets {
+		select {
+		case <-t.exited:
+			continue
+		case <-time.After(time.Until(deadline)):
+		}
+		if err := t.process.Kill(); err != nil {
+			select {
+			case <-t.exited:
+				continue
+			default:
+			}
+			errs = append(errs, fmt.Errorf("failed to kill process %d: %w", t.pid, err))
+		}
+	}
+	return errors.Join(errs...)
+}
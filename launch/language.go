@@ -0,0 +1,25 @@
+package launch
+
+import (
+	"maps"
+	"strings"
+)
+
+// applyLanguage returns opts with opts.Env extended by the locale variables
+// making the program run in opts.Language. Blender follows them while its
+// language preference is Automatic, the default. The variables of the env
+// profile win over these.
+func applyLanguage(opts Options) Options {
+	if opts.Language == "" {
+		return opts
+	}
+	locale := opts.Language
+	if !strings.Contains(locale, ".") {
+		locale += ".UTF-8"
+	}
+	language, _, _ := strings.Cut(opts.Language, ".")
+	env := map[string]string{"LANG": locale, "LC_ALL": locale, "LANGUAGE": language}
+	maps.Copy(env, opts.Env)
+	opts.Env = env
+	return opts
+}
@@ -8,10 +8,34 @@ import (
 	"os/exec"
 )
 
-// BlenderInNewTerminal launches Blender in a new terminal window (macOS-specific)
-func BlenderInNewTerminal(blenderExe string) error {
+// BlenderInNewTerminal launches Blender, or another program shipped with it,
+// in a new terminal window as opts say (macOS-specific)
+func BlenderInNewTerminal(blenderExe string, opts Options) error {
+	if err := createLogDir(opts.LogPath); err != nil {
+		return err
+	}
+
+	name, args, opts, err := prepare(blenderExe, opts)
+	if err != nil {
+		return err
+	}
+
 	cmd := exec.Command("open", "-a", "Terminal", blenderExe)
-	err := cmd.Start()
+	if opts.WorkDir != "" || len(opts.Env) > 0 || len(args) > 0 || opts.LogPath != "" || name != blenderExe {
+		// Terminal starts its shell in the home directory with its own
+		// environment, so change there and set the variables first
+		command := envCommand(opts.Env, shellCommand(name, args))
+		script := "exec " + command
+		if opts.LogPath != "" {
+			// The output stays in the terminal too, the log outlives it
+			script = command + " 2>&1 | tee " + shellQuote(opts.LogPath)
+		}
+		if opts.WorkDir != "" {
+			script = fmt.Sprintf("cd %s && %s", shellQuote(opts.WorkDir), script)
+		}
+		cmd = exec.Command("osascript", "-e", fmt.Sprintf("tell application \"Terminal\" to do script %q", script))
+	}
+	err = cmd.Start()
 	if err != nil {
 		return fmt.Errorf("failed to launch Blender in new terminal: %w", err)
 	}
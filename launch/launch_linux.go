@@ -5,25 +5,51 @@ package launch
 
 import (
 	"fmt"
+	"os"
 	"os/exec"
 	"syscall"
 )
 
-// BlenderInNewTerminal launches Blender in a new terminal window (Linux-specific)
-func BlenderInNewTerminal(blenderExe string) error {
+// BlenderInNewTerminal launches Blender, or another program shipped with it,
+// in a new terminal window as opts say (Linux-specific)
+func BlenderInNewTerminal(blenderExe string, opts Options) error {
+	// Otherwise every terminal would fail to start, hiding the actual problem
+	if opts.WorkDir != "" {
+		if _, err := os.Stat(opts.WorkDir); err != nil {
+			return fmt.Errorf("invalid working directory: %w", err)
+		}
+	}
+	if err := createLogDir(opts.LogPath); err != nil {
+		return err
+	}
+
+	name, args, opts, err := prepare(blenderExe, opts)
+	if err != nil {
+		return err
+	}
+
+	script := "exec " + shellCommand(name, args)
+	direct := append(append([]string{"-e", "nohup", name}, args...), "&")
+	if opts.LogPath != "" {
+		// The output stays in the terminal too, the log outlives it
+		script = shellCommand(name, args) + " 2>&1 | tee " + shellQuote(opts.LogPath)
+		direct = []string{"-e", "bash", "-c", script}
+	}
 	terminals := []struct {
 		name string
 		args []string
 	}{
-		{"x-terminal-emulator", []string{"-e", "nohup", blenderExe, "&"}},
-		{"gnome-terminal", []string{"--", "bash", "-c", "exec " + blenderExe}},
-		{"alacritty", []string{"-e", "bash", "-c", "exec " + blenderExe}},
-		{"xterm", []string{"-e", "bash", "-c", "exec " + blenderExe}},
-		{"konsole", []string{"-e", "bash", "-c", "exec " + blenderExe}},
+		{"x-terminal-emulator", direct},
+		{"gnome-terminal", []string{"--", "bash", "-c", script}},
+		{"alacritty", []string{"-e", "bash", "-c", script}},
+		{"xterm", []string{"-e", "bash", "-c", script}},
+		{"konsole", []string{"-e", "bash", "-c", script}},
 	}
 
 	for _, term := range terminals {
 		cmd := exec.Command(term.name, term.args...)
+		cmd.Dir = opts.WorkDir
+		cmd.Env = Environ(opts.Env)
 		cmd.SysProcAttr = &syscall.SysProcAttr{
 			Setpgid: true,
 		}
@@ -5,15 +5,73 @@ package launch
 
 import (
 	"fmt"
+	"os"
 	"os/exec"
+	"path/filepath"
+	"strings"
+	"syscall"
 )
 
-// BlenderInNewTerminal launches Blender in a new terminal window (Windows-specific)
-func BlenderInNewTerminal(blenderExe string) error {
-	cmd := exec.Command("cmd", "/C", "start", "", blenderExe, "-con")
-	err := cmd.Start()
+// createNewConsole is the CREATE_NEW_CONSOLE process creation flag, which
+// syscall doesn't define.
+const createNewConsole = 0x00000010
+
+// BlenderInNewTerminal launches Blender, or another program shipped with it,
+// in a new console window as opts say (Windows-specific). The console is the
+// system's default terminal: conhost, or Windows Terminal where it's set as
+// the default like on Windows 11. Blender is started directly rather than
+// through cmd's start, which garbles paths and arguments holding & or ^.
+func BlenderInNewTerminal(blenderExe string, opts Options) error {
+	cmd, err := newConsoleCommand(blenderExe, opts)
 	if err != nil {
+		return err
+	}
+	if err := cmd.Start(); err != nil {
 		return fmt.Errorf("failed to launch Blender in new terminal: %w", err)
 	}
+	cmd.Process.Release()
 	return nil
 }
+
+// newConsoleCommand returns the command starting blenderExe in a new console
+// window as opts say, through PowerShell when its output goes to a log.
+func newConsoleCommand(blenderExe string, opts Options) (*exec.Cmd, error) {
+	// Otherwise the error would blame the program
+	if opts.WorkDir != "" {
+		if _, err := os.Stat(opts.WorkDir); err != nil {
+			return nil, fmt.Errorf("invalid working directory: %w", err)
+		}
+	}
+	if err := createLogDir(opts.LogPath); err != nil {
+		return nil, err
+	}
+
+	// Only Blender itself knows -con, which keeps its console attached
+	if name := strings.ToLower(filepath.Base(blenderExe)); strings.HasPrefix(name, "blender") && strings.HasSuffix(name, ".exe") {
+		opts.Args = append([]string{"-con"}, opts.Args...)
+	}
+	name, args, opts, err := prepare(blenderExe, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	cmd := exec.Command(name, args...)
+	if opts.LogPath != "" {
+		// There is no tee, PowerShell copies the output to the log
+		words := []string{"&", powerShellQuote(name)}
+		for _, arg := range args {
+			words = append(words, powerShellQuote(arg))
+		}
+		script := strings.Join(words, " ") + " 2>&1 | Tee-Object -FilePath " + powerShellQuote(opts.LogPath)
+		cmd = exec.Command("powershell", "-NoProfile", "-Command", script)
+	}
+	cmd.Dir = opts.WorkDir
+	cmd.Env = Environ(opts.Env)
+	cmd.SysProcAttr = &syscall.SysProcAttr{CreationFlags: createNewConsole}
+	return cmd, nil
+}
+
+// powerShellQuote quotes s for PowerShell.
+func powerShellQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", "''") + "'"
+}
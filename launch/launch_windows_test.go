@@ -0,0 +1,68 @@
+//go:build windows
+// +build windows
+
+package launch
+
+import (
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestNewConsoleCommand(t *testing.T) {
+	dir := t.TempDir()
+	blender := filepath.Join(dir, "blender.exe")
+	python := filepath.Join(dir, "4.4", "python", "bin", "python.exe")
+	logPath := filepath.Join(dir, "logs", "blender-4.4.1.log")
+
+	tests := []struct {
+		name     string
+		exe      string
+		opts     Options
+		wantArgs []string // Arguments of the command, after the program
+		wantLog  string   // Part of the PowerShell script, "" to start the program directly
+	}{
+		{"blender keeps its console", blender, Options{Args: []string{"scene & co.blend"}},
+			[]string{"-con", "scene & co.blend"}, ""},
+		{"other programs get no -con", python, Options{Args: []string{"-m", "pip"}},
+			[]string{"-m", "pip"}, ""},
+		{"output copied to the log", blender, Options{Args: []string{"it's.blend"}, LogPath: logPath},
+			[]string{"-NoProfile", "-Command"},
+			"& '" + blender + "' '-con' 'it''s.blend' 2>&1 | Tee-Object -FilePath '" + logPath + "'"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.opts.WorkDir = dir
+			cmd, err := newConsoleCommand(tt.exe, tt.opts)
+			if err != nil {
+				t.Fatalf("newConsoleCommand returned an error: %v", err)
+			}
+			if cmd.Dir != dir {
+				t.Errorf("Expected the command to run in %s, got %s", dir, cmd.Dir)
+			}
+			if cmd.SysProcAttr == nil || cmd.SysProcAttr.CreationFlags&createNewConsole == 0 {
+				t.Error("Expected the command to get a new console")
+			}
+
+			if tt.wantLog == "" {
+				if cmd.Args[0] != tt.exe || !slices.Equal(cmd.Args[1:], tt.wantArgs) {
+					t.Errorf("Expected %s %q, got %q", tt.exe, tt.wantArgs, cmd.Args)
+				}
+				return
+			}
+			if cmd.Args[0] != "powershell" || !slices.Equal(cmd.Args[1:3], tt.wantArgs) {
+				t.Errorf("Expected powershell %q, got %q", tt.wantArgs, cmd.Args)
+			}
+			if script := cmd.Args[len(cmd.Args)-1]; !strings.Contains(script, tt.wantLog) {
+				t.Errorf("Expected the script to hold %q, got %q", tt.wantLog, script)
+			}
+		})
+	}
+
+	// A missing working directory is reported as such rather than blaming Blender
+	if _, err := newConsoleCommand(blender, Options{WorkDir: filepath.Join(dir, "missing")}); err == nil ||
+		!strings.Contains(err.Error(), "invalid working directory") {
+		t.Errorf("Expected the missing working directory reported, got %v", err)
+	}
+}
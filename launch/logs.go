@@ -0,0 +1,97 @@
+package launch
+
+import (
+	"TUI-Blender-Launcher/config"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// LaunchLogsKept is how many logs of launched Blender sessions RotateLogs keeps.
+const LaunchLogsKept = 20
+
+// LogDir returns where job logs and logs of launched Blender sessions go, in
+// the state directory, or the temp dir without one.
+func LogDir() string {
+	if stateDir, err := config.GetStateDir(); err == nil {
+		return filepath.Join(stateDir, "logs")
+	}
+	return filepath.Join(os.TempDir(), config.AppName, "logs")
+}
+
+// LogPath returns the path of the log of a session of Blender version
+// launched at the given time, in logDir. The time goes down to nanoseconds, so
+// sessions launched in the same second don't share a log.
+func LogPath(logDir, version string, at time.Time) string {
+	return filepath.Join(logDir, fmt.Sprintf("blender-%s-%s.log", version, at.Format("20060102-150405.000000000")))
+}
+
+// RotateLogs deletes all but the newest keep session logs in logDir. Logs of
+// queued jobs are left alone, and so are logs still open, as those of
+// sessions that are running.
+func RotateLogs(logDir string, keep int) error {
+	entries, err := os.ReadDir(logDir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return fmt.Errorf("failed to read %s: %w", logDir, err)
+	}
+
+	type sessionLog struct {
+		path    string
+		modTime time.Time
+	}
+	var logs []sessionLog
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || !strings.HasPrefix(name, "blender-") || !strings.HasSuffix(name, ".log") {
+			continue
+		}
+		if info, err := entry.Info(); err == nil {
+			logs = append(logs, sessionLog{path: filepath.Join(logDir, name), modTime: info.ModTime()})
+		}
+	}
+	if len(logs) <= keep {
+		return nil
+	}
+	sort.Slice(logs, func(i, j int) bool {
+		return logs[i].modTime.After(logs[j].modTime)
+	})
+	open := OpenFilesIn(logDir)
+	for _, log := range logs[keep:] {
+		if resolved, err := filepath.EvalSymlinks(log.path); err == nil && open[resolved] {
+			continue
+		}
+		if err := os.Remove(log.path); err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("failed to remove old log: %w", err)
+		}
+	}
+	return nil
+}
+
+// CreateLog creates the log file at path and its directory.
+func CreateLog(path string) (*os.File, error) {
+	if err := createLogDir(path); err != nil {
+		return nil, err
+	}
+	logFile, err := os.Create(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create log file: %w", err)
+	}
+	return logFile, nil
+}
+
+// createLogDir creates the directory of the log at path, if there is one.
+func createLogDir(path string) error {
+	if path == "" {
+		return nil
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0750); err != nil {
+		return fmt.Errorf("failed to create log directory: %w", err)
+	}
+	return nil
+}
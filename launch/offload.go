@@ -0,0 +1,75 @@
+package launch
+
+import (
+	"TUI-Blender-Launcher/config"
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"os/exec"
+	"slices"
+)
+
+// ErrUnknownOffload is returned for a GPU offload method that isn't one of
+// config.OffloadMethods.
+var ErrUnknownOffload = errors.New("unknown GPU offload")
+
+// offload is how a method starts a program on the discrete GPU.
+type offload struct {
+	wrapper []string          // Command the program runs through
+	env     map[string]string // Variables set for the program
+}
+
+var offloads = map[string]offload{
+	config.OffloadPrimeRun: {wrapper: []string{"prime-run"}},
+	config.OffloadDRIPrime: {env: map[string]string{"DRI_PRIME": "1"}},
+	config.OffloadNvidia: {env: map[string]string{
+		"__NV_PRIME_RENDER_OFFLOAD": "1",
+		"__GLX_VENDOR_LIBRARY_NAME": "nvidia",
+		"__VK_LAYER_NV_optimus":     "NVIDIA_only",
+	}},
+	config.OffloadSwitcheroo: {wrapper: []string{"switcherooctl", "launch"}},
+}
+
+// applyOffload returns the program and arguments running name with args on
+// the discrete GPU as opts.Offload says, with opts.Env extended by what the
+// method needs. The variables of the env profile win over those of the offload.
+func applyOffload(name string, args []string, opts Options) (string, []string, Options, error) {
+	if opts.Offload == "" {
+		return name, args, opts, nil
+	}
+	method, ok := offloads[opts.Offload]
+	if !ok {
+		return "", nil, opts, fmt.Errorf("%w %q", ErrUnknownOffload, opts.Offload)
+	}
+
+	env := maps.Clone(method.env)
+	if env == nil {
+		env = make(map[string]string, len(opts.Env))
+	}
+	maps.Copy(env, opts.Env)
+	opts.Env = env
+	if len(method.wrapper) == 0 {
+		return name, args, opts, nil
+	}
+	wrapped := append(slices.Clone(method.wrapper[1:]), name)
+	return method.wrapper[0], append(wrapped, args...), opts, nil
+}
+
+// Command returns the command running blenderExe in the foreground as opts
+// say, for callers wiring up its input and output themselves.
+func Command(blenderExe string, opts Options) (*exec.Cmd, error) {
+	return CommandContext(context.Background(), blenderExe, opts)
+}
+
+// CommandContext is like Command, the program being killed once ctx is done.
+func CommandContext(ctx context.Context, blenderExe string, opts Options) (*exec.Cmd, error) {
+	name, args, opts, err := prepare(blenderExe, opts)
+	if err != nil {
+		return nil, err
+	}
+	cmd := exec.CommandContext(ctx, name, args...)
+	cmd.Dir = opts.WorkDir
+	cmd.Env = Environ(opts.Env)
+	return cmd, nil
+}
@@ -0,0 +1,191 @@
+package launch
+
+import (
+	"TUI-Blender-Launcher/config"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// ErrBadTemplate is returned for a launch command template that can't be expanded.
+var ErrBadTemplate = errors.New("bad launch command template")
+
+// ErrNoSandbox is returned for launching a build that must be sandboxed
+// without a sandbox command to do it with.
+var ErrNoSandbox = errors.New("no sandbox command set")
+
+// Options are how BlenderInNewTerminal and StartDetached start a program.
+type Options struct {
+	WorkDir    string            // Working directory, the launcher's own if empty
+	Env        map[string]string // Variables set on top of the launcher's environment
+	Args       []string          // Arguments passed to the program
+	LogPath    string            // File the program's output is copied to, none if empty
+	Offload    string            // Method moving the program to the discrete GPU, one of the Offload values or none if empty
+	Language   string            // Locale the program runs in, e.g. "fr_FR", the launcher's own if empty
+	Display    string            // Display server the program is forced onto, one of the Display values or its own choice if empty
+	PythonPath []string          // Directories of Python modules the program's Python imports from, before those of the env profile
+
+	// Command line the program is started with, e.g. "gamemoderun {exe} {args}",
+	// the program and its arguments alone if empty. See expandTemplate.
+	Template string
+
+	// Command line confining the program, e.g. "firejail --whitelist={project}
+	// {exe} {args}", inside Template. None if empty. See expandTemplate.
+	Sandbox  string
+	BuildDir string // Directory of the build the program ships with, that of the program if empty
+	Project  string // Directory the sandbox lets the program write to, WorkDir or a scratch directory in the state directory if empty
+}
+
+// prepare returns the program and arguments starting blenderExe as opts say,
+// and opts with the variables the GPU offload, language, display server and
+// Python path need added, and the asset libraries of the env profile.
+func prepare(blenderExe string, opts Options) (string, []string, Options, error) {
+	opts, err := applyDisplay(applyLanguage(applyPythonPath(blenderExe, applyAssetLibraries(blenderExe, opts))))
+	if err != nil {
+		return "", nil, opts, err
+	}
+	name, args, opts, err := applyOffload(blenderExe, opts.Args, opts)
+	if err != nil {
+		return "", nil, opts, err
+	}
+
+	dirs := map[string]string{"{build}": opts.BuildDir, "{project}": opts.Project}
+	if dirs["{build}"] == "" {
+		dirs["{build}"] = filepath.Dir(blenderExe)
+	}
+	if dirs["{project}"] == "" {
+		dirs["{project}"] = opts.WorkDir
+	}
+	switch {
+	case dirs["{project}"] != "":
+	case opts.Sandbox != "":
+		// Opening no file in particular, the sandbox must not open up the
+		// launcher's working directory, often home
+		if dirs["{project}"], err = scratchDir(); err != nil {
+			return "", nil, opts, err
+		}
+		opts.WorkDir = dirs["{project}"]
+	default:
+		if dirs["{project}"], err = os.Getwd(); err != nil {
+			return "", nil, opts, fmt.Errorf("failed to get the working directory: %w", err)
+		}
+	}
+	for _, template := range []string{opts.Sandbox, opts.Template} {
+		if template == "" {
+			continue
+		}
+		words, err := expandTemplate(template, name, args, dirs)
+		if err != nil {
+			return "", nil, opts, err
+		}
+		name, args = words[0], words[1:]
+	}
+	return name, args, opts, nil
+}
+
+// scratchDir returns the directory, in the state directory, a sandboxed
+// program without a project may write to, creating it if needed.
+func scratchDir() (string, error) {
+	stateDir, err := config.GetStateDir()
+	if err != nil {
+		return "", err
+	}
+	dir := filepath.Join(stateDir, "sandbox")
+	if err := os.MkdirAll(dir, 0750); err != nil {
+		return "", fmt.Errorf("failed to create the sandbox scratch directory: %w", err)
+	}
+	return dir, nil
+}
+
+// expandTemplate splits template into words like a POSIX shell and fills in
+// the program: a word that is just {exe} becomes name and one that is just
+// {args} becomes args, so they reach the wrapper as they are. Inside other
+// words, e.g. the command of nix-shell --run '{exe} {args}', they are filled
+// in quoted for a shell. The directories in dirs, e.g. {project}, are quoted
+// inside such words alone, so flags like --bind={project} get them as they are.
+func expandTemplate(template, name string, args []string, dirs map[string]string) ([]string, error) {
+	words, err := splitWords(template)
+	if err != nil {
+		return nil, err
+	}
+	quotedArgs := make([]string, len(args))
+	for i, arg := range args {
+		quotedArgs[i] = shellQuote(arg)
+	}
+
+	var expanded []string
+	hasExe := false
+	for _, word := range words {
+		switch {
+		case word == "{exe}":
+			expanded = append(expanded, name)
+			hasExe = true
+		case word == "{args}":
+			expanded = append(expanded, args...)
+		case !strings.Contains(word, "{exe}") && !strings.Contains(word, "{args}"):
+			// Not a command line, e.g. --whitelist={project}
+			for placeholder, dir := range dirs {
+				word = strings.ReplaceAll(word, placeholder, dir)
+			}
+			expanded = append(expanded, word)
+		default:
+			hasExe = hasExe || strings.Contains(word, "{exe}")
+			word = strings.ReplaceAll(word, "{exe}", shellQuote(name))
+			for placeholder, dir := range dirs {
+				word = strings.ReplaceAll(word, placeholder, shellQuote(dir))
+			}
+			expanded = append(expanded, strings.ReplaceAll(word, "{args}", strings.Join(quotedArgs, " ")))
+		}
+	}
+	if !hasExe {
+		return nil, fmt.Errorf("%w: %q has no {exe}", ErrBadTemplate, template)
+	}
+	return expanded, nil
+}
+
+// splitWords splits s into words at unquoted whitespace, removing the single
+// and double quotes and backslashes that quote the rest.
+func splitWords(s string) ([]string, error) {
+	var words []string
+	var word strings.Builder
+	inWord := false
+	var quote rune
+	escaped := false
+	for _, r := range s {
+		switch {
+		case escaped:
+			word.WriteRune(r)
+			escaped = false
+		case quote != 0:
+			if r == quote {
+				quote = 0
+			} else if r == '\\' && quote == '"' {
+				escaped = true
+			} else {
+				word.WriteRune(r)
+			}
+		case r == '\'' || r == '"':
+			quote, inWord = r, true
+		case r == '\\':
+			escaped, inWord = true, true
+		case r == ' ' || r == '\t' || r == '\n':
+			if inWord {
+				words = append(words, word.String())
+				word.Reset()
+				inWord = false
+			}
+		default:
+			word.WriteRune(r)
+			inWord = true
+		}
+	}
+	if quote != 0 || escaped {
+		return nil, fmt.Errorf("%w: unterminated quote in %q", ErrBadTemplate, s)
+	}
+	if inWord {
+		words = append(words, word.String())
+	}
+	return words, nil
+}
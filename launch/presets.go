@@ -0,0 +1,17 @@
+package launch
+
+// Preset is a way of starting Blender, picked when launching it.
+type Preset struct {
+	Name string
+	Args []string // Arguments passed before the file to open
+}
+
+// Presets are the ways of starting Blender offered when launching it, the
+// plain one first.
+var Presets = []Preset{
+	{Name: "Normal"},
+	{Name: "Factory startup", Args: []string{"--factory-startup"}},
+	{Name: "Debug all", Args: []string{"--debug-all"}},
+	{Name: "Python console", Args: []string{"--python-console"}},
+	{Name: "Background", Args: []string{"-b"}},
+}
@@ -0,0 +1,44 @@
+package launch
+
+import (
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+// pythonUseSystemEnv makes Blender's Python follow PYTHONPATH, which it
+// ignores otherwise.
+const pythonUseSystemEnv = "--python-use-system-env"
+
+// applyPythonPath returns opts with PYTHONPATH set to opts.PythonPath followed
+// by the PYTHONPATH of the env profile, if either is set. Blender itself, unlike
+// the Python it bundles, is also told to follow PYTHONPATH.
+func applyPythonPath(blenderExe string, opts Options) Options {
+	profilePath, ok := opts.Env["PYTHONPATH"]
+	if len(opts.PythonPath) == 0 && !ok {
+		return opts
+	}
+	dirs := slices.Clone(opts.PythonPath)
+	if profilePath != "" {
+		dirs = append(dirs, profilePath)
+	}
+	env := maps.Clone(opts.Env)
+	if env == nil {
+		env = make(map[string]string, 1)
+	}
+	env["PYTHONPATH"] = strings.Join(dirs, string(os.PathListSeparator))
+	opts.Env = env
+
+	if isBlender(blenderExe) && !slices.Contains(opts.Args, pythonUseSystemEnv) {
+		opts.Args = append([]string{pythonUseSystemEnv}, opts.Args...)
+	}
+	return opts
+}
+
+// isBlender tells whether exe is Blender itself rather than another program
+// shipped with it, such as its Python.
+func isBlender(exe string) bool {
+	return strings.TrimSuffix(strings.ToLower(filepath.Base(exe)), ".exe") == "blender"
+}
@@ -0,0 +1,150 @@
+package launch
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// framePattern matches the frame Blender reports while rendering, e.g.
+// "Fra:12 Mem:210.45M (Peak 230.11M) | Time:00:03.21 | Rendering 5 / 64 samples".
+var framePattern = regexp.MustCompile(`^Fra:(\d+) `)
+
+// RenderArgs returns the arguments after the file that render frames start
+// to end in the background: the scene's frame range if both are 0, the one
+// frame if they are equal.
+func RenderArgs(start, end int) []string {
+	switch {
+	case start == 0 && end == 0:
+		return []string{"-a"}
+	case start == end:
+		return []string{"-f", strconv.Itoa(start)}
+	default:
+		return []string{"-s", strconv.Itoa(start), "-e", strconv.Itoa(end), "-a"}
+	}
+}
+
+// ParseFrameRange parses a frame range like "1-250" or a single frame like
+// "12" into its first and last frame.
+func ParseFrameRange(s string) (int, int, error) {
+	first, last, isRange := strings.Cut(strings.TrimSpace(s), "-")
+	start, err := strconv.Atoi(first)
+	if err != nil || start < 0 {
+		return 0, 0, fmt.Errorf("invalid frame range %q", s)
+	}
+	if !isRange {
+		return start, start, nil
+	}
+	end, err := strconv.Atoi(last)
+	if err != nil || end < start {
+		return 0, 0, fmt.Errorf("invalid frame range %q", s)
+	}
+	return start, end, nil
+}
+
+// renderFrames returns how many frames Blender renders with args as passed
+// after the file, 0 if it renders none or the scene's frame range decides.
+func renderFrames(args []string) int {
+	start, end, step := -1, -1, 1
+	animation, frames := false, 0
+	value := func(i int) int {
+		if i+1 >= len(args) {
+			return -1
+		}
+		n, err := strconv.Atoi(args[i+1])
+		if err != nil {
+			return -1
+		}
+		return n
+	}
+loop:
+	for i, arg := range args {
+		switch arg {
+		case "--":
+			break loop // Arguments for Python scripts follow
+		case "-s", "--frame-start":
+			start = value(i)
+		case "-e", "--frame-end":
+			end = value(i)
+		case "-j", "--frame-jump":
+			step = max(value(i), 1)
+		case "-a", "--render-anim":
+			animation = true
+		case "-f", "--render-frame":
+			if i+1 < len(args) {
+				frames += countFrames(args[i+1])
+			}
+		}
+	}
+	if animation && start >= 0 && end >= start {
+		frames += (end-start)/step + 1
+	}
+	return frames
+}
+
+// countFrames returns how many frames a -f argument like "1,3,10..20" names.
+func countFrames(list string) int {
+	count := 0
+	for _, part := range strings.Split(list, ",") {
+		first, last, isRange := strings.Cut(part, "..")
+		if !isRange {
+			count++
+			continue
+		}
+		start, err1 := strconv.Atoi(first)
+		end, err2 := strconv.Atoi(last)
+		if err1 != nil || err2 != nil || end < start {
+			count++
+			continue
+		}
+		count += end - start + 1
+	}
+	return count
+}
+
+// progressWriter copies a job's output to its log, following the frame it
+// renders and counting the frames Blender saved.
+type progressWriter struct {
+	queue *JobQueue
+	job   *Job
+	log   io.Writer
+	line  []byte // Output since the last newline
+}
+
+// Write copies p to the log and updates the job's progress from its lines.
+func (w *progressWriter) Write(p []byte) (int, error) {
+	n, err := w.log.Write(p)
+	w.line = append(w.line, p...)
+	for {
+		end := bytes.IndexByte(w.line, '\n')
+		if end < 0 {
+			break
+		}
+		w.follow(string(bytes.TrimRight(w.line[:end], "\r")))
+		w.line = w.line[end+1:]
+	}
+	return n, err
+}
+
+// follow updates the job's progress from one line of its output.
+func (w *progressWriter) follow(line string) {
+	frame := -1
+	if match := framePattern.FindStringSubmatch(line); match != nil {
+		frame, _ = strconv.Atoi(match[1])
+	}
+	saved := strings.HasPrefix(line, "Saved: ")
+	if frame < 0 && !saved {
+		return
+	}
+	w.queue.mu.Lock()
+	defer w.queue.mu.Unlock()
+	if frame >= 0 {
+		w.job.Frame, w.job.Rendering = frame, true
+	}
+	if saved {
+		w.job.FramesDone++
+	}
+}
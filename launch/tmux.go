@@ -0,0 +1,45 @@
+package launch
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+)
+
+// InTmux reports whether the launcher runs inside a tmux session, where
+// BlenderInTmux can open a window.
+func InTmux() bool {
+	return os.Getenv("TMUX") != ""
+}
+
+// BlenderInTmux launches Blender, or another program shipped with it, as
+// opts say in a new window named title of the tmux session the launcher runs
+// in. The window shows its console output and closes when it exits.
+func BlenderInTmux(blenderExe, title string, opts Options) error {
+	if err := createLogDir(opts.LogPath); err != nil {
+		return err
+	}
+	name, args, opts, err := prepare(blenderExe, opts)
+	if err != nil {
+		return err
+	}
+
+	// The window starts with the environment of the tmux server, not the
+	// launcher's, so the variables are set in the command
+	command := envCommand(opts.Env, shellCommand(name, args))
+	script := "exec " + command
+	if opts.LogPath != "" {
+		// The output stays in the window too, the log outlives it
+		script = command + " 2>&1 | tee " + shellQuote(opts.LogPath)
+	}
+	tmuxArgs := []string{"new-window", "-n", title}
+	if opts.WorkDir != "" {
+		tmuxArgs = append(tmuxArgs, "-c", opts.WorkDir)
+	}
+	out, err := exec.Command("tmux", append(tmuxArgs, script)...).CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("failed to open a tmux window: %w: %s", err, strings.TrimSpace(string(out)))
+	}
+	return nil
+}
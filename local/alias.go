@@ -0,0 +1,105 @@
+package local
+
+import (
+	"TUI-Blender-Launcher/model"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Built-in aliases understood by ResolveBuild
+const (
+	AliasLatest      = "latest"       // The newest build
+	AliasStable      = "stable"       // The newest stable release
+	AliasLTS         = "lts"          // The newest stable release of a long-term support series
+	AliasLatestDaily = "latest-daily" // The newest build that isn't a stable release
+)
+
+// ltsSeries are the Blender series with long-term support.
+var ltsSeries = map[string]bool{"2.83": true, "2.93": true, "3.3": true, "3.6": true, "4.2": true, "4.5": true}
+
+// ResolveBuild returns the build among builds that name refers to: an exact
+// version, the newest build of a major.minor series (e.g. "4.2"), a built-in
+// alias or one of the user's aliases, which name any of these including other
+// aliases. User aliases take precedence, so a built-in one can be pinned.
+func ResolveBuild(builds []model.BlenderBuild, name string, aliases map[string]string) (model.BlenderBuild, error) {
+	seen := make(map[string]bool)
+	for {
+		target, ok := aliases[name]
+		if !ok {
+			break
+		}
+		if seen[name] {
+			return model.BlenderBuild{}, fmt.Errorf("alias %s refers to itself", name)
+		}
+		seen[name] = true
+		name = target
+	}
+
+	var match func(model.BlenderBuild) bool
+	switch name {
+	case AliasLatest:
+		match = func(model.BlenderBuild) bool { return true }
+	case AliasStable:
+		match = isStable
+	case AliasLTS:
+		match = func(b model.BlenderBuild) bool { return isStable(b) && ltsSeries[model.VersionSeries(b.Version)] }
+	case AliasLatestDaily:
+		match = func(b model.BlenderBuild) bool { return !isStable(b) }
+	default:
+		for _, build := range builds {
+			if build.Version == name {
+				return build, nil
+			}
+		}
+		match = func(b model.BlenderBuild) bool { return model.VersionSeries(b.Version) == name }
+	}
+
+	var newest *model.BlenderBuild
+	for i := range builds {
+		if match(builds[i]) && (newest == nil || isNewer(builds[i], *newest)) {
+			newest = &builds[i]
+		}
+	}
+	if newest == nil {
+		return model.BlenderBuild{}, fmt.Errorf("blender version %s: %w", name, ErrBuildNotFound)
+	}
+	return *newest, nil
+}
+
+// isStable reports whether build is a stable release rather than a daily build
+// of an alpha, beta or release candidate.
+func isStable(build model.BlenderBuild) bool {
+	return build.ReleaseCycle == "stable"
+}
+
+// isNewer reports whether a is a newer build than b: a higher version, or the
+// same version built later.
+func isNewer(a, b model.BlenderBuild) bool {
+	if c := compareVersions(a.Version, b.Version); c != 0 {
+		return c > 0
+	}
+	return a.BuildDate.Time().After(b.BuildDate.Time())
+}
+
+// compareVersions compares dotted version numbers numerically, e.g. 4.10.0
+// is newer than 4.9.1. It returns -1, 0 or 1.
+func compareVersions(a, b string) int {
+	aParts, bParts := strings.Split(a, "."), strings.Split(b, ".")
+	for i := 0; i < max(len(aParts), len(bParts)); i++ {
+		var aNum, bNum int
+		if i < len(aParts) {
+			aNum, _ = strconv.Atoi(aParts[i])
+		}
+		if i < len(bParts) {
+			bNum, _ = strconv.Atoi(bParts[i])
+		}
+		if aNum != bNum {
+			if aNum > bNum {
+				return 1
+			}
+			return -1
+		}
+	}
+	return 0
+}
@@ -0,0 +1,71 @@
+package local
+
+import (
+	"TUI-Blender-Launcher/launch"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// ErrBuildDoesNotRun is returned when a build's executable fails its test run.
+var ErrBuildDoesNotRun = errors.New("build does not run")
+
+// checkRunTimeout bounds how long the test run of a build may take. A first
+// run can be slow while the system loads its libraries from a cold disk.
+const checkRunTimeout = 60 * time.Second
+
+// missingLibraryPattern matches the dynamic loader's report of a missing
+// shared library, e.g. "error while loading shared libraries: libXi.so.6".
+var missingLibraryPattern = regexp.MustCompile(`error while loading shared libraries: ([^:\s]+)`)
+
+// CheckBuildRuns starts the Blender executable exe in the background with its
+// factory settings and has it print its version, which catches missing
+// libraries, builds for another architecture and damaged binaries before the
+// build is needed. The error tells why the build doesn't run. opts wrap the
+// run the way the build is launched, e.g. in its sandbox.
+func CheckBuildRuns(exe string, opts launch.Options) error {
+	ctx, cancel := context.WithTimeout(context.Background(), checkRunTimeout)
+	defer cancel()
+	var out bytes.Buffer
+	opts.Args = []string{"--version", "--factory-startup", "-b"}
+	cmd, err := launch.CommandContext(ctx, exe, opts)
+	if err != nil {
+		return err
+	}
+	cmd.Stdout, cmd.Stderr = &out, &out
+	err = cmd.Run()
+	output := out.String()
+
+	switch {
+	case ctx.Err() != nil:
+		return fmt.Errorf("%w: no answer within %s", ErrBuildDoesNotRun, checkRunTimeout)
+	case errors.Is(err, fs.ErrNotExist):
+		return fmt.Errorf("%w: %w at %s", ErrBuildDoesNotRun, ErrNoExecutable, exe)
+	case err != nil && strings.Contains(err.Error(), "exec format error"):
+		return fmt.Errorf("%w: built for another platform or architecture", ErrBuildDoesNotRun)
+	}
+	if match := missingLibraryPattern.FindStringSubmatch(output); match != nil {
+		return fmt.Errorf("%w: missing library %s", ErrBuildDoesNotRun, match[1])
+	}
+	if err != nil {
+		if line := lastLine(output); line != "" {
+			return fmt.Errorf("%w: %v: %s", ErrBuildDoesNotRun, err, line)
+		}
+		return fmt.Errorf("%w: %v", ErrBuildDoesNotRun, err)
+	}
+	if _, err := parseVersionOutput(output); err != nil {
+		return fmt.Errorf("%w: no version reported", ErrBuildDoesNotRun)
+	}
+	return nil
+}
+
+// lastLine returns the last line of out that isn't blank.
+func lastLine(out string) string {
+	lines := strings.Split(strings.TrimSpace(out), "\n")
+	return strings.TrimSpace(lines[len(lines)-1])
+}
@@ -0,0 +1,226 @@
+package local
+
+import (
+	"TUI-Blender-Launcher/download"
+	"TUI-Blender-Launcher/launch"
+	"TUI-Blender-Launcher/model"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+)
+
+// Kinds of reclaimable items
+const (
+	ReclaimBackup  = "Old build"          // Build replaced by an update, in .oldbuilds
+	ReclaimArchive = "Kept archive"       // Archive kept in archives/
+	ReclaimPartial = "Partial download"   // Leftover in .downloading
+	ReclaimResume  = "Resumable download" // Interrupted download in .downloading that can be resumed
+	ReclaimExpired = "Expired trial"      // Temporary install past its expiry
+	ReclaimBuild   = "Installed build"
+)
+
+// ReclaimableItem is a file or directory in the download directory that can be
+// deleted to free space.
+type ReclaimableItem struct {
+	Kind string
+	Name string // Version for installed builds, file name otherwise
+	Path string
+	Size int64
+}
+
+// Disposable reports whether the item can go without losing an installed
+// build or a download the user means to keep. Cleanup suggests these by default.
+func (i ReclaimableItem) Disposable() bool {
+	return i.Kind != ReclaimBuild && i.Kind != ReclaimResume
+}
+
+// FindReclaimable lists what could be deleted from the build roots to free
+// space, largest first. The first root is the download directory, which alone
+// holds archives and partial downloads. Partial downloads are only included if
+// includePartial is set, as they may belong to a download in progress.
+func FindReclaimable(roots []string, includePartial bool) ([]ReclaimableItem, error) {
+	var items []ReclaimableItem
+	now := time.Now()
+	for i, root := range roots {
+		// Everything inside the managed directories
+		dirs := []struct{ dir, kind string }{
+			{download.OldBuildsDir, ReclaimBackup},
+		}
+		if i == 0 {
+			dirs = append(dirs, struct{ dir, kind string }{download.ArchivesDir, ReclaimArchive})
+			if includePartial {
+				dirs = append(dirs, struct{ dir, kind string }{download.DownloadingDir, ReclaimPartial})
+			}
+		}
+		for _, d := range dirs {
+			entries, err := os.ReadDir(filepath.Join(root, d.dir))
+			if err != nil {
+				if os.IsNotExist(err) {
+					continue
+				}
+				return nil, fmt.Errorf("failed to read %s directory: %w", d.dir, err)
+			}
+			for _, entry := range entries {
+				path := filepath.Join(root, d.dir, entry.Name())
+				kind := d.kind
+				if kind == ReclaimPartial && download.Resumable(path) {
+					kind = ReclaimResume
+				}
+				items = append(items, ReclaimableItem{Kind: kind, Name: entry.Name(), Path: path, Size: diskUsage(path)})
+			}
+		}
+
+		// Installed builds
+		entries, err := os.ReadDir(root)
+		if err != nil && !os.IsNotExist(err) {
+			return nil, fmt.Errorf("failed to read %s: %w", root, err)
+		}
+		ignore := download.LoadIgnore(root)
+		for _, entry := range entries {
+			if !entry.IsDir() || entry.Name() == download.DownloadingDir ||
+				entry.Name() == download.OldBuildsDir || entry.Name() == download.ArchivesDir ||
+				ignore.Ignored(entry.Name()) {
+				continue
+			}
+			path := filepath.Join(root, entry.Name())
+			build, err := ReadBuildInfo(path)
+			if err != nil || build == nil {
+				continue // Not a build the launcher installed
+			}
+			kind := ReclaimBuild
+			if build.Expired(now) {
+				kind = ReclaimExpired
+			}
+			items = append(items, ReclaimableItem{Kind: kind, Name: build.Version, Path: path, Size: diskUsage(path)})
+		}
+	}
+
+	sort.SliceStable(items, func(i, j int) bool { return items[i].Size > items[j].Size })
+	return items, nil
+}
+
+// RemoveReclaimable deletes the given items and returns the space freed.
+// It stops at the first item that can't be removed, e.g. a build Blender
+// runs from.
+func RemoveReclaimable(items []ReclaimableItem) (int64, error) {
+	var freed int64
+	for _, item := range items {
+		if err := launch.CheckNotInUse(item.Path); err != nil {
+			return freed, fmt.Errorf("failed to delete %s: %w", item.Name, err)
+		}
+		if err := os.RemoveAll(item.Path); err != nil {
+			return freed, fmt.Errorf("failed to delete %s: %w", item.Name, err)
+		}
+		freed += item.Size
+	}
+	return freed, nil
+}
+
+// RetentionExcess lists the daily builds beyond the newest keep of each
+// version series, counting installed builds in every root and the backups of
+// replaced ones in .oldbuilds alike. Stable releases, favorites, the default
+// build and builds Blender runs from are always kept and don't count towards
+// keep.
+func RetentionExcess(roots []string, keep int) ([]ReclaimableItem, error) {
+	type candidate struct {
+		item  ReclaimableItem
+		build *model.BlenderBuild
+	}
+	var current string
+	if len(roots) > 0 {
+		current, _ = os.Readlink(filepath.Join(roots[0], CurrentLinkName))
+	}
+	series := make(map[string][]candidate)
+	for _, root := range roots {
+		ignore := download.LoadIgnore(root)
+		for _, d := range []struct{ dir, kind string }{{"", ReclaimBuild}, {download.OldBuildsDir, ReclaimBackup}} {
+			dir := filepath.Join(root, d.dir)
+			entries, err := os.ReadDir(dir)
+			if os.IsNotExist(err) {
+				continue
+			} else if err != nil {
+				return nil, fmt.Errorf("failed to read %s: %w", dir, err)
+			}
+			for _, entry := range entries {
+				if !entry.IsDir() || (d.dir == "" && (entry.Name() == download.DownloadingDir || entry.Name() == download.OldBuildsDir ||
+					entry.Name() == download.ArchivesDir || ignore.Ignored(entry.Name()))) {
+					continue
+				}
+				path := filepath.Join(dir, entry.Name())
+				build, err := ReadBuildInfo(path)
+				if err != nil || build == nil || build.Favorite || isStable(*build) {
+					continue
+				}
+				if abs, err := filepath.Abs(path); err == nil && current != "" && filepath.Clean(current) == abs {
+					continue
+				}
+				if len(launch.ProcessesIn(path)) > 0 {
+					continue
+				}
+				name := entry.Name()
+				if d.kind == ReclaimBuild {
+					name = build.Version
+				}
+				key := model.VersionSeries(build.Version)
+				series[key] = append(series[key], candidate{ReclaimableItem{Kind: d.kind, Name: name, Path: path}, build})
+			}
+		}
+	}
+
+	var items []ReclaimableItem
+	for _, candidates := range series {
+		sort.SliceStable(candidates, func(i, j int) bool { return isNewer(*candidates[i].build, *candidates[j].build) })
+		for _, c := range candidates[min(keep, len(candidates)):] {
+			c.item.Size = diskUsage(c.item.Path)
+			items = append(items, c.item)
+		}
+	}
+	sort.SliceStable(items, func(i, j int) bool { return items[i].Size > items[j].Size })
+	return items, nil
+}
+
+// diskUsage returns the total size of the regular files at or below path.
+// Unreadable entries don't count.
+func diskUsage(path string) int64 {
+	var size int64
+	_ = filepath.WalkDir(path, func(_ string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return nil
+		}
+		if d.Type().IsRegular() {
+			if info, err := d.Info(); err == nil {
+				size += info.Size()
+			}
+		}
+		return nil
+	})
+	return size
+}
+
+// libraryUsage returns the disk usage of the download directory, leaving out
+// what its .launcherignore lists.
+func libraryUsage(downloadDir string) int64 {
+	ignore := download.LoadIgnore(downloadDir)
+	var size int64
+	_ = filepath.WalkDir(downloadDir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return nil
+		}
+		if rel, err := filepath.Rel(downloadDir, path); err == nil && rel != "." && ignore.Ignored(rel) {
+			if d.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if d.Type().IsRegular() {
+			if info, err := d.Info(); err == nil {
+				size += info.Size()
+			}
+		}
+		return nil
+	})
+	return size
+}
@@ -0,0 +1,77 @@
+package local
+
+import (
+	"TUI-Blender-Launcher/model"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+)
+
+// CurrentLinkName is the symlink in the download directory pointing at the
+// default build, so scripts and desktop entries can run current/blender.
+const CurrentLinkName = "current"
+
+// SetCurrentBuild points the current symlink in downloadDir at the installed
+// version and returns the link's path. The link is replaced in one step, so
+// programs using it never find it missing.
+func SetCurrentBuild(roots []string, downloadDir, version string) (string, error) {
+	dir, err := FindBuildDir(roots, version)
+	if err != nil {
+		return "", err
+	}
+	if dir, err = filepath.Abs(dir); err != nil {
+		return "", fmt.Errorf("failed to resolve build directory: %w", err)
+	}
+
+	link := filepath.Join(downloadDir, CurrentLinkName)
+	if info, err := os.Lstat(link); err == nil && info.Mode()&os.ModeSymlink == 0 {
+		return "", fmt.Errorf("%s exists and isn't a symlink, move it away first", link)
+	}
+
+	tmp := link + ".tmp"
+	_ = os.Remove(tmp)
+	if err := os.Symlink(dir, tmp); err != nil {
+		if runtime.GOOS == "windows" {
+			return "", fmt.Errorf("failed to create symlink, Windows needs Developer Mode for it: %w", err)
+		}
+		return "", fmt.Errorf("failed to create symlink: %w", err)
+	}
+	if err := os.Rename(tmp, link); err != nil {
+		_ = os.Remove(tmp)
+		return "", fmt.Errorf("failed to replace %s: %w", link, err)
+	}
+	return link, nil
+}
+
+// CurrentBuild returns the build the current symlink in downloadDir points
+// at, or nil if there is none or it points at a build that is gone.
+func CurrentBuild(downloadDir string) *model.BlenderBuild {
+	target, err := os.Readlink(filepath.Join(downloadDir, CurrentLinkName))
+	if err != nil {
+		return nil
+	}
+	if !filepath.IsAbs(target) {
+		target = filepath.Join(downloadDir, target)
+	}
+	build, err := ReadBuildInfo(target)
+	if err != nil {
+		return nil
+	}
+	return build
+}
+
+// unlinkCurrent removes the current symlink of each root if it points at dir,
+// so deleting the default build leaves no dangling link.
+func unlinkCurrent(roots []string, dir string) {
+	abs, err := filepath.Abs(dir)
+	if err != nil {
+		return
+	}
+	for _, root := range roots {
+		link := filepath.Join(root, CurrentLinkName)
+		if target, err := os.Readlink(link); err == nil && filepath.Clean(target) == abs {
+			_ = os.Remove(link)
+		}
+	}
+}
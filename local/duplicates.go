@@ -0,0 +1,45 @@
+package local
+
+import (
+	"TUI-Blender-Launcher/launch"
+	"fmt"
+)
+
+// RemoveDuplicates deletes the other directories holding the installed build
+// of version, keeping the one it is listed from, and returns how many went.
+// With toTrash they are moved to the trash instead. A current symlink
+// pointing at a removed copy is relinked to the kept one.
+func RemoveDuplicates(roots []string, version string, toTrash bool) (int, error) {
+	builds, err := ScanLocalBuilds(roots...)
+	if err != nil {
+		return 0, err
+	}
+	for _, build := range builds {
+		if build.Version != version {
+			continue
+		}
+		wasCurrent := false
+		if current := CurrentBuild(roots[0]); current != nil && current.Version == version {
+			wasCurrent = true
+		}
+		for _, dir := range build.Duplicates {
+			if err := launch.CheckNotInUse(dir); err != nil {
+				return 0, err
+			}
+		}
+		removed := 0
+		for _, dir := range build.Duplicates {
+			if err := removeBuildDir(dir, toTrash); err != nil {
+				return removed, err
+			}
+			removed++
+		}
+		if wasCurrent {
+			if _, err := SetCurrentBuild(roots, roots[0], version); err != nil {
+				return removed, fmt.Errorf("removed %d copies but failed to relink the default build: %w", removed, err)
+			}
+		}
+		return removed, nil
+	}
+	return 0, fmt.Errorf("blender version %s: %w", version, ErrBuildNotFound)
+}
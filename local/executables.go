@@ -0,0 +1,74 @@
+package local
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+)
+
+// BundledExecutable is a program shipped in a build besides its main Blender
+// executable, such as blender-thumbnailer, a GPU debug script or Python.
+type BundledExecutable struct {
+	Name string // Path relative to the build directory
+	Path string
+}
+
+// FindBundledExecutables lists the programs shipped in the installed build at
+// installDir other than its main executable: those next to it and the bundled
+// Python interpreter.
+func FindBundledExecutables(installDir string) []BundledExecutable {
+	dirs := []string{installDir}
+	pythonGlob := filepath.Join(installDir, "*", "python", "bin", "*")
+	if runtime.GOOS == "darwin" {
+		bundle := appBundle(installDir)
+		dirs = []string{filepath.Join(bundle, "Contents", "MacOS")}
+		pythonGlob = filepath.Join(bundle, "Contents", "Resources", "*", "python", "bin", "*")
+	}
+
+	var paths []string
+	for _, dir := range dirs {
+		entries, err := os.ReadDir(dir)
+		if err != nil {
+			continue
+		}
+		for _, entry := range entries {
+			paths = append(paths, filepath.Join(dir, entry.Name()))
+		}
+	}
+	python, _ := filepath.Glob(pythonGlob)
+	paths = append(paths, python...)
+
+	main := findBlenderExecutable(installDir)
+	var executables []BundledExecutable
+	for _, path := range paths {
+		if path == main || !isExecutable(path) {
+			continue
+		}
+		name, err := filepath.Rel(installDir, path)
+		if err != nil {
+			name = filepath.Base(path)
+		}
+		executables = append(executables, BundledExecutable{Name: name, Path: path})
+	}
+	sort.Slice(executables, func(i, j int) bool { return executables[i].Name < executables[j].Name })
+	return executables
+}
+
+// isExecutable reports whether path is a program that can be run: a file
+// with an executable extension on Windows, with the executable bit elsewhere.
+func isExecutable(path string) bool {
+	info, err := os.Stat(path)
+	if err != nil || !info.Mode().IsRegular() {
+		return false
+	}
+	if runtime.GOOS == "windows" {
+		switch strings.ToLower(filepath.Ext(path)) {
+		case ".exe", ".cmd", ".bat":
+			return true
+		}
+		return false
+	}
+	return info.Mode().Perm()&0111 != 0
+}
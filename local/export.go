@@ -0,0 +1,262 @@
+package local
+
+import (
+	"TUI-Blender-Launcher/download"
+	"TUI-Blender-Launcher/model"
+	"archive/tar"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"time"
+
+	"github.com/ulikunitz/xz"
+)
+
+// exportEntry is a file or directory added to an export under an archive path.
+type exportEntry struct {
+	path string
+	name string
+	info fs.FileInfo
+}
+
+// ExportBuild packs the installed build of version into a .tar.xz in destDir
+// and returns its path. The archive holds the build directory with its
+// version.json, written from `blender --version` if the build has none. With
+// withConfig, Blender's user config of the build's series is added where the
+// build reads it as a portable install. Progress is reported as the fraction
+// of the uncompressed data packed so far.
+func ExportBuild(roots []string, version, destDir string, withConfig bool, progressCb download.ExtractionProgressCallback, cancelCh <-chan struct{}) (_ string, err error) {
+	buildDir, err := FindBuildDir(roots, version)
+	if err != nil {
+		return "", err
+	}
+	name := filepath.Base(buildDir)
+	// Imported builds are links to where they are installed
+	if resolved, err := filepath.EvalSymlinks(buildDir); err == nil {
+		buildDir = resolved
+	}
+
+	var meta []byte
+	build, err := ReadBuildInfo(buildDir)
+	if err != nil {
+		return "", err
+	}
+	if build == nil {
+		exe := findBlenderExecutable(buildDir)
+		if exe == "" {
+			return "", fmt.Errorf("no Blender executable in %s", buildDir)
+		}
+		probed, err := probeBuildInfo(exe)
+		if err != nil {
+			return "", err
+		}
+		probed.FileName = name
+		if meta, err = json.MarshalIndent(probed, "", "  "); err != nil {
+			return "", fmt.Errorf("failed to marshal build metadata: %w", err)
+		}
+	}
+
+	entries, total, err := exportEntries(buildDir, name)
+	if err != nil {
+		return "", err
+	}
+	// A portable install has its config in the build directory already
+	portableConfig := portableConfigPath(version)
+	if _, err := os.Stat(filepath.Join(buildDir, portableConfig)); withConfig && os.IsNotExist(err) {
+		configDir := blenderConfigDir(model.VersionSeries(version))
+		if info, err := os.Stat(configDir); err == nil && info.IsDir() {
+			configEntries, size, err := exportEntries(configDir, name+"/"+portableConfig)
+			if err != nil {
+				return "", err
+			}
+			entries = append(entries, configEntries...)
+			total += size
+		}
+	}
+
+	if rest, ok := strings.CutPrefix(destDir, "~"); ok {
+		if homeDir, err := os.UserHomeDir(); err == nil {
+			destDir = filepath.Join(homeDir, rest)
+		}
+	}
+	if err := os.MkdirAll(destDir, 0750); err != nil {
+		return "", fmt.Errorf("failed to create %s: %w", destDir, err)
+	}
+	archivePath := filepath.Join(destDir, name+".tar.xz")
+	partPath := archivePath + ".part"
+	file, err := os.Create(partPath)
+	if err != nil {
+		return "", fmt.Errorf("failed to create %s: %w", partPath, err)
+	}
+	defer func() {
+		if err != nil {
+			file.Close()
+			os.Remove(partPath)
+		}
+	}()
+
+	xzWriter, err := xz.NewWriter(file)
+	if err != nil {
+		return "", fmt.Errorf("failed to start compression: %w", err)
+	}
+	tarWriter := tar.NewWriter(xzWriter)
+
+	var packed int64
+	for _, entry := range entries {
+		select {
+		case <-cancelCh:
+			return "", download.ErrCancelled
+		default:
+		}
+		if err := addExportEntry(tarWriter, entry); err != nil {
+			return "", err
+		}
+		if entry.info.Mode().IsRegular() {
+			packed += entry.info.Size()
+			if progressCb != nil && total > 0 {
+				progressCb(float64(packed) / float64(total))
+			}
+		}
+	}
+	if meta != nil {
+		header := &tar.Header{Name: name + "/" + versionMetaFilename, Mode: 0644, Size: int64(len(meta)), ModTime: time.Now()}
+		if err := tarWriter.WriteHeader(header); err != nil {
+			return "", fmt.Errorf("failed to add %s: %w", versionMetaFilename, err)
+		}
+		if _, err := tarWriter.Write(meta); err != nil {
+			return "", fmt.Errorf("failed to add %s: %w", versionMetaFilename, err)
+		}
+	}
+
+	if err := tarWriter.Close(); err != nil {
+		return "", fmt.Errorf("failed to finish archive: %w", err)
+	}
+	if err := xzWriter.Close(); err != nil {
+		return "", fmt.Errorf("failed to finish compression: %w", err)
+	}
+	if err := file.Close(); err != nil {
+		return "", fmt.Errorf("failed to write %s: %w", partPath, err)
+	}
+	if err := os.Rename(partPath, archivePath); err != nil {
+		return "", fmt.Errorf("failed to move archive into place: %w", err)
+	}
+	return archivePath, nil
+}
+
+// exportEntries lists the files and directories under dir with their archive
+// paths below prefix, and the total size of the files.
+func exportEntries(dir, prefix string) ([]exportEntry, int64, error) {
+	var entries []exportEntry
+	var total int64
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(dir, path)
+		if err != nil {
+			return err
+		}
+		name := prefix
+		if rel != "." {
+			name += "/" + filepath.ToSlash(rel)
+		}
+		entries = append(entries, exportEntry{path: path, name: name, info: info})
+		if info.Mode().IsRegular() {
+			total += info.Size()
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to read %s: %w", dir, err)
+	}
+	return entries, total, nil
+}
+
+// addExportEntry writes a file, directory or symlink to the archive.
+func addExportEntry(tarWriter *tar.Writer, entry exportEntry) error {
+	var link string
+	if entry.info.Mode()&fs.ModeSymlink != 0 {
+		target, err := os.Readlink(entry.path)
+		if err != nil {
+			return fmt.Errorf("failed to read link %s: %w", entry.path, err)
+		}
+		link = target
+	}
+	header, err := tar.FileInfoHeader(entry.info, link)
+	if err != nil {
+		return fmt.Errorf("failed to add %s: %w", entry.path, err)
+	}
+	header.Name = entry.name
+	if entry.info.IsDir() {
+		header.Name += "/"
+	}
+	if err := tarWriter.WriteHeader(header); err != nil {
+		return fmt.Errorf("failed to add %s: %w", entry.path, err)
+	}
+	if !entry.info.Mode().IsRegular() {
+		return nil
+	}
+
+	file, err := os.Open(entry.path)
+	if err != nil {
+		return fmt.Errorf("failed to add %s: %w", entry.path, err)
+	}
+	defer file.Close()
+	if _, err := io.Copy(tarWriter, file); err != nil {
+		return fmt.Errorf("failed to add %s: %w", entry.path, err)
+	}
+	return nil
+}
+
+// blenderConfigDir returns the directory holding Blender's user config of a
+// version series, e.g. ~/.config/blender/4.2/config.
+func blenderConfigDir(series string) string {
+	return filepath.Join(blenderUserDir(series), "config")
+}
+
+// blenderUserDir returns the directory holding Blender's user files of a
+// version series, its config, scripts and extensions, e.g. ~/.config/blender/4.2.
+func blenderUserDir(series string) string {
+	return filepath.Join(blenderUserBase(), series)
+}
+
+// blenderUserBase returns the directory holding the user files of every
+// version series, e.g. ~/.config/blender.
+func blenderUserBase() string {
+	var base string
+	switch runtime.GOOS {
+	case "windows":
+		base = filepath.Join(os.Getenv("APPDATA"), "Blender Foundation", "Blender")
+	case "darwin":
+		homeDir, _ := os.UserHomeDir()
+		base = filepath.Join(homeDir, "Library", "Application Support", "Blender")
+	default:
+		base = os.Getenv("XDG_CONFIG_HOME")
+		if base == "" {
+			homeDir, _ := os.UserHomeDir()
+			base = filepath.Join(homeDir, ".config")
+		}
+		base = filepath.Join(base, "blender")
+	}
+	return base
+}
+
+// portableConfigPath returns where a build reads its user config from when it
+// is installed portably, relative to the build directory. Blender 4.2 moved
+// it from the series directory to portable/.
+func portableConfigPath(version string) string {
+	series := model.VersionSeries(version)
+	if compareVersions(series, "4.2") >= 0 {
+		return "portable/config"
+	}
+	return series + "/config"
+}
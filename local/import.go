@@ -0,0 +1,83 @@
+package local
+
+import (
+	"TUI-Blender-Launcher/download"
+	"TUI-Blender-Launcher/model"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+)
+
+// ImportBuild registers a Blender installed outside the launcher, given its
+// directory or executable, by linking it into downloadDir. A missing
+// version.json is written from what `blender --version` reports. The build
+// stays where it is; deleting it in the launcher only removes the link.
+func ImportBuild(path string, roots []string, downloadDir string) (model.BlenderBuild, error) {
+	if rest, ok := strings.CutPrefix(path, "~"); ok {
+		if homeDir, err := os.UserHomeDir(); err == nil {
+			path = filepath.Join(homeDir, rest)
+		}
+	}
+	dir, err := filepath.Abs(path)
+	if err != nil {
+		return model.BlenderBuild{}, fmt.Errorf("failed to resolve %s: %w", path, err)
+	}
+	info, err := os.Stat(dir)
+	if err != nil {
+		return model.BlenderBuild{}, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+	if !info.IsDir() {
+		dir = filepath.Dir(dir)
+	}
+	if runtime.GOOS == "darwin" && strings.HasSuffix(dir, ".app") {
+		dir = filepath.Dir(dir)
+	}
+	exe := findBlenderExecutable(dir)
+	if exe == "" {
+		return model.BlenderBuild{}, fmt.Errorf("no Blender executable in %s", dir)
+	}
+
+	build, err := ReadBuildInfo(dir)
+	if err != nil {
+		return model.BlenderBuild{}, err
+	}
+	probed := build == nil
+	if probed {
+		info, err := probeBuildInfo(exe)
+		if err != nil {
+			return model.BlenderBuild{}, err
+		}
+		info.FileName = filepath.Base(dir)
+		build = &info
+	}
+	// The build is left untouched until nothing stands in the way of the import
+	if existing, err := FindBuildDir(roots, build.Version); err == nil {
+		return model.BlenderBuild{}, fmt.Errorf("Blender %s is installed already in %s", build.Version, existing)
+	} else if !errors.Is(err, ErrBuildNotFound) {
+		return model.BlenderBuild{}, err
+	}
+
+	if err := os.MkdirAll(downloadDir, 0750); err != nil {
+		return model.BlenderBuild{}, fmt.Errorf("failed to create download directory: %w", err)
+	}
+	link := filepath.Join(downloadDir, filepath.Base(dir))
+	if _, err := os.Lstat(link); err == nil {
+		link = filepath.Join(downloadDir, "blender-"+build.Version+"-imported")
+	}
+	if err := os.Symlink(dir, link); err != nil {
+		if runtime.GOOS == "windows" {
+			return model.BlenderBuild{}, fmt.Errorf("failed to create symlink, Windows needs Developer Mode for it: %w", err)
+		}
+		return model.BlenderBuild{}, fmt.Errorf("failed to create symlink: %w", err)
+	}
+	if probed {
+		if err := download.SaveVersionMetadata(*build, dir); err != nil {
+			os.Remove(link)
+			return model.BlenderBuild{}, fmt.Errorf("failed to add version.json to %s: %w", dir, err)
+		}
+	}
+	return *build, nil
+}
@@ -0,0 +1,235 @@
+package local
+
+import (
+	"TUI-Blender-Launcher/config"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+)
+
+// insightsFilename is the file in the state directory that stores the usage insights.
+const insightsFilename = "insights.json"
+
+// insightsRetention is how many days of insights are kept.
+const insightsRetention = 365
+
+// insightsLockStale is how old a lock on the insights file may get before it
+// is taken to be left behind by a crashed process and broken.
+const insightsLockStale = 10 * time.Second
+
+// InsightsDay summarizes one day of library and launcher usage.
+type InsightsDay struct {
+	Date            string         `json:"date"`          // YYYY-MM-DD, local time
+	LibraryBytes    int64          `json:"library_bytes"` // Disk usage of the download directory at the last scan
+	Builds          int            `json:"builds"`        // Installed builds at the last scan
+	DownloadedBytes int64          `json:"downloaded_bytes"`
+	Downloads       int            `json:"downloads"`
+	Launches        map[string]int `json:"launches,omitempty"` // Launch counts by version
+}
+
+// Insights is the locally stored usage history, oldest day first. It is
+// only recorded when enabled in the config and never leaves the machine.
+type Insights struct {
+	Days []InsightsDay `json:"days"`
+}
+
+// insightsPath returns the full path to the insights file.
+func insightsPath() (string, error) {
+	stateDir, err := config.GetStateDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(stateDir, insightsFilename), nil
+}
+
+// LoadInsights reads the recorded insights.
+// A missing file is not an error and yields empty insights.
+func LoadInsights() (Insights, error) {
+	var insights Insights
+
+	path, err := insightsPath()
+	if err != nil {
+		return insights, err
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return insights, nil
+		}
+		return insights, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+
+	if err := json.Unmarshal(data, &insights); err != nil {
+		return Insights{}, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+	return insights, nil
+}
+
+// today returns the entry for the given day, appending one if needed.
+func (in *Insights) today(now time.Time) *InsightsDay {
+	date := now.Format("2006-01-02")
+	if n := len(in.Days); n > 0 && in.Days[n-1].Date == date {
+		return &in.Days[n-1]
+	}
+
+	// A new day starts with the library as it was last seen
+	day := InsightsDay{Date: date}
+	if n := len(in.Days); n > 0 {
+		day.LibraryBytes = in.Days[n-1].LibraryBytes
+		day.Builds = in.Days[n-1].Builds
+	}
+	in.Days = append(in.Days, day)
+	if len(in.Days) > insightsRetention {
+		in.Days = in.Days[len(in.Days)-insightsRetention:]
+	}
+	return &in.Days[len(in.Days)-1]
+}
+
+// lockInsights takes the lock next to the insights file at path, so the TUI
+// and the CLI don't lose each other's updates. It returns the unlock function.
+func lockInsights(path string) (func(), error) {
+	lock := path + ".lock"
+	deadline := time.Now().Add(insightsLockStale)
+	for {
+		file, err := os.OpenFile(lock, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0644)
+		if err == nil {
+			file.Close()
+			return func() { _ = os.Remove(lock) }, nil
+		}
+		if !os.IsExist(err) {
+			return nil, fmt.Errorf("failed to lock %s: %w", path, err)
+		}
+		if info, err := os.Stat(lock); err == nil && time.Since(info.ModTime()) > insightsLockStale {
+			_ = os.Remove(lock)
+			continue
+		}
+		if time.Now().After(deadline) {
+			return nil, fmt.Errorf("failed to lock %s: held by another process", path)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+// recordInsight applies update to today's entry and saves the insights.
+func recordInsight(update func(day *InsightsDay)) error {
+	path, err := insightsPath()
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0750); err != nil {
+		return fmt.Errorf("could not create state directory: %w", err)
+	}
+	unlock, err := lockInsights(path)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	insights, err := LoadInsights()
+	if err != nil {
+		return err
+	}
+	update(insights.today(time.Now()))
+
+	data, err := json.MarshalIndent(insights, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal insights: %w", err)
+	}
+	// Written next to it first, so readers never see half a file
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0644); err != nil {
+		return fmt.Errorf("failed to write %s: %w", tmp, err)
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		return fmt.Errorf("failed to write %s: %w", path, err)
+	}
+	return nil
+}
+
+// RecordLaunchInsight counts a launch of the given version.
+func RecordLaunchInsight(version string) error {
+	return recordInsight(func(day *InsightsDay) {
+		if day.Launches == nil {
+			day.Launches = make(map[string]int)
+		}
+		day.Launches[version]++
+	})
+}
+
+// RecordDownloadInsight adds a completed download of the given size.
+func RecordDownloadInsight(size int64) error {
+	return recordInsight(func(day *InsightsDay) {
+		day.Downloads++
+		day.DownloadedBytes += size
+	})
+}
+
+// RecordLibraryInsight measures the disk usage of the download directory,
+// without ignored entries, and records it with the number of installed builds.
+func RecordLibraryInsight(downloadDir string, builds int) error {
+	size := libraryUsage(downloadDir)
+	return recordInsight(func(day *InsightsDay) {
+		day.LibraryBytes = size
+		day.Builds = builds
+	})
+}
+
+// VersionLaunches returns the launch counts by version over all recorded days,
+// most launched first.
+func (in Insights) VersionLaunches() []VersionCount {
+	totals := make(map[string]int)
+	for _, day := range in.Days {
+		for version, count := range day.Launches {
+			totals[version] += count
+		}
+	}
+
+	counts := make([]VersionCount, 0, len(totals))
+	for version, count := range totals {
+		counts = append(counts, VersionCount{Version: version, Count: count})
+	}
+	sort.Slice(counts, func(i, j int) bool {
+		if counts[i].Count != counts[j].Count {
+			return counts[i].Count > counts[j].Count
+		}
+		return counts[i].Version > counts[j].Version
+	})
+	return counts
+}
+
+// VersionCount is the number of launches of one version.
+type VersionCount struct {
+	Version string
+	Count   int
+}
+
+// InsightsMonth aggregates the recorded days of one month.
+type InsightsMonth struct {
+	Month           string // YYYY-MM
+	LibraryBytes    int64  // Library size on the last recorded day
+	DownloadedBytes int64
+	Downloads       int
+}
+
+// Monthly returns the insights aggregated by month, oldest first.
+func (in Insights) Monthly() []InsightsMonth {
+	var months []InsightsMonth
+	for _, day := range in.Days {
+		if len(day.Date) < 7 {
+			continue
+		}
+		month := day.Date[:7]
+		if len(months) == 0 || months[len(months)-1].Month != month {
+			months = append(months, InsightsMonth{Month: month})
+		}
+		m := &months[len(months)-1]
+		m.LibraryBytes = day.LibraryBytes
+		m.DownloadedBytes += day.DownloadedBytes
+		m.Downloads += day.Downloads
+	}
+	return months
+}
@@ -0,0 +1,238 @@
+package local
+
+import (
+	"TUI-Blender-Launcher/config"
+	"TUI-Blender-Launcher/download"
+	"TUI-Blender-Launcher/model"
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"time"
+)
+
+// versionProbeTimeout bounds how long `blender --version` may take when
+// working out the metadata of a build that lost its version.json.
+const versionProbeTimeout = 30 * time.Second
+
+// LibraryReport summarizes what RepairLibrary checked and fixed.
+type LibraryReport struct {
+	Builds           int      // Build directories checked
+	Verified         int      // Builds whose files match their manifest
+	NoManifest       int      // Builds installed before manifests existed, which can't be verified
+	Repaired         int      // Damaged builds extracted again from their kept archive
+	PermissionsFixed int      // Builds whose programs had lost their executable bit
+	MetadataRestored int      // Builds whose missing version.json was worked out from the executable
+	Indexed          int      // Builds listed in the rebuilt scan cache
+	Damaged          []string // Damaged builds that couldn't be repaired, with why
+	Skipped          []string // Directories that don't hold a Blender build
+}
+
+// RepairLibrary checks every build in the roots of cfg, e.g. after restoring
+// them from a backup: it gives programs back their executable bit, writes a
+// missing version.json from what `blender --version` reports, verifies the
+// files against the manifest and extracts damaged builds again from their
+// kept archive. Only builds in the download directory can be repaired that way. The
+// cached sizes and probes are dropped and the library scanned again, its
+// builds saved to the scan cache at scanCachePath unless that is "".
+func RepairLibrary(cfg config.Config, scanCachePath string) (LibraryReport, error) {
+	var report LibraryReport
+	roots := cfg.Roots()
+	for _, root := range roots {
+		entries, err := os.ReadDir(root)
+		if os.IsNotExist(err) {
+			continue
+		} else if err != nil {
+			return report, fmt.Errorf("failed to read download directory %s: %w", root, err)
+		}
+
+		ignore := download.LoadIgnore(root)
+		for _, entry := range entries {
+			if !entry.IsDir() || entry.Name() == download.DownloadingDir || entry.Name() == download.OldBuildsDir ||
+				entry.Name() == download.ArchivesDir || ignore.Ignored(entry.Name()) {
+				continue
+			}
+			repairBuildDir(filepath.Join(root, entry.Name()), root == cfg.DownloadDir, cfg, &report)
+		}
+	}
+
+	sizeCache.Lock()
+	clear(sizeCache.entries)
+	sizeCache.Unlock()
+	probeCache.Lock()
+	clear(probeCache.entries)
+	probeCache.Unlock()
+
+	builds, err := ScanLocalBuilds(roots...)
+	if err != nil {
+		return report, fmt.Errorf("failed to rebuild the index: %w", err)
+	}
+	if scanCachePath != "" {
+		if err := SaveScanCache(scanCachePath, roots, builds); err != nil {
+			return report, fmt.Errorf("failed to rebuild the index: %w", err)
+		}
+	}
+	report.Indexed = len(builds)
+	return report, nil
+}
+
+// repairBuildDir checks and fixes the build in dir, adding the outcome to report.
+func repairBuildDir(dir string, repairable bool, cfg config.Config, report *LibraryReport) {
+	build, err := ReadBuildInfo(dir)
+	if err != nil {
+		report.Builds++
+		report.Damaged = append(report.Damaged, fmt.Sprintf("%s: %v", filepath.Base(dir), err))
+		return
+	}
+	// Permissions are only touched once the directory turns out to hold Blender
+	exe := findBlenderExecutable(dir)
+	if build == nil && exe == "" {
+		report.Skipped = append(report.Skipped, dir)
+		return
+	}
+	if fixPermissions(dir) {
+		report.PermissionsFixed++
+	}
+
+	if build == nil {
+		probed, err := probeBuildInfo(exe)
+		if err == nil {
+			probed.FileName = filepath.Base(dir)
+			err = download.SaveVersionMetadata(probed, dir)
+		}
+		if err != nil {
+			report.Builds++
+			report.Damaged = append(report.Damaged, fmt.Sprintf("%s: no version.json, %v", filepath.Base(dir), err))
+			return
+		}
+		report.MetadataRestored++
+		build = &probed
+	}
+	report.Builds++
+
+	err = download.VerifyManifest(dir)
+	switch {
+	case err == nil:
+		report.Verified++
+	case errors.Is(err, download.ErrNoManifest):
+		report.NoManifest++
+	case errors.Is(err, download.ErrVerificationFailed) && repairable && download.HasKeptArchive(*build, cfg.DownloadDir):
+		if _, err := download.RepairBuild(*build, cfg, nil, make(chan struct{})); err != nil {
+			report.Damaged = append(report.Damaged, fmt.Sprintf("%s: repair failed: %v", build.Version, err))
+		} else {
+			report.Repaired++
+		}
+	default:
+		report.Damaged = append(report.Damaged, fmt.Sprintf("%s: %v", build.Version, err))
+	}
+}
+
+// fixPermissions sets the executable bit of the programs of the build in dir
+// that lost it, as copying from some backups or file systems does. Programs
+// are the files without an extension or with a .sh one next to the main
+// executable, and the bundled Python's. It reports whether anything changed.
+func fixPermissions(dir string) bool {
+	if runtime.GOOS == "windows" {
+		return false // Windows has no executable bit
+	}
+	exeDir := dir
+	pythonGlob := filepath.Join(dir, "*", "python", "bin", "*")
+	if runtime.GOOS == "darwin" {
+		bundle := appBundle(dir)
+		exeDir = filepath.Join(bundle, "Contents", "MacOS")
+		pythonGlob = filepath.Join(bundle, "Contents", "Resources", "*", "python", "bin", "*")
+	}
+
+	var candidates []string
+	if entries, err := os.ReadDir(exeDir); err == nil {
+		for _, entry := range entries {
+			if ext := filepath.Ext(entry.Name()); ext == "" || ext == ".sh" {
+				candidates = append(candidates, filepath.Join(exeDir, entry.Name()))
+			}
+		}
+	}
+	python, _ := filepath.Glob(pythonGlob)
+	candidates = append(candidates, python...)
+
+	fixed := false
+	for _, path := range candidates {
+		info, err := os.Lstat(path)
+		if err != nil || !info.Mode().IsRegular() || info.Mode().Perm()&0100 != 0 {
+			continue
+		}
+		// Readers of the file may run it too
+		mode := info.Mode().Perm() | 0100 | (info.Mode().Perm()&0044)>>2
+		if os.Chmod(path, mode) == nil {
+			fixed = true
+		}
+	}
+	return fixed
+}
+
+// probeBuildInfo works out the metadata of a build from what its executable
+// reports with --version.
+func probeBuildInfo(exe string) (model.BlenderBuild, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), versionProbeTimeout)
+	defer cancel()
+	out, err := exec.CommandContext(ctx, exe, "--version").Output()
+	if err != nil {
+		return model.BlenderBuild{}, fmt.Errorf("failed to run %s --version: %w", filepath.Base(exe), err)
+	}
+	return parseVersionOutput(string(out))
+}
+
+// parseVersionOutput reads the output of `blender --version`, which starts
+// with e.g. "Blender 4.2.1 LTS" followed by "build hash: ..." lines.
+func parseVersionOutput(out string) (model.BlenderBuild, error) {
+	var build model.BlenderBuild
+	var commitDate, commitTime string
+	scanner := bufio.NewScanner(strings.NewReader(out))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if rest, ok := strings.CutPrefix(line, "Blender "); ok && build.Version == "" {
+			fields := strings.Fields(rest)
+			if len(fields) == 0 {
+				continue
+			}
+			build.Version = fields[0]
+			build.ReleaseCycle = "stable"
+			switch suffix := strings.ToLower(strings.Join(fields[1:], " ")); {
+			case strings.Contains(suffix, "alpha"):
+				build.ReleaseCycle = "alpha"
+			case strings.Contains(suffix, "beta"):
+				build.ReleaseCycle = "beta"
+			case strings.Contains(suffix, "candidate"):
+				build.ReleaseCycle = "candidate"
+			}
+			continue
+		}
+		key, value, ok := strings.Cut(line, ":")
+		if !ok {
+			continue
+		}
+		value = strings.TrimSpace(value)
+		switch key {
+		case "build hash":
+			build.Hash = value
+		case "build branch":
+			build.Branch = value
+		case "build commit date":
+			commitDate = value
+		case "build commit time":
+			commitTime = value
+		}
+	}
+	if build.Version == "" {
+		return model.BlenderBuild{}, errors.New("no version in the output of --version")
+	}
+	if date, err := time.Parse("2006-01-02 15:04", commitDate+" "+commitTime); err == nil {
+		build.BuildDate = model.Timestamp(date)
+	}
+	build.OperatingSystem = runtime.GOOS
+	return build, nil
+}
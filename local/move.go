@@ -0,0 +1,171 @@
+package local
+
+import (
+	"TUI-Blender-Launcher/download"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+)
+
+// movingSuffix marks a build directory still being copied to another root.
+const movingSuffix = ".moving"
+
+// MoveBuild moves the installed build of version to destRoot, one of roots,
+// and returns its new directory. The directory is renamed, and only if that
+// fails for crossing devices copied, the copy checked against the checksum
+// manifest (or the file sizes without one) and only then the original
+// removed. Progress is reported as the fraction of the data copied. The
+// current symlink follows the build.
+func MoveBuild(roots []string, version, destRoot string, progressCb download.ExtractionProgressCallback, cancelCh <-chan struct{}) (string, error) {
+	srcDir, err := FindBuildDir(roots, version)
+	if err != nil {
+		return "", err
+	}
+	if info, err := os.Lstat(srcDir); err == nil && info.Mode()&fs.ModeSymlink != 0 {
+		return "", fmt.Errorf("Blender %s is imported from %s, move it there instead", version, srcDir)
+	}
+	if filepath.Clean(filepath.Dir(srcDir)) == filepath.Clean(destRoot) {
+		return "", fmt.Errorf("Blender %s is in %s already", version, destRoot)
+	}
+	destDir := filepath.Join(destRoot, filepath.Base(srcDir))
+	if _, err := os.Lstat(destDir); err == nil {
+		return "", fmt.Errorf("%s exists already", destDir)
+	}
+	if err := os.MkdirAll(destRoot, 0750); err != nil {
+		return "", fmt.Errorf("failed to create %s: %w", destRoot, err)
+	}
+
+	// The default build is relinked once it moved
+	downloadDir := roots[0]
+	wasCurrent := false
+	if target, err := os.Readlink(filepath.Join(downloadDir, CurrentLinkName)); err == nil {
+		if abs, err := filepath.Abs(srcDir); err == nil && filepath.Clean(target) == abs {
+			wasCurrent = true
+		}
+	}
+
+	if err := os.Rename(srcDir, destDir); err != nil {
+		if !isCrossDevice(err) {
+			return "", fmt.Errorf("failed to move %s: %w", srcDir, err)
+		}
+		if err := copyBuild(srcDir, destDir, progressCb, cancelCh); err != nil {
+			return "", err
+		}
+		if err := os.RemoveAll(srcDir); err != nil {
+			return "", fmt.Errorf("copied to %s but failed to remove %s: %w", destDir, srcDir, err)
+		}
+	}
+	if progressCb != nil {
+		progressCb(1)
+	}
+
+	if wasCurrent {
+		if _, err := SetCurrentBuild(roots, downloadDir, version); err != nil {
+			return "", fmt.Errorf("moved to %s but failed to relink the default build: %w", destDir, err)
+		}
+	}
+	return destDir, nil
+}
+
+// copyBuild copies srcDir to destDir through a temporary directory next to
+// it, which only takes destDir's name once the copy checks out.
+func copyBuild(srcDir, destDir string, progressCb download.ExtractionProgressCallback, cancelCh <-chan struct{}) (err error) {
+	entries, total, err := exportEntries(srcDir, ".")
+	if err != nil {
+		return err
+	}
+	tmpDir := destDir + movingSuffix
+	if err := os.RemoveAll(tmpDir); err != nil {
+		return fmt.Errorf("failed to clear %s: %w", tmpDir, err)
+	}
+	defer func() {
+		if err != nil {
+			os.RemoveAll(tmpDir)
+		}
+	}()
+
+	var copied int64
+	for _, entry := range entries {
+		select {
+		case <-cancelCh:
+			return download.ErrCancelled
+		default:
+		}
+		dest := filepath.Join(tmpDir, filepath.FromSlash(entry.name))
+		mode := entry.info.Mode()
+		switch {
+		case entry.info.IsDir():
+			if err := os.MkdirAll(dest, mode.Perm()|0700); err != nil {
+				return fmt.Errorf("failed to create %s: %w", dest, err)
+			}
+		case mode&fs.ModeSymlink != 0:
+			target, err := os.Readlink(entry.path)
+			if err != nil {
+				return fmt.Errorf("failed to read link %s: %w", entry.path, err)
+			}
+			if err := os.Symlink(target, dest); err != nil {
+				return fmt.Errorf("failed to create link %s: %w", dest, err)
+			}
+		case mode.IsRegular():
+			if err := copyFile(entry.path, dest, mode.Perm()); err != nil {
+				return err
+			}
+			copied += entry.info.Size()
+			if progressCb != nil && total > 0 {
+				// The last bit is left for checking the copy
+				progressCb(0.99 * float64(copied) / float64(total))
+			}
+		}
+	}
+
+	if err := verifyCopy(tmpDir, entries); err != nil {
+		return err
+	}
+	if err := os.Rename(tmpDir, destDir); err != nil {
+		return fmt.Errorf("failed to move copy into place: %w", err)
+	}
+	return nil
+}
+
+// copyFile copies a regular file, keeping its permissions.
+func copyFile(src, dest string, perm fs.FileMode) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return fmt.Errorf("failed to open %s: %w", src, err)
+	}
+	defer in.Close()
+	out, err := os.OpenFile(dest, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, perm)
+	if err != nil {
+		return fmt.Errorf("failed to create %s: %w", dest, err)
+	}
+	if _, err := io.Copy(out, in); err != nil {
+		out.Close()
+		return fmt.Errorf("failed to copy %s: %w", src, err)
+	}
+	if err := out.Close(); err != nil {
+		return fmt.Errorf("failed to write %s: %w", dest, err)
+	}
+	return nil
+}
+
+// verifyCopy checks a copied build against its checksum manifest, or against
+// the sizes of the original files when it has none.
+func verifyCopy(dir string, entries []exportEntry) error {
+	err := download.VerifyManifest(dir)
+	if !errors.Is(err, download.ErrNoManifest) {
+		return err
+	}
+	for _, entry := range entries {
+		if !entry.info.Mode().IsRegular() {
+			continue
+		}
+		info, err := os.Stat(filepath.Join(dir, filepath.FromSlash(entry.name)))
+		if err != nil || info.Size() != entry.info.Size() {
+			return fmt.Errorf("%w: %s differs from the original", download.ErrVerificationFailed, entry.name)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,15 @@
+//go:build !windows
+// +build !windows
+
+package local
+
+import (
+	"errors"
+	"syscall"
+)
+
+// isCrossDevice reports whether a rename failed because it would move across
+// file systems.
+func isCrossDevice(err error) bool {
+	return errors.Is(err, syscall.EXDEV)
+}
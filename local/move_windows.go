@@ -0,0 +1,19 @@
+//go:build windows
+// +build windows
+
+package local
+
+import (
+	"errors"
+	"syscall"
+)
+
+// errorNotSameDevice is ERROR_NOT_SAME_DEVICE, which a rename to another
+// volume fails with.
+const errorNotSameDevice syscall.Errno = 17
+
+// isCrossDevice reports whether a rename failed because it would move across
+// volumes.
+func isCrossDevice(err error) bool {
+	return errors.Is(err, errorNotSameDevice)
+}
@@ -0,0 +1,60 @@
+package local
+
+import (
+	"TUI-Blender-Launcher/model"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// probeCache remembers the metadata worked out for builds without a
+// version.json, so rescans don't run their executables again.
+var probeCache = struct {
+	sync.Mutex
+	entries map[string]cachedProbe
+}{entries: make(map[string]cachedProbe)}
+
+// cachedProbe is the metadata of a build directory without version.json, nil
+// if it holds no Blender, valid while its executable has the recorded
+// modification time.
+type cachedProbe struct {
+	stamp time.Time
+	build *model.BlenderBuild
+}
+
+// detectBuildInfo works out the metadata of the build in dir, which has no
+// version.json, from what its executable reports with --version. Executables
+// that don't run, e.g. ones built for another platform, leave what the
+// directory's name tells. Returns nil if dir holds no Blender.
+func detectBuildInfo(dir string) *model.BlenderBuild {
+	exe := findBlenderExecutable(dir)
+	if exe == "" {
+		return nil
+	}
+	info, err := os.Stat(exe)
+	if err != nil {
+		return nil
+	}
+	probeCache.Lock()
+	cached, ok := probeCache.entries[dir]
+	probeCache.Unlock()
+	if ok && cached.stamp.Equal(info.ModTime()) {
+		return cached.build
+	}
+
+	var detected *model.BlenderBuild
+	if build, err := probeBuildInfo(exe); err == nil {
+		build.FileName = filepath.Base(dir)
+		detected = &build
+	} else if build, err := model.ParseBuildDirName(filepath.Base(dir)); err == nil {
+		detected = &build
+	}
+	if detected != nil {
+		detected.Status = model.StateLocal
+	}
+	probeCache.Lock()
+	probeCache.entries[dir] = cachedProbe{stamp: info.ModTime(), build: detected}
+	probeCache.Unlock()
+	return detected
+}
@@ -0,0 +1,52 @@
+package local
+
+import (
+	"TUI-Blender-Launcher/model"
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// RecentFilesName is the file Blender lists its recently opened .blend files
+// in, newest first, in its user config directory.
+const RecentFilesName = "recent-files.txt"
+
+// RecentFiles returns the .blend files Blender of version opened last, newest
+// first, leaving out those that no longer exist. A build in buildDir installed
+// portably keeps its own list; system installs pass an empty buildDir.
+func RecentFiles(buildDir, version string) ([]string, error) {
+	path := filepath.Join(blenderConfigDir(model.VersionSeries(version)), RecentFilesName)
+	if buildDir != "" {
+		portable := filepath.Join(buildDir, portableConfigPath(version))
+		if info, err := os.Stat(portable); err == nil && info.IsDir() {
+			path = filepath.Join(portable, RecentFilesName)
+		}
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to read recent files: %w", err)
+	}
+	defer file.Close()
+
+	var files []string
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+		if info, err := os.Stat(line); err == nil && info.Mode().IsRegular() {
+			files = append(files, line)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+	return files, nil
+}
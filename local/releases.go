@@ -0,0 +1,62 @@
+package local
+
+import (
+	"TUI-Blender-Launcher/config"
+	"TUI-Blender-Launcher/model"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// stableSeenFilename is the file in the state directory recording the newest
+// stable release seen online.
+const stableSeenFilename = "stable_seen"
+
+// IsNewRelease reports whether build is the first stable build of a minor
+// version, e.g. 4.3.0, rather than a bugfix release or a daily build.
+func IsNewRelease(build model.BlenderBuild) bool {
+	return isStable(build) && strings.Count(build.Version, ".") == 2 && strings.HasSuffix(build.Version, ".0")
+}
+
+// NewStableRelease returns the newest new release among builds if it is newer
+// than any seen by an earlier call, and records it as seen. The first call
+// only records what is online, so releases that were out before the launcher
+// was first used aren't announced.
+func NewStableRelease(builds []model.BlenderBuild) (*model.BlenderBuild, error) {
+	var newest *model.BlenderBuild
+	for i, build := range builds {
+		if IsNewRelease(build) && (newest == nil || compareVersions(build.Version, newest.Version) > 0) {
+			newest = &builds[i]
+		}
+	}
+	if newest == nil {
+		return nil, nil
+	}
+
+	stateDir, err := config.GetStateDir()
+	if err != nil {
+		return nil, err
+	}
+	path := filepath.Join(stateDir, stableSeenFilename)
+	data, err := os.ReadFile(path)
+	if err != nil && !os.IsNotExist(err) {
+		return nil, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+	seen := strings.TrimSpace(string(data))
+	if seen != "" && compareVersions(newest.Version, seen) <= 0 {
+		return nil, nil
+	}
+
+	if err := os.MkdirAll(stateDir, 0750); err != nil {
+		return nil, fmt.Errorf("failed to create state directory: %w", err)
+	}
+	if err := os.WriteFile(path, []byte(newest.Version+"\n"), 0644); err != nil {
+		return nil, fmt.Errorf("failed to write %s: %w", path, err)
+	}
+	if seen == "" {
+		return nil, nil
+	}
+	release := *newest
+	return &release, nil
+}
@@ -0,0 +1,91 @@
+package local
+
+import (
+	"TUI-Blender-Launcher/download"
+	"TUI-Blender-Launcher/model"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"time"
+)
+
+// ErrNoBackup is returned when an update left no backup to roll back to.
+var ErrNoBackup = errors.New("no backup in " + download.OldBuildsDir)
+
+// backupSuffixPattern matches the time an update appended to the name of the
+// build it backed up, e.g. "_20250318_120000.123456789", and the number of a
+// backup made at the same time. Older backups have no fraction of a second.
+// Later times sort after earlier ones.
+var backupSuffixPattern = regexp.MustCompile(`_\d{8}_\d{6}(\.\d+)?(-\d+)?$`)
+
+// RollbackBuild swaps the installed build of version, in any of roots, for
+// the newest backup of the same version that an update left in the
+// .oldbuilds of its root. The replaced build becomes a backup itself, so
+// rolling back again undoes the rollback. The current symlink in downloadDir
+// follows the build. Returns the restored build.
+func RollbackBuild(roots []string, downloadDir, version string) (model.BlenderBuild, error) {
+	installedDir, err := FindBuildDir(roots, version)
+	if err != nil {
+		return model.BlenderBuild{}, err
+	}
+	root := filepath.Dir(installedDir)
+	backupDir, backup, err := newestBackup(root, version)
+	if err != nil {
+		return model.BlenderBuild{}, err
+	}
+
+	wasCurrent := false
+	if current := CurrentBuild(downloadDir); current != nil && current.Version == version {
+		wasCurrent = true
+	}
+
+	// Swap the two, putting the installed build back should the restore fail
+	swapped := download.BackupPath(filepath.Dir(backupDir), filepath.Base(installedDir), time.Now())
+	if err := os.Rename(installedDir, swapped); err != nil {
+		return model.BlenderBuild{}, fmt.Errorf("failed to back up %s: %w", installedDir, err)
+	}
+	restored := filepath.Join(root, backupSuffixPattern.ReplaceAllString(filepath.Base(backupDir), ""))
+	if _, err := os.Lstat(restored); err == nil {
+		restored = installedDir // Something else took the backup's old name
+	}
+	if err := os.Rename(backupDir, restored); err != nil {
+		_ = os.Rename(swapped, installedDir)
+		return model.BlenderBuild{}, fmt.Errorf("failed to restore %s: %w", backupDir, err)
+	}
+
+	if wasCurrent {
+		if _, err := SetCurrentBuild(roots, downloadDir, version); err != nil {
+			return *backup, fmt.Errorf("rolled back but failed to relink the default build: %w", err)
+		}
+	}
+	return *backup, nil
+}
+
+// newestBackup returns the directory and metadata of the newest backup of
+// version in the .oldbuilds directory of root.
+func newestBackup(root, version string) (string, *model.BlenderBuild, error) {
+	oldBuildsDir := filepath.Join(root, download.OldBuildsDir)
+	entries, err := os.ReadDir(oldBuildsDir)
+	if err != nil && !os.IsNotExist(err) {
+		return "", nil, fmt.Errorf("failed to read %s: %w", oldBuildsDir, err)
+	}
+
+	var newestDir, newestStamp string
+	var newest *model.BlenderBuild
+	for _, entry := range entries {
+		stamp := backupSuffixPattern.FindString(entry.Name())
+		if !entry.IsDir() || (newest != nil && stamp <= newestStamp) {
+			continue
+		}
+		dir := filepath.Join(oldBuildsDir, entry.Name())
+		if build, err := ReadBuildInfo(dir); err == nil && build != nil && build.Version == version {
+			newestDir, newestStamp, newest = dir, stamp, build
+		}
+	}
+	if newest == nil {
+		return "", nil, fmt.Errorf("Blender %s: %w", version, ErrNoBackup)
+	}
+	return newestDir, newest, nil
+}
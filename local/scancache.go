@@ -0,0 +1,93 @@
+package local
+
+import (
+	"TUI-Blender-Launcher/model"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+)
+
+// ScanCacheFilename is the file in the cache directory holding the last scan
+// of the install roots.
+const ScanCacheFilename = "scan_cache.json"
+
+// scanCache is the last scan of the install roots, with the disk usage of
+// their build directories.
+type scanCache struct {
+	Roots  []string              `json:"roots"`
+	Builds []cachedBuild         `json:"builds"`
+	Sizes  map[string]cachedSize `json:"sizes"`
+}
+
+// cachedBuild is a build of the last scan, with what version.json leaves out.
+type cachedBuild struct {
+	Build         model.BlenderBuild `json:"build"`
+	InstalledSize int64              `json:"installed_size,omitempty"`
+	Duplicates    []string           `json:"duplicates,omitempty"`
+}
+
+// LoadScanCache returns the builds of the last scan saved at path, to list
+// them while the install roots are scanned again. The sizes saved along are
+// reused by that scan for builds that didn't change. A cache of other roots,
+// or none at all, yields nil.
+func LoadScanCache(path string, roots []string) []model.BlenderBuild {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil
+	}
+	var cache scanCache
+	if err := json.Unmarshal(data, &cache); err != nil || !slices.Equal(cache.Roots, roots) {
+		return nil
+	}
+
+	sizeCache.Lock()
+	for dir, size := range cache.Sizes {
+		if _, ok := sizeCache.entries[dir]; !ok {
+			sizeCache.entries[dir] = size
+		}
+	}
+	sizeCache.Unlock()
+
+	builds := make([]model.BlenderBuild, len(cache.Builds))
+	for i, cached := range cache.Builds {
+		builds[i] = cached.Build
+		builds[i].Status = model.StateLocal
+		builds[i].InstalledSize = cached.InstalledSize
+		builds[i].Duplicates = cached.Duplicates
+	}
+	return builds
+}
+
+// SaveScanCache saves the builds a scan of roots found to path, along with the
+// disk usage measured so far.
+func SaveScanCache(path string, roots []string, builds []model.BlenderBuild) error {
+	cache := scanCache{Roots: roots, Builds: make([]cachedBuild, len(builds))}
+	for i, build := range builds {
+		cache.Builds[i] = cachedBuild{Build: build, InstalledSize: build.InstalledSize, Duplicates: build.Duplicates}
+	}
+	sizeCache.Lock()
+	cache.Sizes = make(map[string]cachedSize, len(sizeCache.entries))
+	for dir, size := range sizeCache.entries {
+		cache.Sizes[dir] = size
+	}
+	sizeCache.Unlock()
+
+	data, err := json.Marshal(cache)
+	if err != nil {
+		return fmt.Errorf("failed to marshal scan cache: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0750); err != nil {
+		return fmt.Errorf("failed to create cache directory: %w", err)
+	}
+	// Written next to it first, so a crash never leaves half a cache
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0644); err != nil {
+		return fmt.Errorf("failed to write %s: %w", tmp, err)
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		return fmt.Errorf("failed to write %s: %w", path, err)
+	}
+	return nil
+}
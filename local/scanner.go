@@ -4,18 +4,28 @@ import (
 	"TUI-Blender-Launcher/download"
 	"TUI-Blender-Launcher/model"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"os"
 	"os/exec"
 	"path/filepath"
 	"runtime"
 	"sort"
+	"strings"
+	"sync"
+	"time"
 
 	tea "github.com/charmbracelet/bubbletea"
 )
 
 const versionMetaFilename = "version.json"
 
+// ErrBuildNotFound is returned when a requested version is not installed.
+var ErrBuildNotFound = errors.New("build not found")
+
+// ErrNoExecutable is returned when an installed build lost its Blender executable.
+var ErrNoExecutable = errors.New("no Blender executable")
+
 // ReadBuildInfo reads build information from version.json in the given directory.
 // Returns nil if version.json does not exist.
 func ReadBuildInfo(dirPath string) (*model.BlenderBuild, error) {
@@ -37,122 +47,308 @@ func ReadBuildInfo(dirPath string) (*model.BlenderBuild, error) {
 	return &build, nil
 }
 
-// ScanLocalBuilds scans the download directory for local Blender builds using version.json.
-func ScanLocalBuilds(downloadDir string) ([]model.BlenderBuild, error) {
-	var localBuilds []model.BlenderBuild
-	entries, err := os.ReadDir(downloadDir)
+// installedBuild is a build found in an install root with its directory.
+type installedBuild struct {
+	dir   string
+	build model.BlenderBuild
+}
+
+// scanWorkers bounds how many build directories are read at once, enough to
+// hide the latency of network drives and spinning disks without flooding them.
+const scanWorkers = 8
+
+// forEachParallel calls fn with each index below n on up to scanWorkers
+// goroutines and returns once all calls did.
+func forEachParallel(n int, fn func(i int)) {
+	indexes := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < min(n, scanWorkers); w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range indexes {
+				fn(i)
+			}
+		}()
+	}
+	for i := 0; i < n; i++ {
+		indexes <- i
+	}
+	close(indexes)
+	wg.Wait()
+}
+
+// installedBuilds reads the builds installed in root using version.json,
+// skipping the launcher's own directories and entries listed in its
+// .launcherignore. A missing root holds no builds.
+func installedBuilds(root string) ([]installedBuild, error) {
+	return scanRoot(root, nil)
+}
+
+// scanRoot is installedBuilds reading the build directories in parallel,
+// calling found with each build as soon as it is read. The builds are
+// returned in the order of their directories.
+func scanRoot(root string, found func(installedBuild)) ([]installedBuild, error) {
+	entries, err := os.ReadDir(root)
 	if err != nil {
 		if os.IsNotExist(err) {
-			return localBuilds, nil
+			return nil, nil
 		}
-		return nil, fmt.Errorf("failed to read download directory %s: %w", downloadDir, err)
+		return nil, fmt.Errorf("failed to read download directory %s: %w", root, err)
 	}
 
+	var dirs []string
+	ignore := download.LoadIgnore(root)
 	for _, entry := range entries {
-		if entry.IsDir() && entry.Name() != download.OldBuildsDir {
-			dirPath := filepath.Join(downloadDir, entry.Name())
-			buildInfo, err := ReadBuildInfo(dirPath)
-			if err != nil {
-				fmt.Fprintf(os.Stderr, "Error processing directory %s: %v\n", dirPath, err)
-				continue
+		if (entry.IsDir() || isBuildLink(root, entry)) && entry.Name() != download.DownloadingDir && entry.Name() != download.OldBuildsDir &&
+			entry.Name() != download.ArchivesDir && !strings.HasSuffix(entry.Name(), movingSuffix) && !ignore.Ignored(entry.Name()) {
+			dirs = append(dirs, filepath.Join(root, entry.Name()))
+		}
+	}
+
+	read := make([]*model.BlenderBuild, len(dirs))
+	var foundMu sync.Mutex
+	forEachParallel(len(dirs), func(i int) {
+		buildInfo, err := ReadBuildInfo(dirs[i])
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error processing directory %s: %v\n", dirs[i], err)
+			return
+		}
+		if buildInfo == nil {
+			// Hand-copied builds may come without version.json
+			buildInfo = detectBuildInfo(dirs[i])
+		}
+		read[i] = buildInfo
+		if buildInfo != nil && found != nil {
+			foundMu.Lock()
+			found(installedBuild{dir: dirs[i], build: *buildInfo})
+			foundMu.Unlock()
+		}
+	})
+
+	var builds []installedBuild
+	for i, buildInfo := range read {
+		if buildInfo != nil {
+			builds = append(builds, installedBuild{dir: dirs[i], build: *buildInfo})
+		}
+	}
+	return builds, nil
+}
+
+// isBuildLink reports whether entry of root links to a directory elsewhere,
+// as ImportBuild creates for builds installed outside the launcher.
+func isBuildLink(root string, entry os.DirEntry) bool {
+	if entry.Type()&os.ModeSymlink == 0 || entry.Name() == CurrentLinkName {
+		return false
+	}
+	info, err := os.Stat(filepath.Join(root, entry.Name()))
+	return err == nil && info.IsDir()
+}
+
+// ScanLocalBuilds scans the install roots for local Blender builds using
+// version.json, or what the executable reports for builds without one,
+// skipping entries listed in their .launcherignore. A build
+// installed in several directories is listed once, from the first root,
+// with the other directories in its Duplicates if it has a hash telling
+// they hold the same build. Builds without one are listed from each directory.
+func ScanLocalBuilds(roots ...string) ([]model.BlenderBuild, error) {
+	return ScanLocalBuildsFunc(nil, roots...)
+}
+
+// ScanLocalBuildsFunc is ScanLocalBuilds calling found with each build as
+// soon as its directory is read, so callers can list builds while slow drives
+// are still being scanned. Those builds are neither sized nor merged with
+// their duplicates yet. found is called by one goroutine at a time.
+func ScanLocalBuildsFunc(found func(model.BlenderBuild), roots ...string) ([]model.BlenderBuild, error) {
+	var foundInstalled func(installedBuild)
+	if found != nil {
+		foundInstalled = func(installed installedBuild) { found(installed.build) }
+	}
+
+	var localBuilds []model.BlenderBuild
+	var localDirs []string
+	seen := make(map[string]int)
+	for _, root := range roots {
+		builds, err := scanRoot(root, foundInstalled)
+		if err != nil {
+			return nil, err
+		}
+		for _, installed := range builds {
+			key := installed.build.Version + "-" + installed.build.Hash
+			if installed.build.Hash == "" {
+				// Without a hash to compare, two builds of a version may differ,
+				// so each directory is listed as a build of its own rather than
+				// offered for removal as the other's copy
+				dir := installed.dir
+				if resolved, err := filepath.EvalSymlinks(dir); err == nil {
+					dir = resolved
+				}
+				key += dir
 			}
-			if buildInfo != nil {
-				localBuilds = append(localBuilds, *buildInfo)
+			if i, ok := seen[key]; ok {
+				if !sameDir(localDirs[i], installed.dir) {
+					localBuilds[i].Duplicates = append(localBuilds[i].Duplicates, installed.dir)
+				}
+				continue
 			}
+			seen[key] = len(localBuilds)
+			localBuilds = append(localBuilds, installed.build)
+			localDirs = append(localDirs, installed.dir)
 		}
 	}
 
-	sort.Slice(localBuilds, func(i, j int) bool {
+	// Sizing walks each build, which only happens when one changed
+	forEachParallel(len(localBuilds), func(i int) {
+		localBuilds[i].InstalledSize = installedSize(localDirs[i])
+	})
+
+	sort.SliceStable(localBuilds, func(i, j int) bool {
 		return localBuilds[i].Version > localBuilds[j].Version
 	})
 
 	return localBuilds, nil
 }
 
-// BuildLocalLookupMap creates a map of available local build versions.
-func BuildLocalLookupMap(downloadDir string) (map[string]bool, error) {
-	lookupMap := make(map[string]bool)
-	entries, err := os.ReadDir(downloadDir)
-	if err != nil {
-		if os.IsNotExist(err) {
-			return lookupMap, nil
-		}
-		return nil, fmt.Errorf("failed to read download directory %s: %w", downloadDir, err)
-	}
+// sizeCache remembers the disk usage of build directories, so rescans don't
+// walk every installed build again. It is saved with the scan cache, so
+// neither does the first scan after a restart.
+var sizeCache = struct {
+	sync.Mutex
+	entries map[string]cachedSize
+}{entries: make(map[string]cachedSize)}
+
+// cachedSize is the disk usage of a build directory, valid while its
+// version.json has the recorded modification time.
+type cachedSize struct {
+	Stamp time.Time `json:"stamp"`
+	Size  int64     `json:"size"`
+}
 
-	for _, entry := range entries {
-		if entry.IsDir() && entry.Name() != download.OldBuildsDir {
-			dirPath := filepath.Join(downloadDir, entry.Name())
-			buildInfo, err := ReadBuildInfo(dirPath)
-			if err != nil {
-				fmt.Fprintf(os.Stderr, "Error processing directory %s: %v\n", dirPath, err)
-				continue
-			}
-			if buildInfo != nil {
-				lookupMap[buildInfo.Version] = true
-			}
+// installedSize returns the disk usage of the build installed in dir. Builds
+// are only replaced as a whole, rewriting their version.json (or executable,
+// without one), so the size is walked again only once that file changed.
+func installedSize(dir string) int64 {
+	if resolved, err := filepath.EvalSymlinks(dir); err == nil {
+		dir = resolved // Imported builds are links, measure what they point at
+	}
+	info, err := os.Stat(filepath.Join(dir, versionMetaFilename))
+	if os.IsNotExist(err) {
+		if exe := findBlenderExecutable(dir); exe != "" {
+			info, err = os.Stat(exe)
 		}
 	}
+	if err != nil {
+		return 0
+	}
+	sizeCache.Lock()
+	cached, ok := sizeCache.entries[dir]
+	sizeCache.Unlock()
+	if ok && cached.Stamp.Equal(info.ModTime()) {
+		return cached.Size
+	}
 
-	return lookupMap, nil
+	size := diskUsage(dir)
+	sizeCache.Lock()
+	sizeCache.entries[dir] = cachedSize{Stamp: info.ModTime(), Size: size}
+	sizeCache.Unlock()
+	return size
 }
 
-// DeleteBuild finds and deletes a local build by version. Returns true if deletion was successful.
-func DeleteBuild(downloadDir string, version string) (bool, error) {
-	entries, err := os.ReadDir(downloadDir)
+// sameDir reports whether a and b are the same directory, e.g. an imported
+// build and the install root it was imported from.
+func sameDir(a, b string) bool {
+	resolvedA, errA := filepath.EvalSymlinks(a)
+	resolvedB, errB := filepath.EvalSymlinks(b)
+	return errA == nil && errB == nil && resolvedA == resolvedB
+}
+
+// BuildLocalLookupMap creates a map of available local build versions.
+func BuildLocalLookupMap(roots []string) (map[string]bool, error) {
+	builds, err := ScanLocalBuilds(roots...)
 	if err != nil {
-		return false, fmt.Errorf("failed to read download directory %s: %w", downloadDir, err)
+		return nil, err
+	}
+	lookupMap := make(map[string]bool)
+	for _, build := range builds {
+		lookupMap[build.Version] = true
 	}
+	return lookupMap, nil
+}
 
-	for _, entry := range entries {
-		if entry.IsDir() {
-			dirPath := filepath.Join(downloadDir, entry.Name())
-			buildInfo, err := ReadBuildInfo(dirPath)
-			if err != nil {
-				continue
-			}
-			if buildInfo != nil && buildInfo.Version == version {
-				if err := os.RemoveAll(dirPath); err != nil {
-					return false, fmt.Errorf("failed to delete build directory %s: %w", dirPath, err)
-				}
-				return true, nil
-			}
-		}
+// DeleteBuild finds and deletes a local build by version in any of the install
+// roots, moving it to the trash with toTrash. Imported builds only lose their
+// link. Returns true if deletion was successful.
+func DeleteBuild(roots []string, version string, toTrash bool) (bool, error) {
+	dirPath, err := FindBuildDir(roots, version)
+	if errors.Is(err, ErrBuildNotFound) {
+		return false, nil
+	} else if err != nil {
+		return false, err
+	}
+	if err := removeBuildDir(dirPath, toTrash); err != nil {
+		return false, err
 	}
+	unlinkCurrent(roots, dirPath)
+	return true, nil
+}
 
-	return false, nil
+// removeBuildDir deletes the build directory dirPath, or moves it to the
+// trash with toTrash. Imported builds only lose their link.
+func removeBuildDir(dirPath string, toTrash bool) error {
+	if info, err := os.Lstat(dirPath); err == nil && info.Mode()&os.ModeSymlink != 0 {
+		toTrash = false
+	}
+	if toTrash {
+		return moveToTrash(dirPath)
+	}
+	if err := os.RemoveAll(dirPath); err != nil {
+		return fmt.Errorf("failed to delete build directory %s: %w", dirPath, err)
+	}
+	return nil
 }
 
 // LaunchBlenderCmd creates a command to launch Blender for a specific version.
-func LaunchBlenderCmd(downloadDir string, version string) tea.Cmd {
+func LaunchBlenderCmd(roots []string, version string) tea.Cmd {
 	return func() tea.Msg {
-		entries, err := os.ReadDir(downloadDir)
+		blenderExe, err := FindBuildExecutable(roots, version)
 		if err != nil {
-			return fmt.Errorf("failed to read download directory %s: %w", downloadDir, err)
+			return err
 		}
+		return model.BlenderExecMsg{
+			Version:    version,
+			Executable: blenderExe,
+		}
+	}
+}
 
-		for _, entry := range entries {
-			if entry.IsDir() {
-				dirPath := filepath.Join(downloadDir, entry.Name())
-				buildInfo, err := ReadBuildInfo(dirPath)
-				if err != nil {
-					continue
-				}
-				if buildInfo != nil && buildInfo.Version == version {
-					blenderExe := findBlenderExecutable(dirPath)
-					if blenderExe == "" {
-						return fmt.Errorf("could not find Blender executable in %s", dirPath)
-					}
-					return model.BlenderExecMsg{
-						Version:    version,
-						Executable: blenderExe,
-					}
-				}
+// FindBuildExecutable returns the path of the Blender executable of an installed version.
+func FindBuildExecutable(roots []string, version string) (string, error) {
+	dirPath, err := FindBuildDir(roots, version)
+	if err != nil {
+		return "", err
+	}
+	blenderExe := findBlenderExecutable(dirPath)
+	if blenderExe == "" {
+		return "", fmt.Errorf("%w in %s", ErrNoExecutable, dirPath)
+	}
+	return blenderExe, nil
+}
+
+// FindBuildDir returns the directory an installed version was extracted to,
+// looking through the install roots in order.
+func FindBuildDir(roots []string, version string) (string, error) {
+	for _, root := range roots {
+		builds, err := installedBuilds(root)
+		if err != nil {
+			return "", err
+		}
+		for _, installed := range builds {
+			if installed.build.Version == version {
+				return installed.dir, nil
 			}
 		}
-
-		return fmt.Errorf("blender version %s not found", version)
 	}
+	return "", fmt.Errorf("blender version %s: %w", version, ErrBuildNotFound)
 }
 
 // OpenDownloadDirCmd creates a command to open the download directory.
@@ -190,23 +386,48 @@ func OpenDirCmd(dir string) tea.Cmd {
 }
 
 // findBlenderExecutable locates the Blender executable in the installation directory.
+// On Windows that's blender.exe, which keeps a console for Blender's output and
+// answers --version, unlike the blender-launcher.exe beside it in newer builds.
 func findBlenderExecutable(installDir string) string {
-	var candidate string
+	var candidates []string
 	switch runtime.GOOS {
 	case "windows":
-		candidate = filepath.Join(installDir, "blender-launcher.exe")
-	case "linux":
-		candidate = filepath.Join(installDir, "blender")
+		candidates = []string{
+			filepath.Join(installDir, "blender.exe"),
+			filepath.Join(installDir, "blender-launcher.exe"),
+		}
+	case "darwin":
+		bundle := appBundle(installDir)
+		candidates = []string{
+			filepath.Join(bundle, "Contents", "MacOS", "Blender"),
+			filepath.Join(bundle, "Contents", "MacOS", "blender"),
+		}
 	default:
-		candidate = filepath.Join(installDir, "blender")
+		candidates = []string{filepath.Join(installDir, "blender")}
 	}
 
-	if _, err := os.Stat(candidate); err == nil {
-		return candidate
+	for _, candidate := range candidates {
+		if _, err := os.Stat(candidate); err == nil {
+			return candidate
+		}
 	}
 	return ""
 }
 
+// appBundle returns the macOS app bundle of the build in installDir:
+// Blender.app, or else the .app the disk image named otherwise, e.g.
+// "Blender 4.4.app". It is Blender.app if there is none.
+func appBundle(installDir string) string {
+	bundle := filepath.Join(installDir, "Blender.app")
+	if _, err := os.Stat(bundle); err == nil {
+		return bundle
+	}
+	if matches, _ := filepath.Glob(filepath.Join(installDir, "*.app")); len(matches) > 0 {
+		return matches[0]
+	}
+	return bundle
+}
+
 // OpenFileExplorer opens the default file explorer to the specified directory.
 func OpenFileExplorer(dir string) error {
 	var cmd *exec.Cmd
@@ -238,33 +459,31 @@ func openFileExplorer(dir string) error {
 	return OpenFileExplorer(dir)
 }
 
-// CleanOldBuilds removes all builds from the .oldbuilds directory.
+// CleanOldBuilds removes all builds from the .oldbuilds directories of roots.
 // Returns the number of cleaned builds and any error encountered.
-func CleanOldBuilds(downloadDir string) (int, error) {
-	oldBuildsDir := filepath.Join(downloadDir, download.OldBuildsDir)
-
-	// Check if the old builds directory exists
-	if _, err := os.Stat(oldBuildsDir); os.IsNotExist(err) {
-		// If it doesn't exist, there's nothing to clean
-		return 0, nil
-	}
-
-	// Read the contents of the old builds directory
-	entries, err := os.ReadDir(oldBuildsDir)
-	if err != nil {
-		return 0, fmt.Errorf("failed to read %s directory: %w", download.OldBuildsDir, err)
-	}
-
+func CleanOldBuilds(roots []string) (int, error) {
 	cleanedCount := 0
+	for _, root := range roots {
+		oldBuildsDir := filepath.Join(root, download.OldBuildsDir)
 
-	// Delete each old build
-	for _, entry := range entries {
-		if entry.IsDir() {
-			dirPath := filepath.Join(oldBuildsDir, entry.Name())
-			if err := os.RemoveAll(dirPath); err != nil {
-				return cleanedCount, fmt.Errorf("failed to delete old build %s: %w", entry.Name(), err)
+		// Read the contents of the old builds directory, if there is one
+		entries, err := os.ReadDir(oldBuildsDir)
+		if os.IsNotExist(err) {
+			continue
+		}
+		if err != nil {
+			return cleanedCount, fmt.Errorf("failed to read %s directory: %w", oldBuildsDir, err)
+		}
+
+		// Delete each old build
+		for _, entry := range entries {
+			if entry.IsDir() {
+				dirPath := filepath.Join(oldBuildsDir, entry.Name())
+				if err := os.RemoveAll(dirPath); err != nil {
+					return cleanedCount, fmt.Errorf("failed to delete old build %s: %w", entry.Name(), err)
+				}
+				cleanedCount++
 			}
-			cleanedCount++
 		}
 	}
 
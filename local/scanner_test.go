@@ -0,0 +1,47 @@
+package local
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+)
+
+func TestAppBundle(t *testing.T) {
+	tests := []struct {
+		name    string
+		bundles []string // App bundles in the build directory
+		want    string
+	}{
+		{"Blender.app", []string{"Blender.app"}, "Blender.app"},
+		{"named by the disk image", []string{"Blender 4.4.app"}, "Blender 4.4.app"},
+		{"Blender.app first", []string{"Blender 4.4.app", "Blender.app"}, "Blender.app"},
+		{"no bundle", nil, "Blender.app"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			for _, bundle := range tt.bundles {
+				macOS := filepath.Join(dir, bundle, "Contents", "MacOS")
+				if err := os.MkdirAll(macOS, 0750); err != nil {
+					t.Fatalf("Failed to create %s: %v", macOS, err)
+				}
+				if err := os.WriteFile(filepath.Join(macOS, "Blender"), []byte("#!/bin/sh\n"), 0755); err != nil {
+					t.Fatalf("Failed to write the executable: %v", err)
+				}
+			}
+
+			want := filepath.Join(dir, tt.want)
+			if got := appBundle(dir); got != want {
+				t.Errorf("Expected %s, got %s", want, got)
+			}
+			// The executable inside the bundle is what macOS launches
+			if runtime.GOOS == "darwin" && len(tt.bundles) > 0 {
+				exe := filepath.Join(want, "Contents", "MacOS", "Blender")
+				if got := findBlenderExecutable(dir); got != exe {
+					t.Errorf("Expected the executable %s, got %s", exe, got)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,112 @@
+package local
+
+import (
+	"TUI-Blender-Launcher/model"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+)
+
+// seriesDirPattern matches the per-series directories of Blender's user files.
+var seriesDirPattern = regexp.MustCompile(`^\d+\.\d+$`)
+
+// PreviousSettings returns the newest series before that of version with
+// Blender user files to copy, e.g. "4.2" when installing the first 4.3.
+// Returns "" once version's series has user files of its own.
+func PreviousSettings(version string) string {
+	series := model.VersionSeries(version)
+	userDir := blenderUserDir(series)
+	if _, err := os.Stat(userDir); !os.IsNotExist(err) {
+		return ""
+	}
+	entries, err := os.ReadDir(blenderUserBase())
+	if err != nil {
+		return ""
+	}
+	previous := ""
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() && seriesDirPattern.MatchString(name) && compareVersions(name, series) < 0 &&
+			(previous == "" || compareVersions(name, previous) > 0) {
+			previous = name
+		}
+	}
+	return previous
+}
+
+// HasUserPreferences reports whether Blender saved preferences for the series
+// of version, which keep the add-ons enabled in it.
+func HasUserPreferences(version string) bool {
+	_, err := os.Stat(filepath.Join(blenderConfigDir(model.VersionSeries(version)), "userpref.blend"))
+	return err == nil
+}
+
+// CopyPreviousSettings copies the Blender user files of series from,
+// preferences, add-ons and extensions, to the series of version, like
+// Blender's own "Load Previous Settings".
+func CopyPreviousSettings(from, version string) error {
+	destDir := blenderUserDir(model.VersionSeries(version))
+	if _, err := os.Stat(destDir); err == nil {
+		return fmt.Errorf("%s exists already", destDir)
+	}
+	if err := copyBuild(blenderUserDir(from), destDir, nil, nil); err != nil {
+		return fmt.Errorf("failed to copy the settings of Blender %s: %w", from, err)
+	}
+	return nil
+}
+
+// UserConfig is the directory of Blender's user files of one version series.
+type UserConfig struct {
+	Series    string
+	Path      string
+	Size      int64
+	Installed bool // A build of the series is installed
+}
+
+// ListUserConfigs lists the directories of Blender's user files, newest
+// series first, marking those of the series of the installed builds.
+func ListUserConfigs(installed []model.BlenderBuild) ([]UserConfig, error) {
+	base := blenderUserBase()
+	entries, err := os.ReadDir(base)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to read %s: %w", base, err)
+	}
+	series := make(map[string]bool, len(installed))
+	for _, build := range installed {
+		series[model.VersionSeries(build.Version)] = true
+	}
+
+	var configs []UserConfig
+	for _, entry := range entries {
+		if entry.IsDir() && seriesDirPattern.MatchString(entry.Name()) {
+			configs = append(configs, UserConfig{Series: entry.Name(), Path: filepath.Join(base, entry.Name()), Installed: series[entry.Name()]})
+		}
+	}
+	forEachParallel(len(configs), func(i int) {
+		configs[i].Size = diskUsage(configs[i].Path)
+	})
+	sort.Slice(configs, func(i, j int) bool {
+		return compareVersions(configs[i].Series, configs[j].Series) > 0
+	})
+	return configs, nil
+}
+
+// RemoveUserConfigs moves the given directories of Blender's user files to
+// the trash, as they hold settings and add-ons that can't be downloaded again,
+// and returns their size. Without toTrash they are deleted for good, which
+// is only for when the trash has failed with ErrNoTrash and that is confirmed.
+func RemoveUserConfigs(configs []UserConfig, toTrash bool) (int64, error) {
+	var freed int64
+	for _, config := range configs {
+		if err := removeBuildDir(config.Path, toTrash); err != nil {
+			return freed, err
+		}
+		freed += config.Size
+	}
+	return freed, nil
+}
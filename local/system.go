@@ -0,0 +1,125 @@
+package local
+
+import (
+	"TUI-Blender-Launcher/model"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"sync"
+)
+
+// systemInstall is a place Blender is commonly installed to outside the launcher.
+type systemInstall struct {
+	source string // Where it comes from, e.g. "Steam"
+	exe    string // Executable, may hold a glob pattern
+}
+
+// systemInstalls returns the places Blender is commonly installed to on this
+// platform by package managers, stores and installers.
+func systemInstalls() []systemInstall {
+	homeDir, _ := os.UserHomeDir()
+	var installs []systemInstall
+	switch runtime.GOOS {
+	case "windows":
+		programFiles := os.Getenv("ProgramFiles")
+		programFilesX86 := os.Getenv("ProgramFiles(x86)")
+		installs = []systemInstall{
+			{"System", filepath.Join(programFiles, "Blender Foundation", "Blender*", "blender.exe")},
+			{"Steam", filepath.Join(programFilesX86, "Steam", "steamapps", "common", "Blender", "blender.exe")},
+		}
+	case "darwin":
+		installs = []systemInstall{
+			{"System", "/Applications/Blender*.app/Contents/MacOS/Blender"},
+			{"System", filepath.Join(homeDir, "Applications", "Blender*.app", "Contents", "MacOS", "Blender")},
+			{"Steam", filepath.Join(homeDir, "Library", "Application Support", "Steam", "steamapps", "common", "Blender", "Blender.app", "Contents", "MacOS", "Blender")},
+		}
+	default:
+		installs = []systemInstall{
+			{"System", "/usr/bin/blender"},
+			{"System", "/usr/local/bin/blender"},
+			{"System", "/opt/blender*/blender"},
+			{"Flatpak", "/var/lib/flatpak/exports/bin/org.blender.Blender"},
+			{"Flatpak", filepath.Join(homeDir, ".local", "share", "flatpak", "exports", "bin", "org.blender.Blender")},
+			{"Snap", "/snap/bin/blender"},
+			{"Steam", filepath.Join(homeDir, ".steam", "steam", "steamapps", "common", "Blender", "blender")},
+			{"Steam", filepath.Join(homeDir, ".local", "share", "Steam", "steamapps", "common", "Blender", "blender")},
+		}
+	}
+	name := "blender"
+	if runtime.GOOS == "windows" {
+		name = "blender.exe"
+	}
+	for _, dir := range filepath.SplitList(os.Getenv("PATH")) {
+		if dir != "" {
+			installs = append(installs, systemInstall{"PATH", filepath.Join(dir, name)})
+		}
+	}
+	return installs
+}
+
+// ScanSystemBuilds finds the Blender installs the launcher doesn't manage, in
+// the places systemInstalls lists, skipping those in the install roots. Their
+// metadata comes from running them with --version, so installs that don't
+// run are left out. The builds have StateSystem, with their Executable set
+// and where they come from in FileName.
+func ScanSystemBuilds(roots []string) []model.BlenderBuild {
+	var managed []string
+	for _, root := range roots {
+		if resolved, err := filepath.EvalSymlinks(root); err == nil {
+			managed = append(managed, resolved+string(filepath.Separator))
+		}
+	}
+
+	// The same install is often reachable from several places, e.g. PATH and /usr/bin
+	var installs []systemInstall
+	seen := make(map[string]bool)
+	for _, install := range systemInstalls() {
+		matches, _ := filepath.Glob(install.exe)
+		for _, exe := range matches {
+			resolved, err := filepath.EvalSymlinks(exe)
+			if err != nil || seen[resolved] || isManaged(resolved, managed) {
+				continue
+			}
+			seen[resolved] = true
+			installs = append(installs, systemInstall{source: install.source, exe: exe})
+		}
+	}
+
+	found := make([]*model.BlenderBuild, len(installs))
+	var wg sync.WaitGroup
+	for i, install := range installs {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			build, err := probeBuildInfo(install.exe)
+			if err != nil {
+				return
+			}
+			build.Status = model.StateSystem
+			build.Executable = install.exe
+			build.FileName = install.source
+			found[i] = &build
+		}()
+	}
+	wg.Wait()
+
+	var builds []model.BlenderBuild
+	for _, build := range found {
+		if build != nil {
+			builds = append(builds, *build)
+		}
+	}
+	return builds
+}
+
+// isManaged reports whether the resolved path of an executable lies in one of
+// the resolved install roots, each ending in a separator.
+func isManaged(exe string, managed []string) bool {
+	for _, root := range managed {
+		if strings.HasPrefix(exe, root) {
+			return true
+		}
+	}
+	return false
+}
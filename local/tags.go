@@ -0,0 +1,95 @@
+package local
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// ParseTags splits a comma or space separated list of tags, dropping
+// duplicates and empty entries.
+func ParseTags(input string) []string {
+	var tags []string
+	seen := make(map[string]bool)
+	for _, tag := range strings.FieldsFunc(input, func(r rune) bool { return r == ',' || r == ' ' }) {
+		if key := strings.ToLower(tag); !seen[key] {
+			seen[key] = true
+			tags = append(tags, tag)
+		}
+	}
+	return tags
+}
+
+// SetBuildTags stores the tags and favorite mark of an installed version in
+// its version.json.
+func SetBuildTags(roots []string, version string, tags []string, favorite bool) error {
+	return updateVersionMeta(roots, version, func(meta map[string]any) {
+		delete(meta, "tags")
+		delete(meta, "favorite")
+		if len(tags) > 0 {
+			meta["tags"] = tags
+		}
+		if favorite {
+			meta["favorite"] = true
+		}
+	})
+}
+
+// SetBuildExpiry makes an installed version temporary until expires, or keeps
+// it for good if expires is nil.
+func SetBuildExpiry(roots []string, version string, expires *time.Time) error {
+	return updateVersionMeta(roots, version, func(meta map[string]any) {
+		delete(meta, "expires_at")
+		if expires != nil {
+			meta["expires_at"] = expires
+		}
+	})
+}
+
+// RecordBuildLaunch stores in the version.json of an installed version that
+// it was launched at the given time, counting the launch.
+func RecordBuildLaunch(roots []string, version string, at time.Time) error {
+	return updateVersionMeta(roots, version, func(meta map[string]any) {
+		count, _ := meta["launch_count"].(float64)
+		meta["launch_count"] = int(count) + 1
+		meta["last_launched"] = at
+	})
+}
+
+// updateVersionMeta applies update to the version.json of an installed
+// version. Fields update leaves alone are written back as they were read.
+func updateVersionMeta(roots []string, version string, update func(meta map[string]any)) error {
+	dir, err := FindBuildDir(roots, version)
+	if err != nil {
+		return err
+	}
+	metaPath := filepath.Join(dir, versionMetaFilename)
+	data, err := os.ReadFile(metaPath)
+	if os.IsNotExist(err) {
+		// Builds listed from what their executable reports get a version.json now
+		if build := detectBuildInfo(dir); build != nil {
+			data, err = json.Marshal(build)
+		}
+	}
+	if err != nil {
+		return fmt.Errorf("failed to read %s: %w", metaPath, err)
+	}
+	var meta map[string]any
+	if err := json.Unmarshal(data, &meta); err != nil {
+		return fmt.Errorf("failed to parse %s: %w", metaPath, err)
+	}
+
+	update(meta)
+
+	data, err = json.MarshalIndent(meta, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal build metadata: %w", err)
+	}
+	if err := os.WriteFile(metaPath, data, 0644); err != nil {
+		return fmt.Errorf("failed to write %s: %w", metaPath, err)
+	}
+	return nil
+}
@@ -0,0 +1,28 @@
+package local
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// ErrNoTrash is returned when the platform's trash can't take a file.
+var ErrNoTrash = errors.New("no trash available")
+
+// moveToTrash moves path to the trash of the desktop, so it can be restored
+// from there: the XDG trash on Linux and BSDs, ~/.Trash on macOS and the
+// Recycle Bin on Windows.
+func moveToTrash(path string) error {
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return fmt.Errorf("failed to resolve %s: %w", path, err)
+	}
+	if _, err := os.Lstat(abs); err != nil {
+		return fmt.Errorf("failed to trash %s: %w", path, err)
+	}
+	if err := trash(abs); err != nil {
+		return fmt.Errorf("failed to trash %s: %w", path, err)
+	}
+	return nil
+}
@@ -0,0 +1,144 @@
+//go:build !windows
+// +build !windows
+
+package local
+
+import (
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"syscall"
+	"time"
+)
+
+// trash moves the absolute path into the trash. On macOS that is ~/.Trash,
+// elsewhere the XDG trash of the home directory or, for files on another
+// device, the .Trash-$uid directory at the top of that device.
+func trash(path string) error {
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrNoTrash, err)
+	}
+	if runtime.GOOS == "darwin" {
+		dir := filepath.Join(homeDir, ".Trash")
+		if err := os.Rename(path, uniqueTrashName(dir, filepath.Base(path))); err != nil {
+			return fmt.Errorf("%w: %v", ErrNoTrash, err)
+		}
+		return nil
+	}
+
+	dataHome := os.Getenv("XDG_DATA_HOME")
+	if dataHome == "" {
+		dataHome = filepath.Join(homeDir, ".local", "share")
+	}
+	trashDir := filepath.Join(dataHome, "Trash")
+	if !sameDevice(path, dataHome) {
+		top := mountPoint(path)
+		trashDir = filepath.Join(top, ".Trash-"+strconv.Itoa(os.Getuid()))
+	}
+	return xdgTrash(path, trashDir)
+}
+
+// xdgTrash moves path into trashDir as the XDG trash specification lays out:
+// the file goes to files/ and a .trashinfo in info/ records where it came from.
+func xdgTrash(path, trashDir string) error {
+	filesDir := filepath.Join(trashDir, "files")
+	infoDir := filepath.Join(trashDir, "info")
+	for _, dir := range []string{filesDir, infoDir} {
+		if err := os.MkdirAll(dir, 0700); err != nil {
+			return fmt.Errorf("%w: %v", ErrNoTrash, err)
+		}
+	}
+
+	// Claim a name by creating its info file, which fails if it is taken
+	var name string
+	var info *os.File
+	for i := 1; ; i++ {
+		name = filepath.Base(path)
+		if i > 1 {
+			name = fmt.Sprintf("%s.%d", name, i)
+		}
+		file, err := os.OpenFile(filepath.Join(infoDir, name+".trashinfo"), os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0600)
+		if err == nil {
+			info = file
+			break
+		}
+		if !os.IsExist(err) {
+			return fmt.Errorf("%w: %v", ErrNoTrash, err)
+		}
+	}
+
+	escaped := (&url.URL{Path: path}).EscapedPath()
+	_, err := fmt.Fprintf(info, "[Trash Info]\nPath=%s\nDeletionDate=%s\n", escaped, time.Now().Format("2006-01-02T15:04:05"))
+	if closeErr := info.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Rename(path, filepath.Join(filesDir, name))
+	}
+	if err != nil {
+		os.Remove(filepath.Join(infoDir, name+".trashinfo"))
+		return fmt.Errorf("%w: %v", ErrNoTrash, err)
+	}
+	return nil
+}
+
+// uniqueTrashName returns a path in dir for name that isn't taken yet.
+func uniqueTrashName(dir, name string) string {
+	candidate := filepath.Join(dir, name)
+	for i := 2; ; i++ {
+		if _, err := os.Lstat(candidate); os.IsNotExist(err) {
+			return candidate
+		}
+		candidate = filepath.Join(dir, fmt.Sprintf("%s %d", name, i))
+	}
+}
+
+// sameDevice reports whether path and dir are on the same device, so path can
+// be renamed into dir. A dir that doesn't exist yet counts for its parent.
+func sameDevice(path, dir string) bool {
+	for {
+		if _, err := os.Stat(dir); err == nil || dir == filepath.Dir(dir) {
+			break
+		}
+		dir = filepath.Dir(dir)
+	}
+	a, errA := deviceOf(path)
+	b, errB := deviceOf(dir)
+	return errA == nil && errB == nil && a == b
+}
+
+// mountPoint returns the top directory of the device path is on.
+func mountPoint(path string) string {
+	dev, err := deviceOf(path)
+	if err != nil {
+		return filepath.Dir(path)
+	}
+	top := filepath.Dir(path)
+	for {
+		parent := filepath.Dir(top)
+		if parent == top {
+			return top
+		}
+		if parentDev, err := deviceOf(parent); err != nil || parentDev != dev {
+			return top
+		}
+		top = parent
+	}
+}
+
+// deviceOf returns the ID of the device holding path.
+func deviceOf(path string) (uint64, error) {
+	info, err := os.Lstat(path)
+	if err != nil {
+		return 0, err
+	}
+	stat, ok := info.Sys().(*syscall.Stat_t)
+	if !ok {
+		return 0, fmt.Errorf("no device of %s", path)
+	}
+	return uint64(stat.Dev), nil
+}
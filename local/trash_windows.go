@@ -0,0 +1,28 @@
+//go:build windows
+// +build windows
+
+package local
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+)
+
+// trash moves the absolute path into the Recycle Bin, through the .NET
+// helpers PowerShell has at hand.
+func trash(path string) error {
+	method := "DeleteFile"
+	if info, err := os.Stat(path); err == nil && info.IsDir() {
+		method = "DeleteDirectory"
+	}
+	script := fmt.Sprintf("Add-Type -AssemblyName Microsoft.VisualBasic; "+
+		"[Microsoft.VisualBasic.FileIO.FileSystem]::%s('%s', 'OnlyErrorDialogs', 'SendToRecycleBin')",
+		method, strings.ReplaceAll(path, "'", "''"))
+	out, err := exec.Command("powershell", "-NoProfile", "-NonInteractive", "-Command", script).CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("%w: %v: %s", ErrNoTrash, err, strings.TrimSpace(string(out)))
+	}
+	return nil
+}
@@ -0,0 +1,109 @@
+package local
+
+import (
+	"TUI-Blender-Launcher/config"
+	"TUI-Blender-Launcher/model"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// usageFilename is the file in the state directory that stores launch counts.
+// Older versions kept it in the config directory, where it is still read from
+// until the next launch is recorded.
+const usageFilename = "usage.json"
+
+// LaunchUsage maps a version series (e.g. "4.3") to how often it has been launched.
+type LaunchUsage map[string]int
+
+// usagePath returns the full path to the launch usage file.
+func usagePath() (string, error) {
+	stateDir, err := config.GetStateDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(stateDir, usageFilename), nil
+}
+
+// legacyUsagePath returns where older versions kept the launch usage file,
+// "" if that is where it is kept now.
+func legacyUsagePath() string {
+	configDir, err := config.GetConfigDir()
+	if err != nil {
+		return ""
+	}
+	path := filepath.Join(configDir, usageFilename)
+	if current, err := usagePath(); err == nil && current == path {
+		return ""
+	}
+	return path
+}
+
+// LoadLaunchUsage reads the recorded launch counts.
+// A missing file is not an error and yields empty usage.
+func LoadLaunchUsage() (LaunchUsage, error) {
+	usage := make(LaunchUsage)
+
+	path, err := usagePath()
+	if err != nil {
+		return usage, err
+	}
+
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		if legacyPath := legacyUsagePath(); legacyPath != "" {
+			path = legacyPath
+			data, err = os.ReadFile(path)
+		}
+	}
+	if err != nil {
+		if os.IsNotExist(err) {
+			return usage, nil
+		}
+		return usage, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+
+	if err := json.Unmarshal(data, &usage); err != nil {
+		return make(LaunchUsage), fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+	return usage, nil
+}
+
+// RecordLaunch increments the launch count for the series of the given version.
+func RecordLaunch(version string) error {
+	usage, err := LoadLaunchUsage()
+	if err != nil {
+		return err
+	}
+	usage[model.VersionSeries(version)]++
+
+	path, err := usagePath()
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0750); err != nil {
+		return fmt.Errorf("could not create state directory: %w", err)
+	}
+
+	data, err := json.MarshalIndent(usage, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal launch usage: %w", err)
+	}
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		return fmt.Errorf("failed to write %s: %w", path, err)
+	}
+	return nil
+}
+
+// MostLaunchedSeries returns the series launched most often, or "" if nothing has been launched yet.
+func (u LaunchUsage) MostLaunchedSeries() string {
+	best := ""
+	for series, count := range u {
+		// Break ties on the newer series so the result is stable
+		if best == "" || count > u[best] || (count == u[best] && series > best) {
+			best = series
+		}
+	}
+	return best
+}
@@ -0,0 +1,38 @@
+package local
+
+import (
+	"TUI-Blender-Launcher/download"
+	"fmt"
+	"hash/fnv"
+	"os"
+	"path/filepath"
+)
+
+// LibraryFingerprint summarizes which builds are installed in the roots, so
+// builds added, removed or replaced by other programs are noticed by comparing
+// fingerprints. It only reads the roots and stats each build's version.json,
+// which stays cheap enough to poll and works on network drives that don't
+// report changes.
+func LibraryFingerprint(roots []string) string {
+	h := fnv.New64a()
+	for _, root := range roots {
+		entries, err := os.ReadDir(root)
+		if err != nil {
+			fmt.Fprintf(h, "%s:unreadable\n", root)
+			continue
+		}
+		ignore := download.LoadIgnore(root)
+		for _, entry := range entries {
+			if !(entry.IsDir() || isBuildLink(root, entry)) || entry.Name() == download.DownloadingDir || entry.Name() == download.OldBuildsDir ||
+				entry.Name() == download.ArchivesDir || ignore.Ignored(entry.Name()) {
+				continue
+			}
+			info, err := os.Stat(filepath.Join(root, entry.Name(), versionMetaFilename))
+			if err != nil {
+				continue // Not a build, or one still being moved in
+			}
+			fmt.Fprintf(h, "%s/%s:%d:%d\n", root, entry.Name(), info.Size(), info.ModTime().UnixNano())
+		}
+	}
+	return fmt.Sprintf("%016x", h.Sum64())
+}
@@ -1,6 +1,7 @@
 package main
 
 import (
+	"TUI-Blender-Launcher/cli"
 	"TUI-Blender-Launcher/config" // Import config package
 	"TUI-Blender-Launcher/tui"    // Import the tui package
 	"fmt"
@@ -10,6 +11,16 @@ import (
 )
 
 func main() {
+	// A config file given with --config replaces the default one, for the CLI and the TUI
+	configPath, args, err := cli.SplitConfigFlag(os.Args[1:])
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(cli.ExitCode(err))
+	}
+	if configPath != "" {
+		config.SetConfigPath(configPath)
+	}
+
 	// Load configuration
 	cfg, err := config.LoadConfig()
 	if err != nil {
@@ -17,6 +28,11 @@ func main() {
 		os.Exit(1)
 	}
 
+	// Any arguments select a non-interactive CLI command
+	if len(args) > 0 {
+		os.Exit(cli.Run(cfg, args))
+	}
+
 	// Check if config file *actually* exists (LoadConfig returns defaults if not)
 	configFilePath, _ := config.GetConfigPath()
 	needsInitialSetup := false
@@ -4,6 +4,8 @@ import (
 	"encoding/json"
 	"fmt"
 	"sort"
+	"strconv"
+	"strings"
 	"time"
 )
 
@@ -20,6 +22,12 @@ const (
 	StateUpdate
 	StateFailed
 	StateCancelled
+	StatePrefetched
+	StateQueued
+	StatePaused
+	StateScheduled
+	StateInterrupted
+	StateSystem // Installed outside the launcher, e.g. by a package manager
 )
 
 // String returns the string representation of the BuildState
@@ -41,6 +49,18 @@ func (s BuildState) String() string {
 		return "Failed"
 	case StateCancelled:
 		return "Cancelled"
+	case StatePrefetched:
+		return "Prefetched"
+	case StateQueued:
+		return "Queued"
+	case StatePaused:
+		return "Paused"
+	case StateScheduled:
+		return "Scheduled"
+	case StateInterrupted:
+		return "Interrupted"
+	case StateSystem:
+		return "System"
 	default:
 		return "Unknown"
 	}
@@ -104,8 +124,20 @@ type BlenderBuild struct {
 	FileExtension   string    `json:"file_extension"` // e.g., "zip", "tar.gz", "sha256", "msi"
 	ReleaseCycle    string    `json:"release_cycle"`  // e.g., "daily", "stable", "candidate" (replaces previous 'Type')
 
+	// Set by the user, stored in version.json
+	Tags      []string   `json:"tags,omitempty"`       // Labels such as "production" or "testme"
+	Favorite  bool       `json:"favorite,omitempty"`   // Marked as a favorite
+	ExpiresAt *time.Time `json:"expires_at,omitempty"` // Temporary install, offered for cleanup after this
+
+	// Recorded on launch, stored in version.json
+	LastLaunched *time.Time `json:"last_launched,omitempty"` // When the build was last launched
+	LaunchCount  int        `json:"launch_count,omitempty"`  // How often the build has been launched
+
 	// Internal state (not from API)
-	Status BuildState // Changed from types.BuildState to BuildState
+	Status        BuildState // Changed from types.BuildState to BuildState
+	InstalledSize int64      `json:"-"` // Size on disk of an installed build, 0 when unknown
+	Duplicates    []string   `json:"-"` // Other directories holding the same installed build
+	Executable    string     `json:"-"` // Executable of a system install, which has no build directory
 	// Selected field removed - we only work with highlighted builds now
 }
 
@@ -118,21 +150,84 @@ type BlenderLaunchedMsg struct {
 // BlenderExecMsg is sent when Blender should be executed directly
 // This will cause the TUI to exit and exec Blender in its place
 type BlenderExecMsg struct {
-	Version    string // The version of Blender to launch
-	Executable string // The path to the Blender executable
+	Version    string   // The version of Blender to launch
+	Executable string   // The path to the Blender executable
+	EnvProfile string   // Env profile to launch with, the configured one if empty
+	File       string   // .blend file to open, none if empty
+	Args       []string // Arguments of the launch preset, passed before File
+	Integrated bool     // Stay on the integrated GPU despite gpu_offload
+	Display    string   // Display server to force, one of launch.Displays or Blender's choice if empty
+	Sandboxed  bool     // Launch through the sandbox command, the build being tagged for it
 }
 
 // DownloadState holds progress info for an active download
 type DownloadState struct {
-	BuildID     string        // Unique identifier for build (version + hash)
-	Progress    float64       // Progress from 0.0 to 1.0
-	Current     int64         // Bytes downloaded so far (renamed from CurrentBytes)
-	Total       int64         // Total bytes to download (renamed from TotalBytes)
-	Speed       float64       // Download speed in bytes/sec
-	BuildState  BuildState    // Changed from Message to BuildState
-	LastUpdated time.Time     // Timestamp of last progress update
-	StartTime   time.Time     // When the download started
-	CancelCh    chan struct{} // Per-download cancel channel
+	BuildID       string        // Unique identifier for build (version + hash)
+	Progress      float64       // Progress from 0.0 to 1.0
+	Current       int64         // Bytes downloaded so far (renamed from CurrentBytes)
+	Total         int64         // Total bytes to download (renamed from TotalBytes)
+	Speed         float64       // Download speed in bytes/sec
+	ResumedFrom   float64       // Progress the download resumed from (0 when started fresh)
+	QueuePosition int           // 1-based position while waiting for a download slot
+	RateLimit     float64       // Active bandwidth limit in bytes/sec (0 when unlimited)
+	Retry         int           // Current automatic retry (0 on the first attempt)
+	MaxRetries    int           // Automatic retries allowed before the download fails
+	RetryNow      chan struct{} // Skips the backoff before the next retry
+	Err           error         // Why the download failed, classified by the download package
+	BuildState    BuildState    // Changed from Message to BuildState
+	LastUpdated   time.Time     // Timestamp of last progress update
+	StartTime     time.Time     // When the download started
+	ScheduledAt   time.Time     // When a scheduled download will start
+	CancelCh      chan struct{} // Per-download cancel channel
+}
+
+// VersionSeries returns the major.minor series of a version string, e.g. "4.3" for "4.3.2".
+func VersionSeries(version string) string {
+	parts := strings.SplitN(version, ".", 3)
+	if len(parts) < 2 {
+		return version
+	}
+	return parts[0] + "." + parts[1]
+}
+
+// SortBuildsByKey sorts builds by a computed string key, such as the cell of a
+// custom column. Keys that are both numbers compare numerically, ties fall back
+// to the version.
+func SortBuildsByKey(builds []BlenderBuild, key func(BlenderBuild) string, reverse bool) []BlenderBuild {
+	type keyedBuild struct {
+		build BlenderBuild
+		key   string
+	}
+	keyed := make([]keyedBuild, len(builds))
+	for i, build := range builds {
+		keyed[i] = keyedBuild{build, key(build)}
+	}
+
+	less := func(a, b string) bool {
+		aNum, aErr := strconv.ParseFloat(a, 64)
+		bNum, bErr := strconv.ParseFloat(b, 64)
+		if aErr == nil && bErr == nil {
+			return aNum < bNum
+		}
+		return a < b
+	}
+
+	sort.SliceStable(keyed, func(i, j int) bool {
+		a, b := keyed[i], keyed[j]
+		if a.key != b.key {
+			if reverse {
+				return less(b.key, a.key)
+			}
+			return less(a.key, b.key)
+		}
+		return a.build.Version < b.build.Version
+	})
+
+	sortedBuilds := make([]BlenderBuild, len(keyed))
+	for i, k := range keyed {
+		sortedBuilds[i] = k.build
+	}
+	return sortedBuilds
 }
 
 // FormatByteSize converts bytes to human-readable sizes
@@ -149,6 +244,30 @@ func FormatByteSize(bytes int64) string {
 	return fmt.Sprintf("%.1f%cB", float64(bytes)/float64(div), "KMGTPE"[exp])
 }
 
+// HasTag reports whether the build is tagged with tag, ignoring case.
+func (b BlenderBuild) HasTag(tag string) bool {
+	for _, t := range b.Tags {
+		if strings.EqualFold(t, tag) {
+			return true
+		}
+	}
+	return false
+}
+
+// Expired reports whether the build is a temporary install past its expiry.
+func (b BlenderBuild) Expired(now time.Time) bool {
+	return b.ExpiresAt != nil && !now.Before(*b.ExpiresAt)
+}
+
+// ShownSize returns the size shown for a build: its size on disk once
+// installed, else the size of its archive.
+func (b BlenderBuild) ShownSize() int64 {
+	if b.Status == StateLocal && b.InstalledSize > 0 {
+		return b.InstalledSize
+	}
+	return b.Size
+}
+
 // FormatBuildDate formats a build date in yyyy-mm-dd-hh-mm format
 func FormatBuildDate(t Timestamp) string {
 	return t.Time().Format("2006-01-02-15:04")
@@ -181,7 +300,7 @@ func SortBuilds(builds []BlenderBuild, column int, reverse bool) []BlenderBuild
 			return a.Hash < b.Hash
 		},
 		5: func(a, b BlenderBuild) bool { // Size
-			return a.Size < b.Size
+			return a.ShownSize() < b.ShownSize()
 		},
 		6: func(a, b BlenderBuild) bool { // Build Date
 			return a.BuildDate.Time().Before(b.BuildDate.Time())
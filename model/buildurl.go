@@ -0,0 +1,100 @@
+package model
+
+import (
+	"fmt"
+	"net/url"
+	"path"
+	"regexp"
+	"strings"
+)
+
+// buildFileNamePattern matches builder archive names such as
+// "blender-4.2.0-alpha+main.3c6b3c2a0f29-linux.x86_64-release.tar.xz".
+var buildFileNamePattern = regexp.MustCompile(
+	`^blender-(\d+\.\d+\.\d+)-([a-z]+)\+([^.]+)\.([0-9a-f]+)-([a-z]+)\.([a-z0-9_]+)-release\.(tar\.xz|zip|dmg)$`)
+
+// releaseDirPattern matches the directories release archives extract to,
+// such as "blender-4.2.1-linux-x64" or "blender-3.6.0-windows-x64".
+var releaseDirPattern = regexp.MustCompile(`^blender-(\d+\.\d+(?:\.\d+)?)-`)
+
+// urlPattern matches an http(s) URL in free text.
+var urlPattern = regexp.MustCompile(`https?://\S+`)
+
+// hashPattern matches a (short or full) git commit hash in free text.
+var hashPattern = regexp.MustCompile(`\b[0-9a-f]{7,40}\b`)
+
+// ParseBuildURL builds a BlenderBuild from a direct builder download URL, so a
+// shared link can be installed even when it isn't in the fetched catalogue.
+// The size is unknown, so the downloaded archive isn't checked against it.
+func ParseBuildURL(rawURL string) (BlenderBuild, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") {
+		return BlenderBuild{}, fmt.Errorf("not a download URL: %s", rawURL)
+	}
+
+	fileName := path.Base(u.Path)
+	m := buildFileNamePattern.FindStringSubmatch(fileName)
+	if m == nil {
+		return BlenderBuild{}, fmt.Errorf("not a Blender build archive: %s", fileName)
+	}
+
+	return BlenderBuild{
+		Version:         m[1],
+		ReleaseCycle:    m[2],
+		Branch:          m[3],
+		Hash:            m[4],
+		OperatingSystem: m[5],
+		Architecture:    m[6],
+		FileExtension:   m[7],
+		FileName:        fileName,
+		DownloadURL:     rawURL,
+		Status:          StateOnline,
+	}, nil
+}
+
+// ParseBuildDirName reads what the name of an extracted build directory tells
+// about the build: everything for directories of builder archives, named like
+// the archive without its extension, only the version for stable releases.
+func ParseBuildDirName(name string) (BlenderBuild, error) {
+	for _, ext := range []string{"tar.xz", "zip", "dmg"} {
+		if m := buildFileNamePattern.FindStringSubmatch(name + "." + ext); m != nil {
+			return BlenderBuild{
+				Version:         m[1],
+				ReleaseCycle:    m[2],
+				Branch:          m[3],
+				Hash:            m[4],
+				OperatingSystem: m[5],
+				Architecture:    m[6],
+				FileName:        name,
+			}, nil
+		}
+	}
+	if m := releaseDirPattern.FindStringSubmatch(name); m != nil {
+		return BlenderBuild{Version: m[1], ReleaseCycle: "stable", FileName: name}, nil
+	}
+	return BlenderBuild{}, fmt.Errorf("not a Blender build directory: %s", name)
+}
+
+// FindHash returns the first commit hash found in text, e.g. in a pasted chat
+// message or commit link, or "" if there is none.
+func FindHash(text string) string {
+	return hashPattern.FindString(strings.ToLower(text))
+}
+
+// FindURL returns the first http(s) URL found in text, or "" if there is none.
+func FindURL(text string) string {
+	return urlPattern.FindString(text)
+}
+
+// ShareDescriptor describes a build in one line, e.g.
+// "Blender 4.2.0 alpha main 3c6b3c2a0f29 https://...". Pasting it into another
+// launcher installs exactly the same build.
+func ShareDescriptor(build BlenderBuild) string {
+	parts := []string{"Blender", build.Version}
+	for _, part := range []string{build.ReleaseCycle, build.Branch, build.Hash, build.DownloadURL} {
+		if part != "" {
+			parts = append(parts, part)
+		}
+	}
+	return strings.Join(parts, " ")
+}
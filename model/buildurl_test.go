@@ -0,0 +1,126 @@
+package model
+
+import "testing"
+
+func TestParseBuildURL(t *testing.T) {
+	tests := []struct {
+		name    string
+		url     string
+		want    BlenderBuild
+		wantErr bool
+	}{
+		{
+			name: "daily linux build",
+			url:  "https://cdn.builder.blender.org/download/daily/blender-4.2.0-alpha+main.3c6b3c2a0f29-linux.x86_64-release.tar.xz",
+			want: BlenderBuild{Version: "4.2.0", ReleaseCycle: "alpha", Branch: "main", Hash: "3c6b3c2a0f29", OperatingSystem: "linux", Architecture: "x86_64", FileExtension: "tar.xz"},
+		},
+		{
+			name: "patch windows build",
+			url:  "https://builder.blender.org/download/patch/blender-4.3.0-alpha+PR12345.0123456789ab-windows.amd64-release.zip",
+			want: BlenderBuild{Version: "4.3.0", ReleaseCycle: "alpha", Branch: "PR12345", Hash: "0123456789ab", OperatingSystem: "windows", Architecture: "amd64", FileExtension: "zip"},
+		},
+		{name: "not an archive", url: "https://builder.blender.org/download/daily/", wantErr: true},
+		{name: "not a URL", url: "blender-4.2.0-alpha+main.3c6b3c2a0f29-linux.x86_64-release.tar.xz", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseBuildURL(tt.url)
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("Expected an error for %s", tt.url)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParseBuildURL returned an error: %v", err)
+			}
+			if got.Version != tt.want.Version || got.ReleaseCycle != tt.want.ReleaseCycle ||
+				got.Branch != tt.want.Branch || got.Hash != tt.want.Hash ||
+				got.OperatingSystem != tt.want.OperatingSystem || got.Architecture != tt.want.Architecture ||
+				got.FileExtension != tt.want.FileExtension {
+				t.Errorf("ParseBuildURL(%s) = %+v, want %+v", tt.url, got, tt.want)
+			}
+			if got.DownloadURL != tt.url {
+				t.Errorf("Expected DownloadURL %s, got %s", tt.url, got.DownloadURL)
+			}
+		})
+	}
+}
+
+func TestParseBuildDirName(t *testing.T) {
+	tests := []struct {
+		name    string
+		dir     string
+		want    BlenderBuild
+		wantErr bool
+	}{
+		{
+			name: "daily build",
+			dir:  "blender-4.2.0-alpha+main.3c6b3c2a0f29-linux.x86_64-release",
+			want: BlenderBuild{Version: "4.2.0", ReleaseCycle: "alpha", Branch: "main", Hash: "3c6b3c2a0f29", OperatingSystem: "linux", Architecture: "x86_64"},
+		},
+		{
+			name: "stable release",
+			dir:  "blender-4.2.1-linux-x64",
+			want: BlenderBuild{Version: "4.2.1", ReleaseCycle: "stable"},
+		},
+		{name: "anything else", dir: "my-blender", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseBuildDirName(tt.dir)
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("Expected an error for %s", tt.dir)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParseBuildDirName returned an error: %v", err)
+			}
+			if got.Version != tt.want.Version || got.ReleaseCycle != tt.want.ReleaseCycle ||
+				got.Branch != tt.want.Branch || got.Hash != tt.want.Hash ||
+				got.OperatingSystem != tt.want.OperatingSystem || got.Architecture != tt.want.Architecture ||
+				got.FileName != tt.dir {
+				t.Errorf("ParseBuildDirName(%s) = %+v, want %+v", tt.dir, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFindHash(t *testing.T) {
+	tests := []struct {
+		text string
+		want string
+	}{
+		{"try 3c6b3c2a0f29 please", "3c6b3c2a0f29"},
+		{"https://projects.blender.org/blender/blender/commit/3C6B3C2A0F29", "3c6b3c2a0f29"},
+		{"no hash here", ""},
+	}
+
+	for _, tt := range tests {
+		if got := FindHash(tt.text); got != tt.want {
+			t.Errorf("FindHash(%q) = %q, want %q", tt.text, got, tt.want)
+		}
+	}
+}
+
+func TestShareDescriptorRoundTrip(t *testing.T) {
+	build := BlenderBuild{
+		Version:      "4.2.0",
+		ReleaseCycle: "alpha",
+		Branch:       "main",
+		Hash:         "3c6b3c2a0f29",
+		DownloadURL:  "https://cdn.builder.blender.org/download/daily/blender-4.2.0-alpha+main.3c6b3c2a0f29-linux.x86_64-release.tar.xz",
+	}
+
+	descriptor := ShareDescriptor(build)
+	if got := FindURL(descriptor); got != build.DownloadURL {
+		t.Errorf("Expected URL %s in descriptor %q, got %q", build.DownloadURL, descriptor, got)
+	}
+	if got := FindHash(descriptor); got != build.Hash {
+		t.Errorf("Expected hash %s in descriptor %q, got %q", build.Hash, descriptor, got)
+	}
+}
@@ -0,0 +1,107 @@
+package tui
+
+import (
+	"TUI-Blender-Launcher/config"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	lp "github.com/charmbracelet/lipgloss"
+)
+
+// changelogSeenFilename is the file in the state directory recording how
+// many changelog entries were shown last time.
+const changelogSeenFilename = "changelog_seen"
+
+// launcherChangelog lists the user-visible changes of the launcher, newest
+// first. Add to the top when releasing; entries beyond the count a user has
+// seen are shown to them once.
+var launcherChangelog = []string{
+	"Downloads resume after interruptions and can be paused with space",
+	"Parallel download segments, a download queue and a rate limit",
+	"Failed downloads are retried and report why they failed",
+	"Installed builds are verified against a checksum manifest (v)",
+	"Builds can be installed from a URL or hash in the clipboard (p) and shared (y)",
+	"Launch queue for headless jobs across builds (b)",
+	"Custom columns, kept archives, delta updates and BitTorrent downloads",
+	"Scripting commands: list, download and launch",
+	"Browse, download, launch and clean up Blender builds",
+}
+
+// changelogMsg asks to show a changelog with entries the user hasn't seen yet.
+type changelogMsg struct{}
+
+// changelogSeenPath returns the full path to the file recording the entries seen.
+func changelogSeenPath() (string, error) {
+	stateDir, err := config.GetStateDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(stateDir, changelogSeenFilename), nil
+}
+
+// checkChangelog returns a command producing changelogMsg if the changelog
+// has entries that weren't shown yet.
+func checkChangelog() tea.Cmd {
+	return func() tea.Msg {
+		path, err := changelogSeenPath()
+		if err != nil {
+			return nil
+		}
+		data, err := os.ReadFile(path)
+		if err != nil && !os.IsNotExist(err) {
+			return nil
+		}
+		if seen, _ := strconv.Atoi(strings.TrimSpace(string(data))); seen >= len(launcherChangelog) {
+			return nil
+		}
+		return changelogMsg{}
+	}
+}
+
+// markChangelogSeen returns a command recording the changelog entries as shown.
+func markChangelogSeen() tea.Cmd {
+	return func() tea.Msg {
+		path, err := changelogSeenPath()
+		if err != nil {
+			return errMsg{err}
+		}
+		if err := os.MkdirAll(filepath.Dir(path), 0750); err != nil {
+			return errMsg{fmt.Errorf("could not create state directory: %w", err)}
+		}
+		if err := os.WriteFile(path, []byte(strconv.Itoa(len(launcherChangelog))+"\n"), 0644); err != nil {
+			return errMsg{fmt.Errorf("failed to write %s: %w", path, err)}
+		}
+		return nil
+	}
+}
+
+// handleShowChangelog opens the changelog view and records it as seen. It
+// only replaces the builds list, never a form the user is filling in.
+func (m *Model) handleShowChangelog() (tea.Model, tea.Cmd) {
+	if m.currentView != viewList {
+		return m, nil
+	}
+	m.currentView = viewChangelog
+	m.scrollOffset = 0
+	return m, markChangelogSeen()
+}
+
+// renderChangelog renders the launcher changelog to fit the given width and height.
+func (m *Model) renderChangelog(width, height int) string {
+	titleStyle := lp.NewStyle().Bold(true).Foreground(lp.Color(highlightColor))
+	bodyStyle := lp.NewStyle().Width(max(width-2*formPadding, 1))
+
+	var b strings.Builder
+	b.WriteString(titleStyle.Render("What's new in the launcher"))
+	b.WriteString("\n")
+	b.WriteString("\n")
+	for _, change := range launcherChangelog {
+		b.WriteString(bodyStyle.Render("• " + change))
+		b.WriteString("\n")
+	}
+	return m.renderScrollable(strings.TrimSuffix(b.String(), "\n"), height)
+}
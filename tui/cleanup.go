@@ -0,0 +1,357 @@
+package tui
+
+import (
+	"TUI-Blender-Launcher/download"
+	"TUI-Blender-Launcher/local"
+	"TUI-Blender-Launcher/model"
+	"fmt"
+	"slices"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	lp "github.com/charmbracelet/lipgloss"
+)
+
+// cleanupState is the cleanup view: what could be deleted and what the user picked.
+type cleanupState struct {
+	items    []local.ReclaimableItem
+	selected []bool
+	cursor   int
+	loading  bool
+	removing bool
+}
+
+// diskSpaceMsg reports the free space in the download directory.
+type diskSpaceMsg struct {
+	free int64
+}
+
+// cleanupItemsMsg carries the reclaimable items found for the cleanup view.
+type cleanupItemsMsg struct {
+	items []local.ReclaimableItem
+	err   error
+}
+
+// cleanupDoneMsg reports the outcome of deleting the selected items.
+type cleanupDoneMsg struct {
+	freed int64
+	err   error
+}
+
+// retentionDoneMsg reports removing the builds beyond keep_per_series.
+type retentionDoneMsg struct {
+	removed int
+	freed   int64
+	err     error
+}
+
+// staleFilesMsg reports leftovers of earlier sessions in .downloading.
+type staleFilesMsg struct {
+	paths []string
+	size  int64
+}
+
+// staleFilesCleanedMsg reports the outcome of removing the leftovers.
+type staleFilesCleanedMsg struct {
+	err error
+}
+
+// checkDiskSpace returns a command measuring the free space in the download directory.
+func checkDiskSpace(downloadDir string) tea.Cmd {
+	return func() tea.Msg {
+		free, err := download.FreeSpace(downloadDir)
+		if err != nil {
+			return nil // Not knowing is no reason to warn
+		}
+		return diskSpaceMsg{free: free}
+	}
+}
+
+// lowDiskThreshold returns the configured free space warning level in bytes, 0 if disabled.
+func (m *Model) lowDiskThreshold() int64 {
+	return int64(m.config.LowDiskThreshold * 1024 * 1024 * 1024)
+}
+
+// handleDiskSpaceMsg shows or clears the low disk banner.
+func (m *Model) handleDiskSpaceMsg(msg diskSpaceMsg) (tea.Model, tea.Cmd) {
+	if threshold := m.lowDiskThreshold(); threshold > 0 && msg.free < threshold {
+		m.lowDiskFree = msg.free
+	} else {
+		m.lowDiskFree = 0
+	}
+	return m, nil
+}
+
+// renderBanner renders the notice shown above the builds list, low disk
+// space before a new stable release before expired temporary builds, or ""
+// if there is none.
+func (m *Model) renderBanner() string {
+	if banner := m.renderLowDiskBanner(); banner != "" {
+		return banner
+	}
+	if banner := m.renderStableBanner(); banner != "" {
+		return banner
+	}
+	return m.renderExpiredBanner()
+}
+
+// renderLowDiskBanner renders the warning shown above the builds list while
+// free space is below the threshold, or "" otherwise.
+func (m *Model) renderLowDiskBanner() string {
+	if m.lowDiskFree <= 0 {
+		return ""
+	}
+	text := fmt.Sprintf(" Low disk space: %s free · press c to clean up", model.FormatByteSize(m.lowDiskFree))
+	return lp.NewStyle().
+		Bold(true).
+		Foreground(lp.Color(textColor)).
+		Background(lp.Color(redColor)).
+		Width(m.terminalWidth).
+		MaxWidth(m.terminalWidth).
+		MaxHeight(1).
+		Render(text)
+}
+
+// handleShowCleanup opens the cleanup view and looks for reclaimable items in
+// the background. Partial downloads are left out while downloads are active.
+func (m *Model) handleShowCleanup() (tea.Model, tea.Cmd) {
+	m.currentView = viewCleanup
+	m.cleanup = cleanupState{loading: true}
+
+	includePartial := !m.hasActiveDownloads()
+	roots := m.config.Roots()
+	return m, func() tea.Msg {
+		items, err := local.FindReclaimable(roots, includePartial)
+		return cleanupItemsMsg{items: items, err: err}
+	}
+}
+
+// hasActiveDownloads reports whether any download is running or waiting to,
+// its files in .downloading must not be touched then.
+func (m *Model) hasActiveDownloads() bool {
+	for _, state := range m.commands.downloads.GetAllStates() {
+		switch state.BuildState {
+		case model.StateDownloading, model.StateExtracting, model.StateQueued, model.StatePaused:
+			return true
+		}
+	}
+	return m.commands.downloads.IsPrefetching()
+}
+
+// checkStaleFiles returns a command looking for leftovers of crashed sessions
+// in .downloading that can't be resumed.
+func checkStaleFiles(downloadDir string) tea.Cmd {
+	return func() tea.Msg {
+		paths, size, err := download.FindStaleFiles(downloadDir)
+		if err != nil || len(paths) == 0 {
+			return nil
+		}
+		return staleFilesMsg{paths: paths, size: size}
+	}
+}
+
+// handleCleanTempFiles removes the leftovers found at startup, unless
+// downloads started since, which may be using them.
+func (m *Model) handleCleanTempFiles() (tea.Model, tea.Cmd) {
+	if len(m.staleFiles) == 0 || m.hasActiveDownloads() {
+		return m, nil
+	}
+	paths := m.staleFiles
+	m.staleFiles, m.staleSize = nil, 0
+	return m, func() tea.Msg {
+		return staleFilesCleanedMsg{err: download.RemoveStaleFiles(paths)}
+	}
+}
+
+// handleCleanupItemsMsg fills the cleanup view, suggesting everything that
+// isn't an installed build or a resumable download.
+func (m *Model) handleCleanupItemsMsg(msg cleanupItemsMsg) (tea.Model, tea.Cmd) {
+	if msg.err != nil {
+		m.err = msg.err
+		m.currentView = viewList
+		return m, nil
+	}
+	m.cleanup = cleanupState{items: msg.items, selected: make([]bool, len(msg.items))}
+	for i, item := range msg.items {
+		m.cleanup.selected[i] = item.Disposable()
+	}
+	return m, nil
+}
+
+// selectedCleanup returns the items picked for deletion and their total size.
+func (m *Model) selectedCleanup() ([]local.ReclaimableItem, int64) {
+	var items []local.ReclaimableItem
+	var size int64
+	for i, item := range m.cleanup.items {
+		if m.cleanup.selected[i] {
+			items = append(items, item)
+			size += item.Size
+		}
+	}
+	return items, size
+}
+
+// handleCleanupDoneMsg returns to the builds list after deleting, refreshing
+// the builds and the free space.
+func (m *Model) handleCleanupDoneMsg(msg cleanupDoneMsg) (tea.Model, tea.Cmd) {
+	m.cleanup = cleanupState{}
+	if m.currentView == viewCleanup {
+		m.currentView = viewList
+	}
+	if msg.err != nil {
+		m.err = msg.err
+	} else {
+		m.notice = "freed " + model.FormatByteSize(msg.freed)
+	}
+	return m, tea.Batch(m.commands.ScanLocalBuilds(), checkDiskSpace(m.config.DownloadDir))
+}
+
+// enforceRetention returns a command removing the daily builds beyond the
+// newest keep_per_series of each series, or nil without a limit. Builds
+// running in the background are kept.
+func (m *Model) enforceRetention() tea.Cmd {
+	keep := m.config.KeepPerSeries
+	if keep <= 0 {
+		return nil
+	}
+	running := make(map[string]bool, len(m.running))
+	for _, blender := range m.running {
+		running[blender.version] = true
+	}
+	roots := m.config.Roots()
+	return func() tea.Msg {
+		items, err := local.RetentionExcess(roots, keep)
+		if err != nil {
+			return retentionDoneMsg{err: err}
+		}
+		items = slices.DeleteFunc(items, func(item local.ReclaimableItem) bool {
+			return item.Kind == local.ReclaimBuild && running[item.Name]
+		})
+		freed, err := local.RemoveReclaimable(items)
+		return retentionDoneMsg{removed: len(items), freed: freed, err: err}
+	}
+}
+
+// handleRetentionDone reports what was removed and lists the builds left.
+func (m *Model) handleRetentionDone(msg retentionDoneMsg) (tea.Model, tea.Cmd) {
+	if msg.err != nil {
+		m.err = fmt.Errorf("failed to remove old builds: %w", msg.err)
+	} else if msg.removed > 0 {
+		m.notice = fmt.Sprintf("removed %d old builds beyond keep_per_series, freed %s", msg.removed, model.FormatByteSize(msg.freed))
+	} else {
+		return m, nil
+	}
+	return m, tea.Batch(m.commands.ScanLocalBuilds(), checkDiskSpace(m.config.DownloadDir))
+}
+
+// updateCleanupViewController handles keys in the cleanup view
+func (m *Model) updateCleanupViewController(msg tea.Msg) (tea.Model, tea.Cmd) {
+	keyMsg, ok := msg.(tea.KeyMsg)
+	if !ok {
+		return m.updateListViewController(msg)
+	}
+
+	for _, cmd := range GetCommandsForView(viewCleanup) {
+		if !MatchKey(keyMsg, cmd.Type) {
+			continue
+		}
+		switch cmd.Type {
+		case CmdQuit:
+			return m, tea.Quit
+		case CmdBack:
+			if !m.cleanup.removing {
+				m.currentView = viewList
+				m.cleanup = cleanupState{}
+			}
+		case CmdMoveUp:
+			m.cleanup.cursor = max(m.cleanup.cursor-1, 0)
+		case CmdMoveDown:
+			m.cleanup.cursor = max(min(m.cleanup.cursor+1, len(m.cleanup.items)-1), 0)
+		case CmdToggleCleanupItem:
+			if m.cleanup.cursor < len(m.cleanup.selected) {
+				m.cleanup.selected[m.cleanup.cursor] = !m.cleanup.selected[m.cleanup.cursor]
+			}
+		case CmdRunCleanup:
+			items, _ := m.selectedCleanup()
+			if len(items) == 0 || m.cleanup.removing {
+				return m, nil
+			}
+			m.cleanup.removing = true
+			return m, func() tea.Msg {
+				freed, err := local.RemoveReclaimable(items)
+				return cleanupDoneMsg{freed: freed, err: err}
+			}
+		}
+		return m, nil
+	}
+	return m, nil
+}
+
+// renderCleanup renders the reclaimable items with their sizes, largest
+// first, to fit the given width and height.
+func (m *Model) renderCleanup(width, height int) string {
+	titleStyle := lp.NewStyle().Bold(true).Foreground(lp.Color(highlightColor))
+	innerWidth := max(width-2*formPadding, 1)
+
+	var b strings.Builder
+	b.WriteString(titleStyle.Render("Clean up"))
+	b.WriteString("\n\n")
+
+	switch {
+	case m.cleanup.loading:
+		b.WriteString("Measuring the download directory...")
+		return lp.NewStyle().Padding(0, formPadding).Render(b.String())
+	case m.cleanup.removing:
+		b.WriteString("Deleting...")
+		return lp.NewStyle().Padding(0, formPadding).Render(b.String())
+	case len(m.cleanup.items) == 0:
+		b.WriteString("Nothing to clean up.")
+		return lp.NewStyle().Padding(0, formPadding).Render(b.String())
+	}
+
+	_, total := m.selectedCleanup()
+	b.WriteString(fmt.Sprintf("%s selected", model.FormatByteSize(total)))
+	headerLines := 3
+
+	sizeWidth, kindWidth := 0, 0
+	for _, item := range m.cleanup.items {
+		sizeWidth = max(sizeWidth, len(model.FormatByteSize(item.Size)))
+		kindWidth = max(kindWidth, len(item.Kind))
+	}
+
+	lines := make([]string, len(m.cleanup.items))
+	for i, item := range m.cleanup.items {
+		check := "[ ]"
+		if m.cleanup.selected[i] {
+			check = "[x]"
+		}
+		line := fmt.Sprintf("%s %*s  %-*s  %s", check, sizeWidth, model.FormatByteSize(item.Size), kindWidth, item.Kind, item.Name)
+		style := lp.NewStyle().MaxWidth(innerWidth)
+		if i == m.cleanup.cursor {
+			style = m.Style.SelectedRow.MaxWidth(innerWidth)
+		}
+		lines[i] = style.Render(line)
+	}
+	list := clipLines(strings.Join(lines, "\n"), height-headerLines, m.cleanup.cursor)
+
+	b.WriteString("\n\n")
+	b.WriteString(list)
+	return lp.NewStyle().Padding(0, formPadding).Render(b.String())
+}
+
+// renderCleanupFooter renders the footer for the cleanup view
+func (m *Model) renderCleanupFooter() string {
+	keyStyle := m.Style.Key
+	separator := m.Style.Separator.Render(" · ")
+	newlineStyle := m.Style.Newline.Render("\n")
+
+	commands := []string{
+		fmt.Sprintf("%s Select", keyStyle.Render("space")),
+		fmt.Sprintf("%s Delete selected", keyStyle.Render("enter")),
+		fmt.Sprintf("%s Back", keyStyle.Render("esc")),
+		fmt.Sprintf("%s Quit", keyStyle.Render("q")),
+	}
+
+	footerContent := m.renderStatus() + newlineStyle + strings.Join(commands, separator)
+	return m.Style.Footer.Width(m.terminalWidth).Render(footerContent)
+}
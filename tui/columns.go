@@ -0,0 +1,119 @@
+package tui
+
+import (
+	"TUI-Blender-Launcher/config"
+	"TUI-Blender-Launcher/model"
+	"fmt"
+	"strconv"
+	"strings"
+	"text/template"
+	"time"
+)
+
+// builtinColumnCount is the number of columns every build table shows
+// (Version, Status, Branch, Type, Hash, Size, Build Date).
+const builtinColumnCount = 7
+
+// customColumn is a user-defined column whose cells are rendered from build metadata
+type customColumn struct {
+	Name    string
+	tmpl    *template.Template
+	sortKey func(model.BlenderBuild) string // Sorts by this instead of the cell content if set
+}
+
+// columnFuncs are the helpers available in column templates
+var columnFuncs = template.FuncMap{
+	// short truncates a commit hash to the length used in build IDs
+	"short": func(s string) string {
+		if len(s) > 8 {
+			return s[:8]
+		}
+		return s
+	},
+	// age returns how many whole days ago a build was made
+	"age": func(t model.Timestamp) int {
+		return int(time.Since(t.Time()).Hours() / 24)
+	},
+	"date": func(t model.Timestamp) string {
+		return t.Time().Format("2006-01-02")
+	},
+	// ago describes how long ago a build was last launched
+	"ago": func(t *time.Time) string {
+		return lastUsedLabel(t, time.Now())
+	},
+	"size":  model.FormatByteSize,
+	"upper": strings.ToUpper,
+	"lower": strings.ToLower,
+	"join":  strings.Join,
+}
+
+// tagsColumn shows the favorite mark and tags of builds, added to the table
+// while any listed build has them.
+var tagsColumn = customColumn{
+	Name: "Tags",
+	tmpl: template.Must(template.New("Tags").Funcs(columnFuncs).Parse(`{{if .Favorite}}★ {{end}}{{join .Tags ", "}}`)),
+}
+
+// lastUsedColumn shows when builds were last launched, added to the table
+// once any listed build has been. It sorts by the launch time.
+var lastUsedColumn = customColumn{
+	Name: "Last Used",
+	tmpl: template.Must(template.New("Last Used").Funcs(columnFuncs).Parse(`{{ago .LastLaunched}}`)),
+	sortKey: func(build model.BlenderBuild) string {
+		if build.LastLaunched == nil {
+			return "0"
+		}
+		return strconv.FormatInt(build.LastLaunched.Unix(), 10)
+	},
+}
+
+// lastUsedLabel describes how long before now t was, "Never" if it is nil.
+func lastUsedLabel(t *time.Time, now time.Time) string {
+	if t == nil {
+		return "Never"
+	}
+	days := int(now.Sub(*t).Hours() / 24)
+	switch {
+	case days < 1:
+		return "Today"
+	case days < 2:
+		return "Yesterday"
+	case days < 14:
+		return fmt.Sprintf("%d days ago", days)
+	case days < 60:
+		return fmt.Sprintf("%d weeks ago", days/7)
+	case days < 730:
+		return fmt.Sprintf("%d months ago", days/30)
+	}
+	return fmt.Sprintf("%d years ago", days/365)
+}
+
+// compileColumns parses the custom column templates from the config
+func compileColumns(defs []config.CustomColumn) ([]customColumn, error) {
+	columns := make([]customColumn, 0, len(defs))
+	for _, def := range defs {
+		tmpl, err := template.New(def.Name).Funcs(columnFuncs).Parse(def.Template)
+		if err != nil {
+			return nil, fmt.Errorf("invalid template for column %q: %w", def.Name, err)
+		}
+		columns = append(columns, customColumn{Name: def.Name, tmpl: tmpl})
+	}
+	return columns, nil
+}
+
+// Value renders the cell of a build in this column
+func (c customColumn) Value(build model.BlenderBuild) string {
+	var b strings.Builder
+	if err := c.tmpl.Execute(&b, build); err != nil {
+		return "!err"
+	}
+	return b.String()
+}
+
+// SortKey returns what a build is sorted by in this column
+func (c customColumn) SortKey(build model.BlenderBuild) string {
+	if c.sortKey != nil {
+		return c.sortKey(build)
+	}
+	return c.Value(build)
+}
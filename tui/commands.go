@@ -4,342 +4,711 @@ import (
 	"TUI-Blender-Launcher/api"
 	"TUI-Blender-Launcher/config"
 	"TUI-Blender-Launcher/download"
+	"TUI-Blender-Launcher/launch"
 	"TUI-Blender-Launcher/local"
 	"TUI-Blender-Launcher/model"
-	"context"
 	"errors"
 	"fmt"
-	"net/http"
 	"os"
 	"path/filepath"
+	"sync"
 	"time"
 
-	"github.com/cavaliergopher/grab/v3"
 	tea "github.com/charmbracelet/bubbletea"
 )
 
-// DownloadManager handles all download operations with thread-safe state access
+// DownloadManager handles all download operations with thread-safe state access.
+// Downloads run in goroutines of their own, so the UI only ever gets copies of
+// the states, taken with mu held.
 type DownloadManager struct {
-	states map[string]*model.DownloadState
-	cfg    config.Config
+	cfg     config.Config
+	limiter *download.RateLimiter // Bandwidth limit shared by all downloads
+
+	// Download states and their fields, the background prefetch (at most one
+	// at a time), the download queue, pause gates of running downloads, the
+	// downloads reinstalling a build, those of them replacing a corrupted
+	// install and the builds of all planned downloads, guarded by mu
+	mu             sync.Mutex
+	states         map[string]*model.DownloadState
+	prefetchID     string
+	prefetchCancel chan struct{}
+	queue          []model.BlenderBuild
+	active         int
+	gates          map[string]*download.PauseGate
+	reinstalls     map[string]bool
+	corrupted      map[string]bool
+	builds         map[string]model.BlenderBuild
+	pauseOnStart   map[string]bool // Downloads paused when the launcher last quit
+
+	// Where the planned downloads are saved for the next start, "" to not save them
+	queuePath string
+}
+
+// downloadID returns the unique identifier of a build used to track its download
+func downloadID(build model.BlenderBuild) string {
+	if build.Hash != "" {
+		return build.Version + "-" + build.Hash[:min(len(build.Hash), 8)]
+	}
+	return build.Version
 }
 
 // NewDownloadManager creates a new download manager
 func NewDownloadManager(cfg config.Config) *DownloadManager {
 	return &DownloadManager{
-		states: make(map[string]*model.DownloadState),
-		cfg:    cfg,
+		states:       make(map[string]*model.DownloadState),
+		cfg:          cfg,
+		limiter:      &download.RateLimiter{},
+		gates:        make(map[string]*download.PauseGate),
+		reinstalls:   make(map[string]bool),
+		corrupted:    make(map[string]bool),
+		builds:       make(map[string]model.BlenderBuild),
+		pauseOnStart: make(map[string]bool),
 	}
 }
 
-// GetState safely retrieves state for a build
+// GetState returns a copy of the state of a build's download, nil if there is none
 func (dm *DownloadManager) GetState(buildID string) *model.DownloadState {
-	return dm.states[buildID]
+	dm.mu.Lock()
+	defer dm.mu.Unlock()
+	state := dm.states[buildID]
+	if state == nil {
+		return nil
+	}
+	snapshot := *state
+	return &snapshot
 }
 
-// GetAllStates returns a copy of all download states
+// GetAllStates returns copies of all download states, which stay as they are
+// while the downloads go on
 func (dm *DownloadManager) GetAllStates() map[string]*model.DownloadState {
-	result := make(map[string]*model.DownloadState)
+	dm.mu.Lock()
+	defer dm.mu.Unlock()
+	result := make(map[string]*model.DownloadState, len(dm.states))
 	for k, v := range dm.states {
-		result[k] = v
+		snapshot := *v
+		result[k] = &snapshot
 	}
 	return result
 }
 
-// StartDownload begins a new download for a build
+// StartDownload queues a download for a build. It starts right away when fewer than
+// the configured maximum of downloads are running, otherwise it waits for a free slot.
 func (dm *DownloadManager) StartDownload(build model.BlenderBuild) tea.Msg {
-	// Create a unique build ID
-	buildID := build.Version
-	if build.Hash != "" {
-		buildID = build.Version + "-" + build.Hash[:8]
+	buildID := downloadID(build)
+	dm.mu.Lock()
+
+	// A real download takes over from a background prefetch of the same build
+	if dm.prefetchID == buildID {
+		dm.cancelPrefetch()
 	}
 
-	// Clean up previous state if it was Failed or Cancelled before starting anew
+	// Clean up previous state if it was Failed or Cancelled before starting anew.
+	// A scheduled download starts now instead.
 	if state, exists := dm.states[buildID]; exists {
-		if state.BuildState == model.StateFailed || state.BuildState == model.StateCancelled {
+		if state.BuildState == model.StateScheduled {
+			close(state.CancelCh)
+			delete(dm.states, buildID)
+		} else if state.BuildState == model.StateFailed || state.BuildState == model.StateCancelled ||
+			state.BuildState == model.StateInterrupted {
 			// Remove the old failed/cancelled state to allow restart
 			delete(dm.states, buildID)
-		} else if state.BuildState == model.StateDownloading || state.BuildState == model.StateExtracting {
-			// If already downloading/extracting this exact build, don't start another one
+		} else if state.BuildState == model.StateDownloading ||
+			state.BuildState == model.StateExtracting ||
+			state.BuildState == model.StateQueued ||
+			state.BuildState == model.StatePaused {
+			// If already queued or running this exact build, don't start another one
+			dm.mu.Unlock()
 			return nil
 		}
 	}
 
-	// Setup download state
 	now := time.Now()
-	cancelCh := make(chan struct{})
 	dm.states[buildID] = &model.DownloadState{
 		BuildID:     buildID,
-		BuildState:  model.StateDownloading,
+		BuildState:  model.StateQueued,
 		StartTime:   now,
 		LastUpdated: now,
-		Progress:    0.0,
-		CancelCh:    cancelCh,
+		CancelCh:    make(chan struct{}),
+		RetryNow:    make(chan struct{}, 1),
 	}
+	dm.builds[buildID] = build
+	if dm.active >= dm.maxConcurrent() {
+		dm.queue = append(dm.queue, build)
+		dm.updateQueuePositions()
+		dm.saveQueue()
+		dm.mu.Unlock()
+		return nil
+	}
+	dm.active++
+	dm.mu.Unlock()
 
-	// Create a temporary directory for downloads if it doesn't exist
-	downloadTempDir := filepath.Join(dm.cfg.DownloadDir, download.DownloadingDir)
-	if err := os.MkdirAll(downloadTempDir, 0750); err != nil {
-		// Handle error creating download directory
-		dm.states[buildID].BuildState = model.StateFailed
-		programCh <- downloadCompleteMsg{
-			buildVersion: build.Version,
-			err:          fmt.Errorf("failed to create download directory: %w", err),
+	dm.run(build)
+	return nil
+}
+
+// StartReinstall queues downloading an installed build again, ignoring any kept
+// archive. The install is replaced once the new one is complete; a corrupted
+// one is removed once the new one verifies.
+func (dm *DownloadManager) StartReinstall(build model.BlenderBuild, corrupted bool) tea.Msg {
+	buildID := downloadID(build)
+	dm.mu.Lock()
+	if state, exists := dm.states[buildID]; exists {
+		switch state.BuildState {
+		case model.StateDownloading, model.StateExtracting, model.StateQueued, model.StatePaused:
+			dm.mu.Unlock()
+			return nil
 		}
-		return nil
 	}
+	dm.reinstalls[buildID] = true
+	dm.corrupted[buildID] = corrupted
+	dm.mu.Unlock()
+	return dm.StartDownload(build)
+}
+
+// ScheduleDownload sets a build to start downloading at the given time. When
+// the time comes, a scheduledDownloadDueMsg asks the UI to start it like any
+// other download, so it still waits for a free slot. Cancelling the download
+// drops the schedule.
+func (dm *DownloadManager) ScheduleDownload(build model.BlenderBuild, at time.Time) {
+	buildID := downloadID(build)
+	dm.mu.Lock()
+	defer dm.mu.Unlock()
+	if state, exists := dm.states[buildID]; exists {
+		switch state.BuildState {
+		case model.StateDownloading, model.StateExtracting, model.StateQueued, model.StatePaused:
+			return
+		case model.StateScheduled:
+			close(state.CancelCh) // Rescheduling replaces the earlier time
+		}
+	}
+
+	state := &model.DownloadState{
+		BuildID:     buildID,
+		BuildState:  model.StateScheduled,
+		ScheduledAt: at,
+		LastUpdated: time.Now(),
+		CancelCh:    make(chan struct{}),
+	}
+	dm.states[buildID] = state
+	dm.builds[buildID] = build
+	dm.saveQueue()
 
-	// Start the download in a goroutine
 	go func() {
-		// Get the filename from the download URL
-		downloadFileName := filepath.Base(build.DownloadURL)
-		downloadPath := filepath.Join(downloadTempDir, downloadFileName)
+		timer := time.NewTimer(time.Until(at))
+		defer timer.Stop()
+		select {
+		case <-timer.C:
+		case <-state.CancelCh:
+			return
+		}
+		dm.mu.Lock()
+		if dm.states[buildID] != state {
+			dm.mu.Unlock()
+			return
+		}
+		delete(dm.states, buildID)
+		dm.mu.Unlock()
+		programCh <- scheduledDownloadDueMsg{build: build}
+	}()
+}
 
-		// Set up the grab library context for cancellation
-		ctx, cancel := context.WithCancel(context.Background())
-		defer cancel()
+// HasFreeSlot reports whether a new download would start immediately instead of queueing
+func (dm *DownloadManager) HasFreeSlot() bool {
+	dm.mu.Lock()
+	defer dm.mu.Unlock()
+	return dm.active < dm.maxConcurrent()
+}
 
-		// Create a go routine to handle cancellation via our channel
-		go func() {
-			select {
-			case <-cancelCh:
-				cancel() // Cancel grab request if our channel is closed
-			case <-ctx.Done():
-				// Context done normally
-			}
-		}()
+// RetryNow skips the backoff of a download waiting to retry, reporting whether one was waiting
+func (dm *DownloadManager) RetryNow(buildID string) bool {
+	dm.mu.Lock()
+	defer dm.mu.Unlock()
+	state := dm.states[buildID]
+	if state == nil || state.Retry == 0 || state.BuildState != model.StateDownloading {
+		return false
+	}
+	select {
+	case state.RetryNow <- struct{}{}:
+	default:
+	}
+	return true
+}
 
-		// Create the grab client with extended timeouts
-		client := grab.NewClient()
-		client.UserAgent = "TUI-Blender-Launcher"
-
-		// Set custom HTTP client with timeouts
-		httpClient := &http.Client{
-			Timeout: 5 * time.Minute,
-			Transport: &http.Transport{
-				IdleConnTimeout:     2 * time.Minute,
-				DisableCompression:  false,
-				TLSHandshakeTimeout: 1 * time.Minute,
-			},
-		}
-		client.HTTPClient = httpClient
+// TogglePause pauses a running download or resumes a paused one, keeping the
+// partial archive and connection. It reports whether the download was toggled.
+func (dm *DownloadManager) TogglePause(buildID string) bool {
+	dm.mu.Lock()
+	defer dm.mu.Unlock()
+	state := dm.states[buildID]
+	gate := dm.gates[buildID]
+	if state == nil || gate == nil {
+		return false
+	}
 
-		// Create the request
-		req, err := grab.NewRequest(downloadPath, build.DownloadURL)
-		if err != nil {
-			dm.states[buildID].BuildState = model.StateFailed
-			programCh <- downloadCompleteMsg{
-				buildVersion: build.Version,
-				err:          fmt.Errorf("failed to create download request: %w", err),
-			}
-			return
+	switch {
+	case state.BuildState == model.StatePaused:
+		gate.Resume()
+		state.BuildState = model.StateDownloading
+	case state.BuildState == model.StateDownloading && state.Retry == 0:
+		gate.Pause()
+		state.BuildState = model.StatePaused
+		state.Speed = 0
+	default:
+		return false
+	}
+	dm.saveQueue()
+	return true
+}
+
+// waitForRetry sleeps through the backoff before a retry. It returns false
+// if the download was cancelled meanwhile.
+func (dm *DownloadManager) waitForRetry(state *model.DownloadState, retry int) bool {
+	select {
+	case <-time.After(download.RetryDelay(retry)):
+		return true
+	case <-state.RetryNow:
+		return true
+	case <-state.CancelCh:
+		return false
+	}
+}
+
+// maxConcurrent returns the configured download concurrency, at least 1
+func (dm *DownloadManager) maxConcurrent() int {
+	if dm.cfg.MaxConcurrentDownloads < 1 {
+		return 1
+	}
+	return dm.cfg.MaxConcurrentDownloads
+}
+
+// updateQueuePositions refreshes the 1-based queue position shown for waiting builds.
+// Must be called with mu held.
+func (dm *DownloadManager) updateQueuePositions() {
+	for i, build := range dm.queue {
+		if state := dm.states[downloadID(build)]; state != nil {
+			state.QueuePosition = i + 1
 		}
-		req = req.WithContext(ctx)
+	}
+}
+
+// releaseSlot frees the slot of a finished download and starts queued ones in its place
+func (dm *DownloadManager) releaseSlot() {
+	dm.mu.Lock()
+	dm.active--
+	next := dm.takeQueued()
+	dm.mu.Unlock()
+
+	for _, build := range next {
+		dm.run(build)
+	}
+}
+
+// SetConfig updates the config of the downloads started from now on. Running
+// ones carry on as they are, and queued ones start if the limit was raised.
+func (dm *DownloadManager) SetConfig(cfg config.Config) {
+	dm.mu.Lock()
+	dm.cfg = cfg
+	next := dm.takeQueued()
+	dm.mu.Unlock()
 
-		// Start download
-		resp := client.Do(req)
+	for _, build := range next {
+		dm.run(build)
+	}
+}
+
+// takeQueued takes the queued builds that fit into the free slots off the
+// queue, giving each a slot. Must be called with mu held.
+func (dm *DownloadManager) takeQueued() []model.BlenderBuild {
+	var next []model.BlenderBuild
+	for len(dm.queue) > 0 && dm.active < dm.maxConcurrent() {
+		next = append(next, dm.queue[0])
+		dm.queue = dm.queue[1:]
+		dm.active++
+	}
+	dm.updateQueuePositions()
+	return next
+}
 
-		// Use a ticker to update the download state
+// run performs the download and extraction of a build that holds a slot
+func (dm *DownloadManager) run(build model.BlenderBuild) {
+	buildID := downloadID(build)
+	dm.mu.Lock()
+	state := dm.states[buildID]
+	if state == nil {
+		dm.mu.Unlock()
+		dm.releaseSlot()
+		return
+	}
+	cancelCh := state.CancelCh
+	cfg := dm.cfg // Settings saved meanwhile don't change a running download
+	gate := download.NewPauseGate()
+	dm.gates[buildID] = gate
+	reinstall := dm.reinstalls[buildID]
+
+	install := download.DownloadAndExtractBuild
+	if reinstall && dm.corrupted[buildID] {
+		install = download.ReplaceCorruptedBuild
+	} else if reinstall {
+		install = download.ReinstallBuild
+	}
+
+	now := time.Now()
+	state.BuildState = model.StateDownloading
+	state.QueuePosition = 0
+	state.RateLimit = cfg.DownloadRateLimit * 1024 * 1024
+	state.StartTime = now
+	state.LastUpdated = now
+	state.Progress = 0.0
+	// A download paused before quitting stays paused until resumed
+	if dm.pauseOnStart[buildID] {
+		delete(dm.pauseOnStart, buildID)
+		gate.Pause()
+		state.BuildState = model.StatePaused
+	}
+	dm.saveQueue()
+	dm.mu.Unlock()
+
+	// Create a temporary directory for downloads if it doesn't exist
+	downloadTempDir := filepath.Join(cfg.DownloadDir, download.DownloadingDir)
+	if err := os.MkdirAll(downloadTempDir, 0750); err != nil {
+		// Handle error creating download directory
+		dm.mu.Lock()
+		state.BuildState = model.StateFailed
+		dm.mu.Unlock()
+		dm.releaseSlot()
+		programCh <- downloadCompleteMsg{
+			buildVersion: build.Version,
+			err:          fmt.Errorf("failed to create download directory: %w", err),
+		}
+		return
+	}
+
+	// Start the download in a goroutine
+	go func() {
+		// Speed is averaged over a few samples to reduce flickering
 		var lastBytes int64
 		var lastTime time.Time
 		var speedSamples []float64
 		var speed float64
-		var speedUpdateCounter int
 
-		// Use a slightly longer interval for UI updates to reduce flickering
-		ticker := time.NewTicker(100 * time.Millisecond)
-		defer ticker.Stop()
+		progressCb := func(downloaded, total int64) {
+			dm.mu.Lock()
+			defer dm.mu.Unlock()
+			state := dm.states[buildID]
+			if state == nil {
+				return
+			}
 
-	downloadLoop:
-		for {
-			select {
-			case <-ticker.C:
-				// Update download state with grab response status
-				now := time.Now()
-				state := dm.states[buildID]
-				if state == nil {
-					break downloadLoop // State was deleted, exit loop
+			now := time.Now()
+			if lastTime.IsZero() {
+				// The first report tells us where the transfer starts
+				if downloaded > 0 && total > 0 {
+					state.ResumedFrom = float64(downloaded) / float64(total)
 				}
-
-				downloaded := resp.BytesComplete()
-				total := resp.Size()
-
-				// Calculate progress percentage
-				percent := 0.0
-				if total > 0 {
-					percent = float64(downloaded) / float64(total)
+				lastBytes = downloaded
+				lastTime = now
+			} else if now.Sub(lastTime) > 2*time.Second {
+				// Data resumes after a pause, start measuring afresh
+				lastBytes = downloaded
+				lastTime = now
+				speedSamples = nil
+			} else if timeDiff := now.Sub(lastTime).Seconds(); timeDiff >= 0.2 {
+				speedSamples = append(speedSamples, float64(downloaded-lastBytes)/timeDiff)
+				if len(speedSamples) > 3 {
+					speedSamples = speedSamples[1:]
 				}
 
-				// Calculate download speed with moving average for smoothing
-				if !lastTime.IsZero() {
-					// Only update speed calculation every 2 ticks to further reduce fluctuations
-					speedUpdateCounter++
-					if speedUpdateCounter >= 2 {
-						speedUpdateCounter = 0
-
-						bytesDiff := downloaded - lastBytes
-						timeDiff := now.Sub(lastTime).Seconds()
-
-						// Calculate current sample
-						currentSpeed := float64(bytesDiff) / timeDiff
-
-						// Add to samples for moving average (keep last 3 samples)
-						speedSamples = append(speedSamples, currentSpeed)
-						if len(speedSamples) > 3 {
-							speedSamples = speedSamples[1:]
-						}
-
-						// Calculate average speed from samples
-						speed = 0
-						for _, s := range speedSamples {
-							speed += s
-						}
-						speed /= float64(len(speedSamples))
-
-						lastBytes = downloaded
-						lastTime = now
-					}
-				} else if lastTime.IsZero() {
-					lastBytes = downloaded
-					lastTime = now
+				speed = 0
+				for _, s := range speedSamples {
+					speed += s
 				}
+				speed /= float64(len(speedSamples))
 
-				// Update state
-				state.LastUpdated = now
-				state.Progress = percent
-				state.Current = downloaded
-				state.Total = total
-				state.Speed = speed
-
-			case <-resp.Done:
-				// Download completed or failed
-				if err := resp.Err(); err != nil {
-					// Handle download error
-					state := dm.states[buildID]
-					if state != nil {
-						// Check if this was a cancellation
-						if errors.Is(err, context.Canceled) {
-							state.BuildState = model.StateCancelled
-						} else {
-							state.BuildState = model.StateFailed
-							state.Progress = 0.0
-						}
-					}
+				lastBytes = downloaded
+				lastTime = now
+			}
 
-					// Clean up partial download
-					go func() {
-						time.Sleep(500 * time.Millisecond) // Brief delay to allow UI update
-						_ = os.RemoveAll(downloadPath)
-					}()
+			percent := 0.0
+			if total > 0 {
+				percent = float64(downloaded) / float64(total)
+			}
 
-					programCh <- downloadCompleteMsg{
-						buildVersion: build.Version,
-						err:          err,
-					}
-					return
-				}
+			state.LastUpdated = now
+			state.Progress = percent
+			state.Current = downloaded
+			state.Total = total
+			state.Speed = speed
+		}
 
-				// Download completed successfully, now proceed to extraction
-				state := dm.states[buildID]
-				if state != nil {
-					state.BuildState = model.StateExtracting
-					state.Progress = 0.0 // Reset progress for extraction phase
-				}
+		extractCb := func(progress float64) {
+			dm.mu.Lock()
+			defer dm.mu.Unlock()
+			state := dm.states[buildID]
+			if state == nil {
+				return
+			}
 
-				// Setup extraction progress callback
-				extractionAdapter := func(downloadedBytes, totalBytes int64) {
-					if totalBytes > 0 {
-						// Convert to estimation progress (0.0-1.0)
-						progress := float64(downloadedBytes) / float64(totalBytes)
-
-						// Update state
-						state := dm.states[buildID]
-						if state == nil {
-							return
-						}
-
-						select {
-						case <-cancelCh:
-							return
-						default:
-						}
-
-						now := time.Now()
-						state.LastUpdated = now
-						state.Progress = progress
-						state.Current = downloadedBytes
-						state.Total = totalBytes
-						state.BuildState = model.StateExtracting
-					}
-				}
+			select {
+			case <-cancelCh:
+				return
+			default:
+			}
 
-				// Start extraction
-				extractedPath, err := download.DownloadAndExtractBuild(build, dm.cfg.DownloadDir, extractionAdapter, cancelCh)
+			state.LastUpdated = time.Now()
+			state.Progress = progress
+			state.BuildState = model.StateExtracting
+		}
 
-				// Update final state based on extraction result
-				state = dm.states[buildID]
-				if state == nil {
-					return
-				}
+		// Transient failures are retried with backoff before giving up
+		var extractedPath string
+		var err error
+		for retry := 0; ; retry++ {
+			lastTime = time.Time{}
+			speedSamples = nil
 
-				if err != nil {
-					// Check if this was a cancellation
-					if errors.Is(err, download.ErrCancelled) {
-						state.BuildState = model.StateCancelled
-					} else {
-						// Any other error should mark as failed
-						state.BuildState = model.StateFailed
-						state.Progress = 0.0
-					}
+			extractedPath, err = install(build, cfg, progressCb, extractCb, cancelCh, gate, dm.limiter)
+			if !download.IsTransient(err) || retry >= cfg.DownloadRetries {
+				break
+			}
+
+			dm.mu.Lock()
+			state.Retry = retry + 1
+			state.MaxRetries = cfg.DownloadRetries
+			if !gate.Paused() {
+				state.BuildState = model.StateDownloading
+			}
+			state.Speed = 0
+			dm.mu.Unlock()
+			if !dm.waitForRetry(state, retry+1) {
+				err = download.ErrCancelled
+				break
+			}
+		}
+
+		// Nothing was downloaded for a build whose commit is installed already,
+		// it is just up to date
+		alreadyInstalled := errors.Is(err, download.ErrAlreadyInstalled)
+		if alreadyInstalled {
+			err = nil
+		}
+
+		// Update final state based on the result
+		dm.mu.Lock()
+		if state := dm.states[buildID]; state != nil {
+			if err != nil {
+				if errors.Is(err, download.ErrCancelled) {
+					state.BuildState = model.StateCancelled
 				} else {
-					state.BuildState = model.StateLocal
-					state.Progress = 1.0
+					// Any other error should mark as failed
+					state.BuildState = model.StateFailed
+					state.Err = err
 				}
+				state.Progress = 0.0
+			} else {
+				state.BuildState = model.StateLocal
+				state.Progress = 1.0
+			}
+		}
+		dm.mu.Unlock()
+		if err == nil && !alreadyInstalled && cfg.Insights {
+			_ = local.RecordDownloadInsight(build.Size)
+		}
+		if !errors.Is(err, download.ErrCancelled) && !alreadyInstalled {
+			// Nobody is there to tell about a failing receiver, so don't hold up the UI
+			go download.Notify(cfg.NotifyURL, cfg.NotifyCommand, download.NewNotification(build, extractedPath, err))
+		}
 
-				// Send completion message
-				programCh <- downloadCompleteMsg{
-					buildVersion:  build.Version,
-					extractedPath: extractedPath,
-					err:           err,
-				}
-				return
+		dm.mu.Lock()
+		delete(dm.gates, buildID)
+		delete(dm.reinstalls, buildID)
+		delete(dm.corrupted, buildID)
+		delete(dm.builds, buildID)
+		dm.saveQueue()
+		dm.mu.Unlock()
+		dm.releaseSlot()
 
-			case <-cancelCh:
-				// Download was cancelled
-				break downloadLoop
-			}
+		programCh <- downloadCompleteMsg{
+			buildVersion:  build.Version,
+			extractedPath: extractedPath,
+			err:           err,
 		}
-	}()
 
-	return nil
+		// The hook may take minutes, so it runs after the slot went to the next download
+		if err == nil && !alreadyInstalled && (len(cfg.Addons) > 0 || cfg.PostInstallHook != "") {
+			// The hook sees the build with the add-ons in place
+			hookErr := errors.Join(download.InstallAddons(cfg.Addons, extractedPath),
+				download.RunPostInstallHook(cfg.PostInstallHook, build, extractedPath))
+			programCh <- postInstallDoneMsg{buildVersion: build.Version, err: hookErr}
+		}
+	}()
 }
 
 // CancelDownload stops an in-progress download
 func (dm *DownloadManager) CancelDownload(buildID string) {
+	dm.mu.Lock()
+	defer dm.mu.Unlock()
 	state := dm.states[buildID]
 	if state == nil {
 		return
 	}
 
+	// Only running or waiting downloads can be cancelled (and CancelCh closed once)
+	if state.BuildState != model.StateDownloading &&
+		state.BuildState != model.StateExtracting &&
+		state.BuildState != model.StateQueued &&
+		state.BuildState != model.StatePaused &&
+		state.BuildState != model.StateScheduled {
+		return
+	}
+
+	// Queued builds just leave the queue
+	for i, build := range dm.queue {
+		if downloadID(build) == buildID {
+			dm.queue = append(dm.queue[:i], dm.queue[i+1:]...)
+			break
+		}
+	}
+	dm.updateQueuePositions()
+	delete(dm.reinstalls, buildID)
+	delete(dm.corrupted, buildID)
+	delete(dm.builds, buildID)
+	delete(dm.pauseOnStart, buildID)
+	dm.saveQueue()
+
 	close(state.CancelCh)
 	state.BuildState = model.StateCancelled
+	state.QueuePosition = 0
 	state.Progress = 0.0 // Reset progress
 
 	// Don't delete the state so we can track that it was cancelled
 	// Keep it so it can be displayed with "Cancelled" status
 }
 
+// RestoreInterrupted tracks a download left behind by an earlier session, with
+// the share of the archive already on disk, unless the build is being
+// downloaded right now.
+func (dm *DownloadManager) RestoreInterrupted(build model.BlenderBuild, downloaded int64) {
+	buildID := downloadID(build)
+	dm.mu.Lock()
+	defer dm.mu.Unlock()
+	if state, exists := dm.states[buildID]; exists && state.BuildState != model.StateCancelled &&
+		state.BuildState != model.StateFailed && state.BuildState != model.StateInterrupted {
+		return
+	}
+
+	progress := 0.0
+	if build.Size > 0 {
+		progress = min(float64(downloaded)/float64(build.Size), 1.0)
+	}
+	dm.states[buildID] = &model.DownloadState{
+		BuildID:     buildID,
+		BuildState:  model.StateInterrupted,
+		Progress:    progress,
+		LastUpdated: time.Now(),
+	}
+}
+
+// ForgetInterrupted stops tracking a download left behind by an earlier session.
+func (dm *DownloadManager) ForgetInterrupted(buildID string) {
+	dm.mu.Lock()
+	defer dm.mu.Unlock()
+	if state, exists := dm.states[buildID]; exists && state.BuildState == model.StateInterrupted {
+		delete(dm.states, buildID)
+	}
+}
+
+// StartPrefetch downloads the archive of a build in the background without installing it.
+// Only one prefetch runs at a time; completion is reported with a prefetchCompleteMsg.
+func (dm *DownloadManager) StartPrefetch(build model.BlenderBuild) {
+	dm.mu.Lock()
+	defer dm.mu.Unlock()
+	if dm.prefetchID != "" {
+		return
+	}
+
+	buildID := downloadID(build)
+	cancelCh := make(chan struct{})
+	dm.prefetchID = buildID
+	dm.prefetchCancel = cancelCh
+
+	cfg := dm.cfg
+	go func() {
+		err := download.PrefetchArchive(build, cfg, nil, cancelCh, dm.limiter)
+		dm.mu.Lock()
+		if dm.prefetchID == buildID {
+			dm.prefetchID = ""
+			dm.prefetchCancel = nil
+		}
+		dm.mu.Unlock()
+		programCh <- prefetchCompleteMsg{build: build, err: err}
+	}()
+}
+
+// CancelPrefetch stops the running background prefetch, keeping its partial archive.
+func (dm *DownloadManager) CancelPrefetch() {
+	dm.mu.Lock()
+	defer dm.mu.Unlock()
+	dm.cancelPrefetch()
+}
+
+// cancelPrefetch is CancelPrefetch with mu held.
+func (dm *DownloadManager) cancelPrefetch() {
+	if dm.prefetchCancel == nil {
+		return
+	}
+	close(dm.prefetchCancel)
+	dm.prefetchID = ""
+	dm.prefetchCancel = nil
+}
+
+// IsPrefetching reports whether a background prefetch is running.
+func (dm *DownloadManager) IsPrefetching() bool {
+	dm.mu.Lock()
+	defer dm.mu.Unlock()
+	return dm.prefetchID != ""
+}
+
 // Commands generates tea commands for the TUI
 type Commands struct {
 	cfg       config.Config
 	downloads *DownloadManager
+	transfers *TransferManager
+	jobs      *launch.JobQueue
+
+	// Where job logs and the output of Blender launched in the background go
+	logDir string
+
+	// How long the last successful API fetch took, used to judge the network
+	lastFetchDuration time.Duration
+
+	// Where the last scan of the install roots is saved for listing them right
+	// away on the next start, "" to not save it
+	scanCachePath string
 }
 
 // NewCommands creates a new Commands instance
 func NewCommands(cfg config.Config) *Commands {
+	logDir := launch.LogDir()
+
+	// Planned downloads are saved next to them, unless there is no state directory
+	downloads := NewDownloadManager(cfg)
+	if stateDir, err := config.GetStateDir(); err == nil {
+		downloads.queuePath = filepath.Join(stateDir, queueFilename)
+	}
+
+	var scanCachePath string
+	if cacheDir, err := config.GetCacheDir(); err == nil {
+		scanCachePath = filepath.Join(cacheDir, local.ScanCacheFilename)
+	}
+
 	return &Commands{
 		cfg:       cfg,
-		downloads: NewDownloadManager(cfg),
+		downloads: downloads,
+		transfers: NewTransferManager(),
+		jobs:      launch.NewJobQueue(logDir),
+
+		logDir:        logDir,
+		scanCachePath: scanCachePath,
 	}
 }
 
@@ -348,19 +717,29 @@ func (c *Commands) FetchBuilds() tea.Cmd {
 	return func() tea.Msg {
 		// Clean up download states, keeping only active ones
 		newStates := make(map[string]*model.DownloadState)
-		if c.downloads != nil && c.downloads.states != nil {
+		if c.downloads != nil {
+			c.downloads.mu.Lock()
 			for id, state := range c.downloads.states {
 				// Only keep states that are actively in progress, discard terminal states like Failed/Cancelled.
-				if state.BuildState == model.StateDownloading || state.BuildState == model.StateExtracting {
+				if state.BuildState == model.StateDownloading ||
+					state.BuildState == model.StateExtracting ||
+					state.BuildState == model.StateQueued ||
+					state.BuildState == model.StatePaused ||
+					state.BuildState == model.StateScheduled {
 					newStates[id] = state
 				}
 			}
-			c.downloads.states = newStates // Atomically replace the map
+			c.downloads.states = newStates
+			c.downloads.mu.Unlock()
 		}
 
 		// Create API instance
 		a := api.NewAPI()
+		start := time.Now()
 		builds, err := a.FetchBuilds(c.cfg.VersionFilter, c.cfg.BuildType)
+		if err == nil {
+			c.lastFetchDuration = time.Since(start)
+		}
 		return buildsFetchedMsg{builds, err}
 	}
 }
@@ -368,15 +747,94 @@ func (c *Commands) FetchBuilds() tea.Cmd {
 // ScanLocalBuilds creates a command to scan for local builds
 func (c *Commands) ScanLocalBuilds() tea.Cmd {
 	return func() tea.Msg {
-		builds, err := local.ScanLocalBuilds(c.cfg.DownloadDir)
-		return localBuildsScannedMsg{builds: builds, err: err}
+		builds, err := local.ScanLocalBuilds(c.cfg.Roots()...)
+		return c.localBuildsScanned(builds, err)
+	}
+}
+
+// StreamLocalBuilds creates a command to scan for local builds like
+// ScanLocalBuilds, which reports the builds found so far in batches until the
+// scan is done, so a slow library doesn't keep the list empty. The builds of
+// the last scan come first, from the scan cache.
+func (c *Commands) StreamLocalBuilds() tea.Cmd {
+	return func() tea.Msg {
+		var cached []model.BlenderBuild
+		if c.scanCachePath != "" {
+			cached = local.LoadScanCache(c.scanCachePath, c.cfg.Roots())
+		}
+
+		found := make(chan model.BlenderBuild, 64)
+		done := make(chan tea.Msg, 1)
+		go func() {
+			builds, err := local.ScanLocalBuildsFunc(func(build model.BlenderBuild) {
+				found <- build
+			}, c.cfg.Roots()...)
+			close(found)
+			done <- c.localBuildsScanned(builds, err)
+		}()
+
+		var next tea.Cmd
+		next = func() tea.Msg {
+			build, ok := <-found
+			if !ok {
+				return <-done
+			}
+			// Whatever else was found meanwhile comes along
+			builds := []model.BlenderBuild{build}
+			for more := true; more; {
+				select {
+				case build, ok := <-found:
+					if ok {
+						builds = append(builds, build)
+					} else {
+						more = false
+					}
+				default:
+					more = false
+				}
+			}
+			return localBuildsFoundMsg{builds: builds, next: next}
+		}
+		if len(cached) > 0 {
+			return localBuildsFoundMsg{builds: cached, next: next}
+		}
+		return next()
+	}
+}
+
+// localBuildsScanned creates the message reporting a finished scan.
+func (c *Commands) localBuildsScanned(builds []model.BlenderBuild, err error) tea.Msg {
+	if err != nil {
+		return localBuildsScannedMsg{err: err}
+	}
+	c.saveScanCache(builds)()
+	// Unfinished downloads are listed too, they may be resumed without fetching first
+	interrupted, _ := download.FindInterrupted(c.cfg.DownloadDir)
+	return localBuildsScannedMsg{builds: builds, interrupted: interrupted}
+}
+
+// saveScanCache creates a command saving the builds of a scan for the next
+// start. Failing to is not worth telling, the next start just scans first.
+func (c *Commands) saveScanCache(builds []model.BlenderBuild) tea.Cmd {
+	path, roots := c.scanCachePath, c.cfg.Roots()
+	return func() tea.Msg {
+		if path != "" {
+			_ = local.SaveScanCache(path, roots, builds)
+		}
+		return nil
 	}
 }
 
-// CheckUpdateAvailable determines if an update is available for a local build by comparing build dates, branch, and release_cycle.
-func CheckUpdateAvailable(localBuild, onlineBuild model.BlenderBuild) model.BuildState {
+// CheckUpdateAvailable determines if an update is available for a local build
+// of the same version, branch and release cycle as an online build.
+//
+// Commit hashes take precedence: different hashes mean an update, whatever the
+// build dates say, so clocks that were skewed when either build was dated don't
+// matter. Dates are only compared when a hash is missing on either side, or
+// always with dateOnly set, for sources whose hashes aren't meaningful.
+func CheckUpdateAvailable(localBuild, onlineBuild model.BlenderBuild, dateOnly bool) model.BuildState {
 	// If online build hash is present and matches local build hash, treat as identical (no update)
-	if onlineBuild.Hash != "" && onlineBuild.Hash == localBuild.Hash {
+	if !dateOnly && onlineBuild.Hash != "" && onlineBuild.Hash == localBuild.Hash {
 		return model.StateLocal
 	}
 
@@ -385,6 +843,11 @@ func CheckUpdateAvailable(localBuild, onlineBuild model.BlenderBuild) model.Buil
 		return model.StateOnline
 	}
 
+	// A different commit is a different build, even if dated the same or earlier
+	if !dateOnly && onlineBuild.Hash != "" && localBuild.Hash != "" {
+		return model.StateUpdate
+	}
+
 	// If local build date is not set, assume update is available
 	if localBuild.BuildDate.Time().IsZero() {
 		return model.StateUpdate
@@ -402,7 +865,7 @@ func CheckUpdateAvailable(localBuild, onlineBuild model.BlenderBuild) model.Buil
 // UpdateBuildStatus creates a command to update status of builds based on local scan
 func (c *Commands) UpdateBuildStatus(onlineBuilds []model.BlenderBuild) tea.Cmd {
 	return func() tea.Msg {
-		localBuilds, err := local.ScanLocalBuilds(c.cfg.DownloadDir)
+		localBuilds, err := local.ScanLocalBuilds(c.cfg.Roots()...)
 		if err != nil {
 			return errMsg{fmt.Errorf("failed local scan during status update: %w", err)}
 		}
@@ -435,12 +898,30 @@ func (c *Commands) UpdateBuildStatus(onlineBuilds []model.BlenderBuild) tea.Cmd
 			if localBuild == nil {
 				if lb, found := localBuildMap[onlineBuild.Version]; found {
 					localBuild = &lb
-					status = CheckUpdateAvailable(*localBuild, onlineBuild)
+					status = CheckUpdateAvailable(*localBuild, onlineBuild, c.cfg.UpdateCheck == config.UpdateCheckDate)
+				}
+			}
+
+			// A fully prefetched archive only needs to be installed, a partial one
+			// was left by an interrupted download
+			if status == model.StateOnline || status == model.StateUpdate {
+				if download.IsArchiveComplete(onlineBuild, c.cfg.DownloadDir) {
+					status = model.StatePrefetched
+				} else if download.PartialSize(onlineBuild, c.cfg.DownloadDir) > 0 {
+					status = model.StateInterrupted
 				}
 			}
 
 			updated := onlineBuild
 			updated.Status = status
+			if status == model.StateLocal {
+				updated.InstalledSize = localBuild.InstalledSize
+			}
+			if localBuild != nil {
+				updated.Tags, updated.Favorite, updated.ExpiresAt = localBuild.Tags, localBuild.Favorite, localBuild.ExpiresAt
+				updated.LastLaunched, updated.LaunchCount = localBuild.LastLaunched, localBuild.LaunchCount
+				updated.Duplicates = localBuild.Duplicates
+			}
 
 			// Composite key: version|branch|releaseCycle
 			key := onlineBuild.Version + "|" + onlineBuild.Branch + "|" + onlineBuild.ReleaseCycle
@@ -472,24 +953,11 @@ func (c *Commands) DoDownload(build model.BlenderBuild) tea.Cmd {
 	}
 }
 
-// StartTicker starts a ticker to regularly update the UI during downloads
-func (c *Commands) StartTicker() tea.Cmd {
+// DoReinstall creates a command to download and install an installed build
+// again, replacing a corrupted install if corrupted is set
+func (c *Commands) DoReinstall(build model.BlenderBuild, corrupted bool) tea.Cmd {
 	return func() tea.Msg {
-		ticker := time.NewTicker(500 * time.Millisecond)
-		done := make(chan bool)
-
-		go func() {
-			for {
-				select {
-				case <-done:
-					return
-				case t := <-ticker.C:
-					programCh <- tickMsg(t)
-				}
-			}
-		}()
-
-		return nil
+		return c.downloads.StartReinstall(build, corrupted)
 	}
 }
 
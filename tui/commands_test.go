@@ -0,0 +1,44 @@
+package tui
+
+import (
+	"TUI-Blender-Launcher/model"
+	"testing"
+	"time"
+)
+
+func TestCheckUpdateAvailable(t *testing.T) {
+	day := func(d int) model.Timestamp {
+		return model.Timestamp(time.Date(2025, time.March, d, 12, 0, 0, 0, time.UTC))
+	}
+	build := func(hash string, date model.Timestamp) model.BlenderBuild {
+		return model.BlenderBuild{Version: "4.5.0", Branch: "main", ReleaseCycle: "alpha", Hash: hash, BuildDate: date}
+	}
+
+	tests := []struct {
+		name     string
+		local    model.BlenderBuild
+		online   model.BlenderBuild
+		dateOnly bool
+		want     model.BuildState
+	}{
+		{"same hash", build("aaa", day(10)), build("aaa", day(12)), false, model.StateLocal},
+		{"newer commit", build("aaa", day(10)), build("bbb", day(12)), false, model.StateUpdate},
+		// A local clock running ahead dated the install after the online build
+		{"new commit dated earlier", build("aaa", day(20)), build("bbb", day(12)), false, model.StateUpdate},
+		{"new commit dated the same", build("aaa", day(12)), build("bbb", day(12)), false, model.StateUpdate},
+		// Without hashes on both sides dates decide
+		{"no local hash, newer", build("", day(10)), build("bbb", day(12)), false, model.StateUpdate},
+		{"no local hash, older", build("", day(12)), build("bbb", day(10)), false, model.StateLocal},
+		{"no dates", build("", model.Timestamp{}), build("", day(10)), false, model.StateUpdate},
+		// Date-only comparison ignores hashes
+		{"date only, older", build("aaa", day(20)), build("bbb", day(12)), true, model.StateLocal},
+		{"date only, newer", build("aaa", day(10)), build("aaa", day(12)), true, model.StateUpdate},
+		// Different branches are different builds
+		{"other branch", build("aaa", day(10)), model.BlenderBuild{Version: "4.5.0", Branch: "v45", ReleaseCycle: "alpha", Hash: "bbb"}, false, model.StateOnline},
+	}
+	for _, tt := range tests {
+		if got := CheckUpdateAvailable(tt.local, tt.online, tt.dateOnly); got != tt.want {
+			t.Errorf("%s: got %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
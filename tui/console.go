@@ -0,0 +1,232 @@
+package tui
+
+import (
+	"TUI-Blender-Launcher/config"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	lp "github.com/charmbracelet/lipgloss"
+)
+
+// Limits of the output kept for the console view, so a chatty Blender
+// doesn't slow the launcher down
+const (
+	consoleTailBytes = 256 * 1024
+	consoleMaxLines  = 2000
+)
+
+// consoleState is the output of a Blender started in the background, shown
+// in the console view.
+type consoleState struct {
+	version string
+	logPath string
+	lines   []string
+	follow  bool // Scrolled to the end, so new output shows up
+}
+
+// consoleReadMsg carries the output read from a console log.
+type consoleReadMsg struct {
+	logPath string
+	lines   []string
+	err     error
+}
+
+// handleShowConsole opens the console of the running Blender of the
+// selected build, or else of the Blender started in the background last.
+func (m *Model) handleShowConsole() (tea.Model, tea.Cmd) {
+	target := m.lastStarted
+	if len(m.running) > 0 {
+		target = m.running[len(m.running)-1]
+	}
+	if build := m.List.GetSelectedBuild(); build != nil {
+		for _, running := range m.running {
+			if running.version == build.Version {
+				target = running
+			}
+		}
+	}
+	if target.logPath == "" {
+		m.err = fmt.Errorf("no Blender started in the background yet, set launch_mode = %q to follow its output here", config.LaunchModeBackground)
+		return m, nil
+	}
+
+	if target.logPath != m.console.logPath {
+		m.console = consoleState{version: target.version, logPath: target.logPath}
+	}
+	m.console.follow = true
+	m.currentView = viewConsole
+	m.clampConsole()
+	return m, readConsole(target.logPath)
+}
+
+// clampConsole keeps the scroll offset within the console's output, at its
+// end while following it, and follows again once scrolled to the end.
+func (m *Model) clampConsole() {
+	maxOffset := max(len(m.console.lines)-m.consoleOutputHeight(), 0)
+	if m.console.follow {
+		m.scrollOffset = maxOffset
+	}
+	m.scrollOffset = min(m.scrollOffset, maxOffset)
+	m.console.follow = m.scrollOffset == maxOffset
+}
+
+// consoleOutputHeight returns the lines of output the console view shows,
+// below its title.
+func (m *Model) consoleOutputHeight() int {
+	return max(m.contentHeight()-2, 1)
+}
+
+// consoleRunning tells whether the Blender shown in the console still runs.
+func (m *Model) consoleRunning() bool {
+	for _, running := range m.running {
+		if running.logPath == m.console.logPath {
+			return true
+		}
+	}
+	return false
+}
+
+// readConsole returns a command reading the end of the log at logPath.
+func readConsole(logPath string) tea.Cmd {
+	return func() tea.Msg {
+		lines, err := readLogTail(logPath)
+		return consoleReadMsg{logPath: logPath, lines: lines, err: err}
+	}
+}
+
+// readLogTail returns the last lines of the log at path, at most
+// consoleMaxLines from its last consoleTailBytes.
+func readLogTail(path string) ([]string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open %s: %w", path, err)
+	}
+	defer file.Close()
+	info, err := file.Stat()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+	offset := max(info.Size()-consoleTailBytes, 0)
+	data := make([]byte, info.Size()-offset)
+	if _, err := file.ReadAt(data, offset); err != nil && !errors.Is(err, io.EOF) {
+		return nil, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+
+	text := strings.TrimSuffix(strings.ReplaceAll(string(data), "\r\n", "\n"), "\n")
+	if text == "" {
+		return nil, nil
+	}
+	lines := strings.Split(text, "\n")
+	if offset > 0 {
+		lines = lines[1:] // Cut mid-line
+	}
+	for i, line := range lines {
+		// Progress redrawn in place shows as it ended up
+		line = line[strings.LastIndex(line, "\r")+1:]
+		lines[i] = strings.ReplaceAll(line, "\t", "    ")
+	}
+	return lines[max(len(lines)-consoleMaxLines, 0):], nil
+}
+
+// handleConsoleRead shows the output read, if it's still of the console's Blender.
+func (m *Model) handleConsoleRead(msg consoleReadMsg) (tea.Model, tea.Cmd) {
+	if msg.logPath != m.console.logPath {
+		return m, nil
+	}
+	if msg.err != nil {
+		m.err = msg.err
+		return m, nil
+	}
+	m.console.lines = msg.lines
+	if m.currentView == viewConsole {
+		m.clampConsole()
+	}
+	return m, nil
+}
+
+// updateConsoleViewController handles keys in the console view
+func (m *Model) updateConsoleViewController(msg tea.Msg) (tea.Model, tea.Cmd) {
+	keyMsg, ok := msg.(tea.KeyMsg)
+	if !ok {
+		return m.updateListViewController(msg)
+	}
+
+	page := m.consoleOutputHeight()
+	for _, cmd := range GetCommandsForView(viewConsole) {
+		if !MatchKey(keyMsg, cmd.Type) {
+			continue
+		}
+		switch cmd.Type {
+		case CmdQuit:
+			return m, tea.Quit
+		case CmdBack, CmdShowConsole:
+			m.currentView = viewList
+			m.scrollOffset = 0
+			return m, nil
+		case CmdMoveUp:
+			m.scrollOffset = max(m.scrollOffset-1, 0)
+			m.console.follow = false
+		case CmdMoveDown:
+			m.scrollOffset++
+		case CmdPageUp:
+			m.scrollOffset = max(m.scrollOffset-page, 0)
+			m.console.follow = false
+		case CmdPageDown:
+			m.scrollOffset += page
+		case CmdEnd:
+			m.console.follow = true
+		}
+		m.clampConsole()
+		return m, nil
+	}
+	return m, nil
+}
+
+// renderConsole renders the output of the console's Blender to fit the given
+// width and height, from the scroll offset Update keeps within the output.
+func (m *Model) renderConsole(width, height int) string {
+	titleStyle := lp.NewStyle().Bold(true).Foreground(lp.Color(highlightColor))
+	innerWidth := max(width-2*formPadding, 1)
+
+	state := "exited"
+	if m.consoleRunning() {
+		state = "running"
+	}
+	title := lp.NewStyle().MaxWidth(innerWidth).Render(
+		titleStyle.Render("Console of Blender "+m.console.version) + fmt.Sprintf(" (%s) %s", state, m.console.logPath))
+	if len(m.console.lines) == 0 {
+		return lp.NewStyle().Padding(0, formPadding).Render(title + "\n\nNo output yet.")
+	}
+
+	lineStyle := lp.NewStyle().MaxWidth(innerWidth)
+	lines := make([]string, len(m.console.lines))
+	for i, line := range m.console.lines {
+		lines[i] = lineStyle.Render(line)
+	}
+	outputHeight := max(height-2, 1)
+	output := clipLines(strings.Join(lines, "\n"), outputHeight, m.scrollOffset+outputHeight-1)
+	return lp.NewStyle().Padding(0, formPadding).Render(title + "\n\n" + output)
+}
+
+// renderConsoleFooter renders the footer for the console view
+func (m *Model) renderConsoleFooter() string {
+	keyStyle := m.Style.Key
+	separator := m.Style.Separator.Render(" · ")
+	newlineStyle := m.Style.Newline.Render("\n")
+
+	commands := []string{fmt.Sprintf("%s Scroll", keyStyle.Render("↑/↓"))}
+	if !m.console.follow {
+		commands = append(commands, fmt.Sprintf("%s Follow", keyStyle.Render("end")))
+	}
+	commands = append(commands,
+		fmt.Sprintf("%s Back", keyStyle.Render("esc/~")),
+		fmt.Sprintf("%s Quit", keyStyle.Render("q")),
+	)
+
+	footerContent := m.renderStatus() + newlineStyle + strings.Join(commands, separator)
+	return m.Style.Footer.Width(m.terminalWidth).Render(footerContent)
+}
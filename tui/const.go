@@ -1,6 +1,8 @@
 package tui
 
 import (
+	"time"
+
 	"github.com/charmbracelet/bubbles/key"
 )
 
@@ -13,6 +15,41 @@ const (
 	orangeColor     = "208" // Orange for local builds
 	greenColor      = "46"  // Green for updated builds
 	redColor        = "196" // Red for failed downloads
+	cyanColor       = "51"  // Cyan for prefetched builds
+	yellowColor     = "226" // Yellow for expired temporary builds
+)
+
+// Prefetch heuristics
+const (
+	// How long the user must be idle before a prefetch starts
+	prefetchIdleDelay = 2 * time.Minute
+	// Fetches slower than this suggest a poor network, so prefetching is skipped
+	prefetchMaxFetchLatency = 3 * time.Second
+)
+
+// Tick loop intervals. With nothing in progress the loop stops altogether and
+// the next message wakes it again.
+const (
+	// Downloads and extractions redraw at this rate
+	activeTickInterval = 250 * time.Millisecond
+	// Paused or queued downloads and running launch jobs only need occasional refreshes
+	slowTickInterval = time.Second
+	// Delay of the first tick after waking up
+	wakeTickDelay = 10 * time.Millisecond
+)
+
+// Integrity check, repair and test run results shown in the status column
+const (
+	verifyRunning    = "Verifying..."
+	verifyPassed     = "Verify: Pass"
+	verifyCorrupted  = "Corrupted" // Files are missing or damaged
+	verifyNoManifest = "No manifest"
+	repairRunning    = "Repairing..."
+	repairDone       = "Repaired"
+	repairFailed     = "Repair: Fail"
+	checkRunning     = "Checking..."
+	checkPassed      = "Runs: OK"
+	checkFailed      = "Runs: Fail"
 )
 
 // View states
@@ -22,6 +59,14 @@ const (
 	viewList viewState = iota
 	viewInitialSetup
 	viewSettings
+	viewJobs
+	viewChangelog
+	viewInsights
+	viewCleanup
+	viewExecutables
+	viewUserConfigs
+	viewRecentFiles
+	viewConsole
 )
 
 // Command types for key bindings
@@ -43,11 +88,49 @@ const (
 	CmdSaveSettings
 	CmdToggleEditMode
 	CmdCancelDownload
-	CmdPageUp         // Add PageUp command
-	CmdPageDown       // Add PageDown command
-	CmdHome           // Add Home command
-	CmdEnd            // Add End command
-	CmdCleanOldBuilds // Add command for cleaning old builds
+	CmdPageUp            // Add PageUp command
+	CmdPageDown          // Add PageDown command
+	CmdHome              // Add Home command
+	CmdEnd               // Add End command
+	CmdCleanOldBuilds    // Add command for cleaning old builds
+	CmdShowJobs          // Queue a headless job for the selected build
+	CmdAddJob            // Add the job described in the jobs form
+	CmdCancelJob         // Cancel the selected job
+	CmdBack              // Return to the build list
+	CmdVerifyBuild       // Re-hash the selected build against its manifest
+	CmdPasteBuild        // Install the build of a URL or hash in the clipboard
+	CmdShareBuild        // Copy a descriptor of the selected build
+	CmdPauseDownload     // Pause or resume the selected download
+	CmdShowChangelog     // Show what's new in the launcher
+	CmdShowInsights      // Show the locally recorded usage insights
+	CmdShowCleanup       // Pick what to delete to free disk space
+	CmdToggleCleanupItem // Select or deselect an item for deletion
+	CmdRunCleanup        // Delete the selected items
+	CmdScheduleDownload  // Download the selected build at a later time
+	CmdMarkBuild         // Mark the selected build for downloading together with others
+	CmdClearMarks        // Unmark all builds
+	CmdCleanTempFiles    // Remove leftovers of crashed sessions from .downloading
+	CmdRepairBuild       // Extract the selected build again from its kept archive
+	CmdReinstallBuild    // Download the selected installed build again
+	CmdShowExecutables   // Pick another program shipped in the selected build to run
+	CmdSetDefaultBuild   // Point the current symlink at the selected build
+	CmdTagBuild          // Edit the tags of the selected build
+	CmdToggleFavorite    // Mark the selected build as a favorite, or unmark it
+	CmdFilterTags        // Only list builds with a tag
+	CmdTryBuild          // Download the selected build temporarily, or keep a temporary one
+	CmdInstallRelease    // Download the announced stable release
+	CmdImportBuild       // Register a Blender installed outside the launcher
+	CmdExportBuild       // Pack the selected build into a portable archive
+	CmdMoveBuild         // Move the selected build to another install root
+	CmdRollbackBuild     // Restore the build the last update replaced
+	CmdRemoveDuplicates  // Delete the identical copies of the selected build
+	CmdShowUserConfigs   // List Blender's user files per version series
+	CmdCheckBuild        // Test run the selected build's executable
+	CmdLaunchWithEnv     // Launch the selected build with a picked env profile
+	CmdShowRecentFiles   // Pick a recent file to launch the selected build with
+	CmdRenderBuild       // Render a file with the selected build as a launch job
+	CmdKillBuild         // Kill the running Blender of the selected build
+	CmdShowConsole       // Show the output of Blender started in the background
 )
 
 // KeyCommand defines a keyboard command with its key binding and description
@@ -81,6 +164,39 @@ var (
 		{Type: CmdPageDown, Keys: []string{"pgdown"}, Description: "Page down"},
 		{Type: CmdHome, Keys: []string{"home"}, Description: "Go to first item"},
 		{Type: CmdEnd, Keys: []string{"end"}, Description: "Go to last item"},
+		{Type: CmdShowJobs, Keys: []string{"b"}, Description: "Queue headless job"},
+		{Type: CmdVerifyBuild, Keys: []string{"v"}, Description: "Verify build integrity"},
+		{Type: CmdPasteBuild, Keys: []string{"p"}, Description: "Install build from clipboard"},
+		{Type: CmdShareBuild, Keys: []string{"y"}, Description: "Copy shareable build descriptor"},
+		{Type: CmdPauseDownload, Keys: []string{" "}, Description: "Pause/resume download"},
+		{Type: CmdShowChangelog, Keys: []string{"w"}, Description: "What's new in the launcher"},
+		{Type: CmdShowInsights, Keys: []string{"i"}, Description: "Show usage insights"},
+		{Type: CmdShowCleanup, Keys: []string{"c"}, Description: "Clean up to free disk space"},
+		{Type: CmdScheduleDownload, Keys: []string{"t"}, Description: "Schedule download"},
+		{Type: CmdMarkBuild, Keys: []string{"m"}, Description: "Mark build for download"},
+		{Type: CmdClearMarks, Keys: []string{"esc"}, Description: "Clear marks and dismiss announcement"},
+		{Type: CmdCleanTempFiles, Keys: []string{"C"}, Description: "Clean temp files"},
+		{Type: CmdRepairBuild, Keys: []string{"R"}, Description: "Repair build from kept archive"},
+		{Type: CmdReinstallBuild, Keys: []string{"D"}, Description: "Download installed build again"},
+		{Type: CmdShowExecutables, Keys: []string{"e"}, Description: "Run bundled executable"},
+		{Type: CmdSetDefaultBuild, Keys: []string{"*"}, Description: "Make build the default"},
+		{Type: CmdTagBuild, Keys: []string{"g"}, Description: "Tag build"},
+		{Type: CmdToggleFavorite, Keys: []string{"F"}, Description: "Toggle favorite"},
+		{Type: CmdFilterTags, Keys: []string{"/"}, Description: "Filter by tag"},
+		{Type: CmdTryBuild, Keys: []string{"T"}, Description: "Try build temporarily"},
+		{Type: CmdInstallRelease, Keys: []string{"I"}, Description: "Install announced release"},
+		{Type: CmdImportBuild, Keys: []string{"a"}, Description: "Import installed Blender"},
+		{Type: CmdExportBuild, Keys: []string{"E"}, Description: "Export build to archive"},
+		{Type: CmdMoveBuild, Keys: []string{"M"}, Description: "Move build to another root"},
+		{Type: CmdRollbackBuild, Keys: []string{"u"}, Description: "Roll back to previous build"},
+		{Type: CmdRemoveDuplicates, Keys: []string{"X"}, Description: "Remove duplicate copies"},
+		{Type: CmdShowUserConfigs, Keys: []string{"P"}, Description: "Manage Blender configs"},
+		{Type: CmdCheckBuild, Keys: []string{"V"}, Description: "Check build runs"},
+		{Type: CmdLaunchWithEnv, Keys: []string{"L"}, Description: "Launch with env profile"},
+		{Type: CmdShowRecentFiles, Keys: []string{"O"}, Description: "Open recent file"},
+		{Type: CmdRenderBuild, Keys: []string{"A"}, Description: "Render file in the background"},
+		{Type: CmdKillBuild, Keys: []string{"K"}, Description: "Kill running Blender"},
+		{Type: CmdShowConsole, Keys: []string{"~"}, Description: "Toggle Blender console"},
 	}
 
 	// Settings view commands
@@ -93,6 +209,70 @@ var (
 		{Type: CmdMoveRight, Keys: []string{"right", "l"}, Description: "Select next option"},
 		{Type: CmdCleanOldBuilds, Keys: []string{"c"}, Description: "Clean old builds"},
 	}
+
+	// Jobs view commands
+	JobsCommands = []KeyCommand{
+		{Type: CmdAddJob, Keys: []string{"a"}, Description: "Add job to the queue"},
+		{Type: CmdCancelJob, Keys: []string{"x"}, Description: "Cancel selected job"},
+		{Type: CmdToggleEditMode, Keys: []string{"enter"}, Description: "Toggle edit mode"},
+		{Type: CmdMoveUp, Keys: []string{"up", "k"}, Description: "Move cursor up"},
+		{Type: CmdMoveDown, Keys: []string{"down", "j"}, Description: "Move cursor down"},
+		{Type: CmdBack, Keys: []string{"esc"}, Description: "Back to builds"},
+	}
+
+	// Commands of the read-only changelog and insights views
+	ScrollCommands = []KeyCommand{
+		{Type: CmdMoveUp, Keys: []string{"up", "k"}, Description: "Scroll up"},
+		{Type: CmdMoveDown, Keys: []string{"down", "j"}, Description: "Scroll down"},
+		{Type: CmdBack, Keys: []string{"esc"}, Description: "Back to builds"},
+	}
+
+	// Cleanup view commands
+	CleanupCommands = []KeyCommand{
+		{Type: CmdMoveUp, Keys: []string{"up", "k"}, Description: "Move cursor up"},
+		{Type: CmdMoveDown, Keys: []string{"down", "j"}, Description: "Move cursor down"},
+		{Type: CmdToggleCleanupItem, Keys: []string{" "}, Description: "Select for deletion"},
+		{Type: CmdRunCleanup, Keys: []string{"enter"}, Description: "Delete selected"},
+		{Type: CmdBack, Keys: []string{"esc"}, Description: "Back to builds"},
+	}
+
+	// Bundled executables view commands
+	ExecutablesCommands = []KeyCommand{
+		{Type: CmdMoveUp, Keys: []string{"up", "k"}, Description: "Move cursor up"},
+		{Type: CmdMoveDown, Keys: []string{"down", "j"}, Description: "Move cursor down"},
+		{Type: CmdLaunchBuild, Keys: []string{"enter"}, Description: "Run selected executable"},
+		{Type: CmdBack, Keys: []string{"esc"}, Description: "Back to builds"},
+	}
+
+	// Blender configs view commands
+	UserConfigsCommands = []KeyCommand{
+		{Type: CmdMoveUp, Keys: []string{"up", "k"}, Description: "Move cursor up"},
+		{Type: CmdMoveDown, Keys: []string{"down", "j"}, Description: "Move cursor down"},
+		{Type: CmdToggleCleanupItem, Keys: []string{" "}, Description: "Select for purging"},
+		{Type: CmdRunCleanup, Keys: []string{"enter"}, Description: "Purge selected"},
+		{Type: CmdOpenBuildDir, Keys: []string{"o"}, Description: "Open config directory"},
+		{Type: CmdBack, Keys: []string{"esc"}, Description: "Back to builds"},
+	}
+
+	// Recent files view commands
+	RecentFilesCommands = []KeyCommand{
+		{Type: CmdMoveUp, Keys: []string{"up", "k"}, Description: "Move cursor up"},
+		{Type: CmdMoveDown, Keys: []string{"down", "j"}, Description: "Move cursor down"},
+		{Type: CmdLaunchBuild, Keys: []string{"enter"}, Description: "Launch with selected file"},
+		{Type: CmdRenderBuild, Keys: []string{"A"}, Description: "Render selected file in the background"},
+		{Type: CmdBack, Keys: []string{"esc"}, Description: "Back to builds"},
+	}
+
+	// Console view commands
+	ConsoleCommands = []KeyCommand{
+		{Type: CmdMoveUp, Keys: []string{"up", "k"}, Description: "Scroll up"},
+		{Type: CmdMoveDown, Keys: []string{"down", "j"}, Description: "Scroll down"},
+		{Type: CmdPageUp, Keys: []string{"pgup"}, Description: "Page up"},
+		{Type: CmdPageDown, Keys: []string{"pgdown"}, Description: "Page down"},
+		{Type: CmdEnd, Keys: []string{"end"}, Description: "Follow new output"},
+		{Type: CmdShowConsole, Keys: []string{"~"}, Description: "Back to builds"},
+		{Type: CmdBack, Keys: []string{"esc"}, Description: "Back to builds"},
+	}
 )
 
 // GetKeyBinding returns a tea key binding for the given command type
@@ -125,6 +305,24 @@ func GetKeyBinding(cmdType CommandType) key.Binding {
 		}
 	}
 
+	if keys == nil {
+		for _, cmd := range JobsCommands {
+			if cmd.Type == cmdType {
+				keys = cmd.Keys
+				break
+			}
+		}
+	}
+
+	if keys == nil {
+		for _, cmd := range CleanupCommands {
+			if cmd.Type == cmdType {
+				keys = cmd.Keys
+				break
+			}
+		}
+	}
+
 	return key.NewBinding(key.WithKeys(keys...))
 }
 
@@ -138,6 +336,20 @@ func GetCommandsForView(view viewState) []KeyCommand {
 		result = append(result, ListCommands...)
 	case viewSettings, viewInitialSetup:
 		result = append(result, SettingsCommands...)
+	case viewJobs:
+		result = append(result, JobsCommands...)
+	case viewChangelog, viewInsights:
+		result = append(result, ScrollCommands...)
+	case viewCleanup:
+		result = append(result, CleanupCommands...)
+	case viewExecutables:
+		result = append(result, ExecutablesCommands...)
+	case viewUserConfigs:
+		result = append(result, UserConfigsCommands...)
+	case viewRecentFiles:
+		result = append(result, RecentFilesCommands...)
+	case viewConsole:
+		result = append(result, ConsoleCommands...)
 	}
 
 	return result
@@ -0,0 +1,49 @@
+package tui
+
+import (
+	"TUI-Blender-Launcher/local"
+	"TUI-Blender-Launcher/model"
+	"fmt"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// defaultBuildSetMsg reports pointing the current symlink at a build.
+type defaultBuildSetMsg struct {
+	buildID string
+	link    string
+	err     error
+}
+
+// handleSetDefaultBuild points the current symlink in the download directory
+// at the selected installed build.
+func (m *Model) handleSetDefaultBuild() (tea.Model, tea.Cmd) {
+	build := m.List.GetSelectedBuild()
+	if build == nil || (build.Status != model.StateLocal && build.Status != model.StateUpdate) {
+		return m, nil
+	}
+	roots, downloadDir, version, buildID := m.config.Roots(), m.config.DownloadDir, build.Version, downloadID(*build)
+	return m, func() tea.Msg {
+		link, err := local.SetCurrentBuild(roots, downloadDir, version)
+		return defaultBuildSetMsg{buildID: buildID, link: link, err: err}
+	}
+}
+
+// handleDefaultBuildSet marks the build the current symlink points at now.
+func (m *Model) handleDefaultBuildSet(msg defaultBuildSetMsg) (tea.Model, tea.Cmd) {
+	if msg.err != nil {
+		m.err = fmt.Errorf("failed to make the build the default: %w", msg.err)
+		return m, nil
+	}
+	m.defaultBuild = msg.buildID
+	m.notice = msg.link + " now points at the selected build"
+	return m, nil
+}
+
+// loadDefaultBuild looks up which build the current symlink points at.
+func (m *Model) loadDefaultBuild() {
+	m.defaultBuild = ""
+	if build := local.CurrentBuild(m.config.DownloadDir); build != nil {
+		m.defaultBuild = downloadID(*build)
+	}
+}
@@ -0,0 +1,137 @@
+package tui
+
+import (
+	"TUI-Blender-Launcher/model"
+	"fmt"
+	"strings"
+	"time"
+
+	lp "github.com/charmbracelet/lipgloss"
+)
+
+// Terminals at least this wide show the selected build's details next to the list
+const splitPaneMinWidth = 180
+
+// detailsPaneWidth is the width of the details pane in the split layout.
+const detailsPaneWidth = 60
+
+// splitPane reports whether the list view shows the details pane.
+func (m *Model) splitPane() bool {
+	return m.terminalWidth >= splitPaneMinWidth
+}
+
+// listWidth is the width available to the build list.
+func (m *Model) listWidth() int {
+	if m.splitPane() {
+		return m.terminalWidth - detailsPaneWidth
+	}
+	return m.terminalWidth
+}
+
+// renderListPanes renders the build list, with the selected build's details
+// beside it when the terminal is wide enough.
+func (m *Model) renderListPanes(height int) string {
+	list := m.renderBuildContent(height)
+	if !m.splitPane() {
+		return list
+	}
+	details := m.Style.DetailsPane.
+		Width(detailsPaneWidth - 1). // The border takes the remaining column
+		Height(height).
+		MaxHeight(height).
+		Render(m.renderBuildDetails(detailsPaneWidth - 3))
+	return lp.JoinHorizontal(lp.Top, list, details)
+}
+
+// renderBuildDetails renders everything known about the selected build to fit the given width.
+func (m *Model) renderBuildDetails(width int) string {
+	build := m.List.GetSelectedBuild()
+	if build == nil {
+		return "No build selected."
+	}
+	buildID := downloadID(*build)
+	titleStyle := lp.NewStyle().Bold(true).Foreground(lp.Color(highlightColor))
+	labelStyle := lp.NewStyle().Width(12).Foreground(lp.Color(highlightColor))
+	valueStyle := lp.NewStyle().Width(max(width-12, 1))
+
+	var b strings.Builder
+	b.WriteString(titleStyle.Render("Blender " + build.Version))
+	b.WriteString("\n\n")
+	field := func(label, value string) {
+		if value == "" {
+			return
+		}
+		b.WriteString(lp.JoinHorizontal(lp.Top, labelStyle.Render(label), valueStyle.Render(value)))
+		b.WriteString("\n")
+	}
+
+	status := build.Status.String()
+	if result := m.verifyResults[buildID]; result != "" {
+		status += ", " + result
+	}
+	if buildID == m.defaultBuild {
+		status += ", default"
+	}
+	if build.Favorite {
+		status += ", favorite"
+	}
+	field("Status", status)
+	if err := m.verifyErrors[buildID]; err != nil {
+		switch m.verifyResults[buildID] {
+		case verifyCorrupted:
+			field("Damaged", err.Error())
+		case checkFailed:
+			field("Reason", err.Error())
+		}
+	}
+	if m.verifyResults[buildID] == verifyCorrupted && build.Status == model.StateLocal {
+		field("Next", "Files are missing or damaged, D downloads the build again and removes this copy once the new one verifies")
+	}
+	field("Tags", strings.Join(build.Tags, ", "))
+	if len(build.Duplicates) > 0 {
+		field("Copies", strings.Join(build.Duplicates, "\n"))
+		field("Next", "Identical copies of this build, X removes them and keeps the listed one")
+	}
+	if build.ExpiresAt != nil {
+		field("Expires", build.ExpiresAt.Format("2006-01-02 15:04")+" ("+temporaryLabel(*build.ExpiresAt, time.Now())+")")
+	}
+	field("Branch", build.Branch)
+	field("Type", build.ReleaseCycle)
+	field("Hash", build.Hash)
+	if !build.BuildDate.Time().IsZero() {
+		field("Built", model.FormatBuildDate(build.BuildDate))
+	}
+	if build.Size > 0 {
+		field("Size", model.FormatByteSize(build.Size))
+	}
+	if build.Status == model.StateLocal && build.InstalledSize > 0 {
+		field("On disk", model.FormatByteSize(build.InstalledSize))
+	}
+	if build.LastLaunched != nil {
+		field("Launched", fmt.Sprintf("%d times, last %s", build.LaunchCount, build.LastLaunched.Format("2006-01-02 15:04")))
+	}
+	if build.OperatingSystem != "" {
+		field("Platform", strings.TrimSpace(build.OperatingSystem+" "+build.Architecture))
+	}
+	if build.Status == model.StateSystem {
+		field("Source", build.FileName)
+		field("Executable", build.Executable)
+	} else {
+		field("File", build.FileName)
+	}
+	field("URL", build.DownloadURL)
+
+	if state := m.Progress.DownloadStates[buildID]; state != nil {
+		switch {
+		case state.BuildState == model.StateScheduled:
+			field("Scheduled", formatScheduled(state.ScheduledAt, time.Now()))
+		case state.Err != nil:
+			b.WriteString("\n")
+			field("Error", state.Err.Error())
+			field("Next", failureGuidance(state.Err))
+		case state.Total > 0 && state.Progress < 1:
+			field("Progress", fmt.Sprintf("%.0f%% of %s", state.Progress*100, model.FormatByteSize(state.Total)))
+		}
+	}
+	return strings.TrimSuffix(b.String(), "\n")
+}
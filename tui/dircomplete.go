@@ -8,6 +8,18 @@ import (
 
 // DirCompletions returns a list of directory completions for the given input path.
 func DirCompletions(input string) ([]string, error) {
+	return pathCompletions(input, "")
+}
+
+// BlendCompletions returns the directories and .blend files completing the
+// given input path.
+func BlendCompletions(input string) ([]string, error) {
+	return pathCompletions(input, ".blend")
+}
+
+// pathCompletions returns the directories completing input, and the files
+// with the extension ext unless it is empty.
+func pathCompletions(input, ext string) ([]string, error) {
 	if input == "" {
 		input = "."
 	}
@@ -39,7 +51,10 @@ func DirCompletions(input string) ([]string, error) {
 	}
 	var matches []string
 	for _, entry := range entries {
-		if entry.IsDir() && strings.HasPrefix(entry.Name(), prefix) {
+		if !strings.HasPrefix(entry.Name(), prefix) {
+			continue
+		}
+		if entry.IsDir() || (ext != "" && strings.EqualFold(filepath.Ext(entry.Name()), ext)) {
 			matches = append(matches, filepath.Join(base, entry.Name()))
 		}
 	}
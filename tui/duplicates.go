@@ -0,0 +1,51 @@
+package tui
+
+import (
+	"TUI-Blender-Launcher/local"
+	"errors"
+	"fmt"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// duplicatesRemovedMsg reports deleting the identical copies of a build.
+type duplicatesRemovedMsg struct {
+	removed int
+	err     error
+}
+
+// handleRemoveDuplicates deletes the identical copies of the selected build,
+// keeping the directory it is listed from.
+func (m *Model) handleRemoveDuplicates() (tea.Model, tea.Cmd) {
+	build := m.List.GetSelectedBuild()
+	if build == nil || len(build.Duplicates) == 0 {
+		return m, nil
+	}
+	return m, removeDuplicates(m.config.Roots(), build.Version, m.config.DeleteToTrash)
+}
+
+// removeDuplicates deletes the identical copies of the build of version,
+// offering to delete them for good if the trash can't take them.
+func removeDuplicates(roots []string, version string, toTrash bool) tea.Cmd {
+	return func() tea.Msg {
+		removed, err := local.RemoveDuplicates(roots, version, toTrash)
+		if errors.Is(err, local.ErrNoTrash) {
+			return noTrashMsg{what: "the copies of Blender " + version, err: err, remove: removeDuplicates(roots, version, false)}
+		}
+		return duplicatesRemovedMsg{removed: removed, err: err}
+	}
+}
+
+// handleDuplicatesRemoved reports the removed copies and lists what is left.
+func (m *Model) handleDuplicatesRemoved(msg duplicatesRemovedMsg) (tea.Model, tea.Cmd) {
+	if msg.err != nil {
+		m.err = fmt.Errorf("removing duplicates failed: %w", msg.err)
+	} else {
+		m.notice = fmt.Sprintf("removed %d duplicate copies", msg.removed)
+	}
+	roots := m.config.Roots()
+	return m, tea.Batch(func() tea.Msg {
+		builds, err := local.ScanLocalBuilds(roots...)
+		return libraryChangedMsg{builds: builds, err: err}
+	}, checkDiskSpace(m.config.DownloadDir))
+}
@@ -0,0 +1,54 @@
+package tui
+
+import (
+	"TUI-Blender-Launcher/model"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// handleLaunchWithEnv asks which env profile to launch the selected build with.
+func (m *Model) handleLaunchWithEnv() (tea.Model, tea.Cmd) {
+	build := m.List.GetSelectedBuild()
+	if build == nil || (build.Status != model.StateLocal && build.Status != model.StateUpdate && build.Status != model.StateSystem) {
+		return m, nil
+	}
+	if len(m.config.EnvProfiles) == 0 {
+		m.err = errors.New("add env_profiles to launch with one")
+		return m, nil
+	}
+	selected := *build
+	m.envLaunch = &selected
+	return m, nil
+}
+
+// updateEnvPrompt handles keys while the env profiles are shown: a profile's
+// number launches the build with it.
+func (m *Model) updateEnvPrompt(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	if msg.String() == "esc" {
+		m.envLaunch = nil
+		return m, nil
+	}
+	names := m.config.EnvProfileNames()
+	choice, err := strconv.Atoi(msg.String())
+	if err != nil || choice < 1 || choice > len(names) {
+		return m, nil
+	}
+	build := *m.envLaunch
+	m.envLaunch = nil
+	return m, m.launchBuild(build, model.BlenderExecMsg{EnvProfile: names[choice-1]})
+}
+
+// renderEnvPrompt renders the env profiles to launch a build with, numbered
+// for choosing one.
+func (m *Model) renderEnvPrompt() string {
+	names := m.config.EnvProfileNames()
+	profiles := make([]string, len(names))
+	for i, name := range names {
+		profiles[i] = fmt.Sprintf("%d %s", i+1, name)
+	}
+	return fmt.Sprintf("Launch %s with: %s", m.envLaunch.Version, strings.Join(profiles, " · "))
+}
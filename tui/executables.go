@@ -0,0 +1,152 @@
+package tui
+
+import (
+	"TUI-Blender-Launcher/config"
+	"TUI-Blender-Launcher/launch"
+	"TUI-Blender-Launcher/local"
+	"TUI-Blender-Launcher/model"
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	lp "github.com/charmbracelet/lipgloss"
+)
+
+// executablesState is the bundled executables view of one build.
+type executablesState struct {
+	version   string
+	dir       string // Directory of the build
+	sandboxed bool   // Programs run through the sandbox command, the build being tagged for it
+	items     []local.BundledExecutable
+	cursor    int
+}
+
+// handleShowExecutables lists the programs shipped in the selected installed
+// build besides Blender itself, to run one with the exact bundled toolchain.
+func (m *Model) handleShowExecutables() (tea.Model, tea.Cmd) {
+	build := m.List.GetSelectedBuild()
+	if build == nil || (build.Status != model.StateLocal && build.Status != model.StateUpdate) {
+		return m, nil
+	}
+	dir, err := local.FindBuildDir(m.config.Roots(), build.Version)
+	if err != nil {
+		m.err = err
+		return m, nil
+	}
+
+	m.executables = executablesState{
+		version:   build.Version,
+		dir:       dir,
+		sandboxed: build.HasTag(config.SandboxTag),
+		items:     local.FindBundledExecutables(dir),
+	}
+	m.currentView = viewExecutables
+	return m, nil
+}
+
+// updateExecutablesViewController handles keys in the bundled executables view
+func (m *Model) updateExecutablesViewController(msg tea.Msg) (tea.Model, tea.Cmd) {
+	keyMsg, ok := msg.(tea.KeyMsg)
+	if !ok {
+		return m.updateListViewController(msg)
+	}
+
+	for _, cmd := range GetCommandsForView(viewExecutables) {
+		if !MatchKey(keyMsg, cmd.Type) {
+			continue
+		}
+		switch cmd.Type {
+		case CmdQuit:
+			return m, tea.Quit
+		case CmdBack:
+			m.currentView = viewList
+			m.executables = executablesState{}
+		case CmdMoveUp:
+			m.executables.cursor = max(m.executables.cursor-1, 0)
+		case CmdMoveDown:
+			m.executables.cursor = max(min(m.executables.cursor+1, len(m.executables.items)-1), 0)
+		case CmdLaunchBuild:
+			if m.executables.cursor >= len(m.executables.items) {
+				return m, nil
+			}
+			path, workDir := m.executables.items[m.executables.cursor].Path, m.config.WorkDirFor("")
+			env, offload, language := m.config.EnvProfiles[m.config.EnvProfile], m.config.GPUOffload, m.config.Language
+			tmux := m.config.LaunchMode == config.LaunchModeTmux && launch.InTmux()
+			// launch_template wraps Blender alone, the sandbox confines every
+			// program of a build tagged for it
+			opts := launch.Options{
+				WorkDir:    workDir,
+				Env:        env,
+				Offload:    offload,
+				Language:   language,
+				PythonPath: m.config.PythonPathFor(m.executables.version),
+				BuildDir:   m.executables.dir,
+			}
+			if m.executables.sandboxed {
+				var err error
+				if opts, err = m.sandbox(opts, m.executables.version, ""); err != nil {
+					m.err = err
+					return m, nil
+				}
+			}
+			return m, func() tea.Msg {
+				var err error
+				if tmux {
+					err = launch.BlenderInTmux(path, filepath.Base(path), opts)
+				} else {
+					err = launch.BlenderInNewTerminal(path, opts)
+				}
+				if err != nil {
+					return errMsg{fmt.Errorf("failed to run %s: %w", path, err)}
+				}
+				return nil
+			}
+		}
+		return m, nil
+	}
+	return m, nil
+}
+
+// renderExecutables renders the programs of the build, to fit the given
+// width and height.
+func (m *Model) renderExecutables(width, height int) string {
+	titleStyle := lp.NewStyle().Bold(true).Foreground(lp.Color(highlightColor))
+	innerWidth := max(width-2*formPadding, 1)
+
+	var b strings.Builder
+	b.WriteString(titleStyle.Render("Executables of Blender " + m.executables.version))
+	b.WriteString("\n\n")
+
+	if len(m.executables.items) == 0 {
+		b.WriteString("This build ships no other executables.")
+		return lp.NewStyle().Padding(0, formPadding).Render(b.String())
+	}
+
+	lines := make([]string, len(m.executables.items))
+	for i, item := range m.executables.items {
+		style := lp.NewStyle().MaxWidth(innerWidth)
+		if i == m.executables.cursor {
+			style = m.Style.SelectedRow.MaxWidth(innerWidth)
+		}
+		lines[i] = style.Render(item.Name)
+	}
+	b.WriteString(clipLines(strings.Join(lines, "\n"), height-2, m.executables.cursor))
+	return lp.NewStyle().Padding(0, formPadding).Render(b.String())
+}
+
+// renderExecutablesFooter renders the footer for the bundled executables view
+func (m *Model) renderExecutablesFooter() string {
+	keyStyle := m.Style.Key
+	separator := m.Style.Separator.Render(" · ")
+	newlineStyle := m.Style.Newline.Render("\n")
+
+	commands := []string{
+		fmt.Sprintf("%s Run", keyStyle.Render("enter")),
+		fmt.Sprintf("%s Back", keyStyle.Render("esc")),
+		fmt.Sprintf("%s Quit", keyStyle.Render("q")),
+	}
+
+	footerContent := m.renderStatus() + newlineStyle + strings.Join(commands, separator)
+	return m.Style.Footer.Width(m.terminalWidth).Render(footerContent)
+}
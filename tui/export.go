@@ -0,0 +1,113 @@
+package tui
+
+import (
+	"TUI-Blender-Launcher/download"
+	"TUI-Blender-Launcher/local"
+	"TUI-Blender-Launcher/model"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// exportDoneMsg reports the end of a build's export.
+type exportDoneMsg struct {
+	buildID string
+	path    string
+	err     error
+}
+
+// newExportInput creates the input asking where to export a build to.
+func newExportInput() textinput.Model {
+	t := textinput.New()
+	t.Prompt = "Export to: "
+	t.Placeholder = "directory, tab completes"
+	t.CharLimit = 256
+	t.Width = 60
+	return t
+}
+
+// handleExportBuild asks where to export the selected installed build to.
+func (m *Model) handleExportBuild() (tea.Model, tea.Cmd) {
+	build := m.List.GetSelectedBuild()
+	if build == nil || (build.Status != model.StateLocal && build.Status != model.StateUpdate) {
+		return m, nil
+	}
+	if _, running := m.transfers[downloadID(*build)]; running {
+		return m, nil
+	}
+	selected := *build
+	m.exportBuild = &selected
+	if m.exportInput.Value() == "" {
+		if homeDir, err := os.UserHomeDir(); err == nil {
+			m.exportInput.SetValue(homeDir + string(os.PathSeparator))
+		}
+	}
+	m.exportInput.CursorEnd()
+	return m, m.exportInput.Focus()
+}
+
+// updateExportInput handles keys while the export input is shown. The last
+// directory stays in the input for the next export.
+func (m *Model) updateExportInput(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "esc":
+		m.exportBuild = nil
+		m.exportInput.Blur()
+		return m, nil
+	case "tab":
+		completePath(&m.exportInput)
+		return m, nil
+	case "enter":
+		destDir := strings.TrimSpace(m.exportInput.Value())
+		build := *m.exportBuild
+		m.exportBuild = nil
+		m.exportInput.Blur()
+		if destDir == "" {
+			return m, nil
+		}
+		return m, m.startExport(build, destDir)
+	}
+
+	var cmd tea.Cmd
+	m.exportInput, cmd = m.exportInput.Update(msg)
+	return m, cmd
+}
+
+// startExport returns a command packing an installed build into destDir,
+// with its progress shown in the build's row like a download.
+func (m *Model) startExport(build model.BlenderBuild, destDir string) tea.Cmd {
+	buildID := downloadID(build)
+	transfers := m.commands.transfers
+	cancelCh, ok := transfers.start(buildID, "Exporting")
+	if !ok {
+		return nil
+	}
+
+	roots, version, withConfig := m.config.Roots(), build.Version, m.config.ExportConfig
+	return func() tea.Msg {
+		progressCb := func(progress float64) {
+			transfers.setProgress(buildID, progress)
+		}
+		path, err := local.ExportBuild(roots, version, destDir, withConfig, progressCb, cancelCh)
+		transfers.finish(buildID)
+		return exportDoneMsg{buildID: buildID, path: path, err: err}
+	}
+}
+
+// handleExportDone reports where the archive went, or why there is none.
+func (m *Model) handleExportDone(msg exportDoneMsg) (tea.Model, tea.Cmd) {
+	if errors.Is(msg.err, download.ErrCancelled) {
+		m.err = nil
+		return m, nil
+	}
+	if msg.err != nil {
+		m.err = fmt.Errorf("export failed: %w", msg.err)
+		return m, nil
+	}
+	m.notice = "exported to " + msg.path
+	return m, nil
+}
@@ -0,0 +1,55 @@
+package tui
+
+import (
+	"TUI-Blender-Launcher/download"
+	"errors"
+)
+
+// failureLabel returns the status cell text of a build whose download failed
+func failureLabel(err error) string {
+	var (
+		netErr        *download.NetworkError
+		diskErr       *download.DiskFullError
+		checksumErr   *download.ChecksumError
+		extractionErr *download.ExtractionError
+	)
+
+	switch {
+	case errors.As(err, &diskErr):
+		return "Failed: Disk full"
+	case errors.As(err, &netErr):
+		return "Failed: Network"
+	case errors.As(err, &checksumErr):
+		return "Failed: Corrupt"
+	case errors.As(err, &extractionErr):
+		return "Failed: Extract"
+	default:
+		return "Failed"
+	}
+}
+
+// failureGuidance tells the user how to recover from a failed download
+func failureGuidance(err error) string {
+	var (
+		netErr        *download.NetworkError
+		diskErr       *download.DiskFullError
+		checksumErr   *download.ChecksumError
+		extractionErr *download.ExtractionError
+	)
+
+	switch {
+	case errors.As(err, &diskErr):
+		return "Free up space, e.g. clean old builds in settings (s, c), then press d to retry"
+	case errors.As(err, &netErr):
+		if download.IsTransient(err) {
+			return "Check your connection and press d to resume the download"
+		}
+		return "The server refused the download, fetch the build list again (f)"
+	case errors.As(err, &checksumErr):
+		return "The archive was discarded, press d to download it again"
+	case errors.As(err, &extractionErr):
+		return "Check permissions of the download directory, then press d to download again"
+	default:
+		return "Press d to retry"
+	}
+}
@@ -2,13 +2,41 @@ package tui
 
 import (
 	"TUI-Blender-Launcher/download"
+	"TUI-Blender-Launcher/launch"
 	"TUI-Blender-Launcher/model"
 	"fmt"
 	"os"
 	"path/filepath"
 	"strings"
+
+	lp "github.com/charmbracelet/lipgloss"
 )
 
+// renderStatus renders the outcome of the last action on one line, "" if
+// there is none to show.
+func (m *Model) renderStatus() string {
+	style, text := m.Style.Notice, m.notice
+	if m.err != nil {
+		style, text = m.Style.Error, m.err.Error()
+	}
+	if text == "" {
+		return ""
+	}
+	text = strings.Join(strings.Fields(text), " ")
+	return style.MaxWidth(max(m.terminalWidth, 1)).Render(text)
+}
+
+// fitCommands joins commands with separator into a line at most width wide.
+// Commands that don't fit are left out from the end, but the last one, such
+// as Quit or Cancel, always stays.
+func fitCommands(commands []string, separator string, width int) string {
+	line := strings.Join(commands, separator)
+	for n := len(commands) - 1; n > 0 && lp.Width(line) > width; n-- {
+		line = strings.Join(append(commands[:n-1:n-1], commands[len(commands)-1]), separator)
+	}
+	return line
+}
+
 // renderBuildFooter renders the footer for the build list view
 func (m *Model) renderBuildFooter() string {
 	keyStyle := m.Style.Key
@@ -20,12 +48,27 @@ func (m *Model) renderBuildFooter() string {
 	generalCommands := []string{
 		fmt.Sprintf("%s Fetch", keyStyle.Render("f")),
 		fmt.Sprintf("%s Reverse Sort", keyStyle.Render("r")),
+		fmt.Sprintf("%s Jobs", keyStyle.Render("b")),
+		fmt.Sprintf("%s Paste", keyStyle.Render("p")),
 		fmt.Sprintf("%s Settings", keyStyle.Render("s")),
 		fmt.Sprintf("%s Quit", keyStyle.Render("q")),
 	}
 
+	// The tag filter hides builds, so it stays in sight while set
+	if m.tagFilter != "" {
+		filter := fmt.Sprintf("%s Tagged %s", keyStyle.Render("/"), m.tagFilter)
+		generalCommands = append([]string{filter}, generalCommands...)
+	}
+
+	// Leftovers of crashed sessions can go once nothing is downloading
+	if len(m.staleFiles) > 0 && !m.hasActiveDownloads() {
+		clean := fmt.Sprintf("%s Clean temp files (%s)", keyStyle.Render("C"), model.FormatByteSize(m.staleSize))
+		generalCommands = append([]string{clean}, generalCommands...)
+	}
+
 	// Contextual commands based on the highlighted build
 	contextualCommands := []string{}
+	guidance := ""
 	if len(m.List.Builds) > 0 && m.List.Cursor < len(m.List.Builds) {
 		build := m.List.Builds[m.List.Cursor]
 		if build.Status == model.StateLocal {
@@ -35,29 +78,61 @@ func (m *Model) renderBuildFooter() string {
 			)
 			contextualCommands = append(contextualCommands,
 				fmt.Sprintf("%s Delete", keyStyle.Render("x")),
+				fmt.Sprintf("%s Verify", keyStyle.Render("v")),
 			)
+			if m.config.KeepArchives {
+				contextualCommands = append(contextualCommands, fmt.Sprintf("%s Repair", keyStyle.Render("R")))
+			}
+			if m.verifyResults[downloadID(build)] == verifyCorrupted {
+				contextualCommands = append(contextualCommands, fmt.Sprintf("%s Re-download", keyStyle.Render("D")))
+			}
 		} else if build.Status == model.StateUpdate {
 			contextualCommands = append(contextualCommands,
 				fmt.Sprintf("%s Download", keyStyle.Render("d")),
 				fmt.Sprintf("%s Launch", keyStyle.Render("enter")),
 				fmt.Sprintf("%s Open Dir", keyStyle.Render("o")),
 				fmt.Sprintf("%s Delete", keyStyle.Render("x")),
+				fmt.Sprintf("%s Verify", keyStyle.Render("v")),
+			)
+			if m.config.KeepArchives {
+				contextualCommands = append(contextualCommands, fmt.Sprintf("%s Repair", keyStyle.Render("R")))
+			}
+		} else if build.Status == model.StateSystem {
+			contextualCommands = append(contextualCommands,
+				fmt.Sprintf("%s Launch", keyStyle.Render("enter")),
+				fmt.Sprintf("%s Open Dir", keyStyle.Render("o")),
+				fmt.Sprintf("%s Check", keyStyle.Render("V")),
+			)
+		} else if build.Status == model.StateInterrupted {
+			contextualCommands = append(contextualCommands,
+				fmt.Sprintf("%s Resume", keyStyle.Render("d")),
+				fmt.Sprintf("%s Discard", keyStyle.Render("x")),
+				fmt.Sprintf("%s Mark", keyStyle.Render("m")),
+			)
+		} else if build.Status == model.StatePrefetched {
+			contextualCommands = append(contextualCommands,
+				fmt.Sprintf("%s Install", keyStyle.Render("d")),
+				fmt.Sprintf("%s Discard", keyStyle.Render("x")),
 			)
 		} else if build.Status == model.StateOnline ||
 			build.Status == model.StateCancelled ||
 			build.Status == model.StateFailed {
 			contextualCommands = append(contextualCommands,
 				fmt.Sprintf("%s Download", keyStyle.Render("d")),
+				fmt.Sprintf("%s Mark", keyStyle.Render("m")),
 			)
 		}
 
 		// Check for active download state
-		buildID := build.Version
-		if build.Hash != "" {
-			buildID = build.Version + "-" + build.Hash[:8]
+		state := m.Progress.DownloadStates[downloadID(build)]
+		if state != nil && state.BuildState == model.StateFailed && state.Err != nil {
+			guidance = failureGuidance(state.Err)
 		}
-		state := m.commands.downloads.GetState(buildID)
-		if state != nil && (state.BuildState == model.StateDownloading || state.BuildState == model.StateExtracting) {
+		if state != nil && (state.BuildState == model.StateDownloading ||
+			state.BuildState == model.StateExtracting ||
+			state.BuildState == model.StateQueued ||
+			state.BuildState == model.StatePaused ||
+			state.BuildState == model.StateScheduled) {
 			// Remove any existing download command
 			filtered := []string{}
 			for _, cmd := range contextualCommands {
@@ -66,14 +141,179 @@ func (m *Model) renderBuildFooter() string {
 				}
 			}
 			contextualCommands = filtered
+			if state.BuildState == model.StateScheduled {
+				contextualCommands = append(contextualCommands,
+					fmt.Sprintf("%s Download now", keyStyle.Render("d")),
+				)
+			}
+			if state.Retry > 0 {
+				contextualCommands = append(contextualCommands,
+					fmt.Sprintf("%s Retry now", keyStyle.Render("d")),
+				)
+			}
+			if state.BuildState == model.StateDownloading && state.Retry == 0 {
+				contextualCommands = append(contextualCommands,
+					fmt.Sprintf("%s Pause", keyStyle.Render("space")),
+				)
+			} else if state.BuildState == model.StatePaused {
+				contextualCommands = append(contextualCommands,
+					fmt.Sprintf("%s Resume", keyStyle.Render("space")),
+				)
+			}
 			contextualCommands = append(contextualCommands,
 				fmt.Sprintf("%s Cancel", keyStyle.Render("x")),
 			)
 		}
 	}
 
-	line1 := strings.Join(contextualCommands, separator)
-	line2 := strings.Join(generalCommands, separator)
+	// With builds marked, d downloads all of them instead of acting on the highlighted build
+	if marked := len(m.List.Marked); marked > 0 {
+		filtered := []string{
+			fmt.Sprintf("%s Download %d marked", keyStyle.Render("d"), marked),
+			fmt.Sprintf("%s Mark", keyStyle.Render("m")),
+			fmt.Sprintf("%s Clear marks", keyStyle.Render("esc")),
+		}
+		for _, cmd := range contextualCommands {
+			if !strings.HasPrefix(cmd, keyStyle.Render("d")+" ") && !strings.HasPrefix(cmd, keyStyle.Render("m")+" ") {
+				filtered = append(filtered, cmd)
+			}
+		}
+		contextualCommands = filtered
+	}
+
+	line1 := fitCommands(contextualCommands, separator, m.terminalWidth)
+	line2 := fitCommands(generalCommands, separator, m.terminalWidth)
+
+	// Without the details pane, a failed download tells how to recover here
+	if guidance != "" && !m.splitPane() {
+		line1 = m.Style.Notice.MaxWidth(max(m.terminalWidth, 1)).Render(guidance)
+	}
+
+	// The outcome of the last action replaces the commands of the build until the next key
+	if status := m.renderStatus(); status != "" {
+		line1 = status
+	}
+
+	// The schedule input takes over the footer while it is shown
+	if m.scheduling {
+		line1 = m.scheduleInput.View()
+		if m.scheduleErr != "" {
+			line1 += separator + m.scheduleErr
+		}
+		line2 = strings.Join([]string{
+			fmt.Sprintf("%s Schedule", keyStyle.Render("enter")),
+			fmt.Sprintf("%s Cancel", keyStyle.Render("esc")),
+		}, separator)
+	}
+
+	// So does the tag input
+	if m.tagPrompt != tagPromptNone {
+		line1 = m.tagInput.View()
+		action := "Save tags"
+		if m.tagPrompt == tagPromptFilter {
+			action = "Filter"
+		}
+		line2 = strings.Join([]string{
+			fmt.Sprintf("%s %s", keyStyle.Render("enter"), action),
+			fmt.Sprintf("%s Cancel", keyStyle.Render("esc")),
+		}, separator)
+	}
+
+	// And the import input
+	if m.importing {
+		line1 = m.importInput.View()
+		line2 = strings.Join([]string{
+			fmt.Sprintf("%s Import", keyStyle.Render("enter")),
+			fmt.Sprintf("%s Complete", keyStyle.Render("tab")),
+			fmt.Sprintf("%s Cancel", keyStyle.Render("esc")),
+		}, separator)
+	}
+
+	// And the export input
+	if m.exportBuild != nil {
+		line1 = m.exportInput.View()
+		line2 = strings.Join([]string{
+			fmt.Sprintf("%s Export %s", keyStyle.Render("enter"), m.exportBuild.Version),
+			fmt.Sprintf("%s Complete", keyStyle.Render("tab")),
+			fmt.Sprintf("%s Cancel", keyStyle.Render("esc")),
+		}, separator)
+	}
+
+	// And the render input
+	if m.renderBuild != nil {
+		line1 = m.renderInput.View()
+		line2 = strings.Join([]string{
+			fmt.Sprintf("%s Render with %s", keyStyle.Render("enter"), m.renderBuild.Version),
+			fmt.Sprintf("%s Complete", keyStyle.Render("tab")),
+			fmt.Sprintf("%s Cancel", keyStyle.Render("esc")),
+		}, separator)
+	}
+
+	// And the install roots to move to
+	if m.moveBuild != nil {
+		keys := "1"
+		if len(m.moveTargets) > 1 {
+			keys = fmt.Sprintf("1-%d", len(m.moveTargets))
+		}
+		line1 = m.renderMovePrompt()
+		line2 = strings.Join([]string{
+			fmt.Sprintf("%s Move", keyStyle.Render(keys)),
+			fmt.Sprintf("%s Cancel", keyStyle.Render("esc")),
+		}, separator)
+	}
+
+	// And the env profiles to launch with
+	if m.envLaunch != nil {
+		keys := "1"
+		if names := m.config.EnvProfileNames(); len(names) > 1 {
+			keys = fmt.Sprintf("1-%d", len(names))
+		}
+		line1 = m.renderEnvPrompt()
+		line2 = strings.Join([]string{
+			fmt.Sprintf("%s Launch", keyStyle.Render(keys)),
+			fmt.Sprintf("%s Cancel", keyStyle.Render("esc")),
+		}, separator)
+	}
+
+	// And the launch presets
+	if m.launchMenu != nil {
+		line1 = m.renderLaunchMenu()
+		keys := []string{
+			fmt.Sprintf("%s Launch", keyStyle.Render("enter")),
+			fmt.Sprintf("%s Preset", keyStyle.Render(fmt.Sprintf("1-%d", len(launch.Presets)))),
+		}
+		if m.config.GPUOffload != "" {
+			keys = append(keys, fmt.Sprintf("%s Switch GPU", keyStyle.Render("g")))
+		}
+		if canPickDisplay() {
+			keys = append(keys, fmt.Sprintf("%s X11/Wayland", keyStyle.Render("w")))
+		}
+		line2 = strings.Join(append(keys, fmt.Sprintf("%s Cancel", keyStyle.Render("esc"))), separator)
+	}
+
+	// And the offer to copy settings to a new series
+	if m.settingsOffer != nil {
+		line1 = fmt.Sprintf("Blender %s is the first of its series, copy the settings and add-ons of Blender %s?",
+			m.settingsOffer.version, m.settingsOffer.from)
+		line2 = strings.Join([]string{
+			fmt.Sprintf("%s Copy", keyStyle.Render("y")),
+			fmt.Sprintf("%s Start fresh", keyStyle.Render("n")),
+		}, separator)
+	}
+
+	// And the confirmation to kill a running Blender
+	if m.killBuild != nil {
+		line1 = m.renderKillPrompt()
+		line2 = strings.Join([]string{
+			fmt.Sprintf("%s Kill", keyStyle.Render("y")),
+			fmt.Sprintf("%s Keep running", keyStyle.Render("n")),
+		}, separator)
+	}
+
+	// And the question whether to delete what the trash couldn't take
+	if m.noTrash != nil {
+		line1, line2 = m.renderNoTrashPrompt()
+	}
 
 	// Combine lines with styled newline
 	footerContent := line1 + newlineStyle + line2
@@ -87,14 +327,12 @@ func (m *Model) renderSettingsFooter() string {
 	separator := sepStyle.Render(" · ")
 	newlineStyle := m.Style.Newline.Render("\n")
 
-	// Check if old builds exist to clean
-	oldBuildsDir := filepath.Join(m.config.DownloadDir, download.OldBuildsDir)
+	// Check if old builds exist to clean, in the .oldbuilds of any install root
 	showCleanOption := false
-
-	// Check if the directory exists and has contents
-	if _, err := os.Stat(oldBuildsDir); !os.IsNotExist(err) {
-		if entries, err := os.ReadDir(oldBuildsDir); err == nil && len(entries) > 0 {
+	for _, root := range m.config.Roots() {
+		if entries, err := os.ReadDir(filepath.Join(root, download.OldBuildsDir)); err == nil && len(entries) > 0 {
 			showCleanOption = true
+			break
 		}
 	}
 
@@ -113,6 +351,38 @@ func (m *Model) renderSettingsFooter() string {
 	line2 := strings.Join(commands, separator)
 
 	// Combine lines with styled newline
-	footerContent := newlineStyle + line2
+	footerContent := m.renderStatus() + newlineStyle + line2
+	return m.Style.Footer.Width(m.terminalWidth).Render(footerContent)
+}
+
+// renderJobsFooter renders the footer for the launch jobs view
+func (m *Model) renderJobsFooter() string {
+	keyStyle := m.Style.Key
+	sepStyle := m.Style.Separator
+	separator := sepStyle.Render(" · ")
+	newlineStyle := m.Style.Newline.Render("\n")
+
+	var contextual []string
+	if m.Jobs.EditMode {
+		contextual = append(contextual, fmt.Sprintf("%s Done editing", keyStyle.Render("enter")))
+	} else if job := m.Jobs.SelectedJob(); job != nil {
+		if job.Status == launch.JobPending || job.Status == launch.JobRunning {
+			contextual = append(contextual, fmt.Sprintf("%s Cancel job", keyStyle.Render("x")))
+		}
+	} else {
+		contextual = append(contextual, fmt.Sprintf("%s Edit", keyStyle.Render("enter")))
+	}
+
+	commands := []string{
+		fmt.Sprintf("%s Add job", keyStyle.Render("a")),
+		fmt.Sprintf("%s Back", keyStyle.Render("esc")),
+		fmt.Sprintf("%s Quit", keyStyle.Render("q")),
+	}
+
+	line1 := strings.Join(contextual, separator)
+	if status := m.renderStatus(); status != "" {
+		line1 = status
+	}
+	footerContent := line1 + newlineStyle + strings.Join(commands, separator)
 	return m.Style.Footer.Width(m.terminalWidth).Render(footerContent)
 }
@@ -1,16 +1,20 @@
 package tui
 
 import (
+	"TUI-Blender-Launcher/config"
 	"TUI-Blender-Launcher/download"
 	"TUI-Blender-Launcher/launch"
 	"TUI-Blender-Launcher/local"
 	"TUI-Blender-Launcher/model"
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
+	"slices"
 	"strings"
 	"time"
 
+	"github.com/atotto/clipboard"
 	"github.com/charmbracelet/bubbles/key"
 	tea "github.com/charmbracelet/bubbletea"
 )
@@ -20,19 +24,33 @@ func MatchKey(msg tea.KeyMsg, cmdType CommandType) bool {
 	return key.Matches(msg, GetKeyBinding(cmdType))
 }
 
-// Helper functions for handling specific actions in list view
-func (m *Model) handleLaunchBlender() (tea.Model, tea.Cmd) {
-	selectedBuild := m.List.GetSelectedBuild()
-	if selectedBuild == nil {
-		return m, nil
-	}
-
+// launchBuild returns a command launching an installed or system build as
+// request says, with its version and executable filled in.
+func (m *Model) launchBuild(build model.BlenderBuild, request model.BlenderExecMsg) tea.Cmd {
 	// Only attempt to launch if it's a local build or has an update available
-	if selectedBuild.Status == model.StateLocal || selectedBuild.Status == model.StateUpdate {
-		cmd := local.LaunchBlenderCmd(m.config.DownloadDir, selectedBuild.Version)
-		return m, cmd
+	if build.Status == model.StateLocal || build.Status == model.StateUpdate {
+		buildID := downloadID(build)
+		find := local.LaunchBlenderCmd(m.config.Roots(), build.Version)
+		return func() tea.Msg {
+			msg := find()
+			if err, failed := msg.(error); failed {
+				return launchFailedMsg{buildID: buildID, err: err}
+			}
+			if execMsg, ok := msg.(model.BlenderExecMsg); ok {
+				request.Version, request.Executable = execMsg.Version, execMsg.Executable
+				request.Sandboxed = build.HasTag(config.SandboxTag)
+				return request
+			}
+			return msg
+		}
 	}
-	return m, nil
+	// System installs have no build directory to look the executable up in
+	if build.Status == model.StateSystem {
+		request.Version, request.Executable = build.Version, build.Executable
+		request.Sandboxed = build.HasTag(config.SandboxTag)
+		return func() tea.Msg { return request }
+	}
+	return nil
 }
 
 // handleOpenBuildDir opens the build directory for a specific version
@@ -45,52 +63,54 @@ func (m *Model) handleOpenBuildDir() (tea.Model, tea.Cmd) {
 	// Only open dir if it's a local build or has an update available
 	if selectedBuild.Status == model.StateLocal || selectedBuild.Status == model.StateUpdate {
 		// Create a command that locates the correct build directory by version
+		roots, version := m.config.Roots(), selectedBuild.Version
 		return m, func() tea.Msg {
-			entries, err := os.ReadDir(m.config.DownloadDir)
+			dirPath, err := local.FindBuildDir(roots, version)
 			if err != nil {
-				return errMsg{fmt.Errorf("failed to read download directory %s: %w", m.config.DownloadDir, err)}
+				return errMsg{fmt.Errorf("build directory for Blender version %s not found", version)}
 			}
-
-			version := selectedBuild.Version
-			for _, entry := range entries {
-				if entry.IsDir() && entry.Name() != download.DownloadingDir && entry.Name() != download.OldBuildsDir {
-					dirPath := filepath.Join(m.config.DownloadDir, entry.Name())
-					buildInfo, err := local.ReadBuildInfo(dirPath)
-					if err != nil {
-						// Error reading build info, but continue checking other directories
-						continue
-					}
-
-					// Check if this is the build we want to open
-					if buildInfo != nil && buildInfo.Version == version {
-						// Open this directory
-						if err := local.OpenFileExplorer(dirPath); err != nil {
-							return errMsg{fmt.Errorf("failed to open directory: %w", err)}
-						}
-						return nil // Success
-					}
-				}
+			if err := local.OpenFileExplorer(dirPath); err != nil {
+				return errMsg{fmt.Errorf("failed to open directory: %w", err)}
 			}
-
-			return errMsg{fmt.Errorf("build directory for Blender version %s not found", version)}
+			return nil // Success
 		}
 	}
+	if selectedBuild.Status == model.StateSystem {
+		return m, local.OpenDirCmd(filepath.Dir(selectedBuild.Executable))
+	}
 	return m, nil
 }
 
-// handleStartDownload initiates a download for the selected build (from key press)
+// handleStartDownload initiates a download for the selected build (from key
+// press), or for all marked builds if any are marked
 func (m *Model) handleStartDownload() (tea.Model, tea.Cmd) {
+	if len(m.List.Marked) > 0 {
+		return m.handleStartMarkedDownloads()
+	}
+
 	selectedBuild := m.List.GetSelectedBuild()
 	if selectedBuild == nil {
 		return m, nil
 	}
 
+	// A download waiting to retry is retried right away
+	if m.commands.downloads.RetryNow(downloadID(*selectedBuild)) {
+		return m, nil
+	}
+
 	// Allow downloading Online, Update, Failed, and Cancelled builds
 	if selectedBuild.Status == model.StateOnline ||
 		selectedBuild.Status == model.StateUpdate ||
 		selectedBuild.Status == model.StateFailed ||
-		selectedBuild.Status == model.StateCancelled { // StateNone == Cancelled
-
+		selectedBuild.Status == model.StateCancelled ||
+		selectedBuild.Status == model.StateScheduled ||
+		selectedBuild.Status == model.StateInterrupted ||
+		selectedBuild.Status == model.StatePrefetched { // StateNone == Cancelled
+
+		if err := download.CheckFreeSpace(*selectedBuild, m.config.DownloadDir); err != nil {
+			m.err = err
+			return m, nil
+		}
 		return m, func() tea.Msg {
 			return startDownloadMsg{build: *selectedBuild}
 		}
@@ -98,28 +118,93 @@ func (m *Model) handleStartDownload() (tea.Model, tea.Cmd) {
 	return m, nil
 }
 
+// handleStartMarkedDownloads starts downloads for all marked builds that can
+// be downloaded. They share the download slots like any other downloads, the
+// ones beyond the limit wait in the queue. Marks are cleared once started.
+func (m *Model) handleStartMarkedDownloads() (tea.Model, tea.Cmd) {
+	var builds []model.BlenderBuild
+	for _, build := range m.List.Builds {
+		if !m.List.Marked[downloadID(build)] {
+			continue
+		}
+		switch build.Status {
+		case model.StateOnline, model.StateUpdate, model.StateFailed, model.StateCancelled,
+			model.StateScheduled, model.StatePrefetched, model.StateInterrupted:
+			builds = append(builds, build)
+		}
+	}
+	if len(builds) == 0 {
+		m.List.ClearMarks()
+		return m, nil
+	}
+
+	if err := download.CheckFreeSpaceAll(builds, m.config.DownloadDir); err != nil {
+		m.err = err
+		return m, nil
+	}
+	m.List.ClearMarks()
+
+	cmds := make([]tea.Cmd, 0, len(builds))
+	for _, build := range builds {
+		cmds = append(cmds, func() tea.Msg {
+			return startDownloadMsg{build: build}
+		})
+	}
+	return m, tea.Batch(cmds...)
+}
+
 // handleStartDownloadMsg handles the actual start message
 func (m *Model) handleStartDownloadMsg(msg startDownloadMsg) (tea.Model, tea.Cmd) {
-	m.Progress.ActiveDownloadID = msg.buildID
+	// Updates and reinstalls replace the installed build once downloaded
+	if msg.reinstall || msg.build.Status == model.StateUpdate {
+		if err := m.checkNotRunning(msg.build.Version, "updating it"); err != nil {
+			m.err = err
+			return m, nil
+		}
+	}
 
-	// Update the build status immediately to show downloading
+	// Update the build status immediately to show downloading (or waiting for a slot)
+	status := model.StateDownloading
+	if !m.commands.downloads.HasFreeSlot() {
+		status = model.StateQueued
+	}
+	buildID := downloadID(msg.build)
 	for i := range m.List.Builds {
-		if m.List.Builds[i].Version == msg.build.Version {
-			m.List.Builds[i].Status = model.StateDownloading
+		if downloadID(m.List.Builds[i]) == buildID {
+			m.List.Builds[i].Status = status
 			break
 		}
 	}
 
-	var cmds []tea.Cmd
-	// Create a Commands instance and call DoDownload directly
-	cmds = append(cmds, m.commands.DoDownload(msg.build))
-
-	// Make sure the ticker is running with a faster initial tick for responsiveness
-	cmds = append(cmds, tea.Tick(time.Millisecond*10, func(t time.Time) tea.Msg {
-		return tickMsg(t)
-	}))
+	// Update wakes the ticker right away, so progress shows up immediately
+	if msg.reinstall {
+		return m, m.commands.DoReinstall(msg.build, msg.corrupted)
+	}
+	return m, m.commands.DoDownload(msg.build)
+}
 
-	return m, tea.Batch(cmds...)
+// handleReinstallBuild downloads the selected installed build again, e.g. after
+// its files were modified. The current install is backed up to .oldbuilds once
+// the new one is complete, and removed from there once a corrupted one's
+// replacement verifies.
+func (m *Model) handleReinstallBuild() (tea.Model, tea.Cmd) {
+	selectedBuild := m.List.GetSelectedBuild()
+	if selectedBuild == nil || selectedBuild.Status != model.StateLocal {
+		return m, nil
+	}
+	if selectedBuild.DownloadURL == "" {
+		m.err = fmt.Errorf("%s: no download address recorded, can't reinstall", selectedBuild.Version)
+		return m, nil
+	}
+	if err := download.CheckFreeSpace(*selectedBuild, m.config.DownloadDir); err != nil {
+		m.err = err
+		return m, nil
+	}
+	build := *selectedBuild
+	corrupted := m.verifyResults[downloadID(build)] == verifyCorrupted
+	return m, func() tea.Msg {
+		return startDownloadMsg{build: build, reinstall: true, corrupted: corrupted}
+	}
 }
 
 // handleCancelDownload cancels an active download
@@ -129,40 +214,34 @@ func (m *Model) handleCancelDownload() (tea.Model, tea.Cmd) {
 		return m, nil
 	}
 
-	selectedBuildID := selectedBuild.Version
-	if selectedBuild.Hash != "" {
-		selectedBuildID = selectedBuild.Version + "-" + selectedBuild.Hash[:8]
-	}
+	// Cancel the download using the download manager, other downloads keep running
+	m.commands.downloads.CancelDownload(downloadID(*selectedBuild))
 
-	// Use activeDownloadID if set; otherwise, use the selected build ID
-	buildID := m.Progress.ActiveDownloadID
-	if buildID == "" {
-		buildID = selectedBuildID
+	// Only update if it's in a downloading, extracting or queued state
+	if selectedBuild.Status == model.StateDownloading ||
+		selectedBuild.Status == model.StateExtracting ||
+		selectedBuild.Status == model.StateQueued ||
+		selectedBuild.Status == model.StatePaused ||
+		selectedBuild.Status == model.StateScheduled {
+		selectedBuild.Status = model.StateCancelled // Set to Cancelled
 	}
 
-	// Cancel the download using the download manager
-	m.commands.downloads.CancelDownload(buildID)
+	return m, nil
+}
 
-	// Update the build status to Cancelled (StateNone) after cancellation
-	for i, build := range m.List.Builds {
-		bID := build.Version
-		if build.Hash != "" {
-			bID = build.Version + "-" + build.Hash[:8]
-		}
+// handlePauseDownload pauses the selected download or resumes it when paused
+func (m *Model) handlePauseDownload() (tea.Model, tea.Cmd) {
+	selectedBuild := m.List.GetSelectedBuild()
+	if selectedBuild == nil {
+		return m, nil
+	}
 
-		// Update the status of both the selected build and any build matching the active download
-		if bID == m.Progress.ActiveDownloadID || bID == selectedBuildID {
-			// Only update if it's in a downloading or extracting state
-			if m.List.Builds[i].Status == model.StateDownloading ||
-				m.List.Builds[i].Status == model.StateExtracting {
-				m.List.Builds[i].Status = model.StateCancelled // Set to Cancelled
-			}
+	buildID := downloadID(*selectedBuild)
+	if m.commands.downloads.TogglePause(buildID) {
+		if state := m.commands.downloads.GetState(buildID); state != nil {
+			selectedBuild.Status = state.BuildState
 		}
 	}
-
-	// Clear the active download ID
-	m.Progress.ActiveDownloadID = ""
-
 	return m, nil
 }
 
@@ -173,27 +252,55 @@ func (m *Model) handleDeleteBuild() (tea.Model, tea.Cmd) {
 		return m, nil
 	}
 
-	if selectedBuild.Status == model.StateDownloading || selectedBuild.Status == model.StateExtracting {
+	if selectedBuild.Status == model.StateDownloading ||
+		selectedBuild.Status == model.StateExtracting ||
+		selectedBuild.Status == model.StateQueued ||
+		selectedBuild.Status == model.StatePaused ||
+		selectedBuild.Status == model.StateScheduled {
 		return m.handleCancelDownload()
 	}
+	// Deleting a prefetched build just discards its archive
+	if selectedBuild.Status == model.StatePrefetched {
+		return m.handleDiscardPrefetch()
+	}
+	if selectedBuild.Status == model.StateInterrupted {
+		return m.handleDiscardInterrupted()
+	}
+	// A build being exported or moved stops that instead
+	if m.commands.transfers.Cancel(downloadID(*selectedBuild)) {
+		return m, nil
+	}
 	// Only allow deleting local builds or builds that can be updated
 	if selectedBuild.Status == model.StateLocal || selectedBuild.Status == model.StateUpdate {
-		return m, func() tea.Msg {
-			success, err := local.DeleteBuild(m.config.DownloadDir, selectedBuild.Version)
-			if err != nil {
-				return errMsg{err}
-			}
-			if !success {
-				return errMsg{fmt.Errorf("failed to delete build %s", selectedBuild.Version)}
-			}
-			// Remove the deleted build from the list
-			// ... (requires manipulating ListModel, maybe better to just rescan)
-			return m.commands.ScanLocalBuilds()()
+		if err := m.checkNotRunning(selectedBuild.Version, "deleting it"); err != nil {
+			m.err = err
+			return m, nil
 		}
+		return m, m.deleteBuild(selectedBuild.Version, m.config.DeleteToTrash)
 	}
 	return m, nil
 }
 
+// deleteBuild deletes the build of version and rescans, offering to delete
+// it for good if the trash can't take it.
+func (m *Model) deleteBuild(version string, toTrash bool) tea.Cmd {
+	return func() tea.Msg {
+		success, err := local.DeleteBuild(m.config.Roots(), version, toTrash)
+		if errors.Is(err, local.ErrNoTrash) {
+			return noTrashMsg{what: "Blender " + version, err: err, remove: m.deleteBuild(version, false)}
+		}
+		if err != nil {
+			return errMsg{err}
+		}
+		if !success {
+			return errMsg{fmt.Errorf("failed to delete build %s", version)}
+		}
+		// Remove the deleted build from the list
+		// ... (requires manipulating ListModel, maybe better to just rescan)
+		return m.commands.ScanLocalBuilds()()
+	}
+}
+
 // handleLocalBuildsScanned processes the result of scanning local builds
 func (m *Model) handleLocalBuildsScanned(msg localBuildsScannedMsg) (tea.Model, tea.Cmd) {
 	// If there was an error scanning builds, store it but continue with empty list
@@ -203,13 +310,25 @@ func (m *Model) handleLocalBuildsScanned(msg localBuildsScannedMsg) (tea.Model,
 		return m, nil
 	}
 
-	// Set builds to local builds only
-	m.List.Builds = msg.builds
+	// Set builds to local builds and the downloads left unfinished last time
+	m.List.Builds = m.withSystemBuilds(msg.builds)
+	m.loadDefaultBuild()
+	for _, interrupted := range msg.interrupted {
+		build := interrupted.Build
+		if download.IsArchiveComplete(build, m.config.DownloadDir) {
+			build.Status = model.StatePrefetched
+		} else {
+			build.Status = model.StateInterrupted
+			m.commands.downloads.RestoreInterrupted(build, interrupted.Downloaded)
+		}
+		m.List.Builds = append(m.List.Builds, build)
+	}
 
 	// Apply version filter if set
 	if m.config.VersionFilter != "" {
 		m.List.Builds = m.applyVersionFilter(m.List.Builds)
 	}
+	m.List.Builds = m.applyTagFilter(m.List.Builds)
 
 	// Sort builds immediately
 	m.List.SortBuilds()
@@ -220,25 +339,64 @@ func (m *Model) handleLocalBuildsScanned(msg localBuildsScannedMsg) (tea.Model,
 		m.List.StartIndex = 0
 	}
 
-	return m, nil
+	cmds := []tea.Cmd{checkDiskSpace(m.config.DownloadDir)}
+
+	// Measuring the library walks the whole download directory, so it runs in the background
+	if m.config.Insights {
+		downloadDir, builds := m.config.DownloadDir, len(msg.builds)
+		cmds = append(cmds, func() tea.Msg {
+			_ = local.RecordLibraryInsight(downloadDir, builds)
+			return nil
+		})
+	}
+
+	return m, tea.Batch(cmds...)
+}
+
+// handleLocalBuildsFound lists the builds a running scan found so far. The
+// finished scan replaces them with the full list.
+func (m *Model) handleLocalBuildsFound(msg localBuildsFoundMsg) (tea.Model, tea.Cmd) {
+	listed := make(map[string]bool, len(m.List.Builds))
+	for _, build := range m.List.Builds {
+		listed[downloadID(build)] = true
+	}
+	for _, build := range msg.builds {
+		if !listed[downloadID(build)] {
+			listed[downloadID(build)] = true
+			m.List.Builds = append(m.List.Builds, build)
+		}
+	}
+	if m.config.VersionFilter != "" {
+		m.List.Builds = m.applyVersionFilter(m.List.Builds)
+	}
+	m.List.Builds = m.applyTagFilter(m.List.Builds)
+	m.List.SortBuilds()
+	return m, msg.next
 }
 
 // handleBuildsFetched processes the result of fetching builds from the API
 func (m *Model) handleBuildsFetched(msg buildsFetchedMsg) (tea.Model, tea.Cmd) {
 	if msg.err != nil {
+		// Check right away whether the builder or the network is at fault
 		m.err = msg.err
-		return m, nil
+		return m, m.checkHealth(false)
 	}
 
-	// Preserve only local builds from the current list.
+	// Preserve only local builds from the current list, and unfinished downloads
+	// of builds that are no longer online.
+	fetched := make(map[string]bool, len(msg.builds))
+	for _, build := range msg.builds {
+		fetched[downloadID(build)] = true
+	}
 	var localBuilds []model.BlenderBuild
 	for _, build := range m.List.Builds {
-		if build.Status == model.StateLocal {
+		if build.Status == model.StateLocal || (build.Status == model.StateInterrupted && !fetched[downloadID(build)]) {
 			localBuilds = append(localBuilds, build)
 		}
 	}
 
 	// Start with local builds + newly fetched builds.
+	m.onlineBuilds = msg.builds
 	m.List.Builds = localBuilds
 	m.List.Builds = append(m.List.Builds, msg.builds...)
 
@@ -250,9 +408,10 @@ func (m *Model) handleBuildsFetched(msg buildsFetchedMsg) (tea.Model, tea.Cmd) {
 	// Reset cursor and startIndex
 	m.List.Cursor = 0
 	m.List.StartIndex = 0
+	m.selectNewestPending = m.config.SelectNewest
 
 	// Update the status based on what's available locally vs online.
-	return m, m.commands.UpdateBuildStatus(m.List.Builds)
+	return m, tea.Batch(m.commands.UpdateBuildStatus(m.List.Builds), checkStableRelease(msg.builds))
 }
 
 // applyVersionFilter filters builds by version
@@ -263,8 +422,8 @@ func (m *Model) applyVersionFilter(builds []model.BlenderBuild) []model.BlenderB
 
 	filtered := make([]model.BlenderBuild, 0)
 	for _, build := range builds {
-		// Always keep local builds regardless of version filter
-		if build.Status == model.StateLocal {
+		// Always keep installed builds regardless of version filter
+		if build.Status == model.StateLocal || build.Status == model.StateSystem {
 			filtered = append(filtered, build)
 			continue
 		}
@@ -280,7 +439,14 @@ func (m *Model) applyVersionFilter(builds []model.BlenderBuild) []model.BlenderB
 // handleBuildsUpdated finalizes the build list after determining local/online status
 func (m *Model) handleBuildsUpdated(msg buildsUpdatedMsg) (tea.Model, tea.Cmd) {
 	// Replace builds with updated ones that have correct status
-	m.List.Builds = msg.builds
+	m.List.Builds = m.withSystemBuilds(msg.builds)
+
+	// Show how far unfinished downloads got
+	for _, build := range m.List.Builds {
+		if build.Status == model.StateInterrupted {
+			m.commands.downloads.RestoreInterrupted(build, download.PartialSize(build, m.config.DownloadDir))
+		}
+	}
 
 	// Sync logic... (skipped simple sync logic for brevity as it's handled in ProgressModel essentially)
 	// But we need to cleanup downloadStates
@@ -289,24 +455,155 @@ func (m *Model) handleBuildsUpdated(msg buildsUpdatedMsg) (tea.Model, tea.Cmd) {
 	if m.config.VersionFilter != "" {
 		m.List.Builds = m.applyVersionFilter(m.List.Builds)
 	}
+	m.List.Builds = m.applyTagFilter(m.List.Builds)
 
 	m.List.SortBuilds()
+
+	// Jump to today's build once the fetched list has its final order
+	if m.selectNewestPending {
+		m.selectNewestPending = false
+		m.List.SelectNewest()
+	}
+	m.restoreSelection()
 	m.List.EnsureCursorVisible()
 
 	return m, nil
 }
 
-// handleBlenderExec handles launching Blender
+// handleBlenderExec handles launching Blender, in a new terminal or, with the
+// background launch mode, without one
 func (m *Model) handleBlenderExec(msg model.BlenderExecMsg) (tea.Model, tea.Cmd) {
 	execInfo := msg
+	insights := m.config.Insights
+	workDir := m.config.WorkDirFor(execInfo.File)
+	roots := m.config.Roots()
+	background, logDir := m.config.LaunchMode == config.LaunchModeBackground, m.commands.logDir
+	tmux := m.config.LaunchMode == config.LaunchModeTmux && launch.InTmux()
+	opts := launch.Options{
+		WorkDir:    workDir,
+		Args:       slices.Clone(execInfo.Args),
+		Template:   m.config.LaunchTemplate,
+		Language:   m.config.Language,
+		Display:    execInfo.Display,
+		PythonPath: m.config.PythonPathFor(execInfo.Version),
+	}
+	if execInfo.File != "" {
+		opts.Args = append(opts.Args, execInfo.File)
+	}
+	if !execInfo.Integrated {
+		opts.Offload = m.config.GPUOffload
+	}
+	if background || m.config.LaunchLogs {
+		opts.LogPath = launch.LogPath(logDir, execInfo.Version, time.Now())
+	}
+	profile := execInfo.EnvProfile
+	if profile == "" {
+		profile = m.config.EnvProfileFor(execInfo.File)
+	}
+	env, ok := m.config.EnvProfiles[profile]
+	if profile != "" && !ok {
+		m.err = fmt.Errorf("can't launch Blender %s: env_profile %q is not in env_profiles", execInfo.Version, profile)
+		return m, nil
+	}
+	opts.Env = env
+	if execInfo.Sandboxed {
+		var err error
+		if opts, err = m.sandbox(opts, execInfo.Version, execInfo.File); err != nil {
+			m.err = err
+			return m, nil
+		}
+	}
 	return m, func() tea.Msg {
 		blenderExe := execInfo.Executable
-		err := launch.BlenderInNewTerminal(blenderExe)
+		var process *launch.Detached
+		var err error
+		if background {
+			process, err = launch.StartDetached(blenderExe, opts)
+		} else if tmux {
+			err = launch.BlenderInTmux(blenderExe, "blender-"+execInfo.Version, opts)
+		} else {
+			err = launch.BlenderInNewTerminal(blenderExe, opts)
+		}
 		if err != nil {
 			return errMsg{fmt.Errorf("failed to launch Blender: %w", err)}
 		}
-		return nil
+		if opts.LogPath != "" {
+			_ = launch.RotateLogs(logDir, launch.LaunchLogsKept)
+		}
+		// Usage only feeds the prefetch heuristic, so failing to record it is not fatal
+		_ = local.RecordLaunch(execInfo.Version)
+		if insights {
+			_ = local.RecordLaunchInsight(execInfo.Version)
+		}
+		// Neither is the launch history, and system installs have no version.json for it
+		var launched *buildLaunchedMsg
+		now := time.Now()
+		if err := local.RecordBuildLaunch(roots, execInfo.Version, now); err == nil {
+			launched = &buildLaunchedMsg{version: execInfo.Version, at: now}
+		}
+		if process != nil {
+			return blenderStartedMsg{version: execInfo.Version, process: process, launched: launched}
+		}
+		if launched == nil {
+			return nil
+		}
+		return *launched
+	}
+}
+
+// jobOptions returns how a launch queue job running version on file is
+// started: like a launch, with the env profile, Python path and wrappers, in
+// the sandbox if the build is tagged for it.
+func (m *Model) jobOptions(version string, sandboxed bool, file string) (launch.Options, error) {
+	opts := launch.Options{
+		WorkDir:    m.config.WorkDirFor(file),
+		Env:        m.config.EnvProfiles[m.config.EnvProfileFor(file)],
+		Offload:    m.config.GPUOffload,
+		Language:   m.config.Language,
+		PythonPath: m.config.PythonPathFor(version),
+		Template:   m.config.LaunchTemplate,
+	}
+	if sandboxed {
+		return m.sandbox(opts, version, file)
+	}
+	return opts, nil
+}
+
+// sandbox returns opts confining Blender version to the project of file with
+// the sandbox command, or an error if none is set.
+func (m *Model) sandbox(opts launch.Options, version, file string) (launch.Options, error) {
+	if m.config.Sandbox == "" {
+		return opts, fmt.Errorf("%w: set sandbox in config.toml to launch builds tagged %q", launch.ErrNoSandbox, config.SandboxTag)
+	}
+	opts.Sandbox, opts.Project = m.config.Sandbox, m.config.ProjectDirFor(file)
+	// System installs have no build directory, the sandbox gets the executable's
+	if dir, err := local.FindBuildDir(m.config.Roots(), version); err == nil {
+		opts.BuildDir = dir
+	}
+	return opts, nil
+}
+
+// handleLaunchFailed tells why a build didn't launch, flagging it as corrupted
+// when its executable is gone.
+func (m *Model) handleLaunchFailed(msg launchFailedMsg) (tea.Model, tea.Cmd) {
+	if errors.Is(msg.err, local.ErrNoExecutable) {
+		m.verifyResults[msg.buildID] = verifyCorrupted
+	}
+	m.err = fmt.Errorf("failed to launch Blender: %w", msg.err)
+	return m, nil
+}
+
+// handleBuildLaunched shows a launch in the build's row without a rescan.
+func (m *Model) handleBuildLaunched(msg buildLaunchedMsg) (tea.Model, tea.Cmd) {
+	for i := range m.List.Builds {
+		build := &m.List.Builds[i]
+		if build.Version == msg.version && (build.Status == model.StateLocal || build.Status == model.StateUpdate) {
+			at := msg.at
+			build.LastLaunched = &at
+			build.LaunchCount++
+		}
 	}
+	return m, nil
 }
 
 // SaveSettingsAndReturn saves settings and returns to list view
@@ -316,8 +613,12 @@ func (m *Model) SaveSettingsAndReturn() (tea.Model, tea.Cmd) {
 		return m, nil
 	}
 
-	// Recreate commands with updated config
+	// Recreate commands with updated config, keeping the running downloads,
+	// transfers and launch queue
+	downloads, transfers, jobs := m.commands.downloads, m.commands.transfers, m.commands.jobs
 	m.commands = NewCommands(m.config)
+	downloads.SetConfig(m.config)
+	m.commands.downloads, m.commands.transfers, m.commands.jobs = downloads, transfers, jobs
 	m.err = nil
 
 	// Refresh list
@@ -329,14 +630,19 @@ func (m *Model) handleDownloadCompleteMsg(msg downloadCompleteMsg) (tea.Model, t
 	for i := range m.List.Builds {
 		// Find the build by version and update its status
 		if m.List.Builds[i].Version == msg.buildVersion {
-			if msg.err != nil {
-				// Handle download error
+			if errors.Is(msg.err, download.ErrCancelled) {
+				// Cancelled downloads keep their partial file and can be resumed
+				m.List.Builds[i].Status = model.StateCancelled
+			} else if msg.err != nil {
+				// Handle download error, telling the user what to do about it
 				m.List.Builds[i].Status = model.StateFailed
-				m.err = msg.err
+				m.err = fmt.Errorf("%s: %w. %s", msg.buildVersion, msg.err, failureGuidance(msg.err))
 			} else {
-				// Update to local state on success
+				// Update to local state on success, a fresh install is no longer corrupted
 				m.List.Builds[i].Status = model.StateLocal
+				delete(m.verifyResults, downloadID(m.List.Builds[i]))
 				m.err = nil
+				m.offerPreviousSettings(msg.buildVersion)
 			}
 			break
 		}
@@ -345,36 +651,87 @@ func (m *Model) handleDownloadCompleteMsg(msg downloadCompleteMsg) (tea.Model, t
 	// Re-sort the builds
 	m.List.SortBuilds()
 
+	// Old builds go once the last download is done, so none is replaced meanwhile
+	var retention tea.Cmd
+	if msg.err == nil && !m.hasActiveDownloads() {
+		retention = m.enforceRetention()
+	}
+
+	// Start listening for more program messages
+	return m, tea.Batch(m.commands.ProgramMsgListener(), checkDiskSpace(m.config.DownloadDir), retention)
+}
+
+// handlePostInstallDoneMsg reports add-ons or a post-install hook that failed
+// for a newly installed build, and add-ons still to be enabled.
+func (m *Model) handlePostInstallDoneMsg(msg postInstallDoneMsg) (tea.Model, tea.Cmd) {
+	if msg.err != nil {
+		m.err = fmt.Errorf("%s is installed, but %w", msg.buildVersion, msg.err)
+	}
+	// While copying earlier settings is offered, the answer decides
+	if m.settingsOffer == nil || model.VersionSeries(m.settingsOffer.version) != model.VersionSeries(msg.buildVersion) {
+		m.suggestEnablingAddons(msg.buildVersion)
+	}
+
 	// Start listening for more program messages
 	return m, m.commands.ProgramMsgListener()
 }
 
 func (m *Model) handleTickMsg(msg tickMsg) (tea.Model, tea.Cmd) {
+	// Drop ticks superseded by a wake-up, so only one tick loop runs
+	if !m.ticking || time.Time(msg).Before(m.tickDue) {
+		return m, nil
+	}
+
 	// Sync download states
 	m.SyncDownloadStates()
 
 	// Logic for finding next tick time
 	activeDownloads := 0
+	waitingDownloads := 0
 	for _, state := range m.Progress.DownloadStates {
-		if state.BuildState == model.StateDownloading || state.BuildState == model.StateExtracting {
+		switch state.BuildState {
+		case model.StateDownloading, model.StateExtracting:
 			activeDownloads++
+		case model.StateQueued, model.StatePaused:
+			waitingDownloads++
 		}
 	}
-
-	var nextTickTime time.Duration = time.Millisecond * 500
-	if activeDownloads > 0 {
-		nextTickTime = time.Millisecond * 250
+	// Exports and moves show their progress like downloads
+	activeDownloads += len(m.transfers)
+
+	// Refresh the launch job snapshot shown in the jobs view
+	m.Jobs.Jobs = m.commands.jobs.Jobs()
+	runningJobs := 0
+	for _, job := range m.Jobs.Jobs {
+		if job.Status == launch.JobPending || job.Status == launch.JobRunning {
+			runningJobs++
+		}
 	}
 
-	cmd := tea.Tick(nextTickTime, func(t time.Time) tea.Msg {
-		return tickMsg(t)
-	})
-
 	// Also perform the logic of handleDownloadProgress to update statuses in the List
 	// We can extract that to a helper
 	m.updateBuildsStatusFromProgress()
 
-	return m, cmd
+	if activeDownloads == 0 && waitingDownloads == 0 {
+		m.maybeStartPrefetch()
+	}
+
+	switch {
+	case m.currentView == viewConsole && m.consoleRunning():
+		// Follow the output of the running Blender
+		return m, tea.Batch(readConsole(m.console.logPath), m.scheduleTick(activeTickInterval))
+	case activeDownloads > 0:
+		return m, m.scheduleTick(activeTickInterval)
+	case waitingDownloads > 0 || runningJobs > 0:
+		return m, m.scheduleTick(slowTickInterval)
+	case m.config.Prefetch && time.Since(m.lastInput) < prefetchIdleDelay:
+		// Come back once the user has been idle long enough to prefetch
+		return m, m.scheduleTick(prefetchIdleDelay - time.Since(m.lastInput))
+	}
+
+	// Nothing to refresh, sleep until the next message
+	m.ticking = false
+	return m, nil
 }
 
 func (m *Model) updateBuildsStatusFromProgress() {
@@ -382,19 +739,382 @@ func (m *Model) updateBuildsStatusFromProgress() {
 	// updating m.List.Builds[i].Status based on m.Progress.DownloadStates
 
 	for i := range m.List.Builds {
-		buildID := m.List.Builds[i].Version
-		if m.List.Builds[i].Hash != "" {
-			buildID = m.List.Builds[i].Version + "-" + m.List.Builds[i].Hash[:8]
+		// Downloads never turn into system installs
+		if m.List.Builds[i].Status == model.StateSystem {
+			continue
 		}
+		buildID := downloadID(m.List.Builds[i])
 
 		if state, ok := m.Progress.DownloadStates[buildID]; ok {
-			if state.BuildState == model.StateDownloading || state.BuildState == model.StateExtracting {
+			if state.BuildState == model.StateDownloading ||
+				state.BuildState == model.StateExtracting ||
+				state.BuildState == model.StateQueued ||
+				state.BuildState == model.StatePaused ||
+				state.BuildState == model.StateScheduled {
 				m.List.Builds[i].Status = state.BuildState
 			} else if state.BuildState == model.StateLocal {
 				m.List.Builds[i].Status = model.StateLocal
-			} else if strings.HasPrefix(state.BuildState.String(), "Failed") {
+			} else if state.BuildState == model.StateFailed {
 				m.List.Builds[i].Status = model.StateFailed
 			}
 		}
 	}
 }
+
+// maybeStartPrefetch starts a background prefetch of the newest build in the series the
+// user launches most often, but only when prefetching is enabled, the user has been idle
+// for a while and the last fetch suggests a fast network.
+func (m *Model) maybeStartPrefetch() {
+	if !m.config.Prefetch || m.commands.downloads.IsPrefetching() {
+		return
+	}
+	if time.Since(m.lastInput) < prefetchIdleDelay {
+		return
+	}
+	if latency := m.commands.lastFetchDuration; latency == 0 || latency > prefetchMaxFetchLatency {
+		return
+	}
+
+	usage, err := local.LoadLaunchUsage()
+	if err != nil {
+		return
+	}
+	series := usage.MostLaunchedSeries()
+	if series == "" {
+		return
+	}
+
+	// Pick the newest build of that series that isn't installed or prefetched yet
+	var candidate *model.BlenderBuild
+	for i := range m.List.Builds {
+		build := &m.List.Builds[i]
+		if model.VersionSeries(build.Version) != series {
+			continue
+		}
+		if build.Status != model.StateOnline && build.Status != model.StateUpdate {
+			continue
+		}
+		if candidate == nil || build.BuildDate.Time().After(candidate.BuildDate.Time()) {
+			candidate = build
+		}
+	}
+	if candidate == nil || m.prefetchAttempted[candidate.DownloadURL] {
+		return
+	}
+
+	// Only try each archive once per session so a failing prefetch doesn't loop
+	m.prefetchAttempted[candidate.DownloadURL] = true
+	m.commands.downloads.StartPrefetch(*candidate)
+}
+
+// handlePrefetchCompleteMsg marks a build as prefetched once its archive is on disk
+func (m *Model) handlePrefetchCompleteMsg(msg prefetchCompleteMsg) (tea.Model, tea.Cmd) {
+	if msg.err == nil {
+		for i := range m.List.Builds {
+			build := &m.List.Builds[i]
+			if build.DownloadURL == msg.build.DownloadURL &&
+				(build.Status == model.StateOnline || build.Status == model.StateUpdate) {
+				build.Status = model.StatePrefetched
+			}
+		}
+	}
+
+	// Start listening for more program messages
+	return m, m.commands.ProgramMsgListener()
+}
+
+// handleDiscardPrefetch removes the prefetched archive of the selected build
+func (m *Model) handleDiscardPrefetch() (tea.Model, tea.Cmd) {
+	selectedBuild := m.List.GetSelectedBuild()
+	if selectedBuild == nil {
+		return m, nil
+	}
+
+	if err := os.Remove(download.ArchivePath(*selectedBuild, m.config.DownloadDir)); err != nil && !os.IsNotExist(err) {
+		m.err = fmt.Errorf("failed to discard prefetched archive: %w", err)
+		return m, nil
+	}
+
+	// Fall back to Update if an older copy of this version is installed
+	selectedBuild.Status = model.StateOnline
+	if lookup, err := local.BuildLocalLookupMap(m.config.Roots()); err == nil && lookup[selectedBuild.Version] {
+		selectedBuild.Status = model.StateUpdate
+	}
+	return m, nil
+}
+
+// handleDiscardInterrupted removes what an interrupted download of the
+// selected build left behind.
+func (m *Model) handleDiscardInterrupted() (tea.Model, tea.Cmd) {
+	selectedBuild := m.List.GetSelectedBuild()
+	if selectedBuild == nil {
+		return m, nil
+	}
+
+	if err := download.DiscardPartial(*selectedBuild, m.config.DownloadDir); err != nil {
+		m.err = err
+		return m, nil
+	}
+	m.commands.downloads.ForgetInterrupted(downloadID(*selectedBuild))
+
+	// Fall back to Update if an older copy of this version is installed
+	selectedBuild.Status = model.StateOnline
+	if lookup, err := local.BuildLocalLookupMap(m.config.Roots()); err == nil && lookup[selectedBuild.Version] {
+		selectedBuild.Status = model.StateUpdate
+	}
+	return m, nil
+}
+
+// handleShowJobs opens the launch jobs view for the selected local build
+func (m *Model) handleShowJobs() (tea.Model, tea.Cmd) {
+	if build := m.List.GetSelectedBuild(); build != nil &&
+		(build.Status == model.StateLocal || build.Status == model.StateUpdate) {
+		exe, err := local.FindBuildExecutable(m.config.Roots(), build.Version)
+		if err != nil {
+			m.err = err
+			return m, nil
+		}
+		m.Jobs.SetBuild(build.Version, exe, build.HasTag(config.SandboxTag))
+	}
+
+	m.Jobs.Jobs = m.commands.jobs.Jobs()
+	m.currentView = viewJobs
+	return m, nil
+}
+
+// handleVerifyBuild re-hashes the files of the selected installed build in the background
+func (m *Model) handleVerifyBuild() (tea.Model, tea.Cmd) {
+	build := m.List.GetSelectedBuild()
+	if build == nil || (build.Status != model.StateLocal && build.Status != model.StateUpdate) {
+		return m, nil
+	}
+
+	buildID := downloadID(*build)
+	if result := m.verifyResults[buildID]; result == verifyRunning || result == repairRunning {
+		return m, nil
+	}
+	m.verifyResults[buildID] = verifyRunning
+
+	roots, version := m.config.Roots(), build.Version
+	return m, func() tea.Msg {
+		dir, err := local.FindBuildDir(roots, version)
+		if err == nil {
+			err = download.VerifyManifest(dir)
+		}
+		return verifyCompleteMsg{buildID: buildID, err: err}
+	}
+}
+
+// handleVerifyCompleteMsg shows the outcome of an integrity check in the status column
+func (m *Model) handleVerifyCompleteMsg(msg verifyCompleteMsg) (tea.Model, tea.Cmd) {
+	delete(m.verifyErrors, msg.buildID)
+	switch {
+	case msg.err == nil:
+		m.verifyResults[msg.buildID] = verifyPassed
+	case errors.Is(msg.err, download.ErrNoManifest):
+		m.verifyResults[msg.buildID] = verifyNoManifest
+	case errors.Is(msg.err, download.ErrVerificationFailed):
+		// The bad files stay listed in the details once the status line is cleared
+		m.verifyResults[msg.buildID] = verifyCorrupted
+		m.verifyErrors[msg.buildID] = msg.err
+		m.err = msg.err
+	default:
+		delete(m.verifyResults, msg.buildID)
+		m.err = fmt.Errorf("failed to verify build: %w", msg.err)
+	}
+	return m, nil
+}
+
+// handleRepairBuild extracts the selected installed build again from its kept
+// archive in the background, without downloading anything
+func (m *Model) handleRepairBuild() (tea.Model, tea.Cmd) {
+	build := m.List.GetSelectedBuild()
+	if build == nil || (build.Status != model.StateLocal && build.Status != model.StateUpdate) {
+		return m, nil
+	}
+
+	buildID := downloadID(*build)
+	if result := m.verifyResults[buildID]; result == verifyRunning || result == repairRunning {
+		return m, nil
+	}
+	if err := m.checkNotRunning(build.Version, "repairing it"); err != nil {
+		m.err = err
+		return m, nil
+	}
+	m.verifyResults[buildID] = repairRunning
+
+	cfg, version := m.config, build.Version
+	return m, func() tea.Msg {
+		// The installed metadata tells which archive the build came from. Repairs
+		// install to the download directory, so builds elsewhere are left alone.
+		dir, err := local.FindBuildDir([]string{cfg.DownloadDir}, version)
+		if err != nil {
+			return repairCompleteMsg{buildID: buildID, err: err}
+		}
+		installed, err := local.ReadBuildInfo(dir)
+		if err != nil || installed == nil {
+			return repairCompleteMsg{buildID: buildID, err: fmt.Errorf("%s: %w", version, download.ErrNoKeptArchive)}
+		}
+		_, err = download.RepairBuild(*installed, cfg, nil, make(chan struct{}))
+		return repairCompleteMsg{buildID: buildID, err: err}
+	}
+}
+
+// handleRepairCompleteMsg shows the outcome of a repair in the status column
+func (m *Model) handleRepairCompleteMsg(msg repairCompleteMsg) (tea.Model, tea.Cmd) {
+	if msg.err != nil {
+		m.verifyResults[msg.buildID] = repairFailed
+		m.err = fmt.Errorf("failed to repair build: %w", msg.err)
+		return m, nil
+	}
+	m.verifyResults[msg.buildID] = repairDone
+	return m, tea.Batch(m.commands.ScanLocalBuilds(), checkDiskSpace(m.config.DownloadDir))
+}
+
+// handleCheckBuild test runs the executable of the selected build in the background
+func (m *Model) handleCheckBuild() (tea.Model, tea.Cmd) {
+	build := m.List.GetSelectedBuild()
+	if build == nil || (build.Status != model.StateLocal && build.Status != model.StateUpdate && build.Status != model.StateSystem) {
+		return m, nil
+	}
+
+	buildID := downloadID(*build)
+	if result := m.verifyResults[buildID]; result == verifyRunning || result == repairRunning || result == checkRunning {
+		return m, nil
+	}
+
+	// The test run is confined like the launches it stands for
+	var opts launch.Options
+	if build.HasTag(config.SandboxTag) {
+		var err error
+		if opts, err = m.sandbox(opts, build.Version, ""); err != nil {
+			m.err = err
+			return m, nil
+		}
+	}
+	m.verifyResults[buildID] = checkRunning
+
+	roots, version, exe := m.config.Roots(), build.Version, build.Executable
+	return m, func() tea.Msg {
+		var err error
+		if exe == "" {
+			exe, err = local.FindBuildExecutable(roots, version)
+		}
+		if err == nil {
+			err = local.CheckBuildRuns(exe, opts)
+		}
+		return checkCompleteMsg{buildID: buildID, err: err}
+	}
+}
+
+// handleCheckCompleteMsg shows the outcome of a test run in the status column,
+// and why the build doesn't run if it failed in the footer and the details
+func (m *Model) handleCheckCompleteMsg(msg checkCompleteMsg) (tea.Model, tea.Cmd) {
+	delete(m.verifyErrors, msg.buildID)
+	switch {
+	case errors.Is(msg.err, local.ErrNoExecutable):
+		m.verifyResults[msg.buildID] = verifyCorrupted
+	case msg.err != nil:
+		m.verifyResults[msg.buildID] = checkFailed
+	default:
+		m.verifyResults[msg.buildID] = checkPassed
+		return m, nil
+	}
+	m.verifyErrors[msg.buildID] = msg.err
+	m.err = msg.err
+	return m, nil
+}
+
+// handlePasteBuild installs the build a URL or commit hash in the clipboard points to
+func (m *Model) handlePasteBuild() (tea.Model, tea.Cmd) {
+	text, err := clipboard.ReadAll()
+	if err != nil {
+		m.err = fmt.Errorf("failed to read clipboard: %w", err)
+		return m, nil
+	}
+	return m.installPasted(text)
+}
+
+// installPasted installs the build pasted text points to, telling in the
+// footer why if it can't.
+func (m *Model) installPasted(text string) (tea.Model, tea.Cmd) {
+	build, err := m.resolvePastedBuild(strings.TrimSpace(text))
+	if err != nil {
+		m.err = err
+		return m, nil
+	}
+	m.err = nil
+
+	// Show builds that aren't in the catalogue, then select the row being installed
+	index := -1
+	for i := range m.List.Builds {
+		if downloadID(m.List.Builds[i]) == downloadID(build) {
+			index = i
+			break
+		}
+	}
+	if index < 0 {
+		m.List.Builds = append(m.List.Builds, build)
+		index = len(m.List.Builds) - 1
+	}
+	m.List.Cursor = index
+	m.List.EnsureCursorVisible()
+
+	if m.List.Builds[index].Status == model.StateLocal {
+		m.notice = fmt.Sprintf("Blender %s (%s) is already installed", build.Version, build.Hash)
+		return m, nil
+	}
+	return m.handleStartDownload()
+}
+
+// resolvePastedBuild finds the build a pasted URL or hash refers to. Catalogue
+// entries are preferred, direct URLs to unknown builds are installed as is.
+func (m *Model) resolvePastedBuild(text string) (model.BlenderBuild, error) {
+	if text == "" {
+		return model.BlenderBuild{}, fmt.Errorf("clipboard is empty")
+	}
+
+	// A URL wins over a hash, shared descriptors contain both
+	if url := model.FindURL(text); url != "" {
+		for _, build := range m.List.Builds {
+			if build.DownloadURL == url {
+				return build, nil
+			}
+		}
+		return model.ParseBuildURL(url)
+	}
+
+	hash := model.FindHash(text)
+	if hash == "" {
+		return model.BlenderBuild{}, fmt.Errorf("clipboard holds neither a build URL nor a commit hash")
+	}
+	for _, build := range m.List.Builds {
+		if build.Hash != "" && (strings.HasPrefix(build.Hash, hash) || strings.HasPrefix(hash, build.Hash)) {
+			return build, nil
+		}
+	}
+	return model.BlenderBuild{}, fmt.Errorf("no build with hash %s, fetch builds with f first", hash)
+}
+
+// handleShareBuild copies a descriptor of the selected build to the clipboard, or
+// writes it to a file in the download directory when no clipboard is available
+func (m *Model) handleShareBuild() (tea.Model, tea.Cmd) {
+	build := m.List.GetSelectedBuild()
+	if build == nil {
+		return m, nil
+	}
+	descriptor := model.ShareDescriptor(*build)
+
+	if err := clipboard.WriteAll(descriptor); err == nil {
+		m.notice = "copied to clipboard: " + descriptor
+		return m, nil
+	}
+
+	path := filepath.Join(m.config.DownloadDir, "blender-"+downloadID(*build)+".share.txt")
+	if err := os.WriteFile(path, []byte(descriptor+"\n"), 0644); err != nil {
+		m.err = fmt.Errorf("failed to share build: %w", err)
+		return m, nil
+	}
+	m.notice = "no clipboard available, descriptor written to " + path
+	return m, nil
+}
@@ -0,0 +1,156 @@
+package tui
+
+import (
+	"TUI-Blender-Launcher/config"
+	"TUI-Blender-Launcher/model"
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// updateGoldenEnv rewrites golden files instead of comparing against them when set to 1.
+const updateGoldenEnv = "UPDATE_GOLDEN"
+
+// TB is the subset of testing.TB the harness reports failures through,
+// so the harness can live outside of test files without importing testing.
+type TB interface {
+	Helper()
+	Fatalf(format string, args ...any)
+}
+
+// Harness drives a Model with scripted messages and captures its rendered frames,
+// for regression tests of the layout at various terminal sizes.
+//
+// Commands returned by Update are not run, which keeps replays deterministic and
+// offline. Tests send the messages those commands would produce themselves.
+type Harness struct {
+	model  *Model
+	frames []string
+}
+
+// NewHarness creates a harness around a fresh Model showing the builds list
+// in a terminal of the given size.
+func NewHarness(cfg config.Config, width, height int) *Harness {
+	h := &Harness{model: InitialModel(cfg, false)}
+	h.model.commands.downloads.queuePath = "" // Leave the user's planned downloads alone
+	h.model.commands.scanCachePath = ""
+	h.Send(tea.WindowSizeMsg{Width: width, Height: height})
+	return h
+}
+
+// Model returns the driven model, for assertions on its state.
+func (h *Harness) Model() *Model {
+	return h.model
+}
+
+// Send feeds messages to the model in order.
+func (h *Harness) Send(msgs ...tea.Msg) *Harness {
+	for _, msg := range msgs {
+		updated, _ := h.model.Update(msg)
+		h.model = updated.(*Model)
+	}
+	return h
+}
+
+// Keys sends key presses by name, e.g. "down", "enter", "esc" or "s".
+func (h *Harness) Keys(keys ...string) *Harness {
+	for _, key := range keys {
+		h.Send(KeyMsg(key))
+	}
+	return h
+}
+
+// Resize changes the terminal size.
+func (h *Harness) Resize(width, height int) *Harness {
+	return h.Send(tea.WindowSizeMsg{Width: width, Height: height})
+}
+
+// SetBuilds replaces the listed builds, as if a scan had just returned them.
+// Builds keep the Status they are given.
+func (h *Harness) SetBuilds(builds []model.BlenderBuild) *Harness {
+	return h.Send(localBuildsScannedMsg{builds: builds})
+}
+
+// Frame renders the current view and records it.
+func (h *Harness) Frame() string {
+	frame := h.model.View()
+	h.frames = append(h.frames, frame)
+	return frame
+}
+
+// Frames returns every frame recorded so far.
+func (h *Harness) Frames() []string {
+	return h.frames
+}
+
+// KeyMsg converts a key name to the message Bubble Tea sends for it.
+func KeyMsg(key string) tea.KeyMsg {
+	switch key {
+	case "up":
+		return tea.KeyMsg{Type: tea.KeyUp}
+	case "down":
+		return tea.KeyMsg{Type: tea.KeyDown}
+	case "left":
+		return tea.KeyMsg{Type: tea.KeyLeft}
+	case "right":
+		return tea.KeyMsg{Type: tea.KeyRight}
+	case "enter":
+		return tea.KeyMsg{Type: tea.KeyEnter}
+	case "esc":
+		return tea.KeyMsg{Type: tea.KeyEsc}
+	case "tab":
+		return tea.KeyMsg{Type: tea.KeyTab}
+	case "backspace":
+		return tea.KeyMsg{Type: tea.KeyBackspace}
+	case "space":
+		return tea.KeyMsg{Type: tea.KeySpace, Runes: []rune{' '}}
+	default:
+		return tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(key)}
+	}
+}
+
+// MatchGolden compares a frame with the golden file at path. Run the tests with
+// UPDATE_GOLDEN=1 to (re)write golden files after an intended layout change.
+func MatchGolden(t TB, path string, frame string) {
+	t.Helper()
+
+	if os.Getenv(updateGoldenEnv) == "1" {
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatalf("Failed to create golden directory: %v", err)
+		}
+		if err := os.WriteFile(path, []byte(frame), 0644); err != nil {
+			t.Fatalf("Failed to write golden file: %v", err)
+		}
+		return
+	}
+
+	want, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("Failed to read golden file (run with %s=1 to create it): %v", updateGoldenEnv, err)
+	}
+	if !bytes.Equal(want, []byte(frame)) {
+		t.Fatalf("Frame doesn't match %s:\n%s", path, diffLines(string(want), frame))
+	}
+}
+
+// diffLines describes the first differing line between two frames.
+func diffLines(want, got string) string {
+	wantLines := bytes.Split([]byte(want), []byte("\n"))
+	gotLines := bytes.Split([]byte(got), []byte("\n"))
+	for i := 0; i < len(wantLines) || i < len(gotLines); i++ {
+		var w, g []byte
+		if i < len(wantLines) {
+			w = wantLines[i]
+		}
+		if i < len(gotLines) {
+			g = gotLines[i]
+		}
+		if !bytes.Equal(w, g) {
+			return fmt.Sprintf("line %d\nwant: %q\ngot:  %q", i+1, w, g)
+		}
+	}
+	return "frames differ"
+}
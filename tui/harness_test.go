@@ -0,0 +1,2305 @@
+package tui
+
+import (
+	"TUI-Blender-Launcher/api"
+	"TUI-Blender-Launcher/config"
+	"TUI-Blender-Launcher/download"
+	"TUI-Blender-Launcher/launch"
+	"TUI-Blender-Launcher/local"
+	"TUI-Blender-Launcher/model"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	lp "github.com/charmbracelet/lipgloss"
+	"github.com/muesli/termenv"
+)
+
+// writeTestBuild installs a fake build in root, in a directory named after the
+// version in meta, with meta as its version.json and, unless script is "",
+// script as its blender executable. It returns the build directory.
+func writeTestBuild(t *testing.T, root, meta, script string) string {
+	t.Helper()
+	var build model.BlenderBuild
+	if err := json.Unmarshal([]byte(meta), &build); err != nil {
+		t.Fatalf("Invalid test metadata: %v", err)
+	}
+	dir := filepath.Join(root, "blender-"+build.Version+"-linux-x64")
+	if err := os.MkdirAll(dir, 0750); err != nil {
+		t.Fatalf("Failed to create build dir: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "version.json"), []byte(meta), 0644); err != nil {
+		t.Fatalf("Failed to write version.json: %v", err)
+	}
+	if script != "" {
+		if err := os.WriteFile(filepath.Join(dir, "blender"), []byte(script), 0755); err != nil {
+			t.Fatalf("Failed to write the executable: %v", err)
+		}
+	}
+	return dir
+}
+
+// testBuilds returns a fixed set of builds so frames don't depend on the network or disk
+func testBuilds() []model.BlenderBuild {
+	date := func(day int) model.Timestamp {
+		return model.Timestamp(time.Date(2025, time.March, day, 12, 0, 0, 0, time.UTC))
+	}
+	return []model.BlenderBuild{
+		{Version: "4.5.0", Branch: "main", Hash: "0123456789ab", BuildDate: date(20), Size: 350 << 20, ReleaseCycle: "alpha", Status: model.StateOnline},
+		{Version: "4.4.1", Branch: "v44", Hash: "abcdef012345", BuildDate: date(18), Size: 340 << 20, ReleaseCycle: "candidate", Status: model.StateLocal},
+		{Version: "4.2.9", Branch: "v42", Hash: "fedcba987654", BuildDate: date(2), Size: 320 << 20, ReleaseCycle: "stable", Status: model.StateUpdate},
+	}
+}
+
+func TestListFrames(t *testing.T) {
+	// Render colors so broken row highlighting shows up in the golden files
+	lp.SetColorProfile(termenv.ANSI256)
+	defer lp.SetColorProfile(termenv.Ascii)
+
+	cfg := config.DefaultConfig()
+	cfg.DownloadDir = t.TempDir()
+
+	// Narrow terminals drop low-priority columns, wide ones must not truncate any
+	// and the widest show the selected build's details beside the list
+	sizes := []struct{ width, height int }{
+		{60, 15},
+		{100, 15},
+		{160, 20},
+		{200, 20},
+	}
+
+	for _, size := range sizes {
+		t.Run(fmt.Sprintf("%dx%d", size.width, size.height), func(t *testing.T) {
+			h := NewHarness(cfg, size.width, size.height).SetBuilds(testBuilds())
+
+			// Move the cursor so the highlighted row is not the first one
+			frame := h.Keys("down").Frame()
+			// The layout leaves the last line free, more lines mean something wrapped
+			if lines := strings.Count(frame, "\n") + 1; lines != size.height-1 {
+				t.Errorf("Frame has %d lines, want %d", lines, size.height-1)
+			}
+
+			golden := filepath.Join("testdata", fmt.Sprintf("list_%dx%d.golden", size.width, size.height))
+			MatchGolden(t, golden, frame)
+		})
+	}
+}
+
+func TestCustomColumnFrame(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.DownloadDir = t.TempDir()
+	cfg.Columns = []config.CustomColumn{
+		{Name: "Ref", Template: "{{.Branch}}/{{.Hash | short}}"},
+	}
+
+	h := NewHarness(cfg, 140, 15).SetBuilds(testBuilds())
+	if h.Model().err != nil {
+		t.Fatalf("Unexpected error compiling columns: %v", h.Model().err)
+	}
+
+	// Move the sort to the custom column, one step left of Version wraps around to it
+	h.Keys("left")
+	if got := h.Model().List.Builds[0].Branch; got != "v44" {
+		t.Errorf("Expected builds sorted by Ref descending to start with v44, got %s", got)
+	}
+
+	MatchGolden(t, filepath.Join("testdata", "custom_column_140x15.golden"), h.Frame())
+}
+
+func TestSaveSettingsKeepsDownloads(t *testing.T) {
+	t.Setenv(config.ConfigEnvVar, filepath.Join(t.TempDir(), "config.toml"))
+	cfg := config.DefaultConfig()
+	cfg.DownloadDir = t.TempDir()
+	cfg.MaxConcurrentDownloads = 1
+	h := NewHarness(cfg, 100, 15).SetBuilds(testBuilds())
+	running, queued := h.Model().List.Builds[0], h.Model().List.Builds[1]
+
+	// One download holds the only slot, another waits for it
+	dm := h.Model().commands.downloads
+	dm.mu.Lock()
+	for _, build := range []model.BlenderBuild{running, queued} {
+		dm.states[downloadID(build)] = &model.DownloadState{BuildID: downloadID(build), BuildState: model.StateQueued, CancelCh: make(chan struct{})}
+		dm.builds[downloadID(build)] = build
+	}
+	dm.states[downloadID(running)].BuildState = model.StateDownloading
+	dm.active = 1
+	dm.queue = []model.BlenderBuild{queued}
+	dm.mu.Unlock()
+
+	h.Keys("s")
+	h.Model().config.DownloadRateLimit = 5
+	h.Model().SaveSettingsAndReturn()
+	if h.Model().commands.downloads != dm {
+		t.Fatal("Expected saving settings to keep the download manager")
+	}
+	dm.mu.Lock()
+	defer dm.mu.Unlock()
+	if dm.active != 1 || len(dm.queue) != 1 || dm.states[downloadID(running)].BuildState != model.StateDownloading {
+		t.Errorf("Expected the running and queued downloads kept, got %d active and queue %v", dm.active, dm.queue)
+	}
+	if dm.cfg.DownloadRateLimit != 5 {
+		t.Errorf("Expected the new settings for later downloads, got a rate limit of %v", dm.cfg.DownloadRateLimit)
+	}
+}
+
+func TestSettingsFrameFitsTerminal(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.DownloadDir = t.TempDir()
+
+	// A small terminal must neither wrap nor push the footer off screen,
+	// even with the last setting focused
+	h := NewHarness(cfg, 40, 12).Keys("s", "up")
+	for _, size := range []struct{ width, height int }{{40, 12}, {30, 10}, {120, 40}} {
+		frame := h.Resize(size.width, size.height).Frame()
+
+		lines := strings.Split(frame, "\n")
+		if len(lines) > size.height {
+			t.Errorf("%dx%d: frame has %d lines", size.width, size.height, len(lines))
+		}
+		for i, line := range lines {
+			if w := lp.Width(line); w > size.width {
+				t.Errorf("%dx%d: line %d is %d columns wide", size.width, size.height, i, w)
+			}
+		}
+		if !strings.Contains(frame, "Launch Mode") {
+			t.Errorf("%dx%d: focused setting scrolled out of view:\n%s", size.width, size.height, frame)
+		}
+	}
+}
+
+func TestTickLoopSleepsWhenIdle(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.DownloadDir = t.TempDir()
+	m := NewHarness(cfg, 100, 15).SetBuilds(testBuilds()).Model()
+
+	// With nothing in progress a due tick schedules no further one
+	if _, cmd := m.Update(tickMsg(m.tickDue)); cmd != nil || m.ticking {
+		t.Fatalf("Expected the tick loop to stop while idle (ticking=%v)", m.ticking)
+	}
+
+	// Any other message wakes it again
+	if _, cmd := m.Update(KeyMsg("down")); cmd == nil || !m.ticking {
+		t.Fatalf("Expected a key press to wake the tick loop (ticking=%v)", m.ticking)
+	}
+
+	// A tick superseded by the wake-up is dropped
+	if _, cmd := m.Update(tickMsg(m.tickDue.Add(-time.Second))); cmd != nil {
+		t.Error("Expected a superseded tick to be dropped")
+	}
+}
+
+func TestChangelogView(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.DownloadDir = t.TempDir()
+	h := NewHarness(cfg, 40, 10).SetBuilds(testBuilds())
+
+	// An unseen version opens the changelog over the builds list
+	h.Send(changelogMsg{})
+	if h.Model().currentView != viewChangelog {
+		t.Fatalf("Expected the changelog view, got %v", h.Model().currentView)
+	}
+
+	// Scrolling to the end stays within the terminal and shows the oldest change
+	for i := 0; i < 40; i++ {
+		h.Keys("down")
+	}
+	frame := h.Frame()
+	if lines := strings.Count(frame, "\n") + 1; lines > 10 {
+		t.Errorf("Frame has %d lines", lines)
+	}
+	if !strings.Contains(frame, "Browse, download") {
+		t.Errorf("Expected the oldest change after scrolling:\n%s", frame)
+	}
+
+	// Background results still reach the list while it is shown
+	h.SetBuilds(testBuilds()[:1])
+	if len(h.Model().List.Builds) != 1 {
+		t.Errorf("Expected the scan result to update the list, got %d builds", len(h.Model().List.Builds))
+	}
+
+	if h.Keys("esc").Model().currentView != viewList {
+		t.Error("Expected esc to return to the builds list")
+	}
+
+	// It never replaces the settings the user is editing
+	h.Keys("s").Send(changelogMsg{})
+	if h.Model().currentView != viewSettings {
+		t.Error("Expected the changelog to wait while settings are open")
+	}
+}
+
+func TestInsightsFrame(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.DownloadDir = t.TempDir()
+	cfg.Insights = true
+	h := NewHarness(cfg, 60, 40).SetBuilds(testBuilds())
+
+	m := h.Model()
+	m.insights = local.Insights{Days: []local.InsightsDay{
+		{Date: "2025-02-27", LibraryBytes: 2 << 30, Builds: 3, DownloadedBytes: 700 << 20, Downloads: 2, Launches: map[string]int{"4.4.1": 3}},
+		{Date: "2025-03-02", LibraryBytes: 3 << 30, Builds: 4, DownloadedBytes: 350 << 20, Downloads: 1, Launches: map[string]int{"4.4.1": 1, "4.5.0": 2}},
+	}}
+	m.currentView = viewInsights
+
+	frame := h.Frame()
+	for _, want := range []string{"2025-02", "2025-03", "(4 builds now)", "4.4.1", "4.5.0"} {
+		if !strings.Contains(frame, want) {
+			t.Errorf("Expected %q in the insights view:\n%s", want, frame)
+		}
+	}
+	for i, line := range strings.Split(frame, "\n") {
+		if w := lp.Width(line); w > 60 {
+			t.Errorf("Line %d is %d columns wide", i, w)
+		}
+	}
+
+	if h.Keys("esc").Model().currentView != viewList {
+		t.Error("Expected esc to return to the builds list")
+	}
+}
+
+func TestLowDiskCleanup(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.DownloadDir = t.TempDir()
+	h := NewHarness(cfg, 80, 15).SetBuilds(testBuilds())
+
+	// Free space below the threshold shows the banner without growing the frame
+	h.Send(diskSpaceMsg{free: 2 << 30})
+	frame := h.Frame()
+	if !strings.Contains(frame, "Low disk space: 2.0GB free") {
+		t.Errorf("Expected the low disk banner:\n%s", frame)
+	}
+	if lines := strings.Count(frame, "\n") + 1; lines > 15 {
+		t.Errorf("Frame has %d lines", lines)
+	}
+	if h.Send(diskSpaceMsg{free: 20 << 30}).Model().lowDiskFree != 0 {
+		t.Error("Expected the banner to clear above the threshold")
+	}
+
+	// The cleanup view suggests everything but installed builds, largest first
+	h.Keys("c").Send(cleanupItemsMsg{items: []local.ReclaimableItem{
+		{Kind: local.ReclaimBuild, Name: "4.4.1", Size: 900 << 20},
+		{Kind: local.ReclaimBackup, Name: "blender-4.2.9_20250301", Size: 800 << 20},
+		{Kind: local.ReclaimArchive, Name: "blender-4.5.0.tar.xz", Size: 300 << 20},
+		{Kind: local.ReclaimResume, Name: "blender-4.5.1.tar.xz", Size: 100 << 20},
+	}})
+	m := h.Model()
+	if m.currentView != viewCleanup {
+		t.Fatalf("Expected the cleanup view, got %v", m.currentView)
+	}
+	if _, size := m.selectedCleanup(); size != 1100<<20 {
+		t.Errorf("Expected the backup and archive preselected, but not the build or the resumable download, got %d bytes", size)
+	}
+	if frame := h.Frame(); !strings.Contains(frame, "1.1GB selected") {
+		t.Errorf("Expected the selected total:\n%s", frame)
+	}
+
+	// Picking the build too and confirming deletes in the background
+	h.Keys(" ")
+	if _, size := h.Model().selectedCleanup(); size != 2000<<20 {
+		t.Errorf("Expected all but the resumable download selected, got %d bytes", size)
+	}
+	if _, cmd := h.Model().Update(KeyMsg("enter")); cmd == nil || !h.Model().cleanup.removing {
+		t.Error("Expected enter to start deleting the selection")
+	}
+	if h.Send(cleanupDoneMsg{freed: 2000 << 20}).Model().currentView != viewList {
+		t.Error("Expected to return to the builds list after cleaning up")
+	}
+
+	// The retention policy after downloads tells what it removed too
+	if frame := h.Send(retentionDoneMsg{removed: 2, freed: 700 << 20}).Frame(); !strings.Contains(frame, "removed 2 old builds beyond keep_per_series") {
+		t.Errorf("Expected the removed builds in the footer:\n%s", frame)
+	}
+}
+
+func TestDownloadNeedsFreeSpace(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.DownloadDir = t.TempDir()
+	builds := testBuilds()
+	builds[0].Size = 1 << 50 // No disk has room for it
+	h := NewHarness(cfg, 160, 15).SetBuilds(builds)
+
+	// The refusal shows in the footer instead of the download starting
+	if _, cmd := h.Model().handleStartDownload(); cmd != nil {
+		t.Fatal("Expected the download to be refused")
+	}
+	if frame := h.Frame(); !strings.Contains(frame, "not enough disk space") {
+		t.Errorf("Expected the refusal in the footer:\n%s", frame)
+	}
+
+	// And goes away with the next key
+	if frame := h.Keys("down").Frame(); strings.Contains(frame, "not enough disk space") {
+		t.Errorf("Expected the refusal to clear:\n%s", frame)
+	}
+}
+
+func TestFailedDownloadGuidance(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.DownloadDir = t.TempDir()
+	builds := testBuilds()
+	builds[0].Status = model.StateFailed
+	buildID := downloadID(builds[0])
+
+	for _, width := range []int{160, 200} {
+		h := NewHarness(cfg, width, 20).SetBuilds(builds)
+		dm := h.Model().commands.downloads
+		dm.states[buildID] = &model.DownloadState{BuildID: buildID, BuildState: model.StateFailed,
+			Err: &download.DiskFullError{Err: errors.New("no space left on device")}}
+
+		// The failed row tells how to recover, in the details pane when there is one
+		if frame := h.Send(tickMsg(time.Now())).Frame(); !strings.Contains(frame, "Free up space") {
+			t.Errorf("Expected the guidance for the failed row at width %d:\n%s", width, frame)
+		}
+	}
+}
+
+func TestPostInstallHookFailure(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.DownloadDir = t.TempDir()
+	h := NewHarness(cfg, 160, 15).SetBuilds(testBuilds())
+
+	// The build is installed before the hook runs, its failure comes on its own
+	h.Send(downloadCompleteMsg{buildVersion: "4.5.0"})
+	h.Send(postInstallDoneMsg{buildVersion: "4.5.0", err: errors.New("post-install hook failed: exit status 3")})
+	if frame := h.Frame(); !strings.Contains(frame, "4.5.0 is installed, but post-install hook failed") {
+		t.Errorf("Expected the hook failure in the footer:\n%s", frame)
+	}
+}
+
+func TestPasteBuild(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.DownloadDir = t.TempDir()
+	h := NewHarness(cfg, 100, 15).SetBuilds(testBuilds())
+
+	// What can't be installed is told in the footer
+	tests := []struct {
+		text string
+		want string
+	}{
+		{"", "clipboard is empty"},
+		{"hello", "clipboard holds neither a build URL nor a commit hash"},
+		{"deadbeef1234", "no build with hash deadbeef1234"},
+		{"abcdef012345", "Blender 4.4.1 (abcdef012345) is already installed"},
+	}
+	for _, tt := range tests {
+		h.Keys("home")
+		h.Model().installPasted(tt.text)
+		if frame := h.Frame(); !strings.Contains(frame, tt.want) {
+			t.Errorf("Expected %q in the footer after pasting %q:\n%s", tt.want, tt.text, frame)
+		}
+	}
+}
+
+func TestShareBuild(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.DownloadDir = t.TempDir()
+	h := NewHarness(cfg, 200, 15).SetBuilds(testBuilds())
+
+	// Whether or not a clipboard is available, the footer tells where the descriptor went
+	h.Model().handleShareBuild()
+	frame := h.Frame()
+	if !strings.Contains(frame, "copied to clipboard") && !strings.Contains(frame, "descriptor written to") {
+		t.Errorf("Expected the shared descriptor in the footer:\n%s", frame)
+	}
+	if h.Model().err != nil {
+		t.Errorf("Expected sharing to succeed, got %v", h.Model().err)
+	}
+}
+
+func TestScheduleDownload(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.DownloadDir = t.TempDir()
+	h := NewHarness(cfg, 100, 15).SetBuilds(testBuilds())
+
+	// The newest build is online; schedule it from the footer input
+	h.Keys("t")
+	if !h.Model().scheduling {
+		t.Fatal("Expected t to ask when to download")
+	}
+	h.Keys("+", "2", "h", "enter")
+	if h.Model().scheduling {
+		t.Fatalf("Expected the schedule to be accepted, got %q", h.Model().scheduleErr)
+	}
+
+	build := h.Model().List.GetSelectedBuild()
+	if build.Status != model.StateScheduled {
+		t.Fatalf("Expected the build to be scheduled, got %v", build.Status)
+	}
+	// The status wraps in the narrow column
+	want := time.Now().Add(2 * time.Hour).Format("15:04")
+	if frame := h.Frame(); !strings.Contains(frame, "Scheduled") || !strings.Contains(frame, want) {
+		t.Errorf("Expected Scheduled %s in the status column:\n%s", want, frame)
+	}
+
+	// Invalid times keep the input open with the reason
+	h.Keys("t", "x", "enter")
+	if !h.Model().scheduling || h.Model().scheduleErr == "" {
+		t.Error("Expected an invalid time to be rejected")
+	}
+	h.Keys("esc")
+
+	// Cancelling drops the schedule
+	h.Keys("x")
+	if build := h.Model().List.GetSelectedBuild(); build.Status != model.StateCancelled {
+		t.Errorf("Expected the schedule to be cancelled, got %v", build.Status)
+	}
+}
+
+func TestMarkedDownloads(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.DownloadDir = t.TempDir()
+	h := NewHarness(cfg, 100, 15).SetBuilds(testBuilds())
+
+	// Marking moves on, so the online and the updated build are marked in a row
+	h.Keys("m", "down", "m")
+	if got := len(h.Model().List.Marked); got != 2 {
+		t.Fatalf("Expected 2 marked builds, got %d", got)
+	}
+	frame := h.Frame()
+	if !strings.Contains(frame, "+ 4.5.0") || !strings.Contains(frame, "+ 4.2.9") {
+		t.Errorf("Expected marked rows to show a +:\n%s", frame)
+	}
+	if !strings.Contains(frame, "Download 2 marked") {
+		t.Errorf("Expected the footer to offer downloading the marked builds:\n%s", frame)
+	}
+
+	// d starts a download for each marked build and clears the marks
+	_, cmd := h.Model().handleStartDownload()
+	if cmd == nil {
+		t.Fatal("Expected d to start the marked downloads")
+	}
+	batch, ok := cmd().(tea.BatchMsg)
+	if !ok || len(batch) != 2 {
+		t.Fatalf("Expected a start message per marked build, got %#v", batch)
+	}
+	for _, start := range batch {
+		h.Send(start())
+	}
+	if got := len(h.Model().List.Marked); got != 0 {
+		t.Errorf("Expected marks to be cleared, got %d", got)
+	}
+	for _, build := range h.Model().List.Builds {
+		if build.Status == model.StateOnline || build.Status == model.StateUpdate {
+			t.Errorf("Expected %s to be downloading, got %v", build.Version, build.Status)
+		}
+	}
+
+	// esc clears marks without downloading
+	h.Keys("m", "esc")
+	if got := len(h.Model().List.Marked); got != 0 {
+		t.Errorf("Expected esc to clear marks, got %d", got)
+	}
+}
+
+func TestInterruptedDownload(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.DownloadDir = t.TempDir()
+	h := NewHarness(cfg, 120, 15)
+
+	// A download left half done by the last session shows up without fetching
+	build := model.BlenderBuild{Version: "4.5.0", Branch: "main", Hash: "0123456789ab", Size: 1000,
+		DownloadURL: "https://example.org/blender-4.5.0.tar.xz"}
+	h.Send(localBuildsScannedMsg{interrupted: []download.InterruptedDownload{{Build: build, Downloaded: 500}}})
+
+	selected := h.Model().List.GetSelectedBuild()
+	if selected == nil || selected.Status != model.StateInterrupted {
+		t.Fatalf("Expected the unfinished download to be listed as interrupted, got %v", selected)
+	}
+	if frame := h.Frame(); !strings.Contains(frame, "Interrupted 50%") || !strings.Contains(frame, "Resume") {
+		t.Errorf("Expected the row to offer resuming at 50%%:\n%s", frame)
+	}
+
+	// Discarding forgets it
+	h.Keys("x")
+	if selected := h.Model().List.GetSelectedBuild(); selected.Status != model.StateOnline {
+		t.Errorf("Expected a discarded download to be online again, got %v", selected.Status)
+	}
+	if state := h.Model().commands.downloads.GetState(downloadID(build)); state != nil {
+		t.Errorf("Expected the interrupted state to be dropped, got %v", state.BuildState)
+	}
+}
+
+func TestCleanTempFiles(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.DownloadDir = t.TempDir()
+	h := NewHarness(cfg, 120, 15).SetBuilds(testBuilds())
+
+	stale := filepath.Join(cfg.DownloadDir, download.DownloadingDir, "4.0.0-tmp")
+	if err := os.MkdirAll(stale, 0750); err != nil {
+		t.Fatalf("Failed to create leftover: %v", err)
+	}
+
+	// Leftovers found at startup are offered for cleaning
+	msg := checkStaleFiles(cfg.DownloadDir)()
+	if _, ok := msg.(staleFilesMsg); !ok {
+		t.Fatalf("Expected the leftover to be found, got %#v", msg)
+	}
+	if frame := h.Send(msg).Frame(); !strings.Contains(frame, "Clean temp files") {
+		t.Errorf("Expected the footer to offer cleaning temp files:\n%s", frame)
+	}
+
+	_, cmd := h.Model().handleCleanTempFiles()
+	if cmd == nil {
+		t.Fatal("Expected C to remove the leftovers")
+	}
+	if done := cmd().(staleFilesCleanedMsg); done.err != nil {
+		t.Fatalf("Removing leftovers failed: %v", done.err)
+	}
+	if _, err := os.Stat(stale); !os.IsNotExist(err) {
+		t.Error("Expected the leftover to be removed")
+	}
+	if frame := h.Frame(); strings.Contains(frame, "Clean temp files") {
+		t.Errorf("Expected the clean option to be gone:\n%s", frame)
+	}
+}
+
+func TestExecutablesView(t *testing.T) {
+	if runtime.GOOS != "linux" {
+		t.Skip("build layout and executable bits are those of Linux builds")
+	}
+	cfg := config.DefaultConfig()
+	cfg.DownloadDir = t.TempDir()
+
+	// A Linux build with its helpers, a data file and the bundled Python
+	dir := filepath.Join(cfg.DownloadDir, "blender-4.4.1-linux-x64")
+	files := map[string]os.FileMode{
+		"blender":                   0755,
+		"blender-thumbnailer":       0755,
+		"blender_debug_gpu.sh":      0755,
+		"blender.desktop":           0644,
+		"4.4/python/bin/python3.11": 0755,
+		"version.json":              0644,
+	}
+	for name, mode := range files {
+		path := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0750); err != nil {
+			t.Fatalf("Failed to create dir for %s: %v", name, err)
+		}
+		if err := os.WriteFile(path, []byte(`{"version": "4.4.1"}`), mode); err != nil {
+			t.Fatalf("Failed to write %s: %v", name, err)
+		}
+	}
+
+	h := NewHarness(cfg, 100, 15).SetBuilds(testBuilds()).Keys("down", "e")
+	if h.Model().currentView != viewExecutables {
+		t.Fatalf("Expected the executables view for the local build")
+	}
+	var names []string
+	for _, item := range h.Model().executables.items {
+		names = append(names, item.Name)
+	}
+	want := []string{filepath.FromSlash("4.4/python/bin/python3.11"), "blender-thumbnailer", "blender_debug_gpu.sh"}
+	if fmt.Sprint(names) != fmt.Sprint(want) {
+		t.Errorf("Expected executables %v, got %v", want, names)
+	}
+	if frame := h.Frame(); !strings.Contains(frame, "blender-thumbnailer") {
+		t.Errorf("Expected the executables to be listed:\n%s", frame)
+	}
+
+	h.Keys("esc")
+	if h.Model().currentView != viewList {
+		t.Errorf("Expected esc to return to the builds list")
+	}
+}
+
+func TestHealthBadge(t *testing.T) {
+	tests := []struct {
+		name string
+		msg  healthMsg
+		want string // Empty when no badge is expected
+	}{
+		{"Not checked yet", healthMsg{}, ""},
+		{"Builder answers", healthMsg{builder: api.HealthUp}, "● builder"},
+		{"Builder errors", healthMsg{builder: api.HealthDown}, "● builder down"},
+		{"Only mirrors answer", healthMsg{builder: api.HealthUnreachable, mirrors: true, mirrorUp: true}, "● builder unreachable"},
+		{"Nothing answers", healthMsg{builder: api.HealthUnreachable, mirrors: true}, "● offline"},
+		{"No mirrors to ask", healthMsg{builder: api.HealthUnreachable}, "● builder unreachable (no mirrors)"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := NewHarness(config.DefaultConfig(), 100, 15).SetBuilds(testBuilds()).Send(tt.msg)
+			header := strings.SplitN(h.Frame(), "\n", 2)[0]
+			if tt.want == "" {
+				if strings.Contains(header, "●") {
+					t.Errorf("Expected no badge, got %q", header)
+				}
+				return
+			}
+			if !strings.Contains(header, tt.want) || !strings.Contains(header, "TUI Blender Launcher") {
+				t.Errorf("Expected header with title and %q, got %q", tt.want, header)
+			}
+		})
+	}
+}
+
+func TestLibraryChangedOutside(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.DownloadDir = t.TempDir()
+	h := NewHarness(cfg, 100, 15).SetBuilds(testBuilds()).Keys("down", "down")
+
+	// Another program installed 4.4.5 and 4.3.0 and removed the local 4.4.1, 4.2.9 stays
+	for _, meta := range []string{
+		`{"version": "4.4.5", "hash": "2222222222bb"}`,
+		`{"version": "4.3.0", "hash": "1111111111aa"}`,
+		`{"version": "4.2.9", "branch": "v42", "hash": "fedcba987654"}`,
+	} {
+		writeTestBuild(t, cfg.DownloadDir, meta, "")
+	}
+
+	// Only a changed fingerprint triggers a rescan
+	fingerprint := local.LibraryFingerprint(cfg.Roots())
+	if _, cmd := h.Model().handleLibraryWatch(libraryWatchMsg{fingerprint: h.Model().libraryFingerprint}); cmd == nil {
+		t.Fatal("Expected the next check to be scheduled")
+	}
+	h.Send(libraryWatchMsg{fingerprint: fingerprint})
+	if h.Model().libraryFingerprint != fingerprint {
+		t.Fatal("Expected the new fingerprint to be kept")
+	}
+
+	builds, err := local.ScanLocalBuilds(cfg.Roots()...)
+	if err != nil {
+		t.Fatalf("ScanLocalBuilds returned an error: %v", err)
+	}
+	h.Send(libraryChangedMsg{builds: builds})
+
+	var versions []string
+	for _, build := range h.Model().List.Builds {
+		versions = append(versions, build.Version)
+	}
+	if want := []string{"4.5.0", "4.4.5", "4.3.0", "4.2.9"}; fmt.Sprint(versions) != fmt.Sprint(want) {
+		t.Errorf("Expected builds %v, got %v", want, versions)
+	}
+	// The selection follows the build it was on
+	if selected := h.Model().List.GetSelectedBuild(); selected == nil || selected.Version != "4.2.9" {
+		t.Errorf("Expected 4.2.9 to stay selected, got %+v", selected)
+	}
+}
+
+func TestInstalledSizeColumn(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.DownloadDir = t.TempDir()
+
+	// The archive was 340MB, the extracted build takes 3KB here
+	dir := filepath.Join(cfg.DownloadDir, "blender-4.4.1-linux-x64")
+	if err := os.MkdirAll(filepath.Join(dir, "lib"), 0750); err != nil {
+		t.Fatalf("Failed to create build dir: %v", err)
+	}
+	meta := `{"version": "4.4.1", "branch": "v44", "hash": "abcdef012345", "file_size": 356515840}`
+	files := map[string]string{
+		"version.json":   meta,
+		"blender":        strings.Repeat("x", 2048-len(meta)),
+		"lib/libcore.so": strings.Repeat("x", 1024),
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+			t.Fatalf("Failed to write %s: %v", name, err)
+		}
+	}
+
+	builds, err := local.ScanLocalBuilds(cfg.Roots()...)
+	if err != nil || len(builds) != 1 {
+		t.Fatalf("Expected one installed build, got %v, %v", builds, err)
+	}
+	if builds[0].InstalledSize != 3072 {
+		t.Errorf("Expected 3072 bytes on disk, got %d", builds[0].InstalledSize)
+	}
+
+	frame := NewHarness(cfg, 100, 15).SetBuilds(builds).Frame()
+	if !strings.Contains(frame, "3.0KB") || strings.Contains(frame, "340.0MB") {
+		t.Errorf("Expected the size on disk instead of the archive size:\n%s", frame)
+	}
+}
+
+func TestRowIcons(t *testing.T) {
+	tests := []struct {
+		name   string
+		icons  string
+		locale string
+		want   []string // Start of the version cell of each test build
+	}{
+		{"none", config.RowIconsNone, "en_US.UTF-8", []string{" 4.5.0 ", " 4.4.1 ", " 4.2.9 "}},
+		{"unicode", config.RowIconsUnicode, "en_US.UTF-8", []string{"☁ 4.5.0", "■ 4.4.1", "↑ 4.2.9"}},
+		{"nerd", config.RowIconsNerd, "C.utf8", []string{"\uf0c2 4.5.0", "\uf0a0 4.4.1", "\uf062 4.2.9"}},
+		{"ascii", config.RowIconsASCII, "en_US.UTF-8", []string{"~ 4.5.0", "= 4.4.1", "^ 4.2.9"}},
+		{"fallback without UTF-8", config.RowIconsUnicode, "C", []string{"~ 4.5.0", "= 4.4.1", "^ 4.2.9"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("LC_ALL", tt.locale)
+			cfg := config.DefaultConfig()
+			cfg.DownloadDir = t.TempDir()
+			cfg.RowIcons = tt.icons
+
+			frame := NewHarness(cfg, 100, 15).SetBuilds(testBuilds()).Frame()
+			for _, want := range tt.want {
+				if !strings.Contains(frame, want) {
+					t.Errorf("Expected %q in the frame:\n%s", want, frame)
+				}
+			}
+		})
+	}
+}
+
+func TestSetDefaultBuild(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("Symlinks need Developer Mode on Windows")
+	}
+	cfg := config.DefaultConfig()
+	cfg.DownloadDir = t.TempDir()
+	dir := writeTestBuild(t, cfg.DownloadDir, `{"version": "4.4.1", "branch": "v44", "hash": "abcdef012345"}`, "")
+
+	h := NewHarness(cfg, 100, 15).SetBuilds(testBuilds()).Keys("down")
+	_, cmd := h.Model().handleSetDefaultBuild()
+	if cmd == nil {
+		t.Fatal("Expected a command setting the default build")
+	}
+	h.Send(cmd())
+
+	link := filepath.Join(cfg.DownloadDir, local.CurrentLinkName)
+	if target, err := os.Readlink(link); err != nil || target != dir {
+		t.Fatalf("Expected %s to point at %s, got %q, %v", link, dir, target, err)
+	}
+	if !strings.Contains(h.Frame(), "4.4.1 *") {
+		t.Errorf("Expected the default build to be marked:\n%s", h.Frame())
+	}
+	// The link is no build of its own
+	if builds, err := local.ScanLocalBuilds(cfg.Roots()...); err != nil || len(builds) != 1 {
+		t.Errorf("Expected only the installed build, got %v, %v", builds, err)
+	}
+
+	// Deleting the default build takes the link with it
+	if _, err := local.DeleteBuild(cfg.Roots(), "4.4.1", false); err != nil {
+		t.Fatalf("DeleteBuild returned an error: %v", err)
+	}
+	if _, err := os.Lstat(link); !os.IsNotExist(err) {
+		t.Errorf("Expected the link to be removed with the build, got %v", err)
+	}
+}
+
+func TestTagBuilds(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.DownloadDir = t.TempDir()
+	for _, meta := range []string{
+		`{"version": "4.4.1", "branch": "v44", "hash": "abcdef012345", "release_cycle": "candidate"}`,
+		`{"version": "4.2.9", "branch": "v42", "hash": "fedcba987654", "release_cycle": "stable"}`,
+	} {
+		writeTestBuild(t, cfg.DownloadDir, meta, "")
+	}
+	run := func(h *Harness, cmd tea.Cmd) {
+		t.Helper()
+		if cmd == nil {
+			t.Fatal("Expected a command")
+		}
+		h.Send(cmd())
+	}
+
+	h := NewHarness(cfg, 160, 15).SetBuilds(testBuilds()).Keys("down")
+	if strings.Contains(h.Frame(), "Tags") {
+		t.Errorf("Expected no tags column without tags:\n%s", h.Frame())
+	}
+
+	// Tag 4.4.1 and make it a favorite
+	h.Keys("g")
+	for _, r := range "production testme, production" {
+		h.Send(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{r}})
+	}
+	_, cmd := h.Model().updateTagInput(tea.KeyMsg{Type: tea.KeyEnter})
+	run(h, cmd)
+	_, cmd = h.Model().handleToggleFavorite()
+	run(h, cmd)
+
+	frame := h.Frame()
+	if !strings.Contains(frame, "Tags") || !strings.Contains(frame, "★ production, testme") {
+		t.Errorf("Expected the tags column with the new tags:\n%s", frame)
+	}
+	builds, err := local.ScanLocalBuilds(cfg.Roots()...)
+	if err != nil {
+		t.Fatalf("ScanLocalBuilds returned an error: %v", err)
+	}
+	for _, build := range builds {
+		tagged := build.Version == "4.4.1"
+		if build.HasTag("production") != tagged || build.Favorite != tagged || build.ReleaseCycle == "" {
+			t.Errorf("Unexpected stored metadata %+v", build)
+		}
+	}
+
+	// Filtering keeps the tagged build only, clearing the filter brings the others back
+	h.Keys("/")
+	for _, r := range "Production" {
+		h.Send(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{r}})
+	}
+	_, cmd = h.Model().updateTagInput(tea.KeyMsg{Type: tea.KeyEnter})
+	run(h, cmd)
+	if got := len(h.Model().List.Builds); got != 1 || h.Model().List.Builds[0].Version != "4.4.1" {
+		t.Errorf("Expected only 4.4.1 to be listed, got %+v", h.Model().List.Builds)
+	}
+	_, cmd = h.Model().setTagFilter("")
+	run(h, cmd)
+	if got := len(h.Model().List.Builds); got != 2 {
+		t.Errorf("Expected both installed builds again, got %d", got)
+	}
+}
+
+func TestTemporaryBuilds(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.DownloadDir = t.TempDir()
+	cfg.InstallRoots = []string{t.TempDir()}
+	expired := time.Now().Add(-time.Hour).UTC().Format(time.RFC3339)
+
+	// The expired build lives in an install root, which cleanup covers too
+	for i, meta := range []string{
+		`{"version": "4.4.1", "branch": "v44", "hash": "abcdef012345", "release_cycle": "candidate", "expires_at": "` + expired + `"}`,
+		`{"version": "4.2.9", "branch": "v42", "hash": "fedcba987654", "release_cycle": "stable"}`,
+	} {
+		writeTestBuild(t, cfg.Roots()[1-i], meta, "")
+	}
+	builds, err := local.ScanLocalBuilds(cfg.Roots()...)
+	if err != nil {
+		t.Fatalf("ScanLocalBuilds returned an error: %v", err)
+	}
+
+	// The expired build is highlighted and suggested for cleanup
+	h := NewHarness(cfg, 160, 15).SetBuilds(builds)
+	frame := h.Frame()
+	if !strings.Contains(frame, "Expired") || !strings.Contains(frame, "1 temporary build expired") {
+		t.Errorf("Expected the expired build to be pointed out:\n%s", frame)
+	}
+	items, err := local.FindReclaimable(cfg.Roots(), false)
+	if err != nil {
+		t.Fatalf("FindReclaimable returned an error: %v", err)
+	}
+	if len(items) != 2 {
+		t.Errorf("Expected the builds of both roots, got %+v", items)
+	}
+	for _, item := range items {
+		if want := item.Name == "4.4.1"; (item.Kind == local.ReclaimExpired) != want || item.Disposable() != want {
+			t.Errorf("Unexpected reclaimable item %+v", item)
+		}
+	}
+
+	// Keeping the build for good clears its expiry
+	for h.Model().List.GetSelectedBuild().Version != "4.4.1" {
+		h.Keys("down")
+	}
+	_, cmd := h.Model().handleTryBuild()
+	if cmd == nil {
+		t.Fatal("Expected a command saving the expiry")
+	}
+	h.Send(cmd())
+	if strings.Contains(h.Frame(), "expired") {
+		t.Errorf("Expected no expired builds once kept:\n%s", h.Frame())
+	}
+	if build, err := local.ReadBuildInfo(filepath.Join(cfg.Roots()[1], "blender-4.4.1-linux-x64")); err != nil || build.ExpiresAt != nil {
+		t.Errorf("Expected the stored expiry to be cleared, got %+v, %v", build, err)
+	}
+
+	// Trying an online build downloads it with an expiry
+	h = NewHarness(cfg, 160, 15).SetBuilds(testBuilds())
+	_, cmd = h.Model().handleTryBuild()
+	if cmd == nil {
+		t.Fatal("Expected a command starting the download")
+	}
+	msg, ok := cmd().(startDownloadMsg)
+	if !ok || msg.build.ExpiresAt == nil {
+		t.Fatalf("Expected a temporary download, got %+v", msg)
+	}
+	if left := time.Until(*msg.build.ExpiresAt); left < 6*24*time.Hour || left > 7*24*time.Hour {
+		t.Errorf("Expected the build to expire in 7 days, got %v", left)
+	}
+}
+
+func TestStableReleaseAnnouncement(t *testing.T) {
+	t.Setenv("XDG_STATE_HOME", t.TempDir())
+	cfg := config.DefaultConfig()
+	cfg.DownloadDir = t.TempDir()
+	release := model.BlenderBuild{Version: "4.3.0", Branch: "v43", Hash: "0123456789ab", ReleaseCycle: "stable", Size: 350 << 20, Status: model.StateOnline}
+	fetched := []model.BlenderBuild{
+		{Version: "4.2.0", Branch: "v42", ReleaseCycle: "stable", Status: model.StateOnline},
+		{Version: "4.3.1", Branch: "v43", ReleaseCycle: "daily", Status: model.StateOnline},
+	}
+	check := func(builds []model.BlenderBuild) tea.Msg {
+		t.Helper()
+		return checkStableRelease(builds)()
+	}
+
+	// The first fetch only records what's out, bugfix and daily builds are never announced
+	if msg := check(fetched); msg != nil {
+		t.Errorf("Expected no announcement on the first fetch, got %+v", msg)
+	}
+	if msg := check(append(fetched, model.BlenderBuild{Version: "4.2.1", ReleaseCycle: "stable"})); msg != nil {
+		t.Errorf("Expected no announcement of a bugfix release, got %+v", msg)
+	}
+
+	msg := check(append(fetched, release))
+	if msg == nil {
+		t.Fatal("Expected 4.3.0 to be announced")
+	}
+	if again := check(append(fetched, release)); again != nil {
+		t.Errorf("Expected 4.3.0 to be announced only once, got %+v", again)
+	}
+
+	h := NewHarness(cfg, 120, 15).SetBuilds(append(testBuilds(), release)).Send(msg)
+	if frame := h.Frame(); !strings.Contains(frame, "Blender 4.3.0 is out (350.0MB)") {
+		t.Errorf("Expected the announcement banner:\n%s", frame)
+	}
+	_, cmd := h.Model().handleInstallRelease()
+	if cmd == nil {
+		t.Fatal("Expected a command installing the release")
+	}
+	if start, ok := cmd().(startDownloadMsg); !ok || start.build.Version != "4.3.0" {
+		t.Errorf("Expected the release to be downloaded, got %+v", start)
+	}
+	if strings.Contains(h.Frame(), "is out") {
+		t.Errorf("Expected the banner to go once the release is installing:\n%s", h.Frame())
+	}
+
+	h.Send(msg).Keys("esc")
+	if strings.Contains(h.Frame(), "is out") {
+		t.Errorf("Expected esc to dismiss the banner:\n%s", h.Frame())
+	}
+}
+
+func TestExportProgress(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.DownloadDir = t.TempDir()
+	dir := filepath.Join(cfg.DownloadDir, "blender-4.4.1-linux-x64")
+	if err := os.MkdirAll(dir, 0750); err != nil {
+		t.Fatalf("Failed to create build dir: %v", err)
+	}
+	for name, content := range map[string]string{
+		"version.json": `{"version": "4.4.1", "hash": "abcdef012345"}`,
+		"blender":      "#!/bin/sh\n",
+	} {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0755); err != nil {
+			t.Fatalf("Failed to write %s: %v", name, err)
+		}
+	}
+	output := t.TempDir()
+
+	h := NewHarness(cfg, 160, 15).SetBuilds(testBuilds()).Keys("down", "E")
+	if frame := h.Frame(); !strings.Contains(frame, "Export to:") {
+		t.Fatalf("Expected the export input in the footer:\n%s", frame)
+	}
+	m := h.Model()
+	m.exportInput.SetValue(output)
+	_, cmd := m.updateExportInput(KeyMsg("enter"))
+	if cmd == nil {
+		t.Fatal("Expected enter to start the export")
+	}
+
+	// The row shows the export like a download until it is done
+	if frame := h.Send(forceRenderMsg{}).Frame(); !strings.Contains(frame, "Exporting") {
+		t.Errorf("Expected the export in the build's row:\n%s", frame)
+	}
+	archive := filepath.Join(output, "blender-4.4.1-linux-x64.tar.xz")
+	if frame := h.Send(cmd()).Frame(); strings.Contains(frame, "Exporting") {
+		t.Errorf("Expected the export to be done:\n%s", frame)
+	}
+	if frame := h.Frame(); !strings.Contains(frame, "exported to "+archive) {
+		t.Errorf("Expected the archive's path in the footer:\n%s", frame)
+	}
+	if _, err := os.Stat(archive); err != nil {
+		t.Errorf("Expected the archive to be written: %v", err)
+	}
+}
+
+func TestMoveBuild(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.DownloadDir = t.TempDir()
+	writeTestBuild(t, cfg.DownloadDir, `{"version": "4.4.1", "hash": "abcdef012345"}`, "")
+
+	// Without further roots there is nowhere to move to
+	h := NewHarness(cfg, 160, 15).SetBuilds(testBuilds()).Keys("down", "M")
+	if frame := h.Frame(); h.Model().moveBuild != nil || !strings.Contains(frame, "install_roots") {
+		t.Errorf("Expected moving to need install roots:\n%s", frame)
+	}
+
+	cfg.InstallRoots = []string{t.TempDir()}
+	h = NewHarness(cfg, 160, 15).SetBuilds(testBuilds()).Keys("down", "M")
+	if frame := h.Frame(); !strings.Contains(frame, "Move 4.4.1 to: 1 "+cfg.InstallRoots[0]) {
+		t.Fatalf("Expected the install roots in the footer:\n%s", frame)
+	}
+	_, cmd := h.Model().updateMovePrompt(KeyMsg("1"))
+	if cmd == nil {
+		t.Fatal("Expected choosing a root to start the move")
+	}
+	if frame := h.Send(forceRenderMsg{}).Frame(); !strings.Contains(frame, "Moving") {
+		t.Errorf("Expected the move in the build's row:\n%s", frame)
+	}
+
+	done, ok := cmd().(moveDoneMsg)
+	if !ok || done.err != nil {
+		t.Fatalf("Expected the move to succeed, got %+v", done)
+	}
+	if moved := filepath.Join(cfg.InstallRoots[0], "blender-4.4.1-linux-x64"); done.dir != moved {
+		t.Errorf("Expected the build in %s, got %s", moved, done.dir)
+	}
+	if frame := h.Send(done).Frame(); strings.Contains(frame, "Moving") {
+		t.Errorf("Expected the move to be done:\n%s", frame)
+	}
+}
+
+func TestLaunchWithEnvProfile(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.DownloadDir = t.TempDir()
+	dir := writeTestBuild(t, cfg.DownloadDir, `{"version": "4.4.1", "hash": "abcdef012345"}`, "#!/bin/sh\n")
+
+	// Without profiles there is nothing to pick
+	h := NewHarness(cfg, 160, 15).SetBuilds(testBuilds()).Keys("down", "L")
+	if frame := h.Frame(); h.Model().envLaunch != nil || !strings.Contains(frame, "env_profiles") {
+		t.Errorf("Expected launching with a profile to need env_profiles:\n%s", frame)
+	}
+
+	cfg.EnvProfiles = map[string]map[string]string{
+		"nvidia": {"__GL_THREADED_OPTIMIZATIONS": "0"},
+		"hip":    {"HSA_OVERRIDE_GFX_VERSION": "11.0.0"},
+	}
+	h = NewHarness(cfg, 160, 15).SetBuilds(testBuilds()).Keys("down", "L")
+	if frame := h.Frame(); !strings.Contains(frame, "Launch 4.4.1 with: 1 hip · 2 nvidia") {
+		t.Fatalf("Expected the env profiles in the footer:\n%s", frame)
+	}
+	_, cmd := h.Model().updateEnvPrompt(KeyMsg("2"))
+	if cmd == nil {
+		t.Fatal("Expected choosing a profile to launch the build")
+	}
+	if h.Model().envLaunch != nil {
+		t.Error("Expected the prompt to close")
+	}
+	if msg, ok := cmd().(model.BlenderExecMsg); !ok || msg.EnvProfile != "nvidia" {
+		t.Errorf("Expected a launch with the nvidia profile, got %+v", msg)
+	}
+
+	// A configured profile that doesn't exist stops the launch
+	h.Model().config.EnvProfile = "cuda"
+	h.Send(model.BlenderExecMsg{Version: "4.4.1", Executable: filepath.Join(dir, "blender")})
+	if frame := h.Frame(); !strings.Contains(frame, `env_profile "cuda" is not in env_profiles`) {
+		t.Errorf("Expected the unknown profile to be reported:\n%s", frame)
+	}
+}
+
+func TestRecentFiles(t *testing.T) {
+	if runtime.GOOS != "linux" {
+		t.Skip("Blender's user config is only looked up in XDG_CONFIG_HOME on Linux")
+	}
+	configHome := t.TempDir()
+	t.Setenv("XDG_CONFIG_HOME", configHome)
+	cfg := config.DefaultConfig()
+	cfg.DownloadDir = t.TempDir()
+	writeTestBuild(t, cfg.DownloadDir, `{"version": "4.4.1", "hash": "abcdef012345"}`, "#!/bin/sh\n")
+
+	// Files that were moved or deleted since are left out
+	projectDir := t.TempDir()
+	shot020, shot010 := filepath.Join(projectDir, "shot020.blend"), filepath.Join(projectDir, "shot010.blend")
+	for _, file := range []string{shot020, shot010} {
+		if err := os.WriteFile(file, []byte("BLENDER"), 0644); err != nil {
+			t.Fatalf("Failed to write %s: %v", file, err)
+		}
+	}
+	recent := strings.Join([]string{shot020, filepath.Join(projectDir, "gone.blend"), shot010}, "\n") + "\n"
+	userConfig := filepath.Join(configHome, "blender", "4.4", "config")
+	if err := os.MkdirAll(userConfig, 0750); err != nil {
+		t.Fatalf("Failed to create %s: %v", userConfig, err)
+	}
+	if err := os.WriteFile(filepath.Join(userConfig, "recent-files.txt"), []byte(recent), 0644); err != nil {
+		t.Fatalf("Failed to write recent-files.txt: %v", err)
+	}
+
+	h := NewHarness(cfg, 100, 15).SetBuilds(testBuilds()).Keys("down", "O")
+	if h.Model().currentView != viewRecentFiles {
+		t.Fatalf("Expected the recent files view for the local build")
+	}
+	if got := h.Model().recentFiles.files; fmt.Sprint(got) != fmt.Sprint([]string{shot020, shot010}) {
+		t.Errorf("Expected the existing recent files newest first, got %v", got)
+	}
+	if frame := h.Frame(); !strings.Contains(frame, "shot010.blend") || strings.Contains(frame, "gone.blend") {
+		t.Errorf("Expected the existing recent files to be listed:\n%s", frame)
+	}
+
+	_, cmd := h.Keys("down").Model().updateRecentFilesViewController(KeyMsg("enter"))
+	if cmd == nil {
+		t.Fatal("Expected enter to launch the build")
+	}
+	if h.Model().currentView != viewList {
+		t.Errorf("Expected launching to return to the builds list")
+	}
+	if msg, ok := cmd().(model.BlenderExecMsg); !ok || msg.File != shot010 {
+		t.Errorf("Expected a launch opening %s, got %+v", shot010, msg)
+	}
+}
+
+func TestBackgroundLaunch(t *testing.T) {
+	if runtime.GOOS != "linux" {
+		t.Skip("The fake Blender executable is a shell script")
+	}
+	t.Setenv("XDG_STATE_HOME", t.TempDir())
+	cfg := config.DefaultConfig()
+	cfg.DownloadDir = t.TempDir()
+	cfg.LaunchMode = config.LaunchModeBackground
+	exe := filepath.Join(t.TempDir(), "blender")
+	if err := os.WriteFile(exe, []byte("#!/bin/sh\necho 'Segmentation fault'\nexit 139\n"), 0755); err != nil {
+		t.Fatalf("Failed to write the executable: %v", err)
+	}
+
+	h := NewHarness(cfg, 160, 15).SetBuilds(testBuilds())
+	h.Model().commands.logDir = t.TempDir()
+	_, cmd := h.Model().handleBlenderExec(model.BlenderExecMsg{Version: "4.4.1", Executable: exe})
+	started, ok := cmd().(blenderStartedMsg)
+	if !ok {
+		t.Fatal("Expected Blender to start in the background")
+	}
+	_, wait := h.Model().handleBlenderStarted(started)
+	if frame := h.Frame(); !strings.Contains(frame, "▶ Blender 4.4.1") {
+		t.Errorf("Expected the running Blender in the header:\n%s", frame)
+	}
+
+	// The launcher stays open and hears about the crash
+	exited, ok := wait().(blenderExitedMsg)
+	if !ok || exited.err == nil {
+		t.Fatalf("Expected Blender to exit with an error, got %+v", exited)
+	}
+	if frame := h.Send(exited).Frame(); strings.Contains(frame, "▶ Blender") {
+		t.Errorf("Expected the exited Blender to leave the header:\n%s", frame)
+	}
+	if frame := h.Frame(); !strings.Contains(frame, "~ shows its output from "+exited.logPath) {
+		t.Errorf("Expected the log to be pointed at:\n%s", frame)
+	}
+	if h.Keys("~"); h.Model().currentView != viewConsole || h.Model().console.logPath != exited.logPath {
+		t.Errorf("Expected ~ to show the output of the exited Blender")
+	}
+	if log, err := os.ReadFile(exited.logPath); err != nil || !strings.Contains(string(log), "Segmentation fault") {
+		t.Errorf("Expected Blender's output in the log, got %q, %v", log, err)
+	}
+}
+
+func TestLaunchMenu(t *testing.T) {
+	if runtime.GOOS != "linux" {
+		t.Skip("The fake Blender executable is a shell script")
+	}
+	t.Setenv("XDG_STATE_HOME", t.TempDir())
+	cfg := config.DefaultConfig()
+	cfg.DownloadDir = t.TempDir()
+	cfg.LaunchMode = config.LaunchModeBackground
+	writeTestBuild(t, cfg.DownloadDir, `{"version": "4.4.1", "hash": "abcdef012345"}`, "#!/bin/sh\necho \"$@\"\n")
+
+	h := NewHarness(cfg, 160, 15).SetBuilds(testBuilds()).Keys("down", "enter")
+	if frame := h.Frame(); !strings.Contains(frame, "Launch 4.4.1: 1 Normal · 2 Factory startup") {
+		t.Fatalf("Expected the launch presets in the footer:\n%s", frame)
+	}
+	_, cmd := h.Model().updateLaunchMenu(KeyMsg("2"))
+	if cmd == nil {
+		t.Fatal("Expected choosing a preset to launch the build")
+	}
+	msg, ok := cmd().(model.BlenderExecMsg)
+	if !ok || fmt.Sprint(msg.Args) != "[--factory-startup]" {
+		t.Fatalf("Expected a launch with factory settings, got %+v", msg)
+	}
+
+	// The preset's arguments go before the file to open
+	h.Model().commands.logDir = t.TempDir()
+	msg.File = filepath.Join(t.TempDir(), "shot010.blend")
+	_, cmd = h.Model().handleBlenderExec(msg)
+	started, ok := cmd().(blenderStartedMsg)
+	if !ok {
+		t.Fatal("Expected Blender to start in the background")
+	}
+	_, wait := h.Model().handleBlenderStarted(started)
+	exited := wait().(blenderExitedMsg)
+	if log, err := os.ReadFile(exited.logPath); err != nil || strings.TrimSpace(string(log)) != "--factory-startup "+msg.File {
+		t.Errorf("Expected Blender started with the preset and file, got %q, %v", log, err)
+	}
+
+	// Enter right away launches plainly
+	h = NewHarness(cfg, 160, 15).SetBuilds(testBuilds()).Keys("down", "enter")
+	if _, cmd := h.Model().updateLaunchMenu(KeyMsg("enter")); cmd == nil {
+		t.Error("Expected enter to launch the build")
+	} else if msg, ok := cmd().(model.BlenderExecMsg); !ok || len(msg.Args) != 0 {
+		t.Errorf("Expected a plain launch, got %+v", msg)
+	}
+	if h.Model().launchMenu != nil {
+		t.Error("Expected the menu to close")
+	}
+}
+
+func TestLaunchProbedBuild(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("The fake Blender executable is a shell script")
+	}
+	cfg := config.DefaultConfig()
+	cfg.DownloadDir = t.TempDir()
+	dir := filepath.Join(cfg.DownloadDir, "blender-4.4.1-copied")
+	if err := os.MkdirAll(dir, 0750); err != nil {
+		t.Fatalf("Failed to create build dir: %v", err)
+	}
+	// Copied by hand, so only the executable tells which build it is
+	script := "#!/bin/sh\necho 'Blender 4.4.1'\necho 'build hash: abcdef012345'\n"
+	if err := os.WriteFile(filepath.Join(dir, "blender"), []byte(script), 0755); err != nil {
+		t.Fatalf("Failed to write the executable: %v", err)
+	}
+	builds, err := local.ScanLocalBuilds(cfg.Roots()...)
+	if err != nil || len(builds) != 1 {
+		t.Fatalf("Expected the build listed, got %v, %v", builds, err)
+	}
+	if builds[0].Status != model.StateLocal {
+		t.Errorf("Expected the probed build to be local, got %v", builds[0].Status)
+	}
+
+	h := NewHarness(cfg, 160, 15).SetBuilds(builds).Keys("enter")
+	if h.Model().launchMenu == nil {
+		t.Fatalf("Expected enter to offer launching the build:\n%s", h.Frame())
+	}
+	_, cmd := h.Model().updateLaunchMenu(KeyMsg("enter"))
+	if cmd == nil {
+		t.Fatal("Expected enter to launch the build")
+	}
+	if msg, ok := cmd().(model.BlenderExecMsg); !ok || msg.Executable != filepath.Join(dir, "blender") {
+		t.Errorf("Expected a launch of %s, got %+v", filepath.Join(dir, "blender"), msg)
+	}
+}
+
+func TestDeleteToTrash(t *testing.T) {
+	if runtime.GOOS != "linux" {
+		t.Skip("The XDG trash is only used on Linux and BSDs")
+	}
+	dataHome := t.TempDir()
+	t.Setenv("XDG_DATA_HOME", dataHome)
+	cfg := config.DefaultConfig()
+	cfg.DownloadDir = t.TempDir()
+	dir := writeTestBuild(t, cfg.DownloadDir, `{"version": "4.4.1", "hash": "abcdef012345"}`, "")
+
+	h := NewHarness(cfg, 160, 15).SetBuilds(testBuilds()).Keys("down")
+	_, cmd := h.Model().handleDeleteBuild()
+	if msg, ok := cmd().(errMsg); ok {
+		t.Fatalf("Expected the build to be trashed, got %v", msg.err)
+	}
+
+	if _, err := os.Stat(dir); !os.IsNotExist(err) {
+		t.Errorf("Expected the build to be gone from %s", dir)
+	}
+	if _, err := os.Stat(filepath.Join(dataHome, "Trash", "files", "blender-4.4.1-linux-x64", "version.json")); err != nil {
+		t.Errorf("Expected the build in the trash: %v", err)
+	}
+	info, err := os.ReadFile(filepath.Join(dataHome, "Trash", "info", "blender-4.4.1-linux-x64.trashinfo"))
+	if err != nil || !strings.Contains(string(info), "Path="+dir+"\n") {
+		t.Errorf("Expected the trash to know where the build came from, got %q, %v", info, err)
+	}
+}
+
+func TestDeleteWithoutTrash(t *testing.T) {
+	if runtime.GOOS != "linux" {
+		t.Skip("The XDG trash is only used on Linux and BSDs")
+	}
+	// A file where the trash should be keeps it from being created
+	dataHome := filepath.Join(t.TempDir(), "share")
+	if err := os.WriteFile(dataHome, nil, 0644); err != nil {
+		t.Fatalf("Failed to block the trash: %v", err)
+	}
+	t.Setenv("XDG_DATA_HOME", dataHome)
+	cfg := config.DefaultConfig()
+	cfg.DownloadDir = t.TempDir()
+	dir := writeTestBuild(t, cfg.DownloadDir, `{"version": "4.4.1", "hash": "abcdef012345"}`, "")
+
+	h := NewHarness(cfg, 160, 15).SetBuilds(testBuilds()).Keys("down")
+	_, cmd := h.Model().handleDeleteBuild()
+	msg, ok := cmd().(noTrashMsg)
+	if !ok {
+		t.Fatal("Expected to be asked whether to delete for good")
+	}
+	h.Send(msg)
+	if frame := h.Frame(); !strings.Contains(frame, "no trash available") || !strings.Contains(frame, "Delete Blender 4.4.1 for good?") {
+		t.Errorf("Expected the trash failure and the question in the footer:\n%s", frame)
+	}
+
+	// n keeps the build
+	h.Send(KeyMsg("n"))
+	if _, err := os.Stat(dir); err != nil {
+		t.Fatalf("Expected n to keep the build: %v", err)
+	}
+	if frame := h.Frame(); !strings.Contains(frame, "kept Blender 4.4.1") {
+		t.Errorf("Expected the build to be reported kept:\n%s", frame)
+	}
+
+	// y deletes it for good
+	h.Send(msg)
+	_, cmd = h.Model().updateNoTrashPrompt(KeyMsg("y"))
+	if cmd == nil {
+		t.Fatal("Expected y to delete the build")
+	}
+	cmd()
+	if _, err := os.Stat(dir); !os.IsNotExist(err) {
+		t.Errorf("Expected the build to be deleted from %s", dir)
+	}
+}
+
+func TestDuplicateBuilds(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.DownloadDir = t.TempDir()
+	cfg.InstallRoots = []string{t.TempDir()}
+	cfg.DeleteToTrash = false
+	dirs := []string{
+		filepath.Join(cfg.DownloadDir, "blender-4.4.1-linux-x64"),
+		filepath.Join(cfg.DownloadDir, "blender-4.4.1-linux-x64 (copy)"),
+		filepath.Join(cfg.InstallRoots[0], "blender-4.4.1-linux-x64"),
+	}
+	for _, dir := range dirs {
+		if err := os.MkdirAll(dir, 0750); err != nil {
+			t.Fatalf("Failed to create build dir: %v", err)
+		}
+		if err := os.WriteFile(filepath.Join(dir, "version.json"), []byte(`{"version": "4.4.1", "hash": "abcdef012345"}`), 0644); err != nil {
+			t.Fatalf("Failed to write version.json: %v", err)
+		}
+	}
+
+	builds, err := local.ScanLocalBuilds(cfg.Roots()...)
+	if err != nil || len(builds) != 1 {
+		t.Fatalf("Expected the copies listed once, got %v, %v", builds, err)
+	}
+	if len(builds[0].Duplicates) != 2 {
+		t.Fatalf("Expected two duplicates, got %v", builds[0].Duplicates)
+	}
+
+	h := NewHarness(cfg, 160, 15).SetBuilds(builds)
+	if frame := h.Frame(); !strings.Contains(frame, "Local ×3") {
+		t.Errorf("Expected the copies flagged in the row:\n%s", frame)
+	}
+	_, cmd := h.Model().handleRemoveDuplicates()
+	if cmd == nil {
+		t.Fatal("Expected a command removing the copies")
+	}
+	if done := cmd().(duplicatesRemovedMsg); done.err != nil || done.removed != 2 {
+		t.Fatalf("Expected two copies removed, got %+v", done)
+	}
+	if _, err := os.Stat(dirs[0]); err != nil {
+		t.Errorf("Expected the listed copy kept: %v", err)
+	}
+	for _, dir := range dirs[1:] {
+		if _, err := os.Stat(dir); !os.IsNotExist(err) {
+			t.Errorf("Expected %s removed, got %v", dir, err)
+		}
+	}
+
+	// Builds without a hash may differ despite their version, so aren't copies
+	other := filepath.Join(cfg.InstallRoots[0], "blender-4.2.0-custom")
+	for _, dir := range []string{filepath.Join(cfg.DownloadDir, "blender-4.2.0-linux-x64"), other} {
+		if err := os.MkdirAll(dir, 0750); err != nil {
+			t.Fatalf("Failed to create build dir: %v", err)
+		}
+		if err := os.WriteFile(filepath.Join(dir, "version.json"), []byte(`{"version": "4.2.0"}`), 0644); err != nil {
+			t.Fatalf("Failed to write version.json: %v", err)
+		}
+	}
+	builds, err = local.ScanLocalBuilds(cfg.Roots()...)
+	if err != nil {
+		t.Fatalf("ScanLocalBuilds returned an error: %v", err)
+	}
+	// Both are listed rather than one hiding the other
+	listed := 0
+	for _, build := range builds {
+		if build.Version == "4.2.0" {
+			listed++
+			if len(build.Duplicates) > 0 {
+				t.Errorf("Expected no duplicates without a hash, got %v", build.Duplicates)
+			}
+		}
+	}
+	if listed != 2 {
+		t.Errorf("Expected both builds without a hash listed, got %d", listed)
+	}
+}
+
+func TestStreamLocalBuilds(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.DownloadDir = t.TempDir()
+	for i := 0; i < 20; i++ {
+		writeTestBuild(t, cfg.DownloadDir, fmt.Sprintf(`{"version": "4.%d.0", "hash": "%012d"}`, i, i), "")
+	}
+
+	h := NewHarness(cfg, 160, 30)
+	msg := h.Model().commands.StreamLocalBuilds()()
+	batches := 0
+	for {
+		found, ok := msg.(localBuildsFoundMsg)
+		if !ok {
+			break
+		}
+		batches++
+		if found.next == nil {
+			t.Fatal("Expected a command waiting for the next builds")
+		}
+		h.Send(found)
+		msg = found.next()
+	}
+	if batches == 0 {
+		t.Error("Expected builds listed before the scan finished")
+	}
+	if got := len(h.Model().List.Builds); got != 20 {
+		t.Errorf("Expected the streamed builds listed, got %d", got)
+	}
+
+	scanned, ok := msg.(localBuildsScannedMsg)
+	if !ok || scanned.err != nil || len(scanned.builds) != 20 {
+		t.Fatalf("Expected the scan to end with all builds, got %+v", msg)
+	}
+	if got := len(h.Send(scanned).Model().List.Builds); got != 20 {
+		t.Errorf("Expected the finished scan to list each build once, got %d", got)
+	}
+}
+
+func TestOfferPreviousSettings(t *testing.T) {
+	if runtime.GOOS != "linux" {
+		t.Skip("Blender's user files are only looked up in XDG_CONFIG_HOME on Linux")
+	}
+	configHome := t.TempDir()
+	t.Setenv("XDG_CONFIG_HOME", configHome)
+	files := []string{"4.2/config/userpref.blend", "4.2/scripts/addons/node_wrangler.py", "3.6/config/userpref.blend"}
+	for _, name := range files {
+		path := filepath.Join(configHome, "blender", filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0750); err != nil {
+			t.Fatalf("Failed to create %s: %v", filepath.Dir(path), err)
+		}
+		if err := os.WriteFile(path, []byte(name), 0644); err != nil {
+			t.Fatalf("Failed to write %s: %v", path, err)
+		}
+	}
+	cfg := config.DefaultConfig()
+	cfg.DownloadDir = t.TempDir()
+
+	h := NewHarness(cfg, 160, 15).SetBuilds(testBuilds()).Send(downloadCompleteMsg{buildVersion: "4.5.0"})
+	if frame := h.Frame(); !strings.Contains(frame, "copy the settings and add-ons of Blender 4.2?") {
+		t.Fatalf("Expected the offer to copy the 4.2 settings:\n%s", frame)
+	}
+
+	// Other keys still reach the list while the offer waits for an answer
+	if h.Keys("down").Model().List.Cursor != 1 || h.Model().settingsOffer == nil {
+		t.Error("Expected the cursor to move and the offer to stay")
+	}
+	_, cmd := h.Model().updateSettingsOffer(KeyMsg("y"))
+	if cmd == nil {
+		t.Fatal("Expected accepting to copy the settings")
+	}
+	if done := cmd().(settingsCopiedMsg); done.err != nil {
+		t.Fatalf("Expected the settings copied, got %v", done.err)
+	}
+	for _, name := range []string{"4.5/config/userpref.blend", "4.5/scripts/addons/node_wrangler.py"} {
+		if _, err := os.Stat(filepath.Join(configHome, "blender", filepath.FromSlash(name))); err != nil {
+			t.Errorf("Expected %s copied: %v", name, err)
+		}
+	}
+
+	// The series has settings of its own now
+	h.Send(downloadCompleteMsg{buildVersion: "4.5.0"})
+	if h.Model().settingsOffer != nil {
+		t.Error("Expected no offer once the series has settings")
+	}
+}
+
+func TestSuggestEnablingAddons(t *testing.T) {
+	if runtime.GOOS != "linux" {
+		t.Skip("Blender's user files are only looked up in XDG_CONFIG_HOME on Linux")
+	}
+	configHome := t.TempDir()
+	t.Setenv("XDG_CONFIG_HOME", configHome)
+	cfg := config.DefaultConfig()
+	cfg.DownloadDir = t.TempDir()
+	cfg.Addons = []string{"~/addons/studio_tools.zip"}
+	const suggestion = "enable your add-ons in Preferences > Add-ons, Blender 4.5 has no preferences"
+
+	// No preferences of the series enable the add-ons yet
+	h := NewHarness(cfg, 200, 15).SetBuilds(testBuilds()).Send(postInstallDoneMsg{buildVersion: "4.5.0"})
+	if !strings.Contains(h.Model().notice, suggestion) {
+		t.Errorf("Expected enabling the add-ons suggested, got %q", h.Model().notice)
+	}
+
+	// While copying earlier settings is offered, declining suggests it
+	prefs := filepath.Join(configHome, "blender", "4.2", "config", "userpref.blend")
+	if err := os.MkdirAll(filepath.Dir(prefs), 0750); err != nil {
+		t.Fatalf("Failed to create %s: %v", filepath.Dir(prefs), err)
+	}
+	if err := os.WriteFile(prefs, []byte("prefs"), 0644); err != nil {
+		t.Fatalf("Failed to write %s: %v", prefs, err)
+	}
+	h = NewHarness(cfg, 200, 15).SetBuilds(testBuilds()).
+		Send(downloadCompleteMsg{buildVersion: "4.5.0"}).Send(postInstallDoneMsg{buildVersion: "4.5.0"})
+	if h.Model().settingsOffer == nil || h.Model().notice != "" {
+		t.Fatalf("Expected only the offer, got notice %q", h.Model().notice)
+	}
+	if h.Model().updateSettingsOffer(KeyMsg("n")); !strings.Contains(h.Model().notice, suggestion) {
+		t.Errorf("Expected enabling the add-ons suggested after declining, got %q", h.Model().notice)
+	}
+
+	// Preferences of the series keep enabling them
+	if err := local.CopyPreviousSettings("4.2", "4.5.0"); err != nil {
+		t.Fatalf("Failed to copy the settings: %v", err)
+	}
+	h = NewHarness(cfg, 200, 15).SetBuilds(testBuilds()).Send(postInstallDoneMsg{buildVersion: "4.5.0"})
+	if h.Model().notice != "" {
+		t.Errorf("Expected no suggestion with preferences, got %q", h.Model().notice)
+	}
+}
+
+func TestSystemBuilds(t *testing.T) {
+	if runtime.GOOS != "linux" {
+		t.Skip("The fake Blender is a shell script")
+	}
+	t.Setenv("HOME", t.TempDir())
+	cfg := config.DefaultConfig()
+	cfg.DownloadDir = t.TempDir()
+	script := "#!/bin/sh\necho \"Blender 3.6.5\"\necho \"	build hash: 0123456789ab\"\n"
+
+	// A Blender on PATH, and the launcher's own build, which is no system install
+	binDir := t.TempDir()
+	managed := filepath.Join(cfg.DownloadDir, "blender-4.4.1-linux-x64")
+	for _, dir := range []string{binDir, managed} {
+		if err := os.MkdirAll(dir, 0750); err != nil {
+			t.Fatalf("Failed to create %s: %v", dir, err)
+		}
+		if err := os.WriteFile(filepath.Join(dir, "blender"), []byte(script), 0755); err != nil {
+			t.Fatalf("Failed to write the fake Blender: %v", err)
+		}
+	}
+	t.Setenv("PATH", managed+string(os.PathListSeparator)+binDir)
+
+	builds := local.ScanSystemBuilds(cfg.Roots())
+	var found *model.BlenderBuild
+	for i, build := range builds {
+		if build.Executable == filepath.Join(managed, "blender") {
+			t.Errorf("Expected the launcher's own build left out, got %+v", build)
+		}
+		if build.Executable == filepath.Join(binDir, "blender") {
+			found = &builds[i]
+		}
+	}
+	if found == nil || found.Version != "3.6.5" || found.Status != model.StateSystem || found.FileName != "PATH" {
+		t.Fatalf("Expected the Blender on PATH as a system install, got %+v", builds)
+	}
+
+	h := NewHarness(cfg, 160, 15).SetBuilds(testBuilds()).Send(systemBuildsMsg{builds: []model.BlenderBuild{*found}})
+	if frame := h.Frame(); !strings.Contains(frame, "System") {
+		t.Errorf("Expected the system install listed:\n%s", frame)
+	}
+	h.Send(localBuildsScannedMsg{builds: testBuilds()[1:2]})
+	for i, build := range h.Model().List.Builds {
+		if build.Status == model.StateSystem {
+			h.Model().List.Cursor = i
+		}
+	}
+	_, cmd := h.Keys("enter").Model().updateLaunchMenu(KeyMsg("enter"))
+	if cmd == nil {
+		t.Fatal("Expected the system install to launch")
+	}
+	if msg, ok := cmd().(model.BlenderExecMsg); !ok || msg.Executable != found.Executable {
+		t.Errorf("Expected the system executable launched, got %+v", msg)
+	}
+}
+
+func TestPurgeUserConfigs(t *testing.T) {
+	if runtime.GOOS != "linux" {
+		t.Skip("Blender's user files are only looked up in XDG_CONFIG_HOME on Linux")
+	}
+	configHome, dataHome := t.TempDir(), t.TempDir()
+	t.Setenv("XDG_CONFIG_HOME", configHome)
+	t.Setenv("XDG_DATA_HOME", dataHome)
+	for _, name := range []string{"4.4/config/userpref.blend", "3.6/config/userpref.blend"} {
+		path := filepath.Join(configHome, "blender", filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0750); err != nil {
+			t.Fatalf("Failed to create %s: %v", filepath.Dir(path), err)
+		}
+		if err := os.WriteFile(path, []byte(name), 0644); err != nil {
+			t.Fatalf("Failed to write %s: %v", path, err)
+		}
+	}
+	cfg := config.DefaultConfig()
+	cfg.DownloadDir = t.TempDir()
+	cfg.DeleteToTrash = false
+	writeTestBuild(t, cfg.DownloadDir, `{"version": "4.4.1", "hash": "abcdef012345"}`, "")
+
+	// Nothing is picked at first, not even the series without a build
+	h := NewHarness(cfg, 160, 15).SetBuilds(testBuilds())
+	_, cmd := h.Model().handleShowUserConfigs()
+	frame := h.Send(cmd()).Frame()
+	for _, want := range []string{"[ ]", "4.4  installed", "3.6  not installed"} {
+		if !strings.Contains(frame, want) {
+			t.Errorf("Expected %q in the configs view:\n%s", want, frame)
+		}
+	}
+	if strings.Contains(frame, "[x]") {
+		t.Errorf("Expected nothing selected:\n%s", frame)
+	}
+
+	// The purge asks first, n keeps the files
+	if frame := h.Keys("down", " ", "enter").Frame(); !strings.Contains(frame, "Move the settings and add-ons of Blender 3.6") {
+		t.Errorf("Expected to be asked before purging:\n%s", frame)
+	}
+	if _, cmd := h.Model().updateUserConfigsViewController(KeyMsg("n")); cmd != nil || h.Model().userConfigs.confirming {
+		t.Error("Expected n to keep the configs")
+	}
+
+	// y moves them to the trash, even with delete_to_trash off
+	h.Keys("enter")
+	_, cmd = h.Model().updateUserConfigsViewController(KeyMsg("y"))
+	if cmd == nil {
+		t.Fatal("Expected y to purge the selected configs")
+	}
+	if frame := h.Send(cmd()).Frame(); !strings.Contains(frame, "moved the configs of Blender 3.6") {
+		t.Errorf("Expected the purge in the footer:\n%s", frame)
+	}
+	if _, err := os.Stat(filepath.Join(configHome, "blender", "3.6")); !os.IsNotExist(err) {
+		t.Errorf("Expected the config of 3.6 purged, got %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(dataHome, "Trash", "files", "3.6", "config", "userpref.blend")); err != nil {
+		t.Errorf("Expected the config of 3.6 in the trash: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(configHome, "blender", "4.4")); err != nil {
+		t.Errorf("Expected the config of the installed 4.4 kept: %v", err)
+	}
+	if h.Model().currentView != viewList {
+		t.Error("Expected to be back at the builds list")
+	}
+}
+
+func TestLastUsedColumn(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.DownloadDir = t.TempDir()
+	writeTestBuild(t, cfg.DownloadDir, `{"version": "4.4.1", "hash": "abcdef012345"}`, "")
+
+	h := NewHarness(cfg, 160, 15).SetBuilds(testBuilds())
+	if frame := h.Frame(); strings.Contains(frame, "Last Used") {
+		t.Errorf("Expected no Last Used column before any launch:\n%s", frame)
+	}
+
+	// Launches are stored in version.json and shown right away
+	now := time.Now()
+	for i := 0; i < 2; i++ {
+		if err := local.RecordBuildLaunch(cfg.Roots(), "4.4.1", now); err != nil {
+			t.Fatalf("Failed to record launch: %v", err)
+		}
+	}
+	builds, err := local.ScanLocalBuilds(cfg.Roots()...)
+	if err != nil || len(builds) != 1 || builds[0].LaunchCount != 2 || builds[0].LastLaunched == nil {
+		t.Fatalf("Expected two launches recorded, got %+v, %v", builds, err)
+	}
+	h.Send(buildLaunchedMsg{version: "4.4.1", at: now})
+	if frame := h.Frame(); !strings.Contains(frame, "Last Used") || !strings.Contains(frame, "Today") {
+		t.Errorf("Expected the launch in a Last Used column:\n%s", frame)
+	}
+
+	// Sorting by the column puts the builds not used for longest last when descending
+	long := now.AddDate(0, -3, 0)
+	for i := range h.Model().List.Builds {
+		if h.Model().List.Builds[i].Version == "4.2.9" {
+			h.Model().List.Builds[i].LastLaunched = &long
+		}
+	}
+	h.Keys("left")
+	var order []string
+	for _, build := range h.Model().List.Builds {
+		order = append(order, build.Version)
+	}
+	if got := strings.Join(order, " "); got != "4.4.1 4.2.9 4.5.0" {
+		t.Errorf("Expected builds sorted by last use, got %s", got)
+	}
+	if frame := h.Frame(); !strings.Contains(frame, "3 months ago") || !strings.Contains(frame, "Never") {
+		t.Errorf("Expected relative launch times:\n%s", frame)
+	}
+}
+
+func TestCorruptedBuild(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.DownloadDir = t.TempDir()
+	dir := writeTestBuild(t, cfg.DownloadDir, `{"version": "4.4.1", "hash": "abcdef012345", "url": "https://example.com/blender-4.4.1-linux-x64.tar.xz", "file_size": 1}`, "")
+	// The executable listed in the manifest is gone
+	manifest := strings.Repeat("0", 64) + "  blender\n"
+	if err := os.WriteFile(filepath.Join(dir, download.ManifestFilename), []byte(manifest), 0644); err != nil {
+		t.Fatalf("Failed to write manifest: %v", err)
+	}
+	builds, err := local.ScanLocalBuilds(cfg.Roots()...)
+	if err != nil || len(builds) != 1 {
+		t.Fatalf("Expected the build listed, got %v, %v", builds, err)
+	}
+
+	// Launching finds no executable and flags the build
+	h := NewHarness(cfg, 160, 15).SetBuilds(builds)
+	_, cmd := h.Keys("enter").Model().updateLaunchMenu(KeyMsg("enter"))
+	if cmd == nil {
+		t.Fatal("Expected a command launching the build")
+	}
+	h.Send(cmd())
+	if frame := h.Frame(); !strings.Contains(frame, "no Blender executable in") {
+		t.Errorf("Expected the missing executable reported:\n%s", frame)
+	}
+	frame := h.Keys("down").Frame()
+	if !strings.Contains(frame, "Corrupted") || !strings.Contains(frame, "D Re-download") {
+		t.Errorf("Expected the build flagged with a re-download offered:\n%s", frame)
+	}
+
+	// So does verifying it
+	h = NewHarness(cfg, 160, 15).SetBuilds(builds)
+	_, cmd = h.Model().handleVerifyBuild()
+	h.Send(cmd())
+	if !strings.Contains(h.Frame(), "Corrupted") {
+		t.Errorf("Expected a failed verification to flag the build:\n%s", h.Frame())
+	}
+
+	// The bad files stay in the details once the status line is cleared
+	h = NewHarness(cfg, 200, 20).SetBuilds(builds)
+	_, cmd = h.Model().handleVerifyBuild()
+	h.Send(cmd())
+	if frame := h.Keys("down").Frame(); !strings.Contains(frame, "Damaged") || !strings.Contains(frame, "(blender)") {
+		t.Errorf("Expected the bad files listed in the details:\n%s", frame)
+	}
+
+	// Re-downloading replaces the corrupted install
+	_, cmd = h.Model().handleReinstallBuild()
+	if cmd == nil {
+		t.Fatal("Expected a command re-downloading the build")
+	}
+	if msg, ok := cmd().(startDownloadMsg); !ok || !msg.reinstall || !msg.corrupted {
+		t.Errorf("Expected a reinstall replacing a corrupted install, got %+v", msg)
+	}
+}
+
+func TestScanCache(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.DownloadDir = t.TempDir()
+	dir := writeTestBuild(t, cfg.DownloadDir, `{"version": "4.4.1", "hash": "abcdef012345"}`, "")
+	cachePath := filepath.Join(t.TempDir(), local.ScanCacheFilename)
+
+	// stream runs a startup scan, returning the builds listed first
+	stream := func() (first []model.BlenderBuild, h *Harness) {
+		h = NewHarness(cfg, 160, 15)
+		h.Model().commands.scanCachePath = cachePath
+		msg := h.Model().commands.StreamLocalBuilds()()
+		for {
+			found, ok := msg.(localBuildsFoundMsg)
+			if !ok {
+				break
+			}
+			if first == nil {
+				first = found.builds
+			}
+			h.Send(found)
+			msg = found.next()
+		}
+		h.Send(msg)
+		return first, h
+	}
+
+	stream()
+	if _, err := os.Stat(cachePath); err != nil {
+		t.Fatalf("Expected the scan saved: %v", err)
+	}
+
+	// The next start lists the cached build before the scan notices it is gone
+	if err := os.RemoveAll(dir); err != nil {
+		t.Fatalf("Failed to remove build: %v", err)
+	}
+	first, h := stream()
+	if len(first) != 1 || first[0].Version != "4.4.1" || first[0].Status != model.StateLocal {
+		t.Fatalf("Expected the cached build listed first, got %+v", first)
+	}
+	if got := len(h.Model().List.Builds); got != 0 {
+		t.Errorf("Expected the finished scan to drop the removed build, got %d", got)
+	}
+
+	// A cache of other install roots is ignored
+	cfg.InstallRoots = []string{t.TempDir()}
+	if cached := local.LoadScanCache(cachePath, cfg.Roots()); cached != nil {
+		t.Errorf("Expected no cached builds for other roots, got %+v", cached)
+	}
+}
+
+func TestRunningBuildIsKept(t *testing.T) {
+	if runtime.GOOS != "linux" {
+		t.Skip("Processes are told apart by their executable only on Linux")
+	}
+	sleep, err := exec.LookPath("sleep")
+	if err != nil {
+		t.Skip("No sleep to stand in for Blender")
+	}
+	cfg := config.DefaultConfig()
+	cfg.DownloadDir = t.TempDir()
+	dir := writeTestBuild(t, cfg.DownloadDir, `{"version": "4.4.1", "hash": "abcdef012345"}`, "")
+	// A real executable, so the process runs from the build's directory
+	data, err := os.ReadFile(sleep)
+	if err != nil {
+		t.Fatalf("Failed to read %s: %v", sleep, err)
+	}
+	exe := filepath.Join(dir, "blender")
+	if err := os.WriteFile(exe, data, 0755); err != nil {
+		t.Fatalf("Failed to write the executable: %v", err)
+	}
+
+	tests := []struct {
+		name   string
+		start  func(t *testing.T, m *Model) // Makes the build run
+		action func(m *Model) (tea.Model, tea.Cmd)
+	}{
+		{"launched from a terminal", startFromBuild(exe), (*Model).handleDeleteBuild},
+		{"launched in the background", func(t *testing.T, m *Model) {
+			m.running = append(m.running, runningBlender{pid: 4242, version: "4.4.1"})
+		}, (*Model).handleDeleteBuild},
+		{"repair", startFromBuild(exe), (*Model).handleRepairBuild},
+		{"reinstall", startFromBuild(exe), func(m *Model) (tea.Model, tea.Cmd) {
+			return m.handleStartDownloadMsg(startDownloadMsg{build: testBuilds()[1], reinstall: true})
+		}},
+		{"config purge", startFromBuild(exe), func(m *Model) (tea.Model, tea.Cmd) {
+			m.currentView = viewUserConfigs
+			m.userConfigs = userConfigsState{configs: []local.UserConfig{{Series: "4.4", Path: t.TempDir()}}, selected: []bool{true}}
+			m.updateUserConfigsViewController(tea.KeyMsg{Type: tea.KeyEnter})
+			return m.updateUserConfigsViewController(KeyMsg("y"))
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := NewHarness(cfg, 160, 15).SetBuilds(testBuilds()).Keys("down")
+			tt.start(t, h.Model())
+			if _, cmd := tt.action(h.Model()); cmd != nil {
+				t.Error("Expected the running build to be left alone")
+			}
+			if err := h.Model().err; err == nil || !strings.Contains(err.Error(), "4.4.1 is running") {
+				t.Errorf("Expected to be told to quit Blender first, got %v", err)
+			}
+		})
+	}
+
+	// Neither does a cleanup that started before Blender did
+	t.Run("cleanup", func(t *testing.T) {
+		startFromBuild(exe)(t, nil)
+		if _, err := local.RemoveReclaimable([]local.ReclaimableItem{{Kind: local.ReclaimBuild, Name: "4.4.1", Path: dir}}); !errors.Is(err, launch.ErrInUse) {
+			t.Errorf("Expected the cleanup to keep the running build, got %v", err)
+		}
+		if _, err := os.Stat(exe); err != nil {
+			t.Errorf("Expected the running build to stay: %v", err)
+		}
+	})
+
+	// Once Blender quits, the build can go
+	h := NewHarness(cfg, 160, 15).SetBuilds(testBuilds()).Keys("down")
+	if _, cmd := h.Model().handleDeleteBuild(); cmd == nil {
+		t.Errorf("Expected the build to be deleted once Blender quit, got %v", h.Model().err)
+	}
+}
+
+func TestConsoleView(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.DownloadDir = t.TempDir()
+
+	// Only Blender started in the background has output to show
+	h := NewHarness(cfg, 100, 12).SetBuilds(testBuilds()).Keys("down", "~")
+	if h.Model().currentView != viewList {
+		t.Fatal("Expected no console without Blender started in the background")
+	}
+	if err := h.Model().err; err == nil || !strings.Contains(err.Error(), `launch_mode = "background"`) {
+		t.Errorf("Expected to be told to launch in the background, got %v", err)
+	}
+
+	logPath := filepath.Join(t.TempDir(), "blender-4.4.1.log")
+	var output strings.Builder
+	for i := 1; i <= 20; i++ {
+		fmt.Fprintf(&output, "line %d\n", i)
+	}
+	if err := os.WriteFile(logPath, []byte(output.String()), 0644); err != nil {
+		t.Fatalf("Failed to write log: %v", err)
+	}
+	h.Model().running = []runningBlender{{pid: 4242, version: "4.4.1", logPath: logPath}}
+
+	// The console opens at the end of the output
+	_, read := h.Model().Update(KeyMsg("~"))
+	if h.Model().currentView != viewConsole || read == nil {
+		t.Fatal("Expected ~ to open the console")
+	}
+	h.Send(readConsole(logPath)())
+	if got, want := h.Model().scrollOffset, 20-h.Model().consoleOutputHeight(); got != want {
+		t.Errorf("Expected the output read to scroll to %d before rendering, got %d", want, got)
+	}
+	if frame := h.Frame(); !strings.Contains(frame, "line 20") || strings.Contains(frame, "line 1\n") {
+		t.Errorf("Expected the end of the output:\n%s", frame)
+	}
+
+	// New output shows up while following, but not when scrolled up
+	output.WriteString("Traceback (most recent call last):\n")
+	if err := os.WriteFile(logPath, []byte(output.String()), 0644); err != nil {
+		t.Fatalf("Failed to write log: %v", err)
+	}
+	h.Send(readConsole(logPath)())
+	if frame := h.Frame(); !strings.Contains(frame, "Traceback") {
+		t.Errorf("Expected new output to show up:\n%s", frame)
+	}
+	h.Keys("k")
+	if frame := h.Frame(); strings.Contains(frame, "Traceback") || !strings.Contains(frame, "end Follow") {
+		t.Errorf("Expected scrolling up to stop following:\n%s", frame)
+	}
+	h.Keys("end")
+	if frame := h.Frame(); !strings.Contains(frame, "Traceback") {
+		t.Errorf("Expected end to follow the output again:\n%s", frame)
+	}
+
+	h.Keys("~")
+	if h.Model().currentView != viewList {
+		t.Error("Expected ~ to close the console")
+	}
+}
+
+// startFromBuild returns a function running exe until the test ends.
+func startFromBuild(exe string) func(t *testing.T, m *Model) {
+	return func(t *testing.T, m *Model) {
+		cmd := exec.Command(exe, "60")
+		if err := cmd.Start(); err != nil {
+			t.Fatalf("Failed to start %s: %v", exe, err)
+		}
+		t.Cleanup(func() {
+			_ = cmd.Process.Kill()
+			_ = cmd.Wait()
+		})
+	}
+}
+
+func TestKillBuild(t *testing.T) {
+	if runtime.GOOS != "linux" {
+		t.Skip("Processes are told apart by their executable only on Linux")
+	}
+	sleep, err := exec.LookPath("sleep")
+	if err != nil {
+		t.Skip("No sleep to stand in for Blender")
+	}
+	cfg := config.DefaultConfig()
+	cfg.DownloadDir = t.TempDir()
+	dir := writeTestBuild(t, cfg.DownloadDir, `{"version": "4.4.1", "hash": "abcdef012345"}`, "")
+	data, err := os.ReadFile(sleep)
+	if err != nil {
+		t.Fatalf("Failed to read %s: %v", sleep, err)
+	}
+	exe := filepath.Join(dir, "blender")
+	if err := os.WriteFile(exe, data, 0755); err != nil {
+		t.Fatalf("Failed to write the executable: %v", err)
+	}
+
+	// Nothing to kill while Blender doesn't run
+	h := NewHarness(cfg, 160, 15).SetBuilds(testBuilds()).Keys("down", "K")
+	if h.Model().killBuild != nil {
+		t.Fatal("Expected no confirmation while Blender doesn't run")
+	}
+
+	// Two instances run from the build
+	var cmds []*exec.Cmd
+	var exited []chan struct{}
+	for range 2 {
+		cmd := exec.Command(exe, "60")
+		if err := cmd.Start(); err != nil {
+			t.Fatalf("Failed to start %s: %v", exe, err)
+		}
+		done := make(chan struct{})
+		go func() {
+			_ = cmd.Wait()
+			close(done)
+		}()
+		t.Cleanup(func() { _ = cmd.Process.Kill() })
+		cmds, exited = append(cmds, cmd), append(exited, done)
+	}
+
+	// Declining leaves them running
+	h.Keys("K")
+	if frame := h.Frame(); !strings.Contains(frame, "Kill Blender 4.4.1 (PIDs") || !strings.Contains(frame, fmt.Sprint(cmds[1].Process.Pid)) {
+		t.Fatalf("Expected the kill to be confirmed first:\n%s", frame)
+	}
+	h.Keys("n")
+	if h.Model().killBuild != nil {
+		t.Fatal("Expected n to dismiss the confirmation")
+	}
+
+	// Both are stopped within one grace period
+	h.Keys("K")
+	_, kill := h.Model().Update(KeyMsg("y"))
+	if kill == nil {
+		t.Fatal("Expected y to kill Blender")
+	}
+	h.Send(kill())
+	for _, done := range exited {
+		select {
+		case <-done:
+		case <-time.After(killGrace):
+			t.Fatal("Expected Blender to be killed")
+		}
+	}
+	if frame := h.Frame(); !strings.Contains(frame, "killed Blender 4.4.1") {
+		t.Errorf("Expected the kill to be reported:\n%s", frame)
+	}
+}
+
+func TestJobsView(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.DownloadDir = t.TempDir()
+
+	// A fresh launcher opens the launch queue with its form ready to fill in
+	h := NewHarness(cfg, 100, 20).SetBuilds(testBuilds()).Keys("b")
+	if h.Model().currentView != viewJobs {
+		t.Fatalf("Expected b to open the launch queue, got %v", h.Model().currentView)
+	}
+	frame := h.Keys("enter", "s", "c", "enter", "down").Frame()
+	if !strings.Contains(frame, "Blend File") || !strings.Contains(frame, "sc") {
+		t.Errorf("Expected the job form with the typed file:\n%s", frame)
+	}
+	if frame := h.Keys("down", "a").Frame(); !strings.Contains(frame, "select a local build with b") {
+		t.Errorf("Expected adding a job to need a build:\n%s", frame)
+	}
+}
+
+func TestJobStartsLikeLaunch(t *testing.T) {
+	if runtime.GOOS != "linux" {
+		t.Skip("The fake Blender executable is a shell script")
+	}
+	t.Setenv("XDG_STATE_HOME", t.TempDir())
+	cfg := config.DefaultConfig()
+	cfg.DownloadDir = t.TempDir()
+	cfg.EnvProfile = "aces"
+	cfg.EnvProfiles = map[string]map[string]string{"aces": {"OCIO": "/studio/aces.ocio"}}
+	cfg.LaunchTemplate = "env LAUNCHED_BY=template {exe} {args}"
+	script := "#!/bin/sh\necho \"$OCIO $LAUNCHED_BY\" > \"$(dirname \"$0\")/env\"\n"
+	dir := writeTestBuild(t, cfg.DownloadDir, `{"version": "4.4.1", "hash": "abcdef012345"}`, script)
+
+	// The job gets the env profile and goes through the launch template
+	h := NewHarness(cfg, 160, 20).SetBuilds(testBuilds()).Keys("down", "b", "a")
+	var job launch.Job
+	for deadline := time.Now().Add(10 * time.Second); time.Now().Before(deadline); time.Sleep(10 * time.Millisecond) {
+		if job = h.Model().commands.jobs.Jobs()[0]; job.Status != launch.JobPending && job.Status != launch.JobRunning {
+			break
+		}
+	}
+	if job.Status != launch.JobSucceeded {
+		t.Fatalf("Expected the job to finish, got %s: %v", job.Status, job.Err)
+	}
+	if env, err := os.ReadFile(filepath.Join(dir, "env")); err != nil || strings.TrimSpace(string(env)) != "/studio/aces.ocio template" {
+		t.Errorf("Expected the job started like a launch, got %q, %v", env, err)
+	}
+}
+
+func TestSandboxedBuild(t *testing.T) {
+	if runtime.GOOS != "linux" {
+		t.Skip("The fake Blender executable is a shell script")
+	}
+	stateDir := t.TempDir()
+	t.Setenv("XDG_STATE_HOME", stateDir)
+	cfg := config.DefaultConfig()
+	cfg.DownloadDir = t.TempDir()
+	dir := writeTestBuild(t, cfg.DownloadDir, `{"version": "4.4.1", "tags": ["sandbox"]}`, "#!/bin/sh\n")
+	// The sandbox records how it was called instead of running Blender
+	seen := filepath.Join(t.TempDir(), "seen")
+	sandbox := filepath.Join(t.TempDir(), "sandbox.sh")
+	if err := os.WriteFile(sandbox, []byte("#!/bin/sh\necho \"$PWD $*\" >> "+seen+"\n"), 0755); err != nil {
+		t.Fatalf("Failed to write the sandbox: %v", err)
+	}
+	cfg.Sandbox = sandbox + " --bind={project} {exe} {args}"
+	builds := testBuilds()
+	builds[1].Tags = []string{config.SandboxTag}
+
+	// Without a file or launch_dir, the sandbox gets a scratch directory rather
+	// than the launcher's working directory
+	scratch := filepath.Join(stateDir, config.AppName, "sandbox")
+	exe := filepath.Join(dir, "blender")
+	h := NewHarness(cfg, 160, 20).SetBuilds(builds).Keys("down", "b", "a")
+	var job launch.Job
+	for deadline := time.Now().Add(10 * time.Second); time.Now().Before(deadline); time.Sleep(10 * time.Millisecond) {
+		if job = h.Model().commands.jobs.Jobs()[0]; job.Status != launch.JobPending && job.Status != launch.JobRunning {
+			break
+		}
+	}
+	if job.Status != launch.JobSucceeded {
+		t.Fatalf("Expected the job to finish, got %s: %v", job.Status, job.Err)
+	}
+	want := scratch + " --bind=" + scratch + " " + exe + " -b\n"
+	if got, err := os.ReadFile(seen); err != nil || string(got) != want {
+		t.Errorf("Expected the job to run sandboxed as %q, got %q, %v", want, got, err)
+	}
+
+	// So is the check that the build runs
+	os.Remove(seen)
+	h = NewHarness(cfg, 160, 20).SetBuilds(builds).Keys("down")
+	_, cmd := h.Model().handleCheckBuild()
+	if cmd == nil {
+		t.Fatalf("Expected the check to start, got %v", h.Model().err)
+	}
+	cmd()
+	want = scratch + " --bind=" + scratch + " " + exe + " --version --factory-startup -b\n"
+	if got, err := os.ReadFile(seen); err != nil || string(got) != want {
+		t.Errorf("Expected the check to run sandboxed as %q, got %q, %v", want, got, err)
+	}
+
+	// Without a sandbox command, nothing runs unconfined
+	cfg.Sandbox = ""
+	h = NewHarness(cfg, 160, 20).SetBuilds(builds).Keys("down", "b", "a")
+	if jobs := h.Model().commands.jobs.Jobs(); len(jobs) != 0 {
+		t.Errorf("Expected no job without a sandbox command, got %v", jobs)
+	}
+	if frame := h.Frame(); !strings.Contains(frame, "set sandbox in config.toml") {
+		t.Errorf("Expected the missing sandbox command in the footer, got:\n%s", frame)
+	}
+}
+
+func TestCheckBuild(t *testing.T) {
+	if runtime.GOOS != "linux" {
+		t.Skip("The fake Blender executable is a shell script")
+	}
+	cfg := config.DefaultConfig()
+	cfg.DownloadDir = t.TempDir()
+	script := "#!/bin/sh\necho 'blender: error while loading shared libraries: libXi.so.6: cannot open shared object file' >&2\nexit 127\n"
+	writeTestBuild(t, cfg.DownloadDir, `{"version": "4.4.1", "hash": "abcdef012345"}`, script)
+
+	h := NewHarness(cfg, 200, 20).SetBuilds(testBuilds()).Keys("down")
+	_, cmd := h.Model().handleCheckBuild()
+	if cmd == nil {
+		t.Fatalf("Expected the check to start, got %v", h.Model().err)
+	}
+	h.Send(cmd())
+	if frame := h.Frame(); !strings.Contains(frame, "Runs: Fail") || !strings.Contains(frame, "missing library libXi.so.6") {
+		t.Errorf("Expected the failed check with its reason:\n%s", frame)
+	}
+
+	// The reason stays in the details once the status line is cleared
+	frame := h.Keys("up", "down").Frame()
+	if !strings.Contains(frame, "Reason      build does not run: missing library") {
+		t.Errorf("Expected the reason in the details:\n%s", frame)
+	}
+}
+
+func TestRenderJob(t *testing.T) {
+	if runtime.GOOS != "linux" {
+		t.Skip("The fake Blender executable is a shell script")
+	}
+	t.Setenv("XDG_STATE_HOME", t.TempDir())
+	cfg := config.DefaultConfig()
+	cfg.DownloadDir = t.TempDir()
+	// Renders frames like Blender does in the background
+	script := `#!/bin/sh
+echo "$@" > "$(dirname "$0")/args"
+for frame in 2 3 4; do
+	echo "Fra:$frame Mem:12.00M (Peak 14.00M) | Time:00:00.01 | Rendering 1 / 1 samples"
+	echo "Saved: '/tmp/000$frame.png'"
+done
+`
+	dir := writeTestBuild(t, cfg.DownloadDir, `{"version": "4.4.1", "hash": "abcdef012345"}`, script)
+	scene := filepath.Join(t.TempDir(), "my scene.blend")
+	if err := os.WriteFile(scene, []byte("BLENDER"), 0644); err != nil {
+		t.Fatalf("Failed to write the scene: %v", err)
+	}
+
+	h := NewHarness(cfg, 160, 15).SetBuilds(testBuilds()).Keys("down", "A")
+	if frame := h.Frame(); !strings.Contains(frame, "Render with 4.4.1") {
+		t.Fatalf("Expected the render input in the footer:\n%s", frame)
+	}
+	h.Model().renderInput.SetValue(scene + " 2-4")
+	h.Keys("enter")
+	if frame := h.Frame(); !strings.Contains(frame, "rendering my scene.blend as job #1") {
+		t.Fatalf("Expected the queued render in the footer:\n%s", frame)
+	}
+
+	var job launch.Job
+	for deadline := time.Now().Add(10 * time.Second); time.Now().Before(deadline); time.Sleep(10 * time.Millisecond) {
+		if job = h.Model().commands.jobs.Jobs()[0]; job.Status != launch.JobPending && job.Status != launch.JobRunning {
+			break
+		}
+	}
+	if job.Status != launch.JobSucceeded {
+		t.Fatalf("Expected the render to finish, got %s: %v", job.Status, job.Err)
+	}
+	if args, err := os.ReadFile(filepath.Join(dir, "args")); err != nil || strings.TrimSpace(string(args)) != "-b "+scene+" -s 2 -e 4 -a" {
+		t.Errorf("Expected the frames to be rendered in the background, got %q, %v", args, err)
+	}
+	if job.Progress() != "3/3" || job.Frame != 4 {
+		t.Errorf("Expected all 3 frames to be rendered, got %s at frame %d", job.Progress(), job.Frame)
+	}
+}
+
+func TestTmuxLaunch(t *testing.T) {
+	if runtime.GOOS != "linux" {
+		t.Skip("The fake tmux is a shell script")
+	}
+	t.Setenv("XDG_STATE_HOME", t.TempDir())
+	bin := t.TempDir()
+	seen := filepath.Join(bin, "seen")
+	if err := os.WriteFile(filepath.Join(bin, "tmux"), []byte("#!/bin/sh\nprintf '%s\\n' \"$@\" > "+seen+"\n"), 0755); err != nil {
+		t.Fatalf("Failed to write the fake tmux: %v", err)
+	}
+	t.Setenv("PATH", bin+string(os.PathListSeparator)+os.Getenv("PATH"))
+	t.Setenv("TMUX", "/tmp/tmux-1000/default,1234,0")
+	cfg := config.DefaultConfig()
+	cfg.DownloadDir = t.TempDir()
+
+	// The launch mode is picked in the settings
+	h := NewHarness(cfg, 100, 30).Keys("s", "up", "right", "right")
+	if got := h.Model().Settings.LaunchMode; got != config.LaunchModeTmux {
+		t.Fatalf("Expected the tmux launch mode to be picked, got %q", got)
+	}
+	h.Model().config.LaunchMode = config.LaunchModeTmux
+	h.Model().config.EnvProfiles = map[string]map[string]string{"hip": {"HIP_VISIBLE_DEVICES": "0"}}
+
+	_, cmd := h.Model().handleBlenderExec(model.BlenderExecMsg{Version: "4.4.1", Executable: "/opt/blender/blender", EnvProfile: "hip"})
+	if msg := cmd(); msg != nil {
+		if err, ok := msg.(errMsg); ok {
+			t.Fatalf("Launch failed: %v", err.err)
+		}
+	}
+	args, err := os.ReadFile(seen)
+	if err != nil {
+		t.Fatalf("Expected tmux to be run: %v", err)
+	}
+	want := "new-window\n-n\nblender-4.4.1\nexec env 'HIP_VISIBLE_DEVICES=0' '/opt/blender/blender'\n"
+	if string(args) != want {
+		t.Errorf("Expected a tmux window running Blender, got:\n%s", args)
+	}
+}
+
+func TestRenderProgress(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.DownloadDir = t.TempDir()
+	dir := t.TempDir()
+	for _, name := range []string{"scene.blend", "notes.txt", "scenes/"} {
+		path := filepath.Join(dir, name)
+		if strings.HasSuffix(name, "/") {
+			if err := os.Mkdir(path, 0750); err != nil {
+				t.Fatalf("Failed to create %s: %v", path, err)
+			}
+		} else if err := os.WriteFile(path, nil, 0644); err != nil {
+			t.Fatalf("Failed to write %s: %v", path, err)
+		}
+	}
+
+	// Completion offers directories and .blend files alone
+	h := NewHarness(cfg, 160, 15).SetBuilds(testBuilds()).Keys("down", "A")
+	tests := []struct {
+		typed string
+		want  string
+	}{
+		{filepath.Join(dir, "n"), filepath.Join(dir, "n")},
+		{filepath.Join(dir, "sc"), filepath.Join(dir, "scene")},
+		{filepath.Join(dir, "scene."), filepath.Join(dir, "scene.blend")},
+		{filepath.Join(dir, "scenes"), filepath.Join(dir, "scenes") + string(os.PathSeparator)},
+	}
+	for _, tt := range tests {
+		h.Model().renderInput.SetValue(tt.typed)
+		if got := h.Keys("tab").Model().renderInput.Value(); got != tt.want {
+			t.Errorf("Expected %s to complete to %s, got %s", tt.typed, tt.want, got)
+		}
+	}
+	h.Keys("esc")
+
+	// The running render shows its progress in the header
+	if frame := h.Frame(); strings.Contains(frame, "Render") {
+		t.Errorf("Expected no render progress while none runs:\n%s", frame)
+	}
+	h.Model().Jobs.Jobs = []launch.Job{
+		{ID: 1, Version: "4.4.1", Status: launch.JobSucceeded, Frames: 1, FramesDone: 1},
+		{ID: 2, Version: "4.4.1", Status: launch.JobRunning, Frames: 250, FramesDone: 12, Frame: 13, Rendering: true},
+	}
+	if frame := h.Frame(); !strings.Contains(frame, "◷ Render 12/250") {
+		t.Errorf("Expected the render progress in the header:\n%s", frame)
+	}
+}
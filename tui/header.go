@@ -1,16 +1,29 @@
 package tui
 
 import (
+	"strings"
+
 	lp "github.com/charmbracelet/lipgloss"
 )
 
-// renderHeader creates a styled header for the TUI
-func renderHeader(width int) string {
+// renderHeader creates a styled header for the TUI, with an optional badge
+// on the right
+func renderHeader(width int, badge string) string {
 	// Create a bold, centered title
-	return lp.NewStyle().
+	style := lp.NewStyle().
 		Bold(true).
 		Foreground(lp.Color(textColor)). // Use our textColor constant
 		Width(width).
-		Align(lp.Center).
-		Render("TUI Blender Launcher")
+		Align(lp.Center)
+
+	const title = "TUI Blender Launcher"
+	badgeWidth := lp.Width(badge)
+	if badge == "" || badgeWidth+1 > (width-lp.Width(title))/2 {
+		return style.Render(title)
+	}
+
+	// Keep the title centered over the whole width
+	left := (width - lp.Width(title)) / 2
+	right := width - left - lp.Width(title) - badgeWidth - 1
+	return style.Render(strings.Repeat(" ", left)+title+strings.Repeat(" ", right)) + badge + " "
 }
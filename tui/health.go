@@ -0,0 +1,88 @@
+package tui
+
+import (
+	"TUI-Blender-Launcher/api"
+	"sync"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	lp "github.com/charmbracelet/lipgloss"
+)
+
+// healthMsg reports the reachability of the builder and the mirrors.
+type healthMsg struct {
+	builder   api.Health
+	mirrors   bool // Mirrors are configured, so their answers tell about the network
+	mirrorUp  bool // At least one mirror answered, so the network works
+	scheduled bool // Part of the periodic checks, which schedule the next one
+}
+
+// healthTickMsg asks for the next periodic health check.
+type healthTickMsg struct{}
+
+// checkHealth returns a command pinging the builder's API for the configured
+// build type and the mirrors in parallel.
+func (m *Model) checkHealth(scheduled bool) tea.Cmd {
+	builderURL := api.SourceURL(m.config.BuildType)
+	mirrors := m.config.Mirrors
+	return func() tea.Msg {
+		a := api.NewAPI()
+		msg := healthMsg{mirrors: len(mirrors) > 0, scheduled: scheduled}
+		var mu sync.Mutex
+		var wg sync.WaitGroup
+		for _, mirror := range mirrors {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				if a.CheckHealth(mirror) == api.HealthUp {
+					mu.Lock()
+					msg.mirrorUp = true
+					mu.Unlock()
+				}
+			}()
+		}
+		msg.builder = a.CheckHealth(builderURL)
+		wg.Wait()
+		return msg
+	}
+}
+
+// healthCheckInterval returns the configured time between health checks, 0 if disabled.
+func (m *Model) healthCheckInterval() time.Duration {
+	return time.Duration(m.config.HealthCheckInterval) * time.Minute
+}
+
+// handleHealthMsg updates the badge and schedules the next periodic check.
+func (m *Model) handleHealthMsg(msg healthMsg) (tea.Model, tea.Cmd) {
+	m.health = msg
+	if !msg.scheduled || m.healthCheckInterval() <= 0 {
+		return m, nil
+	}
+	return m, tea.Tick(m.healthCheckInterval(), func(time.Time) tea.Msg {
+		return healthTickMsg{}
+	})
+}
+
+// renderHealthBadge renders the reachability of the builder for the header,
+// or "" before the first check. Without an answer, mirrors tell whether the
+// builder or the network is at fault, without mirrors there is no telling.
+func (m *Model) renderHealthBadge() string {
+	var text, color string
+	switch m.health.builder {
+	case api.HealthUp:
+		text, color = "● builder", greenColor
+	case api.HealthDown:
+		text, color = "● builder down", redColor
+	case api.HealthUnreachable:
+		if m.health.mirrorUp {
+			text, color = "● builder unreachable", redColor
+		} else if !m.health.mirrors {
+			text, color = "● builder unreachable (no mirrors)", orangeColor
+		} else {
+			text, color = "● offline", orangeColor
+		}
+	default:
+		return ""
+	}
+	return lp.NewStyle().Foreground(lp.Color(color)).Render(text)
+}
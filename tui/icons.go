@@ -0,0 +1,77 @@
+package tui
+
+import (
+	"TUI-Blender-Launcher/config"
+	"TUI-Blender-Launcher/model"
+	"os"
+	"runtime"
+	"strings"
+)
+
+// iconSet holds the icons starting build rows, one per kind of row.
+type iconSet struct {
+	local    string // Installed
+	online   string // Only available online
+	update   string // Installed with a newer build online
+	download string // Downloading, queued or scheduled
+	broken   string // Failed to download, or failed its integrity check
+	system   string // Installed outside the launcher
+}
+
+// iconSets are the icon styles that can be configured.
+var iconSets = map[string]iconSet{
+	config.RowIconsUnicode: {local: "■", online: "☁", update: "↑", download: "↓", broken: "⚠", system: "□"},
+	config.RowIconsNerd:    {local: "\uf0a0", online: "\uf0c2", update: "\uf062", download: "\uf019", broken: "\uf071", system: "\uf108"}, // Font Awesome glyphs of Nerd Fonts
+	config.RowIconsASCII:   {local: "=", online: "~", update: "^", download: "v", broken: "!", system: "#"},
+}
+
+// rowIcons returns the configured icon set, nil if rows have no icons.
+// Terminals without a UTF-8 locale get the ASCII icons.
+func rowIcons(style string) *iconSet {
+	set, ok := iconSets[style]
+	if !ok {
+		return nil
+	}
+	if style != config.RowIconsASCII && !utf8Locale() {
+		set = iconSets[config.RowIconsASCII]
+	}
+	return &set
+}
+
+// utf8Locale reports whether the terminal's locale is UTF-8. Windows consoles
+// are assumed to be.
+func utf8Locale() bool {
+	if runtime.GOOS == "windows" {
+		return true
+	}
+	for _, name := range []string{"LC_ALL", "LC_CTYPE", "LANG"} {
+		if value := os.Getenv(name); value != "" {
+			value = strings.ToLower(value)
+			return strings.Contains(value, "utf-8") || strings.Contains(value, "utf8")
+		}
+	}
+	return false
+}
+
+// prefix returns the icon starting the row of build followed by a space, or
+// nothing without icons.
+func (s *iconSet) prefix(build model.BlenderBuild, verify string) string {
+	if s == nil {
+		return ""
+	}
+	icon := s.online
+	switch {
+	case build.Status == model.StateFailed || verify == verifyCorrupted || verify == repairFailed || verify == checkFailed:
+		icon = s.broken
+	case build.Status == model.StateUpdate:
+		icon = s.update
+	case build.Status == model.StateLocal:
+		icon = s.local
+	case build.Status == model.StateSystem:
+		icon = s.system
+	case build.Status == model.StateDownloading || build.Status == model.StateExtracting ||
+		build.Status == model.StatePaused || build.Status == model.StateQueued || build.Status == model.StateScheduled:
+		icon = s.download
+	}
+	return icon + " "
+}
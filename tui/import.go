@@ -0,0 +1,104 @@
+package tui
+
+import (
+	"TUI-Blender-Launcher/local"
+	"TUI-Blender-Launcher/model"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// importDoneMsg reports registering a Blender installed outside the launcher.
+type importDoneMsg struct {
+	build model.BlenderBuild
+	err   error
+}
+
+// newImportInput creates the input asking for the Blender to import.
+func newImportInput() textinput.Model {
+	t := textinput.New()
+	t.Prompt = "Import Blender at: "
+	t.Placeholder = "directory or executable, tab completes"
+	t.CharLimit = 256
+	t.Width = 60
+	return t
+}
+
+// handleImportBuild asks for the path of a Blender to import.
+func (m *Model) handleImportBuild() (tea.Model, tea.Cmd) {
+	m.importing = true
+	m.importInput.SetValue("")
+	return m, m.importInput.Focus()
+}
+
+// updateImportInput handles keys while the import input is shown.
+func (m *Model) updateImportInput(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "esc":
+		m.importing = false
+		m.importInput.Blur()
+		return m, nil
+	case "tab":
+		completePath(&m.importInput)
+		return m, nil
+	case "enter":
+		path := strings.TrimSpace(m.importInput.Value())
+		m.importing = false
+		m.importInput.Blur()
+		if path == "" {
+			return m, nil
+		}
+		roots, downloadDir := m.config.Roots(), m.config.DownloadDir
+		return m, func() tea.Msg {
+			build, err := local.ImportBuild(path, roots, downloadDir)
+			return importDoneMsg{build: build, err: err}
+		}
+	}
+
+	var cmd tea.Cmd
+	m.importInput, cmd = m.importInput.Update(msg)
+	return m, cmd
+}
+
+// handleImportDone reports the import and lists the imported build.
+func (m *Model) handleImportDone(msg importDoneMsg) (tea.Model, tea.Cmd) {
+	if msg.err != nil {
+		m.err = fmt.Errorf("import failed: %w", msg.err)
+		return m, nil
+	}
+	m.notice = "imported Blender " + msg.build.Version
+	roots := m.config.Roots()
+	return m, func() tea.Msg {
+		builds, err := local.ScanLocalBuilds(roots...)
+		return libraryChangedMsg{builds: builds, err: err}
+	}
+}
+
+// completePath completes the directory typed into input as far as the
+// matching directories agree.
+func completePath(input *textinput.Model) {
+	matches, err := DirCompletions(input.Value())
+	if err != nil || len(matches) == 0 {
+		return
+	}
+	completion := commonPrefix(matches)
+	if len(matches) == 1 {
+		completion += string(os.PathSeparator)
+	}
+	input.SetValue(completion)
+	input.CursorEnd()
+}
+
+// commonPrefix returns the longest prefix shared by all paths.
+func commonPrefix(paths []string) string {
+	prefix := paths[0]
+	for _, path := range paths[1:] {
+		for !strings.HasPrefix(path, prefix) {
+			prefix = prefix[:len(prefix)-1]
+		}
+	}
+	return prefix
+}
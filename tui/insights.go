@@ -0,0 +1,134 @@
+package tui
+
+import (
+	"TUI-Blender-Launcher/local"
+	"TUI-Blender-Launcher/model"
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	lp "github.com/charmbracelet/lipgloss"
+)
+
+// Months and versions shown in the insights charts
+const (
+	insightsMonths   = 12
+	insightsVersions = 5
+)
+
+// barRow is one labelled bar of an insights chart.
+type barRow struct {
+	label string
+	value float64
+	text  string // Value as shown next to the bar
+}
+
+// handleShowInsights opens the insights view with the recorded history.
+func (m *Model) handleShowInsights() (tea.Model, tea.Cmd) {
+	insights, err := local.LoadInsights()
+	if err != nil {
+		m.err = err
+		return m, nil
+	}
+	m.insights = insights
+	m.currentView = viewInsights
+	m.scrollOffset = 0
+	return m, nil
+}
+
+// renderInsights renders charts of the recorded library size, downloads and
+// launches to fit the given width and height.
+func (m *Model) renderInsights(width, height int) string {
+	titleStyle := lp.NewStyle().Bold(true).Foreground(lp.Color(highlightColor))
+	innerWidth := max(width-2*formPadding, 1)
+
+	var b strings.Builder
+	b.WriteString(titleStyle.Render("Usage insights"))
+	b.WriteString("\n\n")
+
+	if !m.config.Insights && len(m.insights.Days) == 0 {
+		b.WriteString(lp.NewStyle().Width(innerWidth).Render(
+			"Insights are off. Set insights = true in config.toml to record library growth, downloads and launches on this machine. Nothing is uploaded."))
+		return m.renderScrollable(b.String(), height)
+	}
+	if len(m.insights.Days) == 0 {
+		b.WriteString("Nothing recorded yet.")
+		return m.renderScrollable(b.String(), height)
+	}
+
+	months := m.insights.Monthly()
+	months = months[max(len(months)-insightsMonths, 0):]
+
+	var library, downloads []barRow
+	for _, month := range months {
+		library = append(library, barRow{
+			label: month.Month,
+			value: float64(month.LibraryBytes),
+			text:  model.FormatByteSize(month.LibraryBytes),
+		})
+		downloads = append(downloads, barRow{
+			label: month.Month,
+			value: float64(month.DownloadedBytes),
+			text:  fmt.Sprintf("%s (%d)", model.FormatByteSize(month.DownloadedBytes), month.Downloads),
+		})
+	}
+
+	var launches []barRow
+	for _, count := range m.insights.VersionLaunches() {
+		if len(launches) == insightsVersions {
+			break
+		}
+		launches = append(launches, barRow{
+			label: count.Version,
+			value: float64(count.Count),
+			text:  fmt.Sprintf("%d", count.Count),
+		})
+	}
+
+	latest := m.insights.Days[len(m.insights.Days)-1]
+	b.WriteString(titleStyle.Render(fmt.Sprintf("Library size (%d builds now)", latest.Builds)))
+	b.WriteString("\n")
+	b.WriteString(renderBars(library, innerWidth))
+	b.WriteString("\n\n")
+	b.WriteString(titleStyle.Render("Downloaded per month"))
+	b.WriteString("\n")
+	b.WriteString(renderBars(downloads, innerWidth))
+	b.WriteString("\n\n")
+	b.WriteString(titleStyle.Render("Most launched"))
+	b.WriteString("\n")
+	if len(launches) == 0 {
+		b.WriteString("No launches recorded yet.")
+	} else {
+		b.WriteString(renderBars(launches, innerWidth))
+	}
+
+	return m.renderScrollable(b.String(), height)
+}
+
+// renderBars renders rows as a horizontal bar chart, the bars scaled to the
+// largest value and the whole chart fitting width.
+func renderBars(rows []barRow, width int) string {
+	labelWidth, textWidth := 0, 0
+	var maxValue float64
+	for _, row := range rows {
+		labelWidth = max(labelWidth, lp.Width(row.label))
+		textWidth = max(textWidth, lp.Width(row.text))
+		maxValue = max(maxValue, row.value)
+	}
+	barWidth := max(width-labelWidth-textWidth-2, 1)
+
+	barStyle := lp.NewStyle().Foreground(lp.Color(orangeColor))
+	lines := make([]string, 0, len(rows))
+	for _, row := range rows {
+		filled := 0
+		if maxValue > 0 {
+			filled = int(row.value / maxValue * float64(barWidth))
+		}
+		if filled == 0 && row.value > 0 {
+			filled = 1 // Anything recorded stays visible
+		}
+		bar := barStyle.Render(strings.Repeat("█", filled)) + strings.Repeat(" ", barWidth-filled)
+		lines = append(lines, fmt.Sprintf("%-*s %s %s", labelWidth, row.label, bar, row.text))
+	}
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,232 @@
+package tui
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"TUI-Blender-Launcher/launch"
+
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	lp "github.com/charmbracelet/lipgloss"
+)
+
+// JobsModel handles the launch queue view: a small form describing the next
+// headless run and the list of queued/finished jobs.
+type JobsModel struct {
+	Inputs     []textinput.Model // Blend file, extra arguments
+	FocusIndex int               // len(Inputs) means the job list is focused
+	EditMode   bool
+	JobCursor  int
+	Version    string // Build the next job will run with
+	Executable string
+	Sandboxed  bool         // Jobs run through the sandbox command, the build being tagged for it
+	Jobs       []launch.Job // Snapshot refreshed on every tick
+	Style      Style
+	width      int
+	height     int
+}
+
+// NewJobsModel creates a new JobsModel.
+func NewJobsModel(style Style) JobsModel {
+	m := JobsModel{Style: style}
+	m.Inputs = make([]textinput.Model, 2)
+
+	t := textinput.New()
+	t.Placeholder = "path/to/scene.blend (optional)"
+	t.CharLimit = 512
+	t.Width = 50
+	m.Inputs[0] = t
+
+	t = textinput.New()
+	t.Placeholder = "e.g. -f 1 or -a -- --cycles-device CUDA"
+	t.CharLimit = 512
+	t.Width = 50
+	m.Inputs[1] = t
+
+	m.updateFocusStyles()
+	return m
+}
+
+// Init initializes the model.
+func (m JobsModel) Init() tea.Cmd {
+	return nil
+}
+
+// SetSize updates the space available to the jobs view, fitting the inputs
+// to the width
+func (m *JobsModel) SetSize(w, h int) {
+	m.width = w
+	m.height = h
+	for i := range m.Inputs {
+		m.Inputs[i].Width = formInputWidth(w)
+	}
+}
+
+// SetBuild selects the build that the next queued job will run with
+func (m *JobsModel) SetBuild(version, executable string, sandboxed bool) {
+	m.Version = version
+	m.Executable = executable
+	m.Sandboxed = sandboxed
+}
+
+// GetJobValues returns the blend file and parsed arguments from the form
+func (m *JobsModel) GetJobValues() (file string, args []string) {
+	return strings.TrimSpace(m.Inputs[0].Value()), strings.Fields(m.Inputs[1].Value())
+}
+
+// SelectedJob returns the highlighted job, or nil if the job list isn't focused
+func (m *JobsModel) SelectedJob() *launch.Job {
+	if m.FocusIndex != len(m.Inputs) || m.JobCursor < 0 || m.JobCursor >= len(m.Jobs) {
+		return nil
+	}
+	return &m.Jobs[m.JobCursor]
+}
+
+// View returns the string representation of the model.
+func (m JobsModel) View() string {
+	var b strings.Builder
+
+	effectiveWidth := m.width
+	if effectiveWidth <= 0 {
+		effectiveWidth = 80 // Fallback
+	}
+	// Width inside the form padding
+	innerWidth := max(effectiveWidth-2*formPadding, 1)
+
+	labelStyle := lp.NewStyle().Bold(true).Foreground(lp.Color(highlightColor)).Width(innerWidth)
+	labelFocusedStyle := lp.NewStyle().Bold(true).Background(lp.Color(highlightColor)).Foreground(lp.Color(backgroundColor)).Width(innerWidth)
+	inputStyle := lp.NewStyle().MarginLeft(2).Width(max(innerWidth-2, 1))
+	descStyle := lp.NewStyle().Italic(true).Foreground(lp.Color("241")).Width(innerWidth)
+
+	// Line of the focused label or job, kept visible when the terminal is short
+	focusLine := 0
+	renderLabel := func(index int, label string) string {
+		if m.FocusIndex == index {
+			focusLine = strings.Count(b.String(), "\n")
+			return labelFocusedStyle.Render(label)
+		}
+		return labelStyle.Render(label)
+	}
+
+	build := m.Version
+	if build == "" {
+		build = "none (press b on a local build)"
+	}
+	b.WriteString(labelStyle.Render("Build: " + build))
+	b.WriteString("\n\n")
+
+	b.WriteString(renderLabel(0, "Blend File"))
+	b.WriteString("\n")
+	b.WriteString(inputStyle.Render(m.Inputs[0].View()))
+	b.WriteString("\n")
+	b.WriteString(descStyle.Render("Opened in background mode (-b)."))
+	b.WriteString("\n\n")
+
+	b.WriteString(renderLabel(1, "Arguments"))
+	b.WriteString("\n")
+	b.WriteString(inputStyle.Render(m.Inputs[1].View()))
+	b.WriteString("\n")
+	b.WriteString(descStyle.Render("Passed after the file, e.g. render flags or a --python script."))
+	b.WriteString("\n\n")
+
+	b.WriteString(renderLabel(len(m.Inputs), "Jobs (run one after another)"))
+	b.WriteString("\n")
+	if len(m.Jobs) == 0 {
+		b.WriteString(descStyle.Render("No jobs queued."))
+	}
+	for i, job := range m.Jobs {
+		file := filepath.Base(job.File)
+		if job.File == "" {
+			file = "-"
+		}
+		status := job.Status.String()
+		if progress := job.Progress(); progress != "" && job.Status == launch.JobRunning {
+			status = progress
+		}
+		line := fmt.Sprintf("#%-3d %-10s %-9s %-8s %-24s %s",
+			job.ID, job.Version, status, job.Duration().Truncate(1e9), file, job.LogPath)
+
+		style := m.Style.RegularRow
+		if job.Status == launch.JobFailed || job.Status == launch.JobCancelled {
+			style = lp.NewStyle().Foreground(lp.Color(redColor))
+		}
+		if m.FocusIndex == len(m.Inputs) && i == m.JobCursor {
+			style = m.Style.SelectedRow
+			focusLine = strings.Count(b.String(), "\n")
+		}
+		b.WriteString(style.Width(innerWidth).MaxWidth(innerWidth).Render(line))
+		if i < len(m.Jobs)-1 {
+			b.WriteString("\n")
+		}
+	}
+
+	view := lp.NewStyle().Width(effectiveWidth).Padding(1, formPadding).Render(b.String())
+	return clipLines(view, m.height, focusLine+1)
+}
+
+// Update handles update messages for the jobs model.
+func (m *JobsModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	keyMsg, ok := msg.(tea.KeyMsg)
+	if !ok {
+		return m, nil
+	}
+
+	for _, cmd := range GetCommandsForView(viewJobs) {
+		if !key.Matches(keyMsg, GetKeyBinding(cmd.Type)) {
+			continue
+		}
+		switch cmd.Type {
+		case CmdToggleEditMode:
+			if m.FocusIndex < len(m.Inputs) {
+				m.EditMode = !m.EditMode
+				m.updateFocusStyles()
+				return m, nil
+			}
+
+		case CmdMoveUp:
+			if !m.EditMode {
+				if m.FocusIndex == len(m.Inputs) && m.JobCursor > 0 {
+					m.JobCursor--
+				} else if m.FocusIndex > 0 {
+					m.FocusIndex--
+				}
+				m.updateFocusStyles()
+				return m, nil
+			}
+
+		case CmdMoveDown:
+			if !m.EditMode {
+				if m.FocusIndex < len(m.Inputs) {
+					m.FocusIndex++
+				} else if m.JobCursor < len(m.Jobs)-1 {
+					m.JobCursor++
+				}
+				m.updateFocusStyles()
+				return m, nil
+			}
+		}
+	}
+
+	// Pass input to text fields
+	if m.EditMode && m.FocusIndex < len(m.Inputs) {
+		var cmd tea.Cmd
+		m.Inputs[m.FocusIndex], cmd = m.Inputs[m.FocusIndex].Update(keyMsg)
+		return m, cmd
+	}
+	return m, nil
+}
+
+func (m *JobsModel) updateFocusStyles() {
+	for i := range m.Inputs {
+		if i == m.FocusIndex && m.EditMode {
+			m.Inputs[i].Focus()
+			m.Inputs[i].TextStyle = m.Style.SelectedRow
+		} else {
+			m.Inputs[i].Blur()
+			m.Inputs[i].TextStyle = m.Style.RegularRow
+		}
+	}
+}
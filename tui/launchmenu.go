@@ -0,0 +1,101 @@
+package tui
+
+import (
+	"TUI-Blender-Launcher/launch"
+	"TUI-Blender-Launcher/model"
+	"fmt"
+	"runtime"
+	"slices"
+	"strconv"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// handleShowLaunchMenu asks which launch preset to start the selected build
+// with.
+func (m *Model) handleShowLaunchMenu() (tea.Model, tea.Cmd) {
+	build := m.List.GetSelectedBuild()
+	if build == nil || (build.Status != model.StateLocal && build.Status != model.StateUpdate && build.Status != model.StateSystem) {
+		return m, nil
+	}
+	selected := *build
+	m.launchMenu, m.launchIntegrated, m.launchDisplay = &selected, false, ""
+	return m, nil
+}
+
+// canPickDisplay tells whether a launch can be forced onto X11 or Wayland,
+// which only Linux has both of.
+func canPickDisplay() bool {
+	return runtime.GOOS == "linux"
+}
+
+// nextDisplay returns the display server after display in launch.Displays,
+// Blender's own choice after the last.
+func nextDisplay(display string) string {
+	i := slices.Index(launch.Displays, display)
+	if i+1 == len(launch.Displays) {
+		return ""
+	}
+	return launch.Displays[i+1]
+}
+
+// displayNames are the display servers as the launch menu shows them.
+var displayNames = map[string]string{launch.DisplayX11: "X11", launch.DisplayWayland: "Wayland"}
+
+// updateLaunchMenu handles keys while the launch presets are shown: enter
+// launches plainly, a preset's number with that preset. With gpu_offload set,
+// g switches between the discrete and the integrated GPU. On Linux, w cycles
+// through forcing X11, forcing Wayland and leaving it to Blender.
+func (m *Model) updateLaunchMenu(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	choice := 1
+	switch msg.String() {
+	case "esc":
+		m.launchMenu = nil
+		return m, nil
+	case "g":
+		if m.config.GPUOffload != "" {
+			m.launchIntegrated = !m.launchIntegrated
+		}
+		return m, nil
+	case "w":
+		if canPickDisplay() {
+			m.launchDisplay = nextDisplay(m.launchDisplay)
+		}
+		return m, nil
+	case "enter":
+	default:
+		var err error
+		choice, err = strconv.Atoi(msg.String())
+		if err != nil || choice < 1 || choice > len(launch.Presets) {
+			return m, nil
+		}
+	}
+	build := *m.launchMenu
+	m.launchMenu = nil
+	return m, m.launchBuild(build, model.BlenderExecMsg{
+		Args:       launch.Presets[choice-1].Args,
+		Integrated: m.launchIntegrated,
+		Display:    m.launchDisplay,
+	})
+}
+
+// renderLaunchMenu renders the launch presets, numbered for choosing one.
+func (m *Model) renderLaunchMenu() string {
+	presets := make([]string, len(launch.Presets))
+	for i, preset := range launch.Presets {
+		presets[i] = fmt.Sprintf("%d %s", i+1, preset.Name)
+	}
+	gpu := ""
+	if m.config.GPUOffload != "" {
+		gpu = " on the discrete GPU"
+		if m.launchIntegrated {
+			gpu = " on the integrated GPU"
+		}
+	}
+	display := ""
+	if m.launchDisplay != "" {
+		display = " under " + displayNames[m.launchDisplay]
+	}
+	return fmt.Sprintf("Launch %s%s%s: %s", m.launchMenu.Version, gpu, display, strings.Join(presets, " · "))
+}
@@ -17,6 +17,8 @@ type ListModel struct {
 	TerminalHeight  int
 	Style           Style // Keep Style here as well if needed for List specific rendering
 	LastRenderState map[string]float64
+	CustomColumns   []customColumn  // User-defined columns shown after the built-in ones
+	Marked          map[string]bool // Builds marked for downloading together, by download ID
 }
 
 // NewListModel creates a new ListModel.
@@ -27,6 +29,7 @@ func NewListModel(style Style) ListModel {
 		Style:           style,
 		Builds:          []model.BlenderBuild{},
 		LastRenderState: make(map[string]float64),
+		Marked:          make(map[string]bool),
 	}
 }
 
@@ -171,8 +174,8 @@ func (m *ListModel) EnsureCursorVisible() {
 
 // UpdateSortColumn changes the sort column
 func (m *ListModel) UpdateSortColumn(direction string) {
-	// Total columns: Version, Status, Branch, Type, Hash, Size, Build Date
-	numColumns := 7
+	// Built-in columns followed by the custom ones
+	numColumns := builtinColumnCount + len(m.Columns())
 
 	if direction == "left" {
 		m.SortColumn--
@@ -189,9 +192,71 @@ func (m *ListModel) UpdateSortColumn(direction string) {
 
 // SortBuilds sorts the build list
 func (m *ListModel) SortBuilds() {
+	columns := m.Columns()
+	if custom := m.SortColumn - builtinColumnCount; custom >= 0 && custom < len(columns) {
+		m.Builds = model.SortBuildsByKey(m.Builds, columns[custom].SortKey, m.SortReversed)
+		return
+	} else if custom >= len(columns) {
+		m.SortColumn = 0 // The tags or last used column went away
+	}
 	m.Builds = model.SortBuilds(m.Builds, m.SortColumn, m.SortReversed)
 }
 
+// Columns returns the columns shown after the built-in ones: the custom
+// columns, then the last used column once any build has been launched and
+// the tags column while any build is tagged or a favorite.
+func (m *ListModel) Columns() []customColumn {
+	var launched, tagged bool
+	for _, build := range m.Builds {
+		launched = launched || build.LastLaunched != nil
+		tagged = tagged || len(build.Tags) > 0 || build.Favorite
+	}
+	columns := m.CustomColumns[:len(m.CustomColumns):len(m.CustomColumns)]
+	if launched {
+		columns = append(columns, lastUsedColumn)
+	}
+	if tagged {
+		columns = append(columns, tagsColumn)
+	}
+	return columns
+}
+
+// SelectNewest moves the cursor to the build with the most recent build date
+func (m *ListModel) SelectNewest() {
+	newest := -1
+	for i, build := range m.Builds {
+		if newest < 0 || build.BuildDate.Time().After(m.Builds[newest].BuildDate.Time()) {
+			newest = i
+		}
+	}
+	if newest >= 0 {
+		m.Cursor = newest
+	}
+}
+
+// ToggleMark marks the selected build for downloading, or unmarks it, and
+// moves the cursor on so several builds can be marked in a row.
+func (m *ListModel) ToggleMark() {
+	build := m.GetSelectedBuild()
+	if build == nil {
+		return
+	}
+	id := downloadID(*build)
+	if m.Marked[id] {
+		delete(m.Marked, id)
+	} else {
+		m.Marked[id] = true
+	}
+	if m.Cursor < len(m.Builds)-1 {
+		m.UpdateCursor("down", m.GetVisibleRowsCount())
+	}
+}
+
+// ClearMarks unmarks all builds.
+func (m *ListModel) ClearMarks() {
+	clear(m.Marked)
+}
+
 // GetSelectedBuild returns the currently selected build, or nil if none
 func (m *ListModel) GetSelectedBuild() *model.BlenderBuild {
 	if len(m.Builds) > 0 && m.Cursor >= 0 && m.Cursor < len(m.Builds) {
@@ -1,8 +1,12 @@
 package tui
 
 import (
+	"TUI-Blender-Launcher/download"
+	"TUI-Blender-Launcher/launch"
 	"TUI-Blender-Launcher/model"
 	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
 )
 
 // Define messages for communication between components
@@ -14,8 +18,13 @@ type (
 		err    error // Add error field
 	}
 	localBuildsScannedMsg struct { // Initial local scan complete
+		builds      []model.BlenderBuild
+		interrupted []download.InterruptedDownload // Downloads that stopped before installing
+		err         error                          // Include error from scanning
+	}
+	localBuildsFoundMsg struct { // Local builds found by a scan still running
 		builds []model.BlenderBuild
-		err    error // Include error from scanning
+		next   tea.Cmd // Waits for the next builds found, or the end of the scan
 	}
 	buildsUpdatedMsg struct { // Builds list updated (e.g., status change)
 		builds []model.BlenderBuild
@@ -23,17 +32,63 @@ type (
 
 	// Action messages
 	startDownloadMsg struct { // Request to start download for a build
-		build   model.BlenderBuild
-		buildID string // Added unique build identifier
+		build     model.BlenderBuild
+		reinstall bool // Download an installed build again, ignoring kept archives
+		corrupted bool // The reinstall replaces a corrupted install
 	}
 	downloadCompleteMsg struct { // Download & extraction finished
 		buildVersion  string // Version of the build that finished
 		extractedPath string
 		err           error
 	}
+	postInstallDoneMsg struct { // Add-ons and post-install hook of a new build finished
+		buildVersion string
+		err          error // The build is installed even if they failed
+	}
+	prefetchCompleteMsg struct { // Background prefetch finished
+		build model.BlenderBuild
+		err   error
+	}
+	scheduledDownloadDueMsg struct { // A scheduled download is due to start
+		build model.BlenderBuild
+	}
+	verifyCompleteMsg struct { // Integrity check of an installed build finished
+		buildID string
+		err     error
+	}
+	repairCompleteMsg struct { // Re-extraction of an installed build finished
+		buildID string
+		err     error
+	}
+	checkCompleteMsg struct { // Test run of a build's executable finished
+		buildID string
+		err     error
+	}
+	launchFailedMsg struct { // An installed build couldn't be launched
+		buildID string
+		err     error
+	}
+	buildLaunchedMsg struct { // Blender was started, recorded in its version.json
+		version string
+		at      time.Time
+	}
+	blenderStartedMsg struct { // Blender was started in the background
+		version  string
+		process  *launch.Detached
+		launched *buildLaunchedMsg // Nil unless the launch was recorded
+	}
+	blenderExitedMsg struct { // Blender started in the background exited
+		pid     int
+		version string
+		logPath string
+		err     error
+	}
 	// Error message
 	errMsg struct{ err error }
 
+	// Outcome of a background action worth reporting, shown like errors
+	noticeMsg string
+
 	// Timer message
 	tickMsg time.Time
 
@@ -2,13 +2,22 @@ package tui
 
 import (
 	"TUI-Blender-Launcher/config"
+	"TUI-Blender-Launcher/local"
+	"TUI-Blender-Launcher/model"
+	"time"
+
+	"github.com/charmbracelet/bubbles/textinput"
 )
 
 // Model represents the state of the TUI application.
 type Model struct {
 	config   config.Config
 	commands *Commands
-	err      error
+
+	// Outcome of the last action, shown in the footer until the next key:
+	// err when it failed, notice when there is something to report
+	err    error
+	notice string
 
 	// Layout
 	terminalWidth  int
@@ -16,11 +25,134 @@ type Model struct {
 
 	// Application State
 	currentView viewState
+	lastInput   time.Time // Last key press, used to detect idleness
+
+	// Tick loop, stopped while idle. tickDue is when the scheduled tick fires;
+	// ticks arriving earlier were superseded and are dropped.
+	ticking bool
+	tickDue time.Time
+
+	// Archives the prefetcher already tried this session
+	prefetchAttempted map[string]bool
+
+	// Integrity check and repair results shown in the status column, by build
+	// ID, and why a build failed them, shown in its details
+	verifyResults map[string]string
+	verifyErrors  map[string]error
+
+	// Scroll position of the changelog and insights views
+	scrollOffset int
+
+	// Usage history shown in the insights view, loaded when it is opened
+	insights local.Insights
+
+	// Free bytes in the download directory while below the low disk threshold, else 0
+	lowDiskFree int64
+	cleanup     cleanupState
+
+	// Programs shipped in the build whose executables view is open
+	executables executablesState
+
+	// Files in the recent files list of the build whose recent files view is open
+	recentFiles recentFilesState
+
+	// Blender's user files per series, shown in their view
+	userConfigs userConfigsState
+
+	// Last reachability check of the builder, shown as a badge in the header
+	health healthMsg
+
+	// Leftovers in .downloading found at startup that can't be resumed
+	staleFiles []string
+	staleSize  int64
+
+	// Input asking when to start a scheduled download, shown in the footer while scheduling
+	scheduleInput textinput.Model
+	scheduling    bool
+	scheduleErr   string
+
+	// Input editing the selected build's tags or the tag filter, shown in the
+	// footer while tagPrompt says which
+	tagInput  textinput.Model
+	tagPrompt tagPrompt
+	tagFilter string // Only builds with this tag are listed, "favorite" lists favorites
+
+	// Input asking for a Blender installed elsewhere to import, shown in the footer while importing
+	importInput textinput.Model
+	importing   bool
+
+	// Input asking where to export exportBuild to, shown in the footer while it is set
+	exportInput textinput.Model
+	exportBuild *model.BlenderBuild
+
+	// Input asking which file renderBuild should render, shown in the footer while it is set
+	renderInput textinput.Model
+	renderBuild *model.BlenderBuild
+
+	// Build asked to move to one of moveTargets, the other install roots, shown
+	// in the footer while it is set
+	moveBuild   *model.BlenderBuild
+	moveTargets []string
+
+	// Build asked to launch with one of the env profiles, shown in the footer
+	// while it is set
+	envLaunch *model.BlenderBuild
+
+	// Build asked to launch with one of the launch presets, shown in the
+	// footer while it is set, whether it stays on the integrated GPU and the
+	// display server it is forced onto
+	launchMenu       *model.BlenderBuild
+	launchIntegrated bool
+	launchDisplay    string
+
+	// Offer to copy Blender's user files to the series of a new build, shown
+	// in the footer while it is set
+	settingsOffer *settingsOffer
+
+	// Running Blender asked to be killed, shown in the footer while it is set
+	killBuild *killTarget
+
+	// Removal the trash couldn't take, asked to be deleted for good in the
+	// footer while it is set
+	noTrash *noTrashPrompt
+
+	// Progress of the running exports and moves by build ID, as of the end of the last update
+	transfers map[string]transferProgress
+
+	// Set while a fetch is in flight and the newest build should be selected afterwards
+	selectNewestPending bool
+
+	// Blender started in the background and still running, shown in the header
+	running []runningBlender
+
+	// Blender started in the background last, whether or not it still runs,
+	// and the output shown in the console view
+	lastStarted runningBlender
+	console     consoleState
+
+	// Blender installed outside the launcher, listed as read-only rows
+	systemBuilds []model.BlenderBuild
+
+	// Builds of the last successful fetch, to work out statuses again when
+	// builds are added or removed outside the launcher
+	onlineBuilds []model.BlenderBuild
+
+	// Build the current symlink points at, by build ID
+	defaultBuild string
+
+	// New stable release announced above the list until installed or dismissed
+	stableRelease *model.BlenderBuild
+
+	// Fingerprint of the install roots at the last check, and the build to
+	// select again once the list is rebuilt after they changed
+	libraryFingerprint string
+	keepSelection      string
 
 	// Sub-models
 	List     ListModel
 	Settings SettingsModel
 	Progress ProgressModel
+	Jobs     JobsModel
 
 	Style Style
 }
@@ -30,12 +162,30 @@ func InitialModel(cfg config.Config, needsSetup bool) *Model {
 	style := NewStyle()
 
 	m := &Model{
-		config:   cfg,
-		commands: NewCommands(cfg),
-		List:     NewListModel(style),
-		Settings: NewSettingsModel(cfg, style),
-		Progress: NewProgressModel(),
-		Style:    style,
+		config:    cfg,
+		commands:  NewCommands(cfg),
+		List:      NewListModel(style),
+		Settings:  NewSettingsModel(cfg, style),
+		Progress:  NewProgressModel(),
+		Jobs:      NewJobsModel(style),
+		Style:     style,
+		lastInput: time.Now(),
+
+		scheduleInput:     newScheduleInput(),
+		tagInput:          newTagInput(),
+		importInput:       newImportInput(),
+		exportInput:       newExportInput(),
+		renderInput:       newRenderInput(),
+		prefetchAttempted: make(map[string]bool),
+		verifyResults:     make(map[string]string),
+		verifyErrors:      make(map[string]error),
+	}
+
+	// A broken column template shouldn't keep the launcher from starting
+	if columns, err := compileColumns(cfg.Columns); err != nil {
+		m.err = err
+	} else {
+		m.List.CustomColumns = columns
 	}
 
 	if needsSetup {
@@ -55,42 +205,41 @@ func (m *Model) UpdateWindowSize(width, height int) {
 	m.terminalHeight = height
 
 	m.List.TerminalHeight = height
-	m.Settings.SetWidth(width)
+	m.Settings.SetSize(width, m.contentHeight())
+	m.Jobs.SetSize(width, m.contentHeight())
+	if m.currentView == viewConsole {
+		m.clampConsole()
+	}
 }
 
-// SyncDownloadStates ensures the model has the latest download states from the commands manager
+// SyncDownloadStates snapshots the download states of the commands manager,
+// and the progress of exports and moves, into the model. Rendering only reads
+// the snapshot, as they all update their managers from their own
+// goroutines.
 func (m *Model) SyncDownloadStates() {
 	if m.commands == nil || m.commands.downloads == nil {
 		return
 	}
 
-	// Get all states from the download manager
-	states := m.commands.downloads.GetAllStates()
-	if states == nil {
-		return
-	}
-
-	// Update our local copy of states
-	m.Progress.SyncDownloadStates(states)
+	m.Progress.SyncDownloadStates(m.commands.downloads.GetAllStates())
+	m.transfers = m.commands.transfers.Progress()
 }
 
 // SaveSettings saves the current settings to the configuration file
 func (m *Model) SaveSettings() error {
 	// Update config values from settings inputs
-	downloadDir, versionFilter, buildType := m.Settings.GetValues()
+	downloadDir, versionFilter, buildType, launchMode := m.Settings.GetValues()
 
 	m.config.DownloadDir = downloadDir
 	m.config.VersionFilter = versionFilter
 	m.config.BuildType = buildType
+	m.config.LaunchMode = launchMode
 
 	// Save the config
 	return config.SaveConfig(m.config)
 }
 
 func (m *Model) View() string {
-	// Sync download states before rendering
-	m.SyncDownloadStates()
-
 	// Render the page using the custom render function.
 	return m.renderPageForView()
 }
@@ -0,0 +1,121 @@
+package tui
+
+import (
+	"TUI-Blender-Launcher/download"
+	"TUI-Blender-Launcher/local"
+	"TUI-Blender-Launcher/model"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// moveDoneMsg reports the end of moving a build to another install root.
+type moveDoneMsg struct {
+	buildID string
+	dir     string
+	err     error
+}
+
+// handleMoveBuild asks which install root to move the selected build to.
+func (m *Model) handleMoveBuild() (tea.Model, tea.Cmd) {
+	build := m.List.GetSelectedBuild()
+	if build == nil || (build.Status != model.StateLocal && build.Status != model.StateUpdate) {
+		return m, nil
+	}
+	if _, running := m.transfers[downloadID(*build)]; running {
+		return m, nil
+	}
+	if err := m.checkNotRunning(build.Version, "moving it"); err != nil {
+		m.err = err
+		return m, nil
+	}
+	roots := m.config.Roots()
+	if len(roots) < 2 {
+		m.err = errors.New("add install_roots to move builds between them")
+		return m, nil
+	}
+	dir, err := local.FindBuildDir(roots, build.Version)
+	if err != nil {
+		m.err = err
+		return m, nil
+	}
+
+	m.moveTargets = nil
+	for _, root := range roots {
+		if filepath.Clean(root) != filepath.Clean(filepath.Dir(dir)) {
+			m.moveTargets = append(m.moveTargets, root)
+		}
+	}
+	selected := *build
+	m.moveBuild = &selected
+	return m, nil
+}
+
+// updateMovePrompt handles keys while the install roots to move to are shown:
+// a root's number starts the move.
+func (m *Model) updateMovePrompt(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	if msg.String() == "esc" {
+		m.moveBuild = nil
+		return m, nil
+	}
+	choice, err := strconv.Atoi(msg.String())
+	if err != nil || choice < 1 || choice > len(m.moveTargets) {
+		return m, nil
+	}
+	build, destRoot := *m.moveBuild, m.moveTargets[choice-1]
+	m.moveBuild = nil
+	return m, m.startMove(build, destRoot)
+}
+
+// startMove returns a command moving an installed build to destRoot, with
+// its progress shown in the build's row like a download.
+func (m *Model) startMove(build model.BlenderBuild, destRoot string) tea.Cmd {
+	buildID := downloadID(build)
+	transfers := m.commands.transfers
+	cancelCh, ok := transfers.start(buildID, "Moving")
+	if !ok {
+		return nil
+	}
+
+	roots, version := m.config.Roots(), build.Version
+	return func() tea.Msg {
+		progressCb := func(progress float64) {
+			transfers.setProgress(buildID, progress)
+		}
+		dir, err := local.MoveBuild(roots, version, destRoot, progressCb, cancelCh)
+		transfers.finish(buildID)
+		return moveDoneMsg{buildID: buildID, dir: dir, err: err}
+	}
+}
+
+// handleMoveDone reports where the build went and lists it there.
+func (m *Model) handleMoveDone(msg moveDoneMsg) (tea.Model, tea.Cmd) {
+	if errors.Is(msg.err, download.ErrCancelled) {
+		m.err = nil
+		return m, nil
+	}
+	if msg.err != nil {
+		m.err = fmt.Errorf("move failed: %w", msg.err)
+		return m, nil
+	}
+	m.notice = "moved to " + msg.dir
+	roots := m.config.Roots()
+	return m, tea.Batch(func() tea.Msg {
+		builds, err := local.ScanLocalBuilds(roots...)
+		return libraryChangedMsg{builds: builds, err: err}
+	}, checkDiskSpace(m.config.DownloadDir))
+}
+
+// renderMovePrompt renders the install roots to move a build to, numbered
+// for choosing one.
+func (m *Model) renderMovePrompt() string {
+	targets := make([]string, len(m.moveTargets))
+	for i, root := range m.moveTargets {
+		targets[i] = fmt.Sprintf("%d %s", i+1, root)
+	}
+	return fmt.Sprintf("Move %s to: %s", m.moveBuild.Version, strings.Join(targets, " · "))
+}
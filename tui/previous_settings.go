@@ -0,0 +1,80 @@
+package tui
+
+import (
+	"TUI-Blender-Launcher/local"
+	"TUI-Blender-Launcher/model"
+	"fmt"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// settingsOffer is the offer to copy the Blender user files of an earlier
+// series to the series of a build installed for the first time.
+type settingsOffer struct {
+	version string
+	from    string // Series to copy from
+}
+
+// settingsCopiedMsg reports the end of copying user files between series.
+type settingsCopiedMsg struct {
+	offer settingsOffer
+	err   error
+}
+
+// offerPreviousSettings offers copying the settings of the last series used
+// when version is the first of its series.
+func (m *Model) offerPreviousSettings(version string) {
+	if m.settingsOffer != nil {
+		return
+	}
+	if from := local.PreviousSettings(version); from != "" {
+		m.settingsOffer = &settingsOffer{version: version, from: from}
+	}
+}
+
+// isSettingsOfferKey reports whether the key answers the offer. Other keys
+// reach the builds list, so the offer doesn't hold it up.
+func isSettingsOfferKey(msg tea.KeyMsg) bool {
+	switch msg.String() {
+	case "y", "n", "esc":
+		return true
+	}
+	return false
+}
+
+// updateSettingsOffer handles the answer to the offer: y copies, n and esc decline.
+func (m *Model) updateSettingsOffer(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "y":
+		offer := *m.settingsOffer
+		m.settingsOffer = nil
+		return m, func() tea.Msg {
+			return settingsCopiedMsg{offer: offer, err: local.CopyPreviousSettings(offer.from, offer.version)}
+		}
+	case "n", "esc":
+		m.suggestEnablingAddons(m.settingsOffer.version)
+		m.settingsOffer = nil
+	}
+	return m, nil
+}
+
+// handleSettingsCopied reports the copied settings.
+func (m *Model) handleSettingsCopied(msg settingsCopiedMsg) (tea.Model, tea.Cmd) {
+	if msg.err != nil {
+		m.err = msg.err
+	} else {
+		m.notice = fmt.Sprintf("copied the settings of Blender %s to %s", msg.offer.from, msg.offer.version)
+	}
+	return m, nil
+}
+
+// suggestEnablingAddons tells to enable the configured add-ons, installed into
+// every new build, when the series of version has no preferences enabling them.
+// Copying the settings of an earlier series brings its enabled add-ons along.
+func (m *Model) suggestEnablingAddons(version string) {
+	if len(m.config.Addons) == 0 || local.HasUserPreferences(version) {
+		return
+	}
+	m.notice = fmt.Sprintf("enable your add-ons in Preferences > Add-ons, Blender %s has no preferences loading them yet",
+		model.VersionSeries(version))
+}
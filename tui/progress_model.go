@@ -9,9 +9,8 @@ import (
 
 // ProgressModel handles the state and logic for download progress.
 type ProgressModel struct {
-	ProgressBar      progress.Model
-	ActiveDownloadID string
-	DownloadStates   map[string]*model.DownloadState
+	ProgressBar    progress.Model
+	DownloadStates map[string]*model.DownloadState
 }
 
 // NewProgressModel creates a new ProgressModel.
@@ -60,19 +59,7 @@ func (m *ProgressModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	return m, nil
 }
 
-// SyncDownloadStates updates the local download states from the source
+// SyncDownloadStates replaces the download states with a fresh snapshot
 func (m *ProgressModel) SyncDownloadStates(states map[string]*model.DownloadState) {
-	for id, state := range states {
-		m.DownloadStates[id] = state
-	}
-}
-
-// GetActiveDownloadProgress returns the progress of the active download or 0
-func (m *ProgressModel) GetActiveDownloadProgress() float64 {
-	if m.ActiveDownloadID != "" {
-		if state, ok := m.DownloadStates[m.ActiveDownloadID]; ok {
-			return state.Progress
-		}
-	}
-	return 0.0
+	m.DownloadStates = states
 }
@@ -0,0 +1,136 @@
+package tui
+
+import (
+	"TUI-Blender-Launcher/model"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// queueFilename is the file in the state directory recording the downloads
+// still planned, so quitting mid-batch resumes them on the next start.
+const queueFilename = "queue.json"
+
+// plannedDownload is a download that was running, queued or scheduled.
+type plannedDownload struct {
+	Build       model.BlenderBuild `json:"build"`
+	Reinstall   bool               `json:"reinstall,omitempty"`
+	Corrupted   bool               `json:"corrupted,omitempty"` // The reinstall replaces a corrupted install
+	Paused      bool               `json:"paused,omitempty"`
+	ScheduledAt time.Time          `json:"scheduled_at"` // Zero unless scheduled
+}
+
+// queueLoadedMsg carries the downloads planned when the launcher last quit.
+type queueLoadedMsg struct {
+	downloads []plannedDownload
+}
+
+// plan lists the downloads still to do: running ones first, in the order
+// they started, then the queue in order and scheduled ones by time.
+// Must be called with mu held.
+func (dm *DownloadManager) plan() []plannedDownload {
+	var running, scheduled []plannedDownload
+	for buildID, build := range dm.builds {
+		state := dm.states[buildID]
+		if state == nil {
+			continue
+		}
+		planned := plannedDownload{Build: build, Reinstall: dm.reinstalls[buildID], Corrupted: dm.corrupted[buildID]}
+		switch state.BuildState {
+		case model.StateDownloading, model.StateExtracting, model.StatePaused:
+			planned.Paused = state.BuildState == model.StatePaused
+			running = append(running, planned)
+		case model.StateScheduled:
+			planned.ScheduledAt = state.ScheduledAt
+			scheduled = append(scheduled, planned)
+		}
+	}
+	startTime := func(p plannedDownload) time.Time { return dm.states[downloadID(p.Build)].StartTime }
+	sort.Slice(running, func(i, j int) bool { return startTime(running[i]).Before(startTime(running[j])) })
+	sort.Slice(scheduled, func(i, j int) bool { return scheduled[i].ScheduledAt.Before(scheduled[j].ScheduledAt) })
+
+	plan := running
+	for _, build := range dm.queue {
+		buildID := downloadID(build)
+		plan = append(plan, plannedDownload{Build: build, Reinstall: dm.reinstalls[buildID], Corrupted: dm.corrupted[buildID],
+			Paused: dm.pauseOnStart[buildID]})
+	}
+	return append(plan, scheduled...)
+}
+
+// saveQueue records the planned downloads, removing the file once there are
+// none. Failing to save only loses the plan, so errors are ignored.
+// Must be called with mu held.
+func (dm *DownloadManager) saveQueue() {
+	if dm.queuePath == "" {
+		return
+	}
+	plan := dm.plan()
+	if len(plan) == 0 {
+		_ = os.Remove(dm.queuePath)
+		return
+	}
+	data, err := json.MarshalIndent(plan, "", "  ")
+	if err != nil {
+		return
+	}
+	if err := os.MkdirAll(filepath.Dir(dm.queuePath), 0750); err != nil {
+		return
+	}
+	// Replace the file at once, so quitting while saving never leaves half a plan
+	tmp := dm.queuePath + ".tmp"
+	if err := os.WriteFile(tmp, data, 0644); err != nil {
+		return
+	}
+	_ = os.Rename(tmp, dm.queuePath)
+}
+
+// LoadQueue creates a command reading the downloads planned when the
+// launcher last quit.
+func (c *Commands) LoadQueue() tea.Cmd {
+	path := c.downloads.queuePath
+	return func() tea.Msg {
+		if path == "" {
+			return nil
+		}
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil
+		}
+		var downloads []plannedDownload
+		if err := json.Unmarshal(data, &downloads); err != nil {
+			return errMsg{fmt.Errorf("failed to read the download queue: %w", err)}
+		}
+		return queueLoadedMsg{downloads: downloads}
+	}
+}
+
+// handleQueueLoaded resumes the downloads planned last time in their order,
+// running as many as the configured concurrency allows and queueing the
+// rest. Interrupted downloads continue from their partial archive, paused
+// ones stay paused.
+func (m *Model) handleQueueLoaded(msg queueLoadedMsg) (tea.Model, tea.Cmd) {
+	dm := m.commands.downloads
+	now := time.Now()
+	for _, planned := range msg.downloads {
+		if planned.Paused {
+			dm.mu.Lock()
+			dm.pauseOnStart[downloadID(planned.Build)] = true
+			dm.mu.Unlock()
+		}
+		switch {
+		case planned.ScheduledAt.After(now):
+			dm.ScheduleDownload(planned.Build, planned.ScheduledAt)
+		case planned.Reinstall:
+			dm.StartReinstall(planned.Build, planned.Corrupted)
+		default:
+			dm.StartDownload(planned.Build)
+		}
+	}
+	return m, nil
+}
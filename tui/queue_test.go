@@ -0,0 +1,179 @@
+package tui
+
+import (
+	"TUI-Blender-Launcher/config"
+	"TUI-Blender-Launcher/download"
+	"TUI-Blender-Launcher/model"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestQueuePersistence(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.DownloadDir = t.TempDir()
+	dm := NewDownloadManager(cfg)
+	dm.queuePath = filepath.Join(t.TempDir(), queueFilename)
+
+	build := func(version string) model.BlenderBuild {
+		return model.BlenderBuild{Version: version, Hash: "0123456789abcdef", DownloadURL: "https://example.org/" + version + ".zip"}
+	}
+	now := time.Now()
+	track := func(b model.BlenderBuild, state model.BuildState, started time.Time) *model.DownloadState {
+		s := &model.DownloadState{BuildID: downloadID(b), BuildState: state, StartTime: started, CancelCh: make(chan struct{})}
+		dm.states[downloadID(b)] = s
+		dm.builds[downloadID(b)] = b
+		return s
+	}
+
+	// Two running downloads, started in this order, the second reinstalling
+	track(build("4.2.0"), model.StateExtracting, now.Add(-time.Minute))
+	track(build("4.3.0"), model.StatePaused, now)
+	dm.reinstalls[downloadID(build("4.3.0"))] = true
+	// The queue in order, and a download scheduled for later
+	track(build("4.5.0"), model.StateQueued, now)
+	track(build("4.4.0"), model.StateQueued, now)
+	dm.queue = []model.BlenderBuild{build("4.5.0"), build("4.4.0")}
+	track(build("4.1.0"), model.StateScheduled, now).ScheduledAt = now.Add(2 * time.Hour)
+	// Finished downloads aren't planned anymore
+	track(build("4.0.0"), model.StateFailed, now)
+
+	dm.mu.Lock()
+	dm.saveQueue()
+	dm.mu.Unlock()
+
+	msg, ok := (&Commands{downloads: dm}).LoadQueue()().(queueLoadedMsg)
+	if !ok {
+		t.Fatal("Expected the saved queue to load")
+	}
+	want := []string{"4.2.0", "4.3.0", "4.5.0", "4.4.0", "4.1.0"}
+	if len(msg.downloads) != len(want) {
+		t.Fatalf("Expected %d planned downloads, got %+v", len(want), msg.downloads)
+	}
+	for i, planned := range msg.downloads {
+		if planned.Build.Version != want[i] {
+			t.Errorf("Planned download %d: got %s, want %s", i, planned.Build.Version, want[i])
+		}
+		if planned.Reinstall != (want[i] == "4.3.0") {
+			t.Errorf("%s: unexpected reinstall %v", want[i], planned.Reinstall)
+		}
+		if planned.Paused != (want[i] == "4.3.0") {
+			t.Errorf("%s: unexpected pause %v", want[i], planned.Paused)
+		}
+		if planned.ScheduledAt.IsZero() != (want[i] != "4.1.0") {
+			t.Errorf("%s: unexpected schedule %v", want[i], planned.ScheduledAt)
+		}
+	}
+
+	// Without planned downloads the file goes away
+	dm.mu.Lock()
+	clear(dm.builds)
+	dm.queue = nil
+	dm.saveQueue()
+	dm.mu.Unlock()
+	if _, err := os.Stat(dm.queuePath); !os.IsNotExist(err) {
+		t.Errorf("Expected the queue file to be removed, got %v", err)
+	}
+	if msg := (&Commands{downloads: dm}).LoadQueue()(); msg != nil {
+		t.Errorf("Expected nothing to resume, got %#v", msg)
+	}
+}
+
+func TestResumeScheduledDownload(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.DownloadDir = t.TempDir()
+	h := NewHarness(cfg, 100, 15).SetBuilds(testBuilds())
+
+	// A download scheduled before quitting is scheduled again
+	build := h.Model().List.Builds[0]
+	at := time.Now().Add(time.Hour).Truncate(time.Second)
+	h.Send(queueLoadedMsg{downloads: []plannedDownload{{Build: build, ScheduledAt: at}}})
+	state := h.Model().commands.downloads.GetState(downloadID(build))
+	if state == nil || state.BuildState != model.StateScheduled || !state.ScheduledAt.Equal(at) {
+		t.Fatalf("Expected the download to be scheduled at %v, got %+v", at, state)
+	}
+	h.Model().commands.downloads.CancelDownload(downloadID(build))
+}
+
+func TestResumePausedDownload(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Length", "1024")
+		_, _ = w.Write(make([]byte, 1024))
+	}))
+	defer server.Close()
+
+	cfg := config.DefaultConfig()
+	cfg.DownloadDir = t.TempDir()
+	cfg.DownloadRetries = 0
+	h := NewHarness(cfg, 100, 15).SetBuilds(testBuilds())
+	dm := h.Model().commands.downloads
+	dm.queuePath = filepath.Join(t.TempDir(), queueFilename)
+
+	// A download paused before quitting starts paused
+	build := h.Model().List.Builds[0]
+	build.DownloadURL = server.URL + "/blender.zip"
+	buildID := downloadID(build)
+	h.Send(queueLoadedMsg{downloads: []plannedDownload{{Build: build, Paused: true}}})
+	if state := dm.GetState(buildID); state == nil || state.BuildState != model.StatePaused {
+		t.Fatalf("Expected the download to stay paused, got %+v", state)
+	}
+	dm.mu.Lock()
+	plan := dm.plan()
+	dm.mu.Unlock()
+	if len(plan) != 1 || !plan[0].Paused {
+		t.Errorf("Expected the pause to be saved again, got %+v", plan)
+	}
+
+	dm.CancelDownload(buildID)
+	select {
+	case msg := <-programCh:
+		if done, ok := msg.(downloadCompleteMsg); !ok || !errors.Is(done.err, download.ErrCancelled) {
+			t.Errorf("Expected the download to be cancelled, got %#v", msg)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("The cancelled download didn't finish")
+	}
+}
+
+// Run with -race: rendering must only read the snapshot taken by Update,
+// never the states the downloads update from their goroutines.
+func TestRenderDuringDownload(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.DownloadDir = t.TempDir()
+	h := NewHarness(cfg, 200, 15).SetBuilds(testBuilds())
+	build := h.Model().List.Builds[0]
+	buildID := downloadID(build)
+
+	dm := h.Model().commands.downloads
+	dm.mu.Lock()
+	dm.states[buildID] = &model.DownloadState{BuildID: buildID, BuildState: model.StateDownloading, Total: 100 << 20, CancelCh: make(chan struct{})}
+	dm.mu.Unlock()
+	h.Model().List.Builds[0].Status = model.StateDownloading
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for i := 0; i < 1000; i++ {
+			dm.mu.Lock()
+			state := dm.states[buildID]
+			state.Progress = float64(i) / 1000
+			state.Current = int64(i) << 10
+			state.Speed = float64(i)
+			dm.mu.Unlock()
+		}
+	}()
+	for i := 0; i < 50; i++ {
+		h.Send(tickMsg(time.Now()))
+		_ = h.Frame()
+	}
+	<-done
+
+	h.Send(tickMsg(time.Now()))
+	if state := h.Model().Progress.DownloadStates[buildID]; state == nil || state.Progress != 0.999 {
+		t.Errorf("Expected the snapshot to catch up with the download, got %+v", state)
+	}
+}
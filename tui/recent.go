@@ -0,0 +1,149 @@
+package tui
+
+import (
+	"TUI-Blender-Launcher/local"
+	"TUI-Blender-Launcher/model"
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	lp "github.com/charmbracelet/lipgloss"
+)
+
+// recentFilesState is the view of the .blend files a build opened last.
+type recentFilesState struct {
+	build  model.BlenderBuild
+	files  []string
+	cursor int
+}
+
+// handleShowRecentFiles lists the files in the selected build's recent-files
+// list, to launch it straight into one of them.
+func (m *Model) handleShowRecentFiles() (tea.Model, tea.Cmd) {
+	build := m.List.GetSelectedBuild()
+	if build == nil {
+		return m, nil
+	}
+	var dir string
+	switch build.Status {
+	case model.StateLocal, model.StateUpdate:
+		var err error
+		if dir, err = local.FindBuildDir(m.config.Roots(), build.Version); err != nil {
+			m.err = err
+			return m, nil
+		}
+	case model.StateSystem:
+		// System installs read the user config of their series
+	default:
+		return m, nil
+	}
+	files, err := local.RecentFiles(dir, build.Version)
+	if err != nil {
+		m.err = err
+		return m, nil
+	}
+
+	m.recentFiles = recentFilesState{build: *build, files: files}
+	m.currentView = viewRecentFiles
+	return m, nil
+}
+
+// updateRecentFilesViewController handles keys in the recent files view
+func (m *Model) updateRecentFilesViewController(msg tea.Msg) (tea.Model, tea.Cmd) {
+	keyMsg, ok := msg.(tea.KeyMsg)
+	if !ok {
+		return m.updateListViewController(msg)
+	}
+
+	for _, cmd := range GetCommandsForView(viewRecentFiles) {
+		if !MatchKey(keyMsg, cmd.Type) {
+			continue
+		}
+		switch cmd.Type {
+		case CmdQuit:
+			return m, tea.Quit
+		case CmdBack:
+			m.currentView = viewList
+			m.recentFiles = recentFilesState{}
+		case CmdMoveUp:
+			m.recentFiles.cursor = max(m.recentFiles.cursor-1, 0)
+		case CmdMoveDown:
+			m.recentFiles.cursor = max(min(m.recentFiles.cursor+1, len(m.recentFiles.files)-1), 0)
+		case CmdLaunchBuild:
+			if m.recentFiles.cursor >= len(m.recentFiles.files) {
+				return m, nil
+			}
+			build, file := m.recentFiles.build, m.recentFiles.files[m.recentFiles.cursor]
+			m.currentView = viewList
+			m.recentFiles = recentFilesState{}
+			return m, m.launchBuild(build, model.BlenderExecMsg{File: file})
+		case CmdRenderBuild:
+			build := m.recentFiles.build
+			if m.recentFiles.cursor >= len(m.recentFiles.files) ||
+				(build.Status != model.StateLocal && build.Status != model.StateUpdate) {
+				return m, nil
+			}
+			// The frames are added in the render input
+			m.renderBuild = &build
+			m.renderInput.SetValue(m.recentFiles.files[m.recentFiles.cursor] + " ")
+			m.renderInput.CursorEnd()
+			m.currentView = viewList
+			m.recentFiles = recentFilesState{}
+			return m, m.renderInput.Focus()
+		}
+		return m, nil
+	}
+	return m, nil
+}
+
+// renderRecentFiles renders the files the build opened last, newest first,
+// to fit the given width and height.
+func (m *Model) renderRecentFiles(width, height int) string {
+	titleStyle := lp.NewStyle().Bold(true).Foreground(lp.Color(highlightColor))
+	innerWidth := max(width-2*formPadding, 1)
+
+	var b strings.Builder
+	b.WriteString(titleStyle.Render("Recent files of Blender " + m.recentFiles.build.Version))
+	b.WriteString("\n\n")
+
+	if len(m.recentFiles.files) == 0 {
+		b.WriteString("Blender has no recent files that still exist.")
+		return lp.NewStyle().Padding(0, formPadding).Render(b.String())
+	}
+
+	nameWidth := 0
+	for _, file := range m.recentFiles.files {
+		nameWidth = max(nameWidth, len(filepath.Base(file)))
+	}
+	lines := make([]string, len(m.recentFiles.files))
+	for i, file := range m.recentFiles.files {
+		line := fmt.Sprintf("%-*s  %s", nameWidth, filepath.Base(file), filepath.Dir(file))
+		style := lp.NewStyle().MaxWidth(innerWidth)
+		if i == m.recentFiles.cursor {
+			style = m.Style.SelectedRow.MaxWidth(innerWidth)
+		}
+		lines[i] = style.Render(line)
+	}
+	b.WriteString(clipLines(strings.Join(lines, "\n"), height-2, m.recentFiles.cursor))
+	return lp.NewStyle().Padding(0, formPadding).Render(b.String())
+}
+
+// renderRecentFilesFooter renders the footer for the recent files view
+func (m *Model) renderRecentFilesFooter() string {
+	keyStyle := m.Style.Key
+	separator := m.Style.Separator.Render(" · ")
+	newlineStyle := m.Style.Newline.Render("\n")
+
+	commands := []string{fmt.Sprintf("%s Open", keyStyle.Render("enter"))}
+	if status := m.recentFiles.build.Status; status == model.StateLocal || status == model.StateUpdate {
+		commands = append(commands, fmt.Sprintf("%s Render", keyStyle.Render("A")))
+	}
+	commands = append(commands,
+		fmt.Sprintf("%s Back", keyStyle.Render("esc")),
+		fmt.Sprintf("%s Quit", keyStyle.Render("q")),
+	)
+
+	footerContent := m.renderStatus() + newlineStyle + strings.Join(commands, separator)
+	return m.Style.Footer.Width(m.terminalWidth).Render(footerContent)
+}
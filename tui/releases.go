@@ -0,0 +1,76 @@
+package tui
+
+import (
+	"TUI-Blender-Launcher/download"
+	"TUI-Blender-Launcher/local"
+	"TUI-Blender-Launcher/model"
+	"fmt"
+
+	tea "github.com/charmbracelet/bubbletea"
+	lp "github.com/charmbracelet/lipgloss"
+)
+
+// stableReleaseMsg announces a stable release that came out since the last fetch.
+type stableReleaseMsg struct {
+	build model.BlenderBuild
+}
+
+// checkStableRelease returns a command looking for a new stable release among
+// the fetched builds.
+func checkStableRelease(builds []model.BlenderBuild) tea.Cmd {
+	return func() tea.Msg {
+		release, err := local.NewStableRelease(builds)
+		if err != nil || release == nil {
+			return nil // Missing an announcement is no reason to bother the user
+		}
+		return stableReleaseMsg{build: *release}
+	}
+}
+
+// handleStableRelease shows the announcement, unless the release is installed already.
+func (m *Model) handleStableRelease(msg stableReleaseMsg) (tea.Model, tea.Cmd) {
+	for _, build := range m.List.Builds {
+		if build.Version == msg.build.Version && build.Status == model.StateLocal {
+			return m, nil
+		}
+	}
+	m.stableRelease = &msg.build
+	return m, nil
+}
+
+// handleInstallRelease downloads the announced stable release.
+func (m *Model) handleInstallRelease() (tea.Model, tea.Cmd) {
+	if m.stableRelease == nil {
+		return m, nil
+	}
+	release := *m.stableRelease
+	if err := download.CheckFreeSpace(release, m.config.DownloadDir); err != nil {
+		m.err = err
+		return m, nil
+	}
+	m.stableRelease = nil
+	return m, func() tea.Msg {
+		return startDownloadMsg{build: release}
+	}
+}
+
+// renderStableBanner renders the announcement of a new stable release, or ""
+// if there is none.
+func (m *Model) renderStableBanner() string {
+	if m.stableRelease == nil {
+		return ""
+	}
+	text := fmt.Sprintf(" Blender %s is out", m.stableRelease.Version)
+	if m.stableRelease.Size > 0 {
+		text += fmt.Sprintf(" (%s)", model.FormatByteSize(m.stableRelease.Size))
+	}
+	text += " · press I to install, esc to dismiss"
+	return lp.NewStyle().
+		Bold(true).
+		Foreground(lp.Color(textColor)).
+		Background(lp.Color(highlightColor)).
+		Width(m.terminalWidth).
+		MaxWidth(m.terminalWidth).
+		MaxHeight(1).
+		Render(text)
+}
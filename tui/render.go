@@ -0,0 +1,144 @@
+package tui
+
+import (
+	"TUI-Blender-Launcher/config"
+	"TUI-Blender-Launcher/launch"
+	"TUI-Blender-Launcher/local"
+	"TUI-Blender-Launcher/model"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	lp "github.com/charmbracelet/lipgloss"
+)
+
+// newRenderInput creates the input asking which file to render, and which frames.
+func newRenderInput() textinput.Model {
+	t := textinput.New()
+	t.Prompt = "Render: "
+	t.Placeholder = "path/to/scene.blend, tab completes, add 1-250 for frames other than the scene's"
+	t.CharLimit = 512
+	t.Width = 60
+	return t
+}
+
+// handleRenderBuild asks which file the selected installed build should
+// render in the background, suggesting the file it opened last.
+func (m *Model) handleRenderBuild() (tea.Model, tea.Cmd) {
+	build := m.List.GetSelectedBuild()
+	if build == nil || (build.Status != model.StateLocal && build.Status != model.StateUpdate) {
+		return m, nil
+	}
+	selected := *build
+	m.renderBuild = &selected
+	if m.renderInput.Value() == "" {
+		if dir, err := local.FindBuildDir(m.config.Roots(), build.Version); err == nil {
+			if files, err := local.RecentFiles(dir, build.Version); err == nil && len(files) > 0 {
+				m.renderInput.SetValue(files[0])
+			}
+		}
+	}
+	m.renderInput.CursorEnd()
+	return m, m.renderInput.Focus()
+}
+
+// updateRenderInput handles keys while the render input is shown. The last
+// file stays in the input for the next render.
+func (m *Model) updateRenderInput(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "esc":
+		m.renderBuild = nil
+		m.renderInput.Blur()
+		return m, nil
+	case "tab":
+		completeBlendPath(&m.renderInput)
+		return m, nil
+	case "enter":
+		build := *m.renderBuild
+		m.renderBuild = nil
+		m.renderInput.Blur()
+		if strings.TrimSpace(m.renderInput.Value()) == "" {
+			return m, nil
+		}
+		file, start, end, err := parseRenderTarget(m.renderInput.Value())
+		if err != nil {
+			m.err = err
+			return m, nil
+		}
+		id, err := m.queueRender(build, file, start, end)
+		if err != nil {
+			m.err = err
+			return m, nil
+		}
+		m.notice = fmt.Sprintf("rendering %s as job #%d, b shows its progress", filepath.Base(file), id)
+		return m, nil
+	}
+
+	var cmd tea.Cmd
+	m.renderInput, cmd = m.renderInput.Update(msg)
+	return m, cmd
+}
+
+// queueRender adds a job rendering frames start to end of file with build to
+// the launch queue and returns the job's ID.
+func (m *Model) queueRender(build model.BlenderBuild, file string, start, end int) (int, error) {
+	if _, err := os.Stat(file); err != nil {
+		return 0, fmt.Errorf("can't render: %w", err)
+	}
+	exe, err := local.FindBuildExecutable(m.config.Roots(), build.Version)
+	if err != nil {
+		return 0, err
+	}
+	opts, err := m.jobOptions(build.Version, build.HasTag(config.SandboxTag), file)
+	if err != nil {
+		return 0, err
+	}
+	id := m.commands.jobs.Add(build.Version, exe, file, launch.RenderArgs(start, end), opts)
+	m.Jobs.Jobs = m.commands.jobs.Jobs()
+	return id, nil
+}
+
+// parseRenderTarget splits what was typed into the render input into the
+// file and the frames to render, both 0 for the scene's frame range.
+func parseRenderTarget(target string) (string, int, int, error) {
+	target = strings.TrimSpace(target)
+	if i := strings.LastIndexAny(target, " \t"); i >= 0 && !strings.HasSuffix(target, ".blend") {
+		start, end, err := launch.ParseFrameRange(target[i+1:])
+		if err != nil {
+			return "", 0, 0, err
+		}
+		return strings.TrimSpace(target[:i]), start, end, nil
+	}
+	return target, 0, 0, nil
+}
+
+// completeBlendPath completes the directory or .blend file typed into input
+// as far as the matching ones agree.
+func completeBlendPath(input *textinput.Model) {
+	matches, err := BlendCompletions(input.Value())
+	if err != nil || len(matches) == 0 {
+		return
+	}
+	completion := commonPrefix(matches)
+	if len(matches) == 1 {
+		if info, err := os.Stat(completion); err == nil && info.IsDir() {
+			completion += string(os.PathSeparator)
+		}
+	}
+	input.SetValue(completion)
+	input.CursorEnd()
+}
+
+// renderRenderBadge renders the progress of the render running in the launch
+// queue for the header, or "" if none does.
+func (m *Model) renderRenderBadge() string {
+	for _, job := range m.Jobs.Jobs {
+		if progress := job.Progress(); job.Status == launch.JobRunning && progress != "" {
+			return lp.NewStyle().Foreground(lp.Color(greenColor)).Render("◷ Render " + progress)
+		}
+	}
+	return ""
+}
@@ -0,0 +1,50 @@
+package tui
+
+import (
+	"TUI-Blender-Launcher/local"
+	"TUI-Blender-Launcher/model"
+	"fmt"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// rollbackDoneMsg reports swapping an installed build for its last backup.
+type rollbackDoneMsg struct {
+	build model.BlenderBuild
+	err   error
+}
+
+// handleRollbackBuild restores the build the last update of the selected
+// build replaced, for when the update broke something.
+func (m *Model) handleRollbackBuild() (tea.Model, tea.Cmd) {
+	build := m.List.GetSelectedBuild()
+	if build == nil || (build.Status != model.StateLocal && build.Status != model.StateUpdate) {
+		return m, nil
+	}
+	if _, running := m.transfers[downloadID(*build)]; running {
+		return m, nil
+	}
+	if err := m.checkNotRunning(build.Version, "rolling it back"); err != nil {
+		m.err = err
+		return m, nil
+	}
+	roots, downloadDir, version := m.config.Roots(), m.config.DownloadDir, build.Version
+	return m, func() tea.Msg {
+		restored, err := local.RollbackBuild(roots, downloadDir, version)
+		return rollbackDoneMsg{build: restored, err: err}
+	}
+}
+
+// handleRollbackDone reports the restored build and lists it.
+func (m *Model) handleRollbackDone(msg rollbackDoneMsg) (tea.Model, tea.Cmd) {
+	if msg.err != nil {
+		m.err = fmt.Errorf("rollback failed: %w", msg.err)
+		return m, nil
+	}
+	m.notice = fmt.Sprintf("rolled back to Blender %s (%s)", msg.build.Version, msg.build.Hash)
+	roots := m.config.Roots()
+	return m, func() tea.Msg {
+		builds, err := local.ScanLocalBuilds(roots...)
+		return libraryChangedMsg{builds: builds, err: err}
+	}
+}
@@ -0,0 +1,204 @@
+package tui
+
+import (
+	"TUI-Blender-Launcher/launch"
+	"TUI-Blender-Launcher/local"
+	"TUI-Blender-Launcher/model"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	lp "github.com/charmbracelet/lipgloss"
+)
+
+// runningBlender is a Blender the launcher started in the background.
+type runningBlender struct {
+	pid     int
+	version string
+	logPath string
+	killed  bool // Asked to be killed, so its exit isn't a failure
+}
+
+// killTarget is a running Blender asked to be killed, with the processes
+// running it.
+type killTarget struct {
+	version string
+	pids    []int
+}
+
+// killDoneMsg reports the end of killing a running Blender.
+type killDoneMsg struct {
+	version string
+	err     error
+}
+
+// killGrace is how long Blender may take to quit before it is killed.
+const killGrace = 5 * time.Second
+
+// handleBlenderStarted shows a Blender started in the background in the
+// header and waits for it to exit.
+func (m *Model) handleBlenderStarted(msg blenderStartedMsg) (tea.Model, tea.Cmd) {
+	if msg.launched != nil {
+		m.handleBuildLaunched(*msg.launched)
+	}
+	process, version := msg.process, msg.version
+	m.lastStarted = runningBlender{pid: process.Pid(), version: version, logPath: process.LogPath}
+	m.running = append(m.running, m.lastStarted)
+	return m, func() tea.Msg {
+		err := process.Wait()
+		return blenderExitedMsg{pid: process.Pid(), version: version, logPath: process.LogPath, err: err}
+	}
+}
+
+// handleBlenderExited drops an exited Blender from the header, pointing at
+// its log if it failed, and shows its last output in the console.
+func (m *Model) handleBlenderExited(msg blenderExitedMsg) (tea.Model, tea.Cmd) {
+	killed := false
+	m.running = slices.DeleteFunc(m.running, func(running runningBlender) bool {
+		if running.pid == msg.pid {
+			killed = running.killed
+		}
+		return running.pid == msg.pid
+	})
+	// The console shows the Blender started last once none runs
+	switch {
+	case msg.err == nil || killed:
+	case len(m.running) == 0 && msg.logPath == m.lastStarted.logPath:
+		m.err = fmt.Errorf("blender %s exited with %w, ~ shows its output from %s", msg.version, msg.err, msg.logPath)
+	default:
+		m.err = fmt.Errorf("blender %s exited with %w, see %s", msg.version, msg.err, msg.logPath)
+	}
+	if m.currentView == viewConsole && msg.logPath == m.console.logPath {
+		return m, readConsole(msg.logPath)
+	}
+	return m, nil
+}
+
+// checkNotRunning returns an error telling to quit Blender first if the build
+// of version runs, started in the background by the launcher or else from the
+// build's files, so action doesn't pull them from under it.
+func (m *Model) checkNotRunning(version, action string) error {
+	if pids := m.runningProcesses(version); len(pids) > 0 {
+		return fmt.Errorf("blender %s is running (PID %d), quit it before %s", version, pids[0], action)
+	}
+	return nil
+}
+
+// checkSeriesNotRunning is checkNotRunning for every build of series, whose
+// Blender reads the user files of the series.
+func (m *Model) checkSeriesNotRunning(series, action string) error {
+	versions := make([]string, 0, len(m.running)+len(m.List.Builds))
+	for _, running := range m.running {
+		versions = append(versions, running.version)
+	}
+	for _, build := range m.List.Builds {
+		if build.Status == model.StateLocal || build.Status == model.StateUpdate || build.Status == model.StateSystem {
+			versions = append(versions, build.Version)
+		}
+	}
+	for _, version := range versions {
+		if model.VersionSeries(version) != series {
+			continue
+		}
+		if err := m.checkNotRunning(version, action); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// runningProcesses returns the IDs of the processes running the build of
+// version, those the launcher started in the background first.
+func (m *Model) runningProcesses(version string) []int {
+	var pids []int
+	for _, running := range m.running {
+		if running.version == version {
+			pids = append(pids, running.pid)
+		}
+	}
+	dir, err := local.FindBuildDir(m.config.Roots(), version)
+	if err != nil {
+		return pids
+	}
+	for _, pid := range launch.ProcessesIn(dir) {
+		if !slices.Contains(pids, pid) {
+			pids = append(pids, pid)
+		}
+	}
+	return pids
+}
+
+// handleKillBuild asks to confirm killing the running Blender of the
+// selected build.
+func (m *Model) handleKillBuild() (tea.Model, tea.Cmd) {
+	build := m.List.GetSelectedBuild()
+	if build == nil {
+		return m, nil
+	}
+	pids := m.runningProcesses(build.Version)
+	if len(pids) == 0 {
+		m.err = fmt.Errorf("blender %s isn't running", build.Version)
+		return m, nil
+	}
+	m.killBuild = &killTarget{version: build.Version, pids: pids}
+	return m, nil
+}
+
+// updateKillPrompt handles keys while the kill is to be confirmed: y kills,
+// n and esc leave Blender running.
+func (m *Model) updateKillPrompt(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "y":
+		target := *m.killBuild
+		m.killBuild = nil
+		for i := range m.running {
+			if slices.Contains(target.pids, m.running[i].pid) {
+				m.running[i].killed = true
+			}
+		}
+		return m, func() tea.Msg {
+			return killDoneMsg{version: target.version, err: launch.Terminate(target.pids, killGrace)}
+		}
+	case "n", "esc":
+		m.killBuild = nil
+	}
+	return m, nil
+}
+
+// handleKillDone reports the killed Blender.
+func (m *Model) handleKillDone(msg killDoneMsg) (tea.Model, tea.Cmd) {
+	if msg.err != nil {
+		m.err = msg.err
+	} else {
+		m.notice = "killed Blender " + msg.version
+	}
+	return m, nil
+}
+
+// renderKillPrompt renders the question whether to kill a running Blender.
+func (m *Model) renderKillPrompt() string {
+	pids := make([]string, len(m.killBuild.pids))
+	for i, pid := range m.killBuild.pids {
+		pids[i] = fmt.Sprint(pid)
+	}
+	label := "PID"
+	if len(pids) > 1 {
+		label = "PIDs"
+	}
+	return fmt.Sprintf("Kill Blender %s (%s %s)? Unsaved work is lost", m.killBuild.version, label, strings.Join(pids, ", "))
+}
+
+// renderRunningBadge renders the Blender started in the background first,
+// with how many more run, or "" if none does.
+func (m *Model) renderRunningBadge() string {
+	if len(m.running) == 0 {
+		return ""
+	}
+	text := "▶ Blender " + m.running[0].version
+	if len(m.running) > 1 {
+		text += fmt.Sprintf(" +%d", len(m.running)-1)
+	}
+	return lp.NewStyle().Foreground(lp.Color(greenColor)).Render(text)
+}
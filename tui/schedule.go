@@ -0,0 +1,133 @@
+package tui
+
+import (
+	"TUI-Blender-Launcher/download"
+	"TUI-Blender-Launcher/model"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// Clock formats accepted when scheduling a download
+var scheduleClockLayouts = []string{"15:04", "3:04pm", "3pm"}
+
+// parseScheduleTime parses when a scheduled download should start: a clock
+// time such as "02:00", "2am" or "tonight at 02:00", meaning its next
+// occurrence, "tomorrow 14:30", or a delay such as "+2h" or "in 90m".
+func parseScheduleTime(input string, now time.Time) (time.Time, error) {
+	s := strings.ToLower(strings.TrimSpace(input))
+
+	// Delays
+	for _, prefix := range []string{"+", "in "} {
+		if rest, ok := strings.CutPrefix(s, prefix); ok {
+			d, err := time.ParseDuration(strings.ReplaceAll(rest, " ", ""))
+			if err != nil || d <= 0 {
+				return time.Time{}, fmt.Errorf("invalid delay %q, use e.g. +2h or in 90m", rest)
+			}
+			return now.Add(d), nil
+		}
+	}
+
+	// Clock times, optionally with a day
+	tomorrow := false
+	for _, prefix := range []string{"tomorrow", "tonight", "today", "at"} {
+		if rest, ok := strings.CutPrefix(s, prefix); ok {
+			tomorrow = tomorrow || prefix == "tomorrow"
+			s = strings.TrimSpace(rest)
+		}
+	}
+	s = strings.TrimSpace(strings.TrimPrefix(s, "at"))
+	s = strings.ReplaceAll(s, " ", "")
+
+	for _, layout := range scheduleClockLayouts {
+		clock, err := time.Parse(layout, s)
+		if err != nil {
+			continue
+		}
+		at := time.Date(now.Year(), now.Month(), now.Day(), clock.Hour(), clock.Minute(), 0, 0, now.Location())
+		if tomorrow {
+			at = at.AddDate(0, 0, 1)
+		} else if !at.After(now) {
+			at = at.AddDate(0, 0, 1) // The next occurrence
+		}
+		return at, nil
+	}
+	return time.Time{}, fmt.Errorf("invalid time %q, use e.g. 02:00, 2am or +2h", input)
+}
+
+// formatScheduled formats the start of a scheduled download for the status
+// column: the clock time within the next day, with the date further out.
+func formatScheduled(at, now time.Time) string {
+	if at.Sub(now) < 24*time.Hour {
+		return "Scheduled " + at.Format("15:04")
+	}
+	return "Scheduled " + at.Format("Jan 2 15:04")
+}
+
+// newScheduleInput creates the input asking when to start a scheduled download.
+func newScheduleInput() textinput.Model {
+	t := textinput.New()
+	t.Prompt = "Download at: "
+	t.Placeholder = "02:00, tomorrow 14:30 or +2h"
+	t.CharLimit = 32
+	t.Width = 30
+	return t
+}
+
+// handleScheduleDownload asks when to download the selected build.
+func (m *Model) handleScheduleDownload() (tea.Model, tea.Cmd) {
+	build := m.List.GetSelectedBuild()
+	if build == nil {
+		return m, nil
+	}
+	switch build.Status {
+	case model.StateOnline, model.StateUpdate, model.StateFailed, model.StateCancelled, model.StateScheduled,
+		model.StateInterrupted:
+	default:
+		return m, nil
+	}
+
+	m.scheduling = true
+	m.scheduleErr = ""
+	m.scheduleInput.SetValue("")
+	return m, m.scheduleInput.Focus()
+}
+
+// updateScheduleInput handles keys while the schedule input is shown.
+func (m *Model) updateScheduleInput(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "esc":
+		m.scheduling = false
+		m.scheduleInput.Blur()
+		return m, nil
+	case "enter":
+		build := m.List.GetSelectedBuild()
+		if build == nil {
+			m.scheduling = false
+			return m, nil
+		}
+		at, err := parseScheduleTime(m.scheduleInput.Value(), time.Now())
+		if err != nil {
+			m.scheduleErr = err.Error()
+			return m, nil
+		}
+		if err := download.CheckFreeSpace(*build, m.config.DownloadDir); err != nil {
+			m.scheduleErr = err.Error()
+			return m, nil
+		}
+
+		m.commands.downloads.ScheduleDownload(*build, at)
+		build.Status = model.StateScheduled
+		m.scheduling = false
+		m.scheduleInput.Blur()
+		return m, nil
+	}
+
+	var cmd tea.Cmd
+	m.scheduleInput, cmd = m.scheduleInput.Update(msg)
+	m.scheduleErr = ""
+	return m, cmd
+}
@@ -0,0 +1,48 @@
+package tui
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseScheduleTime(t *testing.T) {
+	now := time.Date(2025, time.March, 20, 22, 15, 0, 0, time.Local)
+	at := func(day, hour, minute int) time.Time {
+		return time.Date(2025, time.March, day, hour, minute, 0, 0, time.Local)
+	}
+
+	tests := []struct {
+		input string
+		want  time.Time
+	}{
+		// Clock times mean their next occurrence
+		{"02:00", at(21, 2, 0)},
+		{"23:30", at(20, 23, 30)},
+		{"22:15", at(21, 22, 15)},
+		{"tonight at 02:00", at(21, 2, 0)},
+		{"at 11pm", at(20, 23, 0)},
+		{"2:30am", at(21, 2, 30)},
+		// Tomorrow is always the next day
+		{"tomorrow 23:30", at(21, 23, 30)},
+		{"Tomorrow at 9am", at(21, 9, 0)},
+		// Delays
+		{"+2h", at(21, 0, 15)},
+		{"in 90m", at(20, 23, 45)},
+	}
+	for _, tt := range tests {
+		got, err := parseScheduleTime(tt.input, now)
+		if err != nil {
+			t.Errorf("parseScheduleTime(%q) returned an error: %v", tt.input, err)
+			continue
+		}
+		if !got.Equal(tt.want) {
+			t.Errorf("parseScheduleTime(%q) = %v, want %v", tt.input, got, tt.want)
+		}
+	}
+
+	for _, input := range []string{"", "soon", "25:00", "+-1h", "in forever"} {
+		if _, err := parseScheduleTime(input, now); err == nil {
+			t.Errorf("parseScheduleTime(%q) should fail", input)
+		}
+	}
+}
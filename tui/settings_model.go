@@ -1,6 +1,7 @@
 package tui
 
 import (
+	"slices"
 	"strings"
 
 	"TUI-Blender-Launcher/config"
@@ -19,9 +20,11 @@ type SettingsModel struct {
 	BuildType        string
 	BuildTypeOptions []string
 	BuildTypeIndex   int
+	LaunchMode       string // Focused after the build type
 	Style            Style
 	Config           config.Config
 	width            int
+	height           int
 }
 
 // NewSettingsModel creates a new SettingsModel.
@@ -31,6 +34,7 @@ func NewSettingsModel(cfg config.Config, style Style) SettingsModel {
 		Style:            style,
 		BuildTypeOptions: []string{"daily", "experimental", "patch"},
 		BuildType:        cfg.BuildType,
+		LaunchMode:       cfg.LaunchMode,
 		FocusIndex:       0,
 		EditMode:         false,
 	}
@@ -43,8 +47,7 @@ func NewSettingsModel(cfg config.Config, style Style) SettingsModel {
 	t.Placeholder = cfg.DownloadDir
 	t.SetValue(cfg.DownloadDir)
 	t.CharLimit = 256
-	// We'll set width dynamically in View or SetWidth if possible,
-	// but for now initialized width is okay.
+	// Resized to the terminal by SetSize
 	t.Width = 50
 	m.Inputs[0] = t
 
@@ -74,9 +77,14 @@ func (m SettingsModel) Init() tea.Cmd {
 	return nil
 }
 
-// SetWidth updates the width of the settings model
-func (m *SettingsModel) SetWidth(w int) {
+// SetSize updates the space available to the settings view, fitting the
+// inputs to the width
+func (m *SettingsModel) SetSize(w, h int) {
 	m.width = w
+	m.height = h
+	for i := range m.Inputs {
+		m.Inputs[i].Width = formInputWidth(w)
+	}
 }
 
 // View returns the string representation of the model.
@@ -88,6 +96,8 @@ func (m SettingsModel) View() string {
 	if effectiveWidth <= 0 {
 		effectiveWidth = 80 // Fallback
 	}
+	// Width inside the form padding
+	innerWidth := max(effectiveWidth-2*formPadding, 1)
 
 	// Styles
 	// Helper to get alignment based on index
@@ -113,7 +123,7 @@ func (m SettingsModel) View() string {
 	descBase := lp.NewStyle().Italic(true).Foreground(lp.Color("241")).Align(lp.Left)
 
 	// Section takes full width
-	sectionBase := lp.NewStyle().MarginBottom(2).Width(effectiveWidth)
+	sectionBase := lp.NewStyle().MarginBottom(2).Width(innerWidth)
 
 	optionStyle := lp.NewStyle().MarginRight(1).Padding(0, 1)
 	selectedOptionStyle := lp.NewStyle().MarginRight(1).Padding(0, 1).
@@ -124,8 +134,8 @@ func (m SettingsModel) View() string {
 		labelAlign := getAlign(index)
 
 		// Labels: Mixed Alignment
-		lblStyle := labelBase.Align(labelAlign).Width(effectiveWidth)
-		lblStyleFocused := labelFocusedBase.Align(labelAlign).Width(effectiveWidth)
+		lblStyle := labelBase.Align(labelAlign).Width(innerWidth)
+		lblStyleFocused := labelFocusedBase.Align(labelAlign).Width(innerWidth)
 
 		var sb strings.Builder
 		isFocused := (m.FocusIndex == index)
@@ -139,29 +149,28 @@ func (m SettingsModel) View() string {
 
 		// Input: Always Left Aligned
 		inputView := m.Inputs[index].View()
-		inpStyle := inputBase.Width(effectiveWidth)
+		inpStyle := inputBase.Width(max(innerWidth-2, 1))
 
 		sb.WriteString(inpStyle.Render(inputView))
 		sb.WriteString("\n")
 
 		// Description: Always Left Aligned
-		dStyle := descBase.Width(effectiveWidth)
+		dStyle := descBase.Width(innerWidth)
 		sb.WriteString(dStyle.Render(description))
 
 		// Wrap in section style
 		return sectionBase.Render(sb.String())
 	}
 
-	renderBuildTypeSetting := func(label, description string) string {
-		index := 2                    // Hardcoded as 3rd item
-		labelAlign := getAlign(index) // Right
+	renderOptionSetting := func(index int, label string, options []string, selected, description string) string {
+		labelAlign := getAlign(index)
 
 		// Labels: Mixed Alignment
-		lblStyle := labelBase.Align(labelAlign).Width(effectiveWidth)
-		lblStyleFocused := labelFocusedBase.Align(labelAlign).Width(effectiveWidth)
+		lblStyle := labelBase.Align(labelAlign).Width(innerWidth)
+		lblStyleFocused := labelFocusedBase.Align(labelAlign).Width(innerWidth)
 
 		var sb strings.Builder
-		isFocused := (m.FocusIndex == len(m.Inputs))
+		isFocused := (m.FocusIndex == index)
 
 		if isFocused {
 			sb.WriteString(lblStyleFocused.Render(label))
@@ -171,9 +180,8 @@ func (m SettingsModel) View() string {
 		sb.WriteString("\n")
 
 		var horizontalOptions strings.Builder
-		selectedBuildType := m.BuildType
-		for _, option := range m.BuildTypeOptions {
-			if option == selectedBuildType {
+		for _, option := range options {
+			if option == selected {
 				horizontalOptions.WriteString(selectedOptionStyle.Render(option))
 			} else {
 				horizontalOptions.WriteString(optionStyle.Render(option))
@@ -183,24 +191,47 @@ func (m SettingsModel) View() string {
 		// Options: Always Left Aligned
 		// Using MarginLeft(2) to match inputBase for consistency or just Left?
 		// User said "make them all left aligned". Input has MarginLeft(2). Let's match it usually.
-		optsStyle := lp.NewStyle().MarginLeft(2).Align(lp.Left).Width(effectiveWidth)
+		optsStyle := lp.NewStyle().MarginLeft(2).Align(lp.Left).Width(max(innerWidth-2, 1))
 		sb.WriteString(optsStyle.Render(horizontalOptions.String()))
 		sb.WriteString("\n")
 
 		// Description: Always Left Aligned
-		dStyle := descBase.Width(effectiveWidth)
+		dStyle := descBase.Width(innerWidth)
 		sb.WriteString(dStyle.Render(description))
 
 		return sectionBase.Render(sb.String())
 	}
 
-	// Render each setting
-	b.WriteString(renderTextSetting(0, "Download Directory", "Path where Blender builds will be stored."))
-	b.WriteString(renderTextSetting(1, "Version Filter", "Filter versions (e.g., '4.2', '3.6'). Leave empty for all."))
-	b.WriteString(renderBuildTypeSetting("Build Type", "Select default build type to fetch."))
+	// Render each setting, remembering where the focused one starts
+	focusLine := 0
+	sections := []func() string{
+		func() string {
+			return renderTextSetting(0, "Download Directory", "Path where Blender builds will be stored.")
+		},
+		func() string {
+			return renderTextSetting(1, "Version Filter", "Filter versions (e.g., '4.2', '3.6'). Leave empty for all.")
+		},
+		func() string {
+			return renderOptionSetting(2, "Build Type", m.BuildTypeOptions, m.BuildType, "Select default build type to fetch.")
+		},
+		func() string {
+			return renderOptionSetting(3, "Launch Mode", config.LaunchModes, m.LaunchMode,
+				"Where Blender runs: a new terminal window, the background, or a new tmux window when inside tmux.")
+		},
+	}
+	for i, section := range sections {
+		if i > 0 {
+			b.WriteString("\n")
+		}
+		if i == m.FocusIndex {
+			focusLine = strings.Count(b.String(), "\n")
+		}
+		b.WriteString(section())
+	}
 
-	// Final container
-	return lp.NewStyle().Width(effectiveWidth).Padding(1, 2).Render(b.String())
+	// Final container, scrolled to the focused input when the terminal is short
+	view := lp.NewStyle().Width(effectiveWidth).Padding(1, formPadding).Render(b.String())
+	return clipLines(view, m.height, focusLine+2)
 }
 
 // Update handles update messages for the settings model.
@@ -229,7 +260,7 @@ func (m *SettingsModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 				case CmdMoveUp:
 					if !m.EditMode {
-						totalItems := len(m.Inputs) + 1
+						totalItems := len(m.Inputs) + 2
 						m.FocusIndex = (m.FocusIndex - 1 + totalItems) % totalItems
 						m.updateFocusStyles()
 						return m, nil
@@ -237,7 +268,7 @@ func (m *SettingsModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 				case CmdMoveDown:
 					if !m.EditMode {
-						totalItems := len(m.Inputs) + 1
+						totalItems := len(m.Inputs) + 2
 						m.FocusIndex = (m.FocusIndex + 1) % totalItems
 						m.updateFocusStyles()
 						return m, nil
@@ -249,6 +280,10 @@ func (m *SettingsModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 						m.BuildType = m.BuildTypeOptions[m.BuildTypeIndex]
 						return m, nil
 					}
+					if !m.EditMode && m.FocusIndex == len(m.Inputs)+1 {
+						m.LaunchMode = cycleOption(config.LaunchModes, m.LaunchMode, -1)
+						return m, nil
+					}
 
 				case CmdMoveRight:
 					if !m.EditMode && m.FocusIndex == len(m.Inputs) {
@@ -256,6 +291,10 @@ func (m *SettingsModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 						m.BuildType = m.BuildTypeOptions[m.BuildTypeIndex]
 						return m, nil
 					}
+					if !m.EditMode && m.FocusIndex == len(m.Inputs)+1 {
+						m.LaunchMode = cycleOption(config.LaunchModes, m.LaunchMode, 1)
+						return m, nil
+					}
 				}
 			}
 		}
@@ -319,15 +358,26 @@ func (m *SettingsModel) handleDirCompletion() (tea.Model, tea.Cmd) {
 	return m, nil
 }
 
+// cycleOption returns the option step places after current in options,
+// wrapping around. An unknown current one starts from the first.
+func cycleOption(options []string, current string, step int) string {
+	index := slices.Index(options, current)
+	if index < 0 {
+		return options[0]
+	}
+	return options[(index+step+len(options))%len(options)]
+}
+
 // GetValues returns the current values from the inputs
-func (m *SettingsModel) GetValues() (downloadDir string, versionFilter string, buildType string) {
-	return m.Inputs[0].Value(), m.Inputs[1].Value(), m.BuildType
+func (m *SettingsModel) GetValues() (downloadDir string, versionFilter string, buildType string, launchMode string) {
+	return m.Inputs[0].Value(), m.Inputs[1].Value(), m.BuildType, m.LaunchMode
 }
 
 // SetValues sets the values (e.g., when reloading config)
-func (m *SettingsModel) SetValues(downloadDir, versionFilter, buildType string) {
+func (m *SettingsModel) SetValues(downloadDir, versionFilter, buildType, launchMode string) {
 	m.Inputs[0].SetValue(downloadDir)
 	m.Inputs[1].SetValue(versionFilter)
+	m.LaunchMode = launchMode
 
 	m.BuildType = buildType
 	for i, opt := range m.BuildTypeOptions {
@@ -15,6 +15,9 @@ type Style struct {
 	Separator          lp.Style
 	Newline            lp.Style
 	Footer             lp.Style
+	Error              lp.Style
+	Notice             lp.Style
+	DetailsPane        lp.Style
 }
 
 // NewStyle constructs the default UI style palette.
@@ -56,5 +59,17 @@ func NewStyle() Style {
 
 		Footer: lp.NewStyle().
 			Foreground(baseText),
+
+		Error: lp.NewStyle().
+			Foreground(lp.Color(redColor)),
+
+		Notice: lp.NewStyle().
+			Foreground(lp.Color(greenColor)),
+
+		DetailsPane: lp.NewStyle().
+			BorderStyle(lp.NormalBorder()).
+			BorderLeft(true).
+			BorderForeground(lp.Color("241")).
+			PaddingLeft(1),
 	}
 }
@@ -0,0 +1,44 @@
+package tui
+
+import (
+	"TUI-Blender-Launcher/local"
+	"TUI-Blender-Launcher/model"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// systemBuildsMsg carries the Blender installs found outside the launcher.
+type systemBuildsMsg struct {
+	builds []model.BlenderBuild
+}
+
+// scanSystemBuilds returns a command looking for Blender installed by
+// package managers, stores and installers. Running them to learn their
+// versions takes a while, so it happens apart from the library scan.
+func (m *Model) scanSystemBuilds() tea.Cmd {
+	roots := m.config.Roots()
+	return func() tea.Msg {
+		return systemBuildsMsg{builds: local.ScanSystemBuilds(roots)}
+	}
+}
+
+// handleSystemBuilds lists the system installs after the builds.
+func (m *Model) handleSystemBuilds(msg systemBuildsMsg) (tea.Model, tea.Cmd) {
+	m.systemBuilds = msg.builds
+	m.List.Builds = m.applyTagFilter(m.withSystemBuilds(m.List.Builds))
+	m.List.SortBuilds()
+	return m, nil
+}
+
+// withSystemBuilds returns builds with the system installs in place of any
+// listed before. They are read-only rows, so they are kept apart from the
+// builds the launcher works out statuses for.
+func (m *Model) withSystemBuilds(builds []model.BlenderBuild) []model.BlenderBuild {
+	merged := make([]model.BlenderBuild, 0, len(builds)+len(m.systemBuilds))
+	for _, build := range builds {
+		if build.Status != model.StateSystem {
+			merged = append(merged, build)
+		}
+	}
+	return append(merged, m.systemBuilds...)
+}
@@ -4,6 +4,7 @@ import (
 	"TUI-Blender-Launcher/model"
 	"fmt"
 	"strings"
+	"time"
 
 	lp "github.com/charmbracelet/lipgloss"
 )
@@ -12,7 +13,12 @@ import (
 type Row struct {
 	Build      model.BlenderBuild
 	IsSelected bool
+	IsMarked   bool // Marked for downloading together with other builds
+	IsDefault  bool // Pointed at by the current symlink
 	Status     *model.DownloadState
+	Verify     string   // Result of the last integrity check, if any
+	Icons      *iconSet // Icons starting the row, nil for none
+	Transfer   string   // Label of a running export or move, with the progress in Status
 }
 
 // NewRow creates a new row instance from a build
@@ -24,9 +30,12 @@ func NewRow(build model.BlenderBuild, isSelected bool, status *model.DownloadSta
 	}
 }
 
+// resumeNoticeDuration is how long a resumed download shows "Resuming at N%" in its status cell
+const resumeNoticeDuration = 3 * time.Second
+
 // Column configuration
 type columnConfig struct {
-	width    int
+	width    int     // Minimum width, fitting the header with its sort arrow and the usual cells
 	priority int     // Lower number = higher priority (will be shown first)
 	flex     float64 // Flex ratio for dynamic width calculation
 }
@@ -35,13 +44,13 @@ type columnConfig struct {
 var (
 	// Column configurations with priorities and flex values
 	columnConfigs = map[string]columnConfig{
-		"Version":    {width: 0, priority: 1, flex: 1.0}, // Version gets more space
-		"Status":     {width: 0, priority: 2, flex: 1.0}, // Status needs room for different states
-		"Branch":     {width: 0, priority: 5, flex: 1.0},
-		"Type":       {width: 0, priority: 4, flex: 1.0},
-		"Hash":       {width: 0, priority: 6, flex: 1.0},
-		"Size":       {width: 0, priority: 7, flex: 1.0},
-		"Build Date": {width: 0, priority: 3, flex: 1.0},
+		"Version":    {width: 12, priority: 1, flex: 1.0}, // Version gets more space
+		"Status":     {width: 18, priority: 2, flex: 1.0}, // Status needs room for different states
+		"Branch":     {width: 10, priority: 5, flex: 1.0},
+		"Type":       {width: 11, priority: 4, flex: 1.0},
+		"Hash":       {width: 14, priority: 7, flex: 1.0},
+		"Size":       {width: 9, priority: 8, flex: 1.0},
+		"Build Date": {width: 18, priority: 3, flex: 1.0},
 	}
 )
 
@@ -53,25 +62,38 @@ func (r Row) Render(columns []ColumnConfig, style Style) string {
 	// Special handling for downloads and extractions
 	isDownloading := r.Build.Status == model.StateDownloading && r.Status != nil
 	isExtracting := r.Build.Status == model.StateExtracting && r.Status != nil
+	isPaused := r.Build.Status == model.StatePaused && r.Status != nil
+	isTransferring := r.Transfer != "" && r.Status != nil
 	isOnline := r.Build.Status == model.StateOnline
 	isUpdate := r.Build.Status == model.StateUpdate
 	isFailed := r.Build.Status == model.StateFailed
 	isCancelled := r.Build.Status == model.StateCancelled // StateNone is "Cancelled"
+	isPrefetched := r.Build.Status == model.StatePrefetched
+	isExpired := r.Build.Status == model.StateLocal && r.Build.Expired(time.Now())
 
 	// Handle special case for download/extract - we'll render empty cells for Type, Hash, Size, Build Date
 	// and only display content in Version, Status, and Branch columns
-	if isDownloading || isExtracting {
+	if isDownloading || isExtracting || isPaused || isTransferring {
 		for _, col := range columns {
 			var cellContent string
 
 			switch col.Key {
 			case "Version":
-				cellContent = r.Build.Version
+				cellContent = r.Icons.prefix(r.Build, r.Verify) + r.Build.Version
 			case "Status":
 				if isDownloading {
 					cellContent = model.StateDownloading.String()
+					if r.Status.Retry > 0 {
+						cellContent = fmt.Sprintf("Retry %d/%d", r.Status.Retry, r.Status.MaxRetries)
+					} else if r.Status.ResumedFrom > 0 && time.Since(r.Status.StartTime) < resumeNoticeDuration {
+						cellContent = fmt.Sprintf("Resuming at %.0f%%", r.Status.ResumedFrom*100)
+					}
 				} else if isExtracting {
 					cellContent = model.StateExtracting.String()
+				} else if isPaused {
+					cellContent = model.StatePaused.String()
+				} else if isTransferring {
+					cellContent = r.Transfer
 				}
 			case "Branch":
 				// Show download speed in Branch column when downloading
@@ -86,7 +108,11 @@ func (r Row) Render(columns []ColumnConfig, style Style) string {
 						// For very high speeds, don't show decimal places
 						cellContent = fmt.Sprintf("%6.0f MB/s", speedMBps)
 					}
-				} else if isExtracting {
+					// Show the active bandwidth limit next to the speed
+					if r.Status.RateLimit > 0 {
+						cellContent = fmt.Sprintf("%5.1f/%.1f MB/s", speedMBps, r.Status.RateLimit/1024/1024)
+					}
+				} else if isExtracting || isTransferring {
 					// Show percentage in Branch column for extraction with consistent formatting
 					cellContent = fmt.Sprintf("%6.1f%%", r.Status.Progress*100)
 				}
@@ -104,8 +130,30 @@ func (r Row) Render(columns []ColumnConfig, style Style) string {
 			switch col.Key {
 			case "Version":
 				cellContent = r.Build.Version
+				if r.IsMarked {
+					cellContent = "+ " + cellContent
+				}
+				if r.IsDefault {
+					cellContent += " *"
+				}
+				cellContent = r.Icons.prefix(r.Build, r.Verify) + cellContent
 			case "Status":
 				cellContent = r.Build.Status.String()
+				if r.Build.Status == model.StateQueued && r.Status != nil && r.Status.QueuePosition > 0 {
+					cellContent = fmt.Sprintf("Queued (%d)", r.Status.QueuePosition)
+				} else if r.Build.Status == model.StateInterrupted && r.Status != nil && r.Status.Progress > 0 {
+					cellContent = fmt.Sprintf("Interrupted %.0f%%", r.Status.Progress*100)
+				} else if r.Build.Status == model.StateScheduled && r.Status != nil {
+					cellContent = formatScheduled(r.Status.ScheduledAt, time.Now())
+				} else if isFailed && r.Status != nil && r.Status.Err != nil {
+					cellContent = failureLabel(r.Status.Err)
+				} else if r.Verify != "" {
+					cellContent = r.Verify
+				} else if len(r.Build.Duplicates) > 0 {
+					cellContent = fmt.Sprintf("%s ×%d", r.Build.Status, len(r.Build.Duplicates)+1)
+				} else if r.Build.Status == model.StateLocal && r.Build.ExpiresAt != nil {
+					cellContent = temporaryLabel(*r.Build.ExpiresAt, time.Now())
+				}
 			case "Branch":
 				cellContent = r.Build.Branch
 			case "Type":
@@ -113,9 +161,13 @@ func (r Row) Render(columns []ColumnConfig, style Style) string {
 			case "Hash":
 				cellContent = r.Build.Hash
 			case "Size":
-				cellContent = model.FormatByteSize(r.Build.Size)
+				cellContent = model.FormatByteSize(r.Build.ShownSize())
 			case "Build Date":
 				cellContent = model.FormatBuildDate(r.Build.BuildDate)
+			default:
+				if col.Value != nil {
+					cellContent = col.Value(r.Build)
+				}
 			}
 			cells = append(cells, col.Style(cellContent))
 		}
@@ -125,20 +177,18 @@ func (r Row) Render(columns []ColumnConfig, style Style) string {
 	rowString := lp.JoinHorizontal(lp.Left, cells...)
 
 	// Apply a progress bar for downloading/extracting across Type to Build Date columns
-	if (isDownloading || isExtracting) && r.Status != nil {
+	if (isDownloading || isExtracting || isPaused || isTransferring) && r.Status != nil {
 		// Find the beginning of the Type column
 		typeColIndex := -1
 		typePosition := 0
 
 		// Calculate the position of where to insert the progress bar
 		for i, col := range columns {
-			if i < 3 { // Version, Status, Branch columns
-				typePosition += col.Width
-			}
 			if col.Key == "Type" {
 				typeColIndex = i
 				break
 			}
+			typePosition += col.Width
 		}
 
 		if typeColIndex >= 0 {
@@ -182,10 +232,11 @@ func (r Row) Render(columns []ColumnConfig, style Style) string {
 					Render("")
 			}
 
-			// Create a new row string with the progress bar inserted at the Type column
-			if typePosition < len(rowString) {
+			// Create a new row string with the progress bar inserted at the Type column.
+			// Cut by display width, as the styled cells contain escape sequences.
+			if typePosition < lp.Width(rowString) {
 				// Replace from Type column onward with progress bar
-				rowString = rowString[:typePosition] + progressBar
+				rowString = lp.NewStyle().MaxWidth(typePosition).Render(rowString) + progressBar
 			}
 		}
 	}
@@ -213,6 +264,18 @@ func (r Row) Render(columns []ColumnConfig, style Style) string {
 			Width(sumColumnWidths(columns)).
 			Render(rowString)
 	}
+	if isPrefetched {
+		return lp.NewStyle().
+			Foreground(lp.Color(cyanColor)).
+			Width(sumColumnWidths(columns)).
+			Render(rowString)
+	}
+	if isExpired {
+		return lp.NewStyle().
+			Foreground(lp.Color(yellowColor)).
+			Width(sumColumnWidths(columns)).
+			Render(rowString)
+	}
 	return style.RegularRow.Width(sumColumnWidths(columns)).Render(rowString)
 }
 
@@ -232,10 +295,12 @@ type ColumnConfig struct {
 	Width int
 	Index int
 	Style func(string) string
+	Value func(model.BlenderBuild) string // Cell content of custom columns
 }
 
-// Updated GetBuildColumns to accept terminalWidth and compute widths
-func GetBuildColumns(terminalWidth int) []ColumnConfig {
+// Updated GetBuildColumns to accept terminalWidth and compute widths.
+// Custom columns are appended after the built-in ones.
+func GetBuildColumns(terminalWidth int, custom []customColumn) []ColumnConfig {
 	var cellStyleCenter = lp.NewStyle().Align(lp.Center)
 	columns := []ColumnConfig{
 		{Name: "Version", Key: "Version", Index: 0},
@@ -246,18 +311,51 @@ func GetBuildColumns(terminalWidth int) []ColumnConfig {
 		{Name: "Size", Key: "Size", Index: 5},
 		{Name: "Build Date", Key: "Build Date", Index: 6},
 	}
+	for i, col := range custom {
+		columns = append(columns, ColumnConfig{
+			Name:  col.Name,
+			Key:   "custom:" + col.Name,
+			Index: builtinColumnCount + i,
+			Value: col.Value,
+		})
+	}
+	// Narrow terminals drop the least important columns until the rest fit,
+	// Version and Status always stay
+	minWidth := func() int {
+		sum := 0
+		for _, col := range columns {
+			sum += columnSpec(col).width
+		}
+		return sum
+	}
+	for len(columns) > 2 && minWidth() > terminalWidth {
+		drop := 0
+		for i, col := range columns {
+			if columnSpec(col).priority >= columnSpec(columns[drop]).priority {
+				drop = i
+			}
+		}
+		columns = append(columns[:drop], columns[drop+1:]...)
+	}
+
 	// Compute total flex for all columns
 	totalFlex := 0.0
 	for i := range columns {
-		totalFlex += columnConfigs[columns[i].Key].flex
+		totalFlex += columnSpec(columns[i]).flex
 	}
-	// Assign each column a width proportional to its flex value
+	// Assign each column its minimum width and a share of the rest proportional to its flex value
+	extra := max(terminalWidth-minWidth(), 0)
 	for i := range columns {
-		flex := columnConfigs[columns[i].Key].flex
-		colWidth := int((float64(terminalWidth) * flex) / totalFlex)
+		spec := columnSpec(columns[i])
+		colWidth := spec.width + int((float64(extra)*spec.flex)/totalFlex)
 		columns[i].Width = colWidth
 		columns[i].Style = func(width int) func(string) string {
 			return func(s string) string {
+				// Cells too long for the column are cut short instead of wrapping the row,
+				// keeping a space to the next column
+				if lp.Width(s) > width-1 {
+					s = lp.NewStyle().Inline(true).MaxWidth(max(width-2, 0)).Render(s) + "…"
+				}
 				return cellStyleCenter.Width(width).Render(s)
 			}
 		}(colWidth)
@@ -265,13 +363,23 @@ func GetBuildColumns(terminalWidth int) []ColumnConfig {
 	return columns
 }
 
-// Update RenderRows to pass terminalWidth and respect visibleRowsCount
+// columnSpec returns the configuration of a column. Custom columns get room
+// for their free-form cells and are dropped after Hash and Size, the last first.
+func columnSpec(col ColumnConfig) columnConfig {
+	if cfg, ok := columnConfigs[col.Key]; ok {
+		return cfg
+	}
+	return columnConfig{width: 16, priority: 6, flex: 2.0}
+}
+
+// Update RenderRows to pass the list width and respect visibleRowsCount
 func RenderRows(m *Model, visibleRowsCount int) string {
 	var output strings.Builder
 	newlineStyle := lp.NewStyle().Render("\n")
 
 	// Get column configuration with computed widths
-	columns := GetBuildColumns(m.terminalWidth)
+	columns := GetBuildColumns(m.listWidth(), m.List.Columns())
+	icons := rowIcons(m.config.RowIcons)
 
 	// Calculate visible range
 	endIndex := m.List.StartIndex + visibleRowsCount
@@ -287,38 +395,35 @@ func RenderRows(m *Model, visibleRowsCount int) string {
 		build := m.List.Builds[i]
 
 		// Create a buildID to check for download state
-		buildID := build.Version
-		if build.Hash != "" {
-			buildID = build.Version + "-" + build.Hash[:8]
-		}
+		buildID := downloadID(build)
 
 		// Track that we're processing this build
 		processedBuilds[buildID] = true
 
-		// Get download state if exists
-		var downloadState *model.DownloadState = nil
+		// Download state from the snapshot taken at the end of the last update
+		downloadState := m.Progress.DownloadStates[buildID]
 
-		// Check if this is a downloading or extracting build
-		if build.Status == model.StateDownloading || build.Status == model.StateExtracting {
-			// Check in current model's download states using ListModel or ProgressModel
-			// ProgressModel has DownloadStates
-			if state, exists := m.Progress.DownloadStates[buildID]; exists {
-				downloadState = state
+		// Always update last render state for downloads - but don't check for changes
+		// to avoid skipping download renderings
+		if downloadState != nil && (build.Status == model.StateDownloading || build.Status == model.StateExtracting ||
+			build.Status == model.StatePaused) {
+			m.List.LastRenderState[buildID] = downloadState.Progress
+		}
 
-				// Always update last render state for downloads - but don't check for changes
-				// to avoid skipping download renderings
-				m.List.LastRenderState[buildID] = state.Progress
-			}
-		} else {
-			// Fallback to checking in commands downloads manager
-			if m.commands != nil && m.commands.downloads != nil {
-				downloadState = m.commands.downloads.GetState(buildID)
-			}
+		// Exports and moves take the download's place in the row
+		transfer, transferring := m.transfers[buildID]
+		if transferring {
+			downloadState = &model.DownloadState{BuildID: buildID, Progress: transfer.progress}
 		}
 
 		// Always render downloading/extracting rows, never skip them
 		// Create and render row; highlight if this is the current row
 		row := NewRow(build, i == m.List.Cursor, downloadState)
+		row.Transfer = transfer.label
+		row.Verify = m.verifyResults[buildID]
+		row.IsMarked = m.List.Marked[buildID]
+		row.Icons = icons
+		row.IsDefault = buildID == m.defaultBuild
 		rowText := row.Render(columns, m.Style)
 
 		// Ensure each row has proper width
@@ -348,7 +453,7 @@ func (m *Model) renderBuildContent(availableHeight int) string {
 		var msg string = "No Blender builds found locally or online."
 
 		return lp.Place(
-			m.terminalWidth,
+			m.listWidth(),
 			availableHeight,
 			lp.Center,
 			lp.Top,
@@ -357,7 +462,7 @@ func (m *Model) renderBuildContent(availableHeight int) string {
 	}
 
 	// Get column configuration with computed widths
-	columns := GetBuildColumns(m.terminalWidth)
+	columns := GetBuildColumns(m.listWidth(), m.List.Columns())
 
 	// Build table header row first (without styling yet)
 	var headerCells []string
@@ -400,7 +505,7 @@ func (m *Model) renderBuildContent(availableHeight int) string {
 	output.WriteString(rowsContent)
 
 	// Create the final styled table with proper width
-	finalOutput := lp.NewStyle().Width(m.terminalWidth).Render(output.String())
+	finalOutput := lp.NewStyle().Width(m.listWidth()).Render(output.String())
 
 	return finalOutput
 }
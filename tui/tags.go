@@ -0,0 +1,141 @@
+package tui
+
+import (
+	"TUI-Blender-Launcher/local"
+	"TUI-Blender-Launcher/model"
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// tagFavorite as the tag filter lists the favorite builds.
+const tagFavorite = "favorite"
+
+// tagPrompt is what the tag input in the footer is asking for.
+type tagPrompt int
+
+const (
+	tagPromptNone   tagPrompt = iota
+	tagPromptEdit             // The tags of the selected build
+	tagPromptFilter           // The tag to filter the list by
+)
+
+// tagsSavedMsg reports storing a build's tags in its version.json.
+type tagsSavedMsg struct {
+	err error
+}
+
+// newTagInput creates the input editing tags and the tag filter.
+func newTagInput() textinput.Model {
+	t := textinput.New()
+	t.CharLimit = 64
+	t.Width = 40
+	return t
+}
+
+// handleTagBuild asks for the tags of the selected installed build.
+func (m *Model) handleTagBuild() (tea.Model, tea.Cmd) {
+	build := m.List.GetSelectedBuild()
+	if build == nil || (build.Status != model.StateLocal && build.Status != model.StateUpdate) {
+		return m, nil
+	}
+	m.tagPrompt = tagPromptEdit
+	m.tagInput.Prompt = "Tags: "
+	m.tagInput.Placeholder = "production, testme"
+	m.tagInput.SetValue(strings.Join(build.Tags, ", "))
+	m.tagInput.CursorEnd()
+	return m, m.tagInput.Focus()
+}
+
+// handleFilterTags asks for the tag to filter the list by.
+func (m *Model) handleFilterTags() (tea.Model, tea.Cmd) {
+	m.tagPrompt = tagPromptFilter
+	m.tagInput.Prompt = "Only builds tagged: "
+	m.tagInput.Placeholder = "a tag or " + tagFavorite + ", empty for all"
+	m.tagInput.SetValue(m.tagFilter)
+	m.tagInput.CursorEnd()
+	return m, m.tagInput.Focus()
+}
+
+// handleToggleFavorite marks the selected installed build as a favorite, or unmarks it.
+func (m *Model) handleToggleFavorite() (tea.Model, tea.Cmd) {
+	build := m.List.GetSelectedBuild()
+	if build == nil || (build.Status != model.StateLocal && build.Status != model.StateUpdate) {
+		return m, nil
+	}
+	build.Favorite = !build.Favorite
+	return m, m.saveTags(*build)
+}
+
+// updateTagInput handles keys while the tag input is shown.
+func (m *Model) updateTagInput(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "esc":
+		m.tagPrompt = tagPromptNone
+		m.tagInput.Blur()
+		return m, nil
+	case "enter":
+		prompt := m.tagPrompt
+		m.tagPrompt = tagPromptNone
+		m.tagInput.Blur()
+		if prompt == tagPromptFilter {
+			return m.setTagFilter(strings.TrimSpace(m.tagInput.Value()))
+		}
+		build := m.List.GetSelectedBuild()
+		if build == nil {
+			return m, nil
+		}
+		build.Tags = local.ParseTags(m.tagInput.Value())
+		return m, m.saveTags(*build)
+	}
+
+	var cmd tea.Cmd
+	m.tagInput, cmd = m.tagInput.Update(msg)
+	return m, cmd
+}
+
+// saveTags creates a command storing the tags and favorite mark of build.
+func (m *Model) saveTags(build model.BlenderBuild) tea.Cmd {
+	roots := m.config.Roots()
+	return func() tea.Msg {
+		return tagsSavedMsg{err: local.SetBuildTags(roots, build.Version, build.Tags, build.Favorite)}
+	}
+}
+
+// handleTagsSaved shows why tags couldn't be saved.
+func (m *Model) handleTagsSaved(msg tagsSavedMsg) (tea.Model, tea.Cmd) {
+	if msg.err != nil {
+		m.err = fmt.Errorf("failed to save tags: %w", msg.err)
+	}
+	return m, nil
+}
+
+// setTagFilter lists only the builds with tag, or all builds again when it is
+// empty. Builds hidden by the previous filter come back with a rescan.
+func (m *Model) setTagFilter(tag string) (tea.Model, tea.Cmd) {
+	if tag == m.tagFilter {
+		return m, nil
+	}
+	m.tagFilter = tag
+	roots := m.config.Roots()
+	return m, func() tea.Msg {
+		builds, err := local.ScanLocalBuilds(roots...)
+		return libraryChangedMsg{builds: builds, err: err}
+	}
+}
+
+// applyTagFilter keeps the builds with the tag filtered by, if any.
+func (m *Model) applyTagFilter(builds []model.BlenderBuild) []model.BlenderBuild {
+	if m.tagFilter == "" {
+		return builds
+	}
+	filtered := make([]model.BlenderBuild, 0, len(builds))
+	for _, build := range builds {
+		if build.HasTag(m.tagFilter) || (strings.EqualFold(m.tagFilter, tagFavorite) && build.Favorite) {
+			filtered = append(filtered, build)
+		}
+	}
+	return filtered
+}
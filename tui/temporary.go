@@ -0,0 +1,110 @@
+package tui
+
+import (
+	"TUI-Blender-Launcher/local"
+	"TUI-Blender-Launcher/model"
+	"fmt"
+	"math"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	lp "github.com/charmbracelet/lipgloss"
+)
+
+// expirySavedMsg reports storing whether a build is temporary in its version.json.
+type expirySavedMsg struct {
+	err error
+}
+
+// handleTryBuild downloads the selected or marked builds as temporary
+// installs, which are offered for cleanup once they expire. On an installed
+// build it toggles whether the build is temporary.
+func (m *Model) handleTryBuild() (tea.Model, tea.Cmd) {
+	selected := m.List.GetSelectedBuild()
+	if selected == nil {
+		return m, nil
+	}
+	expires := time.Now().Add(time.Duration(max(m.config.TemporaryDays, 1)) * 24 * time.Hour)
+
+	if len(m.List.Marked) == 0 && (selected.Status == model.StateLocal || selected.Status == model.StateUpdate) {
+		if selected.ExpiresAt != nil {
+			selected.ExpiresAt = nil
+		} else {
+			selected.ExpiresAt = &expires
+		}
+		roots, version, expiresAt := m.config.Roots(), selected.Version, selected.ExpiresAt
+		return m, func() tea.Msg {
+			return expirySavedMsg{err: local.SetBuildExpiry(roots, version, expiresAt)}
+		}
+	}
+
+	try := func(build *model.BlenderBuild) {
+		switch build.Status {
+		case model.StateOnline, model.StateFailed, model.StateCancelled,
+			model.StateScheduled, model.StatePrefetched, model.StateInterrupted:
+			build.ExpiresAt = &expires
+		}
+	}
+	if len(m.List.Marked) == 0 {
+		try(selected)
+	}
+	for i := range m.List.Builds {
+		if m.List.Marked[downloadID(m.List.Builds[i])] {
+			try(&m.List.Builds[i])
+		}
+	}
+	return m.handleStartDownload()
+}
+
+// handleExpirySaved shows why a build's expiry couldn't be saved.
+func (m *Model) handleExpirySaved(msg expirySavedMsg) (tea.Model, tea.Cmd) {
+	if msg.err != nil {
+		m.err = fmt.Errorf("failed to save expiry: %w", msg.err)
+	}
+	return m, nil
+}
+
+// temporaryLabel describes how long a temporary build is left, e.g. "Trial, 3d left".
+func temporaryLabel(expires, now time.Time) string {
+	left := expires.Sub(now)
+	switch {
+	case left <= 0:
+		return "Expired"
+	case left < 24*time.Hour:
+		return fmt.Sprintf("Trial, %dh left", int(math.Ceil(left.Hours())))
+	}
+	return fmt.Sprintf("Trial, %dd left", int(math.Ceil(left.Hours()/24)))
+}
+
+// expiredBuilds counts the installed temporary builds past their expiry.
+func (m *Model) expiredBuilds() int {
+	count := 0
+	now := time.Now()
+	for _, build := range m.List.Builds {
+		if build.Status == model.StateLocal && build.Expired(now) {
+			count++
+		}
+	}
+	return count
+}
+
+// renderExpiredBanner renders the reminder shown above the builds list while
+// temporary builds have expired, or "" otherwise.
+func (m *Model) renderExpiredBanner() string {
+	count := m.expiredBuilds()
+	if count == 0 {
+		return ""
+	}
+	text := fmt.Sprintf(" %d temporary builds expired · press c to clean up", count)
+	if count == 1 {
+		text = " 1 temporary build expired · press c to clean up"
+	}
+	return lp.NewStyle().
+		Bold(true).
+		Foreground(lp.Color("0")).
+		Background(lp.Color(yellowColor)).
+		Width(m.terminalWidth).
+		MaxWidth(m.terminalWidth).
+		MaxHeight(1).
+		Render(text)
+}
@@ -0,0 +1,81 @@
+package tui
+
+import (
+	"maps"
+	"sync"
+)
+
+// transferProgress is how far an export or move of a build got.
+type transferProgress struct {
+	label    string // Shown in the status column, e.g. "Exporting"
+	progress float64
+}
+
+// TransferManager tracks the exports and moves copying builds in the
+// background. They run in commands of their own, so the UI only gets copies
+// of their progress.
+type TransferManager struct {
+	mu       sync.Mutex
+	progress map[string]transferProgress
+	cancel   map[string]chan struct{}
+}
+
+// NewTransferManager creates a transfer manager with nothing running
+func NewTransferManager() *TransferManager {
+	return &TransferManager{
+		progress: make(map[string]transferProgress),
+		cancel:   make(map[string]chan struct{}),
+	}
+}
+
+// start registers a transfer of a build and returns the channel cancelling
+// it, or false if the build is being transferred already.
+func (tm *TransferManager) start(buildID, label string) (chan struct{}, bool) {
+	tm.mu.Lock()
+	defer tm.mu.Unlock()
+	if _, running := tm.cancel[buildID]; running {
+		return nil, false
+	}
+	cancelCh := make(chan struct{})
+	tm.progress[buildID] = transferProgress{label: label}
+	tm.cancel[buildID] = cancelCh
+	return cancelCh, true
+}
+
+// setProgress records how far a transfer got
+func (tm *TransferManager) setProgress(buildID string, progress float64) {
+	tm.mu.Lock()
+	defer tm.mu.Unlock()
+	if transfer, running := tm.progress[buildID]; running {
+		transfer.progress = progress
+		tm.progress[buildID] = transfer
+	}
+}
+
+// finish forgets a transfer that ended
+func (tm *TransferManager) finish(buildID string) {
+	tm.mu.Lock()
+	defer tm.mu.Unlock()
+	delete(tm.progress, buildID)
+	delete(tm.cancel, buildID)
+}
+
+// Cancel stops the transfer of a build, returning false if there is none
+func (tm *TransferManager) Cancel(buildID string) bool {
+	tm.mu.Lock()
+	defer tm.mu.Unlock()
+	cancelCh := tm.cancel[buildID]
+	if cancelCh == nil {
+		return false
+	}
+	close(cancelCh)
+	tm.cancel[buildID] = nil // Still running until it notices
+	return true
+}
+
+// Progress returns a copy of the progress of the running transfers, by build ID
+func (tm *TransferManager) Progress() map[string]transferProgress {
+	tm.mu.Lock()
+	defer tm.mu.Unlock()
+	return maps.Clone(tm.progress)
+}
@@ -0,0 +1,50 @@
+package tui
+
+import (
+	"fmt"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// noTrashPrompt is a removal the trash couldn't take, to be confirmed before
+// deleting for good.
+type noTrashPrompt struct {
+	what   string
+	err    error
+	remove tea.Cmd // Deletes for good
+}
+
+// noTrashMsg reports a removal the trash couldn't take.
+type noTrashMsg noTrashPrompt
+
+// handleNoTrash asks whether to delete for good what the trash couldn't take.
+func (m *Model) handleNoTrash(msg noTrashMsg) (tea.Model, tea.Cmd) {
+	prompt := noTrashPrompt(msg)
+	m.noTrash = &prompt
+	return m, nil
+}
+
+// updateNoTrashPrompt handles keys while deleting for good is to be
+// confirmed: y deletes, n and esc keep the files.
+func (m *Model) updateNoTrashPrompt(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "y":
+		remove := m.noTrash.remove
+		m.noTrash = nil
+		return m, remove
+	case "n", "esc":
+		m.notice = "kept " + m.noTrash.what
+		m.noTrash = nil
+		m.userConfigs.removing = false
+	}
+	return m, nil
+}
+
+// renderNoTrashPrompt renders the question whether to delete for good, with
+// why the trash couldn't be used.
+func (m *Model) renderNoTrashPrompt() (string, string) {
+	keyStyle := m.Style.Key
+	line1 := fmt.Sprintf("%v. Delete %s for good?", m.noTrash.err, m.noTrash.what)
+	line2 := fmt.Sprintf("%s Delete%s%s Keep", keyStyle.Render("y"), m.Style.Separator.Render(" · "), keyStyle.Render("n"))
+	return line1, line2
+}
@@ -4,6 +4,7 @@ import (
 	"TUI-Blender-Launcher/local"
 	"TUI-Blender-Launcher/model"
 	"fmt"
+	"time"
 
 	"github.com/charmbracelet/bubbles/progress"
 	tea "github.com/charmbracelet/bubbletea"
@@ -13,20 +14,67 @@ import (
 func (m *Model) Init() tea.Cmd {
 	var cmds []tea.Cmd
 
-	// Start with local build scan to get builds already on disk
-	cmds = append(cmds, m.commands.ScanLocalBuilds())
+	// Start with local build scan to get builds already on disk, and look for
+	// what crashed sessions left behind
+	cmds = append(cmds, m.commands.StreamLocalBuilds(), checkStaleFiles(m.config.DownloadDir))
+
+	// List what package managers and stores installed alongside
+	if m.config.SystemBuilds {
+		cmds = append(cmds, m.scanSystemBuilds())
+	}
+
+	// Resume the downloads planned when the launcher last quit
+	cmds = append(cmds, m.commands.LoadQueue())
+
+	// Notice builds added or removed outside the launcher
+	m.libraryFingerprint = local.LibraryFingerprint(m.config.Roots())
+	cmds = append(cmds, m.watchLibrary())
 
 	// Add a program message listener to receive messages from background goroutines
 	cmds = append(cmds, m.commands.ProgramMsgListener())
 
 	// Start a ticker for continuous UI updates to show download progress
-	cmds = append(cmds, m.commands.StartTicker())
+	cmds = append(cmds, m.scheduleTick(activeTickInterval))
+
+	// Keep an eye on the builder's reachability
+	if m.healthCheckInterval() > 0 {
+		cmds = append(cmds, m.checkHealth(true))
+	}
+
+	// Show what changed since the last launcher version used. New users have
+	// nothing to catch up on.
+	if m.currentView == viewInitialSetup {
+		cmds = append(cmds, markChangelogSeen())
+	} else {
+		cmds = append(cmds, checkChangelog())
+	}
 
 	return tea.Batch(cmds...)
 }
 
-// Update updates the model based on messages
+// Update updates the model based on messages. Any message other than a tick
+// may start work, so it wakes the tick loop if that is idle or running slowly.
 func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	updated, cmd := m.update(msg)
+	// Views render the download states as of the end of this update
+	m.SyncDownloadStates()
+	if _, ok := msg.(tickMsg); !ok && (!m.ticking || time.Until(m.tickDue) > activeTickInterval) {
+		cmd = tea.Batch(cmd, m.scheduleTick(wakeTickDelay))
+	}
+	return updated, cmd
+}
+
+// scheduleTick schedules the next tick after d, superseding any tick already scheduled
+func (m *Model) scheduleTick(d time.Duration) tea.Cmd {
+	m.ticking = true
+	m.tickDue = time.Now().Add(d)
+	return tea.Tick(d, func(t time.Time) tea.Msg {
+		return tickMsg(t)
+	})
+}
+
+// update handles a message for the current view
+func (m *Model) update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	// Handle global messages
 	switch msg := msg.(type) {
 	case tea.WindowSizeMsg:
@@ -34,9 +82,143 @@ func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		return m, nil
 
 	case errMsg:
-		m.err = msg.err
+		m.err, m.notice = msg.err, ""
 		return m, nil
 
+	case noticeMsg:
+		m.err, m.notice = nil, string(msg)
+		return m, nil
+
+	// Messages from background goroutines are handled regardless of the current view
+	case downloadCompleteMsg:
+		return m.handleDownloadCompleteMsg(msg)
+
+	case postInstallDoneMsg:
+		return m.handlePostInstallDoneMsg(msg)
+
+	case prefetchCompleteMsg:
+		return m.handlePrefetchCompleteMsg(msg)
+
+	case verifyCompleteMsg:
+		return m.handleVerifyCompleteMsg(msg)
+
+	case repairCompleteMsg:
+		return m.handleRepairCompleteMsg(msg)
+
+	case checkCompleteMsg:
+		return m.handleCheckCompleteMsg(msg)
+
+	case defaultBuildSetMsg:
+		return m.handleDefaultBuildSet(msg)
+
+	case buildLaunchedMsg:
+		return m.handleBuildLaunched(msg)
+
+	case blenderStartedMsg:
+		return m.handleBlenderStarted(msg)
+
+	case blenderExitedMsg:
+		return m.handleBlenderExited(msg)
+
+	case launchFailedMsg:
+		return m.handleLaunchFailed(msg)
+
+	case tagsSavedMsg:
+		return m.handleTagsSaved(msg)
+
+	case expirySavedMsg:
+		return m.handleExpirySaved(msg)
+
+	case stableReleaseMsg:
+		return m.handleStableRelease(msg)
+
+	case importDoneMsg:
+		return m.handleImportDone(msg)
+
+	case exportDoneMsg:
+		return m.handleExportDone(msg)
+
+	case moveDoneMsg:
+		return m.handleMoveDone(msg)
+
+	case rollbackDoneMsg:
+		return m.handleRollbackDone(msg)
+
+	case duplicatesRemovedMsg:
+		return m.handleDuplicatesRemoved(msg)
+
+	case settingsCopiedMsg:
+		return m.handleSettingsCopied(msg)
+
+	case noTrashMsg:
+		return m.handleNoTrash(msg)
+
+	case killDoneMsg:
+		return m.handleKillDone(msg)
+
+	case consoleReadMsg:
+		return m.handleConsoleRead(msg)
+
+	case userConfigsMsg:
+		return m.handleUserConfigsMsg(msg)
+
+	case userConfigsPurgedMsg:
+		return m.handleUserConfigsPurged(msg)
+
+	case libraryWatchMsg:
+		return m.handleLibraryWatch(msg)
+
+	case libraryChangedMsg:
+		return m.handleLibraryChanged(msg)
+
+	case queueLoadedMsg:
+		return m.handleQueueLoaded(msg)
+
+	case healthMsg:
+		return m.handleHealthMsg(msg)
+
+	case healthTickMsg:
+		return m, m.checkHealth(true)
+
+	case scheduledDownloadDueMsg:
+		_, cmd := m.handleStartDownloadMsg(startDownloadMsg{build: msg.build})
+		return m, tea.Batch(cmd, m.commands.ProgramMsgListener())
+
+	case changelogMsg:
+		return m.handleShowChangelog()
+
+	case diskSpaceMsg:
+		return m.handleDiskSpaceMsg(msg)
+
+	case cleanupItemsMsg:
+		return m.handleCleanupItemsMsg(msg)
+
+	case cleanupDoneMsg:
+		return m.handleCleanupDoneMsg(msg)
+
+	case retentionDoneMsg:
+		return m.handleRetentionDone(msg)
+
+	case staleFilesMsg:
+		m.staleFiles, m.staleSize = msg.paths, msg.size
+		return m, nil
+
+	case staleFilesCleanedMsg:
+		m.err = msg.err
+		return m, checkDiskSpace(m.config.DownloadDir)
+
+	// The tick loop must keep running whichever view is shown
+	case tickMsg:
+		return m.handleTickMsg(msg)
+
+	case tea.KeyMsg:
+		m.lastInput = time.Now()
+		// The outcome of the last action has been seen once a key is pressed
+		m.err, m.notice = nil, ""
+		if m.noTrash != nil {
+			return m.updateNoTrashPrompt(msg)
+		}
+
 	case progress.FrameMsg:
 		// Pass to progress model
 		newProgress, cmd := m.Progress.Update(msg)
@@ -63,6 +245,34 @@ func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		}
 		return m, cmd
 
+	case viewJobs:
+		var newJobs tea.Model
+		newJobs, cmd = m.Jobs.Update(msg)
+		m.Jobs = *newJobs.(*JobsModel)
+
+		if keyMsg, ok := msg.(tea.KeyMsg); ok {
+			return m.updateJobsViewController(keyMsg, cmd)
+		}
+		return m, cmd
+
+	case viewChangelog, viewInsights:
+		return m.updateScrollViewController(msg)
+
+	case viewCleanup:
+		return m.updateCleanupViewController(msg)
+
+	case viewExecutables:
+		return m.updateExecutablesViewController(msg)
+
+	case viewUserConfigs:
+		return m.updateUserConfigsViewController(msg)
+
+	case viewRecentFiles:
+		return m.updateRecentFilesViewController(msg)
+
+	case viewConsole:
+		return m.updateConsoleViewController(msg)
+
 	default: // viewList
 		// Handle list logic
 		return m.updateListViewController(msg)
@@ -84,15 +294,16 @@ func (m *Model) updateSettingsViewController(msg tea.KeyMsg, innerCmd tea.Cmd) (
 				}
 			case CmdCleanOldBuilds:
 				if !m.Settings.EditMode {
+					roots := m.config.Roots()
 					return m, func() tea.Msg {
-						count, err := local.CleanOldBuilds(m.config.DownloadDir)
+						count, err := local.CleanOldBuilds(roots)
 						if err != nil {
 							return errMsg{err}
 						}
 						if count == 0 {
-							return errMsg{fmt.Errorf("no old builds to clean")}
+							return noticeMsg("no old builds to clean")
 						}
-						return errMsg{fmt.Errorf("successfully cleaned %d old build(s)", count)}
+						return noticeMsg(fmt.Sprintf("successfully cleaned %d old build(s)", count))
 					}
 				}
 			}
@@ -101,6 +312,48 @@ func (m *Model) updateSettingsViewController(msg tea.KeyMsg, innerCmd tea.Cmd) (
 	return m, innerCmd
 }
 
+// updateJobsViewController handles app-level logic for the launch jobs view
+func (m *Model) updateJobsViewController(msg tea.KeyMsg, innerCmd tea.Cmd) (tea.Model, tea.Cmd) {
+	// Keys typed into the form must not trigger commands
+	if m.Jobs.EditMode {
+		return m, innerCmd
+	}
+
+	for _, cmd := range GetCommandsForView(viewJobs) {
+		if MatchKey(msg, cmd.Type) {
+			switch cmd.Type {
+			case CmdQuit:
+				return m, tea.Quit
+			case CmdBack:
+				m.currentView = viewList
+				return m, nil
+			case CmdAddJob:
+				if m.Jobs.Executable == "" {
+					m.err = fmt.Errorf("select a local build with b before adding jobs")
+					return m, nil
+				}
+				file, args := m.Jobs.GetJobValues()
+				opts, err := m.jobOptions(m.Jobs.Version, m.Jobs.Sandboxed, file)
+				if err != nil {
+					m.err = err
+					return m, nil
+				}
+				m.commands.jobs.Add(m.Jobs.Version, m.Jobs.Executable, file, args, opts)
+				m.Jobs.Jobs = m.commands.jobs.Jobs()
+				m.err = nil
+				return m, nil
+			case CmdCancelJob:
+				if job := m.Jobs.SelectedJob(); job != nil {
+					m.commands.jobs.Cancel(job.ID)
+					m.Jobs.Jobs = m.commands.jobs.Jobs()
+				}
+				return m, nil
+			}
+		}
+	}
+	return m, innerCmd
+}
+
 // updateListViewController handles logic for list view (controller layer)
 func (m *Model) updateListViewController(msg tea.Msg) (tea.Model, tea.Cmd) {
 	var cmd tea.Cmd
@@ -110,6 +363,12 @@ func (m *Model) updateListViewController(msg tea.Msg) (tea.Model, tea.Cmd) {
 	case localBuildsScannedMsg:
 		return m.handleLocalBuildsScanned(msg)
 
+	case localBuildsFoundMsg:
+		return m.handleLocalBuildsFound(msg)
+
+	case systemBuildsMsg:
+		return m.handleSystemBuilds(msg)
+
 	case buildsFetchedMsg:
 		return m.handleBuildsFetched(msg)
 
@@ -122,13 +381,39 @@ func (m *Model) updateListViewController(msg tea.Msg) (tea.Model, tea.Cmd) {
 	case startDownloadMsg:
 		return m.handleStartDownloadMsg(msg)
 
-	case downloadCompleteMsg:
-		return m.handleDownloadCompleteMsg(msg)
-
-	case tickMsg:
-		return m.handleTickMsg(msg)
-
 	case tea.KeyMsg:
+		// Keys typed into the schedule input must not trigger commands
+		if m.scheduling {
+			return m.updateScheduleInput(msg)
+		}
+		if m.tagPrompt != tagPromptNone {
+			return m.updateTagInput(msg)
+		}
+		if m.importing {
+			return m.updateImportInput(msg)
+		}
+		if m.exportBuild != nil {
+			return m.updateExportInput(msg)
+		}
+		if m.renderBuild != nil {
+			return m.updateRenderInput(msg)
+		}
+		if m.moveBuild != nil {
+			return m.updateMovePrompt(msg)
+		}
+		if m.envLaunch != nil {
+			return m.updateEnvPrompt(msg)
+		}
+		if m.launchMenu != nil {
+			return m.updateLaunchMenu(msg)
+		}
+		if m.settingsOffer != nil && isSettingsOfferKey(msg) {
+			return m.updateSettingsOffer(msg)
+		}
+		if m.killBuild != nil {
+			return m.updateKillPrompt(msg)
+		}
+
 		// Check for app-level commands first
 		for _, command := range GetCommandsForView(viewList) {
 			if MatchKey(msg, command.Type) {
@@ -137,18 +422,87 @@ func (m *Model) updateListViewController(msg tea.Msg) (tea.Model, tea.Cmd) {
 					return m, tea.Quit
 				case CmdShowSettings:
 					m.currentView = viewSettings
-					m.Settings.SetValues(m.config.DownloadDir, m.config.VersionFilter, m.config.BuildType)
+					m.Settings.SetValues(m.config.DownloadDir, m.config.VersionFilter, m.config.BuildType, m.config.LaunchMode)
 					return m, nil
 				case CmdFetchBuilds:
 					return m, m.commands.FetchBuilds()
 				case CmdDownloadBuild:
 					return m.handleStartDownload()
 				case CmdLaunchBuild:
-					return m.handleLaunchBlender()
+					return m.handleShowLaunchMenu()
+				case CmdLaunchWithEnv:
+					return m.handleLaunchWithEnv()
+				case CmdShowRecentFiles:
+					return m.handleShowRecentFiles()
+				case CmdRenderBuild:
+					return m.handleRenderBuild()
+				case CmdKillBuild:
+					return m.handleKillBuild()
+				case CmdShowConsole:
+					return m.handleShowConsole()
 				case CmdOpenBuildDir:
 					return m.handleOpenBuildDir()
 				case CmdDeleteBuild:
 					return m.handleDeleteBuild()
+				case CmdShowJobs:
+					return m.handleShowJobs()
+				case CmdVerifyBuild:
+					return m.handleVerifyBuild()
+				case CmdRepairBuild:
+					return m.handleRepairBuild()
+				case CmdCheckBuild:
+					return m.handleCheckBuild()
+				case CmdReinstallBuild:
+					return m.handleReinstallBuild()
+				case CmdPasteBuild:
+					return m.handlePasteBuild()
+				case CmdShareBuild:
+					return m.handleShareBuild()
+				case CmdPauseDownload:
+					return m.handlePauseDownload()
+				case CmdShowChangelog:
+					return m.handleShowChangelog()
+				case CmdShowInsights:
+					return m.handleShowInsights()
+				case CmdShowCleanup:
+					return m.handleShowCleanup()
+				case CmdShowExecutables:
+					return m.handleShowExecutables()
+				case CmdSetDefaultBuild:
+					return m.handleSetDefaultBuild()
+				case CmdTagBuild:
+					return m.handleTagBuild()
+				case CmdToggleFavorite:
+					return m.handleToggleFavorite()
+				case CmdFilterTags:
+					return m.handleFilterTags()
+				case CmdTryBuild:
+					return m.handleTryBuild()
+				case CmdInstallRelease:
+					return m.handleInstallRelease()
+				case CmdImportBuild:
+					return m.handleImportBuild()
+				case CmdExportBuild:
+					return m.handleExportBuild()
+				case CmdMoveBuild:
+					return m.handleMoveBuild()
+				case CmdRollbackBuild:
+					return m.handleRollbackBuild()
+				case CmdRemoveDuplicates:
+					return m.handleRemoveDuplicates()
+				case CmdShowUserConfigs:
+					return m.handleShowUserConfigs()
+				case CmdScheduleDownload:
+					return m.handleScheduleDownload()
+				case CmdMarkBuild:
+					m.List.ToggleMark()
+					return m, nil
+				case CmdClearMarks:
+					m.List.ClearMarks()
+					m.stableRelease = nil
+					return m, nil
+				case CmdCleanTempFiles:
+					return m.handleCleanTempFiles()
 				}
 			}
 		}
@@ -0,0 +1,272 @@
+package tui
+
+import (
+	"TUI-Blender-Launcher/local"
+	"TUI-Blender-Launcher/model"
+	"errors"
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	lp "github.com/charmbracelet/lipgloss"
+)
+
+// userConfigsState is the view of Blender's per-series user files and those
+// picked for purging.
+type userConfigsState struct {
+	configs    []local.UserConfig
+	selected   []bool
+	cursor     int
+	loading    bool
+	confirming bool // The purge of the selection waits for y
+	removing   bool
+}
+
+// userConfigsMsg carries the directories of Blender's user files found.
+type userConfigsMsg struct {
+	configs []local.UserConfig
+	err     error
+}
+
+// userConfigsPurgedMsg reports the outcome of purging the selected directories.
+type userConfigsPurgedMsg struct {
+	series  []string
+	freed   int64
+	trashed bool
+	err     error
+}
+
+// handleShowUserConfigs opens the view of Blender's user files and measures
+// them in the background.
+func (m *Model) handleShowUserConfigs() (tea.Model, tea.Cmd) {
+	m.currentView = viewUserConfigs
+	m.userConfigs = userConfigsState{loading: true}
+
+	roots, systemBuilds := m.config.Roots(), m.systemBuilds
+	return m, func() tea.Msg {
+		installed, err := local.ScanLocalBuilds(roots...)
+		if err != nil {
+			return userConfigsMsg{err: err}
+		}
+		configs, err := local.ListUserConfigs(append(installed, systemBuilds...))
+		return userConfigsMsg{configs: configs, err: err}
+	}
+}
+
+// handleUserConfigsMsg fills the view with nothing picked for purging, as
+// the user files of a series outlive its builds on purpose.
+func (m *Model) handleUserConfigsMsg(msg userConfigsMsg) (tea.Model, tea.Cmd) {
+	if msg.err != nil {
+		m.err = msg.err
+		m.currentView = viewList
+		return m, nil
+	}
+	m.userConfigs = userConfigsState{configs: msg.configs, selected: make([]bool, len(msg.configs))}
+	return m, nil
+}
+
+// selectedUserConfigs returns the directories picked for purging and their total size.
+func (m *Model) selectedUserConfigs() ([]local.UserConfig, int64) {
+	var configs []local.UserConfig
+	var size int64
+	for i, config := range m.userConfigs.configs {
+		if m.userConfigs.selected[i] {
+			configs = append(configs, config)
+			size += config.Size
+		}
+	}
+	return configs, size
+}
+
+// handleUserConfigsPurged returns to the builds list after purging.
+func (m *Model) handleUserConfigsPurged(msg userConfigsPurgedMsg) (tea.Model, tea.Cmd) {
+	m.userConfigs = userConfigsState{}
+	if m.currentView == viewUserConfigs {
+		m.currentView = viewList
+	}
+	switch {
+	case msg.err != nil:
+		m.err = msg.err
+	case msg.trashed:
+		m.notice = fmt.Sprintf("moved the configs of Blender %s (%s) to the trash", strings.Join(msg.series, ", "), model.FormatByteSize(msg.freed))
+	default:
+		m.notice = fmt.Sprintf("deleted the configs of Blender %s (%s)", strings.Join(msg.series, ", "), model.FormatByteSize(msg.freed))
+	}
+	return m, nil
+}
+
+// purgeUserConfigs removes the given directories of Blender's user files,
+// offering to delete them for good if the trash can't take them.
+func purgeUserConfigs(configs []local.UserConfig, series []string, toTrash bool) tea.Cmd {
+	return func() tea.Msg {
+		freed, err := local.RemoveUserConfigs(configs, toTrash)
+		if errors.Is(err, local.ErrNoTrash) {
+			return noTrashMsg{
+				what:   fmt.Sprintf("the configs of Blender %s", strings.Join(series, ", ")),
+				err:    err,
+				remove: purgeUserConfigs(configs, series, false),
+			}
+		}
+		return userConfigsPurgedMsg{series: series, freed: freed, trashed: toTrash, err: err}
+	}
+}
+
+// updateUserConfigsPrompt handles keys while the purge is to be confirmed: y
+// moves the selection to the trash, n and esc keep it.
+func (m *Model) updateUserConfigsPrompt(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "y":
+		m.userConfigs.confirming = false
+		configs, _ := m.selectedUserConfigs()
+		series := make([]string, len(configs))
+		for i, config := range configs {
+			if err := m.checkSeriesNotRunning(config.Series, "purging its config"); err != nil {
+				m.err = err
+				return m, nil
+			}
+			series[i] = config.Series
+		}
+		m.userConfigs.removing = true
+		return m, purgeUserConfigs(configs, series, true)
+	case "n", "esc":
+		m.userConfigs.confirming = false
+	}
+	return m, nil
+}
+
+// updateUserConfigsViewController handles keys in the view of Blender's user files
+func (m *Model) updateUserConfigsViewController(msg tea.Msg) (tea.Model, tea.Cmd) {
+	keyMsg, ok := msg.(tea.KeyMsg)
+	if !ok {
+		return m.updateListViewController(msg)
+	}
+	if m.userConfigs.confirming {
+		return m.updateUserConfigsPrompt(keyMsg)
+	}
+
+	for _, cmd := range GetCommandsForView(viewUserConfigs) {
+		if !MatchKey(keyMsg, cmd.Type) {
+			continue
+		}
+		switch cmd.Type {
+		case CmdQuit:
+			return m, tea.Quit
+		case CmdBack:
+			if !m.userConfigs.removing {
+				m.currentView = viewList
+				m.userConfigs = userConfigsState{}
+			}
+		case CmdMoveUp:
+			m.userConfigs.cursor = max(m.userConfigs.cursor-1, 0)
+		case CmdMoveDown:
+			m.userConfigs.cursor = max(min(m.userConfigs.cursor+1, len(m.userConfigs.configs)-1), 0)
+		case CmdToggleCleanupItem:
+			if m.userConfigs.cursor < len(m.userConfigs.selected) {
+				m.userConfigs.selected[m.userConfigs.cursor] = !m.userConfigs.selected[m.userConfigs.cursor]
+			}
+		case CmdOpenBuildDir:
+			if m.userConfigs.cursor < len(m.userConfigs.configs) {
+				return m, local.OpenDirCmd(m.userConfigs.configs[m.userConfigs.cursor].Path)
+			}
+		case CmdRunCleanup:
+			if configs, _ := m.selectedUserConfigs(); len(configs) > 0 && !m.userConfigs.removing {
+				m.userConfigs.confirming = true
+			}
+		}
+		return m, nil
+	}
+	return m, nil
+}
+
+// renderUserConfigs renders Blender's user files per series with their sizes,
+// newest series first, to fit the given width and height.
+func (m *Model) renderUserConfigs(width, height int) string {
+	titleStyle := lp.NewStyle().Bold(true).Foreground(lp.Color(highlightColor))
+	innerWidth := max(width-2*formPadding, 1)
+
+	var b strings.Builder
+	b.WriteString(titleStyle.Render("Blender configs"))
+	b.WriteString("\n\n")
+
+	switch {
+	case m.userConfigs.loading:
+		b.WriteString("Measuring Blender's user files...")
+		return lp.NewStyle().Padding(0, formPadding).Render(b.String())
+	case m.userConfigs.removing:
+		b.WriteString("Purging...")
+		return lp.NewStyle().Padding(0, formPadding).Render(b.String())
+	case len(m.userConfigs.configs) == 0:
+		b.WriteString("Blender has no user files yet.")
+		return lp.NewStyle().Padding(0, formPadding).Render(b.String())
+	}
+
+	_, total := m.selectedUserConfigs()
+	b.WriteString(fmt.Sprintf("%s selected", model.FormatByteSize(total)))
+	headerLines := 3
+
+	sizeWidth, seriesWidth := 0, 0
+	for _, config := range m.userConfigs.configs {
+		sizeWidth = max(sizeWidth, len(model.FormatByteSize(config.Size)))
+		seriesWidth = max(seriesWidth, len(config.Series))
+	}
+
+	lines := make([]string, len(m.userConfigs.configs))
+	for i, config := range m.userConfigs.configs {
+		check := "[ ]"
+		if m.userConfigs.selected[i] {
+			check = "[x]"
+		}
+		state := "not installed"
+		if config.Installed {
+			state = "installed    "
+		}
+		line := fmt.Sprintf("%s %*s  %-*s  %s  %s", check, sizeWidth, model.FormatByteSize(config.Size), seriesWidth, config.Series, state, config.Path)
+		style := lp.NewStyle().MaxWidth(innerWidth)
+		if i == m.userConfigs.cursor {
+			style = m.Style.SelectedRow.MaxWidth(innerWidth)
+		}
+		lines[i] = style.Render(line)
+	}
+	list := clipLines(strings.Join(lines, "\n"), height-headerLines, m.userConfigs.cursor)
+
+	b.WriteString("\n\n")
+	b.WriteString(list)
+	return lp.NewStyle().Padding(0, formPadding).Render(b.String())
+}
+
+// renderUserConfigsFooter renders the footer for the view of Blender's user files
+func (m *Model) renderUserConfigsFooter() string {
+	keyStyle := m.Style.Key
+	separator := m.Style.Separator.Render(" · ")
+	newlineStyle := m.Style.Newline.Render("\n")
+
+	commands := []string{
+		fmt.Sprintf("%s Select", keyStyle.Render("space")),
+		fmt.Sprintf("%s Purge selected", keyStyle.Render("enter")),
+		fmt.Sprintf("%s Open", keyStyle.Render("o")),
+		fmt.Sprintf("%s Back", keyStyle.Render("esc")),
+		fmt.Sprintf("%s Quit", keyStyle.Render("q")),
+	}
+	status := m.renderStatus()
+
+	// The purge waits for confirmation, settings and add-ons can't be downloaded again
+	if m.userConfigs.confirming {
+		configs, size := m.selectedUserConfigs()
+		series := make([]string, len(configs))
+		for i, config := range configs {
+			series[i] = config.Series
+		}
+		status = fmt.Sprintf("Move the settings and add-ons of Blender %s (%s) to the trash?", strings.Join(series, ", "), model.FormatByteSize(size))
+		commands = []string{
+			fmt.Sprintf("%s Purge", keyStyle.Render("y")),
+			fmt.Sprintf("%s Keep", keyStyle.Render("n")),
+		}
+	}
+
+	footerContent := status + newlineStyle + strings.Join(commands, separator)
+	if m.noTrash != nil {
+		line1, line2 := m.renderNoTrashPrompt()
+		footerContent = line1 + newlineStyle + line2
+	}
+	return m.Style.Footer.Width(m.terminalWidth).Render(footerContent)
+}
@@ -1,10 +1,95 @@
 package tui
 
 import (
+	"fmt"
 	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	lp "github.com/charmbracelet/lipgloss"
 )
 
-func (m *Model) renderPageForView() string {
+// formPadding is the horizontal padding around the settings and jobs forms
+const formPadding = 2
+
+// formInputWidth returns the width of a form text input that fits a terminal
+// of the given width, leaving room for the form padding, the input margin,
+// the prompt and the cursor.
+func formInputWidth(terminalWidth int) int {
+	return max(terminalWidth-2*formPadding-2-3, 1)
+}
+
+// clipLines cuts content to at most height lines, scrolled so that the anchor
+// line stays visible. A height of 0 or less leaves the content untouched.
+func clipLines(content string, height, anchor int) string {
+	lines := strings.Split(content, "\n")
+	if height <= 0 || len(lines) <= height {
+		return content
+	}
+
+	start := 0
+	if anchor >= height {
+		start = anchor - height + 1
+	}
+	start = min(start, len(lines)-height)
+	return strings.Join(lines[start:start+height], "\n")
+}
+
+// renderScrollable renders the content of a read-only view, clipped to height
+// lines from the scroll offset, which is kept within the content.
+func (m *Model) renderScrollable(content string, height int) string {
+	lines := strings.Count(content, "\n") + 1
+	m.scrollOffset = max(min(m.scrollOffset, lines-height), 0)
+
+	content = clipLines(content, height, m.scrollOffset+height-1)
+	return lp.NewStyle().Padding(0, formPadding).Render(content)
+}
+
+// updateScrollViewController handles keys in the read-only changelog and insights views
+func (m *Model) updateScrollViewController(msg tea.Msg) (tea.Model, tea.Cmd) {
+	keyMsg, ok := msg.(tea.KeyMsg)
+	if !ok {
+		// Background results, e.g. the startup scan, still update the builds list
+		return m.updateListViewController(msg)
+	}
+
+	for _, cmd := range GetCommandsForView(m.currentView) {
+		if MatchKey(keyMsg, cmd.Type) {
+			switch cmd.Type {
+			case CmdQuit:
+				return m, tea.Quit
+			case CmdMoveUp:
+				m.scrollOffset = max(m.scrollOffset-1, 0)
+			case CmdMoveDown:
+				m.scrollOffset++
+			case CmdBack:
+				m.currentView = viewList
+				m.scrollOffset = 0
+			}
+			return m, nil
+		}
+	}
+	return m, nil
+}
+
+// renderScrollFooter renders the footer for the read-only views
+func (m *Model) renderScrollFooter() string {
+	keyStyle := m.Style.Key
+	separator := m.Style.Separator.Render(" · ")
+	newlineStyle := m.Style.Newline.Render("\n")
+
+	commands := []string{
+		fmt.Sprintf("%s Scroll", keyStyle.Render("↑/↓")),
+		fmt.Sprintf("%s Back", keyStyle.Render("esc")),
+		fmt.Sprintf("%s Quit", keyStyle.Render("q")),
+	}
+
+	footerContent := m.renderStatus() + newlineStyle + strings.Join(commands, separator)
+	return m.Style.Footer.Width(m.terminalWidth).Render(footerContent)
+}
+
+// contentHeight returns the lines left for the page content between the
+// header and footer
+func (m *Model) contentHeight() int {
 	// Define fixed heights
 	headerHeight := 2
 	footerHeight := 2
@@ -12,14 +97,20 @@ func (m *Model) renderPageForView() string {
 	// Fixed items: header, footer, 2 separator lines
 	fixedHeightItems := headerHeight + footerHeight + 2
 
-	// Calculate content height
-	contentHeight := m.terminalHeight - fixedHeightItems
-	if contentHeight < 1 {
-		contentHeight = 1
-	}
+	return max(m.terminalHeight-fixedHeightItems, 1)
+}
+
+func (m *Model) renderPageForView() string {
+	contentHeight := m.contentHeight()
 
 	// Generate app components
-	header := renderHeader(m.terminalWidth)
+	var badges []string
+	for _, badge := range []string{m.renderRenderBadge(), m.renderRunningBadge(), m.renderHealthBadge()} {
+		if badge != "" {
+			badges = append(badges, badge)
+		}
+	}
+	header := renderHeader(m.terminalWidth, strings.Join(badges, " "))
 
 	// Create slim horizontal separators
 	separatorStyle := m.Style.Separator
@@ -32,8 +123,35 @@ func (m *Model) renderPageForView() string {
 	if m.currentView == viewInitialSetup || m.currentView == viewSettings {
 		content = m.Settings.View()
 		footer = m.renderSettingsFooter()
+	} else if m.currentView == viewJobs {
+		content = m.Jobs.View()
+		footer = m.renderJobsFooter()
+	} else if m.currentView == viewChangelog {
+		content = m.renderChangelog(m.terminalWidth, contentHeight)
+		footer = m.renderScrollFooter()
+	} else if m.currentView == viewInsights {
+		content = m.renderInsights(m.terminalWidth, contentHeight)
+		footer = m.renderScrollFooter()
+	} else if m.currentView == viewCleanup {
+		content = m.renderCleanup(m.terminalWidth, contentHeight)
+		footer = m.renderCleanupFooter()
+	} else if m.currentView == viewExecutables {
+		content = m.renderExecutables(m.terminalWidth, contentHeight)
+		footer = m.renderExecutablesFooter()
+	} else if m.currentView == viewUserConfigs {
+		content = m.renderUserConfigs(m.terminalWidth, contentHeight)
+		footer = m.renderUserConfigsFooter()
+	} else if m.currentView == viewRecentFiles {
+		content = m.renderRecentFiles(m.terminalWidth, contentHeight)
+		footer = m.renderRecentFilesFooter()
+	} else if m.currentView == viewConsole {
+		content = m.renderConsole(m.terminalWidth, contentHeight)
+		footer = m.renderConsoleFooter()
+	} else if banner := m.renderBanner(); banner != "" && contentHeight > 2 {
+		content = banner + "\n" + m.renderListPanes(contentHeight-1)
+		footer = m.renderBuildFooter()
 	} else {
-		content = m.renderBuildContent(contentHeight)
+		content = m.renderListPanes(contentHeight)
 		footer = m.renderBuildFooter()
 	}
 
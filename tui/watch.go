@@ -0,0 +1,103 @@
+package tui
+
+import (
+	"TUI-Blender-Launcher/local"
+	"TUI-Blender-Launcher/model"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// libraryWatchInterval is how often the install roots are checked for builds
+// added or removed outside the launcher.
+const libraryWatchInterval = 2 * time.Second
+
+// libraryWatchMsg carries the fingerprint of the install roots at a check.
+type libraryWatchMsg struct {
+	fingerprint string
+}
+
+// libraryChangedMsg carries the builds installed after the roots changed.
+type libraryChangedMsg struct {
+	builds []model.BlenderBuild
+	err    error
+}
+
+// watchLibrary returns a command checking the install roots after the watch interval.
+func (m *Model) watchLibrary() tea.Cmd {
+	roots := m.config.Roots()
+	return tea.Tick(libraryWatchInterval, func(time.Time) tea.Msg {
+		return libraryWatchMsg{fingerprint: local.LibraryFingerprint(roots)}
+	})
+}
+
+// handleLibraryWatch rescans the local builds when the install roots changed
+// since the last check, and schedules the next check.
+func (m *Model) handleLibraryWatch(msg libraryWatchMsg) (tea.Model, tea.Cmd) {
+	if msg.fingerprint == m.libraryFingerprint {
+		return m, m.watchLibrary()
+	}
+	m.libraryFingerprint = msg.fingerprint
+	roots := m.config.Roots()
+	return m, tea.Batch(m.watchLibrary(), func() tea.Msg {
+		builds, err := local.ScanLocalBuilds(roots...)
+		return libraryChangedMsg{builds: builds, err: err}
+	})
+}
+
+// handleLibraryChanged replaces the local builds in the list with the ones
+// installed now, keeping the selection. Once builds were fetched, their
+// statuses are worked out again against the fetched builds.
+func (m *Model) handleLibraryChanged(msg libraryChangedMsg) (tea.Model, tea.Cmd) {
+	if msg.err != nil {
+		m.err = msg.err
+		return m, nil
+	}
+	if selected := m.List.GetSelectedBuild(); selected != nil {
+		m.keepSelection = downloadID(*selected)
+	}
+	m.loadDefaultBuild()
+
+	// Rows that aren't installed builds stay, unless they are fetched again below
+	fetched := make(map[string]bool, len(m.onlineBuilds))
+	for _, build := range m.onlineBuilds {
+		fetched[downloadID(build)] = true
+	}
+	saveCache := m.commands.saveScanCache(msg.builds)
+	builds := msg.builds
+	for _, build := range m.List.Builds {
+		if build.Status != model.StateLocal && build.Status != model.StateUpdate && build.Status != model.StateSystem && !fetched[downloadID(build)] {
+			builds = append(builds, build)
+		}
+	}
+
+	if len(m.onlineBuilds) > 0 {
+		return m, tea.Batch(m.commands.UpdateBuildStatus(append(builds, m.onlineBuilds...)), saveCache)
+	}
+
+	m.List.Builds = m.withSystemBuilds(builds)
+	if m.config.VersionFilter != "" {
+		m.List.Builds = m.applyVersionFilter(m.List.Builds)
+	}
+	m.List.Builds = m.applyTagFilter(m.List.Builds)
+	m.List.SortBuilds()
+	m.restoreSelection()
+	return m, saveCache
+}
+
+// restoreSelection moves the cursor back to the build selected before the
+// list was rebuilt, if it is still listed.
+func (m *Model) restoreSelection() {
+	if m.keepSelection == "" {
+		return
+	}
+	for i, build := range m.List.Builds {
+		if downloadID(build) == m.keepSelection {
+			m.List.Cursor = i
+			break
+		}
+	}
+	m.keepSelection = ""
+	m.List.Cursor = min(m.List.Cursor, max(len(m.List.Builds)-1, 0))
+	m.List.EnsureCursorVisible()
+}